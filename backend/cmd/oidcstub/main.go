@@ -0,0 +1,55 @@
+// Command oidcstub runs the stub OpenID Connect identity provider for local
+// single sign-on development. Every authorization request signs in the
+// identity configured through OIDC_STUB_* environment variables.
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"socialpredict/internal/service/auth/oidc/oidctest"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "oidc stub failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	appEnv := strings.ToLower(strings.TrimSpace(os.Getenv("APP_ENV")))
+	if appEnv != "development" {
+		return fmt.Errorf("refusing to run outside APP_ENV=development; current APP_ENV=%q", appEnv)
+	}
+
+	addr := envString("OIDC_STUB_ADDR", "127.0.0.1:9400")
+	issuer := envString("OIDC_STUB_ISSUER", "http://"+addr)
+	provider, err := oidctest.NewProvider(issuer, envString("OIDC_CLIENT_ID", "socialpredict-local"))
+	if err != nil {
+		return err
+	}
+	provider.ClientSecret = strings.TrimSpace(os.Getenv("OIDC_CLIENT_SECRET"))
+	provider.SetIdentity(oidctest.Identity{
+		Subject:           envString("OIDC_STUB_SUBJECT", "local-dev-user"),
+		Email:             envString("OIDC_STUB_EMAIL", "dev.user@example.com"),
+		EmailVerified:     envString("OIDC_STUB_EMAIL_VERIFIED", "true") == "true",
+		PreferredUsername: envString("OIDC_STUB_USERNAME", "devuser"),
+		Name:              envString("OIDC_STUB_NAME", "Dev User"),
+	})
+
+	fmt.Printf("stub OIDC provider listening on %s (issuer %s)\n", addr, provider.Issuer())
+	server := &http.Server{Addr: addr, Handler: provider.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	return server.ListenAndServe()
+}
+
+func envString(key, fallback string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	return value
+}
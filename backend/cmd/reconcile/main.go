@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"socialpredict/internal/app"
+	appruntime "socialpredict/internal/app/runtime"
+	"socialpredict/setup"
+)
+
+// exitDiscrepancies is the exit status when the ledger does not reconcile,
+// so scripts can tell a failed check apart from a failed run.
+const exitDiscrepancies = 2
+
+func main() {
+	balanced, err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reconcile failed: %v\n", err)
+		os.Exit(1)
+	}
+	if !balanced {
+		os.Exit(exitDiscrepancies)
+	}
+}
+
+func run() (bool, error) {
+	dbCfg, err := appruntime.LoadDBConfigFromEnv()
+	if err != nil {
+		return false, err
+	}
+	db, err := appruntime.InitDB(dbCfg, appruntime.PostgresFactory{})
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = appruntime.CloseDB(db) }()
+
+	configSvc, err := appruntime.LoadConfigService(setup.EmbeddedSource{})
+	if err != nil {
+		return false, err
+	}
+
+	container := app.BuildApplicationWithConfigService(db, configSvc)
+	report, err := container.GetAnalyticsService().Reconcile(context.Background(), time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	var out io.Writer = os.Stdout
+	if path := strings.TrimSpace(os.Getenv("RECONCILE_OUTPUT")); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return false, err
+		}
+		defer func() { _ = file.Close() }()
+		out = file
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Reconciled %d users, %d resolved markets, %d snapshots (%d stale skipped): %d discrepancies\n",
+		report.UsersChecked, report.MarketsChecked, report.SnapshotsChecked, report.SnapshotsStale, len(report.Discrepancies))
+	return report.Balanced, nil
+}
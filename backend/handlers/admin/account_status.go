@@ -0,0 +1,164 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+const defaultAdminAccountStatusAuditsLimit = 50
+const maxAdminAccountStatusAuditsLimit = 200
+
+type accountStatusManager interface {
+	SetAccountStatus(ctx context.Context, actor *dusers.User, username string, change dusers.AccountStatusChange) (*dusers.User, error)
+	ListAccountStatusAudits(ctx context.Context, actor *dusers.User, username string, limit, offset int) ([]*dusers.AccountStatusAuditRecord, error)
+}
+
+type accountStatusRequest struct {
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+type accountStatusAuditResponse struct {
+	ID            int64   `json:"id"`
+	Username      string  `json:"username"`
+	ActorUsername string  `json:"actorUsername"`
+	Action        string  `json:"action"`
+	FromStatus    string  `json:"fromStatus"`
+	ToStatus      string  `json:"toStatus"`
+	Reason        string  `json:"reason,omitempty"`
+	ExpiresAt     *string `json:"expiresAt,omitempty"`
+	CreatedAt     string  `json:"createdAt"`
+}
+
+type accountStatusAuditsResponse struct {
+	Audits []accountStatusAuditResponse `json:"audits"`
+	Total  int                          `json:"total"`
+	Limit  int                          `json:"limit"`
+	Offset int                          `json:"offset"`
+}
+
+// UpdateAccountStatusHandler suspends, bans, or reinstates an account.
+func UpdateAccountStatusHandler(svc accountStatusManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.UsersManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		username, ok := adminUsernameFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		var req accountStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		change := dusers.AccountStatusChange{
+			Status: dusers.AccountStatus(strings.TrimSpace(req.Status)),
+			Reason: req.Reason,
+			Now:    now().UTC(),
+		}
+		if raw := strings.TrimSpace(req.ExpiresAt); raw != "" {
+			expiresAt, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+				return
+			}
+			expiresAt = expiresAt.UTC()
+			change.ExpiresAt = &expiresAt
+		}
+
+		user, err := svc.SetAccountStatus(r.Context(), admin, username, change)
+		if err != nil {
+			writeAdminUserError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, adminUserResponseFromUser(user, change.Now))
+	}
+}
+
+// ListAccountStatusAuditsHandler returns an account's suspension, ban, and
+// reinstatement history, newest first.
+func ListAccountStatusAuditsHandler(svc accountStatusManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.UsersManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		username, ok := adminUsernameFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		limit, ok := parseAdminUserListInt(w, query.Get("limit"), defaultAdminAccountStatusAuditsLimit, 1, maxAdminAccountStatusAuditsLimit)
+		if !ok {
+			return
+		}
+		offset, ok := parseAdminUserListInt(w, query.Get("offset"), 0, 0, 100000)
+		if !ok {
+			return
+		}
+
+		records, err := svc.ListAccountStatusAudits(r.Context(), admin, username, limit, offset)
+		if err != nil {
+			writeAdminUserError(w, err)
+			return
+		}
+
+		audits := make([]accountStatusAuditResponse, 0, len(records))
+		for _, record := range records {
+			audit := accountStatusAuditResponse{
+				ID:            record.ID,
+				Username:      record.Username,
+				ActorUsername: record.ActorUsername,
+				Action:        string(record.Action),
+				FromStatus:    string(record.FromStatus),
+				ToStatus:      string(record.ToStatus),
+				Reason:        record.Reason,
+				CreatedAt:     formatAdminTime(record.CreatedAt),
+			}
+			if record.ExpiresAt != nil {
+				value := formatAdminTime(*record.ExpiresAt)
+				audit.ExpiresAt = &value
+			}
+			audits = append(audits, audit)
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, accountStatusAuditsResponse{
+			Audits: audits,
+			Total:  len(audits),
+			Limit:  limit,
+			Offset: offset,
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package adminhandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	dusers "socialpredict/internal/domain/users"
+)
+
+type accountStatusManagerMock struct {
+	setFn  func(context.Context, *dusers.User, string, dusers.AccountStatusChange) (*dusers.User, error)
+	listFn func(context.Context, *dusers.User, string, int, int) ([]*dusers.AccountStatusAuditRecord, error)
+}
+
+func (m accountStatusManagerMock) SetAccountStatus(ctx context.Context, actor *dusers.User, username string, change dusers.AccountStatusChange) (*dusers.User, error) {
+	return m.setFn(ctx, actor, username, change)
+}
+
+func (m accountStatusManagerMock) ListAccountStatusAudits(ctx context.Context, actor *dusers.User, username string, limit, offset int) ([]*dusers.AccountStatusAuditRecord, error) {
+	return m.listFn(ctx, actor, username, limit, offset)
+}
+
+func TestUpdateAccountStatusHandlerSuspendsUser(t *testing.T) {
+	now := time.Date(2099, 6, 26, 9, 0, 0, 0, time.UTC)
+	expires := now.Add(48 * time.Hour)
+	svc := accountStatusManagerMock{
+		setFn: func(_ context.Context, actor *dusers.User, username string, change dusers.AccountStatusChange) (*dusers.User, error) {
+			if actor.Username != "admin" || username != "troll" {
+				t.Fatalf("unexpected actor/username: %s/%s", actor.Username, username)
+			}
+			if change.Status != dusers.AccountStatusSuspended || change.Reason != "spam" || !change.Now.Equal(now) || change.ExpiresAt == nil || !change.ExpiresAt.Equal(expires) {
+				t.Fatalf("unexpected change: %+v", change)
+			}
+			at := change.Now
+			return &dusers.User{
+				Username:               username,
+				UserType:               string(dusers.UserTypeRegular),
+				AccountStatus:          change.Status,
+				AccountStatusReason:    change.Reason,
+				AccountStatusBy:        actor.Username,
+				AccountStatusAt:        &at,
+				AccountStatusExpiresAt: change.ExpiresAt,
+			}, nil
+		},
+	}
+	auth := marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}
+	handler := UpdateAccountStatusHandler(svc, auth, func() time.Time { return now })
+	body := `{"status":"suspended","reason":"spam","expiresAt":"2099-06-28T09:00:00Z"}`
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPatch, "/v0/admin/users/troll/account-status", bytes.NewBufferString(body)), map[string]string{"username": "troll"})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope handlers.SuccessEnvelope[adminUserResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Result.AccountStatus != string(dusers.AccountStatusSuspended) || envelope.Result.AccountStatusReason != "spam" {
+		t.Fatalf("unexpected response: %+v", envelope.Result)
+	}
+}
+
+func TestUpdateAccountStatusHandlerRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"malformed expiry", `{"status":"suspended","reason":"spam","expiresAt":"tomorrow"}`, nil, http.StatusBadRequest},
+		{"domain validation", `{"status":"frozen","reason":"spam"}`, dusers.ErrInvalidUserData, http.StatusBadRequest},
+		{"admin target", `{"status":"banned","reason":"spam"}`, dusers.ErrUnauthorized, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := accountStatusManagerMock{setFn: func(context.Context, *dusers.User, string, dusers.AccountStatusChange) (*dusers.User, error) {
+				if tt.err == nil {
+					t.Fatalf("service should not be called")
+				}
+				return nil, tt.err
+			}}
+			handler := UpdateAccountStatusHandler(svc, marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}, nil)
+			req := mux.SetURLVars(httptest.NewRequest(http.MethodPatch, "/v0/admin/users/troll/account-status", bytes.NewBufferString(tt.body)), map[string]string{"username": "troll"})
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d body=%s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestListAccountStatusAuditsHandlerReturnsHistory(t *testing.T) {
+	createdAt := time.Date(2026, 6, 26, 9, 0, 0, 0, time.UTC)
+	svc := accountStatusManagerMock{
+		listFn: func(_ context.Context, _ *dusers.User, username string, limit, offset int) ([]*dusers.AccountStatusAuditRecord, error) {
+			if username != "troll" || limit != 10 || offset != 0 {
+				t.Fatalf("unexpected list args: %s limit=%d offset=%d", username, limit, offset)
+			}
+			return []*dusers.AccountStatusAuditRecord{{
+				ID:            1,
+				Username:      "troll",
+				ActorUsername: "admin",
+				Action:        dusers.AccountStatusActionBan,
+				FromStatus:    dusers.AccountStatusActive,
+				ToStatus:      dusers.AccountStatusBanned,
+				Reason:        "abuse",
+				CreatedAt:     createdAt,
+			}}, nil
+		},
+	}
+	handler := ListAccountStatusAuditsHandler(svc, marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}})
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/admin/users/troll/account-status-audits?limit=10", nil), map[string]string{"username": "troll"})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope handlers.SuccessEnvelope[accountStatusAuditsResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Result.Total != 1 || envelope.Result.Audits[0].Action != "ban" || envelope.Result.Audits[0].ToStatus != "banned" {
+		t.Fatalf("unexpected audits response: %+v", envelope.Result)
+	}
+}
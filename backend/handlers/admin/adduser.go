@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"socialpredict/handlers"
 	"socialpredict/handlers/authhttp"
+	"socialpredict/internal/domain/permissions"
 	dusers "socialpredict/internal/domain/users"
 	authsvc "socialpredict/internal/service/auth"
 	configsvc "socialpredict/internal/service/config"
@@ -81,7 +82,7 @@ func processAddUser(r *http.Request, userCreator adminUserCreator, configService
 	if auth == nil {
 		return nil, &handlerError{message: "authentication service unavailable", statusCode: http.StatusInternalServerError, reason: handlers.ReasonInternalError}
 	}
-	if _, authErr := auth.RequireAdmin(r); authErr != nil {
+	if _, authErr := authsvc.RequirePermission(auth, r, permissions.UsersCreate); authErr != nil {
 		return nil, &handlerError{
 			message:    authErr.Message,
 			statusCode: authhttp.StatusCode(authErr),
@@ -0,0 +1,170 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	analytics "socialpredict/internal/domain/analytics"
+	"socialpredict/internal/domain/permissions"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/logger"
+)
+
+type economyHistoryService interface {
+	GetEconomyHistory(ctx context.Context, from, to, now time.Time) ([]analytics.EconomyDay, error)
+}
+
+type economyDayResponse struct {
+	Day                string    `json:"day"`
+	MoneySupply        int64     `json:"moneySupply"`
+	NumUsers           int64     `json:"numUsers"`
+	DebtUtilized       int64     `json:"debtUtilized"`
+	UnusedDebt         int64     `json:"unusedDebt"`
+	ActiveBetVolume    int64     `json:"activeBetVolume"`
+	MarketCreationFees int64     `json:"marketCreationFees"`
+	ParticipationFees  int64     `json:"participationFees"`
+	FeesCollected      int64     `json:"feesCollected"`
+	BonusesPaid        int64     `json:"bonusesPaid"`
+	Surplus            int64     `json:"surplus"`
+	Volume             int64     `json:"volume"`
+	Trades             int64     `json:"trades"`
+	ActiveTraders      int64     `json:"activeTraders"`
+	NewMarkets         int64     `json:"newMarkets"`
+	Resolutions        int64     `json:"resolutions"`
+	Final              bool      `json:"final"`
+	RecordedAt         time.Time `json:"recordedAt"`
+}
+
+type economyHistoryResponse struct {
+	Days []economyDayResponse `json:"days"`
+}
+
+var economyHistoryCSVHeader = []string{
+	"day", "moneySupply", "numUsers", "debtUtilized", "unusedDebt", "activeBetVolume",
+	"marketCreationFees", "participationFees", "feesCollected", "bonusesPaid", "surplus",
+	"volume", "trades", "activeTraders", "newMarkets", "resolutions", "final", "recordedAt",
+}
+
+// GetEconomyHistoryHandler serves GET /v0/admin/economy/history?from=&to=&format=
+// with one row per recorded UTC day. Bounds are RFC3339 and default to the
+// last ninety days; format=csv downloads the same rows as CSV.
+func GetEconomyHistoryHandler(svc economyHistoryService, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if _, ok := requirePermission(w, r, auth, permissions.EconomyView); !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		query := r.URL.Query()
+		from, fromErr := parseEconomyHistoryBound(query.Get("from"))
+		to, toErr := parseEconomyHistoryBound(query.Get("to"))
+		format := strings.ToLower(strings.TrimSpace(query.Get("format")))
+		if fromErr != nil || toErr != nil || (format != "" && format != "json" && format != "csv") {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		days, err := svc.GetEconomyHistory(r.Context(), from, to, now().UTC())
+		if err != nil {
+			if errors.Is(err, analytics.ErrInvalidHistoryRange) {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+				return
+			}
+			logger.LogError("EconomyHistory", "GetEconomyHistory", err)
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		rows := make([]economyDayResponse, 0, len(days))
+		for _, day := range days {
+			rows = append(rows, newEconomyDayResponse(day))
+		}
+		if format == "csv" {
+			writeEconomyHistoryCSV(w, rows)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, economyHistoryResponse{Days: rows})
+	}
+}
+
+func parseEconomyHistoryBound(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+func newEconomyDayResponse(day analytics.EconomyDay) economyDayResponse {
+	return economyDayResponse{
+		Day:                day.Day.UTC().Format(time.DateOnly),
+		MoneySupply:        day.MoneySupply,
+		NumUsers:           day.NumUsers,
+		DebtUtilized:       day.DebtUtilized,
+		UnusedDebt:         day.UnusedDebt,
+		ActiveBetVolume:    day.ActiveBetVolume,
+		MarketCreationFees: day.MarketCreationFees,
+		ParticipationFees:  day.ParticipationFees,
+		FeesCollected:      day.MarketCreationFees + day.ParticipationFees,
+		BonusesPaid:        day.BonusesPaid,
+		Surplus:            day.Surplus,
+		Volume:             day.Volume,
+		Trades:             day.Trades,
+		ActiveTraders:      day.ActiveTraders,
+		NewMarkets:         day.NewMarkets,
+		Resolutions:        day.Resolutions,
+		Final:              day.Final,
+		RecordedAt:         day.RecordedAt.UTC(),
+	}
+}
+
+func writeEconomyHistoryCSV(w http.ResponseWriter, rows []economyDayResponse) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="economy-history.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write(economyHistoryCSVHeader)
+	for _, row := range rows {
+		_ = writer.Write([]string{
+			row.Day,
+			strconv.FormatInt(row.MoneySupply, 10),
+			strconv.FormatInt(row.NumUsers, 10),
+			strconv.FormatInt(row.DebtUtilized, 10),
+			strconv.FormatInt(row.UnusedDebt, 10),
+			strconv.FormatInt(row.ActiveBetVolume, 10),
+			strconv.FormatInt(row.MarketCreationFees, 10),
+			strconv.FormatInt(row.ParticipationFees, 10),
+			strconv.FormatInt(row.FeesCollected, 10),
+			strconv.FormatInt(row.BonusesPaid, 10),
+			strconv.FormatInt(row.Surplus, 10),
+			strconv.FormatInt(row.Volume, 10),
+			strconv.FormatInt(row.Trades, 10),
+			strconv.FormatInt(row.ActiveTraders, 10),
+			strconv.FormatInt(row.NewMarkets, 10),
+			strconv.FormatInt(row.Resolutions, 10),
+			strconv.FormatBool(row.Final),
+			row.RecordedAt.Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		logger.LogError("EconomyHistory", "WriteCSV", err)
+	}
+}
@@ -0,0 +1,107 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"socialpredict/handlers"
+	analytics "socialpredict/internal/domain/analytics"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+type economyHistoryServiceMock struct {
+	from, to time.Time
+	err      error
+}
+
+func (m *economyHistoryServiceMock) GetEconomyHistory(_ context.Context, from, to, _ time.Time) ([]analytics.EconomyDay, error) {
+	m.from, m.to = from, to
+	if m.err != nil {
+		return nil, m.err
+	}
+	day := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	return []analytics.EconomyDay{{
+		Day:                day,
+		MoneySupply:        1000,
+		NumUsers:           2,
+		DebtUtilized:       300,
+		UnusedDebt:         700,
+		MarketCreationFees: 50,
+		ParticipationFees:  4,
+		EconomyActivity:    analytics.EconomyActivity{Volume: 220, Trades: 4, ActiveTraders: 2, NewMarkets: 1},
+		Final:              true,
+		RecordedAt:         day.Add(20 * time.Hour),
+	}}, nil
+}
+
+func TestGetEconomyHistoryHandlerReturnsDays(t *testing.T) {
+	svc := &economyHistoryServiceMock{}
+	rec := httptest.NewRecorder()
+
+	GetEconomyHistoryHandler(svc, webhookAdminAuth(), nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/admin/economy/history?from=2026-07-01T00:00:00Z", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+	if !svc.from.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) || !svc.to.IsZero() {
+		t.Fatalf("service called with from=%s to=%s", svc.from, svc.to)
+	}
+	var envelope handlers.SuccessEnvelope[economyHistoryResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(envelope.Result.Days) != 1 {
+		t.Fatalf("days = %+v", envelope.Result.Days)
+	}
+	if day := envelope.Result.Days[0]; day.Day != "2026-07-10" || day.FeesCollected != 54 || day.Volume != 220 || !day.Final {
+		t.Fatalf("unexpected day %+v", day)
+	}
+}
+
+func TestGetEconomyHistoryHandlerExportsCSV(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	GetEconomyHistoryHandler(&economyHistoryServiceMock{}, webhookAdminAuth(), nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/admin/economy/history?format=csv", nil))
+
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("status = %d content-type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 2 || records[0][0] != "day" || records[1][0] != "2026-07-10" || records[1][8] != "54" {
+		t.Fatalf("unexpected csv %v", records)
+	}
+	if records[1][len(records[1])-1] != "2026-07-10T20:00:00Z" {
+		t.Fatalf("recordedAt = %q", records[1][len(records[1])-1])
+	}
+}
+
+func TestGetEconomyHistoryHandlerRejectsBadRequests(t *testing.T) {
+	cases := []struct {
+		name   string
+		target string
+		auth   marketReviewAuthMock
+		svc    *economyHistoryServiceMock
+		status int
+	}{
+		{"bad bound", "/v0/admin/economy/history?from=yesterday", webhookAdminAuth(), &economyHistoryServiceMock{}, http.StatusBadRequest},
+		{"bad format", "/v0/admin/economy/history?format=xml", webhookAdminAuth(), &economyHistoryServiceMock{}, http.StatusBadRequest},
+		{"bad range", "/v0/admin/economy/history", webhookAdminAuth(), &economyHistoryServiceMock{err: analytics.ErrInvalidHistoryRange}, http.StatusBadRequest},
+		{"anonymous", "/v0/admin/economy/history", marketReviewAuthMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}, &economyHistoryServiceMock{}, http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		GetEconomyHistoryHandler(tc.svc, tc.auth, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+		if rec.Code != tc.status {
+			t.Fatalf("%s: status = %d, want %d body=%s", tc.name, rec.Code, tc.status, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package adminhandlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"socialpredict/handlers"
+	analytics "socialpredict/internal/domain/analytics"
+	"socialpredict/internal/domain/permissions"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/logger"
+)
+
+type economyReconciliationService interface {
+	Reconcile(ctx context.Context, now time.Time) (*analytics.ReconciliationReport, error)
+}
+
+// GetEconomyReconciliationHandler serves GET /v0/admin/economy/reconciliation,
+// replaying the ledger and returning the discrepancy report. It recomputes
+// every market, so it is meant for on-demand checks rather than polling.
+func GetEconomyReconciliationHandler(svc economyReconciliationService, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if _, ok := requirePermission(w, r, auth, permissions.EconomyView); !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		report, err := svc.Reconcile(r.Context(), now())
+		if err != nil {
+			logger.LogError("EconomyReconciliation", "Reconcile", err)
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, report)
+	}
+}
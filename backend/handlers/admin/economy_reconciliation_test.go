@@ -0,0 +1,66 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"socialpredict/handlers"
+	analytics "socialpredict/internal/domain/analytics"
+)
+
+type economyReconciliationServiceMock struct {
+	err error
+}
+
+func (m economyReconciliationServiceMock) Reconcile(_ context.Context, now time.Time) (*analytics.ReconciliationReport, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &analytics.ReconciliationReport{
+		GeneratedAt:  now,
+		UsersChecked: 2,
+		Discrepancies: []analytics.ReconciliationDiscrepancy{{
+			Check:    analytics.ReconciliationCheckUserBalance,
+			Username: "carol",
+			Field:    "accountBalance",
+			Expected: 10,
+			Actual:   17,
+		}},
+	}, nil
+}
+
+func TestGetEconomyReconciliationHandlerReturnsReport(t *testing.T) {
+	now := time.Date(2026, 7, 11, 9, 0, 0, 0, time.UTC)
+	rec := httptest.NewRecorder()
+
+	GetEconomyReconciliationHandler(economyReconciliationServiceMock{}, webhookAdminAuth(), func() time.Time { return now }).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/admin/economy/reconciliation", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope handlers.SuccessEnvelope[analytics.ReconciliationReport]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	report := envelope.Result
+	if report.Balanced || !report.GeneratedAt.Equal(now) || len(report.Discrepancies) != 1 || report.Discrepancies[0].Username != "carol" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestGetEconomyReconciliationHandlerHidesServiceErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	GetEconomyReconciliationHandler(economyReconciliationServiceMock{err: errors.New("boom")}, webhookAdminAuth(), nil).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/admin/economy/reconciliation", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+}
@@ -0,0 +1,237 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+const defaultAdminInvitesLimit = 100
+const maxAdminInvitesLimit = 250
+
+type inviteManager interface {
+	CreateInvite(ctx context.Context, actor *dusers.User, req dusers.InviteCreateRequest) (*dusers.InviteCreateResult, error)
+	ListInvites(ctx context.Context, actor *dusers.User, filters dusers.InviteListFilters) (*dusers.InvitePage, error)
+	RevokeInvite(ctx context.Context, actor *dusers.User, inviteID int64, revokedAt time.Time) (*dusers.Invite, error)
+}
+
+type createInviteRequest struct {
+	UserType              string     `json:"usertype"`
+	InitialAccountBalance *int64     `json:"initialAccountBalance"`
+	MaxUses               int64      `json:"maxUses"`
+	ExpiresAt             *time.Time `json:"expiresAt"`
+	Note                  string     `json:"note"`
+}
+
+type inviteResponse struct {
+	ID                    int64   `json:"id"`
+	Code                  string  `json:"code,omitempty"`
+	CodePrefix            string  `json:"codePrefix"`
+	CreatedBy             string  `json:"createdBy"`
+	UserType              string  `json:"usertype"`
+	InitialAccountBalance *int64  `json:"initialAccountBalance,omitempty"`
+	MaxUses               int64   `json:"maxUses"`
+	UseCount              int64   `json:"useCount"`
+	RemainingUses         int64   `json:"remainingUses"`
+	ExpiresAt             *string `json:"expiresAt,omitempty"`
+	RevokedAt             *string `json:"revokedAt,omitempty"`
+	RevokedBy             string  `json:"revokedBy,omitempty"`
+	Note                  string  `json:"note,omitempty"`
+	CreatedAt             string  `json:"createdAt,omitempty"`
+}
+
+type invitesResponse struct {
+	Invites []inviteResponse `json:"invites"`
+	Total   int64            `json:"total"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+}
+
+// ListInvitesHandler lists invite codes for admins (all) and active moderators (their own).
+func ListInvitesHandler(svc inviteManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		actor, ok := requireInviteActor(w, r, auth)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		query := r.URL.Query()
+		limit, ok := parseAdminUserListInt(w, query.Get("limit"), defaultAdminInvitesLimit, 1, maxAdminInvitesLimit)
+		if !ok {
+			return
+		}
+		offset, ok := parseAdminUserListInt(w, query.Get("offset"), 0, 0, 100000)
+		if !ok {
+			return
+		}
+
+		page, err := svc.ListInvites(r.Context(), actor, dusers.InviteListFilters{
+			CreatedBy: strings.TrimSpace(query.Get("createdBy")),
+			Limit:     limit,
+			Offset:    offset,
+		})
+		if err != nil {
+			writeInviteError(w, err)
+			return
+		}
+
+		responseInvites := make([]inviteResponse, 0, len(page.Invites))
+		for _, invite := range page.Invites {
+			responseInvites = append(responseInvites, inviteResponseFromInvite(invite, ""))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, invitesResponse{
+			Invites: responseInvites,
+			Total:   page.Total,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	}
+}
+
+// CreateInviteHandler mints a new invite code. The plaintext code is only returned here.
+func CreateInviteHandler(svc inviteManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		actor, ok := requireInviteActor(w, r, auth)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		var req createInviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		result, err := svc.CreateInvite(r.Context(), actor, dusers.InviteCreateRequest{
+			UserType:              req.UserType,
+			InitialAccountBalance: req.InitialAccountBalance,
+			MaxUses:               req.MaxUses,
+			ExpiresAt:             req.ExpiresAt,
+			Note:                  req.Note,
+			Now:                   now().UTC(),
+		})
+		if err != nil {
+			writeInviteError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusCreated, inviteResponseFromInvite(result.Invite, result.Code))
+	}
+}
+
+// RevokeInviteHandler disables an invite so it can no longer be redeemed.
+func RevokeInviteHandler(svc inviteManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		actor, ok := requireInviteActor(w, r, auth)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		inviteID, err := strconv.ParseInt(strings.TrimSpace(mux.Vars(r)["id"]), 10, 64)
+		if err != nil || inviteID <= 0 {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		invite, err := svc.RevokeInvite(r.Context(), actor, inviteID, now().UTC())
+		if err != nil {
+			writeInviteError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, inviteResponseFromInvite(invite, ""))
+	}
+}
+
+func requireInviteActor(w http.ResponseWriter, r *http.Request, auth authsvc.Authenticator) (*dusers.User, bool) {
+	if auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return nil, false
+	}
+	actor, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return nil, false
+	}
+	return actor, true
+}
+
+func writeInviteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dusers.ErrInviteNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	default:
+		writeAdminUserError(w, err)
+	}
+}
+
+func inviteResponseFromInvite(invite *dusers.Invite, code string) inviteResponse {
+	if invite == nil {
+		return inviteResponse{}
+	}
+
+	response := inviteResponse{
+		ID:                    invite.ID,
+		Code:                  code,
+		CodePrefix:            invite.CodePrefix,
+		CreatedBy:             invite.CreatedBy,
+		UserType:              invite.UserType,
+		InitialAccountBalance: invite.InitialAccountBalance,
+		MaxUses:               invite.MaxUses,
+		UseCount:              invite.UseCount,
+		RemainingUses:         invite.RemainingUses(),
+		RevokedBy:             invite.RevokedBy,
+		Note:                  invite.Note,
+		CreatedAt:             formatAdminTime(invite.CreatedAt),
+	}
+	if invite.ExpiresAt != nil {
+		value := formatAdminTime(*invite.ExpiresAt)
+		response.ExpiresAt = &value
+	}
+	if invite.RevokedAt != nil {
+		value := formatAdminTime(*invite.RevokedAt)
+		response.RevokedAt = &value
+	}
+	return response
+}
@@ -0,0 +1,58 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"socialpredict/handlers"
+	dusers "socialpredict/internal/domain/users"
+)
+
+type inviteManagerMock struct {
+	listFn func(context.Context, *dusers.User, dusers.InviteListFilters) (*dusers.InvitePage, error)
+}
+
+func (m inviteManagerMock) CreateInvite(context.Context, *dusers.User, dusers.InviteCreateRequest) (*dusers.InviteCreateResult, error) {
+	return nil, nil
+}
+
+func (m inviteManagerMock) ListInvites(ctx context.Context, actor *dusers.User, filters dusers.InviteListFilters) (*dusers.InvitePage, error) {
+	return m.listFn(ctx, actor, filters)
+}
+
+func (m inviteManagerMock) RevokeInvite(context.Context, *dusers.User, int64, time.Time) (*dusers.Invite, error) {
+	return nil, nil
+}
+
+func TestListInvitesHandlerReportsTheRepositoryTotal(t *testing.T) {
+	svc := inviteManagerMock{
+		listFn: func(_ context.Context, _ *dusers.User, filters dusers.InviteListFilters) (*dusers.InvitePage, error) {
+			if filters.Limit != 2 || filters.Offset != 4 {
+				t.Fatalf("unexpected filters: %+v", filters)
+			}
+			return &dusers.InvitePage{
+				Invites: []*dusers.Invite{{ID: 5, CreatedBy: "admin"}, {ID: 6, CreatedBy: "admin"}},
+				Total:   9,
+			}, nil
+		},
+	}
+	auth := marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}
+	rec := httptest.NewRecorder()
+
+	ListInvitesHandler(svc, auth).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/admin/invites?limit=2&offset=4", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope handlers.SuccessEnvelope[invitesResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(envelope.Result.Invites) != 2 || envelope.Result.Total != 9 || envelope.Result.Limit != 2 || envelope.Result.Offset != 4 {
+		t.Fatalf("unexpected response: %+v", envelope.Result)
+	}
+}
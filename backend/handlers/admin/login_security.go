@@ -0,0 +1,225 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+const defaultAdminLoginEventsLimit = 100
+const maxAdminLoginEventsLimit = 500
+
+type loginSecurityManager interface {
+	GetLoginThrottle(ctx context.Context, actor *dusers.User, username string) (*dusers.LoginThrottle, error)
+	UnlockLogin(ctx context.Context, actor *dusers.User, username, reason string, now time.Time) (*dusers.LoginThrottle, error)
+	ListLoginAuditEvents(ctx context.Context, actor *dusers.User, filters dusers.LoginAuditFilters) ([]*dusers.LoginAuditEvent, error)
+}
+
+type unlockLoginRequest struct {
+	Reason string `json:"reason"`
+}
+
+type loginLockResponse struct {
+	Username       string  `json:"username"`
+	FailedAttempts int     `json:"failedAttempts"`
+	Locked         bool    `json:"locked"`
+	LastFailedAt   *string `json:"lastFailedAt,omitempty"`
+	NextAttemptAt  *string `json:"nextAttemptAt,omitempty"`
+	LockedUntil    *string `json:"lockedUntil,omitempty"`
+}
+
+type loginEventResponse struct {
+	ID            int64  `json:"id"`
+	Username      string `json:"username"`
+	Outcome       string `json:"outcome"`
+	Method        string `json:"method"`
+	Reason        string `json:"reason,omitempty"`
+	IPAddress     string `json:"ipAddress,omitempty"`
+	UserAgent     string `json:"userAgent,omitempty"`
+	ActorUsername string `json:"actorUsername,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+type loginEventsResponse struct {
+	Events []loginEventResponse `json:"events"`
+	Total  int                  `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+// ListLoginEventsHandler returns the login audit trail, newest first.
+func ListLoginEventsHandler(svc loginSecurityManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.UsersManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		query := r.URL.Query()
+		limit, ok := parseAdminUserListInt(w, query.Get("limit"), defaultAdminLoginEventsLimit, 1, maxAdminLoginEventsLimit)
+		if !ok {
+			return
+		}
+		offset, ok := parseAdminUserListInt(w, query.Get("offset"), 0, 0, 100000)
+		if !ok {
+			return
+		}
+		filters := dusers.LoginAuditFilters{
+			Username:  strings.TrimSpace(query.Get("username")),
+			Outcome:   dusers.LoginOutcome(strings.TrimSpace(query.Get("outcome"))),
+			IPAddress: strings.TrimSpace(query.Get("ip")),
+			Limit:     limit,
+			Offset:    offset,
+		}
+		if raw := strings.TrimSpace(query.Get("since")); raw != "" {
+			since, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+				return
+			}
+			filters.Since = &since
+		}
+
+		events, err := svc.ListLoginAuditEvents(r.Context(), admin, filters)
+		if err != nil {
+			writeAdminUserError(w, err)
+			return
+		}
+
+		responseEvents := make([]loginEventResponse, 0, len(events))
+		for _, event := range events {
+			responseEvents = append(responseEvents, loginEventResponse{
+				ID:            event.ID,
+				Username:      event.Username,
+				Outcome:       string(event.Outcome),
+				Method:        event.Method,
+				Reason:        event.Reason,
+				IPAddress:     event.IPAddress,
+				UserAgent:     event.UserAgent,
+				ActorUsername: event.ActorUsername,
+				CreatedAt:     formatAdminTime(event.CreatedAt),
+			})
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, loginEventsResponse{
+			Events: responseEvents,
+			Total:  len(responseEvents),
+			Limit:  limit,
+			Offset: offset,
+		})
+	}
+}
+
+// GetLoginLockHandler reports an account's failed-login counters and lockout state.
+func GetLoginLockHandler(svc loginSecurityManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.UsersManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		username, ok := adminUsernameFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		throttle, err := svc.GetLoginThrottle(r.Context(), admin, username)
+		if err != nil {
+			writeAdminUserError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, loginLockResponseFromThrottle(throttle, now().UTC()))
+	}
+}
+
+// UnlockLoginHandler clears an account's failed-login counters and lockout.
+func UnlockLoginHandler(svc loginSecurityManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.UsersManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		username, ok := adminUsernameFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		var req unlockLoginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		current := now().UTC()
+		throttle, err := svc.UnlockLogin(r.Context(), admin, username, req.Reason, current)
+		if err != nil {
+			writeAdminUserError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, loginLockResponseFromThrottle(throttle, current))
+	}
+}
+
+func loginLockResponseFromThrottle(throttle *dusers.LoginThrottle, now time.Time) loginLockResponse {
+	if throttle == nil {
+		return loginLockResponse{}
+	}
+
+	response := loginLockResponse{
+		Username:       throttle.Username,
+		FailedAttempts: throttle.FailedAttempts,
+		Locked:         throttle.Locked(now),
+	}
+	if throttle.LastFailedAt != nil {
+		value := formatAdminTime(*throttle.LastFailedAt)
+		response.LastFailedAt = &value
+	}
+	if throttle.NextAttemptAt != nil {
+		value := formatAdminTime(*throttle.NextAttemptAt)
+		response.NextAttemptAt = &value
+	}
+	if throttle.LockedUntil != nil {
+		value := formatAdminTime(*throttle.LockedUntil)
+		response.LockedUntil = &value
+	}
+	return response
+}
@@ -12,6 +12,7 @@ import (
 
 	"socialpredict/handlers"
 	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
 	authsvc "socialpredict/internal/service/auth"
 )
 
@@ -90,7 +91,7 @@ func GetMarketGovernanceSettingsHandler(svc marketDescriptionAmendmentReviewer,
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		if _, ok := requireAdminForMarketReview(w, r, auth); !ok {
+		if _, ok := requirePermission(w, r, auth, permissions.MarketsAmendmentsReview); !ok {
 			return
 		}
 		if svc == nil {
@@ -112,7 +113,7 @@ func UpdateMarketGovernanceSettingsHandler(svc marketDescriptionAmendmentReviewe
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsAmendmentsReview)
 		if !ok {
 			return
 		}
@@ -147,7 +148,7 @@ func ListMarketDescriptionAmendmentsHandler(svc marketDescriptionAmendmentReview
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		if _, ok := requireAdminForMarketReview(w, r, auth); !ok {
+		if _, ok := requirePermission(w, r, auth, permissions.MarketsAmendmentsReview); !ok {
 			return
 		}
 		if svc == nil {
@@ -182,7 +183,7 @@ func ReviewMarketDescriptionAmendmentHandler(svc marketDescriptionAmendmentRevie
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsAmendmentsReview)
 		if !ok {
 			return
 		}
@@ -215,7 +216,7 @@ func ReviewGroupedMarketDescriptionAmendmentsHandler(svc marketDescriptionAmendm
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsAmendmentsReview)
 		if !ok {
 			return
 		}
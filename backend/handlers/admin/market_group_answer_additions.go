@@ -12,6 +12,7 @@ import (
 
 	"socialpredict/handlers"
 	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
 	authsvc "socialpredict/internal/service/auth"
 )
 
@@ -57,7 +58,7 @@ func ListMarketGroupAnswerAdditionsHandler(svc marketGroupAnswerAdditionReviewer
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		if _, ok := requireAdminForMarketReview(w, r, auth); !ok {
+		if _, ok := requirePermission(w, r, auth, permissions.MarketsApprove); !ok {
 			return
 		}
 		if svc == nil {
@@ -92,7 +93,7 @@ func ReviewMarketGroupAnswerAdditionHandler(svc marketGroupAnswerAdditionReviewe
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsApprove)
 		if !ok {
 			return
 		}
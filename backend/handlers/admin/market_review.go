@@ -12,9 +12,8 @@ import (
 	"github.com/gorilla/mux"
 
 	"socialpredict/handlers"
-	"socialpredict/handlers/authhttp"
 	dmarkets "socialpredict/internal/domain/markets"
-	dusers "socialpredict/internal/domain/users"
+	"socialpredict/internal/domain/permissions"
 	authsvc "socialpredict/internal/service/auth"
 )
 
@@ -156,7 +155,7 @@ func ApproveMarketHandler(svc marketReviewer, auth authsvc.Authenticator) http.H
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsApprove)
 		if !ok {
 			return
 		}
@@ -189,7 +188,7 @@ func ApproveMarketGroupHandler(svc marketGroupReviewer, auth authsvc.Authenticat
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsApprove)
 		if !ok {
 			return
 		}
@@ -222,7 +221,7 @@ func ListReviewMarketsHandler(svc marketReviewLister, auth authsvc.Authenticator
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		if _, ok := requireAdminForMarketReview(w, r, auth); !ok {
+		if _, ok := requirePermission(w, r, auth, permissions.MarketsApprove); !ok {
 			return
 		}
 		if svc == nil {
@@ -258,7 +257,7 @@ func RejectMarketHandler(svc marketReviewer, auth authsvc.Authenticator) http.Ha
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsApprove)
 		if !ok {
 			return
 		}
@@ -291,7 +290,7 @@ func RejectMarketGroupHandler(svc marketGroupReviewer, auth authsvc.Authenticato
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsApprove)
 		if !ok {
 			return
 		}
@@ -324,7 +323,7 @@ func ReassignMarketStewardHandler(svc marketStewardReassigner, auth authsvc.Auth
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsStewardAssign)
 		if !ok {
 			return
 		}
@@ -357,7 +356,7 @@ func ReassignMarketGroupStewardHandler(svc marketGroupStewardReassigner, auth au
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.MarketsStewardAssign)
 		if !ok {
 			return
 		}
@@ -390,7 +389,7 @@ func UpdateMarketTagsHandler(svc marketTagAdjuster, auth authsvc.Authenticator)
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.TagsManage)
 		if !ok {
 			return
 		}
@@ -423,7 +422,7 @@ func UpdateMarketGroupTagsHandler(svc marketGroupTagAdjuster, auth authsvc.Authe
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.TagsManage)
 		if !ok {
 			return
 		}
@@ -450,19 +449,6 @@ func UpdateMarketGroupTagsHandler(svc marketGroupTagAdjuster, auth authsvc.Authe
 	}
 }
 
-func requireAdminForMarketReview(w http.ResponseWriter, r *http.Request, auth authsvc.Authenticator) (*dusers.User, bool) {
-	if auth == nil {
-		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
-		return nil, false
-	}
-	admin, authErr := auth.RequireAdmin(r)
-	if authErr != nil {
-		_ = authhttp.WriteFailure(w, authErr)
-		return nil, false
-	}
-	return admin, true
-}
-
 func marketIDFromRequest(w http.ResponseWriter, r *http.Request) (int64, bool) {
 	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 	if err != nil || id <= 0 {
@@ -11,6 +11,7 @@ import (
 
 	"socialpredict/handlers"
 	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
 	authsvc "socialpredict/internal/service/auth"
 )
 
@@ -52,7 +53,7 @@ func ListAdminMarketTagsHandler(svc marketTagManager, auth authsvc.Authenticator
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		if _, ok := requireAdminForMarketReview(w, r, auth); !ok {
+		if _, ok := requirePermission(w, r, auth, permissions.TagsManage); !ok {
 			return
 		}
 		if svc == nil {
@@ -79,7 +80,7 @@ func CreateAdminMarketTagHandler(svc marketTagManager, auth authsvc.Authenticato
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForMarketReview(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.TagsManage)
 		if !ok {
 			return
 		}
@@ -108,7 +109,7 @@ func UpdateAdminMarketTagHandler(svc marketTagManager, auth authsvc.Authenticato
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		if _, ok := requireAdminForMarketReview(w, r, auth); !ok {
+		if _, ok := requirePermission(w, r, auth, permissions.TagsManage); !ok {
 			return
 		}
 		if svc == nil {
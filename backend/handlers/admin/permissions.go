@@ -0,0 +1,273 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+type roleManager interface {
+	ListRoles(ctx context.Context, actor permissions.Subject) ([]*permissions.Role, error)
+	SaveRole(ctx context.Context, actor permissions.Subject, update permissions.RoleUpdate, now time.Time) (*permissions.Role, error)
+	UserRoles(ctx context.Context, actor permissions.Subject, username string) (*permissions.UserRoles, error)
+	SetUserRoles(ctx context.Context, actor permissions.Subject, username string, roles []string, now time.Time) (*permissions.UserRoles, error)
+}
+
+type permissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type permissionsResponse struct {
+	Permissions []permissionResponse `json:"permissions"`
+}
+
+type roleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	BuiltIn     bool     `json:"builtIn"`
+	Superuser   bool     `json:"superuser"`
+	Permissions []string `json:"permissions"`
+	UpdatedBy   string   `json:"updatedBy,omitempty"`
+	UpdatedAt   string   `json:"updatedAt,omitempty"`
+}
+
+type rolesResponse struct {
+	Roles []roleResponse `json:"roles"`
+}
+
+type updateRoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type userRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+type userRolesResponse struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+}
+
+// ListPermissionsHandler returns the permission registry.
+func ListPermissionsHandler(auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if _, ok := requirePermission(w, r, auth, permissions.RolesManage); !ok {
+			return
+		}
+
+		registry := permissions.Registry()
+		response := permissionsResponse{Permissions: make([]permissionResponse, 0, len(registry))}
+		for _, definition := range registry {
+			response.Permissions = append(response.Permissions, permissionResponse{
+				Name:        string(definition.Name),
+				Description: definition.Description,
+			})
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// ListRolesHandler returns every role with its permission grants.
+func ListRolesHandler(svc roleManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.RolesManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		roles, err := svc.ListRoles(r.Context(), admin.PermissionSubject())
+		if err != nil {
+			writeRoleError(w, err)
+			return
+		}
+		response := rolesResponse{Roles: make([]roleResponse, 0, len(roles))}
+		for _, role := range roles {
+			response.Roles = append(response.Roles, roleResponseFromDomain(role))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// UpdateRoleHandler creates a custom role or replaces a role's permission grants.
+func UpdateRoleHandler(svc roleManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.RolesManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		var req updateRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Permissions == nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		role, err := svc.SaveRole(r.Context(), admin.PermissionSubject(), permissions.RoleUpdate{
+			Name:        strings.TrimSpace(mux.Vars(r)["role"]),
+			Description: req.Description,
+			Permissions: req.Permissions,
+		}, now().UTC())
+		if err != nil {
+			writeRoleError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, roleResponseFromDomain(role))
+	}
+}
+
+// GetUserRolesHandler returns the custom roles assigned to an account.
+func GetUserRolesHandler(svc roleManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.RolesManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		username, ok := adminUsernameFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		assigned, err := svc.UserRoles(r.Context(), admin.PermissionSubject(), username)
+		if err != nil {
+			writeRoleError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, userRolesResponse{Username: assigned.Username, Roles: assigned.Roles})
+	}
+}
+
+// UpdateUserRolesHandler replaces the custom roles assigned to an account.
+func UpdateUserRolesHandler(svc roleManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requirePermission(w, r, auth, permissions.RolesManage)
+		if !ok {
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		username, ok := adminUsernameFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		var req userRolesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Roles == nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		assigned, err := svc.SetUserRoles(r.Context(), admin.PermissionSubject(), username, req.Roles, now().UTC())
+		if err != nil {
+			writeRoleError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, userRolesResponse{Username: assigned.Username, Roles: assigned.Roles})
+	}
+}
+
+// requirePermission resolves the current user and ensures it holds permission.
+func requirePermission(w http.ResponseWriter, r *http.Request, auth authsvc.Authenticator, permission permissions.Permission) (*dusers.User, bool) {
+	if auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return nil, false
+	}
+	user, authErr := authsvc.RequirePermission(auth, r, permission)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return nil, false
+	}
+	return user, true
+}
+
+func writeRoleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, permissions.ErrPermissionDenied):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	case errors.Is(err, permissions.ErrUserNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonUserNotFound)
+	case errors.Is(err, permissions.ErrRoleNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, permissions.ErrImmutableRole):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	case errors.Is(err, permissions.ErrUnknownPermission), errors.Is(err, permissions.ErrInvalidRole):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func roleResponseFromDomain(role *permissions.Role) roleResponse {
+	if role == nil {
+		return roleResponse{Permissions: []string{}}
+	}
+	response := roleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		BuiltIn:     role.BuiltIn,
+		Superuser:   role.Superuser,
+		Permissions: make([]string, 0, len(role.Permissions)),
+		UpdatedBy:   role.UpdatedBy,
+	}
+	for _, permission := range role.Permissions {
+		response.Permissions = append(response.Permissions, string(permission))
+	}
+	if !role.UpdatedAt.IsZero() {
+		response.UpdatedAt = formatAdminTime(role.UpdatedAt)
+	}
+	return response
+}
@@ -0,0 +1,153 @@
+package adminhandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+type permissionAuthMock struct {
+	marketReviewAuthMock
+	granted map[permissions.Permission]bool
+}
+
+func (m permissionAuthMock) RequirePermission(_ *http.Request, permission permissions.Permission) (*dusers.User, *authsvc.AuthError) {
+	if !m.granted[permission] {
+		return nil, &authsvc.AuthError{Kind: authsvc.ErrorKindPermissionDenied}
+	}
+	return m.admin, nil
+}
+
+type roleManagerMock struct {
+	saveFn         func(context.Context, permissions.Subject, permissions.RoleUpdate, time.Time) (*permissions.Role, error)
+	setUserRolesFn func(context.Context, permissions.Subject, string, []string, time.Time) (*permissions.UserRoles, error)
+}
+
+func (m roleManagerMock) ListRoles(context.Context, permissions.Subject) ([]*permissions.Role, error) {
+	return []*permissions.Role{{Name: permissions.RoleAdmin, BuiltIn: true, Superuser: true, Permissions: permissions.All()}}, nil
+}
+
+func (m roleManagerMock) SaveRole(ctx context.Context, actor permissions.Subject, update permissions.RoleUpdate, now time.Time) (*permissions.Role, error) {
+	return m.saveFn(ctx, actor, update, now)
+}
+
+func (m roleManagerMock) UserRoles(_ context.Context, _ permissions.Subject, username string) (*permissions.UserRoles, error) {
+	return &permissions.UserRoles{Username: username, Roles: []string{}}, nil
+}
+
+func (m roleManagerMock) SetUserRoles(ctx context.Context, actor permissions.Subject, username string, roles []string, now time.Time) (*permissions.UserRoles, error) {
+	return m.setUserRolesFn(ctx, actor, username, roles, now)
+}
+
+func TestUpdateRoleHandlerSavesGrants(t *testing.T) {
+	delegate := &dusers.User{Username: "security_lead", UserType: string(dusers.UserTypeRegular)}
+	svc := roleManagerMock{
+		saveFn: func(_ context.Context, actor permissions.Subject, update permissions.RoleUpdate, _ time.Time) (*permissions.Role, error) {
+			if actor.Username != "security_lead" || update.Name != "CMS_EDITOR" || len(update.Permissions) != 1 || update.Permissions[0] != "cms.edit" {
+				t.Fatalf("unexpected save args: actor=%+v update=%+v", actor, update)
+			}
+			return &permissions.Role{Name: update.Name, Description: update.Description, Permissions: []permissions.Permission{permissions.CMSEdit}}, nil
+		},
+	}
+	auth := permissionAuthMock{marketReviewAuthMock: marketReviewAuthMock{admin: delegate}, granted: map[permissions.Permission]bool{permissions.RolesManage: true}}
+	handler := UpdateRoleHandler(svc, auth, nil)
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/v0/admin/roles/CMS_EDITOR", bytes.NewBufferString(`{"description":"Content team","permissions":["cms.edit"]}`)), map[string]string{"role": "CMS_EDITOR"})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope handlers.SuccessEnvelope[roleResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Result.Name != "CMS_EDITOR" || len(envelope.Result.Permissions) != 1 || envelope.Result.Permissions[0] != "cms.edit" {
+		t.Fatalf("unexpected role response: %+v", envelope.Result)
+	}
+}
+
+func TestUpdateRoleHandlerMapsDomainErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantReason handlers.FailureReason
+	}{
+		{"immutable", permissions.ErrImmutableRole, http.StatusConflict, handlers.ReasonInvalidState},
+		{"unknown permission", permissions.ErrUnknownPermission, http.StatusBadRequest, handlers.ReasonValidationFailed},
+		{"denied", permissions.ErrPermissionDenied, http.StatusForbidden, handlers.ReasonAuthorizationDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := roleManagerMock{saveFn: func(context.Context, permissions.Subject, permissions.RoleUpdate, time.Time) (*permissions.Role, error) {
+				return nil, tt.err
+			}}
+			handler := UpdateRoleHandler(svc, marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}, nil)
+			req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/v0/admin/roles/ADMIN", bytes.NewBufferString(`{"permissions":[]}`)), map[string]string{"role": "ADMIN"})
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var envelope handlers.FailureEnvelope
+			if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if envelope.Reason != string(tt.wantReason) {
+				t.Fatalf("reason = %q, want %q", envelope.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestUpdateUserRolesHandlerRequiresRolesManage(t *testing.T) {
+	svc := roleManagerMock{setUserRolesFn: func(context.Context, permissions.Subject, string, []string, time.Time) (*permissions.UserRoles, error) {
+		t.Fatalf("service should not be called without roles.manage")
+		return nil, nil
+	}}
+	auth := permissionAuthMock{
+		marketReviewAuthMock: marketReviewAuthMock{admin: &dusers.User{Username: "editor", UserType: string(dusers.UserTypeRegular)}},
+		granted:              map[permissions.Permission]bool{permissions.CMSEdit: true},
+	}
+	handler := UpdateUserRolesHandler(svc, auth, nil)
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/v0/admin/users/editor/roles", bytes.NewBufferString(`{"roles":["CMS_EDITOR"]}`)), map[string]string{"username": "editor"})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403 body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRequirePermissionHonoursDelegatedGrants(t *testing.T) {
+	auth := permissionAuthMock{
+		marketReviewAuthMock: marketReviewAuthMock{admin: &dusers.User{Username: "curator", UserType: string(dusers.UserTypeRegular)}},
+		granted:              map[permissions.Permission]bool{permissions.TagsManage: true},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/v0/admin/market-tags", nil)
+
+	if user, ok := requirePermission(httptest.NewRecorder(), req, auth, permissions.TagsManage); !ok || user.Username != "curator" {
+		t.Fatalf("tags.manage holder should pass, got user=%+v ok=%v", user, ok)
+	}
+	rec := httptest.NewRecorder()
+	if _, ok := requirePermission(rec, req, auth, permissions.MarketsApprove); ok || rec.Code != http.StatusForbidden {
+		t.Fatalf("markets.approve should be denied, got ok=%v status=%d", ok, rec.Code)
+	}
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"socialpredict/handlers"
-	"socialpredict/handlers/authhttp"
+	"socialpredict/internal/domain/permissions"
 	dusers "socialpredict/internal/domain/users"
 	authsvc "socialpredict/internal/service/auth"
 )
@@ -38,6 +38,11 @@ type adminUserResponse struct {
 	ModeratorSuspensionReason string  `json:"moderatorSuspensionReason,omitempty"`
 	ModeratorSuspendedBy      string  `json:"moderatorSuspendedBy,omitempty"`
 	ModeratorSuspendedAt      *string `json:"moderatorSuspendedAt,omitempty"`
+	AccountStatus             string  `json:"accountStatus"`
+	AccountStatusReason       string  `json:"accountStatusReason,omitempty"`
+	AccountStatusBy           string  `json:"accountStatusBy,omitempty"`
+	AccountStatusAt           *string `json:"accountStatusAt,omitempty"`
+	AccountStatusExpiresAt    *string `json:"accountStatusExpiresAt,omitempty"`
 	CreatedAt                 string  `json:"createdAt,omitempty"`
 	UpdatedAt                 string  `json:"updatedAt,omitempty"`
 }
@@ -59,13 +64,17 @@ type adminModeratorSuspensionRequest struct {
 	Reason    string `json:"reason"`
 }
 
-func ListAdminUsersHandler(svc adminUserManager, auth authsvc.Authenticator) http.HandlerFunc {
+func ListAdminUsersHandler(svc adminUserManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		if _, ok := requireAdminForUserManagement(w, r, auth); !ok {
+		if _, ok := requirePermission(w, r, auth, permissions.UsersManage); !ok {
 			return
 		}
 		if svc == nil {
@@ -85,7 +94,7 @@ func ListAdminUsersHandler(svc adminUserManager, auth authsvc.Authenticator) htt
 
 		responseUsers := make([]adminUserResponse, 0, len(users))
 		for _, user := range users {
-			responseUsers = append(responseUsers, adminUserResponseFromUser(user))
+			responseUsers = append(responseUsers, adminUserResponseFromUser(user, now()))
 		}
 
 		_ = handlers.WriteResult(w, http.StatusOK, adminUsersResponse{
@@ -97,13 +106,17 @@ func ListAdminUsersHandler(svc adminUserManager, auth authsvc.Authenticator) htt
 	}
 }
 
-func UpdateAdminUserRoleHandler(svc adminUserManager, auth authsvc.Authenticator) http.HandlerFunc {
+func UpdateAdminUserRoleHandler(svc adminUserManager, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPatch {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForUserManagement(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.UsersManage)
 		if !ok {
 			return
 		}
@@ -131,7 +144,7 @@ func UpdateAdminUserRoleHandler(svc adminUserManager, auth authsvc.Authenticator
 			writeAdminUserError(w, err)
 			return
 		}
-		_ = handlers.WriteResult(w, http.StatusOK, adminUserResponseFromUser(user))
+		_ = handlers.WriteResult(w, http.StatusOK, adminUserResponseFromUser(user, now()))
 	}
 }
 
@@ -145,7 +158,7 @@ func UpdateAdminModeratorSuspensionHandler(svc adminUserManager, auth authsvc.Au
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
 			return
 		}
-		admin, ok := requireAdminForUserManagement(w, r, auth)
+		admin, ok := requirePermission(w, r, auth, permissions.UsersManage)
 		if !ok {
 			return
 		}
@@ -177,23 +190,10 @@ func UpdateAdminModeratorSuspensionHandler(svc adminUserManager, auth authsvc.Au
 			writeAdminUserError(w, err)
 			return
 		}
-		_ = handlers.WriteResult(w, http.StatusOK, adminUserResponseFromUser(user))
+		_ = handlers.WriteResult(w, http.StatusOK, adminUserResponseFromUser(user, now()))
 	}
 }
 
-func requireAdminForUserManagement(w http.ResponseWriter, r *http.Request, auth authsvc.Authenticator) (*dusers.User, bool) {
-	if auth == nil {
-		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
-		return nil, false
-	}
-	admin, authErr := auth.RequireAdmin(r)
-	if authErr != nil {
-		_ = authhttp.WriteFailure(w, authErr)
-		return nil, false
-	}
-	return admin, true
-}
-
 func adminUserListFiltersFromRequest(w http.ResponseWriter, r *http.Request) (dusers.ListFilters, bool) {
 	query := r.URL.Query()
 	limit, ok := parseAdminUserListInt(w, query.Get("limit"), defaultAdminUsersLimit, 1, maxAdminUsersLimit)
@@ -250,7 +250,7 @@ func writeAdminUserError(w http.ResponseWriter, err error) {
 	}
 }
 
-func adminUserResponseFromUser(user *dusers.User) adminUserResponse {
+func adminUserResponseFromUser(user *dusers.User, now time.Time) adminUserResponse {
 	if user == nil {
 		return adminUserResponse{}
 	}
@@ -265,6 +265,7 @@ func adminUserResponseFromUser(user *dusers.User) adminUserResponse {
 		MustChangePassword:        user.MustChangePassword,
 		ModeratorSuspensionReason: user.ModeratorSuspensionReason,
 		ModeratorSuspendedBy:      user.ModeratorSuspendedBy,
+		AccountStatus:             string(user.EffectiveAccountStatus(now.UTC())),
 		CreatedAt:                 formatAdminTime(user.CreatedAt),
 		UpdatedAt:                 formatAdminTime(user.UpdatedAt),
 	}
@@ -272,6 +273,18 @@ func adminUserResponseFromUser(user *dusers.User) adminUserResponse {
 		value := formatAdminTime(*user.ModeratorSuspendedAt)
 		response.ModeratorSuspendedAt = &value
 	}
+	if response.AccountStatus != string(dusers.AccountStatusActive) {
+		response.AccountStatusReason = user.AccountStatusReason
+		response.AccountStatusBy = user.AccountStatusBy
+		if user.AccountStatusAt != nil {
+			value := formatAdminTime(*user.AccountStatusAt)
+			response.AccountStatusAt = &value
+		}
+		if user.AccountStatusExpiresAt != nil {
+			value := formatAdminTime(*user.AccountStatusExpiresAt)
+			response.AccountStatusExpiresAt = &value
+		}
+	}
 	return response
 }
 
@@ -50,7 +50,7 @@ func TestListAdminUsersHandlerReturnsUserQueue(t *testing.T) {
 			}, nil
 		},
 	}
-	handler := ListAdminUsersHandler(svc, marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}})
+	handler := ListAdminUsersHandler(svc, marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}, nil)
 	req := httptest.NewRequest(http.MethodGet, "/v0/admin/users?limit=50&offset=10&usertype=MODERATOR&query=mod", nil)
 	rec := httptest.NewRecorder()
 
@@ -71,6 +71,36 @@ func TestListAdminUsersHandlerReturnsUserQueue(t *testing.T) {
 	}
 }
 
+func TestListAdminUsersHandlerResolvesAccountStatusWithInjectedClock(t *testing.T) {
+	expires := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	svc := adminUserManagerMock{
+		listFn: func(context.Context, dusers.ListFilters) ([]*dusers.User, error) {
+			return []*dusers.User{{Username: "troll", UserType: string(dusers.UserTypeRegular), AccountStatus: dusers.AccountStatusSuspended, AccountStatusExpiresAt: &expires}}, nil
+		},
+	}
+	auth := marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}
+
+	for _, tt := range []struct {
+		now  time.Time
+		want dusers.AccountStatus
+	}{
+		{now: expires.Add(-time.Minute), want: dusers.AccountStatusSuspended},
+		{now: expires, want: dusers.AccountStatusActive},
+	} {
+		handler := ListAdminUsersHandler(svc, auth, func() time.Time { return tt.now })
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/admin/users", nil))
+
+		var envelope handlers.SuccessEnvelope[adminUsersResponse]
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if len(envelope.Result.Users) != 1 || envelope.Result.Users[0].AccountStatus != string(tt.want) {
+			t.Fatalf("at %s account status = %+v, want %q", tt.now, envelope.Result.Users, tt.want)
+		}
+	}
+}
+
 func TestUpdateAdminUserRoleHandlerPromotesModerator(t *testing.T) {
 	svc := adminUserManagerMock{
 		promoteFn: func(_ context.Context, username, actorUsername, reason string) (*dusers.User, error) {
@@ -80,7 +110,7 @@ func TestUpdateAdminUserRoleHandlerPromotesModerator(t *testing.T) {
 			return &dusers.User{ID: 3, Username: username, UserType: string(dusers.UserTypeModerator), ModeratorStatus: dusers.ModeratorStatusActive}, nil
 		},
 	}
-	handler := UpdateAdminUserRoleHandler(svc, marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}})
+	handler := UpdateAdminUserRoleHandler(svc, marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}, nil)
 	req := mux.SetURLVars(
 		httptest.NewRequest(http.MethodPatch, "/v0/admin/users/candidate/role", bytes.NewBufferString(`{"usertype":"MODERATOR","reason":"trusted"}`)),
 		map[string]string{"username": "candidate"},
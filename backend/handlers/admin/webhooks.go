@@ -0,0 +1,442 @@
+package adminhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	"socialpredict/internal/domain/permissions"
+	dwebhooks "socialpredict/internal/domain/webhooks"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+const defaultAdminWebhookDeliveriesLimit = 50
+const maxAdminWebhookDeliveriesLimit = 200
+
+type webhookManager interface {
+	ListEndpoints(ctx context.Context, actor permissions.Subject) ([]*dwebhooks.Endpoint, error)
+	GetEndpoint(ctx context.Context, actor permissions.Subject, id int64) (*dwebhooks.Endpoint, error)
+	CreateEndpoint(ctx context.Context, actor permissions.Subject, input dwebhooks.EndpointInput) (*dwebhooks.Endpoint, error)
+	UpdateEndpoint(ctx context.Context, actor permissions.Subject, id int64, input dwebhooks.EndpointInput) (*dwebhooks.Endpoint, error)
+	DeleteEndpoint(ctx context.Context, actor permissions.Subject, id int64) error
+	ListDeliveries(ctx context.Context, actor permissions.Subject, endpointID int64, filters dwebhooks.DeliveryFilters) ([]*dwebhooks.Delivery, error)
+	SendTestEvent(ctx context.Context, actor permissions.Subject, endpointID int64) (*dwebhooks.Delivery, error)
+	RetryDelivery(ctx context.Context, actor permissions.Subject, endpointID, deliveryID int64) (*dwebhooks.Delivery, error)
+}
+
+type webhookRequest struct {
+	URL          *string  `json:"url"`
+	Description  *string  `json:"description"`
+	EventTypes   []string `json:"eventTypes"`
+	Active       *bool    `json:"active"`
+	RotateSecret bool     `json:"rotateSecret"`
+}
+
+type webhookResponse struct {
+	ID          int64    `json:"id"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"eventTypes"`
+	Active      bool     `json:"active"`
+	SecretHint  string   `json:"secretHint"`
+	Secret      string   `json:"secret,omitempty"`
+	CreatedBy   string   `json:"createdBy"`
+	UpdatedBy   string   `json:"updatedBy"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+type webhooksResponse struct {
+	Webhooks   []webhookResponse `json:"webhooks"`
+	EventTypes []string          `json:"eventTypes"`
+}
+
+type webhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	EndpointID     int64           `json:"endpointId"`
+	EventKey       string          `json:"eventKey"`
+	EventType      string          `json:"eventType"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	NextAttemptAt  *string         `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *string         `json:"lastAttemptAt,omitempty"`
+	DeliveredAt    *string         `json:"deliveredAt,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+type webhookDeliveriesResponse struct {
+	Deliveries []webhookDeliveryResponse `json:"deliveries"`
+	Total      int                       `json:"total"`
+	Limit      int                       `json:"limit"`
+	Offset     int                       `json:"offset"`
+}
+
+// ListWebhooksHandler returns every webhook endpoint and the event types they may subscribe to.
+func ListWebhooksHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+
+		endpoints, err := svc.ListEndpoints(r.Context(), admin)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		response := webhooksResponse{
+			Webhooks:   make([]webhookResponse, 0, len(endpoints)),
+			EventTypes: make([]string, 0),
+		}
+		for _, endpoint := range endpoints {
+			response.Webhooks = append(response.Webhooks, webhookResponseFromDomain(endpoint, false))
+		}
+		for _, eventType := range dwebhooks.EventTypes() {
+			response.EventTypes = append(response.EventTypes, string(eventType))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// CreateWebhookHandler registers an endpoint and returns its signing secret once.
+func CreateWebhookHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		req, ok := decodeWebhookRequest(w, r)
+		if !ok {
+			return
+		}
+
+		endpoint, err := svc.CreateEndpoint(r.Context(), admin, req.input())
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusCreated, webhookResponseFromDomain(endpoint, true))
+	}
+}
+
+// GetWebhookHandler returns one endpoint.
+func GetWebhookHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := webhookIDFromRequest(w, r, "id")
+		if !ok {
+			return
+		}
+
+		endpoint, err := svc.GetEndpoint(r.Context(), admin, id)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, webhookResponseFromDomain(endpoint, false))
+	}
+}
+
+// UpdateWebhookHandler edits an endpoint. The new secret is returned only
+// when rotateSecret is set.
+func UpdateWebhookHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := webhookIDFromRequest(w, r, "id")
+		if !ok {
+			return
+		}
+		req, ok := decodeWebhookRequest(w, r)
+		if !ok {
+			return
+		}
+
+		endpoint, err := svc.UpdateEndpoint(r.Context(), admin, id, req.input())
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, webhookResponseFromDomain(endpoint, req.RotateSecret))
+	}
+}
+
+// DeleteWebhookHandler removes an endpoint and its delivery log.
+func DeleteWebhookHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := webhookIDFromRequest(w, r, "id")
+		if !ok {
+			return
+		}
+
+		if err := svc.DeleteEndpoint(r.Context(), admin, id); err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListWebhookDeliveriesHandler returns an endpoint's delivery log, newest first.
+func ListWebhookDeliveriesHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := webhookIDFromRequest(w, r, "id")
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		limit, ok := parseAdminUserListInt(w, query.Get("limit"), defaultAdminWebhookDeliveriesLimit, 1, maxAdminWebhookDeliveriesLimit)
+		if !ok {
+			return
+		}
+		offset, ok := parseAdminUserListInt(w, query.Get("offset"), 0, 0, 1_000_000)
+		if !ok {
+			return
+		}
+		status := dwebhooks.DeliveryStatus(strings.TrimSpace(query.Get("status")))
+		switch status {
+		case "", dwebhooks.DeliveryPending, dwebhooks.DeliverySucceeded, dwebhooks.DeliveryDead:
+		default:
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		deliveries, err := svc.ListDeliveries(r.Context(), admin, id, dwebhooks.DeliveryFilters{Status: status, Limit: limit, Offset: offset})
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		response := webhookDeliveriesResponse{
+			Deliveries: make([]webhookDeliveryResponse, 0, len(deliveries)),
+			Total:      len(deliveries),
+			Limit:      limit,
+			Offset:     offset,
+		}
+		for _, delivery := range deliveries {
+			response.Deliveries = append(response.Deliveries, webhookDeliveryResponseFromDomain(delivery))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// SendWebhookTestHandler sends a webhook.test event and returns the logged delivery.
+func SendWebhookTestHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := webhookIDFromRequest(w, r, "id")
+		if !ok {
+			return
+		}
+
+		delivery, err := svc.SendTestEvent(r.Context(), admin, id)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, webhookDeliveryResponseFromDomain(delivery))
+	}
+}
+
+// RetryWebhookDeliveryHandler requeues a dead delivery and attempts it immediately.
+func RetryWebhookDeliveryHandler(svc webhookManager, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		admin, ok := requireWebhookManager(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := webhookIDFromRequest(w, r, "id")
+		if !ok {
+			return
+		}
+		deliveryID, ok := webhookIDFromRequest(w, r, "deliveryId")
+		if !ok {
+			return
+		}
+
+		delivery, err := svc.RetryDelivery(r.Context(), admin, id, deliveryID)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, webhookDeliveryResponseFromDomain(delivery))
+	}
+}
+
+func requireWebhookManager(w http.ResponseWriter, r *http.Request, svc webhookManager, auth authsvc.Authenticator) (permissions.Subject, bool) {
+	admin, ok := requirePermission(w, r, auth, permissions.WebhooksManage)
+	if !ok {
+		return permissions.Subject{}, false
+	}
+	if svc == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return permissions.Subject{}, false
+	}
+	return admin.PermissionSubject(), true
+}
+
+func decodeWebhookRequest(w http.ResponseWriter, r *http.Request) (webhookRequest, bool) {
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return webhookRequest{}, false
+	}
+	return req, true
+}
+
+func (req webhookRequest) input() dwebhooks.EndpointInput {
+	return dwebhooks.EndpointInput{
+		URL:          req.URL,
+		Description:  req.Description,
+		EventTypes:   req.EventTypes,
+		Active:       req.Active,
+		RotateSecret: req.RotateSecret,
+	}
+}
+
+func webhookIDFromRequest(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimSpace(mux.Vars(r)[name]), 10, 64)
+	if err != nil || id <= 0 {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, permissions.ErrPermissionDenied):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	case errors.Is(err, dwebhooks.ErrEndpointNotFound), errors.Is(err, dwebhooks.ErrDeliveryNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, dwebhooks.ErrInvalidEndpoint):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	case errors.Is(err, dwebhooks.ErrDeliveryNotRetryable):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func webhookResponseFromDomain(endpoint *dwebhooks.Endpoint, revealSecret bool) webhookResponse {
+	if endpoint == nil {
+		return webhookResponse{EventTypes: []string{}}
+	}
+	response := webhookResponse{
+		ID:          endpoint.ID,
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		EventTypes:  make([]string, 0, len(endpoint.EventTypes)),
+		Active:      endpoint.Active,
+		SecretHint:  secretHint(endpoint.Secret),
+		CreatedBy:   endpoint.CreatedBy,
+		UpdatedBy:   endpoint.UpdatedBy,
+		CreatedAt:   formatAdminTime(endpoint.CreatedAt),
+		UpdatedAt:   formatAdminTime(endpoint.UpdatedAt),
+	}
+	for _, eventType := range endpoint.EventTypes {
+		response.EventTypes = append(response.EventTypes, string(eventType))
+	}
+	if revealSecret {
+		response.Secret = endpoint.Secret
+	}
+	return response
+}
+
+func webhookDeliveryResponseFromDomain(delivery *dwebhooks.Delivery) webhookDeliveryResponse {
+	if delivery == nil {
+		return webhookDeliveryResponse{}
+	}
+	response := webhookDeliveryResponse{
+		ID:             delivery.ID,
+		EndpointID:     delivery.EndpointID,
+		EventKey:       delivery.EventKey,
+		EventType:      string(delivery.EventType),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      formatAdminTime(delivery.CreatedAt),
+		Payload:        json.RawMessage(delivery.Payload),
+	}
+	if delivery.Status == dwebhooks.DeliveryPending && !delivery.NextAttemptAt.IsZero() {
+		value := formatAdminTime(delivery.NextAttemptAt)
+		response.NextAttemptAt = &value
+	}
+	if delivery.LastAttemptAt != nil {
+		value := formatAdminTime(*delivery.LastAttemptAt)
+		response.LastAttemptAt = &value
+	}
+	if delivery.DeliveredAt != nil {
+		value := formatAdminTime(*delivery.DeliveredAt)
+		response.DeliveredAt = &value
+	}
+	if len(response.Payload) == 0 {
+		response.Payload = json.RawMessage("{}")
+	}
+	return response
+}
+
+// secretHint shows just enough of a secret to tell two secrets apart.
+func secretHint(secret string) string {
+	if len(secret) <= 4 {
+		return ""
+	}
+	return "…" + secret[len(secret)-4:]
+}
@@ -0,0 +1,173 @@
+package adminhandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+	dwebhooks "socialpredict/internal/domain/webhooks"
+)
+
+type webhookManagerMock struct {
+	createFn     func(context.Context, permissions.Subject, dwebhooks.EndpointInput) (*dwebhooks.Endpoint, error)
+	deliveriesFn func(context.Context, permissions.Subject, int64, dwebhooks.DeliveryFilters) ([]*dwebhooks.Delivery, error)
+	testFn       func(context.Context, permissions.Subject, int64) (*dwebhooks.Delivery, error)
+}
+
+func (m webhookManagerMock) ListEndpoints(context.Context, permissions.Subject) ([]*dwebhooks.Endpoint, error) {
+	return []*dwebhooks.Endpoint{{ID: 1, URL: "https://hooks.example.com", Secret: "whsec_abcdef", EventTypes: []devents.Type{devents.MarketResolved}, Active: true}}, nil
+}
+
+func (m webhookManagerMock) GetEndpoint(context.Context, permissions.Subject, int64) (*dwebhooks.Endpoint, error) {
+	return nil, dwebhooks.ErrEndpointNotFound
+}
+
+func (m webhookManagerMock) CreateEndpoint(ctx context.Context, actor permissions.Subject, input dwebhooks.EndpointInput) (*dwebhooks.Endpoint, error) {
+	return m.createFn(ctx, actor, input)
+}
+
+func (m webhookManagerMock) UpdateEndpoint(context.Context, permissions.Subject, int64, dwebhooks.EndpointInput) (*dwebhooks.Endpoint, error) {
+	return nil, dwebhooks.ErrInvalidEndpoint
+}
+
+func (m webhookManagerMock) DeleteEndpoint(context.Context, permissions.Subject, int64) error {
+	return nil
+}
+
+func (m webhookManagerMock) ListDeliveries(ctx context.Context, actor permissions.Subject, endpointID int64, filters dwebhooks.DeliveryFilters) ([]*dwebhooks.Delivery, error) {
+	return m.deliveriesFn(ctx, actor, endpointID, filters)
+}
+
+func (m webhookManagerMock) SendTestEvent(ctx context.Context, actor permissions.Subject, endpointID int64) (*dwebhooks.Delivery, error) {
+	return m.testFn(ctx, actor, endpointID)
+}
+
+func (m webhookManagerMock) RetryDelivery(context.Context, permissions.Subject, int64, int64) (*dwebhooks.Delivery, error) {
+	return nil, dwebhooks.ErrDeliveryNotRetryable
+}
+
+func webhookAdminAuth() marketReviewAuthMock {
+	return marketReviewAuthMock{admin: &dusers.User{Username: "admin", UserType: string(dusers.UserTypeAdmin)}}
+}
+
+func TestCreateWebhookHandlerReturnsSecretOnce(t *testing.T) {
+	createdAt := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+	svc := webhookManagerMock{createFn: func(_ context.Context, actor permissions.Subject, input dwebhooks.EndpointInput) (*dwebhooks.Endpoint, error) {
+		if actor.Username != "admin" || input.URL == nil || *input.URL != "https://hooks.example.com" || len(input.EventTypes) != 1 {
+			t.Fatalf("unexpected create args: actor=%+v input=%+v", actor, input)
+		}
+		return &dwebhooks.Endpoint{ID: 9, URL: *input.URL, Secret: "whsec_0123456789", EventTypes: []devents.Type{devents.MarketResolved}, Active: true, CreatedBy: actor.Username, CreatedAt: createdAt, UpdatedAt: createdAt}, nil
+	}}
+	body := `{"url":"https://hooks.example.com","eventTypes":["market.resolved"]}`
+	rec := httptest.NewRecorder()
+
+	CreateWebhookHandler(svc, webhookAdminAuth()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v0/admin/webhooks", bytes.NewBufferString(body)))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope handlers.SuccessEnvelope[webhookResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Result.Secret != "whsec_0123456789" || envelope.Result.SecretHint != "…6789" || envelope.Result.EventTypes[0] != "market.resolved" {
+		t.Fatalf("unexpected response: %+v", envelope.Result)
+	}
+
+	listRec := httptest.NewRecorder()
+	ListWebhooksHandler(svc, webhookAdminAuth()).ServeHTTP(listRec, httptest.NewRequest(http.MethodGet, "/v0/admin/webhooks", nil))
+	var list handlers.SuccessEnvelope[webhooksResponse]
+	if err := json.Unmarshal(listRec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Result.Webhooks) != 1 || list.Result.Webhooks[0].Secret != "" || len(list.Result.EventTypes) != len(dwebhooks.EventTypes()) {
+		t.Fatalf("list leaked secret or missed event types: %+v", list.Result)
+	}
+}
+
+func TestWebhookHandlersRequireWebhooksManage(t *testing.T) {
+	auth := permissionAuthMock{
+		marketReviewAuthMock: marketReviewAuthMock{admin: &dusers.User{Username: "editor", UserType: string(dusers.UserTypeRegular)}},
+		granted:              map[permissions.Permission]bool{permissions.CMSEdit: true},
+	}
+	rec := httptest.NewRecorder()
+
+	ListWebhooksHandler(webhookManagerMock{}, auth).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/admin/webhooks", nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403 body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWebhookDeliveryHandlers(t *testing.T) {
+	attemptedAt := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+	delivery := &dwebhooks.Delivery{
+		ID:             4,
+		EndpointID:     9,
+		EventKey:       "test_ab12",
+		EventType:      dwebhooks.TestEventType,
+		Payload:        `{"id":"test_ab12","type":"webhook.test"}`,
+		Status:         dwebhooks.DeliveryDead,
+		Attempts:       1,
+		ResponseStatus: 500,
+		LastError:      "receiver responded with HTTP 500",
+		LastAttemptAt:  &attemptedAt,
+		CreatedAt:      attemptedAt,
+	}
+	svc := webhookManagerMock{
+		deliveriesFn: func(_ context.Context, _ permissions.Subject, endpointID int64, filters dwebhooks.DeliveryFilters) ([]*dwebhooks.Delivery, error) {
+			if endpointID != 9 || filters.Status != dwebhooks.DeliveryDead || filters.Limit != 10 {
+				t.Fatalf("unexpected list args: %d %+v", endpointID, filters)
+			}
+			return []*dwebhooks.Delivery{delivery}, nil
+		},
+		testFn: func(context.Context, permissions.Subject, int64) (*dwebhooks.Delivery, error) {
+			return delivery, nil
+		},
+	}
+
+	listReq := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/admin/webhooks/9/deliveries?status=dead&limit=10", nil), map[string]string{"id": "9"})
+	listRec := httptest.NewRecorder()
+	ListWebhookDeliveriesHandler(svc, webhookAdminAuth()).ServeHTTP(listRec, listReq)
+	if listRec.Code != http.StatusOK {
+		t.Fatalf("list status = %d, body=%s", listRec.Code, listRec.Body.String())
+	}
+	var list handlers.SuccessEnvelope[webhookDeliveriesResponse]
+	if err := json.Unmarshal(listRec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if list.Result.Total != 1 || list.Result.Deliveries[0].ResponseStatus != 500 || string(list.Result.Deliveries[0].Payload) != delivery.Payload {
+		t.Fatalf("unexpected deliveries: %+v", list.Result)
+	}
+
+	badStatus := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/admin/webhooks/9/deliveries?status=failed", nil), map[string]string{"id": "9"})
+	badRec := httptest.NewRecorder()
+	ListWebhookDeliveriesHandler(svc, webhookAdminAuth()).ServeHTTP(badRec, badStatus)
+	if badRec.Code != http.StatusBadRequest {
+		t.Fatalf("bad status filter = %d, want 400", badRec.Code)
+	}
+
+	testReq := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/v0/admin/webhooks/9/test", nil), map[string]string{"id": "9"})
+	testRec := httptest.NewRecorder()
+	SendWebhookTestHandler(svc, webhookAdminAuth()).ServeHTTP(testRec, testReq)
+	if testRec.Code != http.StatusOK {
+		t.Fatalf("test status = %d, body=%s", testRec.Code, testRec.Body.String())
+	}
+
+	retryReq := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/v0/admin/webhooks/9/deliveries/4/retry", nil), map[string]string{"id": "9", "deliveryId": "4"})
+	retryRec := httptest.NewRecorder()
+	RetryWebhookDeliveryHandler(svc, webhookAdminAuth()).ServeHTTP(retryRec, retryReq)
+	if retryRec.Code != http.StatusConflict {
+		t.Fatalf("retry status = %d, want 409", retryRec.Code)
+	}
+}
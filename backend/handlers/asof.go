@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ParseAsOf reads the optional RFC3339 asOf query parameter shared by the
+// point-in-time market and position reads. A zero time means the caller
+// wants the live state.
+func ParseAsOf(r *http.Request) (time.Time, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get("asOf"))
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
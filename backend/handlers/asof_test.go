@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseAsOf(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    time.Time
+		wantErr bool
+	}{
+		{query: "", want: time.Time{}},
+		{query: "?asOf=%20", want: time.Time{}},
+		{query: "?asOf=2026-07-01T12:00:00Z", want: time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)},
+		{query: "?asOf=yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAsOf(httptest.NewRequest("GET", "/v0/markets/1"+tt.query, nil))
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%q: unexpected error %v", tt.query, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("%q: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
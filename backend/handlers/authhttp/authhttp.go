@@ -67,7 +67,7 @@ func StatusCode(err *authsvc.AuthError) int {
 		return http.StatusUnauthorized
 	case authsvc.ErrorKindUserNotFound:
 		return http.StatusNotFound
-	case authsvc.ErrorKindPasswordChangeRequired, authsvc.ErrorKindAdminRequired:
+	case authsvc.ErrorKindPasswordChangeRequired, authsvc.ErrorKindAdminRequired, authsvc.ErrorKindPermissionDenied, authsvc.ErrorKindAccountBanned:
 		return http.StatusForbidden
 	case authsvc.ErrorKindUserLoadFailed, authsvc.ErrorKindServiceUnavailable:
 		return http.StatusInternalServerError
@@ -88,8 +88,10 @@ func FailureReason(err *authsvc.AuthError) handlers.FailureReason {
 		return handlers.ReasonUserNotFound
 	case authsvc.ErrorKindPasswordChangeRequired:
 		return handlers.ReasonPasswordChangeRequired
-	case authsvc.ErrorKindAdminRequired:
+	case authsvc.ErrorKindAdminRequired, authsvc.ErrorKindPermissionDenied:
 		return handlers.ReasonAuthorizationDenied
+	case authsvc.ErrorKindAccountBanned:
+		return handlers.ReasonAccountBanned
 	case authsvc.ErrorKindUserLoadFailed, authsvc.ErrorKindServiceUnavailable:
 		return handlers.ReasonInternalError
 	default:
@@ -22,6 +22,7 @@ func TestAuthFailureMapping(t *testing.T) {
 		{"invalid token", &authsvc.AuthError{Kind: authsvc.ErrorKindInvalidToken}, http.StatusUnauthorized, handlers.ReasonInvalidToken},
 		{"password change required", &authsvc.AuthError{Kind: authsvc.ErrorKindPasswordChangeRequired}, http.StatusForbidden, handlers.ReasonPasswordChangeRequired},
 		{"authorization denied", &authsvc.AuthError{Kind: authsvc.ErrorKindAdminRequired}, http.StatusForbidden, handlers.ReasonAuthorizationDenied},
+		{"permission denied", &authsvc.AuthError{Kind: authsvc.ErrorKindPermissionDenied}, http.StatusForbidden, handlers.ReasonAuthorizationDenied},
 		{"user not found", &authsvc.AuthError{Kind: authsvc.ErrorKindUserNotFound}, http.StatusNotFound, handlers.ReasonUserNotFound},
 		{"internal", &authsvc.AuthError{Kind: authsvc.ErrorKindUserLoadFailed}, http.StatusInternalServerError, handlers.ReasonInternalError},
 		{"service unavailable", &authsvc.AuthError{Kind: authsvc.ErrorKindServiceUnavailable}, http.StatusInternalServerError, handlers.ReasonInternalError},
@@ -6,14 +6,17 @@ import (
 	"strconv"
 
 	"socialpredict/handlers"
+	privacyhandlers "socialpredict/handlers/privacy"
 	dmarkets "socialpredict/internal/domain/markets"
 	"socialpredict/logger"
 
 	"github.com/gorilla/mux"
 )
 
-// MarketBetsHandlerWithService creates a service-injected bets handler
-func MarketBetsHandlerWithService(svc dmarkets.ServiceInterface) http.HandlerFunc {
+// MarketBetsHandlerWithService creates a service-injected bets handler.
+// Traders who bet anonymously are shown as privacy.AnonymousUsername to
+// everyone but themselves and admins.
+func MarketBetsHandlerWithService(svc dmarkets.ServiceInterface, guard *privacyhandlers.Guard) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -32,6 +35,12 @@ func MarketBetsHandlerWithService(svc dmarkets.ServiceInterface) http.HandlerFun
 			return
 		}
 
+		betsDisplayInfo, err = maskBets(r, guard, betsDisplayInfo)
+		if err != nil {
+			privacyhandlers.WriteError(w, "MarketBets", err)
+			return
+		}
+
 		if err := handlers.WriteResult(w, http.StatusOK, betsDisplayInfo); err != nil {
 			logger.LogError("MarketBets", "WriteResponse", err)
 		}
@@ -45,6 +54,31 @@ func getMarketBets(r *http.Request, svc dmarkets.ServiceInterface, marketID int6
 	return svc.GetMarketBetsPage(r.Context(), marketID, parsePage(r, 20))
 }
 
+// maskBets returns copies of bets with masked usernames, leaving the service's
+// values untouched.
+func maskBets(r *http.Request, guard *privacyhandlers.Guard, bets []*dmarkets.BetDisplayInfo) ([]*dmarkets.BetDisplayInfo, error) {
+	usernames := make([]string, 0, len(bets))
+	for _, bet := range bets {
+		if bet != nil {
+			usernames = append(usernames, bet.Username)
+		}
+	}
+	mask, err := guard.Mask(r, usernames)
+	if err != nil || len(mask) == 0 {
+		return bets, err
+	}
+	masked := make([]*dmarkets.BetDisplayInfo, 0, len(bets))
+	for _, bet := range bets {
+		if bet == nil {
+			continue
+		}
+		copied := *bet
+		copied.Username = mask.Name(bet.Username)
+		masked = append(masked, &copied)
+	}
+	return masked, nil
+}
+
 func parseMarketID(marketIDStr string) (int64, error) {
 	if marketIDStr == "" {
 		return 0, errors.New("Market ID is required")
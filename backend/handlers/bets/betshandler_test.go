@@ -177,7 +177,7 @@ func TestMarketBetsHandlerWithService(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := MarketBetsHandlerWithService(tt.stub)
+			handler := MarketBetsHandlerWithService(tt.stub, nil)
 			req := httptest.NewRequest(tt.method, "/v0/markets/bets/1", nil)
 			if tt.vars != nil {
 				req = mux.SetURLVars(req, tt.vars)
@@ -229,7 +229,7 @@ func TestMarketBetsHandlerWithService_UsesPaginationQuery(t *testing.T) {
 			}
 			return []*dmarkets.BetDisplayInfo{}, nil
 		},
-	})
+	}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/markets/bets/7?limit=20&offset=40", nil)
 	req = mux.SetURLVars(req, map[string]string{"marketId": "7"})
@@ -1,7 +1,6 @@
 package buybetshandlers
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -13,21 +12,10 @@ import (
 	dmarkets "socialpredict/internal/domain/markets"
 	dusers "socialpredict/internal/domain/users"
 	authsvc "socialpredict/internal/service/auth"
-	"socialpredict/logger"
 )
 
-type readModelInvalidator interface {
-	InvalidateAfterMarketTransaction(ctx context.Context, username string, marketID int64, reason string) error
-}
-
 // PlaceBetHandler returns an HTTP handler that delegates bet placement to the bets domain service.
 func PlaceBetHandler(betsSvc dbets.ServiceInterface, usersSvc dusers.ServiceInterface) http.HandlerFunc {
-	return PlaceBetHandlerWithInvalidator(betsSvc, usersSvc, nil)
-}
-
-// PlaceBetHandlerWithInvalidator returns an HTTP handler that delegates bet
-// placement and then marks display read models stale after a successful write.
-func PlaceBetHandlerWithInvalidator(betsSvc dbets.ServiceInterface, usersSvc dusers.ServiceInterface, invalidator readModelInvalidator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -53,11 +41,6 @@ func PlaceBetHandlerWithInvalidator(betsSvc dbets.ServiceInterface, usersSvc dus
 		}
 
 		writePlaceBetResponse(w, placedBet)
-		if invalidator != nil {
-			if err := invalidator.InvalidateAfterMarketTransaction(r.Context(), placedBet.Username, int64(placedBet.MarketID), "bet_accepted"); err != nil {
-				logger.LogError("PlaceBet", "InvalidateReadModels", err)
-			}
-		}
 	}
 }
 
@@ -86,6 +69,12 @@ func writePlaceBetError(w http.ResponseWriter, err error) {
 		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonMarketClosed)
 	case dbets.ErrInsufficientBalance:
 		_ = handlers.WriteFailure(w, http.StatusUnprocessableEntity, handlers.ReasonInsufficientBalance)
+	case dbets.ErrTradeRestricted:
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	case dusers.ErrAccountSuspended:
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountSuspended)
+	case dusers.ErrAccountBanned:
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountBanned)
 	case dmarkets.ErrMarketNotFound:
 		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonMarketNotFound)
 	default:
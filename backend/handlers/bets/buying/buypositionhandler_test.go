@@ -11,9 +11,15 @@ import (
 
 	"socialpredict/handlers"
 	"socialpredict/handlers/bets/dto"
+	"socialpredict/internal/app"
+	"socialpredict/internal/app/readmodelinvalidation"
+	"socialpredict/internal/domain/analytics"
 	bets "socialpredict/internal/domain/bets"
+	devents "socialpredict/internal/domain/events"
 	dmarkets "socialpredict/internal/domain/markets"
 	dusers "socialpredict/internal/domain/users"
+	readmodelrepo "socialpredict/internal/repository/readmodels"
+	configsvc "socialpredict/internal/service/config"
 	"socialpredict/models/modelstesting"
 )
 
@@ -23,22 +29,6 @@ type fakeBetsService struct {
 	err  error
 }
 
-type fakeReadModelInvalidator struct {
-	username string
-	marketID int64
-	reason   string
-	calls    int
-	err      error
-}
-
-func (f *fakeReadModelInvalidator) InvalidateAfterMarketTransaction(ctx context.Context, username string, marketID int64, reason string) error {
-	f.username = username
-	f.marketID = marketID
-	f.reason = reason
-	f.calls++
-	return f.err
-}
-
 func (f *fakeBetsService) Place(ctx context.Context, req bets.PlaceRequest) (*bets.PlacedBet, error) {
 	f.req = req
 	if f.err != nil {
@@ -172,33 +162,56 @@ func TestPlaceBetHandler_Success(t *testing.T) {
 	}
 }
 
-func TestPlaceBetHandler_InvalidatesReadModelsAfterSuccess(t *testing.T) {
+func TestPlaceBetHandler_InvalidatesReadModelsThroughEventBus(t *testing.T) {
 	t.Setenv("JWT_SIGNING_KEY", "test-secret-key-for-testing")
+	db := modelstesting.NewFakeDB(t)
+	econConfig, _ := modelstesting.UseStandardTestEconomics(t)
+	ctx := context.Background()
 
-	userSvc := &fakeUsersService{user: &dusers.User{Username: "alice"}}
-	betsSvc := &fakeBetsService{resp: &bets.PlacedBet{
-		Username: "alice",
-		MarketID: 99,
-		Amount:   10,
-		Outcome:  "YES",
-		PlacedAt: time.Now(),
-	}}
-	invalidator := &fakeReadModelInvalidator{}
-	handler := PlaceBetHandlerWithInvalidator(betsSvc, userSvc, invalidator)
-
-	body, _ := json.Marshal(dto.PlaceBetRequest{MarketID: 99, Amount: 10, Outcome: "YES"})
+	user := modelstesting.GenerateUser("alice", 1000)
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Model(&user).Update("must_change_password", false).Error; err != nil {
+		t.Fatalf("clear password-change flag: %v", err)
+	}
+	market := modelstesting.GenerateMarket(8101, "creator")
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+
+	container := app.BuildApplicationWithConfigService(db, configsvc.NewStaticService(econConfig))
+	snapshots := readmodelrepo.NewGormRepository(db)
+	keys := []string{"market_discovery:markets:status=active:tag=none:limit=21:offset=0", analytics.SystemMetricsSnapshotKey}
+	for _, key := range keys {
+		if err := snapshots.Upsert(ctx, readmodelrepo.Snapshot{Key: key, Kind: "test", PayloadJSON: "{}", GeneratedAt: time.Now().UTC(), Source: "read_model"}); err != nil {
+			t.Fatalf("seed snapshot %s: %v", key, err)
+		}
+	}
+	dispatcher := container.GetEventDispatcher()
+	dispatcher.Subscribe("read_models", readmodelinvalidation.New(container.GetMarketsService(), container.GetAnalyticsService(), snapshots))
+
+	body, _ := json.Marshal(dto.PlaceBetRequest{MarketID: uint(market.ID), Amount: 10, Outcome: "YES"})
 	req := httptest.NewRequest(http.MethodPost, "/v0/bet", bytes.NewReader(body))
 	req.Header.Set("Authorization", "Bearer "+modelstesting.GenerateValidJWT("alice"))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
-
-	handler.ServeHTTP(rr, req)
-
+	PlaceBetHandler(container.GetBetsService(), container.GetUsersService()).ServeHTTP(rr, req)
 	if rr.Code != http.StatusCreated {
 		t.Fatalf("expected status 201, got %d body=%s", rr.Code, rr.Body.String())
 	}
-	if invalidator.calls != 1 || invalidator.username != "alice" || invalidator.marketID != 99 || invalidator.reason != "bet_accepted" {
-		t.Fatalf("unexpected invalidation call: %+v", invalidator)
+
+	if claimed, err := dispatcher.DispatchDue(ctx); err != nil || claimed != 1 {
+		t.Fatalf("DispatchDue claimed=%d err=%v, want the bet event", claimed, err)
+	}
+	for _, key := range keys {
+		snapshot, err := snapshots.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("load snapshot %s: %v", key, err)
+		}
+		if snapshot == nil || !snapshot.IsStale || snapshot.StaleReason != string(devents.BetPlaced) {
+			t.Fatalf("snapshot %s = %+v, want stale after the buy", key, snapshot)
+		}
 	}
 }
 
@@ -1,7 +1,6 @@
 package sellbetshandlers
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -13,21 +12,10 @@ import (
 	dmarkets "socialpredict/internal/domain/markets"
 	dusers "socialpredict/internal/domain/users"
 	authsvc "socialpredict/internal/service/auth"
-	"socialpredict/logger"
 )
 
-type readModelInvalidator interface {
-	InvalidateAfterMarketTransaction(ctx context.Context, username string, marketID int64, reason string) error
-}
-
 // SellPositionHandler returns an HTTP handler that delegates sales to the bets service.
 func SellPositionHandler(betsSvc bets.ServiceInterface, usersSvc dusers.ServiceInterface) http.HandlerFunc {
-	return SellPositionHandlerWithInvalidator(betsSvc, usersSvc, nil)
-}
-
-// SellPositionHandlerWithInvalidator returns an HTTP handler that delegates
-// sales and then marks display read models stale after a successful write.
-func SellPositionHandlerWithInvalidator(betsSvc bets.ServiceInterface, usersSvc dusers.ServiceInterface, invalidator readModelInvalidator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -53,11 +41,6 @@ func SellPositionHandlerWithInvalidator(betsSvc bets.ServiceInterface, usersSvc
 		}
 
 		writeSellResponse(w, result)
-		if invalidator != nil {
-			if err := invalidator.InvalidateAfterMarketTransaction(r.Context(), result.Username, int64(result.MarketID), "sale_accepted"); err != nil {
-				logger.LogError("SellPosition", "InvalidateReadModels", err)
-			}
-		}
 	}
 }
 
@@ -174,6 +157,10 @@ func handleSellError(w http.ResponseWriter, err error) {
 		)
 	case errors.Is(err, dmarkets.ErrMarketNotFound):
 		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonMarketNotFound)
+	case errors.Is(err, dusers.ErrAccountSuspended):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountSuspended)
+	case errors.Is(err, dusers.ErrAccountBanned):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountBanned)
 	default:
 		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
 	}
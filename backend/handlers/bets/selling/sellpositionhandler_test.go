@@ -11,9 +11,15 @@ import (
 
 	"socialpredict/handlers"
 	"socialpredict/handlers/bets/dto"
+	"socialpredict/internal/app"
+	"socialpredict/internal/app/readmodelinvalidation"
+	"socialpredict/internal/domain/analytics"
 	bets "socialpredict/internal/domain/bets"
+	devents "socialpredict/internal/domain/events"
 	dmarkets "socialpredict/internal/domain/markets"
 	dusers "socialpredict/internal/domain/users"
+	readmodelrepo "socialpredict/internal/repository/readmodels"
+	configsvc "socialpredict/internal/service/config"
 	"socialpredict/models/modelstesting"
 )
 
@@ -26,22 +32,6 @@ type fakeSellService struct {
 	quoteErr  error
 }
 
-type fakeReadModelInvalidator struct {
-	username string
-	marketID int64
-	reason   string
-	calls    int
-	err      error
-}
-
-func (f *fakeReadModelInvalidator) InvalidateAfterMarketTransaction(ctx context.Context, username string, marketID int64, reason string) error {
-	f.username = username
-	f.marketID = marketID
-	f.reason = reason
-	f.calls++
-	return f.err
-}
-
 func (f *fakeSellService) Place(ctx context.Context, req bets.PlaceRequest) (*bets.PlacedBet, error) {
 	return nil, nil
 }
@@ -177,36 +167,73 @@ func TestSellPositionHandler_Success(t *testing.T) {
 	}
 }
 
-func TestSellPositionHandler_InvalidatesReadModelsAfterSuccess(t *testing.T) {
+func TestSellPositionHandler_InvalidatesReadModelsThroughEventBus(t *testing.T) {
 	t.Setenv("JWT_SIGNING_KEY", "test-secret-key-for-testing")
+	db := modelstesting.NewFakeDB(t)
+	econConfig, _ := modelstesting.UseStandardTestEconomics(t)
+	ctx := context.Background()
 
-	users := &fakeUsersService{user: &dusers.User{Username: "alice"}}
-	svc := &fakeSellService{resp: &bets.SellResult{
-		Username:      "alice",
-		MarketID:      99,
-		Outcome:       "YES",
-		SharesSold:    2,
-		SaleValue:     10,
-		Dust:          1,
-		NetProceeds:   9,
-		TransactionAt: time.Now(),
-	}}
-	invalidator := &fakeReadModelInvalidator{}
-	handler := SellPositionHandlerWithInvalidator(svc, users, invalidator)
+	user := modelstesting.GenerateUser("alice", 1000)
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Model(&user).Update("must_change_password", false).Error; err != nil {
+		t.Fatalf("clear password-change flag: %v", err)
+	}
+	bob := modelstesting.GenerateUser("bob", 1000)
+	if err := db.Create(&bob).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	market := modelstesting.GenerateMarket(8102, "creator")
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+
+	// Alice's shares only become sellable once another trader follows her.
+	container := app.BuildApplicationWithConfigService(db, configsvc.NewStaticService(econConfig))
+	for _, buy := range []bets.PlaceRequest{
+		{Username: "alice", MarketID: uint(market.ID), Amount: 20, Outcome: "YES"},
+		{Username: "bob", MarketID: uint(market.ID), Amount: 10, Outcome: "YES"},
+	} {
+		if _, err := container.GetBetsService().Place(ctx, buy); err != nil {
+			t.Fatalf("place bet for %s: %v", buy.Username, err)
+		}
+	}
+	dispatcher := container.GetEventDispatcher()
+	if _, err := dispatcher.DispatchDue(ctx); err != nil {
+		t.Fatalf("settle buy events: %v", err)
+	}
+
+	snapshots := readmodelrepo.NewGormRepository(db)
+	keys := []string{"market_discovery:markets:status=active:tag=none:limit=21:offset=0", analytics.SystemMetricsSnapshotKey}
+	for _, key := range keys {
+		if err := snapshots.Upsert(ctx, readmodelrepo.Snapshot{Key: key, Kind: "test", PayloadJSON: "{}", GeneratedAt: time.Now().UTC(), Source: "read_model"}); err != nil {
+			t.Fatalf("seed snapshot %s: %v", key, err)
+		}
+	}
+	dispatcher.Subscribe("read_models", readmodelinvalidation.New(container.GetMarketsService(), container.GetAnalyticsService(), snapshots))
 
-	body, _ := json.Marshal(dto.SellBetRequest{MarketID: 99, Amount: 11, Outcome: "YES"})
+	body, _ := json.Marshal(dto.SellBetRequest{MarketID: uint(market.ID), Amount: 5, Outcome: "YES"})
 	req := httptest.NewRequest(http.MethodPost, "/v0/sell", bytes.NewReader(body))
 	req.Header.Set("Authorization", "Bearer "+modelstesting.GenerateValidJWT("alice"))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
-
-	handler.ServeHTTP(rr, req)
-
+	SellPositionHandler(container.GetBetsService(), container.GetUsersService()).ServeHTTP(rr, req)
 	if rr.Code != http.StatusCreated {
 		t.Fatalf("expected status 201, got %d body=%s", rr.Code, rr.Body.String())
 	}
-	if invalidator.calls != 1 || invalidator.username != "alice" || invalidator.marketID != 99 || invalidator.reason != "sale_accepted" {
-		t.Fatalf("unexpected invalidation call: %+v", invalidator)
+
+	if claimed, err := dispatcher.DispatchDue(ctx); err != nil || claimed != 1 {
+		t.Fatalf("DispatchDue claimed=%d err=%v, want the sale event", claimed, err)
+	}
+	for _, key := range keys {
+		snapshot, err := snapshots.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("load snapshot %s: %v", key, err)
+		}
+		if snapshot == nil || !snapshot.IsStale || snapshot.StaleReason != string(devents.SharesSold) {
+			t.Fatalf("snapshot %s = %+v, want stale after the sale", key, snapshot)
+		}
 	}
 }
 
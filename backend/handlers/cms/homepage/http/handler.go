@@ -7,6 +7,7 @@ import (
 	"socialpredict/handlers"
 	"socialpredict/handlers/authhttp"
 	"socialpredict/handlers/cms/homepage"
+	"socialpredict/internal/domain/permissions"
 	authsvc "socialpredict/internal/service/auth"
 )
 
@@ -50,7 +51,7 @@ func (h *Handler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
 		return
 	}
-	admin, authErr := h.auth.RequireAdmin(r)
+	admin, authErr := authsvc.RequirePermission(h.auth, r, permissions.CMSEdit)
 	if authErr != nil {
 		_ = authhttp.WriteFailure(w, authErr)
 		return
@@ -85,14 +86,15 @@ func (h *Handler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// RequireAdmin middleware wrapper that can be used in routes when an authenticator is available.
-func RequireAdmin(auth authsvc.Authenticator, next http.HandlerFunc) http.HandlerFunc {
+// RequireCMSEdit middleware wrapper that can be used in routes when an
+// authenticator is available. It admits any role granted cms.edit.
+func RequireCMSEdit(auth authsvc.Authenticator, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if auth == nil {
 			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
 			return
 		}
-		if _, authErr := auth.RequireAdmin(r); authErr != nil {
+		if _, authErr := authsvc.RequirePermission(auth, r, permissions.CMSEdit); authErr != nil {
 			_ = authhttp.WriteFailure(w, authErr)
 			return
 		}
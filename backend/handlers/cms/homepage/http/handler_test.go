@@ -13,7 +13,9 @@ import (
 	"socialpredict/models"
 	"socialpredict/models/modelstesting"
 
+	dpermissions "socialpredict/internal/domain/permissions"
 	dusers "socialpredict/internal/domain/users"
+	rpermissions "socialpredict/internal/repository/permissions"
 	rusers "socialpredict/internal/repository/users"
 	"socialpredict/security"
 )
@@ -250,3 +252,93 @@ func TestAdminUpdate_NonAdminReturnsAuthorizationDenied(t *testing.T) {
 		t.Fatalf("expected reason %q, got %q", handlers.ReasonAuthorizationDenied, resp.Reason)
 	}
 }
+
+func TestAdminUpdate_CMSEditorRoleAllowedWithoutAdmin(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	t.Setenv("JWT_SIGNING_KEY", "test-secret-key-for-testing")
+
+	editor := modelstesting.GenerateUser("content_editor", 0)
+	editor.MustChangePassword = false
+	if err := db.Create(&editor).Error; err != nil {
+		t.Fatalf("create editor: %v", err)
+	}
+	if err := db.Model(&editor).Update("must_change_password", false).Error; err != nil {
+		t.Fatalf("clear must_change_password: %v", err)
+	}
+	if err := db.Create(&models.UserRoleAssignment{Username: editor.Username, Role: "CMS_EDITOR", GrantedBy: "admin"}).Error; err != nil {
+		t.Fatalf("assign CMS_EDITOR: %v", err)
+	}
+
+	item := models.HomepageContent{Slug: "home", Title: "Old title", Format: "html", HTML: "<p>Old</p>", Version: 1}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("seed homepage content: %v", err)
+	}
+
+	svc := homepage.NewService(homepage.NewGormRepository(db), homepage.NewDefaultRenderer())
+	usersSvc := dusers.NewService(rusers.NewGormRepository(db), nil, security.NewSecurityService().Sanitizer)
+	auth := authsvc.NewAuthService(usersSvc)
+	auth.SetAuthorizer(dpermissions.NewService(rpermissions.NewGormRepository(db)))
+	handler := NewHandler(svc, auth)
+
+	req := httptest.NewRequest("PUT", "/v0/admin/content/home", bytes.NewReader([]byte(`{"title":"Edited","format":"html","html":"<p>Edited</p>","version":1}`)))
+	req.Header.Set("Authorization", "Bearer "+modelstesting.GenerateValidJWT(editor.Username))
+	rec := httptest.NewRecorder()
+
+	handler.AdminUpdate(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var stored models.HomepageContent
+	if err := db.Where("slug = ?", "home").First(&stored).Error; err != nil {
+		t.Fatalf("fetch stored content: %v", err)
+	}
+	if stored.Title != "Edited" || stored.UpdatedBy != editor.Username {
+		t.Fatalf("unexpected stored content: title=%q updatedBy=%q", stored.Title, stored.UpdatedBy)
+	}
+}
+
+func TestRequireCMSEdit_AdmitsCMSEditorAndDeniesMembers(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	t.Setenv("JWT_SIGNING_KEY", "test-secret-key-for-testing")
+
+	for _, username := range []string{"content_editor", "member_user"} {
+		user := modelstesting.GenerateUser(username, 0)
+		user.UserType = "REGULAR"
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("create %s: %v", username, err)
+		}
+		if err := db.Model(&user).Update("must_change_password", false).Error; err != nil {
+			t.Fatalf("clear must_change_password: %v", err)
+		}
+	}
+	if err := db.Create(&models.UserRoleAssignment{Username: "content_editor", Role: "CMS_EDITOR", GrantedBy: "admin"}).Error; err != nil {
+		t.Fatalf("assign CMS_EDITOR: %v", err)
+	}
+
+	usersSvc := dusers.NewService(rusers.NewGormRepository(db), nil, security.NewSecurityService().Sanitizer)
+	auth := authsvc.NewAuthService(usersSvc)
+	auth.SetAuthorizer(dpermissions.NewService(rpermissions.NewGormRepository(db)))
+	wrapped := RequireCMSEdit(auth, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		username string
+		want     int
+	}{
+		{username: "content_editor", want: http.StatusNoContent},
+		{username: "member_user", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("PUT", "/v0/admin/content/home", nil)
+		req.Header.Set("Authorization", "Bearer "+modelstesting.GenerateValidJWT(tt.username))
+		rec := httptest.NewRecorder()
+
+		wrapped(rec, req)
+
+		if rec.Code != tt.want {
+			t.Fatalf("%s: expected status %d, got %d: %s", tt.username, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -10,27 +10,28 @@ import (
 	"socialpredict/handlers"
 	"socialpredict/handlers/authhttp"
 	"socialpredict/handlers/cms/marketdiscovery"
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/internal/domain/permissions"
 	dusers "socialpredict/internal/domain/users"
 	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/logger"
 	"socialpredict/models"
 )
 
 type Handler struct {
-	svc         *marketdiscovery.Service
-	auth        authsvc.Authenticator
-	invalidator discoveryInvalidator
+	svc    *marketdiscovery.Service
+	auth   authsvc.Authenticator
+	events devents.Recorder
 }
 
 func NewHandler(svc *marketdiscovery.Service, auth authsvc.Authenticator) *Handler {
 	return &Handler{svc: svc, auth: auth}
 }
 
-type discoveryInvalidator interface {
-	MarkMarketDiscoverySnapshotsStale(ctx context.Context, reason string) error
-}
-
-func (h *Handler) SetReadModelInvalidator(invalidator discoveryInvalidator) {
-	h.invalidator = invalidator
+// SetEventRecorder wires the outbox that announces discovery content changes
+// to read-model invalidation and other event subscribers.
+func (h *Handler) SetEventRecorder(recorder devents.Recorder) {
+	h.events = recorder
 }
 
 type updateReq struct {
@@ -98,7 +99,7 @@ func (h *Handler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
 		return
 	}
-	admin, authErr := h.auth.RequireAdmin(r)
+	admin, authErr := authsvc.RequirePermission(h.auth, r, permissions.CMSEdit)
 	if authErr != nil {
 		_ = authhttp.WriteFailure(w, authErr)
 		return
@@ -126,7 +127,7 @@ func (h *Handler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
 		return
 	}
-	h.markDiscoveryReadModelsStale(r.Context(), "cms_layout_changed")
+	h.recordDiscoveryContentChanged(r.Context(), page.Slug, "layout", admin.Username)
 	composition, err := h.svc.GetComposition(page.Slug)
 	if err != nil {
 		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
@@ -150,15 +151,21 @@ func (h *Handler) AdminReplacePins(w http.ResponseWriter, r *http.Request) {
 		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
 		return
 	}
-	h.markDiscoveryReadModelsStale(r.Context(), "cms_pins_changed")
+	h.recordDiscoveryContentChanged(r.Context(), mux.Vars(r)["slug"], "pins", admin.Username)
 	_ = handlers.WriteResult(w, http.StatusOK, responseFromComposition(composition))
 }
 
-func (h *Handler) markDiscoveryReadModelsStale(ctx context.Context, reason string) {
-	if h.invalidator == nil {
+func (h *Handler) recordDiscoveryContentChanged(ctx context.Context, slug string, change string, actorUsername string) {
+	if h.events == nil {
 		return
 	}
-	_ = h.invalidator.MarkMarketDiscoverySnapshotsStale(ctx, reason)
+	if err := h.events.RecordEvents(ctx, devents.Event{
+		Type:     devents.DiscoveryContentChanged,
+		Username: actorUsername,
+		Data:     map[string]any{"slug": slug, "change": change},
+	}); err != nil {
+		logger.LogError("MarketDiscoveryCMS", "RecordEvents", err)
+	}
 }
 
 func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) (*dusers.User, bool) {
@@ -166,7 +173,7 @@ func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) (*dusers.
 		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
 		return nil, false
 	}
-	admin, authErr := h.auth.RequireAdmin(r)
+	admin, authErr := authsvc.RequirePermission(h.auth, r, permissions.CMSEdit)
 	if authErr != nil {
 		_ = authhttp.WriteFailure(w, authErr)
 		return nil, false
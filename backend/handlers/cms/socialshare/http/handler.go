@@ -9,6 +9,7 @@ import (
 	"socialpredict/handlers"
 	"socialpredict/handlers/authhttp"
 	"socialpredict/handlers/cms/socialshare"
+	"socialpredict/internal/domain/permissions"
 	dusers "socialpredict/internal/domain/users"
 	authsvc "socialpredict/internal/service/auth"
 	"socialpredict/models"
@@ -149,7 +150,7 @@ func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) (*dusers.
 		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
 		return nil, false
 	}
-	admin, authErr := h.auth.RequireAdmin(r)
+	admin, authErr := authsvc.RequirePermission(h.auth, r, permissions.CMSEdit)
 	if authErr != nil {
 		_ = authhttp.WriteFailure(w, authErr)
 		return nil, false
@@ -0,0 +1,357 @@
+package commentshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	dcomments "socialpredict/internal/domain/comments"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/security"
+
+	"github.com/gorilla/mux"
+)
+
+const maxCommentsPageParam = 10000
+
+type discussion interface {
+	List(ctx context.Context, target dcomments.Target, limit, offset int) (*dcomments.Page, error)
+	Post(ctx context.Context, actor permissions.Subject, target dcomments.Target, parentID int64, body string) (*dcomments.Comment, error)
+	Edit(ctx context.Context, actor permissions.Subject, id int64, body string) (*dcomments.Comment, error)
+	Delete(ctx context.Context, actor permissions.Subject, id int64) (*dcomments.Comment, error)
+	Hide(ctx context.Context, actor permissions.Subject, id int64, reason string) (*dcomments.Comment, error)
+	Unhide(ctx context.Context, actor permissions.Subject, id int64) (*dcomments.Comment, error)
+}
+
+type holdingResponse struct {
+	MarketID    int64  `json:"marketId"`
+	AnswerLabel string `json:"answerLabel,omitempty"`
+	YesShares   int64  `json:"yesShares"`
+	NoShares    int64  `json:"noShares"`
+}
+
+type commentResponse struct {
+	ID            int64             `json:"id"`
+	MarketID      int64             `json:"marketId,omitempty"`
+	MarketGroupID int64             `json:"marketGroupId,omitempty"`
+	ParentID      int64             `json:"parentId,omitempty"`
+	RootID        int64             `json:"rootId,omitempty"`
+	Username      string            `json:"username"`
+	Body          string            `json:"body"`
+	BodyHTML      string            `json:"bodyHtml"`
+	Edited        bool              `json:"edited"`
+	EditedAt      *string           `json:"editedAt,omitempty"`
+	Deleted       bool              `json:"deleted"`
+	Hidden        bool              `json:"hidden"`
+	HiddenReason  string            `json:"hiddenReason,omitempty"`
+	Holdings      []holdingResponse `json:"holdings,omitempty"`
+	Replies       []commentResponse `json:"replies,omitempty"`
+	CreatedAt     string            `json:"createdAt"`
+}
+
+type commentsResponse struct {
+	Comments []commentResponse `json:"comments"`
+	Total    int64             `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+type postCommentRequest struct {
+	Body     string `json:"body"`
+	ParentID int64  `json:"parentId"`
+}
+
+type editCommentRequest struct {
+	Body string `json:"body"`
+}
+
+type hideCommentRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ListMarketCommentsHandler handles GET /v0/markets/{id}/comments.
+func ListMarketCommentsHandler(svc discussion) http.HandlerFunc {
+	return listHandler(svc, dcomments.MarketTarget)
+}
+
+// ListMarketGroupCommentsHandler handles GET /v0/market-groups/{id}/comments.
+func ListMarketGroupCommentsHandler(svc discussion) http.HandlerFunc {
+	return listHandler(svc, dcomments.GroupTarget)
+}
+
+// PostMarketCommentHandler handles POST /v0/markets/{id}/comments. Posting
+// is rate limited per user when limiter is set.
+func PostMarketCommentHandler(svc discussion, auth authsvc.Authenticator, limiter *security.RateLimiter) http.HandlerFunc {
+	return postHandler(svc, auth, limiter, dcomments.MarketTarget)
+}
+
+// PostMarketGroupCommentHandler handles POST /v0/market-groups/{id}/comments.
+func PostMarketGroupCommentHandler(svc discussion, auth authsvc.Authenticator, limiter *security.RateLimiter) http.HandlerFunc {
+	return postHandler(svc, auth, limiter, dcomments.GroupTarget)
+}
+
+// EditCommentHandler handles PATCH /v0/comments/{id}. Edits share the
+// posting rate limit.
+func EditCommentHandler(svc discussion, auth authsvc.Authenticator, limiter *security.RateLimiter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := idFromRequest(w, r)
+		if !ok {
+			return
+		}
+		var request editCommentRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		if !allow(w, limiter, user.Username) {
+			return
+		}
+		comment, err := svc.Edit(r.Context(), user.PermissionSubject(), id, request.Body)
+		if err != nil {
+			writeCommentsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, commentResponseFromDomain(comment))
+	}
+}
+
+// DeleteCommentHandler handles DELETE /v0/comments/{id}.
+func DeleteCommentHandler(svc discussion, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := idFromRequest(w, r)
+		if !ok {
+			return
+		}
+		comment, err := svc.Delete(r.Context(), user.PermissionSubject(), id)
+		if err != nil {
+			writeCommentsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, commentResponseFromDomain(comment))
+	}
+}
+
+// HideCommentHandler handles POST /v0/admin/comments/{id}/hide.
+func HideCommentHandler(svc discussion, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := idFromRequest(w, r)
+		if !ok {
+			return
+		}
+		var request hideCommentRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		comment, err := svc.Hide(r.Context(), user.PermissionSubject(), id, request.Reason)
+		if err != nil {
+			writeCommentsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, commentResponseFromDomain(comment))
+	}
+}
+
+// UnhideCommentHandler handles POST /v0/admin/comments/{id}/unhide.
+func UnhideCommentHandler(svc discussion, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := idFromRequest(w, r)
+		if !ok {
+			return
+		}
+		comment, err := svc.Unhide(r.Context(), user.PermissionSubject(), id)
+		if err != nil {
+			writeCommentsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, commentResponseFromDomain(comment))
+	}
+}
+
+func listHandler(svc discussion, target func(int64) dcomments.Target) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		id, ok := idFromRequest(w, r)
+		if !ok {
+			return
+		}
+		limit, offset, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		page, err := svc.List(r.Context(), target(id), limit, offset)
+		if err != nil {
+			writeCommentsError(w, err)
+			return
+		}
+		response := commentsResponse{
+			Comments: make([]commentResponse, 0, len(page.Comments)),
+			Total:    page.Total,
+			Limit:    page.Limit,
+			Offset:   page.Offset,
+		}
+		for _, comment := range page.Comments {
+			response.Comments = append(response.Comments, commentResponseFromDomain(comment))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+func postHandler(svc discussion, auth authsvc.Authenticator, limiter *security.RateLimiter, target func(int64) dcomments.Target) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := idFromRequest(w, r)
+		if !ok {
+			return
+		}
+		var request postCommentRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		if !allow(w, limiter, user.Username) {
+			return
+		}
+		comment, err := svc.Post(r.Context(), user.PermissionSubject(), target(id), request.ParentID, request.Body)
+		if err != nil {
+			writeCommentsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusCreated, commentResponseFromDomain(comment))
+	}
+}
+
+// allow spends one token from the user's posting bucket.
+func allow(w http.ResponseWriter, limiter *security.RateLimiter, username string) bool {
+	if limiter == nil || limiter.GetLimiter("comments:"+username).Allow() {
+		return true
+	}
+	_ = handlers.WriteFailure(w, http.StatusTooManyRequests, handlers.ReasonRateLimited)
+	return false
+}
+
+func currentUser(w http.ResponseWriter, r *http.Request, svc discussion, auth authsvc.Authenticator) (*dusers.User, bool) {
+	if svc == nil || auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return nil, false
+	}
+	user, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return nil, false
+	}
+	return user, true
+}
+
+func idFromRequest(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimSpace(mux.Vars(r)["id"]), 10, 64)
+	if err != nil || id <= 0 {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func parsePage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	query := r.URL.Query()
+	var limit, offset int
+	for name, target := range map[string]*int{"limit": &limit, "offset": &offset} {
+		raw := strings.TrimSpace(query.Get(name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 || value > maxCommentsPageParam {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return 0, 0, false
+		}
+		*target = value
+	}
+	return limit, offset, true
+}
+
+func writeCommentsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dcomments.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	case errors.Is(err, dcomments.ErrCommentNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, dmarkets.ErrMarketNotFound), errors.Is(err, dmarkets.ErrMarketGroupNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonMarketNotFound)
+	case errors.Is(err, dcomments.ErrDiscussionClosed), errors.Is(err, dcomments.ErrEditWindowClosed), errors.Is(err, dcomments.ErrCommentRemoved):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	case errors.Is(err, permissions.ErrPermissionDenied):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	case errors.Is(err, dusers.ErrAccountSuspended):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountSuspended)
+	case errors.Is(err, dusers.ErrAccountBanned):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountBanned)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func commentResponseFromDomain(comment *dcomments.Comment) commentResponse {
+	response := commentResponse{
+		ID:            comment.ID,
+		MarketID:      comment.MarketID,
+		MarketGroupID: comment.MarketGroupID,
+		ParentID:      comment.ParentID,
+		RootID:        comment.RootID,
+		Username:      comment.Username,
+		Body:          comment.Body,
+		BodyHTML:      comment.BodyHTML,
+		Edited:        comment.EditedAt != nil,
+		Deleted:       comment.Deleted(),
+		Hidden:        comment.Hidden(),
+		HiddenReason:  comment.HiddenReason,
+		CreatedAt:     comment.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if comment.EditedAt != nil {
+		editedAt := comment.EditedAt.UTC().Format(time.RFC3339)
+		response.EditedAt = &editedAt
+	}
+	for _, holding := range comment.Holdings {
+		response.Holdings = append(response.Holdings, holdingResponse{
+			MarketID:    holding.MarketID,
+			AnswerLabel: holding.AnswerLabel,
+			YesShares:   holding.YesShares,
+			NoShares:    holding.NoShares,
+		})
+	}
+	for _, reply := range comment.Replies {
+		response.Replies = append(response.Replies, commentResponseFromDomain(reply))
+	}
+	return response
+}
@@ -0,0 +1,181 @@
+package commentshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dcomments "socialpredict/internal/domain/comments"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/security"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+type discussionMock struct {
+	target   dcomments.Target
+	limit    int
+	parentID int64
+	body     string
+	err      error
+}
+
+func (m *discussionMock) List(_ context.Context, target dcomments.Target, limit, offset int) (*dcomments.Page, error) {
+	m.target = target
+	m.limit = limit
+	if m.err != nil {
+		return nil, m.err
+	}
+	created := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	return &dcomments.Page{
+		Comments: []*dcomments.Comment{{
+			ID: 1, MarketID: target.MarketID, Username: "alice", Body: "why", BodyHTML: "<p>why</p>", CreatedAt: created,
+			Holdings: []dcomments.Holding{{MarketID: target.MarketID, YesShares: 30}},
+			Replies:  []*dcomments.Comment{{ID: 2, MarketID: target.MarketID, RootID: 1, ParentID: 1, Username: "bob", HiddenAt: &created, HiddenReason: "spam", CreatedAt: created}},
+		}},
+		Total: 1, Limit: 20, Offset: offset,
+	}, nil
+}
+
+func (m *discussionMock) Post(_ context.Context, actor permissions.Subject, target dcomments.Target, parentID int64, body string) (*dcomments.Comment, error) {
+	m.target = target
+	m.parentID = parentID
+	m.body = body
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dcomments.Comment{ID: 3, MarketID: target.MarketID, MarketGroupID: target.MarketGroupID, Username: actor.Username, Body: body, BodyHTML: "<p>" + body + "</p>"}, nil
+}
+
+func (m *discussionMock) Edit(_ context.Context, actor permissions.Subject, id int64, body string) (*dcomments.Comment, error) {
+	return nil, m.err
+}
+
+func (m *discussionMock) Delete(_ context.Context, actor permissions.Subject, id int64) (*dcomments.Comment, error) {
+	return nil, m.err
+}
+
+func (m *discussionMock) Hide(_ context.Context, actor permissions.Subject, id int64, reason string) (*dcomments.Comment, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	now := time.Now()
+	return &dcomments.Comment{ID: id, Username: "bob", HiddenAt: &now, HiddenReason: reason}, nil
+}
+
+func (m *discussionMock) Unhide(context.Context, permissions.Subject, int64) (*dcomments.Comment, error) {
+	return nil, m.err
+}
+
+func signedIn() authMock {
+	return authMock{user: &dusers.User{Username: "alice"}}
+}
+
+func serve(handler http.Handler, method, pattern, target, body string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.Handle(pattern, handler).Methods(method)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func TestListMarketCommentsHandlerReturnsThreads(t *testing.T) {
+	svc := &discussionMock{}
+	rec := serve(ListMarketCommentsHandler(svc), http.MethodGet, "/v0/markets/{id}/comments", "/v0/markets/7/comments?limit=5", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if svc.target != dcomments.MarketTarget(7) || svc.limit != 5 {
+		t.Fatalf("unexpected target %+v limit %d", svc.target, svc.limit)
+	}
+	var body struct {
+		Result commentsResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	root := body.Result.Comments[0]
+	if root.BodyHTML != "<p>why</p>" || len(root.Holdings) != 1 || root.Holdings[0].YesShares != 30 {
+		t.Fatalf("unexpected root %+v", root)
+	}
+	if len(root.Replies) != 1 || !root.Replies[0].Hidden || root.Replies[0].HiddenReason != "spam" {
+		t.Fatalf("unexpected replies %+v", root.Replies)
+	}
+}
+
+func TestListCommentsHandlerMapsMissingMarket(t *testing.T) {
+	svc := &discussionMock{err: dmarkets.ErrMarketGroupNotFound}
+	rec := serve(ListMarketGroupCommentsHandler(svc), http.MethodGet, "/v0/market-groups/{id}/comments", "/v0/market-groups/9/comments", "")
+	if rec.Code != http.StatusNotFound || svc.target != dcomments.GroupTarget(9) {
+		t.Fatalf("status = %d target=%+v", rec.Code, svc.target)
+	}
+}
+
+func TestPostMarketCommentHandlerRateLimitsPerUser(t *testing.T) {
+	svc := &discussionMock{}
+	limiter := security.NewRateLimiter(rate.Every(time.Hour), 1, time.Hour)
+	handler := PostMarketCommentHandler(svc, signedIn(), limiter)
+
+	rec := serve(handler, http.MethodPost, "/v0/markets/{id}/comments", "/v0/markets/7/comments", `{"body":"hello","parentId":4}`)
+	if rec.Code != http.StatusCreated || svc.parentID != 4 || svc.body != "hello" {
+		t.Fatalf("status = %d body=%s mock=%+v", rec.Code, rec.Body.String(), svc)
+	}
+	rec = serve(handler, http.MethodPost, "/v0/markets/{id}/comments", "/v0/markets/7/comments", `{"body":"again"}`)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+}
+
+func TestCommentHandlersMapDomainErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "validation", err: dcomments.ErrInvalidInput, want: http.StatusBadRequest},
+		{name: "closed", err: dcomments.ErrDiscussionClosed, want: http.StatusConflict},
+		{name: "suspended", err: dusers.ErrAccountSuspended, want: http.StatusForbidden},
+		{name: "edit window", err: dcomments.ErrEditWindowClosed, want: http.StatusConflict},
+		{name: "not author", err: permissions.ErrPermissionDenied, want: http.StatusForbidden},
+		{name: "missing", err: dcomments.ErrCommentNotFound, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(EditCommentHandler(&discussionMock{err: tt.err}, signedIn(), nil), http.MethodPatch, "/v0/comments/{id}", "/v0/comments/3", `{"body":"x"}`)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHideCommentHandlerRequiresSignIn(t *testing.T) {
+	rec := serve(HideCommentHandler(&discussionMock{}, authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}), http.MethodPost, "/v0/admin/comments/{id}/hide", "/v0/admin/comments/3/hide", `{"reason":"spam"}`)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	rec = serve(HideCommentHandler(&discussionMock{}, signedIn()), http.MethodPost, "/v0/admin/comments/{id}/hide", "/v0/admin/comments/3/hide", `{"reason":"spam"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"hidden":true`) {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,191 @@
+package emailhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	demail "socialpredict/internal/domain/email"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+type preferencesService interface {
+	Preferences(ctx context.Context, username string) ([]demail.Preference, error)
+	UpdatePreferences(ctx context.Context, username string, changes map[demail.Kind]bool) ([]demail.Preference, error)
+}
+
+type unsubscribeService interface {
+	Subscription(ctx context.Context, token string) (*demail.Subscription, error)
+	Unsubscribe(ctx context.Context, token string, kind demail.Kind) (*demail.Subscription, error)
+}
+
+type preferenceResponse struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+type preferencesResponse struct {
+	Preferences []preferenceResponse `json:"preferences"`
+}
+
+type subscriptionResponse struct {
+	Username    string               `json:"username"`
+	Preferences []preferenceResponse `json:"preferences"`
+}
+
+type preferenceUpdate struct {
+	Kind    string `json:"kind"`
+	Enabled *bool  `json:"enabled"`
+}
+
+type updatePreferencesRequest struct {
+	Preferences []preferenceUpdate `json:"preferences"`
+}
+
+type unsubscribeRequest struct {
+	Token string `json:"token"`
+	Kind  string `json:"kind"`
+}
+
+// GetPreferencesHandler handles GET /v0/email/preferences.
+func GetPreferencesHandler(svc preferencesService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		user, authErr := auth.CurrentUser(r)
+		if authErr != nil {
+			_ = authhttp.WriteFailure(w, authErr)
+			return
+		}
+		preferences, err := svc.Preferences(r.Context(), user.Username)
+		if err != nil {
+			writeEmailError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, preferencesResponse{Preferences: preferencesFromDomain(preferences)})
+	}
+}
+
+// UpdatePreferencesHandler handles PUT /v0/email/preferences. Kinds left out
+// of the request keep their current setting.
+func UpdatePreferencesHandler(svc preferencesService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		user, authErr := auth.CurrentUser(r)
+		if authErr != nil {
+			_ = authhttp.WriteFailure(w, authErr)
+			return
+		}
+		var request updatePreferencesRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		changes := make(map[demail.Kind]bool, len(request.Preferences))
+		for _, update := range request.Preferences {
+			if update.Enabled == nil {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+				return
+			}
+			changes[demail.Kind(strings.TrimSpace(update.Kind))] = *update.Enabled
+		}
+		preferences, err := svc.UpdatePreferences(r.Context(), user.Username, changes)
+		if err != nil {
+			writeEmailError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, preferencesResponse{Preferences: preferencesFromDomain(preferences)})
+	}
+}
+
+// GetUnsubscribeHandler handles GET /v0/email/unsubscribe?token=. It only
+// describes what the token controls, so link scanners that prefetch it
+// change nothing.
+func GetUnsubscribeHandler(svc unsubscribeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		subscription, err := svc.Subscription(r.Context(), r.URL.Query().Get("token"))
+		if err != nil {
+			writeEmailError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, subscriptionFromDomain(subscription))
+	}
+}
+
+// UnsubscribeHandler handles POST /v0/email/unsubscribe. The token and
+// optional kind come from the query string, as in the one-click
+// List-Unsubscribe POST mail clients send, or from a JSON body. Without a
+// kind every kind is turned off.
+func UnsubscribeHandler(svc unsubscribeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		request := unsubscribeRequest{Token: r.URL.Query().Get("token"), Kind: r.URL.Query().Get("kind")}
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+			var body unsubscribeRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+				return
+			}
+			if body.Token != "" {
+				request.Token = body.Token
+			}
+			if body.Kind != "" {
+				request.Kind = body.Kind
+			}
+		}
+		subscription, err := svc.Unsubscribe(r.Context(), request.Token, demail.Kind(strings.TrimSpace(request.Kind)))
+		if err != nil {
+			writeEmailError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, subscriptionFromDomain(subscription))
+	}
+}
+
+func writeEmailError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, demail.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	case errors.Is(err, demail.ErrInvalidToken):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func preferencesFromDomain(preferences []demail.Preference) []preferenceResponse {
+	out := make([]preferenceResponse, 0, len(preferences))
+	for _, preference := range preferences {
+		out = append(out, preferenceResponse{
+			Kind:        string(preference.Kind),
+			Description: preference.Description,
+			Enabled:     preference.Enabled,
+		})
+	}
+	return out
+}
+
+func subscriptionFromDomain(subscription *demail.Subscription) subscriptionResponse {
+	return subscriptionResponse{
+		Username:    subscription.Username,
+		Preferences: preferencesFromDomain(subscription.Preferences),
+	}
+}
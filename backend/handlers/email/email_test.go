@@ -0,0 +1,112 @@
+package emailhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	demail "socialpredict/internal/domain/email"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+type emailMock struct {
+	changes map[demail.Kind]bool
+	token   string
+	kind    demail.Kind
+}
+
+func (m *emailMock) Preferences(context.Context, string) ([]demail.Preference, error) {
+	return []demail.Preference{{Kind: demail.KindWeeklyDigest, Enabled: false}}, nil
+}
+
+func (m *emailMock) UpdatePreferences(_ context.Context, _ string, changes map[demail.Kind]bool) ([]demail.Preference, error) {
+	m.changes = changes
+	for kind := range changes {
+		if !demail.Known(kind) {
+			return nil, demail.ErrInvalidInput
+		}
+	}
+	return []demail.Preference{{Kind: demail.KindWeeklyDigest, Enabled: changes[demail.KindWeeklyDigest]}}, nil
+}
+
+func (m *emailMock) Subscription(_ context.Context, token string) (*demail.Subscription, error) {
+	if token != "good" {
+		return nil, demail.ErrInvalidToken
+	}
+	return &demail.Subscription{Username: "alice"}, nil
+}
+
+func (m *emailMock) Unsubscribe(_ context.Context, token string, kind demail.Kind) (*demail.Subscription, error) {
+	m.token, m.kind = token, kind
+	return m.Subscription(context.Background(), token)
+}
+
+func TestEmailPreferencesHandlers(t *testing.T) {
+	svc := &emailMock{}
+	auth := authMock{user: &dusers.User{Username: "alice"}}
+
+	rec := httptest.NewRecorder()
+	UpdatePreferencesHandler(svc, auth).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v0/email/preferences", strings.NewReader(`{"preferences":[{"kind":"weekly_digest","enabled":true}]}`)))
+	if rec.Code != http.StatusOK || !svc.changes[demail.KindWeeklyDigest] {
+		t.Fatalf("update status=%d changes=%v body=%s", rec.Code, svc.changes, rec.Body.String())
+	}
+	var body struct {
+		Result preferencesResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || len(body.Result.Preferences) != 1 || !body.Result.Preferences[0].Enabled {
+		t.Fatalf("unexpected response %s: %v", rec.Body.String(), err)
+	}
+
+	unknown := httptest.NewRecorder()
+	UpdatePreferencesHandler(svc, auth).ServeHTTP(unknown, httptest.NewRequest(http.MethodPut, "/v0/email/preferences", strings.NewReader(`{"preferences":[{"kind":"spam","enabled":true}]}`)))
+	if unknown.Code != http.StatusBadRequest {
+		t.Fatalf("unknown kind status = %d", unknown.Code)
+	}
+
+	anonymous := httptest.NewRecorder()
+	GetPreferencesHandler(svc, authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}).ServeHTTP(anonymous, httptest.NewRequest(http.MethodGet, "/v0/email/preferences", nil))
+	if anonymous.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous status = %d", anonymous.Code)
+	}
+}
+
+func TestUnsubscribeHandlersAcceptQueryAndJSONTokens(t *testing.T) {
+	svc := &emailMock{}
+
+	preview := httptest.NewRecorder()
+	GetUnsubscribeHandler(svc).ServeHTTP(preview, httptest.NewRequest(http.MethodGet, "/v0/email/unsubscribe?token=bad", nil))
+	if preview.Code != http.StatusNotFound {
+		t.Fatalf("unknown token status = %d", preview.Code)
+	}
+
+	oneClick := httptest.NewRequest(http.MethodPost, "/v0/email/unsubscribe?token=good", strings.NewReader("List-Unsubscribe=One-Click"))
+	oneClick.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	UnsubscribeHandler(svc).ServeHTTP(rec, oneClick)
+	if rec.Code != http.StatusOK || svc.token != "good" || svc.kind != "" {
+		t.Fatalf("one-click status=%d token=%q kind=%q", rec.Code, svc.token, svc.kind)
+	}
+
+	jsonRequest := httptest.NewRequest(http.MethodPost, "/v0/email/unsubscribe", strings.NewReader(`{"token":"good","kind":"weekly_digest"}`))
+	jsonRequest.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	UnsubscribeHandler(svc).ServeHTTP(rec, jsonRequest)
+	if rec.Code != http.StatusOK || svc.kind != demail.KindWeeklyDigest {
+		t.Fatalf("json status=%d kind=%q", rec.Code, svc.kind)
+	}
+}
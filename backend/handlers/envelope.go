@@ -15,6 +15,8 @@ const (
 	ReasonAuthorizationDenied         FailureReason = "AUTHORIZATION_DENIED"
 	ReasonUserNotApproved             FailureReason = "USER_NOT_APPROVED"
 	ReasonPasswordChangeRequired      FailureReason = "PASSWORD_CHANGE_REQUIRED"
+	ReasonAccountSuspended            FailureReason = "ACCOUNT_SUSPENDED"
+	ReasonAccountBanned               FailureReason = "ACCOUNT_BANNED"
 	ReasonNotFound                    FailureReason = "NOT_FOUND"
 	ReasonRateLimited                 FailureReason = "RATE_LIMITED"
 	ReasonLoginRateLimited            FailureReason = "LOGIN_RATE_LIMITED"
@@ -39,6 +41,8 @@ var publicFailureReasons = []FailureReason{
 	ReasonAuthorizationDenied,
 	ReasonUserNotApproved,
 	ReasonPasswordChangeRequired,
+	ReasonAccountSuspended,
+	ReasonAccountBanned,
 	ReasonNotFound,
 	ReasonRateLimited,
 	ReasonLoginRateLimited,
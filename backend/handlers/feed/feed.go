@@ -0,0 +1,251 @@
+package feedhandlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	dfeed "socialpredict/internal/domain/feed"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/logger"
+
+	"github.com/gorilla/mux"
+)
+
+type activityFeed interface {
+	Follow(ctx context.Context, follower, followee string) (*dfeed.Follow, bool, error)
+	Unfollow(ctx context.Context, follower, followee string) error
+	Following(ctx context.Context, follower string) ([]*dfeed.Follow, error)
+	FollowerCount(ctx context.Context, username string) (int64, error)
+	Feed(ctx context.Context, username, cursor string, limit int) (*dfeed.Page, error)
+	Global(ctx context.Context, cursor string, limit int) (*dfeed.Page, error)
+}
+
+type followResponse struct {
+	Username      string `json:"username"`
+	Following     bool   `json:"following"`
+	FollowerCount int64  `json:"followerCount"`
+	FollowedAt    string `json:"followedAt,omitempty"`
+}
+
+type followingEntryResponse struct {
+	Username   string `json:"username"`
+	FollowedAt string `json:"followedAt"`
+}
+
+type followingResponse struct {
+	Following []followingEntryResponse `json:"following"`
+}
+
+type itemResponse struct {
+	Kind        string `json:"kind"`
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	MarketID    int64  `json:"marketId"`
+	MarketTitle string `json:"marketTitle"`
+	Action      string `json:"action,omitempty"`
+	Outcome     string `json:"outcome,omitempty"`
+	Amount      int64  `json:"amount,omitempty"`
+	Resolution  string `json:"resolution,omitempty"`
+	OccurredAt  string `json:"occurredAt"`
+}
+
+type pageResponse struct {
+	Items      []itemResponse `json:"items"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// FollowUserHandler handles POST /v0/users/{username}/follow.
+func FollowUserHandler(svc activityFeed, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, followee, ok := followRequest(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		follow, created, err := svc.Follow(r.Context(), username, followee)
+		if err != nil {
+			writeFeedError(w, err)
+			return
+		}
+		count, err := svc.FollowerCount(r.Context(), follow.Followee)
+		if err != nil {
+			writeFeedError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		_ = handlers.WriteResult(w, status, followResponse{
+			Username:      follow.Followee,
+			Following:     true,
+			FollowerCount: count,
+			FollowedAt:    follow.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// UnfollowUserHandler handles DELETE /v0/users/{username}/follow.
+func UnfollowUserHandler(svc activityFeed, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, followee, ok := followRequest(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		if err := svc.Unfollow(r.Context(), username, followee); err != nil {
+			writeFeedError(w, err)
+			return
+		}
+		count, err := svc.FollowerCount(r.Context(), followee)
+		if err != nil {
+			writeFeedError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, followResponse{Username: followee, Following: false, FollowerCount: count})
+	}
+}
+
+// FollowingHandler handles GET /v0/profile/following.
+func FollowingHandler(svc activityFeed, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		user, authErr := auth.CurrentUser(r)
+		if authErr != nil {
+			_ = authhttp.WriteFailure(w, authErr)
+			return
+		}
+		following, err := svc.Following(r.Context(), user.Username)
+		if err != nil {
+			writeFeedError(w, err)
+			return
+		}
+		response := followingResponse{Following: make([]followingEntryResponse, 0, len(following))}
+		for _, follow := range following {
+			response.Following = append(response.Following, followingEntryResponse{
+				Username:   follow.Followee,
+				FollowedAt: follow.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// FeedHandler handles GET /v0/feed: activity from the people the caller
+// follows, newest first. Pass the returned nextCursor as ?cursor= for the
+// next page.
+func FeedHandler(svc activityFeed, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		user, authErr := auth.CurrentUser(r)
+		if authErr != nil {
+			_ = authhttp.WriteFailure(w, authErr)
+			return
+		}
+		cursor, limit, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		page, err := svc.Feed(r.Context(), user.Username, cursor, limit)
+		if err != nil {
+			writeFeedError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, pageResponseFromDomain(page))
+	}
+}
+
+// GlobalFeedHandler handles GET /v0/activity: activity from everyone.
+func GlobalFeedHandler(svc activityFeed) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		cursor, limit, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		page, err := svc.Global(r.Context(), cursor, limit)
+		if err != nil {
+			writeFeedError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, pageResponseFromDomain(page))
+	}
+}
+
+func followRequest(w http.ResponseWriter, r *http.Request, svc activityFeed, auth authsvc.Authenticator) (string, string, bool) {
+	if svc == nil || auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return "", "", false
+	}
+	user, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return "", "", false
+	}
+	followee := strings.TrimSpace(mux.Vars(r)["username"])
+	if followee == "" {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return "", "", false
+	}
+	return user.Username, followee, true
+}
+
+func parsePage(w http.ResponseWriter, r *http.Request) (string, int, bool) {
+	query := r.URL.Query()
+	var limit int
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return "", 0, false
+		}
+		limit = value
+	}
+	return strings.TrimSpace(query.Get("cursor")), limit, true
+}
+
+func writeFeedError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dfeed.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+	case errors.Is(err, dfeed.ErrUserNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, dfeed.ErrFollowingFull):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	default:
+		logger.LogError("Feed", "Feed", err)
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func pageResponseFromDomain(page *dfeed.Page) pageResponse {
+	response := pageResponse{Items: make([]itemResponse, 0, len(page.Items)), NextCursor: page.NextCursor}
+	for _, item := range page.Items {
+		response.Items = append(response.Items, itemResponse{
+			Kind:        string(item.Kind),
+			ID:          item.ID,
+			Username:    item.Username,
+			MarketID:    item.MarketID,
+			MarketTitle: item.MarketTitle,
+			Action:      item.Action,
+			Outcome:     item.Outcome,
+			Amount:      item.Amount,
+			Resolution:  item.Resolution,
+			OccurredAt:  item.OccurredAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return response
+}
@@ -0,0 +1,140 @@
+package feedhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dfeed "socialpredict/internal/domain/feed"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+
+	"github.com/gorilla/mux"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+type feedMock struct {
+	created bool
+	cursor  string
+	limit   int
+	err     error
+}
+
+var followedAt = time.Date(2026, 7, 4, 9, 0, 0, 0, time.UTC)
+
+func (m *feedMock) Follow(_ context.Context, follower, followee string) (*dfeed.Follow, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	return &dfeed.Follow{ID: 1, Follower: follower, Followee: followee, CreatedAt: followedAt}, m.created, nil
+}
+
+func (m *feedMock) Unfollow(context.Context, string, string) error { return m.err }
+
+func (m *feedMock) Following(_ context.Context, follower string) ([]*dfeed.Follow, error) {
+	return []*dfeed.Follow{{ID: 1, Follower: follower, Followee: "bob", CreatedAt: followedAt}}, m.err
+}
+
+func (m *feedMock) FollowerCount(context.Context, string) (int64, error) { return 3, nil }
+
+func (m *feedMock) Feed(_ context.Context, _ string, cursor string, limit int) (*dfeed.Page, error) {
+	return m.Global(context.Background(), cursor, limit)
+}
+
+func (m *feedMock) Global(_ context.Context, cursor string, limit int) (*dfeed.Page, error) {
+	m.cursor, m.limit = cursor, limit
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dfeed.Page{
+		Items: []dfeed.Item{
+			{Kind: dfeed.KindTrade, ID: 9, Username: "bob", MarketID: 7, MarketTitle: "Will it rain?", Action: dfeed.ActionSell, Outcome: "YES", Amount: 5, OccurredAt: followedAt},
+		},
+		NextCursor: "next",
+	}, nil
+}
+
+func signedIn() authMock {
+	return authMock{user: &dusers.User{Username: "alice"}}
+}
+
+func TestFollowUserHandlerStatusAndErrors(t *testing.T) {
+	svc := &feedMock{created: true}
+	router := mux.NewRouter()
+	router.Handle("/v0/users/{username}/follow", FollowUserHandler(svc, signedIn())).Methods(http.MethodPost)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v0/users/bob/follow", nil))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var decoded struct {
+		Result followResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil || decoded.Result.Username != "bob" || !decoded.Result.Following || decoded.Result.FollowerCount != 3 {
+		t.Fatalf("unexpected response %+v, %v", decoded.Result, err)
+	}
+
+	for err, want := range map[error]int{
+		dfeed.ErrInvalidInput:  http.StatusBadRequest,
+		dfeed.ErrUserNotFound:  http.StatusNotFound,
+		dfeed.ErrFollowingFull: http.StatusConflict,
+	} {
+		svc.err = err
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v0/users/bob/follow", nil))
+		if rec.Code != want {
+			t.Fatalf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
+
+func TestFeedHandlersPassCursorAndShapeItems(t *testing.T) {
+	svc := &feedMock{}
+	rec := httptest.NewRecorder()
+	FeedHandler(svc, signedIn()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/feed?cursor=abc&limit=5", nil))
+	if rec.Code != http.StatusOK || svc.cursor != "abc" || svc.limit != 5 {
+		t.Fatalf("status = %d cursor=%q limit=%d", rec.Code, svc.cursor, svc.limit)
+	}
+	var decoded struct {
+		Result pageResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if item := decoded.Result.Items[0]; item.Kind != "trade" || item.Action != "SELL" || item.Amount != 5 || decoded.Result.NextCursor != "next" {
+		t.Fatalf("unexpected page %+v", decoded.Result)
+	}
+
+	missing := authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}
+	rec = httptest.NewRecorder()
+	FeedHandler(svc, missing).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/feed", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a session, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	GlobalFeedHandler(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/activity?limit=-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a negative limit to be rejected, got %d", rec.Code)
+	}
+	svc.err = dfeed.ErrInvalidInput
+	rec = httptest.NewRecorder()
+	GlobalFeedHandler(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/activity?cursor=bad", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad cursor to be rejected, got %d", rec.Code)
+	}
+}
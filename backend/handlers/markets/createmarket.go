@@ -254,6 +254,7 @@ func logCreateMarketFailure(username string, err error) {
 
 	switch err {
 	case dmarkets.ErrUserNotFound,
+		dmarkets.ErrAccountSuspended,
 		dmarkets.ErrInsufficientBalance,
 		dmarkets.ErrInvalidQuestionLength,
 		dmarkets.ErrInvalidDescriptionLength,
@@ -22,8 +22,11 @@ import (
 
 const (
 	marketDiscoverySnapshotKind            = "market_discovery"
-	marketDiscoverySnapshotVersion         = "v2"
+	marketDiscoverySnapshotVersion         = "v3"
 	marketDiscoverySnapshotTargetFreshness = 10 * time.Minute
+	// marketDiscoveryTrendingFallbackLimit sizes the trending section when a
+	// page has no curated recommendation limit.
+	marketDiscoveryTrendingFallbackLimit = 5
 )
 
 type marketDiscoveryService interface {
@@ -60,8 +63,11 @@ type marketDiscoveryReadModelResponse struct {
 	TopicNav      *discoveryPageResponse        `json:"topicNav,omitempty"`
 	Markets       []*dto.MarketOverviewResponse `json:"markets"`
 	PinnedMarkets []pinnedMarketResponse        `json:"pinnedMarkets"`
-	Total         int                           `json:"total"`
-	Freshness     dto.Freshness                 `json:"freshness"`
+	// TrendingMarkets is the auto-populated section of the page's active
+	// markets with the highest trending scores.
+	TrendingMarkets []*dto.MarketOverviewResponse `json:"trendingMarkets"`
+	Total           int                           `json:"total"`
+	Freshness       dto.Freshness                 `json:"freshness"`
 }
 
 type discoveryPageResponse struct {
@@ -116,7 +122,7 @@ func (h *MarketDiscoveryReadModelHandler) Get(w http.ResponseWriter, r *http.Req
 		slug = marketdiscovery.PageSlugMarkets
 	}
 
-	snapshotKey := marketDiscoverySnapshotKey(slug, params.status, params.filters.TagSlug, params.limit, params.offset)
+	snapshotKey := marketDiscoverySnapshotKey(slug, params.status, params.filters.TagSlug, params.filters.Sort, params.limit, params.offset)
 	if snapshot, err := h.snapshots.Get(r.Context(), snapshotKey); err == nil && h.snapshotUsable(snapshot) {
 		var response marketDiscoveryReadModelResponse
 		if unmarshalErr := json.Unmarshal([]byte(snapshot.PayloadJSON), &response); unmarshalErr == nil {
@@ -191,13 +197,19 @@ func (h *MarketDiscoveryReadModelHandler) buildResponse(ctx context.Context, slu
 		return marketDiscoveryReadModelResponse{}, err
 	}
 
+	trending, err := h.trendingMarketResponses(ctx, filters.TagSlug, layout.CuratedRecommendationLimit)
+	if err != nil {
+		return marketDiscoveryReadModelResponse{}, err
+	}
+
 	return marketDiscoveryReadModelResponse{
-		Layout:        layout,
-		TopicNav:      topicNav,
-		Markets:       overviews,
-		PinnedMarkets: pinned,
-		Total:         total,
-		Freshness:     readModelFreshnessToResponse(readmodels.NewFreshness(h.now(), "read_model", marketDiscoverySnapshotTargetFreshness, false)),
+		Layout:          layout,
+		TopicNav:        topicNav,
+		Markets:         overviews,
+		PinnedMarkets:   pinned,
+		TrendingMarkets: trending,
+		Total:           total,
+		Freshness:       readModelFreshnessToResponse(readmodels.NewFreshness(h.now(), "read_model", marketDiscoverySnapshotTargetFreshness, false)),
 	}, nil
 }
 
@@ -232,7 +244,7 @@ func (h *MarketDiscoveryReadModelHandler) fetchMarketDiscoveryOverviews(ctx cont
 }
 
 func (h *MarketDiscoveryReadModelHandler) fetchMarkets(ctx context.Context, status string, filters dmarkets.ListFilters, limit int, offset int) ([]*dmarkets.Market, error) {
-	if status != "" && filters.CreatedBy == "" && filters.TagSlug == "" {
+	if status != "" && filters.CreatedBy == "" && filters.TagSlug == "" && filters.Sort != dmarkets.ListSortTrending {
 		return h.markets.ListByStatus(ctx, status, dmarkets.Page{Limit: limit, Offset: offset})
 	}
 	filters.Limit = limit
@@ -240,6 +252,33 @@ func (h *MarketDiscoveryReadModelHandler) fetchMarkets(ctx context.Context, stat
 	return h.markets.ListMarkets(ctx, filters)
 }
 
+// trendingMarketResponses lists the page's active markets with a positive
+// trending score, highest first. Services without grouped discovery rows
+// leave the section empty.
+func (h *MarketDiscoveryReadModelHandler) trendingMarketResponses(ctx context.Context, tagSlug string, limit int) ([]*dto.MarketOverviewResponse, error) {
+	provider, ok := h.markets.(marketDiscoveryListProvider)
+	if !ok {
+		return []*dto.MarketOverviewResponse{}, nil
+	}
+	if limit <= 0 {
+		limit = marketDiscoveryTrendingFallbackLimit
+	}
+	page, err := provider.ListMarketDiscovery(ctx, dmarkets.ListFilters{
+		Status:       dmarkets.MarketStatusActive,
+		TagSlug:      tagSlug,
+		Sort:         dmarkets.ListSortTrending,
+		TrendingOnly: true,
+		Limit:        limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		return []*dto.MarketOverviewResponse{}, nil
+	}
+	return buildMarketDiscoveryOverviewResponses(ctx, h.markets, page.Rows)
+}
+
 func (h *MarketDiscoveryReadModelHandler) pinnedMarketResponses(ctx context.Context, pins []discoveryPinResponse) ([]pinnedMarketResponse, error) {
 	responses := make([]pinnedMarketResponse, 0)
 	for _, pin := range pins {
@@ -310,14 +349,17 @@ func (h *MarketDiscoveryReadModelHandler) now() time.Time {
 	return h.clock().UTC()
 }
 
-func marketDiscoverySnapshotKey(slug string, status string, tagSlug string, limit int, offset int) string {
+func marketDiscoverySnapshotKey(slug string, status string, tagSlug string, sort string, limit int, offset int) string {
 	if status == "" {
 		status = "all"
 	}
 	if tagSlug == "" {
 		tagSlug = "none"
 	}
-	return fmt.Sprintf("market_discovery:%s:%s:status=%s:tag=%s:limit=%d:offset=%d", marketDiscoverySnapshotVersion, slug, status, tagSlug, limit, offset)
+	if sort == "" {
+		sort = dmarkets.ListSortNewest
+	}
+	return fmt.Sprintf("market_discovery:%s:%s:status=%s:tag=%s:sort=%s:limit=%d:offset=%d", marketDiscoverySnapshotVersion, slug, status, tagSlug, sort, limit, offset)
 }
 
 func freshnessFromSnapshot(snapshot *readmodelrepo.Snapshot) readmodels.Freshness {
@@ -98,9 +98,10 @@ type MarketGroupAnswerResponse struct {
 
 // MarketGroupDetailsResponse returns group metadata with child market overviews.
 type MarketGroupDetailsResponse struct {
-	Group   *MarketGroupResponse        `json:"group"`
-	Creator *CreatorResponse            `json:"creator"`
-	Answers []MarketGroupAnswerResponse `json:"answers"`
+	Group         *MarketGroupResponse        `json:"group"`
+	Creator       *CreatorResponse            `json:"creator"`
+	Answers       []MarketGroupAnswerResponse `json:"answers"`
+	FollowerCount int64                       `json:"followerCount"`
 }
 
 type MarketGroupAnswerAdditionResponse struct {
@@ -371,6 +372,7 @@ type MarketDetailsResponse struct {
 	TotalVolume           int64                                `json:"totalVolume"`
 	MarketDust            int64                                `json:"marketDust"`
 	DescriptionAmendments []MarketDescriptionAmendmentResponse `json:"descriptionAmendments"`
+	FollowerCount         int64                                `json:"followerCount"`
 }
 
 type MarketDescriptionAmendmentResponse struct {
@@ -416,3 +418,25 @@ type SearchResponse struct {
 	TotalCount      int                       `json:"totalCount"`
 	FallbackUsed    bool                      `json:"fallbackUsed"`
 }
+
+// ProbabilityCandleResponse is one bucket of a market's probability history.
+type ProbabilityCandleResponse struct {
+	Start   time.Time `json:"start"`
+	Open    float64   `json:"open"`
+	High    float64   `json:"high"`
+	Low     float64   `json:"low"`
+	Close   float64   `json:"close"`
+	Volume  int64     `json:"volume"`
+	Trades  int64     `json:"trades"`
+	Traders int64     `json:"traders"`
+}
+
+// ProbabilityHistoryResponse represents the HTTP response for market
+// probability candles.
+type ProbabilityHistoryResponse struct {
+	MarketID int64                       `json:"marketId"`
+	Interval string                      `json:"interval"`
+	From     time.Time                   `json:"from"`
+	To       time.Time                   `json:"to"`
+	Candles  []ProbabilityCandleResponse `json:"candles"`
+}
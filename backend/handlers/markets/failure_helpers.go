@@ -39,6 +39,8 @@ func writeCreateError(w http.ResponseWriter, err error) {
 		_ = handlers.WriteFailure(w, http.StatusUnprocessableEntity, handlers.ReasonInsufficientBalance)
 	case errors.Is(err, dmarkets.ErrUnauthorized):
 		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonUserNotApproved)
+	case errors.Is(err, dmarkets.ErrAccountSuspended):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountSuspended)
 	case isValidationError(err):
 		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
 	default:
@@ -10,7 +10,9 @@ import (
 
 	"socialpredict/handlers"
 	"socialpredict/handlers/markets/dto"
+	privacyhandlers "socialpredict/handlers/privacy"
 	dmarkets "socialpredict/internal/domain/markets"
+	dwatchlists "socialpredict/internal/domain/watchlists"
 	authsvc "socialpredict/internal/service/auth"
 	"socialpredict/logger"
 	"socialpredict/security"
@@ -37,11 +39,8 @@ type Handler struct {
 	service         Service
 	auth            authsvc.Authenticator
 	securityService *security.SecurityService
-	invalidator     readModelInvalidator
-}
-
-type readModelInvalidator interface {
-	InvalidateAfterMarketTransaction(ctx context.Context, username string, marketID int64, reason string) error
+	followers       followerCounter
+	privacy         *privacyhandlers.Guard
 }
 
 type marketLeaderboardReadModelService interface {
@@ -58,12 +57,6 @@ func NewHandler(service Service, auth authsvc.Authenticator, securityService *se
 	}
 }
 
-// SetReadModelInvalidator wires optional post-mutation display read-model
-// invalidation without making it part of the market transaction service.
-func (h *Handler) SetReadModelInvalidator(invalidator readModelInvalidator) {
-	h.invalidator = invalidator
-}
-
 // CreateMarket handles POST /markets
 func (h *Handler) CreateMarket(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -115,12 +108,6 @@ func (h *Handler) CreateMarket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.invalidator != nil {
-		if err := h.invalidator.InvalidateAfterMarketTransaction(r.Context(), user.Username, market.ID, "market_created"); err != nil {
-			logger.LogError("CreateMarket", "InvalidateReadModels", err)
-		}
-	}
-
 	response := marketToResponse(market)
 
 	_ = writeJSON(w, http.StatusCreated, response)
@@ -213,7 +200,7 @@ func (h *Handler) ListMarkets(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var markets []*dmarkets.Market
-	if params.status != "" && params.filters.CreatedBy == "" && params.filters.TagSlug == "" {
+	if params.status != "" && params.filters.CreatedBy == "" && params.filters.TagSlug == "" && params.filters.Sort != dmarkets.ListSortTrending {
 		page := dmarkets.Page{Limit: params.limit, Offset: params.offset}
 		markets, err = h.service.ListByStatus(r.Context(), params.status, page)
 	} else {
@@ -338,11 +325,6 @@ func (h *Handler) ResolveMarket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusNoContent)
-	if h.invalidator != nil {
-		if err := h.invalidator.InvalidateAfterMarketTransaction(r.Context(), user.Username, id, "market_resolved"); err != nil {
-			logger.LogError("ResolveMarket", "InvalidateReadModels", err)
-		}
-	}
 }
 
 // ListByStatus handles GET /markets/status/{status}
@@ -386,7 +368,8 @@ func (h *Handler) ListByStatus(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetDetails handles GET /markets/{id} with full market details
+// GetDetails handles GET /markets/{id} with full market details. An asOf
+// query parameter returns the market as it stood at that time.
 func (h *Handler) GetDetails(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeMethodNotAllowed(w)
@@ -406,18 +389,31 @@ func (h *Handler) GetDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	details, err := h.service.GetMarketDetails(r.Context(), id)
+	asOf, err := handlers.ParseAsOf(r)
+	if err != nil {
+		writeInvalidRequest(w)
+		return
+	}
+
+	var details *dmarkets.MarketOverview
+	if asOf.IsZero() {
+		details, err = h.service.GetMarketDetails(r.Context(), id)
+	} else {
+		details, err = h.marketDetailsAsOf(r.Context(), id, asOf)
+	}
 	if err != nil {
 		writeDetailsError(w, err)
 		return
 	}
 
 	response := marketDetailsToResponse(r.Context(), h.service, details)
+	response.FollowerCount = h.followerCount(r.Context(), dwatchlists.MarketTarget(id))
 
 	_ = writeJSON(w, http.StatusOK, response)
 }
 
-// MarketLeaderboard handles GET /markets/{id}/leaderboard
+// MarketLeaderboard handles GET /markets/{id}/leaderboard. With asOf it
+// replays the market up to that time and skips the read model.
 func (h *Handler) MarketLeaderboard(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeMethodNotAllowed(w)
@@ -431,10 +427,21 @@ func (h *Handler) MarketLeaderboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	page := parsePagination(r, 100)
+	asOf, err := handlers.ParseAsOf(r)
+	if err != nil {
+		writeInvalidRequest(w)
+		return
+	}
 
 	var leaderboard []*dmarkets.LeaderboardRow
 	var freshness *dto.Freshness
-	if snapshot, snapshotErr := h.marketLeaderboardReadModel(r.Context(), id, page); snapshotErr == nil && snapshot != nil {
+	if !asOf.IsZero() {
+		leaderboard, err = h.marketLeaderboardAsOf(r.Context(), id, asOf, page)
+		if err != nil {
+			writeLeaderboardError(w, err)
+			return
+		}
+	} else if snapshot, snapshotErr := h.marketLeaderboardReadModel(r.Context(), id, page); snapshotErr == nil && snapshot != nil {
 		leaderboard = snapshot.Rows
 		freshnessResponse := readModelFreshnessToResponse(snapshot.Freshness())
 		freshness = &freshnessResponse
@@ -451,6 +458,13 @@ func (h *Handler) MarketLeaderboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	leaderRows := buildLeaderboardRows(leaderboard)
+	names := make([]*string, 0, len(leaderRows))
+	for i := range leaderRows {
+		names = append(names, &leaderRows[i].Username)
+	}
+	if !h.maskTraders(w, r, "MarketLeaderboard", names) {
+		return
+	}
 
 	response := dto.LeaderboardResponse{
 		MarketID:    id,
@@ -324,6 +324,31 @@ func TestHandlerListMarkets_UsesTagFilterWhenStatusProvided(t *testing.T) {
 	}
 }
 
+func TestHandlerListMarkets_TrendingSortUsesListMarkets(t *testing.T) {
+	service := &contractServiceMock{
+		listFn: func(ctx context.Context, filters dmarkets.ListFilters) ([]*dmarkets.Market, error) {
+			want := dmarkets.ListFilters{Status: "active", Sort: dmarkets.ListSortTrending, Limit: 5}
+			if filters != want {
+				t.Fatalf("expected filters %+v, got %+v", want, filters)
+			}
+			return []*dmarkets.Market{}, nil
+		},
+		listByStatusFn: func(ctx context.Context, status string, p dmarkets.Page) ([]*dmarkets.Market, error) {
+			t.Fatalf("expected trending request to use ListMarkets, got ListByStatus(%q, %+v)", status, p)
+			return nil, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/markets?status=active&sort=trending&limit=5", nil)
+	rr := httptest.NewRecorder()
+
+	newContractHandler(service, nil).ListMarkets(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+}
+
 func TestHandlerSearchMarkets_SupportsLegacyQAndInvalidRequestFailure(t *testing.T) {
 	now := time.Now().UTC()
 	market := &dmarkets.Market{
@@ -31,6 +31,11 @@ func parseListMarketsParams(r *http.Request) (listMarketsParams, error) {
 		return listMarketsParams{}, statusErr
 	}
 
+	sort, sortErr := dmarkets.ParseListSort(r.URL.Query().Get("sort"))
+	if sortErr != nil {
+		return listMarketsParams{}, sortErr
+	}
+
 	page := parseListPage(r)
 
 	return listMarketsParams{
@@ -41,6 +46,7 @@ func parseListMarketsParams(r *http.Request) (listMarketsParams, error) {
 			Status:    status,
 			CreatedBy: strings.TrimSpace(r.URL.Query().Get("created_by")),
 			TagSlug:   normalizeTagSlugParam(r.URL.Query().Get("tagSlug")),
+			Sort:      sort,
 			Limit:     page.Limit,
 			Offset:    page.Offset,
 		},
@@ -78,7 +84,7 @@ func parseListPage(r *http.Request) dmarkets.Page {
 }
 
 func fetchMarketsByStatus(r *http.Request, svc dmarkets.ServiceInterface, params listMarketsParams) ([]*dmarkets.Market, error) {
-	if params.filters.TagSlug != "" || params.filters.CreatedBy != "" {
+	if params.filters.TagSlug != "" || params.filters.CreatedBy != "" || params.filters.Sort == dmarkets.ListSortTrending {
 		return svc.ListMarkets(r.Context(), params.filters)
 	}
 	return svc.ListByStatus(r.Context(), params.filters.Status, params.page)
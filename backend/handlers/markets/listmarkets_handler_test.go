@@ -178,6 +178,26 @@ func TestListMarketsHandlerFactoryUsesDefaultListingFilters(t *testing.T) {
 	}
 }
 
+func TestListMarketsHandlerFactoryTrendingSortUsesFilteredListing(t *testing.T) {
+	mockSvc := &listMarketsServiceMock{}
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/markets?status=active&sort=Trending&limit=5", nil)
+	res := httptest.NewRecorder()
+
+	ListMarketsHandlerFactory(mockSvc)(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, res.Code)
+	}
+	if mockSvc.capturedStatus != "" {
+		t.Fatalf("expected trending sort to skip ListByStatus, got status %q", mockSvc.capturedStatus)
+	}
+	expectedFilters := dmarkets.ListFilters{Status: "active", Sort: dmarkets.ListSortTrending, Limit: 5}
+	if mockSvc.capturedFilters != expectedFilters {
+		t.Fatalf("expected filters %+v, got %+v", expectedFilters, mockSvc.capturedFilters)
+	}
+}
+
 func TestListMarketsHandlerFactoryUsesFailureEnvelope(t *testing.T) {
 	t.Run("invalid status", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/v0/markets?status=maybe", nil)
@@ -188,6 +208,15 @@ func TestListMarketsHandlerFactoryUsesFailureEnvelope(t *testing.T) {
 		assertFailureEnvelope(t, res, http.StatusBadRequest, handlers.ReasonInvalidRequest)
 	})
 
+	t.Run("invalid sort", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/markets?sort=hot", nil)
+		res := httptest.NewRecorder()
+
+		ListMarketsHandlerFactory(&listMarketsServiceMock{})(res, req)
+
+		assertFailureEnvelope(t, res, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+	})
+
 	t.Run("domain validation", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/v0/markets", nil)
 		res := httptest.NewRecorder()
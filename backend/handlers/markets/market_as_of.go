@@ -0,0 +1,32 @@
+package marketshandlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+var errAsOfUnsupported = errors.New("point-in-time market queries are unavailable")
+
+type marketAsOfService interface {
+	GetMarketDetailsAsOf(ctx context.Context, marketID int64, asOf time.Time) (*dmarkets.MarketOverview, error)
+	GetMarketLeaderboardAsOf(ctx context.Context, marketID int64, asOf time.Time, p dmarkets.Page) ([]*dmarkets.LeaderboardRow, error)
+}
+
+func (h *Handler) marketDetailsAsOf(ctx context.Context, marketID int64, asOf time.Time) (*dmarkets.MarketOverview, error) {
+	service, ok := h.service.(marketAsOfService)
+	if !ok {
+		return nil, errAsOfUnsupported
+	}
+	return service.GetMarketDetailsAsOf(ctx, marketID, asOf)
+}
+
+func (h *Handler) marketLeaderboardAsOf(ctx context.Context, marketID int64, asOf time.Time, page dmarkets.Page) ([]*dmarkets.LeaderboardRow, error) {
+	service, ok := h.service.(marketAsOfService)
+	if !ok {
+		return nil, errAsOfUnsupported
+	}
+	return service.GetMarketLeaderboardAsOf(ctx, marketID, asOf, page)
+}
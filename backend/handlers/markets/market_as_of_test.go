@@ -0,0 +1,100 @@
+package marketshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/markets/dto"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/security"
+
+	"github.com/gorilla/mux"
+)
+
+type marketAsOfMock struct {
+	MockService
+	asOf time.Time
+}
+
+func (m *marketAsOfMock) GetMarketDetailsAsOf(ctx context.Context, marketID int64, asOf time.Time) (*dmarkets.MarketOverview, error) {
+	m.asOf = asOf
+	overview, _ := m.MockService.GetMarketDetails(ctx, marketID)
+	overview.NumUsers = 1
+	return overview, nil
+}
+
+func (m *marketAsOfMock) GetMarketLeaderboardAsOf(_ context.Context, marketID int64, asOf time.Time, _ dmarkets.Page) ([]*dmarkets.LeaderboardRow, error) {
+	m.asOf = asOf
+	return []*dmarkets.LeaderboardRow{{Username: "alice", Profit: 5, Rank: 1}}, nil
+}
+
+func serveMarketAsOf(handler func(http.ResponseWriter, *http.Request), target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestGetDetailsAsOfUsesHistoricalOverview(t *testing.T) {
+	svc := &marketAsOfMock{}
+	handler := NewHandler(svc, nil, security.NewSecurityService())
+
+	rec := serveMarketAsOf(handler.GetDetails, "/v0/markets/7?asOf=2026-07-01T12:00:00Z")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if !svc.asOf.Equal(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("asOf = %s", svc.asOf)
+	}
+	if svc.DetailsCalls != 1 {
+		t.Fatalf("details calls = %d, want only the as-of lookup", svc.DetailsCalls)
+	}
+	var decoded struct {
+		NumUsers int `json:"numUsers"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.NumUsers != 1 {
+		t.Fatalf("numUsers = %d, want historical 1", decoded.NumUsers)
+	}
+}
+
+func TestMarketLeaderboardAsOfSkipsReadModel(t *testing.T) {
+	svc := &marketAsOfMock{MockService: MockService{
+		MarketLeaderboardFn: func(context.Context, int64, dmarkets.Page) ([]*dmarkets.LeaderboardRow, error) {
+			t.Fatalf("live leaderboard should not be used with asOf")
+			return nil, nil
+		},
+	}}
+	handler := NewHandler(svc, nil, security.NewSecurityService())
+
+	rec := serveMarketAsOf(handler.MarketLeaderboard, "/v0/markets/7/leaderboard?asOf=2026-07-01T12:00:00Z")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var resp handlers.SuccessEnvelope[dto.LeaderboardResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Result.Freshness != nil || len(resp.Result.Leaderboard) != 1 || resp.Result.Leaderboard[0].Username != "alice" {
+		t.Fatalf("unexpected leaderboard payload: %+v", resp.Result)
+	}
+}
+
+func TestMarketAsOfRejectsMalformedTimestamp(t *testing.T) {
+	handler := NewHandler(&marketAsOfMock{}, nil, security.NewSecurityService())
+
+	for _, serve := range []func(http.ResponseWriter, *http.Request){handler.GetDetails, handler.MarketLeaderboard} {
+		rec := serveMarketAsOf(serve, "/v0/markets/7?asOf=yesterday")
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", rec.Code)
+		}
+	}
+}
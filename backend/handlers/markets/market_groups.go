@@ -13,6 +13,7 @@ import (
 	"socialpredict/handlers/markets/dto"
 	dmarkets "socialpredict/internal/domain/markets"
 	"socialpredict/internal/domain/readmodels"
+	dwatchlists "socialpredict/internal/domain/watchlists"
 	"socialpredict/logger"
 
 	"github.com/gorilla/mux"
@@ -88,8 +89,6 @@ func (h *Handler) CreateMarketGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.invalidateCreatedMarketGroup(r.Context(), user.Username, group)
-
 	overview, err := svc.GetMarketGroupOverview(r.Context(), group.ID)
 	if err != nil {
 		response := dto.MarketGroupDetailsResponse{
@@ -129,7 +128,9 @@ func (h *Handler) GetMarketGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_ = writeJSON(w, http.StatusOK, marketGroupOverviewToResponse(r.Context(), h.service, overview))
+	response := marketGroupOverviewToResponse(r.Context(), h.service, overview)
+	response.FollowerCount = h.followerCount(r.Context(), dwatchlists.MarketGroupTarget(id))
+	_ = writeJSON(w, http.StatusOK, response)
 }
 
 // ResolveMarketGroup handles POST /v0/market-groups/{id}/resolve.
@@ -177,14 +178,6 @@ func (h *Handler) ResolveMarketGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.invalidator != nil && group != nil {
-		for _, member := range group.Members {
-			if err := h.invalidator.InvalidateAfterMarketTransaction(r.Context(), user.Username, member.MarketID, "market_group_resolved"); err != nil {
-				logger.LogError("ResolveMarketGroup", "InvalidateReadModels", err)
-			}
-		}
-	}
-
 	_ = writeJSON(w, http.StatusOK, marketGroupToResponse(group))
 }
 
@@ -234,11 +227,6 @@ func (h *Handler) ProposeMarketGroupAnswerAddition(w http.ResponseWriter, r *htt
 		writeMarketGroupDetailsError(w, err)
 		return
 	}
-	if h.invalidator != nil && addition != nil && addition.MarketID > 0 {
-		if err := h.invalidator.InvalidateAfterMarketTransaction(r.Context(), user.Username, addition.MarketID, "market_group_answer_added"); err != nil {
-			logger.LogError("ProposeMarketGroupAnswerAddition", "InvalidateReadModels", err)
-		}
-	}
 	_ = writeJSON(w, http.StatusCreated, marketGroupAnswerAdditionToResponse(addition))
 }
 
@@ -329,11 +317,6 @@ func (h *Handler) ReviewMarketGroupAnswerAddition(w http.ResponseWriter, r *http
 		writeMarketGroupDetailsError(w, err)
 		return
 	}
-	if h.invalidator != nil && addition != nil && addition.MarketID > 0 {
-		if err := h.invalidator.InvalidateAfterMarketTransaction(r.Context(), user.Username, addition.MarketID, "market_group_answer_reviewed"); err != nil {
-			logger.LogError("ReviewMarketGroupAnswerAddition", "InvalidateReadModels", err)
-		}
-	}
 	_ = writeJSON(w, http.StatusOK, marketGroupAnswerAdditionToResponse(addition))
 }
 
@@ -397,7 +380,15 @@ func (h *Handler) MarketGroupBets(w http.ResponseWriter, r *http.Request) {
 		writeMarketGroupDetailsError(w, err)
 		return
 	}
-	if err := handlers.WriteResult(w, http.StatusOK, marketGroupBetsPageToResponse(result)); err != nil {
+	response := marketGroupBetsPageToResponse(result)
+	names := make([]*string, 0, len(response.Bets))
+	for i := range response.Bets {
+		names = append(names, &response.Bets[i].Username)
+	}
+	if !h.maskTraders(w, r, "MarketGroupBets", names) {
+		return
+	}
+	if err := handlers.WriteResult(w, http.StatusOK, response); err != nil {
 		logger.LogError("MarketGroupBets", "WriteResponse", err)
 	}
 }
@@ -424,7 +415,15 @@ func (h *Handler) MarketGroupPositions(w http.ResponseWriter, r *http.Request) {
 		writeMarketGroupDetailsError(w, err)
 		return
 	}
-	if err := handlers.WriteResult(w, http.StatusOK, marketGroupPositionsPageToResponse(result)); err != nil {
+	response := marketGroupPositionsPageToResponse(result)
+	names := make([]*string, 0, len(response.Positions))
+	for i := range response.Positions {
+		names = append(names, &response.Positions[i].Username)
+	}
+	if !h.maskTraders(w, r, "MarketGroupPositions", names) {
+		return
+	}
+	if err := handlers.WriteResult(w, http.StatusOK, response); err != nil {
 		logger.LogError("MarketGroupPositions", "WriteResponse", err)
 	}
 }
@@ -451,22 +450,16 @@ func (h *Handler) MarketGroupLeaderboard(w http.ResponseWriter, r *http.Request)
 		writeMarketGroupDetailsError(w, err)
 		return
 	}
-	if err := handlers.WriteResult(w, http.StatusOK, marketGroupLeaderboardPageToResponse(result)); err != nil {
-		logger.LogError("MarketGroupLeaderboard", "WriteResponse", err)
+	response := marketGroupLeaderboardPageToResponse(result)
+	names := make([]*string, 0, len(response.Leaderboard))
+	for i := range response.Leaderboard {
+		names = append(names, &response.Leaderboard[i].Username)
 	}
-}
-
-func (h *Handler) invalidateCreatedMarketGroup(ctx context.Context, username string, group *dmarkets.MarketGroup) {
-	if h.invalidator == nil || group == nil {
+	if !h.maskTraders(w, r, "MarketGroupLeaderboard", names) {
 		return
 	}
-	for _, member := range group.Members {
-		if member.MarketID <= 0 {
-			continue
-		}
-		if err := h.invalidator.InvalidateAfterMarketTransaction(ctx, username, member.MarketID, "market_group_created"); err != nil {
-			logger.LogError("CreateMarketGroup", "InvalidateReadModels", err)
-		}
+	if err := handlers.WriteResult(w, http.StatusOK, response); err != nil {
+		logger.LogError("MarketGroupLeaderboard", "WriteResponse", err)
 	}
 }
 
@@ -791,6 +784,8 @@ func marketGroupAnswerAdditionToResponse(addition *dmarkets.MarketGroupAnswerAdd
 
 func writeMarketGroupDetailsError(w http.ResponseWriter, err error) {
 	switch {
+	case errors.Is(err, dmarkets.ErrAccountSuspended):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountSuspended)
 	case errors.Is(err, dmarkets.ErrMarketGroupNotFound):
 		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonMarketNotFound)
 	case errors.Is(err, dmarkets.ErrUserNotFound):
@@ -0,0 +1,33 @@
+package marketshandlers
+
+import (
+	"net/http"
+
+	privacyhandlers "socialpredict/handlers/privacy"
+)
+
+// SetTradePrivacy masks anonymous traders in market and market group
+// leaderboards, bets, and positions. Without it every name is shown.
+func (h *Handler) SetTradePrivacy(guard *privacyhandlers.Guard) {
+	h.privacy = guard
+}
+
+// maskTraders rewrites the pointed-to usernames the viewer may not see,
+// writing the failure and returning false when the lookup fails. Callers
+// pass fields of response copies, so snapshots keep real names and setting
+// changes apply immediately.
+func (h *Handler) maskTraders(w http.ResponseWriter, r *http.Request, component string, names []*string) bool {
+	usernames := make([]string, 0, len(names))
+	for _, name := range names {
+		usernames = append(usernames, *name)
+	}
+	mask, err := h.privacy.Mask(r, usernames)
+	if err != nil {
+		privacyhandlers.WriteError(w, component, err)
+		return false
+	}
+	for _, name := range names {
+		*name = mask.Name(*name)
+	}
+	return true
+}
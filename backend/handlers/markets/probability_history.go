@@ -0,0 +1,88 @@
+package marketshandlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/markets/dto"
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+type probabilityHistoryService interface {
+	GetMarketProbabilityHistory(ctx context.Context, marketID int64, interval dmarkets.CandleInterval, from, to time.Time) (*dmarkets.ProbabilityHistory, error)
+}
+
+// ProbabilityHistory handles GET /markets/{id}/history?interval=&from=&to=
+// with downsampled probability candles. Bounds are RFC3339; from defaults to
+// the market's creation and to to now.
+func (h *Handler) ProbabilityHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	marketID, err := parseMarketIDFromRequest(r)
+	if err != nil {
+		writeInvalidRequest(w)
+		return
+	}
+
+	query := r.URL.Query()
+	interval, err := dmarkets.ParseCandleInterval(query.Get("interval"))
+	if err != nil {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+		return
+	}
+	from, fromErr := parseHistoryTime(query.Get("from"))
+	to, toErr := parseHistoryTime(query.Get("to"))
+	if fromErr != nil || toErr != nil {
+		writeInvalidRequest(w)
+		return
+	}
+
+	service, ok := h.service.(probabilityHistoryService)
+	if !ok {
+		writeInternalError(w)
+		return
+	}
+	history, err := service.GetMarketProbabilityHistory(r.Context(), marketID, interval, from, to)
+	if err != nil {
+		if isRequestCanceled(err) {
+			return
+		}
+		writeDetailsError(w, err)
+		return
+	}
+
+	candles := make([]dto.ProbabilityCandleResponse, 0, len(history.Candles))
+	for _, candle := range history.Candles {
+		candles = append(candles, dto.ProbabilityCandleResponse{
+			Start:   candle.BucketStart.UTC(),
+			Open:    candle.Open,
+			High:    candle.High,
+			Low:     candle.Low,
+			Close:   candle.Close,
+			Volume:  candle.Volume,
+			Trades:  candle.Trades,
+			Traders: candle.Traders,
+		})
+	}
+	_ = handlers.WriteResult(w, http.StatusOK, dto.ProbabilityHistoryResponse{
+		MarketID: history.MarketID,
+		Interval: string(history.Interval),
+		From:     history.From.UTC(),
+		To:       history.To.UTC(),
+		Candles:  candles,
+	})
+}
+
+func parseHistoryTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
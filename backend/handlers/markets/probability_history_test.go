@@ -0,0 +1,94 @@
+package marketshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+
+	"github.com/gorilla/mux"
+)
+
+type probabilityHistoryMock struct {
+	MockService
+	interval dmarkets.CandleInterval
+	from, to time.Time
+	err      error
+}
+
+func (m *probabilityHistoryMock) GetMarketProbabilityHistory(_ context.Context, marketID int64, interval dmarkets.CandleInterval, from, to time.Time) (*dmarkets.ProbabilityHistory, error) {
+	m.interval, m.from, m.to = interval, from, to
+	if m.err != nil {
+		return nil, m.err
+	}
+	start := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	return &dmarkets.ProbabilityHistory{
+		MarketID: marketID,
+		Interval: interval,
+		From:     start,
+		To:       start.Add(24 * time.Hour),
+		Candles:  []dmarkets.ProbabilityCandle{{BucketStart: start, Open: 0.5, High: 0.7, Low: 0.5, Close: 0.6, Volume: 150, Trades: 2, Traders: 2}},
+	}, nil
+}
+
+func serveProbabilityHistory(svc Service, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	rec := httptest.NewRecorder()
+	NewHandler(svc, nil, nil).ProbabilityHistory(rec, req)
+	return rec
+}
+
+func TestProbabilityHistoryReturnsCandles(t *testing.T) {
+	svc := &probabilityHistoryMock{}
+	rec := serveProbabilityHistory(svc, "/v0/markets/7/history?interval=1D&from=2026-07-01T00:00:00Z")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if svc.interval != dmarkets.CandleInterval1d || !svc.from.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) || !svc.to.IsZero() {
+		t.Fatalf("service called with interval=%s from=%s to=%s", svc.interval, svc.from, svc.to)
+	}
+	var decoded struct {
+		Result struct {
+			MarketID int64  `json:"marketId"`
+			Interval string `json:"interval"`
+			Candles  []struct {
+				Start   string  `json:"start"`
+				Close   float64 `json:"close"`
+				Traders int64   `json:"traders"`
+			} `json:"candles"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	result := decoded.Result
+	if result.MarketID != 7 || result.Interval != "1d" || len(result.Candles) != 1 || result.Candles[0].Start != "2026-07-01T09:00:00Z" || result.Candles[0].Close != 0.6 {
+		t.Fatalf("unexpected response %+v", result)
+	}
+}
+
+func TestProbabilityHistoryRejectsBadQueries(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		err    error
+		want   int
+	}{
+		{name: "interval", target: "/v0/markets/7/history?interval=2h", want: http.StatusBadRequest},
+		{name: "bound", target: "/v0/markets/7/history?to=tomorrow", want: http.StatusBadRequest},
+		{name: "range", target: "/v0/markets/7/history", err: dmarkets.ErrInvalidInput, want: http.StatusBadRequest},
+		{name: "missing market", target: "/v0/markets/7/history", err: dmarkets.ErrMarketNotFound, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serveProbabilityHistory(&probabilityHistoryMock{err: tt.err}, tt.target); rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
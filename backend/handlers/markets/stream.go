@@ -0,0 +1,165 @@
+package marketshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	dlivestream "socialpredict/internal/domain/livestream"
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+const liveStreamRetryMillis = 3000
+
+type liveStreamer interface {
+	Open(ctx context.Context, topic dlivestream.Topic, clientID string, lastEventID int64) (dlivestream.Stream, error)
+	Heartbeat() time.Duration
+}
+
+// MarketStreamHandler handles GET /v0/markets/{id}/stream, a server-sent
+// event stream of trades, probability points, and status changes.
+func MarketStreamHandler(streams liveStreamer, clientID func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		marketID, err := parseMarketIDFromRequest(r)
+		if err != nil || marketID <= 0 {
+			writeInvalidRequest(w)
+			return
+		}
+		serveLiveStream(w, r, streams, dlivestream.MarketTopic(marketID), clientID)
+	}
+}
+
+// MarketGroupStreamHandler handles GET /v0/market-groups/{id}/stream, which
+// follows the group and every answer market inside it.
+func MarketGroupStreamHandler(streams liveStreamer, clientID func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		groupID, err := parseMarketGroupIDFromRequest(r)
+		if err != nil {
+			writeInvalidRequest(w)
+			return
+		}
+		serveLiveStream(w, r, streams, dlivestream.GroupTopic(groupID), clientID)
+	}
+}
+
+func serveLiveStream(w http.ResponseWriter, r *http.Request, streams liveStreamer, topic dlivestream.Topic, clientID func(*http.Request) string) {
+	if streams == nil {
+		writeInternalError(w)
+		return
+	}
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		writeInvalidRequest(w)
+		return
+	}
+	client := ""
+	if clientID != nil {
+		client = clientID(r)
+	}
+
+	stream, err := streams.Open(r.Context(), topic, client, lastEventID)
+	if err != nil {
+		writeLiveStreamError(w, err)
+		return
+	}
+	defer stream.Close()
+
+	controller := http.NewResponseController(w)
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", liveStreamRetryMillis); err != nil {
+		return
+	}
+	for _, frame := range stream.Replay() {
+		if err := writeLiveStreamFrame(w, frame); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streams.Heartbeat())
+	defer heartbeat.Stop()
+	replayedThrough := stream.ReplayedThrough()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case frame, ok := <-stream.Messages():
+			if !ok {
+				return
+			}
+			if frame.ID > 0 && frame.ID <= replayedThrough {
+				continue
+			}
+			if err := writeLiveStreamFrame(w, frame); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseLastEventID reads the resume cursor from the Last-Event-ID header
+// browsers send on reconnect, or from lastEventId for clients that cannot
+// set headers.
+func parseLastEventID(r *http.Request) (int64, error) {
+	raw := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(r.URL.Query().Get("lastEventId"))
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("invalid last event id")
+	}
+	return id, nil
+}
+
+func writeLiveStreamFrame(w http.ResponseWriter, frame dlivestream.Message) error {
+	data, err := json.Marshal(frame.Data)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if frame.ID > 0 {
+		fmt.Fprintf(&b, "id: %d\n", frame.ID)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", frame.Event, data)
+	_, err = fmt.Fprint(w, b.String())
+	return err
+}
+
+func writeLiveStreamError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dlivestream.ErrTooManyStreams):
+		_ = handlers.WriteFailure(w, http.StatusTooManyRequests, handlers.ReasonRateLimited)
+	case errors.Is(err, dlivestream.ErrClosed):
+		_ = handlers.WriteFailure(w, http.StatusServiceUnavailable, handlers.ReasonInvalidState)
+	case errors.Is(err, dmarkets.ErrMarketNotFound), errors.Is(err, dmarkets.ErrMarketGroupNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonMarketNotFound)
+	case errors.Is(err, dmarkets.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	default:
+		writeInternalError(w)
+	}
+}
@@ -0,0 +1,123 @@
+package marketshandlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	dlivestream "socialpredict/internal/domain/livestream"
+	"socialpredict/security"
+)
+
+type fakeLiveStream struct {
+	replay   []dlivestream.Message
+	through  int64
+	messages chan dlivestream.Message
+}
+
+func (s *fakeLiveStream) Replay() []dlivestream.Message        { return s.replay }
+func (s *fakeLiveStream) ReplayedThrough() int64               { return s.through }
+func (s *fakeLiveStream) Messages() <-chan dlivestream.Message { return s.messages }
+func (s *fakeLiveStream) Close()                               {}
+
+type fakeLiveStreamer struct {
+	stream      *fakeLiveStream
+	err         error
+	topic       dlivestream.Topic
+	lastEventID int64
+	clientID    string
+}
+
+func (f *fakeLiveStreamer) Open(_ context.Context, topic dlivestream.Topic, clientID string, lastEventID int64) (dlivestream.Stream, error) {
+	f.topic, f.clientID, f.lastEventID = topic, clientID, lastEventID
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.stream, nil
+}
+
+func (f *fakeLiveStreamer) Heartbeat() time.Duration { return time.Hour }
+
+func TestMarketStreamHandlerStreamsThroughRequestBoundary(t *testing.T) {
+	stream := &fakeLiveStream{
+		replay:   []dlivestream.Message{dlivestream.ProbabilityFrame(5, 7, 0.42, time.Date(2026, 6, 29, 9, 0, 0, 0, time.UTC))},
+		through:  5,
+		messages: make(chan dlivestream.Message, 2),
+	}
+	streamer := &fakeLiveStreamer{stream: stream}
+	router := mux.NewRouter()
+	router.Handle("/v0/markets/{id}/stream", MarketStreamHandler(streamer, func(*http.Request) string { return "client-1" }))
+	server := httptest.NewServer(security.RequestBoundaryMiddleware()(router))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v0/markets/7/stream", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status=%d content-type=%q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if streamer.topic != dlivestream.MarketTopic(7) || streamer.lastEventID != 4 || streamer.clientID != "client-1" {
+		t.Fatalf("unexpected Open args: %+v", streamer)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	snapshot := readSSEFrame(t, reader)
+	if !strings.Contains(snapshot, "id: 5\nevent: probability\n") || !strings.Contains(snapshot, `"probability":0.42`) {
+		t.Fatalf("unexpected snapshot frame: %q", snapshot)
+	}
+
+	stream.messages <- dlivestream.Message{ID: 5, Event: dlivestream.EventTrade, Data: map[string]any{"stale": true}}
+	stream.messages <- dlivestream.Message{ID: 6, Event: dlivestream.EventTrade, Data: map[string]any{"marketId": 7}}
+	live := readSSEFrame(t, reader)
+	if !strings.HasPrefix(live, "id: 6\nevent: trade\n") {
+		t.Fatalf("live frame = %q, want the id 6 trade with the replayed duplicate skipped", live)
+	}
+}
+
+func TestMarketStreamHandlerRejectsOverLimitAndBadCursor(t *testing.T) {
+	streamer := &fakeLiveStreamer{err: dlivestream.ErrTooManyStreams}
+	handler := MarketStreamHandler(streamer, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/markets/7/stream", nil), map[string]string{"id": "7"})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+
+	bad := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/markets/7/stream?lastEventId=abc", nil), map[string]string{"id": "7"})
+	badRec := httptest.NewRecorder()
+	handler.ServeHTTP(badRec, bad)
+	if badRec.Code != http.StatusBadRequest {
+		t.Fatalf("bad cursor status = %d, want 400", badRec.Code)
+	}
+}
+
+func readSSEFrame(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	var frame strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v (partial %q)", err, frame.String())
+		}
+		if line == "\n" {
+			if frame.Len() == 0 || strings.HasPrefix(frame.String(), "retry:") {
+				frame.Reset()
+				continue
+			}
+			return frame.String()
+		}
+		frame.WriteString(line)
+	}
+}
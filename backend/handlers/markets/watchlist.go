@@ -0,0 +1,228 @@
+package marketshandlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	"socialpredict/handlers/markets/dto"
+	dwatchlists "socialpredict/internal/domain/watchlists"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/logger"
+)
+
+type followerCounter interface {
+	FollowerCount(ctx context.Context, target dwatchlists.Target) (int64, error)
+}
+
+type watchlistService interface {
+	followerCounter
+	Follow(ctx context.Context, username string, target dwatchlists.Target) (*dwatchlists.Follow, bool, error)
+	Unfollow(ctx context.Context, username string, target dwatchlists.Target) error
+	Watchlist(ctx context.Context, username string) (*dwatchlists.Watchlist, error)
+}
+
+type followResponse struct {
+	TargetType    string `json:"targetType"`
+	TargetID      string `json:"targetId"`
+	Following     bool   `json:"following"`
+	FollowerCount int64  `json:"followerCount"`
+	FollowedAt    string `json:"followedAt,omitempty"`
+}
+
+type watchlistFollowResponse struct {
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetId"`
+	FollowedAt string `json:"followedAt"`
+}
+
+type watchedTagResponse struct {
+	Tag     dto.MarketTagResponse         `json:"tag"`
+	Markets []*dto.MarketOverviewResponse `json:"markets"`
+}
+
+type watchlistResponse struct {
+	Follows []watchlistFollowResponse     `json:"follows"`
+	Markets []*dto.MarketOverviewResponse `json:"markets"`
+	Tags    []watchedTagResponse          `json:"tags"`
+}
+
+// SetFollowerCounter adds follower counts to market and market group
+// details.
+func (h *Handler) SetFollowerCounter(counter followerCounter) {
+	h.followers = counter
+}
+
+// followerCount is decoration on the details pages, so a failed count is
+// logged and shown as zero rather than failing the page.
+func (h *Handler) followerCount(ctx context.Context, target dwatchlists.Target) int64 {
+	if h.followers == nil {
+		return 0
+	}
+	count, err := h.followers.FollowerCount(ctx, target)
+	if err != nil {
+		logger.LogError("Watchlists", "FollowerCount", err)
+		return 0
+	}
+	return count
+}
+
+// FollowHandler handles POST on a market, market group, or tag follow path.
+// The target ID comes from the {id} route variable, or {slug} for tags.
+func FollowHandler(svc watchlistService, auth authsvc.Authenticator, targetType dwatchlists.TargetType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, target, ok := followRequest(w, r, svc, auth, targetType)
+		if !ok {
+			return
+		}
+		follow, created, err := svc.Follow(r.Context(), username, target)
+		if err != nil {
+			writeWatchlistError(w, err)
+			return
+		}
+		count, err := svc.FollowerCount(r.Context(), follow.Target)
+		if err != nil {
+			writeWatchlistError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		_ = handlers.WriteResult(w, status, followResponse{
+			TargetType:    string(follow.Target.Type),
+			TargetID:      follow.Target.ID,
+			Following:     true,
+			FollowerCount: count,
+			FollowedAt:    follow.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// UnfollowHandler handles DELETE on a market, market group, or tag follow
+// path.
+func UnfollowHandler(svc watchlistService, auth authsvc.Authenticator, targetType dwatchlists.TargetType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, target, ok := followRequest(w, r, svc, auth, targetType)
+		if !ok {
+			return
+		}
+		target, err := dwatchlists.Normalize(target)
+		if err != nil {
+			writeWatchlistError(w, err)
+			return
+		}
+		if err := svc.Unfollow(r.Context(), username, target); err != nil {
+			writeWatchlistError(w, err)
+			return
+		}
+		count, err := svc.FollowerCount(r.Context(), target)
+		if err != nil {
+			writeWatchlistError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, followResponse{
+			TargetType:    string(target.Type),
+			TargetID:      target.ID,
+			Following:     false,
+			FollowerCount: count,
+		})
+	}
+}
+
+// WatchlistHandler handles GET /v0/profile/watchlist. Followed markets and
+// market groups come back as the same live overviews the market lists use,
+// and each followed tag with its active markets.
+func WatchlistHandler(svc watchlistService, markets marketOverviewProvider, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if svc == nil || markets == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		user, authErr := auth.CurrentUser(r)
+		if authErr != nil {
+			_ = authhttp.WriteFailure(w, authErr)
+			return
+		}
+
+		watchlist, err := svc.Watchlist(r.Context(), user.Username)
+		if err != nil {
+			writeWatchlistError(w, err)
+			return
+		}
+		overviews, err := buildMarketDiscoveryOverviewResponses(r.Context(), markets, watchlist.Rows)
+		if err != nil {
+			logger.LogError("Watchlists", "BuildMarketOverviewResponses", err)
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		response := watchlistResponse{
+			Follows: make([]watchlistFollowResponse, 0, len(watchlist.Follows)),
+			Markets: overviews,
+			Tags:    make([]watchedTagResponse, 0, len(watchlist.Tags)),
+		}
+		for _, follow := range watchlist.Follows {
+			response.Follows = append(response.Follows, watchlistFollowResponse{
+				TargetType: string(follow.Target.Type),
+				TargetID:   follow.Target.ID,
+				FollowedAt: follow.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		for _, watch := range watchlist.Tags {
+			tagged, err := buildMarketDiscoveryOverviewResponses(r.Context(), markets, watch.Rows)
+			if err != nil {
+				logger.LogError("Watchlists", "BuildMarketOverviewResponses", err)
+				_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+				return
+			}
+			response.Tags = append(response.Tags, watchedTagResponse{Tag: marketTagResponseFromDomain(watch.Tag), Markets: tagged})
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+func followRequest(w http.ResponseWriter, r *http.Request, svc watchlistService, auth authsvc.Authenticator, targetType dwatchlists.TargetType) (string, dwatchlists.Target, bool) {
+	if svc == nil || auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return "", dwatchlists.Target{}, false
+	}
+	user, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return "", dwatchlists.Target{}, false
+	}
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if targetType == dwatchlists.TargetTag {
+		id = vars["slug"]
+	}
+	if strings.TrimSpace(id) == "" {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return "", dwatchlists.Target{}, false
+	}
+	return user.Username, dwatchlists.Target{Type: targetType, ID: id}, true
+}
+
+func writeWatchlistError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dwatchlists.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+	case errors.Is(err, dwatchlists.ErrTargetNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, dwatchlists.ErrWatchlistFull):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	default:
+		logger.LogError("Watchlists", "Watchlist", err)
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
@@ -0,0 +1,133 @@
+package marketshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	dusers "socialpredict/internal/domain/users"
+	dwatchlists "socialpredict/internal/domain/watchlists"
+
+	"github.com/gorilla/mux"
+)
+
+type watchlistServiceMock struct {
+	target    dwatchlists.Target
+	created   bool
+	err       error
+	watchlist *dwatchlists.Watchlist
+}
+
+func (m *watchlistServiceMock) Follow(_ context.Context, username string, target dwatchlists.Target) (*dwatchlists.Follow, bool, error) {
+	m.target = target
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	return &dwatchlists.Follow{ID: 1, Username: username, Target: dwatchlists.Target{Type: target.Type, ID: "7"}, CreatedAt: time.Date(2026, 7, 3, 9, 0, 0, 0, time.UTC)}, m.created, nil
+}
+
+func (m *watchlistServiceMock) Unfollow(_ context.Context, _ string, target dwatchlists.Target) error {
+	m.target = target
+	return m.err
+}
+
+func (m *watchlistServiceMock) Watchlist(context.Context, string) (*dwatchlists.Watchlist, error) {
+	return m.watchlist, m.err
+}
+
+func (m *watchlistServiceMock) FollowerCount(context.Context, dwatchlists.Target) (int64, error) {
+	return 3, nil
+}
+
+func serveWatchlist(handler http.Handler, method, pattern, target string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.Handle(pattern, handler).Methods(method)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestFollowHandlersReportFollowerCounts(t *testing.T) {
+	svc := &watchlistServiceMock{created: true}
+	auth := lifecycleAuthMock{user: &dusers.User{Username: "alice"}}
+
+	rec := serveWatchlist(FollowHandler(svc, auth, dwatchlists.TargetMarket), http.MethodPost, "/v0/markets/{id}/follow", "/v0/markets/7/follow")
+	if rec.Code != http.StatusCreated || svc.target != (dwatchlists.Target{Type: dwatchlists.TargetMarket, ID: "7"}) {
+		t.Fatalf("status = %d target=%+v body=%s", rec.Code, svc.target, rec.Body.String())
+	}
+	var body struct {
+		Result followResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || !body.Result.Following || body.Result.FollowerCount != 3 {
+		t.Fatalf("unexpected response %+v, %v", body.Result, err)
+	}
+
+	rec = serveWatchlist(UnfollowHandler(svc, auth, dwatchlists.TargetTag), http.MethodDelete, "/v0/market-tags/{slug}/follow", "/v0/market-tags/Politics/follow")
+	if rec.Code != http.StatusOK || svc.target != dwatchlists.TagTarget("politics") {
+		t.Fatalf("status = %d target=%+v", rec.Code, svc.target)
+	}
+
+	svc.err = dwatchlists.ErrTargetNotFound
+	if rec := serveWatchlist(FollowHandler(svc, auth, dwatchlists.TargetMarketGroup), http.MethodPost, "/v0/market-groups/{id}/follow", "/v0/market-groups/9/follow"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing group, got %d", rec.Code)
+	}
+	if rec := serveWatchlist(UnfollowHandler(svc, auth, dwatchlists.TargetMarket), http.MethodDelete, "/v0/markets/{id}/follow", "/v0/markets/abc/follow"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad ID, got %d", rec.Code)
+	}
+}
+
+func TestWatchlistHandlerBuildsOverviews(t *testing.T) {
+	group := &dmarkets.MarketGroup{ID: 2, QuestionTitle: "Who wins?", LifecycleStatus: dmarkets.MarketLifecyclePublished, Members: []dmarkets.MarketGroupMember{{MarketID: 21, AnswerLabel: "Red"}}}
+	svc := &watchlistServiceMock{watchlist: &dwatchlists.Watchlist{
+		Follows: []*dwatchlists.Follow{{Target: dwatchlists.MarketGroupTarget(2)}, {Target: dwatchlists.MarketTarget(7)}, {Target: dwatchlists.TagTarget("politics")}},
+		Rows: []dmarkets.MarketDiscoveryRow{
+			{Group: group, Market: &dmarkets.Market{ID: 21}, Children: []*dmarkets.Market{{ID: 21}}},
+			{Market: &dmarkets.Market{ID: 7}},
+		},
+		Tags: []dwatchlists.TagWatch{{Tag: dmarkets.MarketTag{Slug: "politics", DisplayName: "Politics"}, Rows: []dmarkets.MarketDiscoveryRow{{Market: &dmarkets.Market{ID: 30}}}}},
+	}}
+	markets := &MockService{}
+	auth := lifecycleAuthMock{user: &dusers.User{Username: "alice"}}
+
+	rec := httptest.NewRecorder()
+	WatchlistHandler(svc, markets, auth).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/profile/watchlist", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Result watchlistResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	result := body.Result
+	if len(result.Follows) != 3 || len(result.Markets) != 2 || len(result.Tags) != 1 {
+		t.Fatalf("unexpected watchlist %+v", result)
+	}
+	if !result.Markets[0].Market.IsMarketGroupAggregate || result.Markets[0].Market.QuestionTitle != "Who wins?" || result.Markets[1].Market.ID != 7 {
+		t.Fatalf("unexpected overviews %+v %+v", result.Markets[0].Market, result.Markets[1].Market)
+	}
+	if result.Tags[0].Tag.Slug != "politics" || len(result.Tags[0].Markets) != 1 || result.Tags[0].Markets[0].Market.ID != 30 {
+		t.Fatalf("unexpected tag watch %+v", result.Tags[0])
+	}
+}
+
+func TestGetDetailsIncludesFollowerCount(t *testing.T) {
+	handler := NewHandler(&MockService{}, nil, nil)
+	handler.SetFollowerCounter(&watchlistServiceMock{})
+
+	rec := serveWatchlist(http.HandlerFunc(handler.GetDetails), http.MethodGet, "/v0/markets/{id}", "/v0/markets/1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		FollowerCount int64 `json:"followerCount"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.FollowerCount != 3 {
+		t.Fatalf("followerCount = %d, %v", body.FollowerCount, err)
+	}
+}
@@ -0,0 +1,122 @@
+package metricshandlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	analytics "socialpredict/internal/domain/analytics"
+	"socialpredict/internal/domain/readmodels"
+)
+
+type UserCalibrationResponse struct {
+	Username        string                        `json:"username"`
+	ResolvedMarkets int                           `json:"resolvedMarkets"`
+	BrierScore      *float64                      `json:"brierScore,omitempty"`
+	LogScore        *float64                      `json:"logScore,omitempty"`
+	Buckets         []analytics.CalibrationBucket `json:"buckets"`
+	Freshness       *FreshnessResponse            `json:"freshness,omitempty"`
+}
+
+type SkillLeaderboardResponse struct {
+	Entries        []analytics.SkillLeaderboardEntry `json:"entries"`
+	MinimumMarkets int                               `json:"minimumMarkets"`
+	Freshness      *FreshnessResponse                `json:"freshness,omitempty"`
+}
+
+// GetUserCalibrationHandler handles GET /v0/users/{username}/calibration.
+// Users without scored forecasts get zero markets and no scores.
+func GetUserCalibrationHandler(svc CalibrationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := strings.TrimSpace(mux.Vars(r)["username"])
+		if username == "" {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		snapshot, freshness, err := calibrationReadModel(r.Context(), svc)
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		response := UserCalibrationResponse{Username: username, Buckets: []analytics.CalibrationBucket{}}
+		if user := snapshot.User(username); user != nil {
+			response.ResolvedMarkets = user.ResolvedMarkets
+			response.BrierScore = &user.BrierScore
+			response.LogScore = &user.LogScore
+			response.Buckets = user.Buckets
+		}
+		if freshness != nil {
+			converted := freshnessResponseFromDomain(*freshness)
+			response.Freshness = &converted
+		}
+		if err := handlers.WriteResult(w, http.StatusOK, response); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		}
+	}
+}
+
+// GetSkillLeaderboardHandler handles GET /v0/global/leaderboard/skill: users
+// ranked by Brier score instead of profit.
+func GetSkillLeaderboardHandler(svc CalibrationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset := parseLeaderboardPage(r)
+		snapshot, freshness, err := calibrationReadModel(r.Context(), svc)
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		response := SkillLeaderboardResponse{
+			Entries:        snapshot.SkillLeaderboard(limit, offset),
+			MinimumMarkets: analytics.MinimumSkillLeaderboardMarkets,
+		}
+		if freshness != nil {
+			converted := freshnessResponseFromDomain(*freshness)
+			response.Freshness = &converted
+		}
+		if err := handlers.WriteResult(w, http.StatusOK, response); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		}
+	}
+}
+
+// calibrationReadModel serves the stored snapshot, recomputing it when it is
+// missing or when it is stale and older than its target freshness. Scoring
+// replays every resolved market, so a recently stale snapshot is served as is
+// with its freshness saying so.
+func calibrationReadModel(ctx context.Context, svc CalibrationService) (*analytics.CalibrationSnapshot, *readmodels.Freshness, error) {
+	readSvc, ok := svc.(CalibrationReadModelService)
+	if !ok {
+		snapshot, err := svc.ComputeCalibrationSnapshot(ctx)
+		return snapshot, nil, err
+	}
+
+	readModel, err := readSvc.GetCalibrationReadModel(ctx)
+	if err == nil && readModel != nil && !calibrationDue(readModel.Freshness) {
+		return &readModel.Snapshot, &readModel.Freshness, nil
+	}
+
+	refreshed, refreshErr := readSvc.RefreshCalibrationSnapshot(ctx)
+	if refreshErr == nil && refreshed != nil {
+		return &refreshed.Snapshot, &refreshed.Freshness, nil
+	}
+	if readModel != nil {
+		return &readModel.Snapshot, &readModel.Freshness, nil
+	}
+
+	snapshot, computeErr := svc.ComputeCalibrationSnapshot(ctx)
+	if computeErr != nil {
+		return nil, nil, computeErr
+	}
+	return snapshot, nil, nil
+}
+
+func calibrationDue(freshness readmodels.Freshness) bool {
+	target := time.Duration(freshness.TargetFreshnessSeconds) * time.Second
+	return freshness.IsStale && time.Since(freshness.GeneratedAt) > target
+}
@@ -0,0 +1,139 @@
+package metricshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	analytics "socialpredict/internal/domain/analytics"
+	"socialpredict/internal/domain/readmodels"
+)
+
+type cachedCalibrationService struct {
+	readModel    *analytics.CalibrationReadModel
+	refreshCalls int
+}
+
+func (s *cachedCalibrationService) ComputeCalibrationSnapshot(context.Context) (*analytics.CalibrationSnapshot, error) {
+	return &analytics.CalibrationSnapshot{}, nil
+}
+
+func (s *cachedCalibrationService) GetCalibrationReadModel(context.Context) (*analytics.CalibrationReadModel, error) {
+	return s.readModel, nil
+}
+
+func (s *cachedCalibrationService) RefreshCalibrationSnapshot(context.Context) (*analytics.CalibrationReadModel, error) {
+	s.refreshCalls++
+	return &analytics.CalibrationReadModel{
+		Snapshot:  calibrationFixture("refreshed"),
+		Freshness: readmodels.NewFreshness(time.Now().UTC(), "read_model", analytics.CalibrationSnapshotTargetFreshness, false),
+	}, nil
+}
+
+func calibrationFixture(username string) analytics.CalibrationSnapshot {
+	return analytics.CalibrationSnapshot{Users: []analytics.UserCalibration{{
+		Username:        username,
+		ResolvedMarkets: analytics.MinimumSkillLeaderboardMarkets,
+		BrierScore:      0.1,
+		LogScore:        -0.3,
+		Buckets:         []analytics.CalibrationBucket{{Lower: 0.7, Upper: 0.8, Forecasts: 5, MeanForecast: 0.75, RealizedFrequency: 0.8}},
+	}}}
+}
+
+func staleCalibrationReadModel(age time.Duration) *analytics.CalibrationReadModel {
+	return &analytics.CalibrationReadModel{
+		Snapshot: calibrationFixture("cached"),
+		Freshness: readmodels.NewStaleFreshness(
+			time.Now().UTC().Add(-age),
+			"read_model",
+			analytics.CalibrationSnapshotTargetFreshness,
+			false,
+			"market_resolved",
+			nil,
+		),
+	}
+}
+
+func TestCalibrationReadModel_RefreshesOnlyOldStaleSnapshots(t *testing.T) {
+	recent := &cachedCalibrationService{readModel: staleCalibrationReadModel(time.Minute)}
+	snapshot, freshness, err := calibrationReadModel(context.Background(), recent)
+	if err != nil {
+		t.Fatalf("calibrationReadModel returned error: %v", err)
+	}
+	if snapshot.User("cached") == nil || freshness == nil || !freshness.IsStale || recent.refreshCalls != 0 {
+		t.Fatalf("expected recent stale snapshot to be served, got %+v %+v (%d refreshes)", snapshot, freshness, recent.refreshCalls)
+	}
+
+	old := &cachedCalibrationService{readModel: staleCalibrationReadModel(2 * analytics.CalibrationSnapshotTargetFreshness)}
+	snapshot, _, err = calibrationReadModel(context.Background(), old)
+	if err != nil {
+		t.Fatalf("calibrationReadModel returned error: %v", err)
+	}
+	if snapshot.User("refreshed") == nil || old.refreshCalls != 1 {
+		t.Fatalf("expected old stale snapshot to be refreshed, got %+v (%d refreshes)", snapshot, old.refreshCalls)
+	}
+}
+
+func TestGetUserCalibrationHandler(t *testing.T) {
+	svc := &cachedCalibrationService{readModel: staleCalibrationReadModel(time.Minute)}
+	tests := []struct {
+		username    string
+		wantMarkets int
+		wantScores  bool
+	}{
+		{username: "cached", wantMarkets: analytics.MinimumSkillLeaderboardMarkets, wantScores: true},
+		{username: "newcomer", wantMarkets: 0, wantScores: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/users/"+tt.username+"/calibration", nil), map[string]string{"username": tt.username})
+			rec := httptest.NewRecorder()
+			GetUserCalibrationHandler(svc).ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+
+			var payload struct {
+				OK     bool                    `json:"ok"`
+				Result UserCalibrationResponse `json:"result"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.Result.ResolvedMarkets != tt.wantMarkets || (payload.Result.BrierScore != nil) != tt.wantScores {
+				t.Fatalf("unexpected calibration: %+v", payload.Result)
+			}
+			if payload.Result.Buckets == nil || payload.Result.Freshness == nil {
+				t.Fatalf("expected buckets and freshness, got %+v", payload.Result)
+			}
+		})
+	}
+}
+
+func TestGetSkillLeaderboardHandler(t *testing.T) {
+	svc := &cachedCalibrationService{readModel: staleCalibrationReadModel(time.Minute)}
+	rec := httptest.NewRecorder()
+	GetSkillLeaderboardHandler(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/global/leaderboard/skill?limit=5", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	var payload struct {
+		OK     bool                     `json:"ok"`
+		Result SkillLeaderboardResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Result.Entries) != 1 || payload.Result.Entries[0].Username != "cached" || payload.Result.Entries[0].Rank != 1 {
+		t.Fatalf("unexpected entries: %+v", payload.Result.Entries)
+	}
+	if payload.Result.MinimumMarkets != analytics.MinimumSkillLeaderboardMarkets {
+		t.Fatalf("minimumMarkets = %d", payload.Result.MinimumMarkets)
+	}
+}
@@ -2,8 +2,12 @@ package metricshandlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
 
 	"socialpredict/handlers"
 	analytics "socialpredict/internal/domain/analytics"
@@ -12,27 +16,66 @@ import (
 
 type GlobalLeaderboardResponse struct {
 	Entries   []analytics.GlobalUserProfitability `json:"entries"`
+	Window    string                              `json:"window,omitempty"`
+	Tag       string                              `json:"tag,omitempty"`
 	Freshness *FreshnessResponse                  `json:"freshness,omitempty"`
 }
 
 // GetGlobalLeaderboardHandler returns an application reporting handler for the global leaderboard.
+// The optional window and tag query parameters narrow it to recent profit or
+// to tagged markets.
 func GetGlobalLeaderboardHandler(svc GlobalLeaderboardService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		limit, offset := parseLeaderboardPage(r)
-		entries, freshness, err := globalLeaderboardReadModel(r.Context(), svc, limit, offset)
-		if err != nil {
-			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		query := r.URL.Query()
+		writeScopedLeaderboard(w, r, svc, query.Get("window"), query.Get("tag"))
+	}
+}
+
+// GetTagLeaderboardHandler handles GET /v0/market-tags/{slug}/leaderboard.
+func GetTagLeaderboardHandler(svc GlobalLeaderboardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slug := mux.Vars(r)["slug"]
+		if slug == "" {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
 			return
 		}
+		writeScopedLeaderboard(w, r, svc, r.URL.Query().Get("window"), slug)
+	}
+}
 
-		response := GlobalLeaderboardResponse{Entries: entries}
-		if freshness != nil {
-			converted := freshnessResponseFromDomain(*freshness)
-			response.Freshness = &converted
-		}
-		if err := handlers.WriteResult(w, http.StatusOK, response); err != nil {
-			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
-		}
+func writeScopedLeaderboard(w http.ResponseWriter, r *http.Request, svc GlobalLeaderboardService, window, tag string) {
+	scope, err := analytics.NewLeaderboardScope(window, tag)
+	if err != nil {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return
+	}
+	limit, offset := parseLeaderboardPage(r)
+
+	var entries []analytics.GlobalUserProfitability
+	var freshness *readmodels.Freshness
+	response := GlobalLeaderboardResponse{}
+	if scope.IsGlobal() {
+		entries, freshness, err = globalLeaderboardReadModel(r.Context(), svc, limit, offset)
+	} else {
+		response.Window, response.Tag = string(scope.Window), scope.TagSlug
+		entries, freshness, err = scopedLeaderboardReadModel(r.Context(), svc, scope, limit, offset)
+	}
+	if errors.Is(err, analytics.ErrLeaderboardTagNotFound) {
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+		return
+	}
+	if err != nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return
+	}
+
+	response.Entries = entries
+	if freshness != nil {
+		converted := freshnessResponseFromDomain(*freshness)
+		response.Freshness = &converted
+	}
+	if err := handlers.WriteResult(w, http.StatusOK, response); err != nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
 	}
 }
 
@@ -67,6 +110,50 @@ func globalLeaderboardReadModel(ctx context.Context, svc GlobalLeaderboardServic
 	return snapshot.ResultPage(limit, offset), nil, nil
 }
 
+// scopedLeaderboardReadModel serves a windowed or tag leaderboard snapshot.
+// Unlike the all-time board, a windowed snapshot drifts as its window slides,
+// so any scoped snapshot older than its target freshness is recomputed once it
+// is stale or windowed.
+func scopedLeaderboardReadModel(ctx context.Context, svc GlobalLeaderboardService, scope analytics.LeaderboardScope, limit int, offset int) ([]analytics.GlobalUserProfitability, *readmodels.Freshness, error) {
+	scopedSvc, ok := svc.(ScopedLeaderboardService)
+	if !ok {
+		return nil, nil, errors.New("scoped leaderboards not supported")
+	}
+	readSvc, ok := svc.(ScopedLeaderboardReadModelService)
+	if !ok {
+		snapshot, err := scopedSvc.ComputeScopedLeaderboardSnapshot(ctx, scope)
+		if err != nil {
+			return nil, nil, err
+		}
+		return snapshot.ResultPage(limit, offset), nil, nil
+	}
+
+	readModel, err := readSvc.GetScopedLeaderboardReadModel(ctx, scope, limit, offset)
+	if err == nil && readModel != nil && !scopedLeaderboardDue(scope, readModel.Freshness) {
+		return readModel.Entries, &readModel.Freshness, nil
+	}
+
+	refreshed, refreshErr := readSvc.RefreshScopedLeaderboardSnapshot(ctx, scope)
+	if refreshErr == nil && refreshed != nil {
+		paged := (&analytics.GlobalLeaderboardSnapshot{Entries: refreshed.Entries}).ResultPage(limit, offset)
+		return paged, &refreshed.Freshness, nil
+	}
+	if readModel != nil {
+		return readModel.Entries, &readModel.Freshness, nil
+	}
+
+	snapshot, computeErr := scopedSvc.ComputeScopedLeaderboardSnapshot(ctx, scope)
+	if computeErr != nil {
+		return nil, nil, computeErr
+	}
+	return snapshot.ResultPage(limit, offset), nil, nil
+}
+
+func scopedLeaderboardDue(scope analytics.LeaderboardScope, freshness readmodels.Freshness) bool {
+	target := time.Duration(freshness.TargetFreshnessSeconds) * time.Second
+	return (freshness.IsStale || scope.Windowed()) && time.Since(freshness.GeneratedAt) > target
+}
+
 func parseLeaderboardPage(r *http.Request) (int, int) {
 	query := r.URL.Query()
 	limit := 20
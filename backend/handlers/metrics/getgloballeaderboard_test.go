@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
+
 	"socialpredict/handlers"
 	analytics "socialpredict/internal/domain/analytics"
 	"socialpredict/internal/domain/boundary"
@@ -290,3 +292,69 @@ func TestGetGlobalLeaderboardHandler_PaginatesResult(t *testing.T) {
 		t.Fatalf("stub compute fallback should not include freshness, got %+v", payload.Result.Freshness)
 	}
 }
+
+type scopedLeaderboardServiceStub struct {
+	globalLeaderboardServiceStub
+	scopes []analytics.LeaderboardScope
+}
+
+func (s *scopedLeaderboardServiceStub) ComputeScopedLeaderboardSnapshot(_ context.Context, scope analytics.LeaderboardScope) (*analytics.GlobalLeaderboardSnapshot, error) {
+	s.scopes = append(s.scopes, scope)
+	if scope.TagSlug == "missing" {
+		return nil, analytics.ErrLeaderboardTagNotFound
+	}
+	return s.snapshot, nil
+}
+
+func TestLeaderboardHandlers_Scopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(GlobalLeaderboardService) http.HandlerFunc
+		target     string
+		slug       string
+		wantStatus int
+		wantScope  analytics.LeaderboardScope
+	}{
+		{name: "global window", handler: GetGlobalLeaderboardHandler, target: "/v0/global/leaderboard?window=30d", wantStatus: http.StatusOK, wantScope: analytics.LeaderboardScope{Window: "30d"}},
+		{name: "global tag", handler: GetGlobalLeaderboardHandler, target: "/v0/global/leaderboard?tag=Sports", wantStatus: http.StatusOK, wantScope: analytics.LeaderboardScope{Window: "all", TagSlug: "sports"}},
+		{name: "tag route", handler: GetTagLeaderboardHandler, target: "/v0/market-tags/sports/leaderboard?window=month", slug: "sports", wantStatus: http.StatusOK, wantScope: analytics.LeaderboardScope{Window: "month", TagSlug: "sports"}},
+		{name: "unknown window", handler: GetGlobalLeaderboardHandler, target: "/v0/global/leaderboard?window=1y", wantStatus: http.StatusBadRequest},
+		{name: "unknown tag", handler: GetTagLeaderboardHandler, target: "/v0/market-tags/missing/leaderboard", slug: "missing", wantStatus: http.StatusNotFound, wantScope: analytics.LeaderboardScope{Window: "all", TagSlug: "missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &scopedLeaderboardServiceStub{globalLeaderboardServiceStub: globalLeaderboardServiceStub{
+				snapshot: &analytics.GlobalLeaderboardSnapshot{Entries: []analytics.GlobalUserProfitability{{Username: "alice", Rank: 1}}},
+			}}
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.slug != "" {
+				req = mux.SetURLVars(req, map[string]string{"slug": tt.slug})
+			}
+			rec := httptest.NewRecorder()
+			tt.handler(svc).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				if len(svc.scopes) != 0 {
+					t.Fatalf("expected no computation for a bad scope, got %+v", svc.scopes)
+				}
+				return
+			}
+			if len(svc.scopes) != 1 || svc.scopes[0] != tt.wantScope {
+				t.Fatalf("computed scopes %+v, want %+v", svc.scopes, tt.wantScope)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var payload handlers.SuccessEnvelope[GlobalLeaderboardResponse]
+			if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("unmarshal response: %v", err)
+			}
+			if payload.Result.Window != string(tt.wantScope.Window) || payload.Result.Tag != tt.wantScope.TagSlug || len(payload.Result.Entries) != 1 {
+				t.Fatalf("unexpected response %+v", payload.Result)
+			}
+		})
+	}
+}
@@ -29,6 +29,27 @@ type GlobalLeaderboardReadModelService interface {
 	RefreshGlobalLeaderboardSnapshot(context.Context) (*analytics.GlobalLeaderboardReadModel, error)
 }
 
+// ScopedLeaderboardService defines the windowed and tag leaderboard seam.
+type ScopedLeaderboardService interface {
+	ComputeScopedLeaderboardSnapshot(context.Context, analytics.LeaderboardScope) (*analytics.GlobalLeaderboardSnapshot, error)
+}
+
+type ScopedLeaderboardReadModelService interface {
+	GetScopedLeaderboardReadModel(context.Context, analytics.LeaderboardScope, int, int) (*analytics.GlobalLeaderboardReadModel, error)
+	RefreshScopedLeaderboardSnapshot(context.Context, analytics.LeaderboardScope) (*analytics.GlobalLeaderboardReadModel, error)
+}
+
+// CalibrationService defines the forecasting skill seam behind user
+// calibration and the skill leaderboard.
+type CalibrationService interface {
+	ComputeCalibrationSnapshot(context.Context) (*analytics.CalibrationSnapshot, error)
+}
+
+type CalibrationReadModelService interface {
+	GetCalibrationReadModel(context.Context) (*analytics.CalibrationReadModel, error)
+	RefreshCalibrationSnapshot(context.Context) (*analytics.CalibrationReadModel, error)
+}
+
 type FreshnessResponse struct {
 	GeneratedAt            time.Time  `json:"generatedAt"`
 	Source                 string     `json:"source"`
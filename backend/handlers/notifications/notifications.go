@@ -0,0 +1,282 @@
+package notificationshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	dnotifications "socialpredict/internal/domain/notifications"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+const maxNotificationsPageParam = 10000
+
+type inbox interface {
+	List(ctx context.Context, username string, filters dnotifications.ListFilters) (*dnotifications.Page, error)
+	UnreadCount(ctx context.Context, username string) (int64, error)
+	MarkRead(ctx context.Context, username string, ids []int64) (int64, error)
+	MarkAllRead(ctx context.Context, username string) error
+	Preferences(ctx context.Context, username string) ([]dnotifications.Preference, error)
+	UpdatePreferences(ctx context.Context, username string, changes map[dnotifications.Type]bool) ([]dnotifications.Preference, error)
+}
+
+type notificationResponse struct {
+	ID            int64   `json:"id"`
+	Type          string  `json:"type"`
+	Title         string  `json:"title"`
+	Body          string  `json:"body"`
+	MarketID      int64   `json:"marketId,omitempty"`
+	MarketGroupID int64   `json:"marketGroupId,omitempty"`
+	Read          bool    `json:"read"`
+	ReadAt        *string `json:"readAt,omitempty"`
+	CreatedAt     string  `json:"createdAt"`
+}
+
+type notificationsResponse struct {
+	Notifications []notificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unreadCount"`
+	Total         int64                  `json:"total"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+}
+
+type unreadCountResponse struct {
+	UnreadCount int64 `json:"unreadCount"`
+}
+
+type markReadRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+type preferenceResponse struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+type preferencesResponse struct {
+	Preferences []preferenceResponse `json:"preferences"`
+}
+
+type preferenceUpdate struct {
+	Type    string `json:"type"`
+	Enabled *bool  `json:"enabled"`
+}
+
+type updatePreferencesRequest struct {
+	Preferences []preferenceUpdate `json:"preferences"`
+}
+
+// ListNotificationsHandler handles GET /v0/notifications.
+func ListNotificationsHandler(svc inbox, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		filters, ok := parseListFilters(w, r)
+		if !ok {
+			return
+		}
+		page, err := svc.List(r.Context(), user.Username, filters)
+		if err != nil {
+			writeNotificationsError(w, err)
+			return
+		}
+		response := notificationsResponse{
+			Notifications: make([]notificationResponse, 0, len(page.Notifications)),
+			UnreadCount:   page.UnreadCount,
+			Total:         page.Total,
+			Limit:         page.Limit,
+			Offset:        page.Offset,
+		}
+		for _, notification := range page.Notifications {
+			response.Notifications = append(response.Notifications, notificationResponseFromDomain(notification))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// UnreadCountHandler handles GET /v0/notifications/unread-count, the cheap
+// poll behind the unread badge.
+func UnreadCountHandler(svc inbox, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		count, err := svc.UnreadCount(r.Context(), user.Username)
+		if err != nil {
+			writeNotificationsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, unreadCountResponse{UnreadCount: count})
+	}
+}
+
+// MarkReadHandler handles POST /v0/notifications/read.
+func MarkReadHandler(svc inbox, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request markReadRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		count, err := svc.MarkRead(r.Context(), user.Username, request.IDs)
+		if err != nil {
+			writeNotificationsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, unreadCountResponse{UnreadCount: count})
+	}
+}
+
+// MarkAllReadHandler handles POST /v0/notifications/read-all.
+func MarkAllReadHandler(svc inbox, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		if err := svc.MarkAllRead(r.Context(), user.Username); err != nil {
+			writeNotificationsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, unreadCountResponse{UnreadCount: 0})
+	}
+}
+
+// GetPreferencesHandler handles GET /v0/notifications/preferences.
+func GetPreferencesHandler(svc inbox, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		preferences, err := svc.Preferences(r.Context(), user.Username)
+		if err != nil {
+			writeNotificationsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, preferencesResponseFromDomain(preferences))
+	}
+}
+
+// UpdatePreferencesHandler handles PUT /v0/notifications/preferences. Types
+// left out of the request keep their current setting.
+func UpdatePreferencesHandler(svc inbox, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request updatePreferencesRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		changes := make(map[dnotifications.Type]bool, len(request.Preferences))
+		for _, update := range request.Preferences {
+			if update.Enabled == nil {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+				return
+			}
+			changes[dnotifications.Type(strings.TrimSpace(update.Type))] = *update.Enabled
+		}
+		preferences, err := svc.UpdatePreferences(r.Context(), user.Username, changes)
+		if err != nil {
+			writeNotificationsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, preferencesResponseFromDomain(preferences))
+	}
+}
+
+func currentUser(w http.ResponseWriter, r *http.Request, svc inbox, auth authsvc.Authenticator) (*dusers.User, bool) {
+	if svc == nil || auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return nil, false
+	}
+	user, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return nil, false
+	}
+	return user, true
+}
+
+func parseListFilters(w http.ResponseWriter, r *http.Request) (dnotifications.ListFilters, bool) {
+	query := r.URL.Query()
+	var filters dnotifications.ListFilters
+	if raw := strings.TrimSpace(query.Get("unread")); raw != "" {
+		unread, err := strconv.ParseBool(raw)
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return filters, false
+		}
+		filters.UnreadOnly = unread
+	}
+	for name, target := range map[string]*int{"limit": &filters.Limit, "offset": &filters.Offset} {
+		raw := strings.TrimSpace(query.Get(name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 || value > maxNotificationsPageParam {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return filters, false
+		}
+		*target = value
+	}
+	return filters, true
+}
+
+func writeNotificationsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dnotifications.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func notificationResponseFromDomain(notification *dnotifications.Notification) notificationResponse {
+	response := notificationResponse{
+		ID:            notification.ID,
+		Type:          string(notification.Type),
+		Title:         notification.Title,
+		Body:          notification.Body,
+		MarketID:      notification.MarketID,
+		MarketGroupID: notification.MarketGroupID,
+		Read:          notification.Read(),
+		CreatedAt:     notification.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if notification.ReadAt != nil {
+		readAt := notification.ReadAt.UTC().Format(time.RFC3339)
+		response.ReadAt = &readAt
+	}
+	return response
+}
+
+func preferencesResponseFromDomain(preferences []dnotifications.Preference) preferencesResponse {
+	response := preferencesResponse{Preferences: make([]preferenceResponse, 0, len(preferences))}
+	for _, preference := range preferences {
+		response.Preferences = append(response.Preferences, preferenceResponse{
+			Type:        string(preference.Type),
+			Description: preference.Description,
+			Enabled:     preference.Enabled,
+		})
+	}
+	return response
+}
@@ -0,0 +1,138 @@
+package notificationshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dnotifications "socialpredict/internal/domain/notifications"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+type inboxMock struct {
+	listFilters dnotifications.ListFilters
+	markedIDs   []int64
+	changes     map[dnotifications.Type]bool
+}
+
+func (m *inboxMock) List(_ context.Context, username string, filters dnotifications.ListFilters) (*dnotifications.Page, error) {
+	m.listFilters = filters
+	readAt := time.Date(2026, 6, 29, 10, 0, 0, 0, time.UTC)
+	return &dnotifications.Page{
+		Notifications: []*dnotifications.Notification{
+			{ID: 2, Username: username, Type: dnotifications.TypeMarketResolved, Title: "A market you hold resolved", MarketID: 7, CreatedAt: readAt},
+			{ID: 1, Username: username, Type: dnotifications.TypeMarketApproved, Title: "Your market was approved", MarketID: 7, ReadAt: &readAt, CreatedAt: readAt},
+		},
+		Total:       2,
+		UnreadCount: 1,
+		Limit:       20,
+	}, nil
+}
+
+func (m *inboxMock) UnreadCount(context.Context, string) (int64, error) { return 1, nil }
+
+func (m *inboxMock) MarkRead(_ context.Context, _ string, ids []int64) (int64, error) {
+	m.markedIDs = ids
+	if len(ids) == 0 {
+		return 0, dnotifications.ErrInvalidInput
+	}
+	return 0, nil
+}
+
+func (m *inboxMock) MarkAllRead(context.Context, string) error { return nil }
+
+func (m *inboxMock) Preferences(context.Context, string) ([]dnotifications.Preference, error) {
+	return []dnotifications.Preference{{Type: dnotifications.TypeMarketResolved, Enabled: true}}, nil
+}
+
+func (m *inboxMock) UpdatePreferences(_ context.Context, _ string, changes map[dnotifications.Type]bool) ([]dnotifications.Preference, error) {
+	m.changes = changes
+	for t := range changes {
+		if !dnotifications.Known(t) {
+			return nil, dnotifications.ErrInvalidInput
+		}
+	}
+	return []dnotifications.Preference{{Type: dnotifications.TypeMarketResolved, Enabled: changes[dnotifications.TypeMarketResolved]}}, nil
+}
+
+func signedIn() authMock {
+	return authMock{user: &dusers.User{Username: "alice"}}
+}
+
+func TestListNotificationsHandlerReturnsPageAndUnreadCount(t *testing.T) {
+	svc := &inboxMock{}
+	rec := httptest.NewRecorder()
+	ListNotificationsHandler(svc, signedIn()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/notifications?unread=true&limit=5", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if !svc.listFilters.UnreadOnly || svc.listFilters.Limit != 5 {
+		t.Fatalf("unexpected filters: %+v", svc.listFilters)
+	}
+	var body struct {
+		Result notificationsResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Result.UnreadCount != 1 || len(body.Result.Notifications) != 2 || body.Result.Notifications[0].Read || !body.Result.Notifications[1].Read {
+		t.Fatalf("unexpected response: %+v", body.Result)
+	}
+
+	bad := httptest.NewRecorder()
+	ListNotificationsHandler(svc, signedIn()).ServeHTTP(bad, httptest.NewRequest(http.MethodGet, "/v0/notifications?unread=maybe", nil))
+	if bad.Code != http.StatusBadRequest {
+		t.Fatalf("bad unread flag status = %d", bad.Code)
+	}
+}
+
+func TestNotificationsHandlersRequireSignedInUser(t *testing.T) {
+	auth := authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}
+	rec := httptest.NewRecorder()
+	UnreadCountHandler(&inboxMock{}, auth).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/notifications/unread-count", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestMarkReadAndPreferencesHandlers(t *testing.T) {
+	svc := &inboxMock{}
+	rec := httptest.NewRecorder()
+	MarkReadHandler(svc, signedIn()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v0/notifications/read", strings.NewReader(`{"ids":[1,2]}`)))
+	if rec.Code != http.StatusOK || len(svc.markedIDs) != 2 {
+		t.Fatalf("mark read status=%d ids=%v", rec.Code, svc.markedIDs)
+	}
+	empty := httptest.NewRecorder()
+	MarkReadHandler(svc, signedIn()).ServeHTTP(empty, httptest.NewRequest(http.MethodPost, "/v0/notifications/read", strings.NewReader(`{"ids":[]}`)))
+	if empty.Code != http.StatusBadRequest {
+		t.Fatalf("empty ids status = %d", empty.Code)
+	}
+
+	update := httptest.NewRecorder()
+	UpdatePreferencesHandler(svc, signedIn()).ServeHTTP(update, httptest.NewRequest(http.MethodPut, "/v0/notifications/preferences", strings.NewReader(`{"preferences":[{"type":"market_resolved","enabled":false}]}`)))
+	if update.Code != http.StatusOK || svc.changes[dnotifications.TypeMarketResolved] {
+		t.Fatalf("update status=%d changes=%v", update.Code, svc.changes)
+	}
+	unknown := httptest.NewRecorder()
+	UpdatePreferencesHandler(svc, signedIn()).ServeHTTP(unknown, httptest.NewRequest(http.MethodPut, "/v0/notifications/preferences", strings.NewReader(`{"preferences":[{"type":"bogus","enabled":true}]}`)))
+	if unknown.Code != http.StatusBadRequest {
+		t.Fatalf("unknown type status = %d", unknown.Code)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"socialpredict/handlers"
 	"socialpredict/handlers/markets/dto"
+	privacyhandlers "socialpredict/handlers/privacy"
 	dmarkets "socialpredict/internal/domain/markets"
 	"socialpredict/internal/domain/readmodels"
 	"socialpredict/logger"
@@ -26,8 +27,18 @@ type marketPositionsReadModelService interface {
 	RefreshMarketPositionsSnapshot(ctx context.Context, marketID int64) (*dmarkets.MarketPositionsSnapshot, error)
 }
 
-// MarketPositionsHandlerWithService creates a service-injected positions handler for all users
-func MarketPositionsHandlerWithService(svc dmarkets.ServiceInterface) http.HandlerFunc {
+type marketPositionsAsOfService interface {
+	GetMarketPositionsAsOf(ctx context.Context, marketID int64, asOf time.Time) (dmarkets.MarketPositions, error)
+	GetMarketPositionsPageAsOf(ctx context.Context, marketID int64, asOf time.Time, p dmarkets.Page) (dmarkets.MarketPositions, error)
+	GetUserPositionInMarketAsOf(ctx context.Context, marketID int64, username string, asOf time.Time) (*dmarkets.UserPosition, error)
+}
+
+var errAsOfUnsupported = errors.New("point-in-time positions are unavailable")
+
+// MarketPositionsHandlerWithService creates a service-injected positions handler for all users.
+// Anonymous traders are masked for everyone but themselves and admins. An
+// RFC3339 asOf query parameter replays only the bets placed by then.
+func MarketPositionsHandlerWithService(svc dmarkets.ServiceInterface, guard *privacyhandlers.Guard) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -39,14 +50,29 @@ func MarketPositionsHandlerWithService(svc dmarkets.ServiceInterface) http.Handl
 			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
 			return
 		}
+		asOf, err := handlers.ParseAsOf(r)
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
 
-		positions, freshness, err := getMarketPositions(r, svc, marketID)
+		var positions dmarkets.MarketPositions
+		var freshness *dto.Freshness
+		if asOf.IsZero() {
+			positions, freshness, err = getMarketPositions(r, svc, marketID)
+		} else {
+			positions, err = getMarketPositionsAsOf(r, svc, marketID, asOf)
+		}
 		if err != nil {
 			writePositionsError(w, marketID, err)
 			return
 		}
 
 		responses := mapPositionsToResponses(positions)
+		if err := maskPositions(r, guard, responses); err != nil {
+			privacyhandlers.WriteError(w, "MarketPositions", err)
+			return
+		}
 
 		payload := any(responses)
 		if hasPaginationQuery(r) {
@@ -81,6 +107,17 @@ func getMarketPositions(r *http.Request, svc dmarkets.ServiceInterface, marketID
 	return positions, &freshness, err
 }
 
+func getMarketPositionsAsOf(r *http.Request, svc dmarkets.ServiceInterface, marketID int64, asOf time.Time) (dmarkets.MarketPositions, error) {
+	service, ok := svc.(marketPositionsAsOfService)
+	if !ok {
+		return nil, errAsOfUnsupported
+	}
+	if !hasPaginationQuery(r) {
+		return service.GetMarketPositionsAsOf(r.Context(), marketID, asOf)
+	}
+	return service.GetMarketPositionsPageAsOf(r.Context(), marketID, asOf, parsePage(r, 20))
+}
+
 func marketPositionsReadModel(ctx context.Context, svc dmarkets.ServiceInterface, marketID int64, page dmarkets.Page) (*dmarkets.MarketPositionsSnapshot, error) {
 	service, ok := svc.(marketPositionsReadModelService)
 	if !ok {
@@ -117,8 +154,10 @@ func readModelFreshnessToResponse(freshness readmodels.Freshness) dto.Freshness
 	}
 }
 
-// MarketUserPositionHandlerWithService creates a service-injected handler for a specific user's position
-func MarketUserPositionHandlerWithService(svc dmarkets.ServiceInterface) http.HandlerFunc {
+// MarketUserPositionHandlerWithService creates a service-injected handler for a specific user's position.
+// An anonymous trader's position is forbidden to everyone but them and admins.
+// It honours the same asOf query parameter as the market positions list.
+func MarketUserPositionHandlerWithService(svc dmarkets.ServiceInterface, guard *privacyhandlers.Guard) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -131,7 +170,23 @@ func MarketUserPositionHandlerWithService(svc dmarkets.ServiceInterface) http.Ha
 			return
 		}
 
-		position, err := svc.GetUserPositionInMarket(r.Context(), marketID, username)
+		asOf, err := handlers.ParseAsOf(r)
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		mask, err := guard.Mask(r, []string{username})
+		if err != nil {
+			privacyhandlers.WriteError(w, "MarketUserPosition", err)
+			return
+		}
+		if mask[username] {
+			_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+			return
+		}
+
+		position, err := getUserPosition(r, svc, marketID, username, asOf)
 		if err != nil {
 			writeUserPositionError(w, marketID, username, err)
 			return
@@ -144,6 +199,17 @@ func MarketUserPositionHandlerWithService(svc dmarkets.ServiceInterface) http.Ha
 	}
 }
 
+func getUserPosition(r *http.Request, svc dmarkets.ServiceInterface, marketID int64, username string, asOf time.Time) (*dmarkets.UserPosition, error) {
+	if asOf.IsZero() {
+		return svc.GetUserPositionInMarket(r.Context(), marketID, username)
+	}
+	service, ok := svc.(marketPositionsAsOfService)
+	if !ok {
+		return nil, errAsOfUnsupported
+	}
+	return service.GetUserPositionInMarketAsOf(r.Context(), marketID, username, asOf)
+}
+
 func parseMarketID(marketIDStr string) (int64, error) {
 	if marketIDStr == "" {
 		return 0, errors.New("Market ID is required")
@@ -201,6 +267,23 @@ func mapPositionsToResponses(positions []*dmarkets.UserPosition) []userPositionR
 	return responses
 }
 
+// maskPositions rewrites masked usernames in place; responses are already
+// copies, so snapshots and cached positions keep the real names.
+func maskPositions(r *http.Request, guard *privacyhandlers.Guard, responses []userPositionResponse) error {
+	usernames := make([]string, 0, len(responses))
+	for _, response := range responses {
+		usernames = append(usernames, response.Username)
+	}
+	mask, err := guard.Mask(r, usernames)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		responses[i].Username = mask.Name(responses[i].Username)
+	}
+	return nil
+}
+
 func parseMarketUserParams(vars map[string]string) (int64, string, error) {
 	marketID, err := parseMarketID(vars["marketId"])
 	if err != nil {
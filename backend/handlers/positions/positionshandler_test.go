@@ -11,9 +11,12 @@ import (
 	"time"
 
 	"socialpredict/handlers"
+	privacyhandlers "socialpredict/handlers/privacy"
 	"socialpredict/internal/domain/boundary"
 	dmarkets "socialpredict/internal/domain/markets"
 	positionsmath "socialpredict/internal/domain/math/positions"
+	dprivacy "socialpredict/internal/domain/privacy"
+	rprivacy "socialpredict/internal/repository/privacy"
 	"socialpredict/models"
 	"socialpredict/models/modelstesting"
 
@@ -203,7 +206,7 @@ func TestMarketPositionsHandlerWithService_IncludesZeroPositionUsers(t *testing.
 	}
 
 	mockSvc := &mockPositionsService{positions: toDomainPositions(positionSnapshot)}
-	handler := MarketPositionsHandlerWithService(mockSvc)
+	handler := MarketPositionsHandlerWithService(mockSvc, nil)
 
 	req := httptest.NewRequest("GET", "/v0/markets/positions/"+marketIDStr, nil)
 	req = mux.SetURLVars(req, map[string]string{
@@ -250,7 +253,7 @@ func TestMarketPositionsHandlerWithService_IncludesZeroPositionUsers(t *testing.
 }
 
 func TestMarketPositionsHandlerWithService_FailureEnvelope(t *testing.T) {
-	handler := MarketPositionsHandlerWithService(&mockPositionsService{err: dmarkets.ErrMarketNotFound})
+	handler := MarketPositionsHandlerWithService(&mockPositionsService{err: dmarkets.ErrMarketNotFound}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/markets/positions/77", nil)
 	req = mux.SetURLVars(req, map[string]string{"marketId": "77"})
@@ -277,7 +280,7 @@ func TestMarketPositionsHandlerWithService_UsesPaginationQuery(t *testing.T) {
 			{Username: "alice", MarketID: 7, YesSharesOwned: 3},
 		},
 	}
-	handler := MarketPositionsHandlerWithService(mockSvc)
+	handler := MarketPositionsHandlerWithService(mockSvc, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7?limit=20&offset=40", nil)
 	req = mux.SetURLVars(req, map[string]string{"marketId": "7"})
@@ -313,7 +316,7 @@ func TestMarketPositionsHandlerWithService_UsesSnapshotFreshnessWhenAvailable(t
 			},
 		},
 	}
-	handler := MarketPositionsHandlerWithService(mockSvc)
+	handler := MarketPositionsHandlerWithService(mockSvc, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7?limit=20&offset=0", nil)
 	req = mux.SetURLVars(req, map[string]string{"marketId": "7"})
@@ -363,7 +366,7 @@ func TestMarketPositionsHandlerWithService_ServesStaleSnapshotWithoutRefresh(t *
 			},
 		},
 	}
-	handler := MarketPositionsHandlerWithService(mockSvc)
+	handler := MarketPositionsHandlerWithService(mockSvc, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7?limit=20&offset=0", nil)
 	req = mux.SetURLVars(req, map[string]string{"marketId": "7"})
@@ -393,7 +396,7 @@ func TestMarketPositionsHandlerWithService_ServesStaleSnapshotWithoutRefresh(t *
 func TestMarketUserPositionHandlerWithService_FailureEnvelope(t *testing.T) {
 	handler := MarketUserPositionHandlerWithService(&mockUserPositionService{
 		mockPositionsService: mockPositionsService{err: errors.New("boom")},
-	})
+	}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/markets/positions/77/alice", nil)
 	req = mux.SetURLVars(req, map[string]string{
@@ -455,3 +458,97 @@ func (m *mockUserPositionService) GetUserPositionInMarket(ctx context.Context, m
 	}
 	return m.position, nil
 }
+
+func TestPositionsHandlersMaskAnonymousTraders(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	if err := db.Create(&models.TradingPrivacySetting{Username: "alice", AnonymousTrades: true}).Error; err != nil {
+		t.Fatalf("create privacy setting: %v", err)
+	}
+	guard := privacyhandlers.NewGuard(dprivacy.NewService(rprivacy.NewGormRepository(db), nil, nil), nil)
+	svc := &mockUserPositionService{mockPositionsService: mockPositionsService{positions: dmarkets.MarketPositions{
+		{Username: "alice", MarketID: 7, YesSharesOwned: 5},
+		{Username: "bob", MarketID: 7, NoSharesOwned: 3},
+	}}}
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7", nil), map[string]string{"marketId": "7"})
+	rec := httptest.NewRecorder()
+	MarketPositionsHandlerWithService(svc, guard).ServeHTTP(rec, req)
+	var envelope handlers.SuccessEnvelope[[]userPositionResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil || len(envelope.Result) != 2 {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	if envelope.Result[0].Username != dprivacy.AnonymousUsername || envelope.Result[0].YesSharesOwned != 5 || envelope.Result[1].Username != "bob" {
+		t.Fatalf("expected alice masked and bob named, got %+v", envelope.Result)
+	}
+	if svc.positions[0].Username != "alice" {
+		t.Fatalf("masking must not rewrite the service's positions")
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7/alice", nil), map[string]string{"marketId": "7", "username": "alice"})
+	rec = httptest.NewRecorder()
+	MarketUserPositionHandlerWithService(svc, guard).ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected an anonymous trader's position to be forbidden, got %d", rec.Code)
+	}
+}
+
+type asOfPositionsService struct {
+	mockPositionsService
+	asOf time.Time
+}
+
+func (m *asOfPositionsService) GetMarketPositionsAsOf(ctx context.Context, marketID int64, asOf time.Time) (dmarkets.MarketPositions, error) {
+	m.asOf = asOf
+	return dmarkets.MarketPositions{{Username: "alice", MarketID: marketID, YesSharesOwned: 2}}, nil
+}
+
+func (m *asOfPositionsService) GetMarketPositionsPageAsOf(ctx context.Context, marketID int64, asOf time.Time, p dmarkets.Page) (dmarkets.MarketPositions, error) {
+	m.page = &p
+	return m.GetMarketPositionsAsOf(ctx, marketID, asOf)
+}
+
+func (m *asOfPositionsService) GetUserPositionInMarketAsOf(ctx context.Context, marketID int64, username string, asOf time.Time) (*dmarkets.UserPosition, error) {
+	m.asOf = asOf
+	return &dmarkets.UserPosition{Username: username, MarketID: marketID, NoSharesOwned: 4}, nil
+}
+
+func TestPositionsHandlersReplayAsOf(t *testing.T) {
+	mockSvc := &asOfPositionsService{mockPositionsService: mockPositionsService{err: errors.New("live positions should not be read")}}
+	asOf := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7?asOf=2026-07-01T12:00:00Z&limit=5", nil)
+	req = mux.SetURLVars(req, map[string]string{"marketId": "7"})
+	rec := httptest.NewRecorder()
+	MarketPositionsHandlerWithService(mockSvc, nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("positions status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if !mockSvc.asOf.Equal(asOf) || mockSvc.page == nil || mockSvc.page.Limit != 5 {
+		t.Fatalf("as-of page not used: asOf=%s page=%+v", mockSvc.asOf, mockSvc.page)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7/alice?asOf=2026-07-01T12:00:00Z", nil)
+	req = mux.SetURLVars(req, map[string]string{"marketId": "7", "username": "alice"})
+	rec = httptest.NewRecorder()
+	MarketUserPositionHandlerWithService(mockSvc, nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("user position status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var resp handlers.SuccessEnvelope[userPositionResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Result.NoSharesOwned != 4 {
+		t.Fatalf("user position = %+v, want the as-of position", resp.Result)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v0/markets/positions/7?asOf=last-week", nil)
+	req = mux.SetURLVars(req, map[string]string{"marketId": "7"})
+	rec = httptest.NewRecorder()
+	MarketPositionsHandlerWithService(mockSvc, nil).ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("malformed asOf status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,166 @@
+package privacyhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	"socialpredict/internal/domain/permissions"
+	dprivacy "socialpredict/internal/domain/privacy"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/logger"
+)
+
+type privacyService interface {
+	Settings(ctx context.Context, username string) (dprivacy.Settings, error)
+	UpdateSettings(ctx context.Context, username string, settings dprivacy.Settings) (dprivacy.Settings, error)
+	Mask(ctx context.Context, viewer permissions.Subject, usernames []string) (dprivacy.Mask, error)
+	CheckPortfolio(ctx context.Context, viewer permissions.Subject, username string) error
+}
+
+type settingsRequest struct {
+	HidePortfolio   *bool `json:"hidePortfolio"`
+	AnonymousTrades *bool `json:"anonymousTrades"`
+}
+
+type settingsResponse struct {
+	HidePortfolio   bool   `json:"hidePortfolio"`
+	AnonymousTrades bool   `json:"anonymousTrades"`
+	UpdatedAt       string `json:"updatedAt,omitempty"`
+}
+
+// Guard applies trading privacy settings to public handlers on behalf of the
+// signed-in viewer, or a signed-out visitor when there is no valid session.
+// A nil Guard shows everything, which keeps handler tests and tools simple.
+type Guard struct {
+	svc  privacyService
+	auth authsvc.Authenticator
+}
+
+// NewGuard constructs a Guard.
+func NewGuard(svc privacyService, auth authsvc.Authenticator) *Guard {
+	return &Guard{svc: svc, auth: auth}
+}
+
+// Mask returns which of usernames the request's viewer must see as
+// anonymous.
+func (g *Guard) Mask(r *http.Request, usernames []string) (dprivacy.Mask, error) {
+	if g == nil || g.svc == nil || len(usernames) == 0 {
+		return dprivacy.Mask{}, nil
+	}
+	return g.svc.Mask(r.Context(), g.viewer(r), usernames)
+}
+
+// CheckPortfolio returns dprivacy.ErrPortfolioHidden when the request's
+// viewer may not see username's portfolio.
+func (g *Guard) CheckPortfolio(r *http.Request, username string) error {
+	if g == nil || g.svc == nil {
+		return nil
+	}
+	return g.svc.CheckPortfolio(r.Context(), g.viewer(r), username)
+}
+
+func (g *Guard) viewer(r *http.Request) permissions.Subject {
+	if g.auth == nil {
+		return permissions.Subject{}
+	}
+	user, authErr := g.auth.CurrentUser(r)
+	if authErr != nil {
+		return permissions.Subject{}
+	}
+	return user.PermissionSubject()
+}
+
+// WriteError writes the failure for a privacy lookup error. Lookups fail
+// closed: a hidden portfolio is a 403 and anything else is a 500.
+func WriteError(w http.ResponseWriter, component string, err error) {
+	if errors.Is(err, dprivacy.ErrPortfolioHidden) {
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+		return
+	}
+	logger.LogError(component, "TradingPrivacy", err)
+	_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+}
+
+// GetSettingsHandler handles GET /v0/profile/privacy.
+func GetSettingsHandler(svc privacyService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		user, authErr := auth.CurrentUser(r)
+		if authErr != nil {
+			_ = authhttp.WriteFailure(w, authErr)
+			return
+		}
+		settings, err := svc.Settings(r.Context(), user.Username)
+		if err != nil {
+			writeSettingsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, settingsResponseFromDomain(settings))
+	}
+}
+
+// UpdateSettingsHandler handles PUT /v0/profile/privacy. Omitted fields keep
+// their current value.
+func UpdateSettingsHandler(svc privacyService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		user, authErr := auth.CurrentUser(r)
+		if authErr != nil {
+			_ = authhttp.WriteFailure(w, authErr)
+			return
+		}
+		var req settingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		settings, err := svc.Settings(r.Context(), user.Username)
+		if err != nil {
+			writeSettingsError(w, err)
+			return
+		}
+		if req.HidePortfolio != nil {
+			settings.HidePortfolio = *req.HidePortfolio
+		}
+		if req.AnonymousTrades != nil {
+			settings.AnonymousTrades = *req.AnonymousTrades
+		}
+		settings, err = svc.UpdateSettings(r.Context(), user.Username, settings)
+		if err != nil {
+			writeSettingsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, settingsResponseFromDomain(settings))
+	}
+}
+
+func writeSettingsError(w http.ResponseWriter, err error) {
+	if errors.Is(err, dprivacy.ErrInvalidInput) {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return
+	}
+	logger.LogError("Privacy", "Settings", err)
+	_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+}
+
+func settingsResponseFromDomain(settings dprivacy.Settings) settingsResponse {
+	response := settingsResponse{
+		HidePortfolio:   settings.HidePortfolio,
+		AnonymousTrades: settings.AnonymousTrades,
+	}
+	if !settings.UpdatedAt.IsZero() {
+		response.UpdatedAt = settings.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,114 @@
+package privacyhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+	dprivacy "socialpredict/internal/domain/privacy"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+type privacyMock struct {
+	settings dprivacy.Settings
+	viewer   permissions.Subject
+	err      error
+}
+
+func (m *privacyMock) Settings(context.Context, string) (dprivacy.Settings, error) {
+	return m.settings, m.err
+}
+
+func (m *privacyMock) UpdateSettings(_ context.Context, _ string, settings dprivacy.Settings) (dprivacy.Settings, error) {
+	settings.UpdatedAt = time.Date(2026, 7, 5, 9, 0, 0, 0, time.UTC)
+	m.settings = settings
+	return settings, m.err
+}
+
+func (m *privacyMock) Mask(_ context.Context, viewer permissions.Subject, _ []string) (dprivacy.Mask, error) {
+	m.viewer = viewer
+	return dprivacy.Mask{"alice": true}, m.err
+}
+
+func (m *privacyMock) CheckPortfolio(_ context.Context, viewer permissions.Subject, _ string) error {
+	m.viewer = viewer
+	return m.err
+}
+
+func TestUpdateSettingsHandlerKeepsOmittedFields(t *testing.T) {
+	svc := &privacyMock{settings: dprivacy.Settings{HidePortfolio: true}}
+	auth := authMock{user: &dusers.User{Username: "alice"}}
+
+	rec := httptest.NewRecorder()
+	UpdateSettingsHandler(svc, auth).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v0/profile/privacy", strings.NewReader(`{"anonymousTrades":true}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var decoded struct {
+		Result settingsResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil || !decoded.Result.HidePortfolio || !decoded.Result.AnonymousTrades || decoded.Result.UpdatedAt != "2026-07-05T09:00:00Z" {
+		t.Fatalf("unexpected response %+v, %v", decoded.Result, err)
+	}
+
+	rec = httptest.NewRecorder()
+	UpdateSettingsHandler(svc, auth).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v0/profile/privacy", strings.NewReader(`{`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected malformed JSON to be rejected, got %d", rec.Code)
+	}
+
+	missing := authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}
+	rec = httptest.NewRecorder()
+	GetSettingsHandler(svc, missing).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/profile/privacy", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a session, got %d", rec.Code)
+	}
+}
+
+func TestGuardResolvesViewerAndFailsClosed(t *testing.T) {
+	svc := &privacyMock{}
+	req := httptest.NewRequest(http.MethodGet, "/v0/markets/bets/1", nil)
+
+	var nilGuard *Guard
+	if mask, err := nilGuard.Mask(req, []string{"alice"}); err != nil || len(mask) != 0 {
+		t.Fatalf("a nil guard should mask nothing, got %v, %v", mask, err)
+	}
+
+	signedOut := NewGuard(svc, authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}})
+	if mask, err := signedOut.Mask(req, []string{"alice"}); err != nil || mask.Name("alice") != dprivacy.AnonymousUsername || svc.viewer.Username != "" {
+		t.Fatalf("expected a signed-out viewer, got %+v mask=%v err=%v", svc.viewer, mask, err)
+	}
+	signedIn := NewGuard(svc, authMock{user: &dusers.User{Username: "bob", UserType: "ADMIN"}})
+	if err := signedIn.CheckPortfolio(req, "alice"); err != nil || svc.viewer.Username != "bob" || svc.viewer.Role != "ADMIN" {
+		t.Fatalf("expected bob as the viewer, got %+v, %v", svc.viewer, err)
+	}
+
+	for err, want := range map[error]int{
+		dprivacy.ErrPortfolioHidden: http.StatusForbidden,
+		errors.New("db down"):       http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		WriteError(rec, "Test", err)
+		if rec.Code != want {
+			t.Fatalf("%v: status = %d, want %d", err, rec.Code, want)
+		}
+	}
+}
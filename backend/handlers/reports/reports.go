@@ -0,0 +1,357 @@
+package reportshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	dcomments "socialpredict/internal/domain/comments"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dreports "socialpredict/internal/domain/reports"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/security"
+)
+
+const maxReportsPageParam = 10000
+
+type moderationQueue interface {
+	Submit(ctx context.Context, reporter string, targetType dreports.TargetType, targetID string, category dreports.Category, details string) (*dreports.Report, bool, error)
+	MyReports(ctx context.Context, reporter string, limit, offset int) ([]*dreports.Report, int64, error)
+	Queue(ctx context.Context, actor permissions.Subject, status dreports.Status, limit, offset int) ([]*dreports.QueueItem, int64, error)
+	Act(ctx context.Context, actor *dusers.User, input dreports.ActionInput) (*dreports.ModerationAction, error)
+	Actions(ctx context.Context, actor permissions.Subject, targetType dreports.TargetType, targetID string, limit, offset int) ([]*dreports.ModerationAction, error)
+}
+
+type submitReportRequest struct {
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetId"`
+	Category   string `json:"category"`
+	Details    string `json:"details"`
+}
+
+type reportResponse struct {
+	ID         int64   `json:"id"`
+	TargetType string  `json:"targetType"`
+	TargetID   string  `json:"targetId"`
+	Category   string  `json:"category"`
+	Details    string  `json:"details,omitempty"`
+	Status     string  `json:"status"`
+	Outcome    string  `json:"outcome,omitempty"`
+	ReviewedAt *string `json:"reviewedAt,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+type reportsResponse struct {
+	Reports []reportResponse `json:"reports"`
+	Total   int64            `json:"total"`
+}
+
+type queuedReportResponse struct {
+	ID               int64  `json:"id"`
+	ReporterUsername string `json:"reporterUsername"`
+	Category         string `json:"category"`
+	Details          string `json:"details,omitempty"`
+	CreatedAt        string `json:"createdAt"`
+}
+
+type targetResponse struct {
+	Type          string `json:"type"`
+	ID            string `json:"id"`
+	Label         string `json:"label,omitempty"`
+	OwnerUsername string `json:"ownerUsername,omitempty"`
+	MarketID      int64  `json:"marketId,omitempty"`
+	MarketGroupID int64  `json:"marketGroupId,omitempty"`
+}
+
+type queueItemResponse struct {
+	Target          targetResponse         `json:"target"`
+	ReportCount     int                    `json:"reportCount"`
+	Categories      map[string]int         `json:"categories"`
+	FirstReportedAt string                 `json:"firstReportedAt"`
+	LastReportedAt  string                 `json:"lastReportedAt"`
+	Reports         []queuedReportResponse `json:"reports"`
+}
+
+type queueResponse struct {
+	Items []queueItemResponse `json:"items"`
+	Total int64               `json:"total"`
+}
+
+type actionRequest struct {
+	TargetType string  `json:"targetType"`
+	TargetID   string  `json:"targetId"`
+	Action     string  `json:"action"`
+	Reason     string  `json:"reason"`
+	ExpiresAt  *string `json:"expiresAt"`
+}
+
+type actionResponse struct {
+	ID            int64  `json:"id"`
+	TargetType    string `json:"targetType"`
+	TargetID      string `json:"targetId"`
+	Action        string `json:"action"`
+	ActorUsername string `json:"actorUsername"`
+	Reason        string `json:"reason"`
+	ReportsClosed int    `json:"reportsClosed"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+type actionsResponse struct {
+	Actions []actionResponse `json:"actions"`
+}
+
+// SubmitReportHandler handles POST /v0/reports. Reporting is rate limited
+// per user when limiter is set.
+func SubmitReportHandler(svc moderationQueue, auth authsvc.Authenticator, limiter *security.RateLimiter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request submitReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		if limiter != nil && !limiter.GetLimiter("reports:"+user.Username).Allow() {
+			_ = handlers.WriteFailure(w, http.StatusTooManyRequests, handlers.ReasonRateLimited)
+			return
+		}
+		report, created, err := svc.Submit(r.Context(), user.Username, dreports.TargetType(strings.TrimSpace(request.TargetType)), request.TargetID, dreports.Category(strings.TrimSpace(request.Category)), request.Details)
+		if err != nil {
+			writeReportsError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		_ = handlers.WriteResult(w, status, reportResponseFromDomain(report))
+	}
+}
+
+// ListMyReportsHandler handles GET /v0/reports, the reporter's view of what
+// happened to their reports.
+func ListMyReportsHandler(svc moderationQueue, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		limit, offset, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		reports, total, err := svc.MyReports(r.Context(), user.Username, limit, offset)
+		if err != nil {
+			writeReportsError(w, err)
+			return
+		}
+		response := reportsResponse{Reports: make([]reportResponse, 0, len(reports)), Total: total}
+		for _, report := range reports {
+			response.Reports = append(response.Reports, reportResponseFromDomain(report))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// ListReportQueueHandler handles GET /v0/admin/reports.
+func ListReportQueueHandler(svc moderationQueue, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		limit, offset, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		status := dreports.Status(strings.TrimSpace(r.URL.Query().Get("status")))
+		items, total, err := svc.Queue(r.Context(), user.PermissionSubject(), status, limit, offset)
+		if err != nil {
+			writeReportsError(w, err)
+			return
+		}
+		response := queueResponse{Items: make([]queueItemResponse, 0, len(items)), Total: total}
+		for _, item := range items {
+			response.Items = append(response.Items, queueItemResponseFromDomain(item))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// CreateModerationActionHandler handles POST /v0/admin/reports/actions.
+func CreateModerationActionHandler(svc moderationQueue, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request actionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		input := dreports.ActionInput{
+			TargetType: dreports.TargetType(strings.TrimSpace(request.TargetType)),
+			TargetID:   request.TargetID,
+			Action:     dreports.Action(strings.TrimSpace(request.Action)),
+			Reason:     request.Reason,
+		}
+		if request.ExpiresAt != nil && strings.TrimSpace(*request.ExpiresAt) != "" {
+			expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(*request.ExpiresAt))
+			if err != nil {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+				return
+			}
+			input.ExpiresAt = &expiresAt
+		}
+		action, err := svc.Act(r.Context(), user, input)
+		if err != nil {
+			writeReportsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusCreated, actionResponseFromDomain(action))
+	}
+}
+
+// ListModerationActionsHandler handles GET /v0/admin/reports/actions.
+func ListModerationActionsHandler(svc moderationQueue, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		limit, offset, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		actions, err := svc.Actions(r.Context(), user.PermissionSubject(), dreports.TargetType(strings.TrimSpace(query.Get("targetType"))), query.Get("targetId"), limit, offset)
+		if err != nil {
+			writeReportsError(w, err)
+			return
+		}
+		response := actionsResponse{Actions: make([]actionResponse, 0, len(actions))}
+		for _, action := range actions {
+			response.Actions = append(response.Actions, actionResponseFromDomain(action))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+func currentUser(w http.ResponseWriter, r *http.Request, svc moderationQueue, auth authsvc.Authenticator) (*dusers.User, bool) {
+	if svc == nil || auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return nil, false
+	}
+	user, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return nil, false
+	}
+	return user, true
+}
+
+func parsePage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	query := r.URL.Query()
+	var limit, offset int
+	for name, target := range map[string]*int{"limit": &limit, "offset": &offset} {
+		raw := strings.TrimSpace(query.Get(name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 || value > maxReportsPageParam {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return 0, 0, false
+		}
+		*target = value
+	}
+	return limit, offset, true
+}
+
+func writeReportsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dreports.ErrInvalidInput), errors.Is(err, dusers.ErrInvalidUserData):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	case errors.Is(err, dreports.ErrTargetNotFound), errors.Is(err, dcomments.ErrCommentNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, dreports.ErrActionNotApplicable), errors.Is(err, dmarkets.ErrInvalidState):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	case errors.Is(err, permissions.ErrPermissionDenied), errors.Is(err, dmarkets.ErrUnauthorized), errors.Is(err, dusers.ErrUnauthorized):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func reportResponseFromDomain(report *dreports.Report) reportResponse {
+	response := reportResponse{
+		ID:         report.ID,
+		TargetType: string(report.TargetType),
+		TargetID:   report.TargetID,
+		Category:   string(report.Category),
+		Details:    report.Details,
+		Status:     string(report.Status),
+		Outcome:    string(report.Outcome),
+		CreatedAt:  report.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if report.ReviewedAt != nil {
+		reviewedAt := report.ReviewedAt.UTC().Format(time.RFC3339)
+		response.ReviewedAt = &reviewedAt
+	}
+	return response
+}
+
+func queueItemResponseFromDomain(item *dreports.QueueItem) queueItemResponse {
+	response := queueItemResponse{
+		Target: targetResponse{
+			Type:          string(item.Target.Type),
+			ID:            item.Target.ID,
+			Label:         item.Target.Label,
+			OwnerUsername: item.Target.OwnerUsername,
+			MarketID:      item.Target.MarketID,
+			MarketGroupID: item.Target.MarketGroupID,
+		},
+		ReportCount:     item.ReportCount,
+		Categories:      make(map[string]int, len(item.Categories)),
+		FirstReportedAt: item.FirstReportedAt.UTC().Format(time.RFC3339),
+		LastReportedAt:  item.LastReportedAt.UTC().Format(time.RFC3339),
+		Reports:         make([]queuedReportResponse, 0, len(item.Reports)),
+	}
+	for category, count := range item.Categories {
+		response.Categories[string(category)] = count
+	}
+	for _, report := range item.Reports {
+		response.Reports = append(response.Reports, queuedReportResponse{
+			ID:               report.ID,
+			ReporterUsername: report.ReporterUsername,
+			Category:         string(report.Category),
+			Details:          report.Details,
+			CreatedAt:        report.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return response
+}
+
+func actionResponseFromDomain(action *dreports.ModerationAction) actionResponse {
+	return actionResponse{
+		ID:            action.ID,
+		TargetType:    string(action.TargetType),
+		TargetID:      action.TargetID,
+		Action:        string(action.Action),
+		ActorUsername: action.ActorUsername,
+		Reason:        action.Reason,
+		ReportsClosed: action.ReportsClosed,
+		CreatedAt:     action.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
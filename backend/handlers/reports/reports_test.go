@@ -0,0 +1,181 @@
+package reportshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dreports "socialpredict/internal/domain/reports"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/security"
+
+	"golang.org/x/time/rate"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+type queueMock struct {
+	created  bool
+	category dreports.Category
+	status   dreports.Status
+	input    dreports.ActionInput
+	err      error
+}
+
+var reportedAt = time.Date(2026, 7, 2, 9, 0, 0, 0, time.UTC)
+
+func (m *queueMock) Submit(_ context.Context, reporter string, targetType dreports.TargetType, targetID string, category dreports.Category, details string) (*dreports.Report, bool, error) {
+	m.category = category
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	return &dreports.Report{ID: 4, TargetType: targetType, TargetID: targetID, ReporterUsername: reporter, Category: category, Details: details, Status: dreports.StatusOpen, CreatedAt: reportedAt}, m.created, nil
+}
+
+func (m *queueMock) MyReports(_ context.Context, reporter string, _, _ int) ([]*dreports.Report, int64, error) {
+	return []*dreports.Report{{ID: 4, TargetType: dreports.TargetComment, TargetID: "3", ReporterUsername: reporter, Category: dreports.CategorySpam, Status: dreports.StatusActioned, Outcome: dreports.ActionHideContent, ReviewedBy: "mod", ReviewedAt: &reportedAt, CreatedAt: reportedAt}}, 1, m.err
+}
+
+func (m *queueMock) Queue(_ context.Context, _ permissions.Subject, status dreports.Status, _, _ int) ([]*dreports.QueueItem, int64, error) {
+	m.status = status
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+	return []*dreports.QueueItem{{
+		Target:          dreports.Target{Type: dreports.TargetMarket, ID: "7", Label: "Will it rain?", OwnerUsername: "carol", MarketID: 7},
+		ReportCount:     2,
+		Categories:      map[dreports.Category]int{dreports.CategoryMisleading: 2},
+		FirstReportedAt: reportedAt,
+		LastReportedAt:  reportedAt.Add(time.Hour),
+		Reports:         []*dreports.Report{{ID: 9, ReporterUsername: "alice", Category: dreports.CategoryMisleading, CreatedAt: reportedAt}},
+	}}, 1, nil
+}
+
+func (m *queueMock) Act(_ context.Context, actor *dusers.User, input dreports.ActionInput) (*dreports.ModerationAction, error) {
+	m.input = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dreports.ModerationAction{ID: 1, TargetType: input.TargetType, TargetID: input.TargetID, Action: input.Action, ActorUsername: actor.Username, Reason: input.Reason, ReportsClosed: 2, CreatedAt: reportedAt}, nil
+}
+
+func (m *queueMock) Actions(context.Context, permissions.Subject, dreports.TargetType, string, int, int) ([]*dreports.ModerationAction, error) {
+	return nil, m.err
+}
+
+func signedIn() authMock {
+	return authMock{user: &dusers.User{Username: "alice"}}
+}
+
+func serve(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func TestSubmitReportHandlerStatusAndRateLimit(t *testing.T) {
+	svc := &queueMock{created: true}
+	limiter := security.NewRateLimiter(rate.Every(time.Hour), 1, time.Hour)
+	handler := SubmitReportHandler(svc, signedIn(), limiter)
+	body := `{"targetType":"comment","targetId":"3","category":" spam ","details":"link farm"}`
+
+	rec := serve(handler, http.MethodPost, "/v0/reports", body)
+	if rec.Code != http.StatusCreated || svc.category != dreports.CategorySpam {
+		t.Fatalf("status = %d category=%q body=%s", rec.Code, svc.category, rec.Body.String())
+	}
+	var decoded struct {
+		Result reportResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil || decoded.Result.ID != 4 || decoded.Result.Status != "open" {
+		t.Fatalf("unexpected response %+v, %v", decoded.Result, err)
+	}
+
+	if rec := serve(handler, http.MethodPost, "/v0/reports", body); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected rate limit, got %d", rec.Code)
+	}
+
+	svc.created = false
+	if rec := serve(SubmitReportHandler(svc, signedIn(), nil), http.MethodPost, "/v0/reports", body); rec.Code != http.StatusOK {
+		t.Fatalf("expected an existing open report to return 200, got %d", rec.Code)
+	}
+}
+
+func TestReportsHandlersMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "invalid", err: dreports.ErrInvalidInput, want: http.StatusBadRequest},
+		{name: "missing target", err: dreports.ErrTargetNotFound, want: http.StatusNotFound},
+		{name: "not applicable", err: dreports.ErrActionNotApplicable, want: http.StatusConflict},
+		{name: "already resolved", err: dmarkets.ErrInvalidState, want: http.StatusConflict},
+		{name: "denied", err: permissions.ErrPermissionDenied, want: http.StatusForbidden},
+		{name: "cannot suspend admin", err: dusers.ErrUnauthorized, want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &queueMock{err: tt.err}
+			rec := serve(CreateModerationActionHandler(svc, signedIn()), http.MethodPost, "/v0/admin/reports/actions", `{"targetType":"market","targetId":"7","action":"yank_market","reason":"dup"}`)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	missing := authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}
+	if rec := serve(ListMyReportsHandler(&queueMock{}, missing), http.MethodGet, "/v0/reports", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a session, got %d", rec.Code)
+	}
+}
+
+func TestCreateModerationActionHandlerParsesExpiry(t *testing.T) {
+	svc := &queueMock{}
+	handler := CreateModerationActionHandler(svc, signedIn())
+
+	if rec := serve(handler, http.MethodPost, "/v0/admin/reports/actions", `{"targetType":"user","targetId":"dave","action":"suspend_user","reason":"spam","expiresAt":"tomorrow"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad expiry to be rejected, got %d", rec.Code)
+	}
+	rec := serve(handler, http.MethodPost, "/v0/admin/reports/actions", `{"targetType":"user","targetId":"dave","action":"suspend_user","reason":"spam","expiresAt":"2026-08-01T00:00:00Z"}`)
+	if rec.Code != http.StatusCreated || svc.input.ExpiresAt == nil || svc.input.Action != dreports.ActionSuspendUser {
+		t.Fatalf("status = %d input=%+v", rec.Code, svc.input)
+	}
+}
+
+func TestListReportQueueHandlerShapesItems(t *testing.T) {
+	svc := &queueMock{}
+	rec := serve(ListReportQueueHandler(svc, signedIn()), http.MethodGet, "/v0/admin/reports?status=dismissed&limit=5", "")
+	if rec.Code != http.StatusOK || svc.status != dreports.StatusDismissed {
+		t.Fatalf("status = %d queue status=%q", rec.Code, svc.status)
+	}
+	var decoded struct {
+		Result queueResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	item := decoded.Result.Items[0]
+	if item.Target.Label != "Will it rain?" || item.Categories["misleading"] != 2 || item.Reports[0].ReporterUsername != "alice" {
+		t.Fatalf("unexpected item %+v", item)
+	}
+
+	if rec := serve(ListReportQueueHandler(svc, signedIn()), http.MethodGet, "/v0/admin/reports?limit=-1", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad limit to be rejected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,340 @@
+package seasonshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	"socialpredict/internal/domain/permissions"
+	dseasons "socialpredict/internal/domain/seasons"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+
+	"github.com/gorilla/mux"
+)
+
+const maxSeasonsPageParam = 10000
+
+type seasonService interface {
+	List(ctx context.Context) ([]dseasons.Season, error)
+	Get(ctx context.Context, id int64) (*dseasons.Season, error)
+	Standings(ctx context.Context, id int64, limit, offset int) ([]dseasons.Standing, int64, error)
+	Create(ctx context.Context, actor permissions.Subject, input dseasons.Input) (*dseasons.Season, error)
+	Update(ctx context.Context, actor permissions.Subject, id int64, input dseasons.Input) (*dseasons.Season, error)
+	Rollover(ctx context.Context, actor permissions.Subject, id int64) (*dseasons.Season, error)
+}
+
+type seasonRequest struct {
+	Name           string `json:"name"`
+	StartsAt       string `json:"startsAt"`
+	EndsAt         string `json:"endsAt"`
+	ResetBalances  bool   `json:"resetBalances"`
+	PositionPolicy string `json:"positionPolicy"`
+}
+
+type seasonResponse struct {
+	ID                  int64   `json:"id"`
+	Name                string  `json:"name"`
+	StartsAt            string  `json:"startsAt"`
+	EndsAt              string  `json:"endsAt"`
+	Status              string  `json:"status"`
+	ResetBalances       bool    `json:"resetBalances"`
+	PositionPolicy      string  `json:"positionPolicy"`
+	CreatedBy           string  `json:"createdBy"`
+	ArchivedAt          *string `json:"archivedAt,omitempty"`
+	ArchivedBy          string  `json:"archivedBy,omitempty"`
+	PositionsLiquidated int     `json:"positionsLiquidated"`
+	PositionsCarried    int     `json:"positionsCarried"`
+	BalancesReset       int     `json:"balancesReset"`
+	CreatedAt           string  `json:"createdAt"`
+}
+
+type seasonsResponse struct {
+	Seasons []seasonResponse `json:"seasons"`
+}
+
+type standingResponse struct {
+	Rank            int    `json:"rank"`
+	Username        string `json:"username"`
+	Profit          int64  `json:"profit"`
+	Spent           int64  `json:"spent"`
+	CurrentValue    int64  `json:"currentValue"`
+	ActiveMarkets   int    `json:"activeMarkets"`
+	ResolvedMarkets int    `json:"resolvedMarkets"`
+	FinalBalance    *int64 `json:"finalBalance,omitempty"`
+}
+
+type standingsResponse struct {
+	Season    seasonResponse     `json:"season"`
+	Standings []standingResponse `json:"standings"`
+	Total     int64              `json:"total"`
+}
+
+// ListSeasonsHandler handles GET /v0/seasons.
+func ListSeasonsHandler(svc seasonService, now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		seasons, err := svc.List(r.Context())
+		if err != nil {
+			writeSeasonsError(w, err)
+			return
+		}
+		at := clock(now)
+		response := seasonsResponse{Seasons: make([]seasonResponse, 0, len(seasons))}
+		for i := range seasons {
+			response.Seasons = append(response.Seasons, seasonResponseFromDomain(&seasons[i], at))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// GetSeasonHandler handles GET /v0/seasons/{id}.
+func GetSeasonHandler(svc seasonService, now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		id, ok := seasonID(w, r)
+		if !ok {
+			return
+		}
+		season, err := svc.Get(r.Context(), id)
+		if err != nil {
+			writeSeasonsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, seasonResponseFromDomain(season, clock(now)))
+	}
+}
+
+// GetSeasonStandingsHandler handles GET /v0/seasons/{id}/standings: the
+// frozen standings of an archived season, or live standings otherwise.
+func GetSeasonStandingsHandler(svc seasonService, now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		id, ok := seasonID(w, r)
+		if !ok {
+			return
+		}
+		limit, offset, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		season, err := svc.Get(r.Context(), id)
+		if err != nil {
+			writeSeasonsError(w, err)
+			return
+		}
+		standings, total, err := svc.Standings(r.Context(), id, limit, offset)
+		if err != nil {
+			writeSeasonsError(w, err)
+			return
+		}
+		response := standingsResponse{
+			Season:    seasonResponseFromDomain(season, clock(now)),
+			Standings: make([]standingResponse, 0, len(standings)),
+			Total:     total,
+		}
+		for _, standing := range standings {
+			entry := standingResponse{
+				Rank:            standing.Rank,
+				Username:        standing.Username,
+				Profit:          standing.Profit,
+				Spent:           standing.Spent,
+				CurrentValue:    standing.CurrentValue,
+				ActiveMarkets:   standing.ActiveMarkets,
+				ResolvedMarkets: standing.ResolvedMarkets,
+			}
+			if season.ArchivedAt != nil {
+				balance := standing.FinalBalance
+				entry.FinalBalance = &balance
+			}
+			response.Standings = append(response.Standings, entry)
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// CreateSeasonHandler handles POST /v0/admin/seasons.
+func CreateSeasonHandler(svc seasonService, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		input, ok := decodeSeasonInput(w, r)
+		if !ok {
+			return
+		}
+		season, err := svc.Create(r.Context(), user.PermissionSubject(), input)
+		if err != nil {
+			writeSeasonsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusCreated, seasonResponseFromDomain(season, clock(now)))
+	}
+}
+
+// UpdateSeasonHandler handles PATCH /v0/admin/seasons/{id}. The request
+// replaces the whole definition.
+func UpdateSeasonHandler(svc seasonService, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := seasonID(w, r)
+		if !ok {
+			return
+		}
+		input, ok := decodeSeasonInput(w, r)
+		if !ok {
+			return
+		}
+		season, err := svc.Update(r.Context(), user.PermissionSubject(), id, input)
+		if err != nil {
+			writeSeasonsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, seasonResponseFromDomain(season, clock(now)))
+	}
+}
+
+// RolloverSeasonHandler handles POST /v0/admin/seasons/{id}/rollover.
+func RolloverSeasonHandler(svc seasonService, auth authsvc.Authenticator, now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := seasonID(w, r)
+		if !ok {
+			return
+		}
+		season, err := svc.Rollover(r.Context(), user.PermissionSubject(), id)
+		if err != nil {
+			writeSeasonsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, seasonResponseFromDomain(season, clock(now)))
+	}
+}
+
+func decodeSeasonInput(w http.ResponseWriter, r *http.Request) (dseasons.Input, bool) {
+	var request seasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return dseasons.Input{}, false
+	}
+	startsAt, startErr := time.Parse(time.RFC3339, strings.TrimSpace(request.StartsAt))
+	endsAt, endErr := time.Parse(time.RFC3339, strings.TrimSpace(request.EndsAt))
+	if startErr != nil || endErr != nil {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+		return dseasons.Input{}, false
+	}
+	return dseasons.Input{
+		Name:           request.Name,
+		StartsAt:       startsAt,
+		EndsAt:         endsAt,
+		ResetBalances:  request.ResetBalances,
+		PositionPolicy: dseasons.PositionPolicy(strings.ToLower(strings.TrimSpace(request.PositionPolicy))),
+	}, true
+}
+
+func currentUser(w http.ResponseWriter, r *http.Request, svc seasonService, auth authsvc.Authenticator) (*dusers.User, bool) {
+	if svc == nil || auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return nil, false
+	}
+	user, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return nil, false
+	}
+	return user, true
+}
+
+func seasonID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func parsePage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	query := r.URL.Query()
+	var limit, offset int
+	for name, target := range map[string]*int{"limit": &limit, "offset": &offset} {
+		raw := strings.TrimSpace(query.Get(name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 || value > maxSeasonsPageParam {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return 0, 0, false
+		}
+		*target = value
+	}
+	return limit, offset, true
+}
+
+func clock(now func() time.Time) time.Time {
+	if now == nil {
+		return time.Now()
+	}
+	return now()
+}
+
+func writeSeasonsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dseasons.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	case errors.Is(err, dseasons.ErrSeasonNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, dseasons.ErrSeasonOverlap), errors.Is(err, dseasons.ErrSeasonNotEnded), errors.Is(err, dseasons.ErrSeasonArchived), errors.Is(err, dseasons.ErrPositionsMoved):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	case errors.Is(err, permissions.ErrPermissionDenied):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func seasonResponseFromDomain(season *dseasons.Season, now time.Time) seasonResponse {
+	response := seasonResponse{
+		ID:                  season.ID,
+		Name:                season.Name,
+		StartsAt:            season.StartsAt.UTC().Format(time.RFC3339),
+		EndsAt:              season.EndsAt.UTC().Format(time.RFC3339),
+		Status:              string(season.Status(now)),
+		ResetBalances:       season.ResetBalances,
+		PositionPolicy:      string(season.PositionPolicy),
+		CreatedBy:           season.CreatedBy,
+		ArchivedBy:          season.ArchivedBy,
+		PositionsLiquidated: season.PositionsLiquidated,
+		PositionsCarried:    season.PositionsCarried,
+		BalancesReset:       season.BalancesReset,
+		CreatedAt:           season.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if season.ArchivedAt != nil {
+		archivedAt := season.ArchivedAt.UTC().Format(time.RFC3339)
+		response.ArchivedAt = &archivedAt
+	}
+	return response
+}
@@ -0,0 +1,162 @@
+package seasonshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+	dseasons "socialpredict/internal/domain/seasons"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+
+	"github.com/gorilla/mux"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+var (
+	seasonStart = time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	seasonEnd   = seasonStart.AddDate(0, 3, 0)
+)
+
+type seasonsMock struct {
+	season *dseasons.Season
+	input  dseasons.Input
+	err    error
+}
+
+func (m *seasonsMock) List(context.Context) ([]dseasons.Season, error) {
+	return []dseasons.Season{*m.season}, m.err
+}
+
+func (m *seasonsMock) Get(context.Context, int64) (*dseasons.Season, error) {
+	return m.season, m.err
+}
+
+func (m *seasonsMock) Standings(context.Context, int64, int, int) ([]dseasons.Standing, int64, error) {
+	return []dseasons.Standing{{Rank: 1, Username: "alice", Profit: 300, FinalBalance: 1300}}, 1, m.err
+}
+
+func (m *seasonsMock) Create(_ context.Context, actor permissions.Subject, input dseasons.Input) (*dseasons.Season, error) {
+	m.input = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dseasons.Season{ID: 1, Name: input.Name, StartsAt: input.StartsAt, EndsAt: input.EndsAt, PositionPolicy: input.PositionPolicy, CreatedBy: actor.Username}, nil
+}
+
+func (m *seasonsMock) Update(_ context.Context, _ permissions.Subject, _ int64, input dseasons.Input) (*dseasons.Season, error) {
+	m.input = input
+	return m.season, m.err
+}
+
+func (m *seasonsMock) Rollover(context.Context, permissions.Subject, int64) (*dseasons.Season, error) {
+	return m.season, m.err
+}
+
+func signedIn() authMock {
+	return authMock{user: &dusers.User{Username: "root", UserType: "ADMIN"}}
+}
+
+func serve(handler http.HandlerFunc, method, target, body string, vars map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if vars != nil {
+		req = mux.SetURLVars(req, vars)
+	}
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func fixedNow() time.Time { return seasonEnd.Add(time.Hour) }
+
+func TestCreateSeasonHandlerParsesPeriod(t *testing.T) {
+	svc := &seasonsMock{}
+	handler := CreateSeasonHandler(svc, signedIn(), fixedNow)
+
+	if rec := serve(handler, http.MethodPost, "/v0/admin/seasons", `{"name":"Q3","startsAt":"July","endsAt":"2026-10-01T00:00:00Z"}`, nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad date to be rejected, got %d", rec.Code)
+	}
+	rec := serve(handler, http.MethodPost, "/v0/admin/seasons", `{"name":"Q3","startsAt":"2026-07-01T00:00:00Z","endsAt":"2026-10-01T00:00:00Z","resetBalances":true,"positionPolicy":" Liquidate "}`, nil)
+	if rec.Code != http.StatusCreated || !svc.input.ResetBalances || svc.input.PositionPolicy != dseasons.PositionsLiquidate || !svc.input.EndsAt.Equal(seasonEnd) {
+		t.Fatalf("status = %d input=%+v body=%s", rec.Code, svc.input, rec.Body.String())
+	}
+	var decoded struct {
+		Result seasonResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil || decoded.Result.Status != "ended" || decoded.Result.CreatedBy != "root" {
+		t.Fatalf("unexpected response %+v, %v", decoded.Result, err)
+	}
+}
+
+func TestGetSeasonStandingsHandlerShowsFrozenBalances(t *testing.T) {
+	archivedAt := seasonEnd.Add(time.Minute)
+	svc := &seasonsMock{season: &dseasons.Season{ID: 1, Name: "Q3", StartsAt: seasonStart, EndsAt: seasonEnd}}
+	handler := GetSeasonStandingsHandler(svc, fixedNow)
+
+	decode := func(rec *httptest.ResponseRecorder) standingsResponse {
+		t.Helper()
+		var decoded struct {
+			Result standingsResponse `json:"result"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return decoded.Result
+	}
+	live := decode(serve(handler, http.MethodGet, "/v0/seasons/1/standings", "", map[string]string{"id": "1"}))
+	if live.Total != 1 || live.Standings[0].FinalBalance != nil || live.Season.Status != "ended" {
+		t.Fatalf("live standings should omit final balances, got %+v", live)
+	}
+
+	svc.season.ArchivedAt = &archivedAt
+	archived := decode(serve(handler, http.MethodGet, "/v0/seasons/1/standings", "", map[string]string{"id": "1"}))
+	if archived.Standings[0].FinalBalance == nil || *archived.Standings[0].FinalBalance != 1300 || archived.Season.ArchivedAt == nil {
+		t.Fatalf("archived standings should carry final balances, got %+v", archived)
+	}
+
+	if rec := serve(handler, http.MethodGet, "/v0/seasons/x/standings", "", map[string]string{"id": "x"}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad id to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestRolloverSeasonHandlerMapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "missing", err: dseasons.ErrSeasonNotFound, want: http.StatusNotFound},
+		{name: "not ended", err: dseasons.ErrSeasonNotEnded, want: http.StatusConflict},
+		{name: "archived", err: dseasons.ErrSeasonArchived, want: http.StatusConflict},
+		{name: "denied", err: permissions.ErrPermissionDenied, want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &seasonsMock{err: tt.err}
+			rec := serve(RolloverSeasonHandler(svc, signedIn(), fixedNow), http.MethodPost, "/v0/admin/seasons/1/rollover", "", map[string]string{"id": "1"})
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	missing := authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}
+	if rec := serve(RolloverSeasonHandler(&seasonsMock{}, missing, fixedNow), http.MethodPost, "/v0/admin/seasons/1/rollover", "", map[string]string{"id": "1"}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a session, got %d", rec.Code)
+	}
+}
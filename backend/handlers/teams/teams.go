@@ -0,0 +1,516 @@
+package teamshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	"socialpredict/handlers/authhttp"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dteams "socialpredict/internal/domain/teams"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+
+	"github.com/gorilla/mux"
+)
+
+const maxTeamsPageParam = 10000
+
+type teamService interface {
+	List(ctx context.Context) ([]dteams.Team, error)
+	Profile(ctx context.Context, slug string) (*dteams.Profile, error)
+	Leaderboard(ctx context.Context, limit, offset int) ([]dteams.LeaderboardEntry, int, error)
+	Create(ctx context.Context, actor permissions.Subject, input dteams.Input) (*dteams.Team, error)
+	Update(ctx context.Context, actor permissions.Subject, slug string, input dteams.Input) (*dteams.Team, error)
+	Invite(ctx context.Context, actor permissions.Subject, slug, username string) (*dteams.Invite, bool, error)
+	MyInvites(ctx context.Context, username string) ([]dteams.Invite, error)
+	AnswerInvite(ctx context.Context, username string, inviteID int64, accept bool) (*dteams.Invite, error)
+	AddMember(ctx context.Context, actor permissions.Subject, slug, username string, role dteams.Role) (*dteams.Member, error)
+	RemoveMember(ctx context.Context, actor permissions.Subject, slug, username string) error
+	RestrictMarket(ctx context.Context, actor permissions.Subject, slug string, marketID int64) error
+	UnrestrictMarket(ctx context.Context, actor permissions.Subject, slug string, marketID int64) error
+}
+
+type teamRequest struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Owner       string `json:"owner"`
+}
+
+type inviteRequest struct {
+	Username string `json:"username"`
+}
+
+type memberRequest struct {
+	Role string `json:"role"`
+}
+
+type teamResponse struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedBy   string `json:"createdBy"`
+	CreatedAt   string `json:"createdAt"`
+	MemberCount int    `json:"memberCount"`
+}
+
+type teamsResponse struct {
+	Teams []teamResponse `json:"teams"`
+}
+
+type statsResponse struct {
+	Members         int      `json:"members"`
+	AccountBalance  int64    `json:"accountBalance"`
+	Equity          int64    `json:"equity"`
+	AmountInPlay    int64    `json:"amountInPlay"`
+	TotalSpent      int64    `json:"totalSpent"`
+	TradingProfits  int64    `json:"tradingProfits"`
+	WorkProfits     int64    `json:"workProfits"`
+	TotalProfits    int64    `json:"totalProfits"`
+	ResolvedMarkets int      `json:"resolvedMarkets"`
+	BrierScore      *float64 `json:"brierScore,omitempty"`
+	LogScore        *float64 `json:"logScore,omitempty"`
+}
+
+type memberResponse struct {
+	Username        string   `json:"username"`
+	Role            string   `json:"role"`
+	JoinedAt        string   `json:"joinedAt"`
+	TotalProfits    int64    `json:"totalProfits"`
+	ResolvedMarkets int      `json:"resolvedMarkets"`
+	BrierScore      *float64 `json:"brierScore,omitempty"`
+}
+
+type profileResponse struct {
+	Team      teamResponse     `json:"team"`
+	Stats     statsResponse    `json:"stats"`
+	Members   []memberResponse `json:"members"`
+	MarketIDs []int64          `json:"marketIds"`
+}
+
+type leaderboardEntryResponse struct {
+	Rank  int           `json:"rank"`
+	Team  teamResponse  `json:"team"`
+	Stats statsResponse `json:"stats"`
+}
+
+type leaderboardResponse struct {
+	Entries []leaderboardEntryResponse `json:"entries"`
+	Total   int                        `json:"total"`
+}
+
+type inviteResponse struct {
+	ID          int64   `json:"id"`
+	TeamSlug    string  `json:"teamSlug"`
+	TeamName    string  `json:"teamName"`
+	Username    string  `json:"username"`
+	InvitedBy   string  `json:"invitedBy"`
+	Status      string  `json:"status"`
+	CreatedAt   string  `json:"createdAt"`
+	RespondedAt *string `json:"respondedAt,omitempty"`
+}
+
+type invitesResponse struct {
+	Invites []inviteResponse `json:"invites"`
+}
+
+type membershipResponse struct {
+	TeamSlug string `json:"teamSlug"`
+	Username string `json:"username"`
+	Member   bool   `json:"member"`
+	Role     string `json:"role,omitempty"`
+}
+
+type teamMarketResponse struct {
+	TeamSlug string `json:"teamSlug"`
+	MarketID int64  `json:"marketId"`
+	TeamOnly bool   `json:"teamOnly"`
+}
+
+// ListTeamsHandler handles GET /v0/teams.
+func ListTeamsHandler(svc teamService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		teams, err := svc.List(r.Context())
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		response := teamsResponse{Teams: make([]teamResponse, 0, len(teams))}
+		for i := range teams {
+			response.Teams = append(response.Teams, teamResponseFromDomain(&teams[i]))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// GetTeamHandler handles GET /v0/teams/{slug}: the team's profile with its
+// members' contributions, aggregated stats, and team-only markets.
+func GetTeamHandler(svc teamService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		profile, err := svc.Profile(r.Context(), mux.Vars(r)["slug"])
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		response := profileResponse{
+			Team:      teamResponseFromDomain(&profile.Team),
+			Stats:     statsResponseFromDomain(profile.Stats),
+			Members:   make([]memberResponse, 0, len(profile.Members)),
+			MarketIDs: profile.MarketIDs,
+		}
+		if response.MarketIDs == nil {
+			response.MarketIDs = []int64{}
+		}
+		for _, member := range profile.Members {
+			response.Members = append(response.Members, memberResponse{
+				Username:        member.Username,
+				Role:            string(member.Role),
+				JoinedAt:        member.JoinedAt.UTC().Format(time.RFC3339),
+				TotalProfits:    member.TotalProfits,
+				ResolvedMarkets: member.ResolvedMarkets,
+				BrierScore:      member.BrierScore,
+			})
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// TeamLeaderboardHandler handles GET /v0/global/leaderboard/teams.
+func TeamLeaderboardHandler(svc teamService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		limit, offset, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		entries, total, err := svc.Leaderboard(r.Context(), limit, offset)
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		response := leaderboardResponse{Entries: make([]leaderboardEntryResponse, 0, len(entries)), Total: total}
+		for i := range entries {
+			response.Entries = append(response.Entries, leaderboardEntryResponse{
+				Rank:  entries[i].Rank,
+				Team:  teamResponseFromDomain(&entries[i].Team),
+				Stats: statsResponseFromDomain(entries[i].Stats),
+			})
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// CreateTeamHandler handles POST /v0/admin/teams.
+func CreateTeamHandler(svc teamService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request teamRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		team, err := svc.Create(r.Context(), user.PermissionSubject(), dteams.Input{
+			Slug:        request.Slug,
+			Name:        request.Name,
+			Description: request.Description,
+			Owner:       request.Owner,
+		})
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusCreated, teamResponseFromDomain(team))
+	}
+}
+
+// UpdateTeamHandler handles PATCH /v0/teams/{slug}. The request replaces the
+// name and description; the slug never changes.
+func UpdateTeamHandler(svc teamService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request teamRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		team, err := svc.Update(r.Context(), user.PermissionSubject(), mux.Vars(r)["slug"], dteams.Input{
+			Name:        request.Name,
+			Description: request.Description,
+		})
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, teamResponseFromDomain(team))
+	}
+}
+
+// InviteMemberHandler handles POST /v0/teams/{slug}/invites. It answers 201
+// for a new invitation and 200 when one is already pending.
+func InviteMemberHandler(svc teamService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request inviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		invite, created, err := svc.Invite(r.Context(), user.PermissionSubject(), mux.Vars(r)["slug"], request.Username)
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		_ = handlers.WriteResult(w, status, inviteResponseFromDomain(invite))
+	}
+}
+
+// MyInvitesHandler handles GET /v0/profile/team-invites.
+func MyInvitesHandler(svc teamService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		invites, err := svc.MyInvites(r.Context(), user.Username)
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		response := invitesResponse{Invites: make([]inviteResponse, 0, len(invites))}
+		for i := range invites {
+			response.Invites = append(response.Invites, inviteResponseFromDomain(&invites[i]))
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+// AnswerInviteHandler handles POST /v0/team-invites/{id}/accept and
+// POST /v0/team-invites/{id}/decline.
+func AnswerInviteHandler(svc teamService, auth authsvc.Authenticator, accept bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		invite, err := svc.AnswerInvite(r.Context(), user.Username, id, accept)
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, inviteResponseFromDomain(invite))
+	}
+}
+
+// AddMemberHandler handles PUT /v0/admin/teams/{slug}/members/{username}.
+func AddMemberHandler(svc teamService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		var request memberRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+				return
+			}
+		}
+		vars := mux.Vars(r)
+		role := dteams.Role(strings.ToLower(strings.TrimSpace(request.Role)))
+		member, err := svc.AddMember(r.Context(), user.PermissionSubject(), vars["slug"], vars["username"], role)
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, membershipResponse{
+			TeamSlug: strings.ToLower(strings.TrimSpace(vars["slug"])),
+			Username: member.Username,
+			Member:   true,
+			Role:     string(member.Role),
+		})
+	}
+}
+
+// RemoveMemberHandler handles DELETE /v0/teams/{slug}/members/{username}.
+// Members remove themselves to leave.
+func RemoveMemberHandler(svc teamService, auth authsvc.Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		if err := svc.RemoveMember(r.Context(), user.PermissionSubject(), vars["slug"], vars["username"]); err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, membershipResponse{
+			TeamSlug: strings.ToLower(strings.TrimSpace(vars["slug"])),
+			Username: vars["username"],
+			Member:   false,
+		})
+	}
+}
+
+// TeamMarketHandler handles PUT and DELETE on
+// /v0/teams/{slug}/markets/{marketId}: restrict is true for PUT, making the
+// market team-only, and false for DELETE, opening it again.
+func TeamMarketHandler(svc teamService, auth authsvc.Authenticator, restrict bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUser(w, r, svc, auth)
+		if !ok {
+			return
+		}
+		marketID, ok := pathID(w, r, "marketId")
+		if !ok {
+			return
+		}
+		slug := strings.ToLower(strings.TrimSpace(mux.Vars(r)["slug"]))
+		var err error
+		if restrict {
+			err = svc.RestrictMarket(r.Context(), user.PermissionSubject(), slug, marketID)
+		} else {
+			err = svc.UnrestrictMarket(r.Context(), user.PermissionSubject(), slug, marketID)
+		}
+		if err != nil {
+			writeTeamsError(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, teamMarketResponse{TeamSlug: slug, MarketID: marketID, TeamOnly: restrict})
+	}
+}
+
+func currentUser(w http.ResponseWriter, r *http.Request, svc teamService, auth authsvc.Authenticator) (*dusers.User, bool) {
+	if svc == nil || auth == nil {
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return nil, false
+	}
+	user, authErr := auth.CurrentUser(r)
+	if authErr != nil {
+		_ = authhttp.WriteFailure(w, authErr)
+		return nil, false
+	}
+	return user, true
+}
+
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)[name], 10, 64)
+	if err != nil || id <= 0 {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func parsePage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	query := r.URL.Query()
+	var limit, offset int
+	for name, target := range map[string]*int{"limit": &limit, "offset": &offset} {
+		raw := strings.TrimSpace(query.Get(name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 || value > maxTeamsPageParam {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return 0, 0, false
+		}
+		*target = value
+	}
+	return limit, offset, true
+}
+
+func writeTeamsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dteams.ErrInvalidInput):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	case errors.Is(err, dteams.ErrTeamNotFound), errors.Is(err, dteams.ErrNotMember), errors.Is(err, dteams.ErrInviteNotFound),
+		errors.Is(err, dusers.ErrUserNotFound), errors.Is(err, dmarkets.ErrMarketNotFound):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, dteams.ErrSlugTaken), errors.Is(err, dteams.ErrAlreadyInTeam), errors.Is(err, dteams.ErrInvalidState):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonInvalidState)
+	case errors.Is(err, permissions.ErrPermissionDenied):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func teamResponseFromDomain(team *dteams.Team) teamResponse {
+	return teamResponse{
+		Slug:        team.Slug,
+		Name:        team.Name,
+		Description: team.Description,
+		CreatedBy:   team.CreatedBy,
+		CreatedAt:   team.CreatedAt.UTC().Format(time.RFC3339),
+		MemberCount: team.MemberCount,
+	}
+}
+
+func statsResponseFromDomain(stats dteams.Stats) statsResponse {
+	return statsResponse{
+		Members:         stats.Members,
+		AccountBalance:  stats.AccountBalance,
+		Equity:          stats.Equity,
+		AmountInPlay:    stats.AmountInPlay,
+		TotalSpent:      stats.TotalSpent,
+		TradingProfits:  stats.TradingProfits,
+		WorkProfits:     stats.WorkProfits,
+		TotalProfits:    stats.TotalProfits,
+		ResolvedMarkets: stats.ResolvedMarkets,
+		BrierScore:      stats.BrierScore,
+		LogScore:        stats.LogScore,
+	}
+}
+
+func inviteResponseFromDomain(invite *dteams.Invite) inviteResponse {
+	response := inviteResponse{
+		ID:        invite.ID,
+		TeamSlug:  invite.TeamSlug,
+		TeamName:  invite.TeamName,
+		Username:  invite.Username,
+		InvitedBy: invite.InvitedBy,
+		Status:    string(invite.Status),
+		CreatedAt: invite.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if invite.RespondedAt != nil {
+		respondedAt := invite.RespondedAt.UTC().Format(time.RFC3339)
+		response.RespondedAt = &respondedAt
+	}
+	return response
+}
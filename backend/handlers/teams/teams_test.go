@@ -0,0 +1,252 @@
+package teamshandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dteams "socialpredict/internal/domain/teams"
+	dusers "socialpredict/internal/domain/users"
+	authsvc "socialpredict/internal/service/auth"
+
+	"github.com/gorilla/mux"
+)
+
+type authMock struct {
+	user *dusers.User
+	err  *authsvc.AuthError
+}
+
+func (m authMock) CurrentUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireUser(*http.Request) (*dusers.User, *authsvc.AuthError) { return m.user, m.err }
+func (m authMock) RequireAdmin(*http.Request) (*dusers.User, *authsvc.AuthError) {
+	return m.user, m.err
+}
+
+var created = time.Date(2026, 7, 7, 9, 0, 0, 0, time.UTC)
+
+type teamsMock struct {
+	team     dteams.Team
+	existing bool
+	accepted *bool
+	role     dteams.Role
+	restrict *bool
+	err      error
+}
+
+func (m *teamsMock) List(context.Context) ([]dteams.Team, error) {
+	return []dteams.Team{m.team}, m.err
+}
+
+func (m *teamsMock) Profile(context.Context, string) (*dteams.Profile, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	brier := 0.2
+	return &dteams.Profile{
+		Team:    m.team,
+		Members: []dteams.MemberStats{{Member: dteams.Member{Username: "alice", Role: dteams.RoleOwner, JoinedAt: created}, TotalProfits: 300, ResolvedMarkets: 3, BrierScore: &brier}},
+		Stats:   dteams.Stats{Members: 1, TotalProfits: 300, ResolvedMarkets: 3, BrierScore: &brier},
+	}, nil
+}
+
+func (m *teamsMock) Leaderboard(_ context.Context, limit, offset int) ([]dteams.LeaderboardEntry, int, error) {
+	return []dteams.LeaderboardEntry{{Rank: offset + 1, Team: m.team, Stats: dteams.Stats{TotalProfits: 300}}}, 4, m.err
+}
+
+func (m *teamsMock) Create(_ context.Context, actor permissions.Subject, input dteams.Input) (*dteams.Team, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dteams.Team{Slug: "risk", Name: input.Name, CreatedBy: actor.Username, CreatedAt: created, MemberCount: 1}, nil
+}
+
+func (m *teamsMock) Update(context.Context, permissions.Subject, string, dteams.Input) (*dteams.Team, error) {
+	return &m.team, m.err
+}
+
+func (m *teamsMock) Invite(_ context.Context, actor permissions.Subject, slug, username string) (*dteams.Invite, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	return &dteams.Invite{ID: 5, TeamSlug: slug, Username: username, InvitedBy: actor.Username, Status: dteams.InvitePending, CreatedAt: created}, !m.existing, nil
+}
+
+func (m *teamsMock) MyInvites(context.Context, string) ([]dteams.Invite, error) {
+	return []dteams.Invite{}, m.err
+}
+
+func (m *teamsMock) AnswerInvite(_ context.Context, username string, inviteID int64, accept bool) (*dteams.Invite, error) {
+	m.accepted = &accept
+	if m.err != nil {
+		return nil, m.err
+	}
+	status := dteams.InviteDeclined
+	if accept {
+		status = dteams.InviteAccepted
+	}
+	return &dteams.Invite{ID: inviteID, Username: username, Status: status, CreatedAt: created, RespondedAt: &created}, nil
+}
+
+func (m *teamsMock) AddMember(_ context.Context, _ permissions.Subject, slug, username string, role dteams.Role) (*dteams.Member, error) {
+	m.role = role
+	if m.err != nil {
+		return nil, m.err
+	}
+	if role == "" {
+		role = dteams.RoleMember
+	}
+	return &dteams.Member{Username: username, Role: role, JoinedAt: created}, nil
+}
+
+func (m *teamsMock) RemoveMember(context.Context, permissions.Subject, string, string) error {
+	return m.err
+}
+
+func (m *teamsMock) RestrictMarket(context.Context, permissions.Subject, string, int64) error {
+	restrict := true
+	m.restrict = &restrict
+	return m.err
+}
+
+func (m *teamsMock) UnrestrictMarket(context.Context, permissions.Subject, string, int64) error {
+	restrict := false
+	m.restrict = &restrict
+	return m.err
+}
+
+func signedIn() authMock {
+	return authMock{user: &dusers.User{Username: "alice", UserType: "REGULAR"}}
+}
+
+func serve(handler http.HandlerFunc, method, target, body string, vars map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if vars != nil {
+		req = mux.SetURLVars(req, vars)
+	}
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetTeamHandlerReturnsProfile(t *testing.T) {
+	svc := &teamsMock{team: dteams.Team{Slug: "risk", Name: "Risk", CreatedBy: "root", CreatedAt: created, MemberCount: 1}}
+	rec := serve(GetTeamHandler(svc), http.MethodGet, "/v0/teams/risk", "", map[string]string{"slug": "risk"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var decoded struct {
+		Result profileResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	result := decoded.Result
+	if result.Team.Slug != "risk" || result.Stats.TotalProfits != 300 || result.Stats.BrierScore == nil || result.MarketIDs == nil {
+		t.Fatalf("unexpected profile %+v", result)
+	}
+	if len(result.Members) != 1 || result.Members[0].Role != "owner" || result.Members[0].JoinedAt != "2026-07-07T09:00:00Z" {
+		t.Fatalf("unexpected members %+v", result.Members)
+	}
+
+	svc.err = dteams.ErrTeamNotFound
+	if rec := serve(GetTeamHandler(svc), http.MethodGet, "/v0/teams/nope", "", map[string]string{"slug": "nope"}); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestTeamLeaderboardHandlerPages(t *testing.T) {
+	svc := &teamsMock{team: dteams.Team{Slug: "risk", Name: "Risk", CreatedAt: created}}
+	if rec := serve(TeamLeaderboardHandler(svc), http.MethodGet, "/v0/global/leaderboard/teams?limit=-1", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad limit to be rejected, got %d", rec.Code)
+	}
+	rec := serve(TeamLeaderboardHandler(svc), http.MethodGet, "/v0/global/leaderboard/teams?limit=1&offset=2", "", nil)
+	var decoded struct {
+		Result leaderboardResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, decode: %v", rec.Code, err)
+	}
+	if decoded.Result.Total != 4 || len(decoded.Result.Entries) != 1 || decoded.Result.Entries[0].Rank != 3 {
+		t.Fatalf("unexpected leaderboard %+v", decoded.Result)
+	}
+}
+
+func TestInviteAndAnswerHandlers(t *testing.T) {
+	svc := &teamsMock{}
+	invite := InviteMemberHandler(svc, signedIn())
+	if rec := serve(invite, http.MethodPost, "/v0/teams/risk/invites", `{"username":"bob"}`, map[string]string{"slug": "risk"}); rec.Code != http.StatusCreated {
+		t.Fatalf("new invite status = %d", rec.Code)
+	}
+	svc.existing = true
+	if rec := serve(invite, http.MethodPost, "/v0/teams/risk/invites", `{"username":"bob"}`, map[string]string{"slug": "risk"}); rec.Code != http.StatusOK {
+		t.Fatalf("pending invite status = %d", rec.Code)
+	}
+
+	decline := AnswerInviteHandler(svc, signedIn(), false)
+	rec := serve(decline, http.MethodPost, "/v0/team-invites/5/decline", "", map[string]string{"id": "5"})
+	if rec.Code != http.StatusOK || svc.accepted == nil || *svc.accepted {
+		t.Fatalf("decline status = %d accepted=%v", rec.Code, svc.accepted)
+	}
+	if rec := serve(decline, http.MethodPost, "/v0/team-invites/x/decline", "", map[string]string{"id": "x"}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad id to be rejected, got %d", rec.Code)
+	}
+
+	missing := authMock{err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing"}}
+	if rec := serve(AnswerInviteHandler(svc, missing, true), http.MethodPost, "/v0/team-invites/5/accept", "", map[string]string{"id": "5"}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a session, got %d", rec.Code)
+	}
+}
+
+func TestAddMemberHandlerDefaultsRole(t *testing.T) {
+	svc := &teamsMock{}
+	vars := map[string]string{"slug": "Risk", "username": "bob"}
+	rec := serve(AddMemberHandler(svc, signedIn()), http.MethodPut, "/v0/admin/teams/Risk/members/bob", "", vars)
+	if rec.Code != http.StatusOK || svc.role != "" {
+		t.Fatalf("status = %d role=%q", rec.Code, svc.role)
+	}
+	rec = serve(AddMemberHandler(svc, signedIn()), http.MethodPut, "/v0/admin/teams/Risk/members/bob", `{"role":" Owner "}`, vars)
+	var decoded struct {
+		Result membershipResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil || svc.role != dteams.RoleOwner || decoded.Result.TeamSlug != "risk" || !decoded.Result.Member {
+		t.Fatalf("unexpected response %+v role=%q err=%v", decoded.Result, svc.role, err)
+	}
+}
+
+func TestTeamHandlersMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "invalid", err: dteams.ErrInvalidInput, want: http.StatusBadRequest},
+		{name: "missing market", err: dmarkets.ErrMarketNotFound, want: http.StatusNotFound},
+		{name: "missing user", err: dusers.ErrUserNotFound, want: http.StatusNotFound},
+		{name: "already in team", err: dteams.ErrAlreadyInTeam, want: http.StatusConflict},
+		{name: "outside bets", err: dteams.ErrInvalidState, want: http.StatusConflict},
+		{name: "denied", err: permissions.ErrPermissionDenied, want: http.StatusForbidden},
+	}
+	vars := map[string]string{"slug": "risk", "marketId": "7"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &teamsMock{err: tt.err}
+			rec := serve(TeamMarketHandler(svc, signedIn(), true), http.MethodPut, "/v0/teams/risk/markets/7", "", vars)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	svc := &teamsMock{}
+	rec := serve(TeamMarketHandler(svc, signedIn(), false), http.MethodDelete, "/v0/teams/risk/markets/7", "", vars)
+	if rec.Code != http.StatusOK || svc.restrict == nil || *svc.restrict {
+		t.Fatalf("unrestrict status = %d restrict=%v", rec.Code, svc.restrict)
+	}
+}
@@ -31,22 +31,25 @@ type ChangePasswordRequest struct {
 
 // PrivateUserResponse represents the shape returned by profile mutation endpoints.
 type PrivateUserResponse struct {
-	ID                    int64  `json:"id"`
-	Username              string `json:"username"`
-	DisplayName           string `json:"displayname"`
-	UserType              string `json:"usertype"`
-	ModeratorStatus       string `json:"moderatorStatus"`
-	InitialAccountBalance int64  `json:"initialAccountBalance"`
-	AccountBalance        int64  `json:"accountBalance"`
-	PersonalEmoji         string `json:"personalEmoji,omitempty"`
-	Description           string `json:"description,omitempty"`
-	PersonalLink1         string `json:"personalink1,omitempty"`
-	PersonalLink2         string `json:"personalink2,omitempty"`
-	PersonalLink3         string `json:"personalink3,omitempty"`
-	PersonalLink4         string `json:"personalink4,omitempty"`
-	Email                 string `json:"email"`
-	APIKey                string `json:"apiKey,omitempty"`
-	MustChangePassword    bool   `json:"mustChangePassword"`
+	ID                     int64  `json:"id"`
+	Username               string `json:"username"`
+	DisplayName            string `json:"displayname"`
+	UserType               string `json:"usertype"`
+	ModeratorStatus        string `json:"moderatorStatus"`
+	InitialAccountBalance  int64  `json:"initialAccountBalance"`
+	AccountBalance         int64  `json:"accountBalance"`
+	PersonalEmoji          string `json:"personalEmoji,omitempty"`
+	Description            string `json:"description,omitempty"`
+	PersonalLink1          string `json:"personalink1,omitempty"`
+	PersonalLink2          string `json:"personalink2,omitempty"`
+	PersonalLink3          string `json:"personalink3,omitempty"`
+	PersonalLink4          string `json:"personalink4,omitempty"`
+	Email                  string `json:"email"`
+	APIKey                 string `json:"apiKey,omitempty"`
+	MustChangePassword     bool   `json:"mustChangePassword"`
+	AccountStatus          string `json:"accountStatus"`
+	AccountStatusReason    string `json:"accountStatusReason,omitempty"`
+	AccountStatusExpiresAt string `json:"accountStatusExpiresAt,omitempty"`
 }
 
 // ErrorResponse represents an error payload returned by profile endpoints.
@@ -7,11 +7,13 @@ import (
 	"github.com/gorilla/mux"
 
 	"socialpredict/handlers"
+	privacyhandlers "socialpredict/handlers/privacy"
 	dusers "socialpredict/internal/domain/users"
 )
 
 // GetUserFinancialHandler returns an HTTP handler that responds with comprehensive user financials.
-func GetUserFinancialHandler(svc dusers.ServiceInterface) http.HandlerFunc {
+// Hidden portfolios are forbidden to everyone but their owner and admins.
+func GetUserFinancialHandler(svc dusers.ServiceInterface, guard *privacyhandlers.Guard) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -23,6 +25,10 @@ func GetUserFinancialHandler(svc dusers.ServiceInterface) http.HandlerFunc {
 			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
 			return
 		}
+		if err := guard.CheckPortfolio(r, username); err != nil {
+			privacyhandlers.WriteError(w, "UserFinancial", err)
+			return
+		}
 
 		snapshot, err := svc.GetUserFinancials(r.Context(), username)
 		if err != nil {
@@ -0,0 +1,127 @@
+package usershandlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"socialpredict/handlers"
+	privacyhandlers "socialpredict/handlers/privacy"
+	analytics "socialpredict/internal/domain/analytics"
+	"socialpredict/logger"
+)
+
+// UserFinancialHistoryService reads a user's equity history.
+type UserFinancialHistoryService interface {
+	GetUserFinancialHistory(ctx context.Context, username string, from, to time.Time, interval analytics.HistoryInterval, now time.Time) ([]analytics.UserFinancialHistoryPoint, error)
+}
+
+type financialHistoryPointResponse struct {
+	At                string `json:"at"`
+	AccountBalance    int64  `json:"accountBalance"`
+	Equity            int64  `json:"equity"`
+	AmountInPlay      int64  `json:"amountInPlay"`
+	RealizedProfits   int64  `json:"realizedProfits"`
+	UnrealizedProfits int64  `json:"unrealizedProfits"`
+}
+
+type financialHistoryResponse struct {
+	Username string                          `json:"username"`
+	Interval string                          `json:"interval"`
+	From     string                          `json:"from"`
+	To       string                          `json:"to"`
+	Points   []financialHistoryPointResponse `json:"points"`
+}
+
+// GetUserFinancialHistoryHandler handles
+// GET /v0/users/{username}/financial/history?from=&to=&interval=. Bounds are
+// RFC3339; to defaults to now and from to thirty days before to. Each point
+// is the last sample taken in its hour, day or week. Hidden portfolios are
+// forbidden to everyone but their owner and admins.
+func GetUserFinancialHistoryHandler(svc UserFinancialHistoryService, guard *privacyhandlers.Guard, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if svc == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		username := mux.Vars(r)["username"]
+		if username == "" {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		query := r.URL.Query()
+		from, fromErr := parseHistoryBound(query.Get("from"))
+		to, toErr := parseHistoryBound(query.Get("to"))
+		if fromErr != nil || toErr != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+		interval, err := analytics.ParseHistoryInterval(query.Get("interval"))
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+			return
+		}
+
+		if err := guard.CheckPortfolio(r, username); err != nil {
+			privacyhandlers.WriteError(w, "FinancialHistory", err)
+			return
+		}
+
+		current := now().UTC()
+		if to.IsZero() {
+			to = current
+		}
+		if from.IsZero() {
+			from = to.Add(-analytics.UserFinancialHistoryDefaultRange)
+		}
+		points, err := svc.GetUserFinancialHistory(r.Context(), username, from, to, interval, current)
+		if err != nil {
+			if errors.Is(err, analytics.ErrInvalidHistoryRange) {
+				_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+				return
+			}
+			logger.LogError("FinancialHistory", "GetUserFinancialHistory", err)
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		response := financialHistoryResponse{
+			Username: username,
+			Interval: string(interval),
+			From:     from.UTC().Format(time.RFC3339),
+			To:       to.UTC().Format(time.RFC3339),
+			Points:   make([]financialHistoryPointResponse, 0, len(points)),
+		}
+		for _, point := range points {
+			response.Points = append(response.Points, financialHistoryPointResponse{
+				At:                point.At.UTC().Format(time.RFC3339),
+				AccountBalance:    point.AccountBalance,
+				Equity:            point.Equity,
+				AmountInPlay:      point.AmountInPlay,
+				RealizedProfits:   point.RealizedProfits,
+				UnrealizedProfits: point.UnrealizedProfits,
+			})
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, response)
+	}
+}
+
+func parseHistoryBound(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
@@ -0,0 +1,81 @@
+package usershandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	analytics "socialpredict/internal/domain/analytics"
+)
+
+type financialHistoryServiceMock struct {
+	from, to time.Time
+	interval analytics.HistoryInterval
+	err      error
+}
+
+func (m *financialHistoryServiceMock) GetUserFinancialHistory(_ context.Context, username string, from, to time.Time, interval analytics.HistoryInterval, _ time.Time) ([]analytics.UserFinancialHistoryPoint, error) {
+	m.from, m.to, m.interval = from, to, interval
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []analytics.UserFinancialHistoryPoint{{Username: username, At: from.Add(time.Hour), Equity: 520, UnrealizedProfits: 20}}, nil
+}
+
+func serveFinancialHistory(svc UserFinancialHistoryService, target string, now time.Time) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req = mux.SetURLVars(req, map[string]string{"username": "alice"})
+	rec := httptest.NewRecorder()
+	GetUserFinancialHistoryHandler(svc, nil, func() time.Time { return now }).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetUserFinancialHistoryHandlerDefaultsRange(t *testing.T) {
+	now := time.Date(2026, 7, 8, 9, 30, 0, 0, time.UTC)
+	svc := &financialHistoryServiceMock{}
+	rec := serveFinancialHistory(svc, "/v0/users/alice/financial/history", now)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if !svc.to.Equal(now) || !svc.from.Equal(now.AddDate(0, 0, -30)) || svc.interval != analytics.HistoryIntervalDay {
+		t.Fatalf("service called with from=%s to=%s interval=%s", svc.from, svc.to, svc.interval)
+	}
+	var decoded struct {
+		Result financialHistoryResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	result := decoded.Result
+	if result.Interval != "day" || result.To != "2026-07-08T09:30:00Z" || len(result.Points) != 1 {
+		t.Fatalf("unexpected response %+v", result)
+	}
+	if point := result.Points[0]; point.Equity != 520 || point.UnrealizedProfits != 20 || point.At != "2026-06-08T10:30:00Z" {
+		t.Fatalf("unexpected point %+v", point)
+	}
+}
+
+func TestGetUserFinancialHistoryHandlerRejectsBadQueries(t *testing.T) {
+	now := time.Date(2026, 7, 8, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		target string
+		err    error
+	}{
+		{name: "bad from", target: "/v0/users/alice/financial/history?from=yesterday"},
+		{name: "bad interval", target: "/v0/users/alice/financial/history?interval=month"},
+		{name: "bad range", target: "/v0/users/alice/financial/history?from=2026-07-08T00:00:00Z&to=2026-07-01T00:00:00Z", err: analytics.ErrInvalidHistoryRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serveFinancialHistory(&financialHistoryServiceMock{err: tt.err}, tt.target, now); rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rec.Code)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"socialpredict/handlers"
 	"socialpredict/handlers/authhttp"
+	privacyhandlers "socialpredict/handlers/privacy"
 	analytics "socialpredict/internal/domain/analytics"
 	authsvc "socialpredict/internal/service/auth"
 )
@@ -33,8 +34,9 @@ type userFinancialReadModelResponse struct {
 
 // GetUserFinancialReadModelHandler returns authenticated game-transparency
 // financial read models. Any logged-in user may view another user's game
-// financial summary, but logged-out visitors cannot.
-func GetUserFinancialReadModelHandler(svc UserFinancialReadModelService, auth authsvc.Authenticator) http.HandlerFunc {
+// financial summary unless that user hid their portfolio, and logged-out
+// visitors cannot.
+func GetUserFinancialReadModelHandler(svc UserFinancialReadModelService, auth authsvc.Authenticator, guard *privacyhandlers.Guard) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -54,6 +56,10 @@ func GetUserFinancialReadModelHandler(svc UserFinancialReadModelService, auth au
 			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
 			return
 		}
+		if err := guard.CheckPortfolio(r, username); err != nil {
+			privacyhandlers.WriteError(w, "UserFinancialReadModel", err)
+			return
+		}
 
 		readModel, err := svc.GetUserFinancialMetricReadModel(r.Context(), username)
 		if err != nil {
@@ -11,9 +11,14 @@ import (
 	"github.com/gorilla/mux"
 
 	"socialpredict/handlers"
+	privacyhandlers "socialpredict/handlers/privacy"
 	analytics "socialpredict/internal/domain/analytics"
+	dprivacy "socialpredict/internal/domain/privacy"
 	dusers "socialpredict/internal/domain/users"
+	rprivacy "socialpredict/internal/repository/privacy"
 	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
 )
 
 type financialReadModelServiceMock struct {
@@ -50,7 +55,7 @@ func (m financialReadModelAuthMock) RequireAdmin(*http.Request) (*dusers.User, *
 func TestGetUserFinancialReadModelHandlerRequiresLogin(t *testing.T) {
 	handler := GetUserFinancialReadModelHandler(&financialReadModelServiceMock{}, financialReadModelAuthMock{
 		err: &authsvc.AuthError{Kind: authsvc.ErrorKindMissingToken, Message: "missing token"},
-	})
+	}, nil)
 	req := httptest.NewRequest(http.MethodGet, "/v0/read/users/alice/financial-summary", nil)
 	req = mux.SetURLVars(req, map[string]string{"username": "alice"})
 	rec := httptest.NewRecorder()
@@ -83,7 +88,7 @@ func TestGetUserFinancialReadModelHandlerReturnsFreshness(t *testing.T) {
 			Snapshot:  snapshot,
 			Freshness: snapshot.Freshness(),
 		},
-	}, financialReadModelAuthMock{})
+	}, financialReadModelAuthMock{}, nil)
 	req := httptest.NewRequest(http.MethodGet, "/v0/read/users/alice/financial-summary", nil)
 	req = mux.SetURLVars(req, map[string]string{"username": "alice"})
 	rec := httptest.NewRecorder()
@@ -116,3 +121,33 @@ func TestGetUserFinancialReadModelHandlerReturnsFreshness(t *testing.T) {
 		t.Fatalf("freshness should not be transaction safe")
 	}
 }
+
+func TestGetUserFinancialReadModelHandlerHonoursHiddenPortfolio(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	for _, username := range []string{"alice", "viewer"} {
+		if err := db.Create(&models.TradingPrivacySetting{Username: username, HidePortfolio: true}).Error; err != nil {
+			t.Fatalf("create privacy setting: %v", err)
+		}
+	}
+	auth := financialReadModelAuthMock{}
+	guard := privacyhandlers.NewGuard(dprivacy.NewService(rprivacy.NewGormRepository(db), nil, nil), auth)
+	svc := &financialReadModelServiceMock{readModel: &analytics.UserFinancialMetricReadModel{
+		Snapshot: analytics.UserFinancialMetricSnapshot{Username: "viewer"},
+	}}
+	handler := GetUserFinancialReadModelHandler(svc, auth, guard)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/read/users/alice/financial-summary", nil), map[string]string{"username": "alice"})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected another user's hidden summary to be forbidden, got %d: %s", rec.Code, rec.Body.String())
+	}
+	requireFinancialFailureReason(t, rec, handlers.ReasonAuthorizationDenied)
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/read/users/viewer/financial-summary", nil), map[string]string{"username": "viewer"})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the owner to see their hidden summary, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
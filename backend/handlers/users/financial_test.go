@@ -11,7 +11,12 @@ import (
 	"github.com/gorilla/mux"
 
 	"socialpredict/handlers"
+	privacyhandlers "socialpredict/handlers/privacy"
+	dprivacy "socialpredict/internal/domain/privacy"
 	dusers "socialpredict/internal/domain/users"
+	rprivacy "socialpredict/internal/repository/privacy"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
 )
 
 type financialServiceMock struct {
@@ -76,7 +81,7 @@ func (m *financialServiceMock) ChangePassword(context.Context, string, string, s
 
 func TestGetUserFinancialHandlerSuccess(t *testing.T) {
 	mock := &financialServiceMock{snapshot: map[string]int64{"accountBalance": 500}}
-	handler := GetUserFinancialHandler(mock)
+	handler := GetUserFinancialHandler(mock, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/users/alice/financial", nil)
 	req = mux.SetURLVars(req, map[string]string{"username": "alice"})
@@ -99,7 +104,7 @@ func TestGetUserFinancialHandlerSuccess(t *testing.T) {
 }
 
 func TestGetUserFinancialHandlerUserNotFound(t *testing.T) {
-	handler := GetUserFinancialHandler(&financialServiceMock{err: dusers.ErrUserNotFound})
+	handler := GetUserFinancialHandler(&financialServiceMock{err: dusers.ErrUserNotFound}, nil)
 	req := httptest.NewRequest(http.MethodGet, "/v0/users/missing/financial", nil)
 	req = mux.SetURLVars(req, map[string]string{"username": "missing"})
 	rec := httptest.NewRecorder()
@@ -113,7 +118,7 @@ func TestGetUserFinancialHandlerUserNotFound(t *testing.T) {
 }
 
 func TestGetUserFinancialHandlerInternalError(t *testing.T) {
-	handler := GetUserFinancialHandler(&financialServiceMock{err: errors.New("boom")})
+	handler := GetUserFinancialHandler(&financialServiceMock{err: errors.New("boom")}, nil)
 	req := httptest.NewRequest(http.MethodGet, "/v0/users/alice/financial", nil)
 	req = mux.SetURLVars(req, map[string]string{"username": "alice"})
 	rec := httptest.NewRecorder()
@@ -127,7 +132,7 @@ func TestGetUserFinancialHandlerInternalError(t *testing.T) {
 }
 
 func TestGetUserFinancialHandlerInvalidMethod(t *testing.T) {
-	handler := GetUserFinancialHandler(&financialServiceMock{})
+	handler := GetUserFinancialHandler(&financialServiceMock{}, nil)
 	req := httptest.NewRequest(http.MethodPost, "/v0/users/alice/financial", nil)
 	rec := httptest.NewRecorder()
 
@@ -139,6 +144,31 @@ func TestGetUserFinancialHandlerInvalidMethod(t *testing.T) {
 	requireFinancialFailureReason(t, rec, handlers.ReasonMethodNotAllowed)
 }
 
+func TestGetUserFinancialHandlerHonoursHiddenPortfolio(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	if err := db.Create(&models.TradingPrivacySetting{Username: "viewer", HidePortfolio: true}).Error; err != nil {
+		t.Fatalf("create privacy setting: %v", err)
+	}
+	privacy := dprivacy.NewService(rprivacy.NewGormRepository(db), nil, nil)
+	svc := &financialServiceMock{snapshot: map[string]int64{"accountBalance": 500}}
+	request := func() *http.Request {
+		return mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/v0/users/viewer/financial", nil), map[string]string{"username": "viewer"})
+	}
+
+	rec := httptest.NewRecorder()
+	GetUserFinancialHandler(svc, privacyhandlers.NewGuard(privacy, nil)).ServeHTTP(rec, request())
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a visitor to be refused a hidden portfolio, got %d: %s", rec.Code, rec.Body.String())
+	}
+	requireFinancialFailureReason(t, rec, handlers.ReasonAuthorizationDenied)
+
+	rec = httptest.NewRecorder()
+	GetUserFinancialHandler(svc, privacyhandlers.NewGuard(privacy, financialReadModelAuthMock{})).ServeHTTP(rec, request())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the owner to see their hidden financials, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
 func requireFinancialFailureReason(t *testing.T, rec *httptest.ResponseRecorder, reason handlers.FailureReason) {
 	t.Helper()
 
@@ -2,6 +2,7 @@ package privateuser
 
 import (
 	"net/http"
+	"time"
 
 	"socialpredict/handlers"
 	"socialpredict/handlers/authhttp"
@@ -37,21 +38,31 @@ func privateProfileResponse(profile *dusers.PrivateProfile) dto.PrivateUserRespo
 	}
 
 	return dto.PrivateUserResponse{
-		ID:                    profile.ID,
-		Username:              profile.Username,
-		DisplayName:           profile.DisplayName,
-		UserType:              profile.UserType,
-		ModeratorStatus:       string(profile.ModeratorStatus),
-		InitialAccountBalance: profile.InitialAccountBalance,
-		AccountBalance:        profile.AccountBalance,
-		PersonalEmoji:         profile.PersonalEmoji,
-		Description:           profile.Description,
-		PersonalLink1:         profile.PersonalLink1,
-		PersonalLink2:         profile.PersonalLink2,
-		PersonalLink3:         profile.PersonalLink3,
-		PersonalLink4:         profile.PersonalLink4,
-		Email:                 profile.Email,
-		APIKey:                profile.APIKey,
-		MustChangePassword:    profile.MustChangePassword,
+		ID:                     profile.ID,
+		Username:               profile.Username,
+		DisplayName:            profile.DisplayName,
+		UserType:               profile.UserType,
+		ModeratorStatus:        string(profile.ModeratorStatus),
+		InitialAccountBalance:  profile.InitialAccountBalance,
+		AccountBalance:         profile.AccountBalance,
+		PersonalEmoji:          profile.PersonalEmoji,
+		Description:            profile.Description,
+		PersonalLink1:          profile.PersonalLink1,
+		PersonalLink2:          profile.PersonalLink2,
+		PersonalLink3:          profile.PersonalLink3,
+		PersonalLink4:          profile.PersonalLink4,
+		Email:                  profile.Email,
+		APIKey:                 profile.APIKey,
+		MustChangePassword:     profile.MustChangePassword,
+		AccountStatus:          string(profile.AccountStatus),
+		AccountStatusReason:    profile.AccountStatusReason,
+		AccountStatusExpiresAt: formatOptionalTime(profile.AccountStatusExpiresAt),
 	}
 }
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -7,12 +7,14 @@ import (
 	"github.com/gorilla/mux"
 
 	"socialpredict/handlers"
+	privacyhandlers "socialpredict/handlers/privacy"
 	"socialpredict/handlers/users/dto"
 	dusers "socialpredict/internal/domain/users"
 )
 
 // GetPortfolioHandler returns an HTTP handler that responds with a user's portfolio by delegating to the users service.
-func GetPortfolioHandler(svc dusers.ServiceInterface) http.HandlerFunc {
+// A hidden portfolio is forbidden to everyone but its owner and admins.
+func GetPortfolioHandler(svc dusers.ServiceInterface, guard *privacyhandlers.Guard) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
@@ -25,6 +27,11 @@ func GetPortfolioHandler(svc dusers.ServiceInterface) http.HandlerFunc {
 			return
 		}
 
+		if err := guard.CheckPortfolio(r, username); err != nil {
+			privacyhandlers.WriteError(w, "Portfolio", err)
+			return
+		}
+
 		portfolio, err := svc.GetUserPortfolio(r.Context(), username)
 		if err != nil {
 			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
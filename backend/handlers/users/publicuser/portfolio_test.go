@@ -90,7 +90,7 @@ func TestGetPortfolioHandlerSuccess(t *testing.T) {
 		TotalSharesOwned: 15,
 	}
 	mock := &portfolioServiceMock{portfolio: portfolio}
-	handler := GetPortfolioHandler(mock)
+	handler := GetPortfolioHandler(mock, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/portfolio/alice", nil)
 	req = mux.SetURLVars(req, map[string]string{"username": "alice"})
@@ -116,7 +116,7 @@ func TestGetPortfolioHandlerSuccess(t *testing.T) {
 }
 
 func TestGetPortfolioHandlerInvalidMethod(t *testing.T) {
-	handler := GetPortfolioHandler(&portfolioServiceMock{})
+	handler := GetPortfolioHandler(&portfolioServiceMock{}, nil)
 	req := httptest.NewRequest(http.MethodPost, "/v0/portfolio/alice", nil)
 	rec := httptest.NewRecorder()
 
@@ -130,7 +130,7 @@ func TestGetPortfolioHandlerInvalidMethod(t *testing.T) {
 
 func TestGetPortfolioHandlerServiceError(t *testing.T) {
 	mock := &portfolioServiceMock{err: errors.New("boom")}
-	handler := GetPortfolioHandler(mock)
+	handler := GetPortfolioHandler(mock, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/v0/portfolio/alice", nil)
 	req = mux.SetURLVars(req, map[string]string{"username": "alice"})
@@ -0,0 +1,114 @@
+package usershandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"socialpredict/handlers"
+	dusers "socialpredict/internal/domain/users"
+	configsvc "socialpredict/internal/service/config"
+	"socialpredict/logger"
+	"socialpredict/security"
+)
+
+type inviteRegistrar interface {
+	RegisterWithInvite(ctx context.Context, req dusers.InviteRegistrationRequest) (*dusers.User, error)
+}
+
+type registerRequest struct {
+	InviteCode  string `json:"inviteCode" validate:"required,max=64"`
+	Username    string `json:"username" validate:"required,min=3,max=30,username"`
+	Password    string `json:"password" validate:"required"`
+	DisplayName string `json:"displayName" validate:"omitempty,max=50"`
+	Email       string `json:"email" validate:"omitempty,email,max=254"`
+}
+
+type registerResult struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	UserType    string `json:"usertype"`
+	Message     string `json:"message"`
+}
+
+// RegisterHandler lets a visitor create their own account by redeeming an invite code.
+func RegisterHandler(svc inviteRegistrar, configService configsvc.Service, securityService *security.SecurityService, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if svc == nil || configService == nil || securityService == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		var req registerRequest
+		decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+			return
+		}
+
+		username, err := securityService.Sanitizer.SanitizeUsername(req.Username)
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+			return
+		}
+		req.Username = username
+		if err := securityService.Validator.ValidateStruct(req); err != nil {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+			return
+		}
+
+		user, err := svc.RegisterWithInvite(r.Context(), dusers.InviteRegistrationRequest{
+			Code:                         req.InviteCode,
+			Username:                     req.Username,
+			Password:                     req.Password,
+			DisplayName:                  req.DisplayName,
+			Email:                        req.Email,
+			DefaultInitialAccountBalance: configService.Economics().User.InitialAccountBalance,
+			Now:                          now().UTC(),
+		})
+		if err != nil {
+			writeRegisterError(w, err)
+			return
+		}
+
+		logger.LogInfo("Register", "RegisterWithInvite", "Registered user "+user.Username)
+		_ = handlers.WriteResult(w, http.StatusCreated, registerResult{
+			Username:    user.Username,
+			DisplayName: user.DisplayName,
+			UserType:    user.UserType,
+			Message:     "Account created successfully",
+		})
+	}
+}
+
+func writeRegisterError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, dusers.ErrInviteNotFound), errors.Is(err, dusers.ErrInviteUnavailable):
+		// Unknown, expired, revoked, and exhausted codes share one response so
+		// callers cannot probe which codes exist.
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	case errors.Is(err, dusers.ErrUserAlreadyExists):
+		_ = handlers.WriteFailure(w, http.StatusConflict, handlers.ReasonValidationFailed)
+	case errors.Is(err, dusers.ErrInvalidUserData):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+	default:
+		if handlers.IsValidationMessage(err.Error()) {
+			_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonValidationFailed)
+			return
+		}
+		logger.LogError("Register", "RegisterWithInvite", err)
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
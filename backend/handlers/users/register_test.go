@@ -0,0 +1,86 @@
+package usershandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"socialpredict/handlers"
+	dusers "socialpredict/internal/domain/users"
+	configsvc "socialpredict/internal/service/config"
+	"socialpredict/models/modelstesting"
+	"socialpredict/security"
+)
+
+type inviteRegistrarMock struct {
+	req dusers.InviteRegistrationRequest
+	err error
+}
+
+func (m *inviteRegistrarMock) RegisterWithInvite(_ context.Context, req dusers.InviteRegistrationRequest) (*dusers.User, error) {
+	m.req = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dusers.User{Username: req.Username, DisplayName: "Fresh", UserType: "REGULAR"}, nil
+}
+
+func serveRegister(t *testing.T, svc *inviteRegistrarMock, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	configService := configsvc.NewStaticService(modelstesting.GenerateEconomicConfig())
+	now := func() time.Time { return time.Date(2026, 6, 22, 9, 0, 0, 0, time.UTC) }
+
+	req := httptest.NewRequest(http.MethodPost, "/v0/register", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	RegisterHandler(svc, configService, security.NewSecurityService(), now).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRegisterHandler_CreatesAccount(t *testing.T) {
+	svc := &inviteRegistrarMock{}
+
+	rec := serveRegister(t, svc, `{"inviteCode":"ABCD-EFGH","username":"newcomer","password":"Sup3rSecret!Pass"}`)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	var resp handlers.SuccessEnvelope[registerResult]
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode success envelope: %v", err)
+	}
+	if !resp.OK || resp.Result.Username != "newcomer" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if svc.req.Code != "ABCD-EFGH" || svc.req.DefaultInitialAccountBalance != modelstesting.GenerateEconomicConfig().Economics.User.InitialAccountBalance {
+		t.Fatalf("unexpected registration request: %+v", svc.req)
+	}
+}
+
+func TestRegisterHandler_HidesInviteState(t *testing.T) {
+	for _, err := range []error{dusers.ErrInviteNotFound, dusers.ErrInviteUnavailable} {
+		rec := serveRegister(t, &inviteRegistrarMock{err: err}, `{"inviteCode":"ABCD","username":"newcomer","password":"Sup3rSecret!Pass"}`)
+
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("%v: expected status 403, got %d", err, rec.Code)
+		}
+		var resp handlers.FailureEnvelope
+		if decodeErr := json.Unmarshal(rec.Body.Bytes(), &resp); decodeErr != nil {
+			t.Fatalf("decode failure envelope: %v", decodeErr)
+		}
+		if resp.Reason != string(handlers.ReasonAuthorizationDenied) {
+			t.Fatalf("%v: expected reason %q, got %q", err, handlers.ReasonAuthorizationDenied, resp.Reason)
+		}
+	}
+}
+
+func TestRegisterHandler_RejectsUnknownFields(t *testing.T) {
+	rec := serveRegister(t, &inviteRegistrarMock{}, `{"inviteCode":"ABCD","username":"newcomer","password":"x","usertype":"ADMIN"}`)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rec.Code)
+	}
+}
@@ -5,18 +5,23 @@ import (
 
 	"gorm.io/gorm"
 
+	"socialpredict/internal/app/eventbus"
+
 	// Domain services
 	analytics "socialpredict/internal/domain/analytics"
 	dbets "socialpredict/internal/domain/bets"
 	dmarkets "socialpredict/internal/domain/markets"
 	positionsmath "socialpredict/internal/domain/math/positions"
 	"socialpredict/internal/domain/math/probabilities/wpam"
+	dpermissions "socialpredict/internal/domain/permissions"
 	dusers "socialpredict/internal/domain/users"
 
 	// Repositories
 	ranalytics "socialpredict/internal/repository/analytics"
 	rbets "socialpredict/internal/repository/bets"
+	revents "socialpredict/internal/repository/events"
 	rmarkets "socialpredict/internal/repository/markets"
+	rpermissions "socialpredict/internal/repository/permissions"
 	rusers "socialpredict/internal/repository/users"
 
 	// Handlers
@@ -46,19 +51,23 @@ type Container struct {
 	clock         Clock
 
 	// Repositories
-	marketsRepo   rmarkets.GormRepository
-	usersRepo     rusers.GormRepository
-	analyticsRepo ranalytics.GormRepository
-	betsRepo      rbets.GormRepository
+	marketsRepo     rmarkets.GormRepository
+	usersRepo       rusers.GormRepository
+	analyticsRepo   ranalytics.GormRepository
+	betsRepo        rbets.GormRepository
+	permissionsRepo rpermissions.GormRepository
+	outboxRepo      revents.GormRepository
 
 	// Domain services
-	analyticsService *analytics.Service
-	marketsService   *dmarkets.Service
-	usersService     *dusers.Service
-	betsService      *dbets.Service
-	authService      *authsvc.AuthService
-	jwtSigningKey    []byte
-	securityService  *security.SecurityService
+	analyticsService   *analytics.Service
+	marketsService     *dmarkets.Service
+	usersService       *dusers.Service
+	betsService        *dbets.Service
+	permissionsService *dpermissions.Service
+	authService        *authsvc.AuthService
+	jwtSigningKey      []byte
+	securityService    *security.SecurityService
+	eventDispatcher    *eventbus.Dispatcher
 
 	// Handlers
 	marketsHandler *hmarkets.Handler
@@ -89,6 +98,8 @@ func (c *Container) InitializeRepositories() {
 	c.marketsRepo = *rmarkets.NewGormRepository(c.db)
 	c.usersRepo = *rusers.NewGormRepository(c.db)
 	c.betsRepo = *rbets.NewGormRepository(c.db)
+	c.permissionsRepo = *rpermissions.NewGormRepository(c.db)
+	c.outboxRepo = *revents.NewGormRepository(c.db)
 }
 
 // InitializeServices sets up all domain services with their dependencies
@@ -113,6 +124,8 @@ func (c *Container) InitializeServices() {
 		MaximumDebtAllowed: c.config.Economics.User.MaximumDebtAllowed,
 		CreateMarketCost:   c.config.Economics.MarketIncentives.CreateMarketCost,
 		InitialBetFee:      c.config.Economics.Betting.BetFees.InitialBetFee,
+		BuySharesFee:       c.config.Economics.Betting.BetFees.BuySharesFee,
+		MaxDustPerSale:     c.config.Economics.Betting.MaxDustPerSale,
 	}
 	betsConfig := dbets.Config{
 		InitialBetFee:      c.config.Economics.Betting.BetFees.InitialBetFee,
@@ -122,9 +135,13 @@ func (c *Container) InitializeServices() {
 	}
 
 	c.analyticsRepo = *ranalytics.NewGormRepository(c.db, ranalytics.WithRepositoryPositionCalculator(positionCalcAdapter))
-	c.analyticsService = analytics.NewService(&c.analyticsRepo, analyticsConfig, analytics.WithPositionCalculator(positionCalcAdapter))
+	c.analyticsService = analytics.NewService(&c.analyticsRepo, analyticsConfig, analytics.WithPositionCalculator(positionCalcAdapter), analytics.WithProbabilityCalculator(wpamCalculator))
+	c.permissionsService = dpermissions.NewService(&c.permissionsRepo)
 	c.usersService = dusers.NewService(&c.usersRepo, c.analyticsService, c.securityService.Sanitizer)
+	c.usersService.SetAuthorizer(c.permissionsService)
+	c.usersService.SetClock(c.clock.Now)
 	c.authService = authsvc.NewAuthService(c.usersService, c.jwtSigningKey)
+	c.authService.SetAuthorizer(c.permissionsService)
 
 	// Markets service depends on markets repository and users service
 	marketsConfig := dmarkets.Config{
@@ -139,15 +156,18 @@ func (c *Container) InitializeServices() {
 		MultipleChoiceBinaryHardAnswerSafetyCap: c.config.Economics.MarketIncentives.MultipleChoiceBinary.HardAnswerSafetyCap,
 	}
 
+	c.eventDispatcher = eventbus.NewDispatcher(&c.outboxRepo, eventbus.Config{}, c.clock.Now)
 	c.marketsService = dmarkets.NewService(
 		&c.marketsRepo,
 		c.usersService,
 		c.clock,
 		marketsConfig,
 		dmarkets.WithProbabilityEngine(dmarkets.DefaultProbabilityEngine(wpamCalculator)),
+		dmarkets.WithAuthorizer(c.permissionsService),
+		dmarkets.WithEventNotifier(c.eventDispatcher),
 	)
 
-	c.betsService = dbets.NewService(&c.betsRepo, c.marketsService, c.usersService, betsConfig, c.clock)
+	c.betsService = dbets.NewService(&c.betsRepo, c.marketsService, c.usersService, betsConfig, c.clock, dbets.WithEventNotifier(c.eventDispatcher))
 }
 
 // InitializeHandlers sets up all HTTP handlers with their service dependencies
@@ -192,6 +212,11 @@ func (c *Container) GetBetsService() *dbets.Service {
 	return c.betsService
 }
 
+// GetPermissionsService returns the role and permission service
+func (c *Container) GetPermissionsService() *dpermissions.Service {
+	return c.permissionsService
+}
+
 // GetAuthService returns the authentication façade
 func (c *Container) GetAuthService() *authsvc.AuthService {
 	return c.authService
@@ -202,6 +227,18 @@ func (c *Container) GetSecurityService() *security.SecurityService {
 	return c.securityService
 }
 
+// GetEventRecorder returns the outbox recorder for side effects that happen
+// outside a domain repository, such as CMS content edits.
+func (c *Container) GetEventRecorder() *revents.GormRepository {
+	return &c.outboxRepo
+}
+
+// GetEventDispatcher returns the outbox dispatcher. Subscribers are registered
+// by the server wiring; the dispatcher only delivers once Run is started.
+func (c *Container) GetEventDispatcher() *eventbus.Dispatcher {
+	return c.eventDispatcher
+}
+
 // GetConfigService returns the runtime configuration service.
 func (c *Container) GetConfigService() configsvc.Service {
 	return c.configService
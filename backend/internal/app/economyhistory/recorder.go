@@ -0,0 +1,54 @@
+package economyhistory
+
+import (
+	"context"
+	"time"
+
+	"socialpredict/logger"
+)
+
+const defaultInterval = time.Hour
+
+// History records the analytics economy history.
+type History interface {
+	RecordEconomyDay(ctx context.Context, now time.Time) error
+}
+
+// Recorder keeps the current day of the economy history up to date and
+// closes out the previous day once it has ended.
+type Recorder struct {
+	history  History
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewRecorder builds a recorder. A non-positive interval samples hourly.
+func NewRecorder(history History, interval time.Duration, now func() time.Time) *Recorder {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Recorder{history: history, interval: interval, now: now}
+}
+
+// Run samples the economy now and then once per interval until ctx is
+// cancelled.
+func (r *Recorder) Run(ctx context.Context) {
+	if r == nil || r.history == nil {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.history.RecordEconomyDay(ctx, r.now().UTC()); err != nil && ctx.Err() == nil {
+			logger.LogError("economyhistory", "RecordEconomyDay", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package economyhistory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeHistory struct {
+	recorded chan time.Time
+}
+
+func (h *fakeHistory) RecordEconomyDay(_ context.Context, now time.Time) error {
+	h.recorded <- now
+	return nil
+}
+
+func TestRunRecordsImmediatelyAndStopsOnCancel(t *testing.T) {
+	history := &fakeHistory{recorded: make(chan time.Time, 1)}
+	at := time.Date(2026, 7, 10, 9, 0, 0, 0, time.FixedZone("plus2", 2*60*60))
+	recorder := NewRecorder(history, time.Hour, func() time.Time { return at })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		recorder.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case got := <-history.recorded:
+		if !got.Equal(at) || got.Location() != time.UTC {
+			t.Fatalf("recorded at %s, want %s in UTC", got, at)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected an immediate sample")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Run did not stop after cancel")
+	}
+}
@@ -0,0 +1,108 @@
+package equityhistory
+
+import (
+	"context"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/logger"
+)
+
+const defaultInterval = time.Hour
+
+// History records and compacts the analytics equity history.
+type History interface {
+	RecordUserFinancialHistory(ctx context.Context, usernames []string, at time.Time) (int, error)
+	CompactUserFinancialHistory(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Positions lists who holds a market.
+type Positions interface {
+	GetMarketPositions(ctx context.Context, marketID int64) (dmarkets.MarketPositions, error)
+}
+
+// Recorder samples every user's equity on a schedule and the holders of a
+// market when it resolves, so resolution payouts show up in the history
+// without waiting for the next sample.
+type Recorder struct {
+	history   History
+	positions Positions
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewRecorder builds a recorder. A non-positive interval samples hourly.
+func NewRecorder(history History, positions Positions, interval time.Duration, now func() time.Time) *Recorder {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Recorder{history: history, positions: positions, interval: interval, now: now}
+}
+
+// Run samples every user and compacts old samples now and then once per
+// interval until ctx is cancelled.
+func (r *Recorder) Run(ctx context.Context) {
+	if r == nil || r.history == nil {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.RecordAll(ctx); err != nil && ctx.Err() == nil {
+			logger.LogError("equityhistory", "RecordAll", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RecordAll samples every user and then compacts samples that aged out of
+// the hourly window.
+func (r *Recorder) RecordAll(ctx context.Context) error {
+	now := r.now().UTC()
+	if _, err := r.history.RecordUserFinancialHistory(ctx, nil, now); err != nil {
+		return err
+	}
+	_, err := r.history.CompactUserFinancialHistory(ctx, now)
+	return err
+}
+
+// HandleEvent subscribes the recorder to the domain event bus. A resolved
+// market samples its holders and whoever resolved it.
+func (r *Recorder) HandleEvent(ctx context.Context, event devents.Event) error {
+	if r == nil || r.history == nil || event.Type != devents.MarketResolved || event.MarketID <= 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	var usernames []string
+	add := func(username string) {
+		if username != "" && !seen[username] {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+	add(event.Username)
+	if r.positions != nil {
+		positions, err := r.positions.GetMarketPositions(ctx, event.MarketID)
+		if err != nil {
+			return err
+		}
+		for _, position := range positions {
+			if position != nil {
+				add(position.Username)
+			}
+		}
+	}
+	if len(usernames) == 0 {
+		return nil
+	}
+	_, err := r.history.RecordUserFinancialHistory(ctx, usernames, r.now().UTC())
+	return err
+}
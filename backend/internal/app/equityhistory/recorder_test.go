@@ -0,0 +1,61 @@
+package equityhistory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+type fakeHistory struct {
+	recorded  [][]string
+	compacted int
+}
+
+func (h *fakeHistory) RecordUserFinancialHistory(_ context.Context, usernames []string, _ time.Time) (int, error) {
+	h.recorded = append(h.recorded, usernames)
+	return len(usernames), nil
+}
+
+func (h *fakeHistory) CompactUserFinancialHistory(context.Context, time.Time) (int64, error) {
+	h.compacted++
+	return 0, nil
+}
+
+type fakePositions map[int64]dmarkets.MarketPositions
+
+func (p fakePositions) GetMarketPositions(_ context.Context, marketID int64) (dmarkets.MarketPositions, error) {
+	return p[marketID], nil
+}
+
+func TestHandleEventRecordsHoldersOfResolvedMarket(t *testing.T) {
+	history := &fakeHistory{}
+	positions := fakePositions{7: {{Username: "bob"}, {Username: "alice"}, {Username: "bob"}}}
+	recorder := NewRecorder(history, positions, 0, nil)
+	ctx := context.Background()
+
+	if err := recorder.HandleEvent(ctx, devents.Event{Type: devents.BetPlaced, MarketID: 7, Username: "bob"}); err != nil {
+		t.Fatalf("HandleEvent(bet) returned error: %v", err)
+	}
+	if err := recorder.HandleEvent(ctx, devents.Event{Type: devents.MarketResolved, MarketID: 7, Username: "creator"}); err != nil {
+		t.Fatalf("HandleEvent(resolved) returned error: %v", err)
+	}
+	want := [][]string{{"creator", "bob", "alice"}}
+	if !reflect.DeepEqual(history.recorded, want) {
+		t.Fatalf("recorded %v, want %v", history.recorded, want)
+	}
+}
+
+func TestRecordAllSamplesEveryoneAndCompacts(t *testing.T) {
+	history := &fakeHistory{}
+	recorder := NewRecorder(history, nil, time.Minute, nil)
+	if err := recorder.RecordAll(context.Background()); err != nil {
+		t.Fatalf("RecordAll returned error: %v", err)
+	}
+	if len(history.recorded) != 1 || history.recorded[0] != nil || history.compacted != 1 {
+		t.Fatalf("recorded %v compacted %d", history.recorded, history.compacted)
+	}
+}
@@ -0,0 +1,208 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/logger"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	defaultLease        = time.Minute
+	defaultMaxAttempts  = 10
+	defaultBaseBackoff  = 2 * time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+)
+
+// Subscriber handles one delivered event. Delivery is at-least-once, so
+// subscribers must tolerate seeing the same event ID again.
+type Subscriber interface {
+	HandleEvent(ctx context.Context, event devents.Event) error
+}
+
+// SubscriberFunc adapts a function to Subscriber.
+type SubscriberFunc func(ctx context.Context, event devents.Event) error
+
+// HandleEvent calls f.
+func (f SubscriberFunc) HandleEvent(ctx context.Context, event devents.Event) error {
+	return f(ctx, event)
+}
+
+// Config tunes polling and retry behaviour. Zero values use the defaults.
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+type namedSubscriber struct {
+	name       string
+	subscriber Subscriber
+}
+
+// Dispatcher delivers outbox events to in-process subscribers. An event is
+// settled only after every subscriber has handled it; failed subscribers are
+// retried with exponential backoff until MaxAttempts, after which the event
+// is parked as dead for inspection.
+type Dispatcher struct {
+	store       devents.OutboxStore
+	config      Config
+	now         func() time.Time
+	subscribers []namedSubscriber
+	wake        chan struct{}
+}
+
+// NewDispatcher builds a dispatcher over store.
+func NewDispatcher(store devents.OutboxStore, config Config, now func() time.Time) *Dispatcher {
+	if now == nil {
+		now = time.Now
+	}
+	return &Dispatcher{
+		store:  store,
+		config: normalizeConfig(config),
+		now:    now,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+func normalizeConfig(config Config) Config {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultBaseBackoff
+	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	return config
+}
+
+// Subscribe registers a subscriber under a stable name. The name keys the
+// delivery ledger, so renaming a subscriber replays events it has not settled.
+func (d *Dispatcher) Subscribe(name string, subscriber Subscriber) {
+	if d == nil || subscriber == nil || name == "" {
+		return
+	}
+	d.subscribers = append(d.subscribers, namedSubscriber{name: name, subscriber: subscriber})
+}
+
+// Notify asks a running dispatcher to poll now instead of waiting for the
+// next tick. It never blocks.
+func (d *Dispatcher) Notify() {
+	if d == nil {
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d == nil || d.store == nil {
+		return
+	}
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+			logger.LogError("eventbus", "DispatchDue", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DispatchDue delivers one batch of due events and returns how many were
+// claimed. Subscriber failures are recorded on the event, not returned.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	if d == nil || d.store == nil {
+		return 0, nil
+	}
+	pending, err := d.store.ClaimDueEvents(ctx, d.now().UTC(), d.config.Lease, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for _, event := range pending {
+		if err := d.deliver(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(pending), errors.Join(errs...)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, pending devents.PendingEvent) error {
+	attempts := pending.Attempts + 1
+	var failures []error
+	for _, sub := range d.subscribers {
+		if pending.DeliveredTo(sub.name) {
+			continue
+		}
+		if err := d.handle(ctx, sub, pending.Event); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		if err := d.store.MarkSubscriberDelivered(ctx, pending.ID, sub.name, d.now().UTC()); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) == 0 {
+		return d.store.MarkEventDelivered(ctx, pending.ID, attempts, d.now().UTC())
+	}
+	lastError := errors.Join(failures...).Error()
+	if attempts >= d.config.MaxAttempts {
+		logger.Warn("eventbus", "outbox event exhausted retries",
+			logger.Operation("deliver"),
+			logger.String("eventType", string(pending.Type)),
+			logger.String("eventId", strconv.FormatInt(pending.ID, 10)),
+		)
+		return d.store.MarkEventDead(ctx, pending.ID, attempts, lastError)
+	}
+	return d.store.ScheduleEventRetry(ctx, pending.ID, attempts, d.now().UTC().Add(d.backoff(attempts)), lastError)
+}
+
+func (d *Dispatcher) handle(ctx context.Context, sub namedSubscriber, event devents.Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("subscriber panicked: %v", recovered)
+		}
+	}()
+	return sub.subscriber.HandleEvent(ctx, event)
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.config.MaxBackoff {
+			return d.config.MaxBackoff
+		}
+	}
+	return delay
+}
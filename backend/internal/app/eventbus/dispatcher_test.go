@@ -0,0 +1,120 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+)
+
+type fakeOutbox struct {
+	pending   []devents.PendingEvent
+	delivered map[int64][]string
+	settled   map[int64]int
+	retries   map[int64]time.Time
+	dead      map[int64]string
+}
+
+func newFakeOutbox(events ...devents.PendingEvent) *fakeOutbox {
+	return &fakeOutbox{
+		pending:   events,
+		delivered: map[int64][]string{},
+		settled:   map[int64]int{},
+		retries:   map[int64]time.Time{},
+		dead:      map[int64]string{},
+	}
+}
+
+func (f *fakeOutbox) ClaimDueEvents(context.Context, time.Time, time.Duration, int) ([]devents.PendingEvent, error) {
+	claimed := f.pending
+	f.pending = nil
+	return claimed, nil
+}
+
+func (f *fakeOutbox) MarkSubscriberDelivered(_ context.Context, eventID int64, subscriber string, _ time.Time) error {
+	f.delivered[eventID] = append(f.delivered[eventID], subscriber)
+	return nil
+}
+
+func (f *fakeOutbox) MarkEventDelivered(_ context.Context, eventID int64, attempts int, _ time.Time) error {
+	f.settled[eventID] = attempts
+	return nil
+}
+
+func (f *fakeOutbox) ScheduleEventRetry(_ context.Context, eventID int64, _ int, nextAttemptAt time.Time, _ string) error {
+	f.retries[eventID] = nextAttemptAt
+	return nil
+}
+
+func (f *fakeOutbox) MarkEventDead(_ context.Context, eventID int64, _ int, lastError string) error {
+	f.dead[eventID] = lastError
+	return nil
+}
+
+func TestDispatcherDeliversToEverySubscriberThenSettles(t *testing.T) {
+	store := newFakeOutbox(devents.PendingEvent{Event: devents.Event{ID: 1, Type: devents.BetPlaced, MarketID: 4}})
+	dispatcher := NewDispatcher(store, Config{}, nil)
+	var seen []string
+	for _, name := range []string{"read_models", "webhooks"} {
+		name := name
+		dispatcher.Subscribe(name, SubscriberFunc(func(_ context.Context, event devents.Event) error {
+			if event.MarketID != 4 {
+				t.Fatalf("unexpected event: %+v", event)
+			}
+			seen = append(seen, name)
+			return nil
+		}))
+	}
+
+	claimed, err := dispatcher.DispatchDue(context.Background())
+	if err != nil || claimed != 1 {
+		t.Fatalf("DispatchDue = %d, %v", claimed, err)
+	}
+	if len(seen) != 2 || len(store.delivered[1]) != 2 || store.settled[1] != 1 {
+		t.Fatalf("unexpected delivery: seen=%v ledger=%v settled=%v", seen, store.delivered, store.settled)
+	}
+}
+
+func TestDispatcherRetriesOnlyFailedSubscribersWithBackoff(t *testing.T) {
+	now := time.Date(2026, 6, 27, 9, 0, 0, 0, time.UTC)
+	store := newFakeOutbox(devents.PendingEvent{
+		Event:     devents.Event{ID: 2, Type: devents.MarketResolved},
+		Attempts:  2,
+		Delivered: []string{"read_models"},
+	})
+	dispatcher := NewDispatcher(store, Config{BaseBackoff: time.Second}, func() time.Time { return now })
+	dispatcher.Subscribe("read_models", SubscriberFunc(func(context.Context, devents.Event) error {
+		t.Fatalf("delivered subscriber should be skipped")
+		return nil
+	}))
+	dispatcher.Subscribe("webhooks", SubscriberFunc(func(context.Context, devents.Event) error {
+		return errors.New("endpoint down")
+	}))
+
+	if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("DispatchDue returned error: %v", err)
+	}
+	if _, settled := store.settled[2]; settled {
+		t.Fatalf("failed event was settled")
+	}
+	if want := now.Add(4 * time.Second); !store.retries[2].Equal(want) {
+		t.Fatalf("next attempt = %v, want %v", store.retries[2], want)
+	}
+}
+
+func TestDispatcherParksEventAfterMaxAttemptsAndRecoversPanics(t *testing.T) {
+	store := newFakeOutbox(devents.PendingEvent{Event: devents.Event{ID: 3, Type: devents.AnswerAdded}, Attempts: 2})
+	dispatcher := NewDispatcher(store, Config{MaxAttempts: 3}, nil)
+	dispatcher.Subscribe("notifications", SubscriberFunc(func(context.Context, devents.Event) error {
+		panic("nil map")
+	}))
+
+	if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("DispatchDue returned error: %v", err)
+	}
+	if store.dead[3] == "" {
+		t.Fatalf("expected event to be parked as dead, retries=%v", store.retries)
+	}
+}
@@ -0,0 +1,416 @@
+package livestream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dlivestream "socialpredict/internal/domain/livestream"
+	dmarkets "socialpredict/internal/domain/markets"
+	dprivacy "socialpredict/internal/domain/privacy"
+	"socialpredict/logger"
+)
+
+const (
+	defaultMaxStreams          = 1000
+	defaultMaxStreamsPerClient = 4
+	defaultBuffer              = 64
+	defaultReplayLimit         = 500
+	defaultHeartbeat           = 15 * time.Second
+)
+
+// Markets is the market read surface the hub needs to validate topics and
+// attach probability points to trades.
+type Markets interface {
+	GetMarket(ctx context.Context, id int64) (*dmarkets.Market, error)
+	GetMarketGroup(ctx context.Context, groupID int64) (*dmarkets.MarketGroup, error)
+	GetMarketGroupForMarket(ctx context.Context, marketID int64) (*dmarkets.MarketGroup, error)
+	GetMarketProbability(ctx context.Context, marketID int64) (*dmarkets.ProbabilityPoint, error)
+}
+
+// TradePrivacy reports which traders asked to trade anonymously.
+type TradePrivacy interface {
+	AnonymousTraders(ctx context.Context, usernames []string) (map[string]bool, error)
+}
+
+// Config bounds the hub. Zero values use the defaults.
+type Config struct {
+	MaxStreams          int
+	MaxStreamsPerClient int
+	// Buffer is how many live frames a stream may fall behind before it is
+	// closed; the client then reconnects and replays from Last-Event-ID.
+	Buffer      int
+	ReplayLimit int
+	Heartbeat   time.Duration
+}
+
+// Hub fans committed domain events out to open SSE streams. It subscribes
+// to the outbox dispatcher, so it only sees events dispatched by this
+// process; resume gaps are filled from the outbox itself.
+type Hub struct {
+	log     devents.Log
+	markets Markets
+	config  Config
+	privacy TradePrivacy
+
+	mu        sync.Mutex
+	closed    bool
+	streams   map[*stream]struct{}
+	perClient map[string]int
+	groupOf   map[int64]int64
+}
+
+// NewHub builds a hub reading resume history from log.
+func NewHub(log devents.Log, markets Markets, config Config) *Hub {
+	return &Hub{
+		log:       log,
+		markets:   markets,
+		config:    normalizeConfig(config),
+		streams:   make(map[*stream]struct{}),
+		perClient: make(map[string]int),
+		groupOf:   make(map[int64]int64),
+	}
+}
+
+// SetTradePrivacy shows anonymous traders as dprivacy.AnonymousUsername in
+// trade frames. Streams are shared by every viewer, so nobody sees through it.
+func (h *Hub) SetTradePrivacy(privacy TradePrivacy) {
+	h.privacy = privacy
+}
+
+func normalizeConfig(config Config) Config {
+	if config.MaxStreams <= 0 {
+		config.MaxStreams = defaultMaxStreams
+	}
+	if config.MaxStreamsPerClient <= 0 {
+		config.MaxStreamsPerClient = defaultMaxStreamsPerClient
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = defaultBuffer
+	}
+	if config.ReplayLimit <= 0 {
+		config.ReplayLimit = defaultReplayLimit
+	}
+	if config.Heartbeat <= 0 {
+		config.Heartbeat = defaultHeartbeat
+	}
+	return config
+}
+
+// Heartbeat is how often idle streams should send a keep-alive comment.
+func (h *Hub) Heartbeat() time.Duration {
+	return h.config.Heartbeat
+}
+
+// Open validates topic, reserves a stream slot for clientID, and prepares
+// the replay: events after lastEventID (or a reset when the gap exceeds the
+// replay limit) followed by the current probability of every market on the
+// topic.
+func (h *Hub) Open(ctx context.Context, topic dlivestream.Topic, clientID string, lastEventID int64) (dlivestream.Stream, error) {
+	marketIDs, err := h.topicMarkets(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := h.register(topic, clientID)
+	if err != nil {
+		return nil, err
+	}
+	replay, cursor, err := h.replay(ctx, topic, marketIDs, lastEventID)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.replay = replay
+	s.replayedThrough = cursor
+	return s, nil
+}
+
+func (h *Hub) topicMarkets(ctx context.Context, topic dlivestream.Topic) ([]int64, error) {
+	if topic.MarketGroupID > 0 {
+		group, err := h.markets.GetMarketGroup(ctx, topic.MarketGroupID)
+		if err != nil {
+			return nil, err
+		}
+		marketIDs := make([]int64, 0, len(group.Members))
+		for _, member := range dmarkets.OrderedMarketGroupMembers(group.Members) {
+			marketIDs = append(marketIDs, member.MarketID)
+			h.rememberGroup(member.MarketID, group.ID)
+		}
+		return marketIDs, nil
+	}
+	if topic.MarketID <= 0 {
+		return nil, dmarkets.ErrInvalidInput
+	}
+	market, err := h.markets.GetMarket(ctx, topic.MarketID)
+	if err != nil {
+		return nil, err
+	}
+	return []int64{market.ID}, nil
+}
+
+func (h *Hub) register(topic dlivestream.Topic, clientID string) (*stream, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, dlivestream.ErrClosed
+	}
+	if len(h.streams) >= h.config.MaxStreams || h.perClient[clientID] >= h.config.MaxStreamsPerClient {
+		return nil, dlivestream.ErrTooManyStreams
+	}
+	s := &stream{
+		hub:      h,
+		topic:    topic,
+		clientID: clientID,
+		messages: make(chan dlivestream.Message, h.config.Buffer),
+	}
+	h.streams[s] = struct{}{}
+	h.perClient[clientID]++
+	return s, nil
+}
+
+func (h *Hub) replay(ctx context.Context, topic dlivestream.Topic, marketIDs []int64, lastEventID int64) ([]dlivestream.Message, int64, error) {
+	latest, err := h.log.LatestEventID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var frames []dlivestream.Message
+	cursor := latest
+	if lastEventID > 0 && lastEventID < latest {
+		filter := devents.Filter{MarketID: topic.MarketID, MarketGroupID: topic.MarketGroupID, Types: dlivestream.StreamedTypes()}
+		missed, err := h.log.ListEventsAfter(ctx, lastEventID, filter, h.config.ReplayLimit+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(missed) > h.config.ReplayLimit {
+			frames = append(frames, dlivestream.ResetFrame(latest))
+		} else {
+			for _, event := range missed {
+				frames = append(frames, dlivestream.FramesFor(event)...)
+			}
+			h.maskTrades(ctx, frames)
+		}
+	}
+
+	for _, marketID := range marketIDs {
+		point, err := h.markets.GetMarketProbability(ctx, marketID)
+		if err != nil {
+			return nil, 0, err
+		}
+		frames = append(frames, dlivestream.ProbabilityFrame(cursor, marketID, point.Probability, point.Timestamp))
+	}
+	return frames, cursor, nil
+}
+
+// HandleEvent subscribes the hub to the domain event bus. Frames are dropped
+// rather than retried when nobody is listening, and a failed probability
+// lookup only skips the probability frame, so the hub never asks the
+// dispatcher to redeliver.
+func (h *Hub) HandleEvent(ctx context.Context, event devents.Event) error {
+	if h == nil || !dlivestream.Streamed(event.Type) {
+		return nil
+	}
+	if event.MarketID > 0 && event.MarketGroupID > 0 {
+		h.rememberGroup(event.MarketID, event.MarketGroupID)
+	}
+	targets := h.listeners(ctx, event)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	frames := dlivestream.FramesFor(event)
+	h.maskTrades(ctx, frames)
+	if dlivestream.IsTrade(event.Type) && event.MarketID > 0 {
+		point, err := h.markets.GetMarketProbability(ctx, event.MarketID)
+		if err != nil {
+			logger.LogError("livestream", "GetMarketProbability", err)
+		} else {
+			frames = append(frames, dlivestream.ProbabilityFrame(event.ID, event.MarketID, point.Probability, point.Timestamp))
+		}
+	}
+	for _, s := range targets {
+		s.send(frames)
+	}
+	return nil
+}
+
+// maskTrades rewrites anonymous traders' names in trade frames. A failed
+// lookup masks every trader rather than risk naming one.
+func (h *Hub) maskTrades(ctx context.Context, frames []dlivestream.Message) {
+	if h.privacy == nil {
+		return
+	}
+	var usernames []string
+	for _, frame := range frames {
+		if trade, ok := frame.Data.(dlivestream.TradePayload); ok {
+			usernames = append(usernames, trade.Username)
+		}
+	}
+	if len(usernames) == 0 {
+		return
+	}
+	anonymous, err := h.privacy.AnonymousTraders(ctx, usernames)
+	if err != nil {
+		logger.LogError("livestream", "AnonymousTraders", err)
+	}
+	for i, frame := range frames {
+		trade, ok := frame.Data.(dlivestream.TradePayload)
+		if ok && (err != nil || anonymous[trade.Username]) {
+			trade.Username = dprivacy.AnonymousUsername
+			frames[i].Data = trade
+		}
+	}
+}
+
+func (h *Hub) listeners(ctx context.Context, event devents.Event) []*stream {
+	h.mu.Lock()
+	hasGroupStreams := false
+	for s := range h.streams {
+		if s.topic.MarketGroupID > 0 {
+			hasGroupStreams = true
+			break
+		}
+	}
+	h.mu.Unlock()
+
+	groupID := event.MarketGroupID
+	if groupID == 0 && event.MarketID > 0 && hasGroupStreams {
+		groupID = h.groupFor(ctx, event.MarketID)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var targets []*stream
+	for s := range h.streams {
+		if s.topic.Matches(event.MarketID, groupID) {
+			targets = append(targets, s)
+		}
+	}
+	return targets
+}
+
+// groupFor returns the group a market belongs to, or zero for standalone
+// markets. Membership never changes once a market exists, so both answers
+// are cached.
+func (h *Hub) groupFor(ctx context.Context, marketID int64) int64 {
+	h.mu.Lock()
+	groupID, known := h.groupOf[marketID]
+	h.mu.Unlock()
+	if known {
+		return groupID
+	}
+	group, err := h.markets.GetMarketGroupForMarket(ctx, marketID)
+	switch {
+	case err == nil && group != nil:
+		groupID = group.ID
+	case errors.Is(err, dmarkets.ErrMarketGroupNotFound):
+		groupID = 0
+	default:
+		if err != nil {
+			logger.LogError("livestream", "GetMarketGroupForMarket", err)
+		}
+		return 0
+	}
+	h.rememberGroup(marketID, groupID)
+	return groupID
+}
+
+func (h *Hub) rememberGroup(marketID, groupID int64) {
+	h.mu.Lock()
+	h.groupOf[marketID] = groupID
+	h.mu.Unlock()
+}
+
+// Run closes every stream when ctx is cancelled, so the hub can be started
+// with the other background workers and stopped with the server.
+func (h *Hub) Run(ctx context.Context) {
+	<-ctx.Done()
+	h.Close()
+}
+
+// Close ends every open stream and rejects new ones. Streams otherwise hold
+// their connections open, so the server calls this before draining.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	streams := make([]*stream, 0, len(h.streams))
+	for s := range h.streams {
+		streams = append(streams, s)
+	}
+	h.mu.Unlock()
+	for _, s := range streams {
+		s.Close()
+	}
+}
+
+// OpenStreams reports how many streams are open.
+func (h *Hub) OpenStreams() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.streams)
+}
+
+func (h *Hub) release(s *stream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.streams[s]; !ok {
+		return
+	}
+	delete(h.streams, s)
+	if h.perClient[s.clientID]--; h.perClient[s.clientID] <= 0 {
+		delete(h.perClient, s.clientID)
+	}
+}
+
+type stream struct {
+	hub             *Hub
+	topic           dlivestream.Topic
+	clientID        string
+	replay          []dlivestream.Message
+	replayedThrough int64
+
+	mu       sync.Mutex
+	closed   bool
+	messages chan dlivestream.Message
+}
+
+func (s *stream) Replay() []dlivestream.Message { return s.replay }
+
+func (s *stream) ReplayedThrough() int64 { return s.replayedThrough }
+
+func (s *stream) Messages() <-chan dlivestream.Message { return s.messages }
+
+func (s *stream) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.messages)
+	s.mu.Unlock()
+	s.hub.release(s)
+}
+
+// send queues frames without blocking; a stream whose buffer is full is
+// closed so one slow client cannot hold up the dispatcher.
+func (s *stream) send(frames []dlivestream.Message) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	for _, frame := range frames {
+		select {
+		case s.messages <- frame:
+		default:
+			s.mu.Unlock()
+			s.Close()
+			return
+		}
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,250 @@
+package livestream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dlivestream "socialpredict/internal/domain/livestream"
+	dmarkets "socialpredict/internal/domain/markets"
+	dprivacy "socialpredict/internal/domain/privacy"
+)
+
+type fakeLog struct {
+	events []devents.Event
+}
+
+func (l *fakeLog) LatestEventID(context.Context) (int64, error) {
+	if len(l.events) == 0 {
+		return 0, nil
+	}
+	return l.events[len(l.events)-1].ID, nil
+}
+
+func (l *fakeLog) ListEventsAfter(_ context.Context, afterID int64, filter devents.Filter, limit int) ([]devents.Event, error) {
+	var out []devents.Event
+	for _, event := range l.events {
+		if event.ID <= afterID || (filter.MarketID > 0 && event.MarketID != filter.MarketID) {
+			continue
+		}
+		out = append(out, event)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+type fakeMarkets struct {
+	groups        map[int64]*dmarkets.MarketGroup
+	probabilities map[int64]float64
+	groupLookups  int
+}
+
+func (m *fakeMarkets) GetMarket(_ context.Context, id int64) (*dmarkets.Market, error) {
+	if _, ok := m.probabilities[id]; !ok {
+		return nil, dmarkets.ErrMarketNotFound
+	}
+	return &dmarkets.Market{ID: id}, nil
+}
+
+func (m *fakeMarkets) GetMarketGroup(_ context.Context, groupID int64) (*dmarkets.MarketGroup, error) {
+	group, ok := m.groups[groupID]
+	if !ok {
+		return nil, dmarkets.ErrMarketGroupNotFound
+	}
+	return group, nil
+}
+
+func (m *fakeMarkets) GetMarketGroupForMarket(_ context.Context, marketID int64) (*dmarkets.MarketGroup, error) {
+	m.groupLookups++
+	for _, group := range m.groups {
+		for _, member := range group.Members {
+			if member.MarketID == marketID {
+				return group, nil
+			}
+		}
+	}
+	return nil, dmarkets.ErrMarketGroupNotFound
+}
+
+func (m *fakeMarkets) GetMarketProbability(_ context.Context, marketID int64) (*dmarkets.ProbabilityPoint, error) {
+	return &dmarkets.ProbabilityPoint{Probability: m.probabilities[marketID], Timestamp: time.Date(2026, 6, 29, 9, 0, 0, 0, time.UTC)}, nil
+}
+
+func newTestHub(config Config) (*Hub, *fakeLog, *fakeMarkets) {
+	log := &fakeLog{}
+	markets := &fakeMarkets{
+		groups: map[int64]*dmarkets.MarketGroup{
+			4: {ID: 4, Members: []dmarkets.MarketGroupMember{{GroupID: 4, MarketID: 11}, {GroupID: 4, MarketID: 12, DisplayOrder: 1}}},
+		},
+		probabilities: map[int64]float64{7: 0.5, 11: 0.3, 12: 0.7},
+	}
+	return NewHub(log, markets, config), log, markets
+}
+
+func TestHubOpenReplaysMissedEventsThenSnapshot(t *testing.T) {
+	hub, log, _ := newTestHub(Config{})
+	log.events = []devents.Event{
+		{ID: 1, Type: devents.BetPlaced, MarketID: 7, Username: "alice"},
+		{ID: 2, Type: devents.BetPlaced, MarketID: 8, Username: "bob"},
+		{ID: 3, Type: devents.MarketResolved, MarketID: 7, Data: map[string]any{"resolution": "YES"}},
+	}
+
+	stream, err := hub.Open(context.Background(), dlivestream.MarketTopic(7), "client", 1)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer stream.Close()
+
+	replay := stream.Replay()
+	if len(replay) != 2 || replay[0].ID != 3 || replay[0].Event != dlivestream.EventStatus || replay[1].Event != dlivestream.EventProbability {
+		t.Fatalf("unexpected replay: %+v", replay)
+	}
+	if stream.ReplayedThrough() != 3 {
+		t.Fatalf("ReplayedThrough = %d, want 3", stream.ReplayedThrough())
+	}
+
+	fresh, err := hub.Open(context.Background(), dlivestream.MarketTopic(7), "client", 0)
+	if err != nil {
+		t.Fatalf("Open without cursor returned error: %v", err)
+	}
+	defer fresh.Close()
+	if got := fresh.Replay(); len(got) != 1 || got[0].ID != 3 || got[0].Data.(dlivestream.ProbabilityPayload).Probability != 0.5 {
+		t.Fatalf("fresh stream replay = %+v", got)
+	}
+}
+
+func TestHubOpenSendsResetWhenGapExceedsReplayLimit(t *testing.T) {
+	hub, log, _ := newTestHub(Config{ReplayLimit: 1})
+	log.events = []devents.Event{
+		{ID: 1, Type: devents.BetPlaced, MarketID: 7},
+		{ID: 2, Type: devents.BetPlaced, MarketID: 7},
+		{ID: 3, Type: devents.BetPlaced, MarketID: 7},
+	}
+
+	stream, err := hub.Open(context.Background(), dlivestream.MarketTopic(7), "client", 1)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer stream.Close()
+	if replay := stream.Replay(); len(replay) != 2 || replay[0].Event != dlivestream.EventReset || replay[0].ID != 3 {
+		t.Fatalf("unexpected replay: %+v", replay)
+	}
+}
+
+func TestHubHandleEventRoutesTradesToMarketAndGroupStreams(t *testing.T) {
+	hub, _, markets := newTestHub(Config{})
+	ctx := context.Background()
+	marketStream, err := hub.Open(ctx, dlivestream.MarketTopic(11), "a", 0)
+	if err != nil {
+		t.Fatalf("Open market: %v", err)
+	}
+	groupStream, err := hub.Open(ctx, dlivestream.GroupTopic(4), "b", 0)
+	if err != nil {
+		t.Fatalf("Open group: %v", err)
+	}
+	otherStream, err := hub.Open(ctx, dlivestream.MarketTopic(7), "c", 0)
+	if err != nil {
+		t.Fatalf("Open other: %v", err)
+	}
+	if len(groupStream.Replay()) != 2 {
+		t.Fatalf("group snapshot should cover both answers: %+v", groupStream.Replay())
+	}
+
+	if err := hub.HandleEvent(ctx, devents.Event{ID: 9, Type: devents.BetPlaced, MarketID: 11, Username: "alice", Data: map[string]any{"amount": 10}}); err != nil {
+		t.Fatalf("HandleEvent returned error: %v", err)
+	}
+	if err := hub.HandleEvent(ctx, devents.Event{ID: 10, Type: devents.TagCatalogChanged}); err != nil {
+		t.Fatalf("HandleEvent returned error: %v", err)
+	}
+
+	for name, stream := range map[string]dlivestream.Stream{"market": marketStream, "group": groupStream} {
+		trade := <-stream.Messages()
+		probability := <-stream.Messages()
+		if trade.ID != 9 || trade.Event != dlivestream.EventTrade || probability.Event != dlivestream.EventProbability {
+			t.Fatalf("%s stream got %+v then %+v", name, trade, probability)
+		}
+		if payload := probability.Data.(dlivestream.ProbabilityPayload); payload.MarketID != 11 || payload.Probability != 0.3 {
+			t.Fatalf("%s probability payload = %+v", name, payload)
+		}
+	}
+	select {
+	case msg := <-otherStream.Messages():
+		t.Fatalf("unrelated stream received %+v", msg)
+	default:
+	}
+	if markets.groupLookups != 0 {
+		t.Fatalf("group membership should come from the opened group, looked up %d times", markets.groupLookups)
+	}
+}
+
+func TestHubEnforcesLimitsAndClosesSlowStreams(t *testing.T) {
+	hub, _, _ := newTestHub(Config{MaxStreamsPerClient: 1, Buffer: 1})
+	ctx := context.Background()
+	stream, err := hub.Open(ctx, dlivestream.MarketTopic(7), "client", 0)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if _, err := hub.Open(ctx, dlivestream.MarketTopic(7), "client", 0); !errors.Is(err, dlivestream.ErrTooManyStreams) {
+		t.Fatalf("second Open error = %v, want ErrTooManyStreams", err)
+	}
+	if _, err := hub.Open(ctx, dlivestream.MarketTopic(99), "other", 0); !errors.Is(err, dmarkets.ErrMarketNotFound) {
+		t.Fatalf("unknown market error = %v", err)
+	}
+
+	_ = hub.HandleEvent(ctx, devents.Event{ID: 1, Type: devents.BetPlaced, MarketID: 7})
+	if _, ok := <-stream.Messages(); !ok {
+		t.Fatalf("expected the buffered trade frame before close")
+	}
+	if _, ok := <-stream.Messages(); ok {
+		t.Fatalf("slow stream should be closed once its buffer overflows")
+	}
+	if hub.OpenStreams() != 0 {
+		t.Fatalf("closed stream still counted: %d", hub.OpenStreams())
+	}
+
+	if _, err := hub.Open(ctx, dlivestream.MarketTopic(7), "client", 0); err != nil {
+		t.Fatalf("slot was not released: %v", err)
+	}
+	hub.Close()
+	if hub.OpenStreams() != 0 {
+		t.Fatalf("Close left %d streams open", hub.OpenStreams())
+	}
+	if _, err := hub.Open(ctx, dlivestream.MarketTopic(7), "client", 0); !errors.Is(err, dlivestream.ErrClosed) {
+		t.Fatalf("Open after Close error = %v, want ErrClosed", err)
+	}
+}
+
+type anonymousTraders map[string]bool
+
+func (a anonymousTraders) AnonymousTraders(context.Context, []string) (map[string]bool, error) {
+	return a, nil
+}
+
+func TestHubMasksAnonymousTraders(t *testing.T) {
+	hub, _, _ := newTestHub(Config{})
+	hub.SetTradePrivacy(anonymousTraders{"alice": true})
+	ctx := context.Background()
+	stream, err := hub.Open(ctx, dlivestream.MarketTopic(11), "a", 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	for id, username := range map[int64]string{9: "alice", 10: "bob"} {
+		if err := hub.HandleEvent(ctx, devents.Event{ID: id, Type: devents.BetPlaced, MarketID: 11, Username: username}); err != nil {
+			t.Fatalf("HandleEvent returned error: %v", err)
+		}
+		trade := <-stream.Messages()
+		<-stream.Messages()
+		want := username
+		if username == "alice" {
+			want = dprivacy.AnonymousUsername
+		}
+		if payload := trade.Data.(dlivestream.TradePayload); payload.Username != want {
+			t.Fatalf("trade by %s streamed as %q, want %q", username, payload.Username, want)
+		}
+	}
+}
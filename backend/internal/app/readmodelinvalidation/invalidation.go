@@ -3,6 +3,8 @@ package readmodelinvalidation
 import (
 	"context"
 	"errors"
+
+	devents "socialpredict/internal/domain/events"
 )
 
 // MarketInvalidator marks market-owned display read models stale.
@@ -18,6 +20,12 @@ type AnalyticsInvalidator interface {
 	MarkAnalyticsReadModelsStale(ctx context.Context, reason string) error
 }
 
+// BulkUserInvalidator optionally marks every user's financial read model
+// stale, for events that move all balances at once.
+type BulkUserInvalidator interface {
+	MarkUserFinancialMetricSnapshotsStale(ctx context.Context, reason string) error
+}
+
 // DiscoveryInvalidator marks page/card discovery read models stale.
 type DiscoveryInvalidator interface {
 	MarkMarketDiscoverySnapshotsStale(ctx context.Context, reason string) error
@@ -71,3 +79,41 @@ func (s *Service) InvalidateAfterMarketTransaction(ctx context.Context, username
 	}
 	return errors.Join(errs...)
 }
+
+// HandleEvent subscribes the invalidator to the domain event bus. Events that
+// change a market's trades, settlement, or membership mark every display read
+// model for that market stale; catalogue and governance events only affect
+// discovery pages and cards. Season rollovers mark every user's financial and
+// aggregate analytics read models stale.
+func (s *Service) HandleEvent(ctx context.Context, event devents.Event) error {
+	if s == nil {
+		return nil
+	}
+	reason := string(event.Type)
+	switch event.Type {
+	case devents.BetPlaced, devents.SharesSold, devents.MarketCreated, devents.MarketResolved, devents.AnswerAdded:
+		if event.MarketID > 0 {
+			return s.InvalidateAfterMarketTransaction(ctx, event.Username, event.MarketID, reason)
+		}
+	case devents.SeasonArchived:
+		return s.invalidateAllUsers(ctx, reason)
+	}
+	if s.discovery == nil {
+		return nil
+	}
+	return s.discovery.MarkMarketDiscoverySnapshotsStale(ctx, reason)
+}
+
+// invalidateAllUsers marks every user-derived read model stale after a season
+// rollover, which may reset every balance.
+func (s *Service) invalidateAllUsers(ctx context.Context, reason string) error {
+	if s.analytics == nil {
+		return nil
+	}
+	if bulk, ok := s.analytics.(BulkUserInvalidator); ok {
+		if err := bulk.MarkUserFinancialMetricSnapshotsStale(ctx, reason); err != nil {
+			return err
+		}
+	}
+	return s.analytics.MarkAnalyticsReadModelsStale(ctx, reason)
+}
@@ -398,6 +398,20 @@ func isProductionRuntime() bool {
 	return env == "prod" || env == "production"
 }
 
+func isDevelopmentRuntime() bool {
+	switch strings.ToLower(firstNonEmpty(
+		os.Getenv("APP_ENV"),
+		os.Getenv("APP_ENVIRONMENT"),
+		os.Getenv("ENVIRONMENT"),
+		os.Getenv("GO_ENV"),
+	)) {
+	case "dev", "development", "local", "localhost":
+		return true
+	default:
+		return false
+	}
+}
+
 func validateSSLMode(sslMode string, requireTLS bool) error {
 	switch sslMode {
 	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
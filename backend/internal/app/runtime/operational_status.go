@@ -97,3 +97,15 @@ func (w *operationalStatusRecorder) Write(data []byte) (int, error) {
 	}
 	return w.ResponseWriter.Write(data)
 }
+
+// Flush passes through to the wrapped writer so streaming responses such as
+// server-sent events reach the client.
+func (w *operationalStatusRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *operationalStatusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -64,3 +64,20 @@ func TestOperationalSnapshotJSONUsesOperatorFacingCounterName(t *testing.T) {
 		t.Fatalf("unexpected operational snapshot JSON: %s", got)
 	}
 }
+
+func TestOperationalMetricsMiddlewarePassesFlushThrough(t *testing.T) {
+	metrics := NewOperationalMetrics()
+	handler := metrics.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush returned error: %v", err)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/markets/1/stream", nil))
+
+	if !rec.Flushed {
+		t.Fatalf("expected the recorder to be flushed")
+	}
+}
@@ -2,6 +2,8 @@ package runtime
 
 import (
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -31,6 +33,80 @@ type SecurityConfig struct {
 	Headers           security.SecurityHeaders
 	Share             ShareConfig
 	RateLimit         security.RateLimitConfig
+	OIDC              OIDCConfig
+	LoginLockout      LoginLockoutConfig
+	Email             EmailConfig
+	Webhooks          WebhooksConfig
+}
+
+// WebhooksConfig describes which destinations outbound webhooks may reach.
+// Plain http and internal addresses are only allowed in development.
+type WebhooksConfig struct {
+	AllowInsecureTargets bool
+}
+
+// LoginLockoutConfig describes the per-account password failure schedule:
+// FreeAttempts undelayed failures, then a delay starting at BaseDelay and
+// doubling up to MaxDelay, and a Duration-long lockout after Threshold failures.
+type LoginLockoutConfig struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Threshold    int
+	Duration     time.Duration
+}
+
+// DefaultLoginLockoutConfig returns the secure-default failure schedule.
+func DefaultLoginLockoutConfig() LoginLockoutConfig {
+	return LoginLockoutConfig{
+		FreeAttempts: 3,
+		BaseDelay:    time.Second,
+		MaxDelay:     5 * time.Minute,
+		Threshold:    10,
+		Duration:     15 * time.Minute,
+	}
+}
+
+// OIDCConfig describes the optional OpenID Connect single sign-on client and
+// the policy for provisioning accounts on first sign-in.
+type OIDCConfig struct {
+	IssuerURL           string
+	ClientID            string
+	ClientSecret        string
+	RedirectURL         string
+	Scopes              []string
+	AutoProvision       bool
+	AllowedEmailDomains []string
+	AllowedEmails       []string
+}
+
+// Enabled reports whether single sign-on is configured.
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+// Email transports.
+const (
+	EmailTransportNone = "none"
+	EmailTransportSMTP = "smtp"
+	EmailTransportFile = "file"
+)
+
+// EmailConfig describes how opt-in email leaves the process: through an SMTP
+// relay, into a directory of .eml files for local testing, or not at all.
+type EmailConfig struct {
+	Transport    string
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FileSinkDir  string
+}
+
+// Enabled reports whether email is sent anywhere.
+func (c EmailConfig) Enabled() bool {
+	return c.Transport == EmailTransportSMTP || c.Transport == EmailTransportFile
 }
 
 // ShareConfig describes public market sharing metadata owned by runtime config.
@@ -56,6 +132,18 @@ func LoadSecurityConfigFromEnv() (SecurityConfig, error) {
 		return SecurityConfig{}, err
 	}
 	rateLimit.TrustProxyHeaders = trustProxyHeaders
+	oidcConfig, err := oidcConfigFromEnv()
+	if err != nil {
+		return SecurityConfig{}, err
+	}
+	loginLockout, err := loginLockoutConfigFromEnv()
+	if err != nil {
+		return SecurityConfig{}, err
+	}
+	emailConfig, err := emailConfigFromEnv()
+	if err != nil {
+		return SecurityConfig{}, err
+	}
 
 	return SecurityConfig{
 		JWTSigningKey:     signingKey,
@@ -75,10 +163,88 @@ func LoadSecurityConfigFromEnv() (SecurityConfig, error) {
 			DefaultImageURL: strings.TrimSpace(os.Getenv("SHARE_DEFAULT_IMAGE_URL")),
 			SiteName:        getRuntimeStringEnv("SHARE_SITE_NAME", "SocialPredict"),
 		},
-		RateLimit: rateLimit,
+		RateLimit:    rateLimit,
+		OIDC:         oidcConfig,
+		LoginLockout: loginLockout,
+		Email:        emailConfig,
+		Webhooks:     WebhooksConfig{AllowInsecureTargets: isDevelopmentRuntime()},
 	}, nil
 }
 
+func emailConfigFromEnv() (EmailConfig, error) {
+	transport := strings.ToLower(getRuntimeStringEnv("EMAIL_TRANSPORT", EmailTransportNone))
+	if transport == EmailTransportNone {
+		return EmailConfig{Transport: EmailTransportNone}, nil
+	}
+	port, err := getRuntimePositiveIntEnv("SMTP_PORT", 587)
+	if err != nil {
+		return EmailConfig{}, err
+	}
+	config := EmailConfig{
+		Transport:    transport,
+		From:         strings.TrimSpace(os.Getenv("EMAIL_FROM")),
+		SMTPHost:     strings.TrimSpace(os.Getenv("SMTP_HOST")),
+		SMTPPort:     port,
+		SMTPUsername: strings.TrimSpace(os.Getenv("SMTP_USERNAME")),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		FileSinkDir:  strings.TrimSpace(os.Getenv("EMAIL_FILE_SINK_DIR")),
+	}
+	if _, err := mail.ParseAddress(config.From); err != nil {
+		return EmailConfig{}, fmt.Errorf("security config: EMAIL_FROM must be an email address when EMAIL_TRANSPORT is %s", transport)
+	}
+	switch transport {
+	case EmailTransportSMTP:
+		if config.SMTPHost == "" {
+			return EmailConfig{}, fmt.Errorf("security config: SMTP_HOST is required when EMAIL_TRANSPORT is smtp")
+		}
+	case EmailTransportFile:
+		if config.FileSinkDir == "" {
+			return EmailConfig{}, fmt.Errorf("security config: EMAIL_FILE_SINK_DIR is required when EMAIL_TRANSPORT is file")
+		}
+	default:
+		return EmailConfig{}, fmt.Errorf("security config: EMAIL_TRANSPORT must be none, smtp, or file")
+	}
+	return config, nil
+}
+
+func oidcConfigFromEnv() (OIDCConfig, error) {
+	config := OIDCConfig{
+		IssuerURL:           strings.TrimRight(strings.TrimSpace(os.Getenv("OIDC_ISSUER_URL")), "/"),
+		ClientID:            strings.TrimSpace(os.Getenv("OIDC_CLIENT_ID")),
+		ClientSecret:        strings.TrimSpace(os.Getenv("OIDC_CLIENT_SECRET")),
+		RedirectURL:         strings.TrimSpace(os.Getenv("OIDC_REDIRECT_URL")),
+		Scopes:              getRuntimeListEnv("OIDC_SCOPES", "openid,email,profile"),
+		AutoProvision:       getRuntimeBoolEnv("OIDC_AUTO_PROVISION", false),
+		AllowedEmailDomains: getRuntimeListEnv("OIDC_ALLOWED_EMAIL_DOMAINS", ""),
+		AllowedEmails:       getRuntimeListEnv("OIDC_ALLOWED_EMAILS", ""),
+	}
+	if !config.Enabled() {
+		return OIDCConfig{}, nil
+	}
+
+	if err := requireAbsoluteURL("OIDC_ISSUER_URL", config.IssuerURL); err != nil {
+		return OIDCConfig{}, err
+	}
+	if err := requireAbsoluteURL("OIDC_REDIRECT_URL", config.RedirectURL); err != nil {
+		return OIDCConfig{}, err
+	}
+	if config.ClientID == "" {
+		return OIDCConfig{}, fmt.Errorf("security config: OIDC_CLIENT_ID is required when OIDC_ISSUER_URL is set")
+	}
+	if config.AutoProvision && len(config.AllowedEmailDomains) == 0 && len(config.AllowedEmails) == 0 {
+		return OIDCConfig{}, fmt.Errorf("security config: OIDC_AUTO_PROVISION requires OIDC_ALLOWED_EMAIL_DOMAINS or OIDC_ALLOWED_EMAILS")
+	}
+	return config, nil
+}
+
+func requireAbsoluteURL(key, value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return fmt.Errorf("security config: %s must be an absolute http(s) URL", key)
+	}
+	return nil
+}
+
 func rateLimitConfigFromEnv() (security.RateLimitConfig, error) {
 	config := security.DefaultRateLimitConfig()
 	var err error
@@ -102,6 +268,35 @@ func rateLimitConfigFromEnv() (security.RateLimitConfig, error) {
 	return config, nil
 }
 
+func loginLockoutConfigFromEnv() (LoginLockoutConfig, error) {
+	config := DefaultLoginLockoutConfig()
+	var err error
+
+	if config.FreeAttempts, err = getRuntimePositiveIntEnv("LOGIN_LOCKOUT_FREE_ATTEMPTS", config.FreeAttempts); err != nil {
+		return LoginLockoutConfig{}, err
+	}
+	if config.BaseDelay, err = getRuntimePositiveDurationEnv("LOGIN_LOCKOUT_BASE_DELAY", config.BaseDelay); err != nil {
+		return LoginLockoutConfig{}, err
+	}
+	if config.MaxDelay, err = getRuntimePositiveDurationEnv("LOGIN_LOCKOUT_MAX_DELAY", config.MaxDelay); err != nil {
+		return LoginLockoutConfig{}, err
+	}
+	if config.Threshold, err = getRuntimePositiveIntEnv("LOGIN_LOCKOUT_THRESHOLD", config.Threshold); err != nil {
+		return LoginLockoutConfig{}, err
+	}
+	if config.Duration, err = getRuntimePositiveDurationEnv("LOGIN_LOCKOUT_DURATION", config.Duration); err != nil {
+		return LoginLockoutConfig{}, err
+	}
+	if config.MaxDelay < config.BaseDelay {
+		return LoginLockoutConfig{}, fmt.Errorf("security config: LOGIN_LOCKOUT_MAX_DELAY must not be shorter than LOGIN_LOCKOUT_BASE_DELAY")
+	}
+	if config.Threshold <= config.FreeAttempts {
+		return LoginLockoutConfig{}, fmt.Errorf("security config: LOGIN_LOCKOUT_THRESHOLD must be greater than LOGIN_LOCKOUT_FREE_ATTEMPTS")
+	}
+
+	return config, nil
+}
+
 func applyFrameAncestors(headers *security.SecurityHeaders, ancestors []string) {
 	if headers == nil {
 		return
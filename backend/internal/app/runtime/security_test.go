@@ -168,3 +168,159 @@ func TestLoadSecurityConfigFromEnvRejectsInvalidRateLimitOverrides(t *testing.T)
 		})
 	}
 }
+
+func TestLoadSecurityConfigFromEnvOwnsOIDCSettings(t *testing.T) {
+	t.Setenv("JWT_SIGNING_KEY", "test-secret-key")
+	t.Setenv("OIDC_ISSUER_URL", "https://idp.example.com/")
+	t.Setenv("OIDC_CLIENT_ID", "socialpredict")
+	t.Setenv("OIDC_REDIRECT_URL", "https://predict.example.com/auth/oidc/callback")
+	t.Setenv("OIDC_AUTO_PROVISION", "true")
+	t.Setenv("OIDC_ALLOWED_EMAIL_DOMAINS", "example.com, corp.example.com")
+
+	config, err := LoadSecurityConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadSecurityConfigFromEnv returned error: %v", err)
+	}
+	if !config.OIDC.Enabled() || config.OIDC.IssuerURL != "https://idp.example.com" {
+		t.Fatalf("unexpected oidc issuer config: %+v", config.OIDC)
+	}
+	if !config.OIDC.AutoProvision || len(config.OIDC.AllowedEmailDomains) != 2 {
+		t.Fatalf("unexpected oidc provisioning config: %+v", config.OIDC)
+	}
+	if len(config.OIDC.Scopes) != 3 || config.OIDC.Scopes[0] != "openid" {
+		t.Fatalf("unexpected default scopes: %v", config.OIDC.Scopes)
+	}
+}
+
+func TestLoadSecurityConfigFromEnvAllowsInsecureWebhookTargetsOnlyInDevelopment(t *testing.T) {
+	tests := map[string]bool{"production": false, "": false, "staging": false, "development": true, "localhost": true}
+	for appEnv, want := range tests {
+		t.Run(appEnv, func(t *testing.T) {
+			t.Setenv("JWT_SIGNING_KEY", "test-secret-key")
+			t.Setenv("APP_ENV", appEnv)
+
+			config, err := LoadSecurityConfigFromEnv()
+			if err != nil {
+				t.Fatalf("LoadSecurityConfigFromEnv returned error: %v", err)
+			}
+			if config.Webhooks.AllowInsecureTargets != want {
+				t.Fatalf("AllowInsecureTargets = %v, want %v", config.Webhooks.AllowInsecureTargets, want)
+			}
+		})
+	}
+}
+
+func TestLoadSecurityConfigFromEnvRejectsIncompleteOIDCSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{name: "missing client id", env: map[string]string{"OIDC_REDIRECT_URL": "https://predict.example.com/cb"}},
+		{name: "relative redirect", env: map[string]string{"OIDC_CLIENT_ID": "sp", "OIDC_REDIRECT_URL": "/cb"}},
+		{name: "provisioning without allow-list", env: map[string]string{"OIDC_CLIENT_ID": "sp", "OIDC_REDIRECT_URL": "https://predict.example.com/cb", "OIDC_AUTO_PROVISION": "true"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SIGNING_KEY", "test-secret-key")
+			t.Setenv("OIDC_ISSUER_URL", "https://idp.example.com")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			if _, err := LoadSecurityConfigFromEnv(); err == nil {
+				t.Fatalf("expected error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestLoadSecurityConfigFromEnvOwnsEmailSettings(t *testing.T) {
+	t.Setenv("JWT_SIGNING_KEY", "test-secret-key")
+
+	config, err := LoadSecurityConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadSecurityConfigFromEnv returned error: %v", err)
+	}
+	if config.Email.Enabled() {
+		t.Fatalf("email should be off by default: %+v", config.Email)
+	}
+
+	t.Setenv("EMAIL_TRANSPORT", "SMTP")
+	t.Setenv("EMAIL_FROM", "SocialPredict <noreply@example.com>")
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	config, err = LoadSecurityConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadSecurityConfigFromEnv returned error: %v", err)
+	}
+	if !config.Email.Enabled() || config.Email.Transport != EmailTransportSMTP || config.Email.SMTPPort != 587 {
+		t.Fatalf("unexpected smtp config: %+v", config.Email)
+	}
+
+	for name, env := range map[string]map[string]string{
+		"unknown transport": {"EMAIL_TRANSPORT": "carrier-pigeon"},
+		"smtp without host": {"SMTP_HOST": ""},
+		"file without dir":  {"EMAIL_TRANSPORT": "file"},
+		"missing sender":    {"EMAIL_FROM": ""},
+		"non-numeric port":  {"SMTP_PORT": "submission"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+			if _, err := LoadSecurityConfigFromEnv(); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		})
+	}
+}
+
+func TestLoadSecurityConfigFromEnvOwnsLoginLockoutSettings(t *testing.T) {
+	t.Setenv("JWT_SIGNING_KEY", "test-secret-key")
+
+	config, err := LoadSecurityConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadSecurityConfigFromEnv returned error: %v", err)
+	}
+	if config.LoginLockout != DefaultLoginLockoutConfig() {
+		t.Fatalf("unexpected default lockout config: %+v", config.LoginLockout)
+	}
+
+	t.Setenv("LOGIN_LOCKOUT_FREE_ATTEMPTS", "5")
+	t.Setenv("LOGIN_LOCKOUT_BASE_DELAY", "2s")
+	t.Setenv("LOGIN_LOCKOUT_MAX_DELAY", "1m")
+	t.Setenv("LOGIN_LOCKOUT_THRESHOLD", "8")
+	t.Setenv("LOGIN_LOCKOUT_DURATION", "1h")
+
+	config, err = LoadSecurityConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadSecurityConfigFromEnv returned error: %v", err)
+	}
+	want := LoginLockoutConfig{FreeAttempts: 5, BaseDelay: 2 * time.Second, MaxDelay: time.Minute, Threshold: 8, Duration: time.Hour}
+	if config.LoginLockout != want {
+		t.Fatalf("lockout config = %+v, want %+v", config.LoginLockout, want)
+	}
+}
+
+func TestLoadSecurityConfigFromEnvRejectsInconsistentLoginLockout(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{name: "threshold not above free attempts", env: map[string]string{"LOGIN_LOCKOUT_FREE_ATTEMPTS": "5", "LOGIN_LOCKOUT_THRESHOLD": "5"}},
+		{name: "max delay below base delay", env: map[string]string{"LOGIN_LOCKOUT_BASE_DELAY": "10s", "LOGIN_LOCKOUT_MAX_DELAY": "1s"}},
+		{name: "invalid duration", env: map[string]string{"LOGIN_LOCKOUT_DURATION": "forever"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SIGNING_KEY", "test-secret-key")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if _, err := LoadSecurityConfigFromEnv(); err == nil {
+				t.Fatalf("expected error for %v", tt.env)
+			}
+		})
+	}
+}
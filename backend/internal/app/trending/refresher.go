@@ -0,0 +1,56 @@
+package trending
+
+import (
+	"context"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/logger"
+)
+
+const defaultInterval = 15 * time.Minute
+
+// Scorer materializes market trending scores.
+type Scorer interface {
+	RefreshTrendingScores(ctx context.Context, now time.Time) ([]dmarkets.MarketTrendingScore, error)
+}
+
+// Refresher periodically recomputes the trending scores behind sort=trending
+// and the discovery trending section.
+type Refresher struct {
+	scorer   Scorer
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewRefresher builds a refresher. A non-positive interval refreshes every
+// fifteen minutes.
+func NewRefresher(scorer Scorer, interval time.Duration, now func() time.Time) *Refresher {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Refresher{scorer: scorer, interval: interval, now: now}
+}
+
+// Run refreshes the scores now and then once per interval until ctx is
+// cancelled.
+func (r *Refresher) Run(ctx context.Context) {
+	if r == nil || r.scorer == nil {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.scorer.RefreshTrendingScores(ctx, r.now().UTC()); err != nil && ctx.Err() == nil {
+			logger.LogError("trending", "RefreshTrendingScores", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
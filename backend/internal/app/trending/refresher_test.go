@@ -0,0 +1,46 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+type fakeScorer struct {
+	refreshed chan time.Time
+}
+
+func (s *fakeScorer) RefreshTrendingScores(_ context.Context, now time.Time) ([]dmarkets.MarketTrendingScore, error) {
+	s.refreshed <- now
+	return nil, nil
+}
+
+func TestRunRefreshesImmediatelyAndStopsOnCancel(t *testing.T) {
+	scorer := &fakeScorer{refreshed: make(chan time.Time, 1)}
+	at := time.Date(2026, 7, 11, 9, 0, 0, 0, time.FixedZone("plus2", 2*60*60))
+	refresher := NewRefresher(scorer, time.Hour, func() time.Time { return at })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		refresher.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case got := <-scorer.refreshed:
+		if !got.Equal(at) || got.Location() != time.UTC {
+			t.Fatalf("refreshed at %s, want %s in UTC", got, at)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected an immediate refresh")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Run did not stop after cancel")
+	}
+}
@@ -0,0 +1,348 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/boundary"
+	positionsmath "socialpredict/internal/domain/math/positions"
+	"socialpredict/internal/domain/math/probabilities/wpam"
+)
+
+const (
+	// calibrationBucketCount splits forecasts into tenths.
+	calibrationBucketCount = 10
+	// logScoreFloor keeps a confident miss from scoring negative infinity.
+	logScoreFloor = 0.01
+	// MinimumSkillLeaderboardMarkets is how many resolved markets a user
+	// needs before ranking on the skill leaderboard, so one lucky call does
+	// not top it.
+	MinimumSkillLeaderboardMarkets = 5
+)
+
+// ResolvedMarketRecord is a market resolved YES or NO, with the instant it
+// resolved. Markets resolved N/A carry no forecasting signal.
+type ResolvedMarketRecord struct {
+	ID               uint
+	CreatedAt        time.Time
+	ResolvedAt       time.Time
+	ResolutionResult string
+}
+
+// CalibrationRepository optionally exposes resolved markets for forecasting
+// skill scores.
+type CalibrationRepository interface {
+	ListResolvedBinaryMarkets(ctx context.Context) ([]ResolvedMarketRecord, error)
+	ListBetsForMarket(ctx context.Context, marketID uint) ([]boundary.Bet, error)
+}
+
+// CalibrationBucket compares forecasts in [Lower, Upper) with how often those
+// markets resolved YES. The last bucket includes 1.
+type CalibrationBucket struct {
+	Lower             float64 `json:"lower"`
+	Upper             float64 `json:"upper"`
+	Forecasts         int     `json:"forecasts"`
+	MeanForecast      float64 `json:"meanForecast"`
+	RealizedFrequency float64 `json:"realizedFrequency"`
+}
+
+// UserCalibration scores one user's forecasts over resolved markets. Each
+// market contributes one forecast: the time-weighted probability the user
+// implied while holding a position, where each trade implies the market
+// probability right after it. BrierScore is the mean squared error (lower is
+// better); LogScore is the mean log probability given to the actual outcome
+// (closer to zero is better).
+type UserCalibration struct {
+	Username        string              `json:"username"`
+	ResolvedMarkets int                 `json:"resolvedMarkets"`
+	BrierScore      float64             `json:"brierScore"`
+	LogScore        float64             `json:"logScore"`
+	Buckets         []CalibrationBucket `json:"buckets"`
+}
+
+// SkillLeaderboardEntry ranks a user by forecasting skill rather than profit.
+type SkillLeaderboardEntry struct {
+	Username        string  `json:"username"`
+	ResolvedMarkets int     `json:"resolvedMarkets"`
+	BrierScore      float64 `json:"brierScore"`
+	LogScore        float64 `json:"logScore"`
+	Rank            int     `json:"rank"`
+}
+
+// CalibrationSnapshot holds every scored user, sorted by username.
+type CalibrationSnapshot struct {
+	Users []UserCalibration
+}
+
+// User returns the named user's calibration, or nil when they have no
+// scored forecasts.
+func (s *CalibrationSnapshot) User(username string) *UserCalibration {
+	if s == nil {
+		return nil
+	}
+	i := sort.Search(len(s.Users), func(i int) bool { return s.Users[i].Username >= username })
+	if i < len(s.Users) && s.Users[i].Username == username {
+		return &s.Users[i]
+	}
+	return nil
+}
+
+// SkillLeaderboard ranks users with at least MinimumSkillLeaderboardMarkets
+// scored markets by Brier score, breaking ties by more markets and then by
+// username. Limit and offset are bounded like the profit leaderboard.
+func (s *CalibrationSnapshot) SkillLeaderboard(limit, offset int) []SkillLeaderboardEntry {
+	entries := []SkillLeaderboardEntry{}
+	if s != nil {
+		for _, user := range s.Users {
+			if user.ResolvedMarkets < MinimumSkillLeaderboardMarkets {
+				continue
+			}
+			entries = append(entries, SkillLeaderboardEntry{
+				Username:        user.Username,
+				ResolvedMarkets: user.ResolvedMarkets,
+				BrierScore:      user.BrierScore,
+				LogScore:        user.LogScore,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].BrierScore != entries[j].BrierScore {
+			return entries[i].BrierScore < entries[j].BrierScore
+		}
+		if entries[i].ResolvedMarkets != entries[j].ResolvedMarkets {
+			return entries[i].ResolvedMarkets > entries[j].ResolvedMarkets
+		}
+		return entries[i].Username < entries[j].Username
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(entries) {
+		return []SkillLeaderboardEntry{}
+	}
+	end := offset + limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+	return entries[offset:end]
+}
+
+// WithProbabilityCalculator sets the WPAM calculator used to replay market
+// probabilities for calibration. It defaults to the stock seeds.
+func WithProbabilityCalculator(calculator wpam.ProbabilityCalculator) ServiceOption {
+	return func(s *Service) {
+		if s != nil {
+			s.probabilities = &calculator
+		}
+	}
+}
+
+// ComputeCalibrationSnapshot scores every user's forecasts over markets
+// resolved YES or NO.
+func (s *Service) ComputeCalibrationSnapshot(ctx context.Context) (*CalibrationSnapshot, error) {
+	repo, ok := s.repo.(CalibrationRepository)
+	if !ok {
+		return nil, errors.New("calibration repository not provided")
+	}
+	markets, err := repo.ListResolvedBinaryMarkets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calculator := wpam.NewProbabilityCalculator(nil)
+	if s.probabilities != nil {
+		calculator = *s.probabilities
+	}
+	positions := positionsmath.NewPositionCalculator(
+		positionsmath.WithProbabilityProvider(positionsmath.NewWPAMProbabilityProvider(calculator)),
+	)
+	forecasts := make(map[string][]forecast)
+	for _, market := range markets {
+		outcome, ok := resolvedOutcome(market.ResolutionResult)
+		if !ok {
+			continue
+		}
+		bets, err := repo.ListBetsForMarket(ctx, market.ID)
+		if err != nil {
+			return nil, err
+		}
+		changes := calculator.CalculateMarketProbabilitiesWPAM(market.CreatedAt, bets)
+		snapshot := positionsmath.MarketSnapshot{ID: int64(market.ID), CreatedAt: market.CreatedAt}
+		implied, err := impliedForecasts(positions, snapshot, bets, changes, market.ResolvedAt)
+		if err != nil {
+			return nil, err
+		}
+		for username, probability := range implied {
+			forecasts[username] = append(forecasts[username], forecast{probability: probability, outcome: outcome})
+		}
+	}
+
+	users := make([]UserCalibration, 0, len(forecasts))
+	for username, userForecasts := range forecasts {
+		users = append(users, scoreForecasts(username, userForecasts))
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return &CalibrationSnapshot{Users: users}, nil
+}
+
+type forecast struct {
+	probability float64
+	outcome     float64
+}
+
+func resolvedOutcome(result string) (float64, bool) {
+	switch strings.ToUpper(strings.TrimSpace(result)) {
+	case "YES":
+		return 1, true
+	case "NO":
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// impliedForecasts returns each trader's time-weighted forecast in one
+// market. changes[i+1] is the probability right after bets[i]; a trade's
+// forecast stands until the trader's next trade or resolution, and time
+// spent with no position counts for nothing. Holdings are kept in shares: a
+// buy adds the shares the position math gives it, and a sale, whose amount
+// is already shares, takes them away.
+func impliedForecasts(positions positionsmath.PositionCalculator, snapshot positionsmath.MarketSnapshot, bets []boundary.Bet, changes []wpam.ProbabilityChange, resolvedAt time.Time) (map[string]float64, error) {
+	type trader struct {
+		shares   map[string]int64
+		current  float64
+		since    time.Time
+		weighted float64
+		seconds  float64
+	}
+	holding := func(t *trader) bool {
+		for _, shares := range t.shares {
+			if shares > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	traders := make(map[string]*trader)
+	settle := func(t *trader, until time.Time) {
+		if holding(t) && until.After(t.since) {
+			seconds := until.Sub(t.since).Seconds()
+			t.weighted += t.current * seconds
+			t.seconds += seconds
+		}
+	}
+
+	before := map[string]positionsmath.MarketPosition{}
+	for i, bet := range bets {
+		if i+1 >= len(changes) || bet.PlacedAt.After(resolvedAt) {
+			break
+		}
+		after, err := positionsByUser(positions, snapshot, bets[:i+1])
+		if err != nil {
+			return nil, err
+		}
+		t := traders[bet.Username]
+		if t == nil {
+			t = &trader{shares: make(map[string]int64)}
+			traders[bet.Username] = t
+		}
+		settle(t, bet.PlacedAt)
+		if bet.Amount >= 0 {
+			if bought := outcomeShares(after[bet.Username], bet.Outcome) - outcomeShares(before[bet.Username], bet.Outcome); bought > 0 {
+				t.shares[bet.Outcome] += bought
+			}
+		} else {
+			t.shares[bet.Outcome] += bet.Amount
+			if t.shares[bet.Outcome] < 0 {
+				t.shares[bet.Outcome] = 0
+			}
+		}
+		t.current = changes[i+1].Probability
+		t.since = bet.PlacedAt
+		before = after
+	}
+
+	forecasts := make(map[string]float64, len(traders))
+	for username, t := range traders {
+		settle(t, resolvedAt)
+		if t.seconds > 0 {
+			forecasts[username] = t.weighted / t.seconds
+		}
+	}
+	return forecasts, nil
+}
+
+func positionsByUser(positions positionsmath.PositionCalculator, snapshot positionsmath.MarketSnapshot, bets []boundary.Bet) (map[string]positionsmath.MarketPosition, error) {
+	marketPositions, err := positions.CalculateMarketPositions(snapshot, bets)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[string]positionsmath.MarketPosition, len(marketPositions))
+	for _, position := range marketPositions {
+		byUser[position.Username] = position
+	}
+	return byUser, nil
+}
+
+func outcomeShares(position positionsmath.MarketPosition, outcome string) int64 {
+	if outcome == "NO" {
+		return position.NoSharesOwned
+	}
+	return position.YesSharesOwned
+}
+
+func scoreForecasts(username string, forecasts []forecast) UserCalibration {
+	buckets := make([]CalibrationBucket, calibrationBucketCount)
+	sums := make([]struct{ forecast, outcome float64 }, calibrationBucketCount)
+	for i := range buckets {
+		buckets[i].Lower = float64(i) / calibrationBucketCount
+		buckets[i].Upper = float64(i+1) / calibrationBucketCount
+	}
+
+	var brier, logScore float64
+	for _, f := range forecasts {
+		brier += (f.probability - f.outcome) * (f.probability - f.outcome)
+		assigned := f.probability
+		if f.outcome == 0 {
+			assigned = 1 - f.probability
+		}
+		logScore += math.Log(math.Max(assigned, logScoreFloor))
+
+		bucket := int(f.probability * calibrationBucketCount)
+		if bucket >= calibrationBucketCount {
+			bucket = calibrationBucketCount - 1
+		}
+		buckets[bucket].Forecasts++
+		sums[bucket].forecast += f.probability
+		sums[bucket].outcome += f.outcome
+	}
+	for i := range buckets {
+		if n := float64(buckets[i].Forecasts); n > 0 {
+			buckets[i].MeanForecast = sums[i].forecast / n
+			buckets[i].RealizedFrequency = sums[i].outcome / n
+		}
+	}
+
+	n := float64(len(forecasts))
+	return UserCalibration{
+		Username:        username,
+		ResolvedMarkets: len(forecasts),
+		BrierScore:      brier / n,
+		LogScore:        logScore / n,
+		Buckets:         buckets,
+	}
+}
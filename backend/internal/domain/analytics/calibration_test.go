@@ -0,0 +1,132 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/boundary"
+	positionsmath "socialpredict/internal/domain/math/positions"
+	"socialpredict/internal/domain/math/probabilities/wpam"
+)
+
+type calibrationRepo struct {
+	Repository
+	markets []ResolvedMarketRecord
+	bets    map[uint][]boundary.Bet
+}
+
+func (r calibrationRepo) ListResolvedBinaryMarkets(context.Context) ([]ResolvedMarketRecord, error) {
+	return r.markets, nil
+}
+
+func (r calibrationRepo) ListBetsForMarket(_ context.Context, marketID uint) ([]boundary.Bet, error) {
+	return r.bets[marketID], nil
+}
+
+func closeTo(got, want float64) bool {
+	return math.Abs(got-want) < 1e-9
+}
+
+func TestComputeCalibrationSnapshotWeightsForecastsByHoldingTime(t *testing.T) {
+	start := time.Date(2026, 7, 6, 9, 0, 0, 0, time.UTC)
+	repo := calibrationRepo{
+		markets: []ResolvedMarketRecord{
+			{ID: 1, CreatedAt: start, ResolvedAt: start.Add(2 * time.Hour), ResolutionResult: "YES"},
+			{ID: 2, CreatedAt: start, ResolvedAt: start.Add(time.Hour), ResolutionResult: "N/A"},
+		},
+		bets: map[uint][]boundary.Bet{
+			// Default seeds start at 0.5 with a subsidy of 1: alice's buy
+			// moves the market to 0.75 and bob's to 0.375. Alice sells out
+			// at 1.5h, so only her first 90 minutes count.
+			1: {
+				{Username: "alice", Outcome: "YES", Amount: 1, PlacedAt: start},
+				{Username: "bob", Outcome: "NO", Amount: 2, PlacedAt: start.Add(time.Hour)},
+				{Username: "alice", Outcome: "YES", Amount: -1, PlacedAt: start.Add(90 * time.Minute)},
+			},
+			2: {{Username: "carol", Outcome: "YES", Amount: 5, PlacedAt: start}},
+		},
+	}
+	svc := NewService(repo, Config{})
+
+	snapshot, err := svc.ComputeCalibrationSnapshot(context.Background())
+	if err != nil {
+		t.Fatalf("ComputeCalibrationSnapshot returned error: %v", err)
+	}
+	if len(snapshot.Users) != 2 || snapshot.User("carol") != nil {
+		t.Fatalf("expected only alice and bob scored, got %+v", snapshot.Users)
+	}
+	alice, bob := snapshot.User("alice"), snapshot.User("bob")
+	if !closeTo(alice.BrierScore, 0.0625) || !closeTo(alice.LogScore, math.Log(0.75)) {
+		t.Fatalf("alice scored %+v", alice)
+	}
+	if !closeTo(bob.BrierScore, 0.390625) || !closeTo(bob.LogScore, math.Log(0.375)) {
+		t.Fatalf("bob scored %+v", bob)
+	}
+	if bucket := alice.Buckets[7]; bucket.Forecasts != 1 || !closeTo(bucket.MeanForecast, 0.75) || bucket.RealizedFrequency != 1 {
+		t.Fatalf("unexpected calibration bucket %+v", bucket)
+	}
+}
+
+func TestComputeCalibrationSnapshotTracksSharesThroughSales(t *testing.T) {
+	start := time.Date(2026, 7, 6, 9, 0, 0, 0, time.UTC)
+	opening := []boundary.Bet{
+		{Username: "alice", Outcome: "YES", Amount: 10, PlacedAt: start},
+		{Username: "bob", Outcome: "NO", Amount: 3, PlacedAt: start.Add(time.Hour)},
+	}
+	held, err := positionsmath.CalculateMarketPositionForUser_WPAM_DBPM(positionsmath.MarketSnapshot{ID: 1, CreatedAt: start}, opening, "bob")
+	if err != nil {
+		t.Fatalf("position: %v", err)
+	}
+	// A sale's amount is shares, not credits. Bob's 3 credits bought fewer
+	// than 3 shares, so selling all of them is a full exit even though it
+	// is less than he spent.
+	if held.NoSharesOwned <= 0 || held.NoSharesOwned >= 3 {
+		t.Fatalf("test needs fewer shares than credits spent, got %+v", held)
+	}
+	bets := append(opening,
+		boundary.Bet{Username: "bob", Outcome: "NO", Amount: -held.NoSharesOwned, PlacedAt: start.Add(2 * time.Hour)},
+		boundary.Bet{Username: "alice", Outcome: "YES", Amount: -4, PlacedAt: start.Add(3 * time.Hour)},
+	)
+	repo := calibrationRepo{
+		markets: []ResolvedMarketRecord{{ID: 1, CreatedAt: start, ResolvedAt: start.Add(11 * time.Hour), ResolutionResult: "YES"}},
+		bets:    map[uint][]boundary.Bet{1: bets},
+	}
+
+	calibration, err := NewService(repo, Config{}).ComputeCalibrationSnapshot(context.Background())
+	if err != nil {
+		t.Fatalf("ComputeCalibrationSnapshot returned error: %v", err)
+	}
+	changes := wpam.NewProbabilityCalculator(nil).CalculateMarketProbabilitiesWPAM(start, bets)
+
+	// Bob's forecast is the probability his buy left, held for the hour
+	// until his sale and no longer.
+	bobForecast := changes[2].Probability
+	if bob := calibration.User("bob"); bob == nil || !closeTo(bob.BrierScore, (bobForecast-1)*(bobForecast-1)) {
+		t.Fatalf("bob forecast should end at his full sale (want %v), got %+v", bobForecast, bob)
+	}
+	// Alice sells part of her shares, so the eight hours from her sale to
+	// resolution count too.
+	aliceForecast := (3*changes[1].Probability + 8*changes[4].Probability) / 11
+	if alice := calibration.User("alice"); alice == nil || !closeTo(alice.BrierScore, (aliceForecast-1)*(aliceForecast-1)) {
+		t.Fatalf("alice forecast should run to resolution (want %v), got %+v", aliceForecast, alice)
+	}
+}
+
+func TestSkillLeaderboardRequiresMinimumMarketsAndRanksByBrier(t *testing.T) {
+	snapshot := &CalibrationSnapshot{Users: []UserCalibration{
+		{Username: "alice", ResolvedMarkets: 5, BrierScore: 0.2},
+		{Username: "bob", ResolvedMarkets: 8, BrierScore: 0.2},
+		{Username: "carol", ResolvedMarkets: 12, BrierScore: 0.1},
+		{Username: "dave", ResolvedMarkets: 2, BrierScore: 0.01},
+	}}
+
+	entries := snapshot.SkillLeaderboard(0, 0)
+	if len(entries) != 3 || entries[0].Username != "carol" || entries[1].Username != "bob" || entries[2].Username != "alice" || entries[2].Rank != 3 {
+		t.Fatalf("unexpected leaderboard %+v", entries)
+	}
+	if page := snapshot.SkillLeaderboard(1, 1); len(page) != 1 || page[0].Username != "bob" || page[0].Rank != 2 {
+		t.Fatalf("unexpected page %+v", page)
+	}
+}
@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// EconomyHistoryDefaultRange is the span returned when no start is given.
+	EconomyHistoryDefaultRange = 90 * 24 * time.Hour
+	// maxEconomyHistoryDays caps how many days one request may span.
+	maxEconomyHistoryDays = 2000
+)
+
+// EconomyActivity counts what happened in the economy over a period.
+// Volume is the absolute amount traded, so sales add to it.
+type EconomyActivity struct {
+	Volume        int64
+	Trades        int64
+	ActiveTraders int64
+	NewMarkets    int64
+	Resolutions   int64
+}
+
+// EconomyDay is one UTC day of the economy history: the system money
+// figures when it was last sampled and the day's activity. DebtUtilized is
+// the credit users have drawn, capacity minus unused debt.
+type EconomyDay struct {
+	Day                time.Time
+	MoneySupply        int64
+	NumUsers           int64
+	DebtUtilized       int64
+	UnusedDebt         int64
+	ActiveBetVolume    int64
+	MarketCreationFees int64
+	ParticipationFees  int64
+	BonusesPaid        int64
+	Surplus            int64
+	EconomyActivity
+	Final      bool
+	RecordedAt time.Time
+}
+
+// EconomyHistoryRepository persists the daily economy history. Like
+// UserFinancialHistoryRepository it is display data kept apart from
+// Repository.
+type EconomyHistoryRepository interface {
+	// CountEconomyActivity counts bets placed, markets created and markets
+	// resolved in [from, to).
+	CountEconomyActivity(ctx context.Context, from, to time.Time) (EconomyActivity, error)
+	// GetEconomyDay returns the stored day starting at day, or nil.
+	GetEconomyDay(ctx context.Context, day time.Time) (*EconomyDay, error)
+	// UpsertEconomyDay stores the day, replacing an earlier sample of it.
+	UpsertEconomyDay(ctx context.Context, day EconomyDay) error
+	// ListEconomyDays returns the days starting in [from, to), oldest first.
+	ListEconomyDays(ctx context.Context, from, to time.Time) ([]EconomyDay, error)
+}
+
+// RecordEconomyDay samples the system metrics and today's activity into the
+// economy history. The previous day's activity is recounted once after it
+// ends so trades after its last sample are not lost; its money figures keep
+// that last sample.
+func (s *Service) RecordEconomyDay(ctx context.Context, now time.Time) error {
+	historyRepo, ok := s.repo.(EconomyHistoryRepository)
+	if !ok {
+		return errors.New("economy history repository not provided")
+	}
+	now = now.UTC()
+	today := HistoryIntervalDay.Truncate(now)
+
+	yesterday, err := historyRepo.GetEconomyDay(ctx, today.AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+	if yesterday != nil && !yesterday.Final {
+		activity, err := historyRepo.CountEconomyActivity(ctx, yesterday.Day, today)
+		if err != nil {
+			return err
+		}
+		yesterday.EconomyActivity = activity
+		yesterday.Final = true
+		if err := historyRepo.UpsertEconomyDay(ctx, *yesterday); err != nil {
+			return err
+		}
+	}
+
+	metrics, err := s.ComputeSystemMetrics(ctx)
+	if err != nil {
+		return err
+	}
+	activity, err := historyRepo.CountEconomyActivity(ctx, today, now)
+	if err != nil {
+		return err
+	}
+	return historyRepo.UpsertEconomyDay(ctx, economyDayFromMetrics(today, now, metrics, activity))
+}
+
+func economyDayFromMetrics(day, now time.Time, metrics *SystemMetrics, activity EconomyActivity) EconomyDay {
+	supply := metrics.MoneyCreated.UserDebtCapacityValue()
+	unused := metrics.MoneyUtilized.UnusedDebtValue()
+	return EconomyDay{
+		Day:                day,
+		MoneySupply:        supply,
+		NumUsers:           metrics.MoneyCreated.NumUsersValue(),
+		DebtUtilized:       supply - unused,
+		UnusedDebt:         unused,
+		ActiveBetVolume:    metrics.MoneyUtilized.ActiveBetVolumeValue(),
+		MarketCreationFees: metrics.MoneyUtilized.MarketCreationFeesValue(),
+		ParticipationFees:  metrics.MoneyUtilized.ParticipationFeesValue(),
+		BonusesPaid:        metrics.MoneyUtilized.BonusesPaidValue(),
+		Surplus:            metrics.Verification.SurplusValue(),
+		EconomyActivity:    activity,
+		RecordedAt:         now,
+	}
+}
+
+// GetEconomyHistory returns the recorded days from the UTC day of from
+// through the UTC day of to, oldest first. A zero to means now and a zero
+// from means the default range before to.
+func (s *Service) GetEconomyHistory(ctx context.Context, from, to, now time.Time) ([]EconomyDay, error) {
+	historyRepo, ok := s.repo.(EconomyHistoryRepository)
+	if !ok {
+		return nil, errors.New("economy history repository not provided")
+	}
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-EconomyHistoryDefaultRange)
+	}
+	from = HistoryIntervalDay.Truncate(from)
+	to = HistoryIntervalDay.Truncate(to).AddDate(0, 0, 1)
+	if !to.After(from) || to.Sub(from) > maxEconomyHistoryDays*24*time.Hour {
+		return nil, ErrInvalidHistoryRange
+	}
+	return historyRepo.ListEconomyDays(ctx, from, to)
+}
@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type economyHistoryRepo struct {
+	Repository
+	from, to time.Time
+}
+
+func (r *economyHistoryRepo) CountEconomyActivity(context.Context, time.Time, time.Time) (EconomyActivity, error) {
+	return EconomyActivity{}, nil
+}
+
+func (r *economyHistoryRepo) GetEconomyDay(context.Context, time.Time) (*EconomyDay, error) {
+	return nil, nil
+}
+
+func (r *economyHistoryRepo) UpsertEconomyDay(context.Context, EconomyDay) error {
+	return nil
+}
+
+func (r *economyHistoryRepo) ListEconomyDays(_ context.Context, from, to time.Time) ([]EconomyDay, error) {
+	r.from, r.to = from, to
+	return nil, nil
+}
+
+func TestGetEconomyHistoryCoversWholeDays(t *testing.T) {
+	repo := &economyHistoryRepo{}
+	svc := NewService(repo, Config{})
+	now := time.Date(2026, 7, 10, 15, 30, 0, 0, time.UTC)
+
+	if _, err := svc.GetEconomyHistory(context.Background(), time.Time{}, time.Time{}, now); err != nil {
+		t.Fatalf("GetEconomyHistory(default) returned error: %v", err)
+	}
+	wantTo := time.Date(2026, 7, 11, 0, 0, 0, 0, time.UTC)
+	if !repo.to.Equal(wantTo) || !repo.from.Equal(wantTo.AddDate(0, 0, -91)) {
+		t.Fatalf("default range = [%s, %s)", repo.from, repo.to)
+	}
+
+	_, err := svc.GetEconomyHistory(context.Background(), now, now.AddDate(0, 0, -2), now)
+	if !errors.Is(err, ErrInvalidHistoryRange) {
+		t.Fatalf("reversed range err = %v, want ErrInvalidHistoryRange", err)
+	}
+	_, err = svc.GetEconomyHistory(context.Background(), now.AddDate(-6, 0, 0), now, now)
+	if !errors.Is(err, ErrInvalidHistoryRange) {
+		t.Fatalf("oversized range err = %v, want ErrInvalidHistoryRange", err)
+	}
+}
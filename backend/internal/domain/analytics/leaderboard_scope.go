@@ -0,0 +1,330 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/boundary"
+	positionsmath "socialpredict/internal/domain/math/positions"
+)
+
+// LeaderboardWindow limits a leaderboard to profit made in a recent period.
+type LeaderboardWindow string
+
+const (
+	LeaderboardWindowAllTime LeaderboardWindow = "all"
+	LeaderboardWindow7Days   LeaderboardWindow = "7d"
+	LeaderboardWindow30Days  LeaderboardWindow = "30d"
+	LeaderboardWindow90Days  LeaderboardWindow = "90d"
+	LeaderboardWindowMonth   LeaderboardWindow = "month"
+)
+
+// leaderboardTagSlugMaxSize matches the market_tags slug column.
+const leaderboardTagSlugMaxSize = 64
+
+var (
+	// ErrInvalidLeaderboardScope indicates an unknown window or malformed tag.
+	ErrInvalidLeaderboardScope = errors.New("invalid leaderboard scope")
+	// ErrLeaderboardTagNotFound indicates the tag does not exist or is
+	// inactive.
+	ErrLeaderboardTagNotFound = errors.New("leaderboard tag not found")
+)
+
+// ParseLeaderboardWindow accepts the window query values; empty means all
+// time.
+func ParseLeaderboardWindow(raw string) (LeaderboardWindow, error) {
+	switch window := LeaderboardWindow(strings.ToLower(strings.TrimSpace(raw))); window {
+	case "":
+		return LeaderboardWindowAllTime, nil
+	case LeaderboardWindowAllTime, LeaderboardWindow7Days, LeaderboardWindow30Days, LeaderboardWindow90Days, LeaderboardWindowMonth:
+		return window, nil
+	default:
+		return "", ErrInvalidLeaderboardScope
+	}
+}
+
+// Start returns when the window opens relative to now. Rolling windows count
+// whole days back; the month window opens at midnight UTC on the first of
+// the current month. All time has no start.
+func (w LeaderboardWindow) Start(now time.Time) (time.Time, bool) {
+	now = now.UTC()
+	switch w {
+	case LeaderboardWindow7Days:
+		return now.AddDate(0, 0, -7), true
+	case LeaderboardWindow30Days:
+		return now.AddDate(0, 0, -30), true
+	case LeaderboardWindow90Days:
+		return now.AddDate(0, 0, -90), true
+	case LeaderboardWindowMonth:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// LeaderboardScope narrows the profit leaderboard to a window and optionally
+// to markets carrying one tag. From and Until bound a fixed period, such as a
+// season, and take precedence over Window. The zero scope is the all-time
+// global board.
+type LeaderboardScope struct {
+	Window  LeaderboardWindow
+	TagSlug string
+	From    time.Time
+	Until   time.Time
+}
+
+// NewLeaderboardScope validates and normalizes the window and tag query
+// values.
+func NewLeaderboardScope(window, tagSlug string) (LeaderboardScope, error) {
+	parsed, err := ParseLeaderboardWindow(window)
+	if err != nil {
+		return LeaderboardScope{}, err
+	}
+	tagSlug = strings.ToLower(strings.TrimSpace(tagSlug))
+	if len(tagSlug) > leaderboardTagSlugMaxSize || strings.ContainsAny(tagSlug, ": ") {
+		return LeaderboardScope{}, ErrInvalidLeaderboardScope
+	}
+	return LeaderboardScope{Window: parsed, TagSlug: tagSlug}, nil
+}
+
+// IsGlobal reports whether the scope is the unscoped all-time leaderboard.
+func (s LeaderboardScope) IsGlobal() bool {
+	return (s.Window == "" || s.Window == LeaderboardWindowAllTime) && s.TagSlug == "" && s.From.IsZero() && s.Until.IsZero()
+}
+
+// Windowed reports whether the scope covers a recent period, so its snapshot
+// ages even when no canonical data changes.
+func (s LeaderboardScope) Windowed() bool {
+	_, ok := s.start(time.Now())
+	return ok && s.Until.IsZero()
+}
+
+func (s LeaderboardScope) start(now time.Time) (time.Time, bool) {
+	if !s.From.IsZero() {
+		return s.From.UTC(), true
+	}
+	return s.Window.Start(now)
+}
+
+func (s LeaderboardScope) snapshotKey() string {
+	if s.IsGlobal() {
+		return GlobalLeaderboardSnapshotKey
+	}
+	period := string(s.Window)
+	if period == "" {
+		period = string(LeaderboardWindowAllTime)
+	}
+	if !s.From.IsZero() || !s.Until.IsZero() {
+		period = fmt.Sprintf("range=%d-%d", unixOrZero(s.From), unixOrZero(s.Until))
+	}
+	return "scoped_leaderboard:" + period + ":" + s.TagSlug
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// LeaderboardMarketRecord is a market with the instant it resolved, zero
+// while unresolved.
+type LeaderboardMarketRecord struct {
+	MarketRecord
+	ResolvedAt time.Time
+}
+
+// ScopedLeaderboardRepository optionally exposes the market data behind
+// windowed and tag leaderboards.
+type ScopedLeaderboardRepository interface {
+	// ListLeaderboardMarkets returns every market, or only those tagged
+	// tagSlug when it is set. It returns ErrLeaderboardTagNotFound for an
+	// unknown or inactive tag.
+	ListLeaderboardMarkets(ctx context.Context, tagSlug string) ([]LeaderboardMarketRecord, error)
+}
+
+// ComputeScopedLeaderboardSnapshot ranks users by profit within scope. In a
+// window, each position counts its profit at the window's end (now, unless
+// Until is set) minus its profit when the window opened, each valued at the
+// market probability and settlement state of the time, so both realized and
+// unrealized gains made in the window count and earlier gains do not. Users
+// rank if they traded in the window or their profit moved in it.
+func (s *Service) ComputeScopedLeaderboardSnapshot(ctx context.Context, scope LeaderboardScope) (*GlobalLeaderboardSnapshot, error) {
+	if scope.IsGlobal() {
+		return s.ComputeGlobalLeaderboardSnapshot(ctx)
+	}
+	repo, ok := s.repo.(ScopedLeaderboardRepository)
+	if !ok {
+		return nil, errors.New("scoped leaderboard repository not provided")
+	}
+	markets, err := repo.ListLeaderboardMarkets(ctx, scope.TagSlug)
+	if err != nil {
+		return nil, err
+	}
+	if s.positions == nil {
+		s.ensureStrategyDefaults()
+	}
+
+	start, windowed := scope.start(time.Now())
+	end, ended := scope.Until.UTC(), !scope.Until.IsZero()
+	aggregates := make(map[string]*leaderboardAggregate)
+	earliest := make(map[string]time.Time)
+	for _, market := range markets {
+		if windowed && market.IsResolved && !market.ResolvedAt.IsZero() && market.ResolvedAt.Before(start) {
+			continue
+		}
+		if ended && !market.CreatedAt.Before(end) {
+			continue
+		}
+		bets, err := s.repo.ListBetsForMarket(ctx, market.ID)
+		if err != nil {
+			return nil, err
+		}
+		var closing []positionsmath.MarketPosition
+		if ended {
+			closing, err = s.positionsAt(market, bets, end)
+		} else {
+			closing, err = s.positions.Calculate(market.Snapshot(), bets)
+		}
+		if err != nil {
+			return nil, err
+		}
+		opening := make(map[string]positionsmath.MarketPosition)
+		if windowed {
+			positions, err := s.positionsAt(market, bets, start)
+			if err != nil {
+				return nil, err
+			}
+			for _, pos := range positions {
+				opening[pos.Username] = pos
+			}
+		}
+
+		traded := make(map[string]bool)
+		for _, bet := range bets {
+			if (windowed && bet.PlacedAt.Before(start)) || (ended && !bet.PlacedAt.Before(end)) {
+				continue
+			}
+			traded[bet.Username] = true
+			if ts, ok := earliest[bet.Username]; !ok || bet.PlacedAt.Before(ts) {
+				earliest[bet.Username] = bet.PlacedAt
+			}
+		}
+		for _, pos := range closing {
+			before := opening[pos.Username]
+			profit := (pos.Value - pos.TotalSpent) - (before.Value - before.TotalSpent)
+			if !traded[pos.Username] && profit == 0 {
+				continue
+			}
+			agg := aggregates[pos.Username]
+			if agg == nil {
+				agg = &leaderboardAggregate{}
+				aggregates[pos.Username] = agg
+			}
+			agg.totalProfit += profit
+			agg.totalCurrentValue += pos.Value
+			agg.totalSpent += pos.TotalSpent - before.TotalSpent
+			if pos.IsResolved {
+				agg.resolvedMarkets++
+			} else {
+				agg.activeMarkets++
+			}
+		}
+	}
+	// Holders who did not trade in the window tie-break as if they traded
+	// when it opened.
+	for username := range aggregates {
+		if _, ok := earliest[username]; !ok {
+			earliest[username] = start
+		}
+	}
+
+	leaderboard := assembleLeaderboardEntries(aggregates, earliest)
+	return newGlobalLeaderboardSnapshot(rankLeaderboardEntries(leaderboard)), nil
+}
+
+// positionsAt values the positions held at an instant, from the bets placed
+// before it, settled only if the market had resolved by then.
+func (s *Service) positionsAt(market LeaderboardMarketRecord, bets []boundary.Bet, at time.Time) ([]positionsmath.MarketPosition, error) {
+	if !market.CreatedAt.Before(at) {
+		return nil, nil
+	}
+	var before []boundary.Bet
+	for _, bet := range bets {
+		if bet.PlacedAt.Before(at) {
+			before = append(before, bet)
+		}
+	}
+	if len(before) == 0 {
+		return nil, nil
+	}
+	snapshot := market.Snapshot()
+	if snapshot.IsResolved && market.ResolvedAt.After(at) {
+		snapshot.IsResolved = false
+		snapshot.ResolutionResult = ""
+	}
+	return s.positions.Calculate(snapshot, before)
+}
+
+// RefreshScopedLeaderboardSnapshot recomputes and stores the display-only
+// snapshot for one leaderboard scope.
+func (s *Service) RefreshScopedLeaderboardSnapshot(ctx context.Context, scope LeaderboardScope) (*GlobalLeaderboardReadModel, error) {
+	if scope.IsGlobal() {
+		return s.RefreshGlobalLeaderboardSnapshot(ctx)
+	}
+	leaderboard, err := s.ComputeScopedLeaderboardSnapshot(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(leaderboard.Result())
+	if err != nil {
+		return nil, err
+	}
+	snapshot := AnalyticsReadModelSnapshot{
+		Key:         scope.snapshotKey(),
+		Kind:        AnalyticsSnapshotKindScopedLeaderboard,
+		PayloadJSON: payload,
+		GeneratedAt: time.Now().UTC(),
+		Source:      "read_model",
+	}
+	repo, err := s.analyticsReadModelSnapshotRepo()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.UpsertAnalyticsReadModelSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return &GlobalLeaderboardReadModel{
+		Entries:   leaderboard.Result(),
+		Freshness: snapshot.Freshness(GlobalLeaderboardSnapshotTargetFreshness),
+	}, nil
+}
+
+// GetScopedLeaderboardReadModel returns a page of the stored snapshot for one
+// leaderboard scope. A missing snapshot is not an error.
+func (s *Service) GetScopedLeaderboardReadModel(ctx context.Context, scope LeaderboardScope, limit, offset int) (*GlobalLeaderboardReadModel, error) {
+	if scope.IsGlobal() {
+		return s.GetGlobalLeaderboardReadModel(ctx, limit, offset)
+	}
+	repo, err := s.analyticsReadModelSnapshotRepo()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := repo.GetAnalyticsReadModelSnapshot(ctx, scope.snapshotKey())
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	var entries []GlobalUserProfitability
+	if err := json.Unmarshal(snapshot.PayloadJSON, &entries); err != nil {
+		return nil, err
+	}
+	paged := (&GlobalLeaderboardSnapshot{Entries: entries}).ResultPage(limit, offset)
+	return &GlobalLeaderboardReadModel{
+		Entries:   paged,
+		Freshness: snapshot.Freshness(GlobalLeaderboardSnapshotTargetFreshness),
+	}, nil
+}
@@ -170,14 +170,18 @@ type AnalyticsReadModelSnapshot struct {
 const (
 	AnalyticsSnapshotKindSystemMetrics     = "system_metrics"
 	AnalyticsSnapshotKindGlobalLeaderboard = "global_leaderboard"
+	AnalyticsSnapshotKindCalibration       = "calibration"
+	AnalyticsSnapshotKindScopedLeaderboard = "scoped_leaderboard"
 
 	SystemMetricsSnapshotKey     = "system_metrics:default"
 	GlobalLeaderboardSnapshotKey = "global_leaderboard:default"
+	CalibrationSnapshotKey       = "calibration:default"
 )
 
 const (
 	SystemMetricsSnapshotTargetFreshness     = time.Hour
 	GlobalLeaderboardSnapshotTargetFreshness = 15 * time.Minute
+	CalibrationSnapshotTargetFreshness       = time.Hour
 )
 
 func (s AnalyticsReadModelSnapshot) Freshness(target time.Duration) readmodels.Freshness {
@@ -204,6 +208,11 @@ type GlobalLeaderboardReadModel struct {
 	Freshness readmodels.Freshness
 }
 
+type CalibrationReadModel struct {
+	Snapshot  CalibrationSnapshot
+	Freshness readmodels.Freshness
+}
+
 func (s FinancialSnapshot) AccountBalanceValue() int64     { return s.AccountBalance }
 func (s FinancialSnapshot) MaximumDebtAllowedValue() int64 { return s.MaximumDebtAllowed }
 func (s FinancialSnapshot) AmountBorrowedValue() int64     { return s.AmountBorrowed }
@@ -112,6 +112,58 @@ func (s *Service) GetGlobalLeaderboardReadModel(ctx context.Context, limit, offs
 	}, nil
 }
 
+// RefreshCalibrationSnapshot recomputes and stores the display-only
+// calibration snapshot behind user calibration and the skill leaderboard.
+func (s *Service) RefreshCalibrationSnapshot(ctx context.Context) (*CalibrationReadModel, error) {
+	calibration, err := s.ComputeCalibrationSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(calibration.Users)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := AnalyticsReadModelSnapshot{
+		Key:         CalibrationSnapshotKey,
+		Kind:        AnalyticsSnapshotKindCalibration,
+		PayloadJSON: payload,
+		GeneratedAt: time.Now().UTC(),
+		Source:      "read_model",
+	}
+	repo, err := s.analyticsReadModelSnapshotRepo()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.UpsertAnalyticsReadModelSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return &CalibrationReadModel{
+		Snapshot:  *calibration,
+		Freshness: snapshot.Freshness(CalibrationSnapshotTargetFreshness),
+	}, nil
+}
+
+// GetCalibrationReadModel returns the stored display-only calibration
+// snapshot. A missing snapshot is not an error.
+func (s *Service) GetCalibrationReadModel(ctx context.Context) (*CalibrationReadModel, error) {
+	repo, err := s.analyticsReadModelSnapshotRepo()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := repo.GetAnalyticsReadModelSnapshot(ctx, CalibrationSnapshotKey)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	var users []UserCalibration
+	if err := json.Unmarshal(snapshot.PayloadJSON, &users); err != nil {
+		return nil, err
+	}
+	return &CalibrationReadModel{
+		Snapshot:  CalibrationSnapshot{Users: users},
+		Freshness: snapshot.Freshness(CalibrationSnapshotTargetFreshness),
+	}, nil
+}
+
 // MarkAnalyticsReadModelsStale marks aggregate analytics snapshots stale after
 // canonical mutations. It is safe to call even before snapshots exist.
 func (s *Service) MarkAnalyticsReadModelsStale(ctx context.Context, reason string) error {
@@ -122,7 +174,13 @@ func (s *Service) MarkAnalyticsReadModelsStale(ctx context.Context, reason strin
 	if err := repo.MarkAnalyticsReadModelSnapshotStale(ctx, SystemMetricsSnapshotKey, reason); err != nil {
 		return err
 	}
-	return repo.MarkAnalyticsReadModelSnapshotStale(ctx, GlobalLeaderboardSnapshotKey, reason)
+	if err := repo.MarkAnalyticsReadModelSnapshotStale(ctx, GlobalLeaderboardSnapshotKey, reason); err != nil {
+		return err
+	}
+	if err := repo.MarkAnalyticsReadModelSnapshotStale(ctx, CalibrationSnapshotKey, reason); err != nil {
+		return err
+	}
+	return repo.MarkAnalyticsReadModelSnapshotsStaleByKind(ctx, AnalyticsSnapshotKindScopedLeaderboard, reason)
 }
 
 func (s *Service) analyticsReadModelSnapshotRepo() (AnalyticsReadModelSnapshotRepository, error) {
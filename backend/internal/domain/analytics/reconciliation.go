@@ -0,0 +1,468 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"socialpredict/internal/domain/boundary"
+	marketmath "socialpredict/internal/domain/math/market"
+	positionsmath "socialpredict/internal/domain/math/positions"
+	"socialpredict/internal/domain/math/probabilities/wpam"
+)
+
+// Reconciliation checks reported in ReconciliationDiscrepancy.Check.
+const (
+	ReconciliationCheckUserBalance    = "user_balance"
+	ReconciliationCheckMarketPayout   = "market_payout"
+	ReconciliationCheckMarketSnapshot = "market_snapshot"
+)
+
+// reconciliationProbabilityTolerance absorbs float noise when comparing a
+// stored snapshot probability with a fresh recompute.
+const reconciliationProbabilityTolerance = 1e-9
+
+// ReconciliationUser captures the balances a user's ledger is checked against.
+type ReconciliationUser struct {
+	Username              string
+	InitialAccountBalance int64
+	AccountBalance        int64
+}
+
+// ReconciliationMarket captures the market fields that move money outside
+// trading: the creation charge, its refund on rejection, resolution payouts
+// and steward work profit.
+type ReconciliationMarket struct {
+	ID               uint
+	CreatorUsername  string
+	StewardUsername  string
+	LifecycleStatus  string
+	CreatedAt        time.Time
+	IsResolved       bool
+	ResolutionResult string
+	ProposalCost     int64
+}
+
+// ReconciliationAdjustment is a recorded balance change that no bet or market
+// row implies, such as an answer-addition charge or a season balance reset.
+type ReconciliationAdjustment struct {
+	Username string
+	Amount   int64
+}
+
+// ReconciliationSaleCredit is what a sale bet was credited when its price was
+// fixed outside the bet sequence, as a season liquidation values every
+// position at one snapshot.
+type ReconciliationSaleCredit struct {
+	BetID  uint
+	Amount int64
+}
+
+// StoredMarketAccounting is the persisted market accounting snapshot as it
+// is compared with a fresh recompute.
+type StoredMarketAccounting struct {
+	MarketID           uint
+	LastProbability    float64
+	NetBetVolume       int64
+	MarketDust         int64
+	VolumeWithDust     int64
+	UserCount          int
+	BetCount           int
+	LastProcessedBetID uint
+	IsStale            bool
+}
+
+// ReconciliationRepository exposes the rows the money-conservation checks
+// replay. Like CalibrationRepository it is kept apart from Repository.
+type ReconciliationRepository interface {
+	ListReconciliationUsers(ctx context.Context) ([]ReconciliationUser, error)
+	ListReconciliationMarkets(ctx context.Context) ([]ReconciliationMarket, error)
+	// ListAnswerAdditionCharges returns one negative adjustment per approved
+	// answer addition that cost its proposer credits.
+	ListAnswerAdditionCharges(ctx context.Context) ([]ReconciliationAdjustment, error)
+	// ListSeasonBalanceResets returns the balance change of every season reset.
+	ListSeasonBalanceResets(ctx context.Context) ([]ReconciliationAdjustment, error)
+	// ListSeasonLiquidationCredits returns the credit of every sale a season
+	// rollover made.
+	ListSeasonLiquidationCredits(ctx context.Context) ([]ReconciliationSaleCredit, error)
+	ListMarketAccountingSnapshots(ctx context.Context) ([]StoredMarketAccounting, error)
+}
+
+// ReconciliationDiscrepancy is one failed invariant. Expected and Actual are
+// credits, except for a lastProbabilityPpm field where they are parts per
+// million. A user balance passes when Actual is at most Tolerance below
+// Expected, the dust sales may have withheld.
+type ReconciliationDiscrepancy struct {
+	Check     string `json:"check"`
+	Username  string `json:"username,omitempty"`
+	MarketID  uint   `json:"marketId,omitempty"`
+	Field     string `json:"field"`
+	Expected  int64  `json:"expected"`
+	Actual    int64  `json:"actual"`
+	Tolerance int64  `json:"tolerance,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// ReconciliationReport is the machine-readable result of Reconcile. Stale
+// snapshots are counted but not compared, since a refresh is already due.
+type ReconciliationReport struct {
+	GeneratedAt      time.Time                   `json:"generatedAt"`
+	Balanced         bool                        `json:"balanced"`
+	UsersChecked     int                         `json:"usersChecked"`
+	MarketsChecked   int                         `json:"marketsChecked"`
+	SnapshotsChecked int                         `json:"snapshotsChecked"`
+	SnapshotsStale   int                         `json:"snapshotsStale"`
+	Discrepancies    []ReconciliationDiscrepancy `json:"discrepancies"`
+}
+
+// Reconcile verifies the system-wide accounting invariants from canonical
+// rows: each user's balance equals their initial balance plus every flow the
+// bets, markets, answer additions and season resets imply; each resolved
+// market paid out no more than its volume plus dust; and every fresh market
+// accounting snapshot matches a recompute. Sale proceeds are replayed from
+// the position the seller held, so fees and costs use the current Config.
+func (s *Service) Reconcile(ctx context.Context, now time.Time) (*ReconciliationReport, error) {
+	repo, ok := s.repo.(ReconciliationRepository)
+	if !ok {
+		return nil, errors.New("reconciliation repository not provided")
+	}
+
+	users, err := repo.ListReconciliationUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	markets, err := repo.ListReconciliationMarkets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bets, err := s.repo.ListBetsOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := listMarketGroupFeeRecords(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	additions, err := repo.ListAnswerAdditionCharges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resets, err := repo.ListSeasonBalanceResets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	liquidations, err := repo.ListSeasonLiquidationCredits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := repo.ListMarketAccountingSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	betsByMarket := make(map[uint][]boundary.Bet)
+	for _, bet := range bets {
+		betsByMarket[bet.MarketID] = append(betsByMarket[bet.MarketID], bet)
+	}
+
+	ledger := newReconciliationLedger(s.config)
+	for _, credit := range liquidations {
+		ledger.saleCredits[credit.BetID] = credit.Amount
+	}
+	report := &ReconciliationReport{GeneratedAt: now.UTC()}
+	childIDs := marketGroupChildIDSet(groups)
+
+	for _, market := range markets {
+		marketBets := betsByMarket[market.ID]
+		if !childIDs[market.ID] {
+			ledger.chargeCreation(market.CreatorUsername, market.ProposalCost, market.LifecycleStatus)
+		}
+		ledger.replayTrades(market, marketBets)
+		if !market.IsResolved {
+			continue
+		}
+		if market.ResolutionResult == "N/A" {
+			ledger.refund(marketBets)
+			continue
+		}
+
+		paid, err := ledger.payout(market, marketBets)
+		if err != nil {
+			return nil, err
+		}
+		report.MarketsChecked++
+		if limit := marketmath.GetMarketVolumeWithDust(marketBets); paid > limit {
+			report.Discrepancies = append(report.Discrepancies, ReconciliationDiscrepancy{
+				Check:    ReconciliationCheckMarketPayout,
+				MarketID: market.ID,
+				Field:    "payout",
+				Expected: limit,
+				Actual:   paid,
+				Detail:   "resolution paid out more than the market volume plus dust",
+			})
+		}
+		if !childIDs[market.ID] {
+			steward := market.StewardUsername
+			if steward == "" {
+				steward = market.CreatorUsername
+			}
+			ledger.credit(steward, participationFeeIncome(marketBets, s.config.InitialBetFee))
+		}
+	}
+
+	marketsByID := make(map[uint]ReconciliationMarket, len(markets))
+	for _, market := range markets {
+		marketsByID[market.ID] = market
+	}
+	for _, group := range groups {
+		ledger.chargeCreation(group.CreatorUsername, group.ProposalCost, group.LifecycleStatus)
+		if group.LifecycleStatus != "resolved" || !groupPaidWorkProfit(group, marketsByID) {
+			continue
+		}
+		groupBets := make([][]boundary.Bet, 0, len(group.MemberMarketIDs))
+		for _, marketID := range group.MemberMarketIDs {
+			groupBets = append(groupBets, betsByMarket[marketID])
+		}
+		steward := group.StewardUsername
+		if steward == "" {
+			steward = group.CreatorUsername
+		}
+		ledger.credit(steward, groupParticipationFeeIncome(groupBets, s.config.InitialBetFee))
+	}
+	for _, adjustment := range append(additions, resets...) {
+		ledger.credit(adjustment.Username, adjustment.Amount)
+	}
+
+	for _, user := range users {
+		report.UsersChecked++
+		expected := user.InitialAccountBalance + ledger.flows[user.Username]
+		tolerance := ledger.dustAllowance[user.Username]
+		if user.AccountBalance > expected || user.AccountBalance < expected-tolerance {
+			report.Discrepancies = append(report.Discrepancies, ReconciliationDiscrepancy{
+				Check:     ReconciliationCheckUserBalance,
+				Username:  user.Username,
+				Field:     "accountBalance",
+				Expected:  expected,
+				Actual:    user.AccountBalance,
+				Tolerance: tolerance,
+			})
+		}
+	}
+	report.Discrepancies = append(report.Discrepancies, ledger.unreplayedSales...)
+
+	calculator := wpam.NewProbabilityCalculator(nil)
+	if s.probabilities != nil {
+		calculator = *s.probabilities
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.IsStale {
+			report.SnapshotsStale++
+			continue
+		}
+		report.SnapshotsChecked++
+		market, ok := marketsByID[snapshot.MarketID]
+		if !ok {
+			report.Discrepancies = append(report.Discrepancies, ReconciliationDiscrepancy{
+				Check:    ReconciliationCheckMarketSnapshot,
+				MarketID: snapshot.MarketID,
+				Field:    "market",
+				Detail:   "snapshot stored for a market that does not exist",
+			})
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies,
+			compareMarketAccounting(snapshot, recomputeMarketAccounting(calculator, market, betsByMarket[market.ID]))...)
+	}
+
+	report.Balanced = len(report.Discrepancies) == 0
+	if report.Discrepancies == nil {
+		report.Discrepancies = []ReconciliationDiscrepancy{}
+	}
+	return report, nil
+}
+
+// reconciliationLedger accumulates each user's derivable balance flows.
+type reconciliationLedger struct {
+	config          Config
+	flows           map[string]int64
+	dustAllowance   map[string]int64
+	saleCredits     map[uint]int64
+	unreplayedSales []ReconciliationDiscrepancy
+}
+
+func newReconciliationLedger(config Config) *reconciliationLedger {
+	return &reconciliationLedger{
+		config:        config,
+		flows:         make(map[string]int64),
+		dustAllowance: make(map[string]int64),
+		saleCredits:   make(map[uint]int64),
+	}
+}
+
+func (l *reconciliationLedger) credit(username string, amount int64) {
+	if username == "" || amount == 0 {
+		return
+	}
+	l.flows[username] += amount
+}
+
+// chargeCreation debits the creation cost and credits it back when the
+// proposal was rejected.
+func (l *reconciliationLedger) chargeCreation(creator string, proposalCost int64, lifecycleStatus string) {
+	cost := creationCostForWorkProfit(proposalCost, l.config.CreateMarketCost)
+	l.credit(creator, -cost)
+	if lifecycleStatus == "rejected" {
+		l.credit(creator, cost)
+	}
+}
+
+// replayTrades debits buys with their fees and credits each sale at the value
+// per share the seller held just before it, or at its recorded credit when a
+// season liquidation priced it. Dust may have withheld up to MaxDustPerSale
+// of each replayed sale, so it widens the seller's tolerance instead.
+func (l *reconciliationLedger) replayTrades(market ReconciliationMarket, bets []boundary.Bet) {
+	snapshot := positionsmath.MarketSnapshot{ID: int64(market.ID), CreatedAt: market.CreatedAt}
+	seen := make(map[string]bool)
+	for i, bet := range bets {
+		if !seen[bet.Username] {
+			seen[bet.Username] = true
+			l.credit(bet.Username, -l.config.InitialBetFee)
+		}
+		if bet.Amount >= 0 {
+			l.credit(bet.Username, -(bet.Amount + l.config.BuySharesFee))
+			continue
+		}
+		if credit, ok := l.saleCredits[bet.ID]; ok {
+			l.credit(bet.Username, credit)
+			continue
+		}
+
+		proceeds, err := replaySaleValue(snapshot, bets[:i], bet)
+		if err != nil {
+			l.unreplayedSales = append(l.unreplayedSales, ReconciliationDiscrepancy{
+				Check:    ReconciliationCheckUserBalance,
+				Username: bet.Username,
+				MarketID: market.ID,
+				Field:    "saleProceeds",
+				Detail:   fmt.Sprintf("sale %d could not be replayed: %v", bet.ID, err),
+			})
+			continue
+		}
+		l.credit(bet.Username, proceeds)
+		if l.config.MaxDustPerSale > 0 {
+			l.dustAllowance[bet.Username] += l.config.MaxDustPerSale
+		}
+	}
+}
+
+func replaySaleValue(snapshot positionsmath.MarketSnapshot, prior []boundary.Bet, sale boundary.Bet) (int64, error) {
+	position, err := positionsmath.CalculateUnlockedSellablePosition_WPAM_DBPM(snapshot, prior, sale.Username, sale.Outcome)
+	if err != nil {
+		return 0, err
+	}
+	shares := position.YesSharesOwned
+	if sale.Outcome == "NO" {
+		shares = position.NoSharesOwned
+	}
+	if shares <= 0 || position.Value <= 0 {
+		return 0, errors.New("seller held no sellable shares")
+	}
+	return -sale.Amount * (position.Value / shares), nil
+}
+
+// refund credits back every bet amount of an N/A market, as resolution does.
+func (l *reconciliationLedger) refund(bets []boundary.Bet) {
+	for _, bet := range bets {
+		l.credit(bet.Username, bet.Amount)
+	}
+}
+
+// payout credits the winning positions of a resolved market through the same
+// position math, including AdjustUserValuationsToMarketVolume, that
+// resolution pays with, and returns the total paid.
+func (l *reconciliationLedger) payout(market ReconciliationMarket, bets []boundary.Bet) (int64, error) {
+	positions, err := positionsmath.CalculateMarketPositions_WPAM_DBPM(positionsmath.MarketSnapshot{
+		ID:               int64(market.ID),
+		CreatedAt:        market.CreatedAt,
+		IsResolved:       true,
+		ResolutionResult: market.ResolutionResult,
+	}, bets)
+	if err != nil {
+		return 0, err
+	}
+	var paid int64
+	for _, position := range positions {
+		if position.Value <= 0 {
+			continue
+		}
+		l.credit(position.Username, position.Value)
+		paid += position.Value
+	}
+	return paid, nil
+}
+
+// groupPaidWorkProfit reports whether resolving the group paid its steward,
+// which it does unless every child resolved N/A.
+func groupPaidWorkProfit(group WorkProfitMarketGroupRecord, markets map[uint]ReconciliationMarket) bool {
+	for _, marketID := range group.MemberMarketIDs {
+		if market, ok := markets[marketID]; ok && market.IsResolved && market.ResolutionResult != "N/A" {
+			return true
+		}
+	}
+	return false
+}
+
+func recomputeMarketAccounting(calculator wpam.ProbabilityCalculator, market ReconciliationMarket, bets []boundary.Bet) StoredMarketAccounting {
+	fresh := StoredMarketAccounting{
+		MarketID:       market.ID,
+		NetBetVolume:   marketmath.GetMarketVolume(bets),
+		MarketDust:     marketmath.GetMarketDust(bets),
+		VolumeWithDust: marketmath.GetMarketVolumeWithDust(bets),
+		BetCount:       len(bets),
+	}
+	if changes := calculator.CalculateMarketProbabilitiesWPAM(market.CreatedAt, bets); len(changes) > 0 {
+		fresh.LastProbability = changes[len(changes)-1].Probability
+	}
+	participants := make(map[string]struct{})
+	for _, bet := range bets {
+		if bet.Username != "" {
+			participants[bet.Username] = struct{}{}
+		}
+		if bet.ID > fresh.LastProcessedBetID {
+			fresh.LastProcessedBetID = bet.ID
+		}
+	}
+	fresh.UserCount = len(participants)
+	return fresh
+}
+
+func compareMarketAccounting(stored, fresh StoredMarketAccounting) []ReconciliationDiscrepancy {
+	var discrepancies []ReconciliationDiscrepancy
+	mismatch := func(field string, expected, actual int64) {
+		if expected != actual {
+			discrepancies = append(discrepancies, ReconciliationDiscrepancy{
+				Check:    ReconciliationCheckMarketSnapshot,
+				MarketID: stored.MarketID,
+				Field:    field,
+				Expected: expected,
+				Actual:   actual,
+			})
+		}
+	}
+	mismatch("netBetVolume", fresh.NetBetVolume, stored.NetBetVolume)
+	mismatch("marketDust", fresh.MarketDust, stored.MarketDust)
+	mismatch("volumeWithDust", fresh.VolumeWithDust, stored.VolumeWithDust)
+	mismatch("userCount", int64(fresh.UserCount), int64(stored.UserCount))
+	mismatch("betCount", int64(fresh.BetCount), int64(stored.BetCount))
+	mismatch("lastProcessedBetId", int64(fresh.LastProcessedBetID), int64(stored.LastProcessedBetID))
+	if math.Abs(fresh.LastProbability-stored.LastProbability) > reconciliationProbabilityTolerance {
+		mismatch("lastProbabilityPpm", probabilityPpm(fresh.LastProbability), probabilityPpm(stored.LastProbability))
+	}
+	return discrepancies
+}
+
+func probabilityPpm(probability float64) int64 {
+	return int64(math.Round(probability * 1_000_000))
+}
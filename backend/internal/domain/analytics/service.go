@@ -5,6 +5,7 @@ import (
 
 	"socialpredict/internal/domain/boundary"
 	positionsmath "socialpredict/internal/domain/math/positions"
+	"socialpredict/internal/domain/math/probabilities/wpam"
 )
 
 // DebtRepository exposes only the user data needed for debt calculations.
@@ -70,6 +71,7 @@ type UserFinancialMetricSnapshotRepository interface {
 	GetUserFinancialMetricSnapshot(ctx context.Context, username string) (*UserFinancialMetricSnapshot, error)
 	UpsertUserFinancialMetricSnapshot(ctx context.Context, snapshot UserFinancialMetricSnapshot) error
 	MarkUserFinancialMetricSnapshotStale(ctx context.Context, username string, reason string) error
+	MarkUserFinancialMetricSnapshotsStale(ctx context.Context, reason string) error
 }
 
 // AnalyticsReadModelSnapshotRepository persists display-only aggregate
@@ -79,6 +81,7 @@ type AnalyticsReadModelSnapshotRepository interface {
 	GetAnalyticsReadModelSnapshot(ctx context.Context, key string) (*AnalyticsReadModelSnapshot, error)
 	UpsertAnalyticsReadModelSnapshot(ctx context.Context, snapshot AnalyticsReadModelSnapshot) error
 	MarkAnalyticsReadModelSnapshotStale(ctx context.Context, key string, reason string) error
+	MarkAnalyticsReadModelSnapshotsStaleByKind(ctx context.Context, kind string, reason string) error
 }
 
 // StatsRepository exposes aggregate account data required by stats reporting.
@@ -93,6 +96,8 @@ type Config struct {
 	MaximumDebtAllowed int64
 	CreateMarketCost   int64
 	InitialBetFee      int64
+	BuySharesFee       int64
+	MaxDustPerSale     int64
 }
 
 // DebtCalculator calculates debt-related metrics.
@@ -143,6 +148,7 @@ type Service struct {
 	feeCalculator    FeeCalculator
 	metricsAssembler MetricsAssembler
 	positions        MarketPositionCalculator
+	probabilities    *wpam.ProbabilityCalculator
 }
 
 // ServiceOption allows customizing analytics strategies.
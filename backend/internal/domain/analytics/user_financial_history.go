@@ -0,0 +1,213 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryInterval is the spacing of an equity history series.
+type HistoryInterval string
+
+const (
+	HistoryIntervalHour HistoryInterval = "hour"
+	HistoryIntervalDay  HistoryInterval = "day"
+	HistoryIntervalWeek HistoryInterval = "week"
+)
+
+const (
+	// UserFinancialHistoryHourlyRetention is how long hourly samples are
+	// kept before compaction leaves the last sample of each UTC day.
+	UserFinancialHistoryHourlyRetention = 30 * 24 * time.Hour
+	// UserFinancialHistoryDefaultRange is the span returned when no start is
+	// given.
+	UserFinancialHistoryDefaultRange = 30 * 24 * time.Hour
+	// maxUserFinancialHistoryBuckets caps how many intervals one request may
+	// span.
+	maxUserFinancialHistoryBuckets = 2000
+	// userFinancialHistoryBatchSize caps how many samples are written at once.
+	userFinancialHistoryBatchSize = 500
+)
+
+// ErrInvalidHistoryRange indicates an unknown interval, a range that ends
+// before it starts, or a range spanning too many intervals.
+var ErrInvalidHistoryRange = errors.New("invalid financial history range")
+
+// ParseHistoryInterval accepts the interval query values; empty means day.
+func ParseHistoryInterval(raw string) (HistoryInterval, error) {
+	switch interval := HistoryInterval(strings.ToLower(strings.TrimSpace(raw))); interval {
+	case "":
+		return HistoryIntervalDay, nil
+	case HistoryIntervalHour, HistoryIntervalDay, HistoryIntervalWeek:
+		return interval, nil
+	default:
+		return "", ErrInvalidHistoryRange
+	}
+}
+
+// Truncate returns the start of the interval containing t, in UTC. Weeks
+// start on Monday.
+func (i HistoryInterval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case HistoryIntervalHour:
+		return t.Truncate(time.Hour)
+	case HistoryIntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func (i HistoryInterval) duration() time.Duration {
+	switch i {
+	case HistoryIntervalHour:
+		return time.Hour
+	case HistoryIntervalWeek:
+		return 7 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// UserFinancialHistoryPoint is one sample of a user's equity history.
+// UnrealizedProfits is the profit on positions in unresolved markets at
+// their current value.
+type UserFinancialHistoryPoint struct {
+	Username          string
+	At                time.Time
+	AccountBalance    int64
+	Equity            int64
+	AmountInPlay      int64
+	RealizedProfits   int64
+	UnrealizedProfits int64
+}
+
+// UserFinancialHistoryRepository persists the display-only equity history.
+// Like UserFinancialMetricSnapshotRepository it is kept apart from
+// Repository so transaction paths cannot depend on it.
+type UserFinancialHistoryRepository interface {
+	// ListUserAccounts returns the named users' balances, or every user's
+	// when usernames is empty.
+	ListUserAccounts(ctx context.Context, usernames []string) ([]UserAccount, error)
+	// UpsertUserFinancialHistory stores each point in its hour, replacing an
+	// earlier sample from the same hour.
+	UpsertUserFinancialHistory(ctx context.Context, points []UserFinancialHistoryPoint) error
+	// ListUserFinancialHistory returns the user's samples in [from, to),
+	// oldest first.
+	ListUserFinancialHistory(ctx context.Context, username string, from, to time.Time) ([]UserFinancialHistoryPoint, error)
+	// CompactUserFinancialHistory keeps only the last sample of each UTC day
+	// for hourly samples before cutoff and returns how many were removed.
+	CompactUserFinancialHistory(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// RecordUserFinancialHistory samples the named users' financials, or every
+// user's when usernames is empty, into the equity history at at. Users whose
+// financials fail to compute are skipped and reported together after the
+// rest are stored.
+func (s *Service) RecordUserFinancialHistory(ctx context.Context, usernames []string, at time.Time) (int, error) {
+	historyRepo, ok := s.repo.(UserFinancialHistoryRepository)
+	if !ok {
+		return 0, errors.New("user financial history repository not provided")
+	}
+	accounts, err := historyRepo.ListUserAccounts(ctx, usernames)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	recorded := 0
+	points := make([]UserFinancialHistoryPoint, 0, userFinancialHistoryBatchSize)
+	flush := func() {
+		if len(points) == 0 {
+			return
+		}
+		if err := historyRepo.UpsertUserFinancialHistory(ctx, points); err != nil {
+			errs = append(errs, err)
+		} else {
+			recorded += len(points)
+		}
+		points = points[:0]
+	}
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		financial, err := s.ComputeUserFinancials(ctx, FinancialSnapshotRequest{
+			Username:       account.Username,
+			AccountBalance: account.AccountBalance,
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		points = append(points, UserFinancialHistoryPoint{
+			Username:          account.Username,
+			At:                at.UTC(),
+			AccountBalance:    financial.AccountBalance,
+			Equity:            financial.Equity,
+			AmountInPlay:      financial.AmountInPlay,
+			RealizedProfits:   financial.RealizedProfits,
+			UnrealizedProfits: financial.PotentialProfits,
+		})
+		if len(points) == userFinancialHistoryBatchSize {
+			flush()
+		}
+	}
+	flush()
+	return recorded, errors.Join(errs...)
+}
+
+// CompactUserFinancialHistory thins hourly samples older than the hourly
+// retention to one per UTC day.
+func (s *Service) CompactUserFinancialHistory(ctx context.Context, now time.Time) (int64, error) {
+	historyRepo, ok := s.repo.(UserFinancialHistoryRepository)
+	if !ok {
+		return 0, errors.New("user financial history repository not provided")
+	}
+	cutoff := HistoryIntervalDay.Truncate(now.Add(-UserFinancialHistoryHourlyRetention))
+	return historyRepo.CompactUserFinancialHistory(ctx, cutoff)
+}
+
+// GetUserFinancialHistory returns the user's equity history in [from, to)
+// with one point per interval: the last sample taken in it. Intervals
+// without a sample are omitted. A zero to means now and a zero from means
+// the default range before to.
+func (s *Service) GetUserFinancialHistory(ctx context.Context, username string, from, to time.Time, interval HistoryInterval, now time.Time) ([]UserFinancialHistoryPoint, error) {
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	historyRepo, ok := s.repo.(UserFinancialHistoryRepository)
+	if !ok {
+		return nil, errors.New("user financial history repository not provided")
+	}
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-UserFinancialHistoryDefaultRange)
+	}
+	from, to = from.UTC(), to.UTC()
+	if !to.After(from) || to.Sub(from)/interval.duration() > maxUserFinancialHistoryBuckets {
+		return nil, ErrInvalidHistoryRange
+	}
+
+	samples, err := historyRepo.ListUserFinancialHistory(ctx, username, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].At.Before(samples[j].At) })
+	points := make([]UserFinancialHistoryPoint, 0, len(samples))
+	for _, sample := range samples {
+		if n := len(points); n > 0 && interval.Truncate(points[n-1].At).Equal(interval.Truncate(sample.At)) {
+			points[n-1] = sample
+			continue
+		}
+		points = append(points, sample)
+	}
+	return points, nil
+}
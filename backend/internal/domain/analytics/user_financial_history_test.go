@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type historyRepo struct {
+	Repository
+	samples []UserFinancialHistoryPoint
+}
+
+func (r historyRepo) ListUserAccounts(context.Context, []string) ([]UserAccount, error) {
+	return nil, nil
+}
+
+func (r historyRepo) UpsertUserFinancialHistory(context.Context, []UserFinancialHistoryPoint) error {
+	return nil
+}
+
+func (r historyRepo) ListUserFinancialHistory(_ context.Context, username string, from, to time.Time) ([]UserFinancialHistoryPoint, error) {
+	var points []UserFinancialHistoryPoint
+	for _, sample := range r.samples {
+		if sample.Username == username && !sample.At.Before(from) && sample.At.Before(to) {
+			points = append(points, sample)
+		}
+	}
+	return points, nil
+}
+
+func (r historyRepo) CompactUserFinancialHistory(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestGetUserFinancialHistoryKeepsLastSamplePerInterval(t *testing.T) {
+	// 2026-07-06 is a Monday.
+	monday := time.Date(2026, 7, 6, 0, 0, 0, 0, time.UTC)
+	repo := historyRepo{}
+	for _, offset := range []time.Duration{1, 2, 30, 31, 24 * 8} {
+		repo.samples = append(repo.samples, UserFinancialHistoryPoint{Username: "alice", At: monday.Add(offset * time.Hour), Equity: int64(offset)})
+	}
+	repo.samples = append(repo.samples, UserFinancialHistoryPoint{Username: "bob", At: monday.Add(time.Hour), Equity: 99})
+	svc := NewService(repo, Config{})
+	ctx := context.Background()
+	now := monday.AddDate(0, 0, 10)
+
+	equities := func(interval HistoryInterval) []int64 {
+		t.Helper()
+		points, err := svc.GetUserFinancialHistory(ctx, "alice", monday, now, interval, now)
+		if err != nil {
+			t.Fatalf("GetUserFinancialHistory(%s) returned error: %v", interval, err)
+		}
+		out := make([]int64, 0, len(points))
+		for _, point := range points {
+			out = append(out, point.Equity)
+		}
+		return out
+	}
+	for interval, want := range map[HistoryInterval][]int64{
+		HistoryIntervalHour: {1, 2, 30, 31, 192},
+		HistoryIntervalDay:  {2, 31, 192},
+		HistoryIntervalWeek: {31, 192},
+	} {
+		if got := equities(interval); len(got) != len(want) || !equalInt64s(got, want) {
+			t.Fatalf("%s history = %v, want %v", interval, got, want)
+		}
+	}
+
+	// Zero bounds cover the default range ending now.
+	points, err := svc.GetUserFinancialHistory(ctx, "alice", time.Time{}, time.Time{}, HistoryIntervalDay, now)
+	if err != nil || len(points) != 3 {
+		t.Fatalf("default range = %+v, %v", points, err)
+	}
+}
+
+func TestGetUserFinancialHistoryRejectsBadRanges(t *testing.T) {
+	svc := NewService(historyRepo{}, Config{})
+	now := time.Date(2026, 7, 8, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		interval HistoryInterval
+	}{
+		{name: "reversed", from: now, to: now.Add(-time.Hour), interval: HistoryIntervalDay},
+		{name: "too many hours", from: now.AddDate(-1, 0, 0), to: now, interval: HistoryIntervalHour},
+	}
+	for _, tt := range tests {
+		if _, err := svc.GetUserFinancialHistory(context.Background(), "alice", tt.from, tt.to, tt.interval, now); !errors.Is(err, ErrInvalidHistoryRange) {
+			t.Fatalf("%s: expected ErrInvalidHistoryRange, got %v", tt.name, err)
+		}
+	}
+	if _, err := ParseHistoryInterval("month"); !errors.Is(err, ErrInvalidHistoryRange) {
+		t.Fatalf("expected an unknown interval to be rejected, got %v", err)
+	}
+	if interval, err := ParseHistoryInterval(""); err != nil || interval != HistoryIntervalDay {
+		t.Fatalf("ParseHistoryInterval(\"\") = %q, %v", interval, err)
+	}
+}
+
+func equalInt64s(a, b []int64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
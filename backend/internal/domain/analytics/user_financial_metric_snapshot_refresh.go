@@ -85,3 +85,13 @@ func (s *Service) MarkUserFinancialMetricSnapshotStale(ctx context.Context, user
 	}
 	return snapshotRepo.MarkUserFinancialMetricSnapshotStale(ctx, username, reason)
 }
+
+// MarkUserFinancialMetricSnapshotsStale marks every user's display snapshot
+// stale after a mutation that touches all balances, such as a season reset.
+func (s *Service) MarkUserFinancialMetricSnapshotsStale(ctx context.Context, reason string) error {
+	snapshotRepo, ok := s.repo.(UserFinancialMetricSnapshotRepository)
+	if !ok {
+		return errors.New("user financial metric snapshot repository not provided")
+	}
+	return snapshotRepo.MarkUserFinancialMetricSnapshotsStale(ctx, reason)
+}
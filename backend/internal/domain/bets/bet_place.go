@@ -3,12 +3,13 @@ package bets
 import (
 	"context"
 
+	devents "socialpredict/internal/domain/events"
 	dusers "socialpredict/internal/domain/users"
 )
 
 // Place creates a buy bet after validating market status and user balance.
-// Placement mutates balances and bet history synchronously inside one transaction;
-// it is not a background execution candidate.
+// Placement mutates balances and bet history synchronously inside one transaction,
+// together with the BetPlaced outbox event; it is not a background execution candidate.
 func (s *Service) Place(ctx context.Context, req PlaceRequest) (*PlacedBet, error) {
 	outcome, err := s.placeValidator.Validate(ctx, req)
 	if err != nil {
@@ -21,6 +22,11 @@ func (s *Service) Place(ctx context.Context, req PlaceRequest) (*PlacedBet, erro
 	if _, err := s.marketGate.Open(ctx, int64(req.MarketID)); err != nil {
 		return nil, err
 	}
+	if s.tradeGuard != nil {
+		if err := s.tradeGuard.CanBuy(ctx, req.Username, int64(req.MarketID)); err != nil {
+			return nil, err
+		}
+	}
 
 	if s.placeUnit == nil {
 		return nil, ErrPlaceTransactionUnavailable
@@ -50,11 +56,12 @@ func (s *Service) placeInTransaction(ctx context.Context, req PlaceRequest, outc
 			return err
 		}
 		placed = new(PlacedBet).FromModel(bet)
-		return nil
+		return devents.Record(txCtx, repo, betPlacedEvent(placed))
 	})
 	if err != nil {
 		return nil, err
 	}
+	s.notifyEvents()
 	return placed, nil
 }
 
@@ -66,6 +73,9 @@ func (s *Service) loadUserAndBetStatus(ctx context.Context, repo Repository, use
 	if user == nil {
 		return nil, false, dusers.ErrUserNotFound
 	}
+	if err := user.EnsureCanAct(s.clock.Now()); err != nil {
+		return nil, false, err
+	}
 
 	hasBet, err := repo.UserHasBet(ctx, req.MarketID, req.Username)
 	if err != nil {
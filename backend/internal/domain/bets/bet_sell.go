@@ -7,6 +7,7 @@ import (
 	"math"
 	"sort"
 
+	devents "socialpredict/internal/domain/events"
 	dmarkets "socialpredict/internal/domain/markets"
 )
 
@@ -73,6 +74,11 @@ func (s *Service) QuoteSell(ctx context.Context, req SellRequest) (*SellQuoteRes
 func (s *Service) sellInTransaction(ctx context.Context, req SellRequest, outcome string) (*SellResult, error) {
 	var result *SellResult
 	err := s.sellUnit.SellBetTransaction(ctx, func(txCtx context.Context, repo Repository, markets MarketService, users UserService) error {
+		if checker, ok := users.(AccountStandingChecker); ok {
+			if err := checker.EnsureAccountCanAct(txCtx, req.Username); err != nil {
+				return err
+			}
+		}
 		if _, err := (marketGate{markets: markets, clock: s.clock}).Open(txCtx, int64(req.MarketID)); err != nil {
 			return err
 		}
@@ -103,11 +109,12 @@ func (s *Service) sellInTransaction(ctx context.Context, req SellRequest, outcom
 		}
 
 		result = new(SellResult).Build(req, outcome, sale, now)
-		return nil
+		return devents.Record(txCtx, repo, sharesSoldEvent(result))
 	})
 	if err != nil {
 		return nil, err
 	}
+	s.notifyEvents()
 	return result, nil
 }
 
@@ -38,6 +38,9 @@ var (
 	ErrInvalidAmount BetError = newDomainError("bet amount must be greater than zero")
 	// ErrMarketClosed is returned when a bet is attempted on a closed or resolved market.
 	ErrMarketClosed BetError = newDomainError("market is closed or resolved")
+	// ErrTradeRestricted is returned when the market only accepts bets from
+	// members of its team.
+	ErrTradeRestricted BetError = newDomainError("market is restricted to members of its team")
 	// ErrInsufficientBalance indicates the user would exceed the maximum allowed debt.
 	ErrInsufficientBalance BetError = newDomainError("insufficient balance for requested bet")
 	// ErrPlaceTransactionUnavailable indicates the buy flow has no explicit transaction boundary.
@@ -0,0 +1,33 @@
+package bets
+
+import (
+	devents "socialpredict/internal/domain/events"
+)
+
+func betPlacedEvent(bet *PlacedBet) devents.Event {
+	return devents.Event{
+		Type:     devents.BetPlaced,
+		MarketID: int64(bet.MarketID),
+		Username: bet.Username,
+		Data: map[string]any{
+			"amount":  bet.Amount,
+			"outcome": bet.Outcome,
+		},
+		OccurredAt: bet.PlacedAt,
+	}
+}
+
+func sharesSoldEvent(result *SellResult) devents.Event {
+	return devents.Event{
+		Type:     devents.SharesSold,
+		MarketID: int64(result.MarketID),
+		Username: result.Username,
+		Data: map[string]any{
+			"outcome":     result.Outcome,
+			"sharesSold":  result.SharesSold,
+			"saleValue":   result.SaleValue,
+			"netProceeds": result.NetProceeds,
+		},
+		OccurredAt: result.TransactionAt,
+	}
+}
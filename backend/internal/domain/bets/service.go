@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"socialpredict/internal/domain/boundary"
+	devents "socialpredict/internal/domain/events"
 	dmarkets "socialpredict/internal/domain/markets"
 	dusers "socialpredict/internal/domain/users"
 )
@@ -69,6 +70,12 @@ type MarketGate interface {
 	Open(ctx context.Context, marketID int64) (*dmarkets.Market, error)
 }
 
+// TradeGuard optionally restricts who may buy into a market, such as
+// team-only markets. It returns ErrTradeRestricted to refuse a buyer.
+type TradeGuard interface {
+	CanBuy(ctx context.Context, username string, marketID int64) error
+}
+
 // UserService exposes the subset of user operations required by bets.
 type UserReader interface {
 	GetUser(ctx context.Context, username string) (*dusers.User, error)
@@ -85,6 +92,13 @@ type UserService interface {
 	TransactionRecorder
 }
 
+// AccountStandingChecker is implemented by user services that enforce admin
+// suspensions and bans. Sells consult it when the transaction's user service
+// provides it.
+type AccountStandingChecker interface {
+	EnsureAccountCanAct(ctx context.Context, username string) error
+}
+
 // PlaceValidator allows validation rules to be extended without changing the service.
 type PlaceValidator interface {
 	Validate(ctx context.Context, req PlaceRequest) (string, error)
@@ -158,6 +172,8 @@ type Service struct {
 	saleCalculator SaleCalculator
 	placeUnit      PlaceUnitOfWork
 	sellUnit       SellUnitOfWork
+	tradeGuard     TradeGuard
+	eventNotifier  devents.Notifier
 }
 
 var (
@@ -366,6 +382,31 @@ func WithClock(clock Clock) ServiceOption {
 	}
 }
 
+// WithEventNotifier announces committed trade events to n, typically the
+// event dispatcher.
+func WithEventNotifier(n devents.Notifier) ServiceOption {
+	return func(s *Service) {
+		if s != nil {
+			s.eventNotifier = n
+		}
+	}
+}
+
+// notifyEvents wakes the event dispatcher after a trade has committed.
+func (s *Service) notifyEvents() {
+	if s != nil && s.eventNotifier != nil {
+		s.eventNotifier.Notify()
+	}
+}
+
+// SetTradeGuard restricts buying to the buyers guard allows. Sales are never
+// restricted, so holders can always exit a position.
+func (s *Service) SetTradeGuard(guard TradeGuard) {
+	if s != nil {
+		s.tradeGuard = guard
+	}
+}
+
 // NewService constructs a bets service.
 func NewService(repo Repository, markets MarketService, users UserService, config Config, clock Clock, opts ...ServiceOption) *Service {
 	s := &Service{
@@ -8,6 +8,7 @@ import (
 
 	bets "socialpredict/internal/domain/bets"
 	"socialpredict/internal/domain/boundary"
+	devents "socialpredict/internal/domain/events"
 	dmarkets "socialpredict/internal/domain/markets"
 	positionsmath "socialpredict/internal/domain/math/positions"
 	dusers "socialpredict/internal/domain/users"
@@ -26,6 +27,7 @@ type fakeRepo struct {
 	created *boundary.Bet
 	writer  fakeBetWriter
 	history fakeBetHistoryReader
+	events  []devents.Event
 }
 
 type fakePlaceUnit struct {
@@ -99,6 +101,11 @@ type fakeBetHistoryReader struct {
 	hasBetFunc func(ctx context.Context, marketID uint, username string) (bool, error)
 }
 
+func (f *fakeRepo) RecordEvents(ctx context.Context, events ...devents.Event) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
 func (f *fakeRepo) UserHasBet(ctx context.Context, marketID uint, username string) (bool, error) {
 	return f.history.UserHasBet(ctx, marketID, username)
 }
@@ -336,11 +343,21 @@ func (c fixedClock) Now() time.Time {
 }
 
 type serviceFixture struct {
-	config  bets.Config
-	repo    *fakeRepo
-	markets *fakeMarkets
-	users   *fakeUsers
-	clock   fixedClock
+	config   bets.Config
+	repo     *fakeRepo
+	markets  *fakeMarkets
+	users    *fakeUsers
+	clock    fixedClock
+	notifier *countingNotifier
+}
+
+// countingNotifier counts post-commit event notifications.
+type countingNotifier struct {
+	calls int
+}
+
+func (n *countingNotifier) Notify() {
+	n.calls++
 }
 
 type serviceFixtureOption func(*serviceFixture)
@@ -447,11 +464,12 @@ func defaultBetsConfig() bets.Config {
 
 func newServiceFixture(now time.Time, opts ...serviceFixtureOption) (*serviceFixture, *bets.Service) {
 	fixture := &serviceFixture{
-		config:  defaultBetsConfig(),
-		repo:    newFakeRepo(),
-		markets: newFakeMarkets(),
-		users:   newFakeUsers(),
-		clock:   newFixedClock(now),
+		config:   defaultBetsConfig(),
+		repo:     newFakeRepo(),
+		markets:  newFakeMarkets(),
+		users:    newFakeUsers(),
+		clock:    newFixedClock(now),
+		notifier: &countingNotifier{},
 	}
 	for _, opt := range opts {
 		opt(fixture)
@@ -466,6 +484,7 @@ func newServiceFixture(now time.Time, opts ...serviceFixtureOption) (*serviceFix
 		fixture.clock,
 		bets.WithPlaceUnitOfWork(placeUnit),
 		bets.WithSellUnitOfWork(sellUnit),
+		bets.WithEventNotifier(fixture.notifier),
 	)
 	return fixture, svc
 }
@@ -523,6 +542,13 @@ func TestServicePlace_Succeeds(t *testing.T) {
 		t.Fatalf("unexpected transaction amount: %d", fixture.users.calls[0].amount)
 	}
 
+	if len(fixture.repo.events) != 1 || fixture.repo.events[0].Type != devents.BetPlaced {
+		t.Fatalf("recorded events = %+v, want one BetPlaced", fixture.repo.events)
+	}
+	if fixture.notifier.calls != 1 {
+		t.Fatalf("notifier calls = %d, want 1 after commit", fixture.notifier.calls)
+	}
+
 	fallbackService := bets.NewService(fixture.repo, fixture.markets, fixture.users, bets.Config{}, nil, bets.WithClock(nil))
 	if fallbackService == nil {
 		t.Fatalf("expected fallback service")
@@ -531,7 +557,7 @@ func TestServicePlace_Succeeds(t *testing.T) {
 
 func TestServicePlace_InsufficientBalance(t *testing.T) {
 	now := serviceTestTime()
-	_, svc := newServiceFixture(
+	fixture, svc := newServiceFixture(
 		now,
 		withFixtureMarket(&dmarkets.Market{ID: 1, Status: "active", ResolutionDateTime: now.Add(24 * time.Hour)}),
 		withFixtureUser(&dusers.User{Username: "alice", AccountBalance: 0}),
@@ -541,6 +567,9 @@ func TestServicePlace_InsufficientBalance(t *testing.T) {
 	if !errors.Is(err, bets.ErrInsufficientBalance) {
 		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
 	}
+	if fixture.notifier.calls != 0 {
+		t.Fatalf("notifier calls = %d, want none after a rolled back trade", fixture.notifier.calls)
+	}
 
 	if _, err := (&fakeRepo{}).UserHasBet(context.Background(), 1, "alice"); !errors.Is(err, errUnexpectedServiceCall) {
 		t.Fatalf("expected zero-value repo to fail predictably, got %v", err)
@@ -583,6 +612,33 @@ func TestServicePlace_MarketClosed(t *testing.T) {
 	}
 }
 
+type teamGuard map[string]bool
+
+func (g teamGuard) CanBuy(_ context.Context, username string, _ int64) error {
+	if !g[username] {
+		return bets.ErrTradeRestricted
+	}
+	return nil
+}
+
+func TestServicePlace_TradeGuardRestrictsBuyers(t *testing.T) {
+	now := serviceTestTime()
+	fixture, svc := newServiceFixture(
+		now,
+		withFixtureMarket(&dmarkets.Market{ID: 1, Status: "active", ResolutionDateTime: now.Add(24 * time.Hour)}),
+		withFixtureUser(&dusers.User{Username: "alice", AccountBalance: 500}),
+	)
+	svc.SetTradeGuard(teamGuard{"bob": true})
+
+	_, err := svc.Place(context.Background(), bets.PlaceRequest{Username: "alice", MarketID: 1, Amount: 10, Outcome: "YES"})
+	if !errors.Is(err, bets.ErrTradeRestricted) {
+		t.Fatalf("expected ErrTradeRestricted, got %v", err)
+	}
+	if fixture.repo.created != nil {
+		t.Fatalf("a refused buyer must not create a bet")
+	}
+}
+
 func TestServiceSell_Succeeds(t *testing.T) {
 	now := serviceTestTime()
 	fixture, svc := newServiceFixture(
@@ -38,6 +38,9 @@ type AuthenticatedUser struct {
 	ModeratorStatus    string
 	PasswordHash       string
 	MustChangePassword bool
+	// AccountStatus and AccountStatusExpiresAt carry any admin suspension or ban.
+	AccountStatus          string
+	AccountStatusExpiresAt *time.Time
 }
 
 // CheckPasswordHash validates the supplied password against the stored hash.
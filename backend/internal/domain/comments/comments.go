@@ -0,0 +1,138 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+var (
+	// ErrInvalidInput indicates a missing target, an empty or oversized body, or a malformed request.
+	ErrInvalidInput = errors.New("invalid comment request")
+	// ErrCommentNotFound indicates that the comment does not exist on the requested thread.
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrDiscussionClosed indicates that the market or group is not published, so it takes no comments.
+	ErrDiscussionClosed = errors.New("discussion is not open")
+	// ErrEditWindowClosed indicates that the author's edit window has passed.
+	ErrEditWindowClosed = errors.New("comment edit window has closed")
+	// ErrCommentRemoved indicates an edit of a comment that was deleted or hidden.
+	ErrCommentRemoved = errors.New("comment was removed")
+)
+
+// Target names the thread a comment belongs to: a market or a market group.
+// Exactly one of the two IDs is set.
+type Target struct {
+	MarketID      int64
+	MarketGroupID int64
+}
+
+// MarketTarget returns the thread of a standalone or grouped market.
+func MarketTarget(marketID int64) Target {
+	return Target{MarketID: marketID}
+}
+
+// GroupTarget returns the thread of a market group.
+func GroupTarget(groupID int64) Target {
+	return Target{MarketGroupID: groupID}
+}
+
+func (t Target) valid() bool {
+	return (t.MarketID > 0) != (t.MarketGroupID > 0)
+}
+
+// Holding is the commenter's current position in one market of the thread.
+// AnswerLabel names the answer when the thread belongs to a market group.
+type Holding struct {
+	MarketID    int64
+	AnswerLabel string
+	YesShares   int64
+	NoShares    int64
+}
+
+// Comment is one post in a thread. Replies carry the ID of the comment they
+// answer and of the top-level comment that started the thread.
+type Comment struct {
+	ID            int64
+	MarketID      int64
+	MarketGroupID int64
+	RootID        int64
+	ParentID      int64
+	Username      string
+	Body          string
+	BodyHTML      string
+	EditedAt      *time.Time
+	DeletedAt     *time.Time
+	DeletedBy     string
+	HiddenAt      *time.Time
+	HiddenBy      string
+	HiddenReason  string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	// Holdings is filled on listings with the commenter's current positions.
+	Holdings []Holding
+	// Replies is filled on top-level comments in listings, oldest first.
+	Replies []*Comment
+}
+
+// Target returns the thread the comment belongs to.
+func (c *Comment) Target() Target {
+	return Target{MarketID: c.MarketID, MarketGroupID: c.MarketGroupID}
+}
+
+// Deleted reports whether the author or a moderator removed the comment.
+func (c *Comment) Deleted() bool {
+	return c != nil && c.DeletedAt != nil
+}
+
+// Hidden reports whether a moderator hid the comment.
+func (c *Comment) Hidden() bool {
+	return c != nil && c.HiddenAt != nil
+}
+
+// Page is one page of top-level comments, oldest first, each with its replies.
+type Page struct {
+	Comments []*Comment
+	Total    int64
+	Limit    int
+	Offset   int
+}
+
+// Repository persists comments.
+type Repository interface {
+	CreateComment(ctx context.Context, comment *Comment) error
+	GetComment(ctx context.Context, id int64) (*Comment, error)
+	// ListRoots returns a page of top-level comments on the target, oldest
+	// first, and how many top-level comments the target has.
+	ListRoots(ctx context.Context, target Target, limit, offset int) ([]*Comment, int64, error)
+	// ListReplies returns every reply under the given top-level comments,
+	// oldest first.
+	ListReplies(ctx context.Context, rootIDs []int64) ([]*Comment, error)
+	// UpdateComment saves the body, edit, deletion, and moderation fields.
+	UpdateComment(ctx context.Context, comment *Comment) error
+}
+
+// Markets is the market read surface used to check threads and attach
+// commenters' positions.
+type Markets interface {
+	GetMarket(ctx context.Context, id int64) (*dmarkets.Market, error)
+	GetMarketGroup(ctx context.Context, groupID int64) (*dmarkets.MarketGroup, error)
+	GetMarketPositions(ctx context.Context, marketID int64) (dmarkets.MarketPositions, error)
+}
+
+// TradePrivacy reports which traders asked to trade anonymously.
+type TradePrivacy interface {
+	AnonymousTraders(ctx context.Context, usernames []string) (map[string]bool, error)
+}
+
+// Renderer turns a comment body into sanitized HTML.
+type Renderer interface {
+	Render(source string) (string, error)
+}
+
+// AccountStandingChecker rejects suspended and banned accounts.
+type AccountStandingChecker interface {
+	EnsureAccountCanAct(ctx context.Context, username string) error
+}
@@ -0,0 +1,419 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+)
+
+const (
+	defaultListLimit  = 20
+	maxListLimit      = 100
+	maxBodyLength     = 4000
+	maxReasonLength   = 500
+	defaultEditWindow = 15 * time.Minute
+)
+
+// Config tunes comment editing. Zero values use the defaults.
+type Config struct {
+	// EditWindow is how long after posting an author may still edit.
+	EditWindow time.Duration
+}
+
+// Service manages the discussion threads of markets and market groups.
+// Authors post, edit within the edit window, and delete their own comments;
+// holders of comments.moderate hide, unhide, and delete anyone's.
+type Service struct {
+	repo       Repository
+	markets    Markets
+	renderer   Renderer
+	standing   AccountStandingChecker
+	authorizer permissions.Authorizer
+	config     Config
+	now        func() time.Time
+	privacy    TradePrivacy
+}
+
+// NewService constructs a comments service.
+func NewService(repo Repository, markets Markets, renderer Renderer, standing AccountStandingChecker, authorizer permissions.Authorizer, config Config, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	if config.EditWindow <= 0 {
+		config.EditWindow = defaultEditWindow
+	}
+	return &Service{
+		repo:       repo,
+		markets:    markets,
+		renderer:   renderer,
+		standing:   standing,
+		authorizer: authorizer,
+		config:     config,
+		now:        now,
+	}
+}
+
+// SetTradePrivacy leaves anonymous traders' holdings off their comments.
+// Comments carry the author's name, so showing the holdings would reveal
+// the trades.
+func (s *Service) SetTradePrivacy(privacy TradePrivacy) {
+	s.privacy = privacy
+}
+
+// List returns a page of the target's top-level comments, oldest first, with
+// their replies and every commenter's current position. Deleted and hidden
+// comments keep their place in the thread but lose their body.
+func (s *Service) List(ctx context.Context, target Target, limit, offset int) (*Page, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	markets, err := s.threadMarkets(ctx, target, false)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	roots, total, err := s.repo.ListRoots(ctx, target, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page{Comments: roots, Total: total, Limit: limit, Offset: offset}
+	if len(roots) == 0 {
+		return page, nil
+	}
+
+	rootIDs := make([]int64, 0, len(roots))
+	byID := make(map[int64]*Comment, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+		byID[root.ID] = root
+	}
+	replies, err := s.repo.ListReplies(ctx, rootIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		if root := byID[reply.RootID]; root != nil {
+			root.Replies = append(root.Replies, reply)
+		}
+	}
+
+	holdings, err := s.holdings(ctx, markets)
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range roots {
+		present(root, holdings)
+		for _, reply := range root.Replies {
+			present(reply, holdings)
+		}
+	}
+	return page, nil
+}
+
+// Get returns one comment, without its body when it was deleted or hidden.
+func (s *Service) Get(ctx context.Context, id int64) (*Comment, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	comment, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return redact(comment), nil
+}
+
+// Post adds a comment to the target, or a reply when parentID is set.
+func (s *Service) Post(ctx context.Context, actor permissions.Subject, target Target, parentID int64, body string) (*Comment, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(actor.Username) == "" || parentID < 0 {
+		return nil, ErrInvalidInput
+	}
+	body, html, err := s.render(body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.threadMarkets(ctx, target, true); err != nil {
+		return nil, err
+	}
+	if err := s.ensureCanAct(ctx, actor.Username); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	comment := &Comment{
+		MarketID:      target.MarketID,
+		MarketGroupID: target.MarketGroupID,
+		Username:      actor.Username,
+		Body:          body,
+		BodyHTML:      html,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if parentID > 0 {
+		parent, err := s.repo.GetComment(ctx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Target() != target {
+			return nil, ErrCommentNotFound
+		}
+		comment.ParentID = parent.ID
+		comment.RootID = parent.RootID
+		if comment.RootID == 0 {
+			comment.RootID = parent.ID
+		}
+	}
+	if err := s.repo.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Edit replaces the body of the actor's own comment while the edit window
+// is open.
+func (s *Service) Edit(ctx context.Context, actor permissions.Subject, id int64, body string) (*Comment, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	comment, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Username != actor.Username {
+		return nil, permissions.ErrPermissionDenied
+	}
+	if comment.Deleted() || comment.Hidden() {
+		return nil, ErrCommentRemoved
+	}
+	now := s.now().UTC()
+	if now.Sub(comment.CreatedAt) > s.config.EditWindow {
+		return nil, ErrEditWindowClosed
+	}
+	body, html, err := s.render(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureCanAct(ctx, actor.Username); err != nil {
+		return nil, err
+	}
+	comment.Body = body
+	comment.BodyHTML = html
+	comment.EditedAt = &now
+	comment.UpdatedAt = now
+	if err := s.repo.UpdateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Delete soft-deletes a comment. Authors may delete their own comments at
+// any time; moderators may delete anyone's. Replies stay in place.
+func (s *Service) Delete(ctx context.Context, actor permissions.Subject, id int64) (*Comment, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	comment, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Username != actor.Username {
+		if err := permissions.Require(ctx, s.authorizer, actor, permissions.CommentsModerate); err != nil {
+			return nil, err
+		}
+	}
+	if comment.Deleted() {
+		return redact(comment), nil
+	}
+	now := s.now().UTC()
+	comment.DeletedAt = &now
+	comment.DeletedBy = actor.Username
+	comment.UpdatedAt = now
+	if err := s.repo.UpdateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return redact(comment), nil
+}
+
+// Hide takes a comment out of public view with a moderator's reason.
+func (s *Service) Hide(ctx context.Context, actor permissions.Subject, id int64, reason string) (*Comment, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		return nil, ErrInvalidInput
+	}
+	return s.moderate(ctx, actor, id, func(comment *Comment, now time.Time) {
+		comment.HiddenAt = &now
+		comment.HiddenBy = actor.Username
+		comment.HiddenReason = reason
+	})
+}
+
+// Unhide restores a hidden comment.
+func (s *Service) Unhide(ctx context.Context, actor permissions.Subject, id int64) (*Comment, error) {
+	return s.moderate(ctx, actor, id, func(comment *Comment, _ time.Time) {
+		comment.HiddenAt = nil
+		comment.HiddenBy = ""
+		comment.HiddenReason = ""
+	})
+}
+
+func (s *Service) moderate(ctx context.Context, actor permissions.Subject, id int64, apply func(*Comment, time.Time)) (*Comment, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if err := permissions.Require(ctx, s.authorizer, actor, permissions.CommentsModerate); err != nil {
+		return nil, err
+	}
+	comment, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	apply(comment, now)
+	comment.UpdatedAt = now
+	if err := s.repo.UpdateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return redact(comment), nil
+}
+
+func (s *Service) get(ctx context.Context, id int64) (*Comment, error) {
+	if id <= 0 {
+		return nil, ErrCommentNotFound
+	}
+	return s.repo.GetComment(ctx, id)
+}
+
+// threadMarkets checks that the target exists and returns the markets whose
+// positions are shown beside its comments. When posting, the market or group
+// must also be published; proposed, rejected, and cancelled ones take no
+// comments.
+func (s *Service) threadMarkets(ctx context.Context, target Target, posting bool) ([]Holding, error) {
+	if !target.valid() {
+		return nil, ErrInvalidInput
+	}
+	if target.MarketID > 0 {
+		market, err := s.markets.GetMarket(ctx, target.MarketID)
+		if err != nil {
+			return nil, err
+		}
+		if posting && !discussionOpen(market.LifecycleStatus) {
+			return nil, ErrDiscussionClosed
+		}
+		return []Holding{{MarketID: market.ID}}, nil
+	}
+	group, err := s.markets.GetMarketGroup(ctx, target.MarketGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if posting && !discussionOpen(group.LifecycleStatus) {
+		return nil, ErrDiscussionClosed
+	}
+	markets := make([]Holding, 0, len(group.Members))
+	for _, member := range group.Members {
+		markets = append(markets, Holding{MarketID: member.MarketID, AnswerLabel: member.AnswerLabel})
+	}
+	return markets, nil
+}
+
+func discussionOpen(lifecycle string) bool {
+	switch dmarkets.NormalizeLifecycleStatus(lifecycle) {
+	case dmarkets.MarketLifecyclePublished, dmarkets.MarketLifecycleClosed, dmarkets.MarketLifecycleResolved:
+		return true
+	default:
+		return false
+	}
+}
+
+// holdings maps each username to their non-zero positions in markets.
+func (s *Service) holdings(ctx context.Context, markets []Holding) (map[string][]Holding, error) {
+	holdings := map[string][]Holding{}
+	for _, market := range markets {
+		positions, err := s.markets.GetMarketPositions(ctx, market.MarketID)
+		if err != nil {
+			return nil, err
+		}
+		for _, position := range positions {
+			if position == nil || (position.YesSharesOwned <= 0 && position.NoSharesOwned <= 0) {
+				continue
+			}
+			holding := market
+			holding.YesShares = position.YesSharesOwned
+			holding.NoShares = position.NoSharesOwned
+			holdings[position.Username] = append(holdings[position.Username], holding)
+		}
+	}
+	if s.privacy == nil || len(holdings) == 0 {
+		return holdings, nil
+	}
+	usernames := make([]string, 0, len(holdings))
+	for username := range holdings {
+		usernames = append(usernames, username)
+	}
+	anonymous, err := s.privacy.AnonymousTraders(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	for username := range anonymous {
+		delete(holdings, username)
+	}
+	return holdings, nil
+}
+
+func present(comment *Comment, holdings map[string][]Holding) {
+	redact(comment)
+	comment.Holdings = holdings[comment.Username]
+}
+
+// redact blanks the body of a deleted or hidden comment.
+func redact(comment *Comment) *Comment {
+	if comment.Deleted() || comment.Hidden() {
+		comment.Body = ""
+		comment.BodyHTML = ""
+	}
+	return comment
+}
+
+func (s *Service) render(body string) (string, string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxBodyLength {
+		return "", "", ErrInvalidInput
+	}
+	html, err := s.renderer.Render(body)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.TrimSpace(html) == "" {
+		return "", "", ErrInvalidInput
+	}
+	return body, html, nil
+}
+
+func (s *Service) ensureCanAct(ctx context.Context, username string) error {
+	if s.standing == nil {
+		return nil
+	}
+	return s.standing.EnsureAccountCanAct(ctx, username)
+}
+
+func (s *Service) ready() error {
+	if s == nil || s.repo == nil || s.markets == nil || s.renderer == nil {
+		return errors.New("comments service unavailable")
+	}
+	return nil
+}
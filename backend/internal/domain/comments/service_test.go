@@ -0,0 +1,318 @@
+package comments_test
+
+import (
+	"context"
+	"errors"
+	"html"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/comments"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+)
+
+type memoryRepo struct {
+	comments []*comments.Comment
+}
+
+func (m *memoryRepo) CreateComment(_ context.Context, comment *comments.Comment) error {
+	comment.ID = int64(len(m.comments) + 1)
+	clone := *comment
+	m.comments = append(m.comments, &clone)
+	return nil
+}
+
+func (m *memoryRepo) GetComment(_ context.Context, id int64) (*comments.Comment, error) {
+	for _, comment := range m.comments {
+		if comment.ID == id {
+			clone := *comment
+			return &clone, nil
+		}
+	}
+	return nil, comments.ErrCommentNotFound
+}
+
+func (m *memoryRepo) ListRoots(_ context.Context, target comments.Target, limit, offset int) ([]*comments.Comment, int64, error) {
+	var roots []*comments.Comment
+	for _, comment := range m.comments {
+		if comment.Target() == target && comment.RootID == 0 {
+			clone := *comment
+			roots = append(roots, &clone)
+		}
+	}
+	total := int64(len(roots))
+	if offset >= len(roots) {
+		return nil, total, nil
+	}
+	roots = roots[offset:]
+	if len(roots) > limit {
+		roots = roots[:limit]
+	}
+	return roots, total, nil
+}
+
+func (m *memoryRepo) ListReplies(_ context.Context, rootIDs []int64) ([]*comments.Comment, error) {
+	var replies []*comments.Comment
+	for _, comment := range m.comments {
+		for _, id := range rootIDs {
+			if comment.RootID == id {
+				clone := *comment
+				replies = append(replies, &clone)
+			}
+		}
+	}
+	return replies, nil
+}
+
+func (m *memoryRepo) UpdateComment(_ context.Context, comment *comments.Comment) error {
+	for i, existing := range m.comments {
+		if existing.ID == comment.ID {
+			clone := *comment
+			m.comments[i] = &clone
+			return nil
+		}
+	}
+	return comments.ErrCommentNotFound
+}
+
+type fakeMarkets struct {
+	markets   map[int64]*dmarkets.Market
+	groups    map[int64]*dmarkets.MarketGroup
+	positions map[int64]dmarkets.MarketPositions
+}
+
+func (f *fakeMarkets) GetMarket(_ context.Context, id int64) (*dmarkets.Market, error) {
+	if market, ok := f.markets[id]; ok {
+		return market, nil
+	}
+	return nil, dmarkets.ErrMarketNotFound
+}
+
+func (f *fakeMarkets) GetMarketGroup(_ context.Context, id int64) (*dmarkets.MarketGroup, error) {
+	if group, ok := f.groups[id]; ok {
+		return group, nil
+	}
+	return nil, dmarkets.ErrMarketGroupNotFound
+}
+
+func (f *fakeMarkets) GetMarketPositions(_ context.Context, id int64) (dmarkets.MarketPositions, error) {
+	return f.positions[id], nil
+}
+
+type escapeRenderer struct{}
+
+func (escapeRenderer) Render(source string) (string, error) {
+	return "<p>" + html.EscapeString(source) + "</p>", nil
+}
+
+type standing map[string]error
+
+func (s standing) EnsureAccountCanAct(_ context.Context, username string) error {
+	return s[username]
+}
+
+type moderators map[string]bool
+
+func (m moderators) Can(_ context.Context, subject permissions.Subject, permission permissions.Permission) (bool, error) {
+	return permission == permissions.CommentsModerate && m[subject.Username], nil
+}
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newService(t *testing.T) (*comments.Service, *memoryRepo, *clock, standing) {
+	t.Helper()
+	markets := &fakeMarkets{
+		markets: map[int64]*dmarkets.Market{
+			1:  {ID: 1, LifecycleStatus: dmarkets.MarketLifecyclePublished},
+			2:  {ID: 2, LifecycleStatus: dmarkets.MarketLifecycleProposed},
+			11: {ID: 11, LifecycleStatus: dmarkets.MarketLifecyclePublished},
+			12: {ID: 12, LifecycleStatus: dmarkets.MarketLifecyclePublished},
+		},
+		groups: map[int64]*dmarkets.MarketGroup{
+			5: {ID: 5, LifecycleStatus: dmarkets.MarketLifecyclePublished, Members: []dmarkets.MarketGroupMember{
+				{GroupID: 5, MarketID: 11, AnswerLabel: "Red"},
+				{GroupID: 5, MarketID: 12, AnswerLabel: "Blue"},
+			}},
+		},
+		positions: map[int64]dmarkets.MarketPositions{
+			1:  {{Username: "alice", YesSharesOwned: 30}, {Username: "carol", NoSharesOwned: 0}},
+			11: {{Username: "alice", NoSharesOwned: 4}},
+			12: {{Username: "alice", YesSharesOwned: 9}},
+		},
+	}
+	repo := &memoryRepo{}
+	c := &clock{now: time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)}
+	users := standing{}
+	svc := comments.NewService(repo, markets, escapeRenderer{}, users, moderators{"mod": true}, comments.Config{EditWindow: 10 * time.Minute}, c.Now)
+	return svc, repo, c, users
+}
+
+var (
+	alice = permissions.Subject{Username: "alice", Role: "REGULAR"}
+	bob   = permissions.Subject{Username: "bob", Role: "REGULAR"}
+	mod   = permissions.Subject{Username: "mod", Role: "MODERATOR"}
+)
+
+func TestPostAndListThreadsWithHoldings(t *testing.T) {
+	svc, _, c, _ := newService(t)
+	ctx := context.Background()
+	market := comments.MarketTarget(1)
+
+	root, err := svc.Post(ctx, alice, market, 0, "  Polls moved <b>a lot</b>  ")
+	if err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if root.Body != "Polls moved <b>a lot</b>" || root.BodyHTML != "<p>Polls moved &lt;b&gt;a lot&lt;/b&gt;</p>" {
+		t.Fatalf("unexpected stored body %q / %q", root.Body, root.BodyHTML)
+	}
+	c.now = c.now.Add(time.Minute)
+	reply, err := svc.Post(ctx, bob, market, root.ID, "Disagree")
+	if err != nil {
+		t.Fatalf("reply returned error: %v", err)
+	}
+	nested, err := svc.Post(ctx, alice, market, reply.ID, "Why?")
+	if err != nil || nested.RootID != root.ID || nested.ParentID != reply.ID {
+		t.Fatalf("nested reply = %+v, %v", nested, err)
+	}
+	if _, err := svc.Post(ctx, bob, market, 0, "Second thread"); err != nil {
+		t.Fatalf("second root returned error: %v", err)
+	}
+
+	page, err := svc.List(ctx, market, 1, 0)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if page.Total != 2 || len(page.Comments) != 1 || len(page.Comments[0].Replies) != 2 {
+		t.Fatalf("unexpected page %+v", page)
+	}
+	if holdings := page.Comments[0].Holdings; len(holdings) != 1 || holdings[0].YesShares != 30 {
+		t.Fatalf("expected alice's YES holding, got %+v", holdings)
+	}
+	if holdings := page.Comments[0].Replies[0].Holdings; len(holdings) != 0 {
+		t.Fatalf("bob holds nothing, got %+v", holdings)
+	}
+
+	if _, err := svc.Post(ctx, bob, comments.GroupTarget(5), root.ID, "wrong thread"); !errors.Is(err, comments.ErrCommentNotFound) {
+		t.Fatalf("expected parent on another thread to be rejected, got %v", err)
+	}
+}
+
+func TestGroupThreadShowsPerAnswerHoldings(t *testing.T) {
+	svc, _, _, _ := newService(t)
+	ctx := context.Background()
+	if _, err := svc.Post(ctx, alice, comments.GroupTarget(5), 0, "Red is underpriced"); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	page, err := svc.List(ctx, comments.GroupTarget(5), 0, 0)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	holdings := page.Comments[0].Holdings
+	if len(holdings) != 2 || holdings[0].AnswerLabel != "Red" || holdings[0].NoShares != 4 || holdings[1].AnswerLabel != "Blue" || holdings[1].YesShares != 9 {
+		t.Fatalf("unexpected holdings %+v", holdings)
+	}
+}
+
+type anonymousTraders map[string]bool
+
+func (a anonymousTraders) AnonymousTraders(context.Context, []string) (map[string]bool, error) {
+	return a, nil
+}
+
+func TestAnonymousTradersHoldingsAreOmitted(t *testing.T) {
+	svc, _, _, _ := newService(t)
+	svc.SetTradePrivacy(anonymousTraders{"alice": true})
+	ctx := context.Background()
+	if _, err := svc.Post(ctx, alice, comments.MarketTarget(1), 0, "No comment on my position"); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	page, err := svc.List(ctx, comments.MarketTarget(1), 0, 0)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if holdings := page.Comments[0].Holdings; len(holdings) != 0 {
+		t.Fatalf("expected alice's holdings to stay private, got %+v", holdings)
+	}
+}
+
+func TestPostRejections(t *testing.T) {
+	svc, _, _, users := newService(t)
+	ctx := context.Background()
+	users["bob"] = dusers.ErrAccountSuspended
+
+	tests := []struct {
+		name   string
+		actor  permissions.Subject
+		target comments.Target
+		body   string
+		want   error
+	}{
+		{name: "empty body", actor: alice, target: comments.MarketTarget(1), body: "   ", want: comments.ErrInvalidInput},
+		{name: "no target", actor: alice, target: comments.Target{}, body: "hi", want: comments.ErrInvalidInput},
+		{name: "both targets", actor: alice, target: comments.Target{MarketID: 1, MarketGroupID: 5}, body: "hi", want: comments.ErrInvalidInput},
+		{name: "unknown market", actor: alice, target: comments.MarketTarget(99), body: "hi", want: dmarkets.ErrMarketNotFound},
+		{name: "proposed market", actor: alice, target: comments.MarketTarget(2), body: "hi", want: comments.ErrDiscussionClosed},
+		{name: "suspended author", actor: bob, target: comments.MarketTarget(1), body: "hi", want: dusers.ErrAccountSuspended},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Post(ctx, tt.actor, tt.target, 0, tt.body); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestEditWindowDeleteAndModeration(t *testing.T) {
+	svc, _, c, _ := newService(t)
+	ctx := context.Background()
+	market := comments.MarketTarget(1)
+	comment, err := svc.Post(ctx, alice, market, 0, "draft")
+	if err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+
+	if _, err := svc.Edit(ctx, bob, comment.ID, "hijack"); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected non-author edit to be denied, got %v", err)
+	}
+	c.now = c.now.Add(5 * time.Minute)
+	edited, err := svc.Edit(ctx, alice, comment.ID, "final")
+	if err != nil || edited.Body != "final" || edited.EditedAt == nil {
+		t.Fatalf("Edit = %+v, %v", edited, err)
+	}
+	c.now = c.now.Add(6 * time.Minute)
+	if _, err := svc.Edit(ctx, alice, comment.ID, "too late"); !errors.Is(err, comments.ErrEditWindowClosed) {
+		t.Fatalf("expected ErrEditWindowClosed, got %v", err)
+	}
+
+	if _, err := svc.Hide(ctx, bob, comment.ID, "spam"); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected regular user hide to be denied, got %v", err)
+	}
+	hidden, err := svc.Hide(ctx, mod, comment.ID, "off topic")
+	if err != nil || !hidden.Hidden() || hidden.Body != "" || hidden.HiddenReason != "off topic" {
+		t.Fatalf("Hide = %+v, %v", hidden, err)
+	}
+	page, _ := svc.List(ctx, market, 0, 0)
+	if got := page.Comments[0]; got.Body != "" || got.BodyHTML != "" || !got.Hidden() {
+		t.Fatalf("hidden comment should be listed without its body, got %+v", got)
+	}
+	restored, err := svc.Unhide(ctx, mod, comment.ID)
+	if err != nil || restored.Hidden() || restored.Body != "final" {
+		t.Fatalf("Unhide = %+v, %v", restored, err)
+	}
+
+	if _, err := svc.Delete(ctx, bob, comment.ID); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected non-author delete to be denied, got %v", err)
+	}
+	deleted, err := svc.Delete(ctx, alice, comment.ID)
+	if err != nil || !deleted.Deleted() || deleted.DeletedBy != "alice" || deleted.Body != "" {
+		t.Fatalf("Delete = %+v, %v", deleted, err)
+	}
+	if _, err := svc.Delete(ctx, mod, comment.ID); err != nil {
+		t.Fatalf("deleting again should be a no-op, got %v", err)
+	}
+}
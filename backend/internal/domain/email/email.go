@@ -0,0 +1,161 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	dusers "socialpredict/internal/domain/users"
+)
+
+// Kind identifies one category of email a user can opt in to.
+type Kind string
+
+const (
+	// KindMarketResolved tells a holder how a market resolved and what they made or lost.
+	KindMarketResolved Kind = "market_resolved"
+	// KindProposalReviewed tells a proposer their market, amendment, or answer was reviewed.
+	KindProposalReviewed Kind = "proposal_reviewed"
+	// KindStewardResolutionDue reminds a steward that their market is past close.
+	KindStewardResolutionDue Kind = "steward_resolution_due"
+	// KindWeeklyDigest is the weekly portfolio change and trending markets summary.
+	KindWeeklyDigest Kind = "weekly_digest"
+)
+
+// KindInfo describes a kind for preference screens.
+type KindInfo struct {
+	Kind        Kind
+	Description string
+}
+
+var kinds = []KindInfo{
+	{KindMarketResolved, "A market you hold resolved, with your profit or loss"},
+	{KindProposalReviewed, "Your market, amendment, or answer proposal was reviewed"},
+	{KindStewardResolutionDue, "A market you steward is past close and needs resolving"},
+	{KindWeeklyDigest, "Weekly summary of your portfolio and trending markets"},
+}
+
+// Kinds lists every kind of email in display order.
+func Kinds() []KindInfo {
+	return append([]KindInfo(nil), kinds...)
+}
+
+// Known reports whether k is a kind of email.
+func Known(k Kind) bool {
+	for _, info := range kinds {
+		if info.Kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is the lifecycle state of a queued message.
+type Status string
+
+const (
+	// StatusPending messages are waiting for their first attempt or a retry.
+	StatusPending Status = "pending"
+	// StatusSent messages were accepted by the transport.
+	StatusSent Status = "sent"
+	// StatusDead messages exhausted their attempts.
+	StatusDead Status = "dead"
+)
+
+var (
+	// ErrInvalidInput indicates an unknown kind or an empty preference change.
+	ErrInvalidInput = errors.New("invalid email input")
+	// ErrInvalidToken indicates an unsubscribe token that matches no user.
+	ErrInvalidToken = errors.New("invalid unsubscribe token")
+)
+
+// Message is one rendered email queued for one user.
+type Message struct {
+	ID             int64
+	Username       string
+	ToAddress      string
+	Kind           Kind
+	DedupeKey      string
+	Subject        string
+	TextBody       string
+	HTMLBody       string
+	UnsubscribeURL string
+	Status         Status
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	SentAt         *time.Time
+	CreatedAt      time.Time
+}
+
+// Preference is a user's choice for one kind.
+type Preference struct {
+	Kind        Kind
+	Description string
+	Enabled     bool
+}
+
+// Subscription is what an unsubscribe token controls.
+type Subscription struct {
+	Username    string
+	Preferences []Preference
+}
+
+// DigestRun records the weekly digest queued for a user.
+type DigestRun struct {
+	Username  string
+	Week      string
+	Equity    int64
+	CreatedAt time.Time
+}
+
+// Repository persists the queue, preferences, tokens, and digest runs.
+type Repository interface {
+	// EnqueueMessage inserts the message unless the user already has one with
+	// the same dedupe key, and reports whether it was inserted.
+	EnqueueMessage(ctx context.Context, message *Message) (bool, error)
+	// ClaimDueMessages leases up to limit pending messages that are due.
+	ClaimDueMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Message, error)
+	SaveMessageOutcome(ctx context.Context, message *Message) error
+	ListPreferences(ctx context.Context, username string) (map[Kind]bool, error)
+	SavePreferences(ctx context.Context, username string, preferences map[Kind]bool, at time.Time) error
+	// ListOptedIn returns the usernames that enabled kind.
+	ListOptedIn(ctx context.Context, kind Kind) ([]string, error)
+	// EnsureUnsubscribeToken stores candidate unless the user already has a
+	// token, and returns the user's token either way.
+	EnsureUnsubscribeToken(ctx context.Context, username, candidate string) (string, error)
+	// UsernameForToken resolves an unsubscribe token or returns ErrInvalidToken.
+	UsernameForToken(ctx context.Context, token string) (string, error)
+	// LatestDigestRun returns the user's most recent digest run, or nil.
+	LatestDigestRun(ctx context.Context, username string) (*DigestRun, error)
+	RecordDigestRun(ctx context.Context, run *DigestRun) error
+}
+
+// Users is the account read surface used to address and describe emails.
+type Users interface {
+	GetUser(ctx context.Context, username string) (*dusers.User, error)
+	GetUserFinancials(ctx context.Context, username string) (map[string]int64, error)
+}
+
+// Markets is the market read surface used to compose emails.
+type Markets interface {
+	GetMarket(ctx context.Context, id int64) (*dmarkets.Market, error)
+	GetUserPositionInMarket(ctx context.Context, marketID int64, username string) (*dmarkets.UserPosition, error)
+	ListByStatus(ctx context.Context, status string, p dmarkets.Page) ([]*dmarkets.Market, error)
+	ListMostTradedMarkets(ctx context.Context, since time.Time, limit int) ([]dmarkets.MarketActivity, error)
+}
+
+// Envelope is one email ready for a transport.
+type Envelope struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+	Headers  map[string]string
+}
+
+// Transport hands an envelope to a mail system.
+type Transport interface {
+	Send(ctx context.Context, envelope Envelope) error
+}
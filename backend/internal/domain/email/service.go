@@ -0,0 +1,531 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	dnotifications "socialpredict/internal/domain/notifications"
+	dusers "socialpredict/internal/domain/users"
+)
+
+const (
+	tokenBytes     = 24
+	maxErrorLength = 500
+
+	defaultSiteName    = "SocialPredict"
+	defaultMaxAttempts = 6
+	defaultBaseBackoff = time.Minute
+	defaultMaxBackoff  = 6 * time.Hour
+	defaultBatchSize   = 50
+	defaultLease       = 2 * time.Minute
+
+	stewardSweepPage = 100
+	trendingWindow   = 7 * 24 * time.Hour
+	trendingLimit    = 5
+
+	// UnsubscribePath is the public API path unsubscribe links point at.
+	UnsubscribePath = "/api/v0/email/unsubscribe"
+	preferencesPath = "/notifications"
+)
+
+// Config carries the links and retry tuning for outgoing email. Zero values
+// use the defaults.
+type Config struct {
+	PublicBaseURL string
+	SiteName      string
+	MaxAttempts   int
+	BaseBackoff   time.Duration
+	MaxBackoff    time.Duration
+	BatchSize     int
+	Lease         time.Duration
+}
+
+func normalizeConfig(config Config) Config {
+	config.PublicBaseURL = strings.TrimRight(strings.TrimSpace(config.PublicBaseURL), "/")
+	if config.PublicBaseURL == "" {
+		config.PublicBaseURL = "http://localhost"
+	}
+	if strings.TrimSpace(config.SiteName) == "" {
+		config.SiteName = defaultSiteName
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultBaseBackoff
+	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+	return config
+}
+
+// Service queues opt-in email for key events and weekly digests and hands
+// due messages to the transport with retries. Nothing is sent to a user who
+// has not opted in to that kind.
+type Service struct {
+	repo      Repository
+	users     Users
+	markets   Markets
+	transport Transport
+	config    Config
+	now       func() time.Time
+}
+
+var _ dnotifications.Forwarder = (*Service)(nil)
+
+// NewService constructs an email service. A nil transport queues messages
+// without sending them.
+func NewService(repo Repository, users Users, markets Markets, transport Transport, config Config, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{
+		repo:      repo,
+		users:     users,
+		markets:   markets,
+		transport: transport,
+		config:    normalizeConfig(config),
+		now:       now,
+	}
+}
+
+// Preferences returns the user's choice for every kind. Kinds the user never
+// enabled are off.
+func (s *Service) Preferences(ctx context.Context, username string) ([]Preference, error) {
+	if err := s.ready(username); err != nil {
+		return nil, err
+	}
+	stored, err := s.repo.ListPreferences(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	preferences := make([]Preference, 0, len(kinds))
+	for _, info := range kinds {
+		preferences = append(preferences, Preference{Kind: info.Kind, Description: info.Description, Enabled: stored[info.Kind]})
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences stores the given choices and returns the full set.
+// Kinds not mentioned keep their current setting.
+func (s *Service) UpdatePreferences(ctx context.Context, username string, changes map[Kind]bool) ([]Preference, error) {
+	if err := s.ready(username); err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, ErrInvalidInput
+	}
+	for kind := range changes {
+		if !Known(kind) {
+			return nil, ErrInvalidInput
+		}
+	}
+	if err := s.repo.SavePreferences(ctx, username, changes, s.now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.Preferences(ctx, username)
+}
+
+// Subscription returns the account and preferences an unsubscribe token
+// controls, so a confirmation screen can show them before anything changes.
+func (s *Service) Subscription(ctx context.Context, token string) (*Subscription, error) {
+	username, err := s.tokenUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	preferences, err := s.Preferences(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{Username: username, Preferences: preferences}, nil
+}
+
+// Unsubscribe turns off kind for the token's user, or every kind when kind
+// is empty.
+func (s *Service) Unsubscribe(ctx context.Context, token string, kind Kind) (*Subscription, error) {
+	username, err := s.tokenUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	changes := map[Kind]bool{}
+	if kind == "" {
+		for _, info := range kinds {
+			changes[info.Kind] = false
+		}
+	} else {
+		changes[kind] = false
+	}
+	preferences, err := s.UpdatePreferences(ctx, username, changes)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{Username: username, Preferences: preferences}, nil
+}
+
+// Forward queues an email for a notification the recipient opted in to
+// receive by email. It is the notifications service's forwarder, so email
+// reaches exactly the users the in-app notification addresses.
+func (s *Service) Forward(ctx context.Context, notification *dnotifications.Notification) error {
+	if s == nil || s.repo == nil || notification == nil || notification.EventKey == "" {
+		return nil
+	}
+	var kind Kind
+	switch notification.Type {
+	case dnotifications.TypeMarketResolved:
+		kind = KindMarketResolved
+	case dnotifications.TypeMarketApproved, dnotifications.TypeMarketRejected,
+		dnotifications.TypeAmendmentReviewed, dnotifications.TypeAnswerReviewed:
+		kind = KindProposalReviewed
+	default:
+		return nil
+	}
+	user, ok, err := s.recipient(ctx, notification.Username, kind)
+	if err != nil || !ok {
+		return err
+	}
+
+	data := templateData{Headline: notification.Title, Detail: notification.Body}
+	switch {
+	case notification.MarketID > 0:
+		data.MarketURL = s.link("markets", strconv.FormatInt(notification.MarketID, 10))
+	case notification.MarketGroupID > 0:
+		data.MarketURL = s.link("markets", "group", strconv.FormatInt(notification.MarketGroupID, 10))
+	}
+	if kind == KindMarketResolved {
+		if err := s.describeResolution(ctx, notification, &data); err != nil {
+			if errors.Is(err, dmarkets.ErrMarketNotFound) {
+				return nil
+			}
+			return err
+		}
+	}
+	_, err = s.enqueue(ctx, user, kind, "notif:"+notification.EventKey, data)
+	return err
+}
+
+func (s *Service) describeResolution(ctx context.Context, notification *dnotifications.Notification, data *templateData) error {
+	market, err := s.markets.GetMarket(ctx, notification.MarketID)
+	if err != nil {
+		return err
+	}
+	position, err := s.markets.GetUserPositionInMarket(ctx, market.ID, notification.Username)
+	if err != nil {
+		return err
+	}
+	data.MarketTitle = market.QuestionTitle
+	data.Resolution = market.ResolutionResult
+	if position != nil {
+		data.Spent = position.TotalSpent
+		data.Payout = position.Value
+		data.Profit = position.Value - position.TotalSpent
+	}
+	return nil
+}
+
+// RemindStewards queues one reminder per market that is past close and
+// unresolved to its steward, and returns how many were queued.
+func (s *Service) RemindStewards(ctx context.Context) (int, error) {
+	if s == nil || s.repo == nil || s.markets == nil {
+		return 0, nil
+	}
+	queued := 0
+	for offset := 0; ; offset += stewardSweepPage {
+		markets, err := s.markets.ListByStatus(ctx, "closed", dmarkets.Page{Limit: stewardSweepPage, Offset: offset})
+		if err != nil {
+			return queued, err
+		}
+		for _, market := range markets {
+			user, ok, err := s.recipient(ctx, market.CurrentStewardUsername(), KindStewardResolutionDue)
+			if err != nil {
+				return queued, err
+			}
+			if !ok {
+				continue
+			}
+			inserted, err := s.enqueue(ctx, user, KindStewardResolutionDue, "steward_due:"+strconv.FormatInt(market.ID, 10), templateData{
+				MarketTitle: market.QuestionTitle,
+				MarketURL:   s.link("markets", strconv.FormatInt(market.ID, 10)),
+				ClosedOn:    market.ResolutionDateTime.UTC().Format("Jan 2, 2006"),
+			})
+			if err != nil {
+				return queued, err
+			}
+			if inserted {
+				queued++
+			}
+		}
+		if len(markets) < stewardSweepPage {
+			return queued, nil
+		}
+	}
+}
+
+// SendWeeklyDigests queues this week's digest for every opted-in user who
+// has not had one yet, and returns how many were queued. Each digest
+// reports equity and its change since the user's previous digest.
+func (s *Service) SendWeeklyDigests(ctx context.Context) (int, error) {
+	if s == nil || s.repo == nil || s.users == nil || s.markets == nil {
+		return 0, nil
+	}
+	usernames, err := s.repo.ListOptedIn(ctx, KindWeeklyDigest)
+	if err != nil || len(usernames) == 0 {
+		return 0, err
+	}
+	now := s.now().UTC()
+	year, weekNumber := now.ISOWeek()
+	week := fmt.Sprintf("%d-W%02d", year, weekNumber)
+	trending, err := s.trending(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	var errs []error
+	for _, username := range usernames {
+		inserted, err := s.queueDigest(ctx, username, week, trending, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digest for %s: %w", username, err))
+			continue
+		}
+		if inserted {
+			queued++
+		}
+	}
+	return queued, errors.Join(errs...)
+}
+
+func (s *Service) queueDigest(ctx context.Context, username, week string, trending []trendingMarket, now time.Time) (bool, error) {
+	previous, err := s.repo.LatestDigestRun(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	if previous != nil && previous.Week == week {
+		return false, nil
+	}
+	user, ok, err := s.recipient(ctx, username, KindWeeklyDigest)
+	if err != nil || !ok {
+		return false, err
+	}
+	financials, err := s.users.GetUserFinancials(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	equity := financials["equity"]
+	data := templateData{Equity: equity, Trending: trending}
+	if previous != nil {
+		data.HasPrevious = true
+		data.EquityChange = equity - previous.Equity
+	}
+	// The dedupe key makes a retry after a failed RecordDigestRun harmless.
+	inserted, err := s.enqueue(ctx, user, KindWeeklyDigest, "digest:"+week, data)
+	if err != nil {
+		return false, err
+	}
+	if err := s.repo.RecordDigestRun(ctx, &DigestRun{Username: username, Week: week, Equity: equity, CreatedAt: now}); err != nil {
+		return false, err
+	}
+	return inserted, nil
+}
+
+func (s *Service) trending(ctx context.Context, now time.Time) ([]trendingMarket, error) {
+	activity, err := s.markets.ListMostTradedMarkets(ctx, now.Add(-trendingWindow), trendingLimit)
+	if err != nil {
+		return nil, err
+	}
+	trending := make([]trendingMarket, 0, len(activity))
+	for _, market := range activity {
+		trending = append(trending, trendingMarket{
+			Title:  market.QuestionTitle,
+			URL:    s.link("markets", strconv.FormatInt(market.MarketID, 10)),
+			Trades: market.Trades,
+		})
+	}
+	return trending, nil
+}
+
+// DeliverDue sends one batch of due messages and returns how many were
+// claimed. Transport failures are recorded on the message, not returned.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	if s == nil || s.repo == nil {
+		return 0, nil
+	}
+	claimed, err := s.repo.ClaimDueMessages(ctx, s.now().UTC(), s.config.Lease, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for _, message := range claimed {
+		if err := s.attempt(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(claimed), errors.Join(errs...)
+}
+
+// attempt sends the message once and records the outcome. Failed attempts
+// back off exponentially until MaxAttempts, then the message is dead.
+func (s *Service) attempt(ctx context.Context, message *Message) error {
+	var failure error
+	if s.transport == nil {
+		failure = errors.New("email transport unavailable")
+	} else {
+		failure = s.transport.Send(ctx, Envelope{
+			To:       message.ToAddress,
+			Subject:  message.Subject,
+			TextBody: message.TextBody,
+			HTMLBody: message.HTMLBody,
+			Headers: map[string]string{
+				"List-Unsubscribe":      "<" + message.UnsubscribeURL + ">",
+				"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+			},
+		})
+	}
+
+	now := s.now().UTC()
+	message.Attempts++
+	switch {
+	case failure == nil:
+		message.Status = StatusSent
+		message.LastError = ""
+		message.SentAt = &now
+	case message.Attempts >= s.config.MaxAttempts:
+		message.Status = StatusDead
+		message.LastError = truncateError(failure)
+	default:
+		message.Status = StatusPending
+		message.LastError = truncateError(failure)
+		message.NextAttemptAt = now.Add(s.backoff(message.Attempts))
+	}
+	return s.repo.SaveMessageOutcome(ctx, message)
+}
+
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.config.MaxBackoff {
+			return s.config.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// recipient returns the user when they opted in to kind, have an address,
+// and are not banned.
+func (s *Service) recipient(ctx context.Context, username string, kind Kind) (*dusers.User, bool, error) {
+	username = strings.TrimSpace(username)
+	if username == "" || s.users == nil {
+		return nil, false, nil
+	}
+	preferences, err := s.repo.ListPreferences(ctx, username)
+	if err != nil || !preferences[kind] {
+		return nil, false, err
+	}
+	user, err := s.users.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, dusers.ErrUserNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if strings.TrimSpace(user.Email) == "" || user.EnsureCanAuthenticate(s.now()) != nil {
+		return nil, false, nil
+	}
+	return user, true, nil
+}
+
+// enqueue renders kind for user and queues it under dedupeKey.
+func (s *Service) enqueue(ctx context.Context, user *dusers.User, kind Kind, dedupeKey string, data templateData) (bool, error) {
+	token, err := s.unsubscribeToken(ctx, user.Username)
+	if err != nil {
+		return false, err
+	}
+	unsubscribeURL := s.config.PublicBaseURL + UnsubscribePath + "?" + url.Values{"token": {token}}.Encode()
+	data.SiteName = s.config.SiteName
+	data.Username = user.Username
+	data.PreferencesURL = s.link(strings.TrimPrefix(preferencesPath, "/"))
+	data.UnsubscribeURL = unsubscribeURL
+
+	subject, text, html, err := render(kind, data)
+	if err != nil {
+		return false, err
+	}
+	now := s.now().UTC()
+	return s.repo.EnqueueMessage(ctx, &Message{
+		Username:       user.Username,
+		ToAddress:      strings.TrimSpace(user.Email),
+		Kind:           kind,
+		DedupeKey:      dedupeKey,
+		Subject:        singleLine(subject),
+		TextBody:       text,
+		HTMLBody:       html,
+		UnsubscribeURL: unsubscribeURL,
+		Status:         StatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	})
+}
+
+func (s *Service) unsubscribeToken(ctx context.Context, username string) (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate unsubscribe token: %w", err)
+	}
+	return s.repo.EnsureUnsubscribeToken(ctx, username, hex.EncodeToString(buf))
+}
+
+func (s *Service) tokenUser(ctx context.Context, token string) (string, error) {
+	if s == nil || s.repo == nil {
+		return "", errors.New("email service unavailable")
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrInvalidToken
+	}
+	return s.repo.UsernameForToken(ctx, token)
+}
+
+func (s *Service) link(elems ...string) string {
+	link, err := url.JoinPath(s.config.PublicBaseURL, elems...)
+	if err != nil {
+		return s.config.PublicBaseURL
+	}
+	return link
+}
+
+func (s *Service) ready(username string) error {
+	if s == nil || s.repo == nil {
+		return errors.New("email service unavailable")
+	}
+	if strings.TrimSpace(username) == "" {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
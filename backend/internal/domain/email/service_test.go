@@ -0,0 +1,376 @@
+package email_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/email"
+	dmarkets "socialpredict/internal/domain/markets"
+	dnotifications "socialpredict/internal/domain/notifications"
+	dusers "socialpredict/internal/domain/users"
+)
+
+type memoryRepo struct {
+	messages    []*email.Message
+	preferences map[string]map[email.Kind]bool
+	tokens      map[string]string
+	runs        []*email.DigestRun
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{preferences: map[string]map[email.Kind]bool{}, tokens: map[string]string{}}
+}
+
+func (m *memoryRepo) EnqueueMessage(_ context.Context, message *email.Message) (bool, error) {
+	for _, existing := range m.messages {
+		if existing.Username == message.Username && existing.DedupeKey == message.DedupeKey {
+			return false, nil
+		}
+	}
+	message.ID = int64(len(m.messages) + 1)
+	clone := *message
+	m.messages = append(m.messages, &clone)
+	return true, nil
+}
+
+func (m *memoryRepo) ClaimDueMessages(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*email.Message, error) {
+	var claimed []*email.Message
+	for _, message := range m.messages {
+		if len(claimed) < limit && message.Status == email.StatusPending && !message.NextAttemptAt.After(now) {
+			message.NextAttemptAt = now.Add(lease)
+			clone := *message
+			claimed = append(claimed, &clone)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *memoryRepo) SaveMessageOutcome(_ context.Context, message *email.Message) error {
+	for i, existing := range m.messages {
+		if existing.ID == message.ID {
+			clone := *message
+			m.messages[i] = &clone
+		}
+	}
+	return nil
+}
+
+func (m *memoryRepo) ListPreferences(_ context.Context, username string) (map[email.Kind]bool, error) {
+	out := map[email.Kind]bool{}
+	for kind, enabled := range m.preferences[username] {
+		out[kind] = enabled
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) SavePreferences(_ context.Context, username string, preferences map[email.Kind]bool, _ time.Time) error {
+	if m.preferences[username] == nil {
+		m.preferences[username] = map[email.Kind]bool{}
+	}
+	for kind, enabled := range preferences {
+		m.preferences[username][kind] = enabled
+	}
+	return nil
+}
+
+func (m *memoryRepo) ListOptedIn(_ context.Context, kind email.Kind) ([]string, error) {
+	var out []string
+	for username, preferences := range m.preferences {
+		if preferences[kind] {
+			out = append(out, username)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) EnsureUnsubscribeToken(_ context.Context, username, candidate string) (string, error) {
+	for token, owner := range m.tokens {
+		if owner == username {
+			return token, nil
+		}
+	}
+	m.tokens[candidate] = username
+	return candidate, nil
+}
+
+func (m *memoryRepo) UsernameForToken(_ context.Context, token string) (string, error) {
+	username, ok := m.tokens[token]
+	if !ok {
+		return "", email.ErrInvalidToken
+	}
+	return username, nil
+}
+
+func (m *memoryRepo) LatestDigestRun(_ context.Context, username string) (*email.DigestRun, error) {
+	var latest *email.DigestRun
+	for _, run := range m.runs {
+		if run.Username == username {
+			latest = run
+		}
+	}
+	return latest, nil
+}
+
+func (m *memoryRepo) RecordDigestRun(_ context.Context, run *email.DigestRun) error {
+	for _, existing := range m.runs {
+		if existing.Username == run.Username && existing.Week == run.Week {
+			return nil
+		}
+	}
+	clone := *run
+	m.runs = append(m.runs, &clone)
+	return nil
+}
+
+type fakeUsers struct {
+	users  map[string]*dusers.User
+	equity map[string]int64
+}
+
+func (f *fakeUsers) GetUser(_ context.Context, username string) (*dusers.User, error) {
+	user, ok := f.users[username]
+	if !ok {
+		return nil, dusers.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeUsers) GetUserFinancials(_ context.Context, username string) (map[string]int64, error) {
+	return map[string]int64{"equity": f.equity[username]}, nil
+}
+
+type fakeMarkets struct {
+	markets  map[int64]*dmarkets.Market
+	position map[string]*dmarkets.UserPosition
+	closed   []*dmarkets.Market
+}
+
+func (f *fakeMarkets) GetMarket(_ context.Context, id int64) (*dmarkets.Market, error) {
+	market, ok := f.markets[id]
+	if !ok {
+		return nil, dmarkets.ErrMarketNotFound
+	}
+	return market, nil
+}
+
+func (f *fakeMarkets) GetUserPositionInMarket(_ context.Context, _ int64, username string) (*dmarkets.UserPosition, error) {
+	return f.position[username], nil
+}
+
+func (f *fakeMarkets) ListByStatus(_ context.Context, status string, p dmarkets.Page) ([]*dmarkets.Market, error) {
+	if status != "closed" || p.Offset > 0 {
+		return nil, nil
+	}
+	return f.closed, nil
+}
+
+func (f *fakeMarkets) ListMostTradedMarkets(context.Context, time.Time, int) ([]dmarkets.MarketActivity, error) {
+	return []dmarkets.MarketActivity{{MarketID: 9, QuestionTitle: "Will it snow?", Trades: 12, Volume: 300}}, nil
+}
+
+type recordingTransport struct {
+	sent []email.Envelope
+	err  error
+}
+
+func (r *recordingTransport) Send(_ context.Context, envelope email.Envelope) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, envelope)
+	return nil
+}
+
+type fixture struct {
+	svc       *email.Service
+	repo      *memoryRepo
+	users     *fakeUsers
+	markets   *fakeMarkets
+	transport *recordingTransport
+	now       time.Time
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		repo: newMemoryRepo(),
+		users: &fakeUsers{
+			users: map[string]*dusers.User{
+				"alice": {Username: "alice", Email: "alice@example.com"},
+				"bob":   {Username: "bob", Email: "bob@example.com"},
+				"carol": {Username: "carol", Email: "carol@example.com"},
+				"dave":  {Username: "dave", Email: "dave@example.com", AccountStatus: dusers.AccountStatusBanned},
+			},
+			equity: map[string]int64{"alice": 1200},
+		},
+		markets: &fakeMarkets{
+			markets: map[int64]*dmarkets.Market{
+				7: {ID: 7, QuestionTitle: "Will it rain?", CreatorUsername: "carol", ResolutionResult: "YES"},
+			},
+			position: map[string]*dmarkets.UserPosition{
+				"alice": {Username: "alice", Value: 140, TotalSpent: 100},
+				"bob":   {Username: "bob", Value: 0, TotalSpent: 30},
+			},
+		},
+		transport: &recordingTransport{},
+		now:       time.Date(2026, 6, 30, 9, 0, 0, 0, time.UTC),
+	}
+	f.svc = email.NewService(f.repo, f.users, f.markets, f.transport, email.Config{PublicBaseURL: "https://predict.example.com/", MaxAttempts: 2}, func() time.Time { return f.now })
+	return f
+}
+
+func (f *fixture) optIn(t *testing.T, username string, kinds ...email.Kind) {
+	t.Helper()
+	changes := map[email.Kind]bool{}
+	for _, kind := range kinds {
+		changes[kind] = true
+	}
+	if _, err := f.svc.UpdatePreferences(context.Background(), username, changes); err != nil {
+		t.Fatalf("UpdatePreferences returned error: %v", err)
+	}
+}
+
+func TestForwardQueuesOnlyOptedInKindsWithProfitAndLoss(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.optIn(t, "alice", email.KindMarketResolved)
+	f.optIn(t, "carol", email.KindProposalReviewed)
+	f.optIn(t, "dave", email.KindMarketResolved)
+
+	notifications := []*dnotifications.Notification{
+		{Username: "alice", EventKey: "evt_5", Type: dnotifications.TypeMarketResolved, Title: "A market you hold resolved", MarketID: 7},
+		{Username: "alice", EventKey: "evt_5", Type: dnotifications.TypeMarketResolved, Title: "A market you hold resolved", MarketID: 7},
+		{Username: "bob", EventKey: "evt_5", Type: dnotifications.TypeMarketResolved, Title: "A market you hold resolved", MarketID: 7},
+		{Username: "dave", EventKey: "evt_5", Type: dnotifications.TypeMarketResolved, Title: "A market you hold resolved", MarketID: 7},
+		{Username: "carol", EventKey: "evt_6", Type: dnotifications.TypeMarketApproved, Title: "Your market was approved", Body: "Will it rain?", MarketID: 7},
+		{Username: "carol", EventKey: "evt_7", Type: dnotifications.TypeStewardAssigned, Title: "You are now the steward of a market", MarketID: 7},
+	}
+	for _, notification := range notifications {
+		if err := f.svc.Forward(ctx, notification); err != nil {
+			t.Fatalf("Forward returned error: %v", err)
+		}
+	}
+
+	if len(f.repo.messages) != 2 {
+		t.Fatalf("expected alice's resolution and carol's review, got %+v", f.repo.messages)
+	}
+	resolved := f.repo.messages[0]
+	if resolved.Username != "alice" || resolved.Kind != email.KindMarketResolved || resolved.DedupeKey != "notif:evt_5" {
+		t.Fatalf("unexpected resolution message: %+v", resolved)
+	}
+	if resolved.Subject != "Resolved YES: Will it rain?" || !strings.Contains(resolved.TextBody, "profit of +40") || !strings.Contains(resolved.HTMLBody, "<strong>&#43;40</strong>") {
+		t.Fatalf("resolution email should carry the P&L: %q / %q", resolved.Subject, resolved.TextBody)
+	}
+	if !strings.Contains(resolved.TextBody, "https://predict.example.com/markets/7") || !strings.HasPrefix(resolved.UnsubscribeURL, "https://predict.example.com"+email.UnsubscribePath+"?token=") {
+		t.Fatalf("unexpected links: %q unsubscribe=%q", resolved.TextBody, resolved.UnsubscribeURL)
+	}
+	if review := f.repo.messages[1]; review.Kind != email.KindProposalReviewed || review.Subject != "Your market was approved" {
+		t.Fatalf("unexpected review message: %+v", review)
+	}
+}
+
+func TestSweepsQueueStewardRemindersAndWeeklyDigestsOnce(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.optIn(t, "carol", email.KindStewardResolutionDue)
+	f.optIn(t, "alice", email.KindWeeklyDigest)
+	f.markets.closed = []*dmarkets.Market{
+		{ID: 7, QuestionTitle: "Will it rain?", CreatorUsername: "carol", ResolutionDateTime: f.now.Add(-24 * time.Hour)},
+		{ID: 8, QuestionTitle: "Will it hail?", CreatorUsername: "carol", StewardUsername: "bob", ResolutionDateTime: f.now.Add(-time.Hour)},
+	}
+
+	for i := 0; i < 2; i++ {
+		if queued, err := f.svc.RemindStewards(ctx); err != nil || queued != 1-i {
+			t.Fatalf("RemindStewards run %d = %d, %v", i, queued, err)
+		}
+		if queued, err := f.svc.SendWeeklyDigests(ctx); err != nil || queued != 1-i {
+			t.Fatalf("SendWeeklyDigests run %d = %d, %v", i, queued, err)
+		}
+	}
+	if len(f.repo.messages) != 2 {
+		t.Fatalf("expected one reminder and one digest, got %d", len(f.repo.messages))
+	}
+	if reminder := f.repo.messages[0]; reminder.Username != "carol" || reminder.DedupeKey != "steward_due:7" {
+		t.Fatalf("unexpected reminder: %+v", reminder)
+	}
+	digest := f.repo.messages[1]
+	if digest.DedupeKey != "digest:2026-W27" || !strings.Contains(digest.TextBody, "worth 1200.") || !strings.Contains(digest.TextBody, "Will it snow? (12 trades)") {
+		t.Fatalf("unexpected first digest: %q", digest.TextBody)
+	}
+
+	f.now = f.now.Add(7 * 24 * time.Hour)
+	f.users.equity["alice"] = 1150
+	if queued, err := f.svc.SendWeeklyDigests(ctx); err != nil || queued != 1 {
+		t.Fatalf("next week's digest = %d, %v", queued, err)
+	}
+	if next := f.repo.messages[2]; !strings.Contains(next.TextBody, "worth 1150, -50 since last week") {
+		t.Fatalf("digest should report the weekly change: %q", next.TextBody)
+	}
+}
+
+func TestDeliverDueRetriesThenGivesUpAndUnsubscribeStopsEmail(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.optIn(t, "alice", email.KindMarketResolved, email.KindWeeklyDigest)
+	if err := f.svc.Forward(ctx, &dnotifications.Notification{Username: "alice", EventKey: "evt_1", Type: dnotifications.TypeMarketResolved, MarketID: 7}); err != nil {
+		t.Fatalf("Forward returned error: %v", err)
+	}
+
+	f.transport.err = errors.New("relay unavailable")
+	if claimed, err := f.svc.DeliverDue(ctx); err != nil || claimed != 1 {
+		t.Fatalf("DeliverDue = %d, %v", claimed, err)
+	}
+	if message := f.repo.messages[0]; message.Status != email.StatusPending || message.Attempts != 1 || message.LastError != "relay unavailable" || !message.NextAttemptAt.Equal(f.now.Add(time.Minute)) {
+		t.Fatalf("failed attempt should back off: %+v", message)
+	}
+	if claimed, _ := f.svc.DeliverDue(ctx); claimed != 0 {
+		t.Fatalf("message should wait for its backoff")
+	}
+	f.now = f.now.Add(time.Minute)
+	if _, err := f.svc.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue returned error: %v", err)
+	}
+	if message := f.repo.messages[0]; message.Status != email.StatusDead || message.Attempts != 2 {
+		t.Fatalf("message should be dead after MaxAttempts: %+v", message)
+	}
+
+	f.transport.err = nil
+	if err := f.svc.Forward(ctx, &dnotifications.Notification{Username: "alice", EventKey: "evt_2", Type: dnotifications.TypeMarketResolved, MarketID: 7}); err != nil {
+		t.Fatalf("Forward returned error: %v", err)
+	}
+	if _, err := f.svc.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue returned error: %v", err)
+	}
+	if len(f.transport.sent) != 1 || f.repo.messages[1].Status != email.StatusSent {
+		t.Fatalf("expected one sent message, got %+v", f.transport.sent)
+	}
+	envelope := f.transport.sent[0]
+	if envelope.To != "alice@example.com" || envelope.Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+		t.Fatalf("unexpected envelope: %+v", envelope)
+	}
+
+	token := strings.TrimPrefix(f.repo.messages[1].UnsubscribeURL, "https://predict.example.com"+email.UnsubscribePath+"?token=")
+	if _, err := f.svc.Subscription(ctx, "bogus"); !errors.Is(err, email.ErrInvalidToken) {
+		t.Fatalf("unknown token error = %v", err)
+	}
+	subscription, err := f.svc.Unsubscribe(ctx, token, email.KindMarketResolved)
+	if err != nil || subscription.Username != "alice" {
+		t.Fatalf("Unsubscribe = %+v, %v", subscription, err)
+	}
+	for _, preference := range subscription.Preferences {
+		if want := preference.Kind == email.KindWeeklyDigest; preference.Enabled != want {
+			t.Fatalf("preference %s enabled=%v, want %v", preference.Kind, preference.Enabled, want)
+		}
+	}
+	if _, err := f.svc.Unsubscribe(ctx, token, ""); err != nil {
+		t.Fatalf("Unsubscribe all returned error: %v", err)
+	}
+	if err := f.svc.Forward(ctx, &dnotifications.Notification{Username: "alice", EventKey: "evt_3", Type: dnotifications.TypeMarketResolved, MarketID: 7}); err != nil {
+		t.Fatalf("Forward returned error: %v", err)
+	}
+	if len(f.repo.messages) != 2 {
+		t.Fatalf("unsubscribed user should not be emailed, got %d messages", len(f.repo.messages))
+	}
+}
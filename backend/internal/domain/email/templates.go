@@ -0,0 +1,89 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strconv"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+type trendingMarket struct {
+	Title  string
+	URL    string
+	Trades int64
+}
+
+// templateData is the view model every template renders from. Each kind
+// uses the fields it needs.
+type templateData struct {
+	SiteName       string
+	Username       string
+	PreferencesURL string
+	UnsubscribeURL string
+
+	Headline    string
+	Detail      string
+	MarketTitle string
+	MarketURL   string
+	Resolution  string
+	ClosedOn    string
+	Spent       int64
+	Payout      int64
+	Profit      int64
+
+	Equity       int64
+	EquityChange int64
+	HasPrevious  bool
+	Trending     []trendingMarket
+}
+
+var templateFuncs = map[string]any{
+	"signed": func(value int64) string {
+		if value > 0 {
+			return "+" + strconv.FormatInt(value, 10)
+		}
+		return strconv.FormatInt(value, 10)
+	},
+}
+
+type kindTemplates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var renderers = func() map[Kind]kindTemplates {
+	out := make(map[Kind]kindTemplates, len(kinds))
+	for _, info := range kinds {
+		name := string(info.Kind)
+		out[info.Kind] = kindTemplates{
+			text: texttemplate.Must(texttemplate.New(name).Funcs(templateFuncs).
+				ParseFS(templateFiles, "templates/footer.txt.tmpl", "templates/"+name+".txt.tmpl")),
+			html: htmltemplate.Must(htmltemplate.New(name).Funcs(templateFuncs).
+				ParseFS(templateFiles, "templates/layout.html.tmpl", "templates/"+name+".html.tmpl")),
+		}
+	}
+	return out
+}()
+
+// render produces the subject, plain-text body, and HTML body for kind.
+func render(kind Kind, data templateData) (string, string, string, error) {
+	templates, ok := renderers[kind]
+	if !ok {
+		return "", "", "", ErrInvalidInput
+	}
+	var subject, text, html bytes.Buffer
+	if err := templates.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", "", err
+	}
+	if err := templates.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return "", "", "", err
+	}
+	if err := templates.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return "", "", "", err
+	}
+	return subject.String(), text.String(), html.String(), nil
+}
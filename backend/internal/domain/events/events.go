@@ -0,0 +1,160 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// Type names a domain event.
+type Type string
+
+const (
+	// BetPlaced is recorded when a buy is accepted.
+	BetPlaced Type = "bet.placed"
+	// SharesSold is recorded when a sale is accepted.
+	SharesSold Type = "shares.sold"
+	// MarketCreated is recorded for every new market, including grouped answers.
+	MarketCreated Type = "market.created"
+	// MarketApproved is recorded when a proposed market is published.
+	MarketApproved Type = "market.approved"
+	// MarketRejected is recorded when a proposed market is rejected.
+	MarketRejected Type = "market.rejected"
+	// MarketResolved is recorded when a market settles, including group members.
+	MarketResolved Type = "market.resolved"
+	// MarketStewardChanged is recorded when a market steward is reassigned.
+	MarketStewardChanged Type = "market.steward_changed"
+	// MarketTagsChanged is recorded when a market's tags are replaced.
+	MarketTagsChanged Type = "market.tags_changed"
+	// AmendmentApproved is recorded when a description amendment is applied.
+	AmendmentApproved Type = "market.amendment_approved"
+	// AmendmentRejected is recorded when a description amendment is rejected.
+	AmendmentRejected Type = "market.amendment_rejected"
+	// MarketGroupCreated is recorded when a market group is created.
+	MarketGroupCreated Type = "market_group.created"
+	// MarketGroupApproved is recorded when a proposed market group is published.
+	MarketGroupApproved Type = "market_group.approved"
+	// MarketGroupRejected is recorded when a proposed market group is rejected.
+	MarketGroupRejected Type = "market_group.rejected"
+	// MarketGroupResolved is recorded when a market group settles.
+	MarketGroupResolved Type = "market_group.resolved"
+	// MarketGroupStewardChanged is recorded when a group steward is reassigned.
+	MarketGroupStewardChanged Type = "market_group.steward_changed"
+	// MarketGroupTagsChanged is recorded when a group's tags are replaced.
+	MarketGroupTagsChanged Type = "market_group.tags_changed"
+	// AnswerProposed is recorded when an answer addition awaits review.
+	AnswerProposed Type = "market_group.answer_proposed"
+	// AnswerAdded is recorded when an answer joins a market group.
+	AnswerAdded Type = "market_group.answer_added"
+	// AnswerRejected is recorded when a proposed answer is rejected.
+	AnswerRejected Type = "market_group.answer_rejected"
+	// AnswerAdditionSettingsChanged is recorded when a group opens or closes answer additions.
+	AnswerAdditionSettingsChanged Type = "market_group.answer_settings_changed"
+	// TagCatalogChanged is recorded when a tag is created or edited.
+	TagCatalogChanged Type = "tags.catalog_changed"
+	// DiscoveryContentChanged is recorded when CMS discovery pages or pins change.
+	DiscoveryContentChanged Type = "cms.discovery_changed"
+	// ReportResolved is recorded for each content report a moderator dismisses or acts on.
+	ReportResolved Type = "report.resolved"
+	// SeasonArchived is recorded when a season rolls over, after any balance reset.
+	SeasonArchived Type = "season.archived"
+)
+
+var registry = []Type{
+	BetPlaced,
+	SharesSold,
+	MarketCreated,
+	MarketApproved,
+	MarketRejected,
+	MarketResolved,
+	MarketStewardChanged,
+	MarketTagsChanged,
+	AmendmentApproved,
+	AmendmentRejected,
+	MarketGroupCreated,
+	MarketGroupApproved,
+	MarketGroupRejected,
+	MarketGroupResolved,
+	MarketGroupStewardChanged,
+	MarketGroupTagsChanged,
+	AnswerProposed,
+	AnswerAdded,
+	AnswerRejected,
+	AnswerAdditionSettingsChanged,
+	TagCatalogChanged,
+	DiscoveryContentChanged,
+	ReportResolved,
+	SeasonArchived,
+}
+
+// All returns every registered event type in registry order.
+func All() []Type {
+	return append([]Type(nil), registry...)
+}
+
+// Known reports whether t is a registered event type.
+func Known(t Type) bool {
+	for _, known := range registry {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a fact about a committed domain change. MarketID and MarketGroupID
+// are zero when the event is not scoped to a market or group; Username is the
+// account that caused the change, when there is one.
+type Event struct {
+	ID            int64
+	Type          Type
+	MarketID      int64
+	MarketGroupID int64
+	Username      string
+	Data          map[string]any
+	OccurredAt    time.Time
+}
+
+// Recorder appends events to the outbox. Repositories scoped to a unit of
+// work implement it so events commit or roll back with the change itself.
+type Recorder interface {
+	RecordEvents(ctx context.Context, events ...Event) error
+}
+
+// Notifier is told after a unit of work that recorded events has committed,
+// so delivery can start without waiting for the next outbox poll.
+type Notifier interface {
+	Notify()
+}
+
+// ErrRecorderUnavailable is returned by Record when repo cannot append to
+// the outbox, so a write path never drops its events silently.
+var ErrRecorderUnavailable = errors.New("events: repository cannot record outbox events")
+
+// Record appends events through repo. It fails with ErrRecorderUnavailable
+// when repo is nil or does not implement Recorder.
+func Record(ctx context.Context, repo any, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	recorder, ok := repo.(Recorder)
+	if !ok || isNilRecorder(recorder) {
+		return ErrRecorderUnavailable
+	}
+	return recorder.RecordEvents(ctx, events...)
+}
+
+// isNilRecorder also catches a typed nil pointer stored in the interface.
+func isNilRecorder(recorder Recorder) bool {
+	if recorder == nil {
+		return true
+	}
+	value := reflect.ValueOf(recorder)
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Interface, reflect.Chan:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
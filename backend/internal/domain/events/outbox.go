@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// PendingEvent is an outbox event claimed for delivery together with the
+// retry state needed to decide what happens after a failure.
+type PendingEvent struct {
+	Event
+	Attempts  int
+	Delivered []string
+}
+
+// DeliveredTo reports whether subscriber already handled the event on an
+// earlier attempt.
+func (p PendingEvent) DeliveredTo(subscriber string) bool {
+	for _, name := range p.Delivered {
+		if name == subscriber {
+			return true
+		}
+	}
+	return false
+}
+
+// OutboxStore reads and settles outbox events for the dispatcher.
+type OutboxStore interface {
+	// ClaimDueEvents leases up to limit pending events whose next attempt is
+	// due, hiding them from other dispatchers until the lease expires.
+	ClaimDueEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]PendingEvent, error)
+	MarkSubscriberDelivered(ctx context.Context, eventID int64, subscriber string, at time.Time) error
+	MarkEventDelivered(ctx context.Context, eventID int64, attempts int, at time.Time) error
+	ScheduleEventRetry(ctx context.Context, eventID int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkEventDead(ctx context.Context, eventID int64, attempts int, lastError string) error
+}
+
+// Filter narrows an event log read. MarketGroupID matches events scoped to
+// the group or to any of its answer markets; Types limits the event types.
+type Filter struct {
+	MarketID      int64
+	MarketGroupID int64
+	Types         []Type
+}
+
+// Log reads committed events in ID order, for consumers that resume from a
+// cursor such as live streams.
+type Log interface {
+	LatestEventID(ctx context.Context) (int64, error)
+	ListEventsAfter(ctx context.Context, afterID int64, filter Filter, limit int) ([]Event, error)
+}
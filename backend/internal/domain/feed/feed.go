@@ -0,0 +1,173 @@
+package feed
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dusers "socialpredict/internal/domain/users"
+)
+
+// Kind names what an activity item records.
+type Kind string
+
+const (
+	// KindTrade is a buy or a sale on a public market.
+	KindTrade Kind = "trade"
+	// KindMarketPublished is a standalone market going live.
+	KindMarketPublished Kind = "market_published"
+	// KindMarketResolved is a standalone market settling.
+	KindMarketResolved Kind = "market_resolved"
+)
+
+// Trade actions.
+const (
+	ActionBuy  = "BUY"
+	ActionSell = "SELL"
+)
+
+var (
+	// ErrInvalidInput indicates a bad username, cursor, or page size.
+	ErrInvalidInput = errors.New("invalid feed request")
+	// ErrUserNotFound indicates the user to follow does not exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrFollowingFull indicates the user already follows the maximum number
+	// of people.
+	ErrFollowingFull = errors.New("following list is full")
+)
+
+// Follow records that Follower follows Followee.
+type Follow struct {
+	ID        int64
+	Follower  string
+	Followee  string
+	CreatedAt time.Time
+}
+
+// Item is one entry in an activity feed. Username is the trader for trades
+// and the market creator for publications and resolutions. For trades Amount
+// is the credits spent on a buy or the shares sold on a sale; Resolution is
+// set only on resolutions.
+type Item struct {
+	Kind        Kind
+	ID          int64
+	Username    string
+	MarketID    int64
+	MarketTitle string
+	Action      string
+	Outcome     string
+	Amount      int64
+	Resolution  string
+	OccurredAt  time.Time
+}
+
+// Cursor marks the position of an item in feed order: newest first, then
+// by kind, then by ID, all descending.
+type Cursor struct {
+	At   time.Time
+	Kind Kind
+	ID   int64
+}
+
+// CursorFor returns the cursor that resumes a feed after item.
+func CursorFor(item Item) Cursor {
+	return Cursor{At: item.OccurredAt, Kind: item.Kind, ID: item.ID}
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c Cursor) String() string {
+	raw := fmt.Sprintf("%d:%s:%d", c.At.UnixNano(), c.Kind, c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a token produced by Cursor.String. An empty token
+// returns nil, meaning the start of the feed.
+func ParseCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return nil, ErrInvalidInput
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || id <= 0 || Kind(parts[1]).Rank() == 0 {
+		return nil, ErrInvalidInput
+	}
+	return &Cursor{At: time.Unix(0, nanos).UTC(), Kind: Kind(parts[1]), ID: id}, nil
+}
+
+// After reports whether an item of kind with at and id comes after the
+// cursor in feed order, that is, belongs on a later page.
+func (c Cursor) After(at time.Time, kind Kind, id int64) bool {
+	if !at.Equal(c.At) {
+		return at.Before(c.At)
+	}
+	if kind != c.Kind {
+		return kind.Rank() < c.Kind.Rank()
+	}
+	return id < c.ID
+}
+
+// Rank orders kinds that share a timestamp, lowest last; zero means the
+// kind is unknown.
+func (k Kind) Rank() int {
+	switch k {
+	case KindTrade:
+		return 1
+	case KindMarketPublished:
+		return 2
+	case KindMarketResolved:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Query selects one page of one kind of activity. Usernames limits the feed
+// to those users, and an empty non-nil slice matches nobody; nil means
+// everyone. After resumes past a cursor.
+type Query struct {
+	Kind      Kind
+	Usernames []string
+	After     *Cursor
+	Limit     int
+}
+
+// Page is a page of feed items. NextCursor is empty on the last page.
+type Page struct {
+	Items      []Item
+	NextCursor string
+}
+
+// Repository persists follows and reads activity from bets and markets.
+type Repository interface {
+	// AddFollow inserts the follow unless it already exists, sets its ID and
+	// CreatedAt from the stored row, and reports whether it was new.
+	AddFollow(ctx context.Context, follow *Follow) (bool, error)
+	RemoveFollow(ctx context.Context, follower, followee string) error
+	// ListFollowing returns who follower follows, most recent first.
+	ListFollowing(ctx context.Context, follower string) ([]*Follow, error)
+	CountFollowing(ctx context.Context, follower string) (int64, error)
+	CountFollowers(ctx context.Context, followee string) (int64, error)
+	// ListActivity returns up to query.Limit items of query.Kind in feed
+	// order, only on published, closed, or resolved markets.
+	ListActivity(ctx context.Context, query Query) ([]Item, error)
+}
+
+// Users looks up the accounts being followed.
+type Users interface {
+	GetUser(ctx context.Context, username string) (*dusers.User, error)
+}
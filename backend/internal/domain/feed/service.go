@@ -0,0 +1,183 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	dusers "socialpredict/internal/domain/users"
+)
+
+const (
+	// maxFollowing caps how many people one user can follow.
+	maxFollowing = 1000
+	// defaultPageSize is the feed page size when none is requested.
+	defaultPageSize = 20
+	// maxPageSize caps one feed page.
+	maxPageSize = 100
+)
+
+// kinds lists every kind of activity a feed merges.
+var kinds = []Kind{KindTrade, KindMarketPublished, KindMarketResolved}
+
+// Service lets users follow other forecasters and reads the activity feeds
+// built from their trades and markets.
+type Service struct {
+	repo  Repository
+	users Users
+	now   func() time.Time
+}
+
+// NewService constructs a feed service.
+func NewService(repo Repository, users Users, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{repo: repo, users: users, now: now}
+}
+
+// Follow makes follower follow followee. Following someone already followed
+// returns the existing follow with created false.
+func (s *Service) Follow(ctx context.Context, follower, followee string) (*Follow, bool, error) {
+	if err := s.ready(follower); err != nil {
+		return nil, false, err
+	}
+	followee = strings.TrimSpace(followee)
+	if followee == "" || followee == follower {
+		return nil, false, ErrInvalidInput
+	}
+	user, err := s.users.GetUser(ctx, followee)
+	if errors.Is(err, dusers.ErrUserNotFound) {
+		return nil, false, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	count, err := s.repo.CountFollowing(ctx, follower)
+	if err != nil {
+		return nil, false, err
+	}
+	if count >= maxFollowing {
+		// Re-following is still fine; AddFollow reports it as not created.
+		following, err := s.repo.ListFollowing(ctx, follower)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, follow := range following {
+			if follow.Followee == user.Username {
+				return follow, false, nil
+			}
+		}
+		return nil, false, ErrFollowingFull
+	}
+	follow := &Follow{Follower: follower, Followee: user.Username, CreatedAt: s.now().UTC()}
+	created, err := s.repo.AddFollow(ctx, follow)
+	if err != nil {
+		return nil, false, err
+	}
+	return follow, created, nil
+}
+
+// Unfollow stops follower following followee. Unfollowing someone not
+// followed is not an error.
+func (s *Service) Unfollow(ctx context.Context, follower, followee string) error {
+	if err := s.ready(follower); err != nil {
+		return err
+	}
+	followee = strings.TrimSpace(followee)
+	if followee == "" {
+		return ErrInvalidInput
+	}
+	return s.repo.RemoveFollow(ctx, follower, followee)
+}
+
+// Following returns who follower follows, most recent first.
+func (s *Service) Following(ctx context.Context, follower string) ([]*Follow, error) {
+	if err := s.ready(follower); err != nil {
+		return nil, err
+	}
+	return s.repo.ListFollowing(ctx, follower)
+}
+
+// FollowerCount returns how many users follow username.
+func (s *Service) FollowerCount(ctx context.Context, username string) (int64, error) {
+	if err := s.ready(username); err != nil {
+		return 0, err
+	}
+	return s.repo.CountFollowers(ctx, strings.TrimSpace(username))
+}
+
+// Feed returns a page of activity from the people username follows. An
+// empty cursor starts from the newest item and a zero limit uses the default
+// page size.
+func (s *Service) Feed(ctx context.Context, username, cursor string, limit int) (*Page, error) {
+	if err := s.ready(username); err != nil {
+		return nil, err
+	}
+	following, err := s.repo.ListFollowing(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	usernames := make([]string, 0, len(following))
+	for _, follow := range following {
+		usernames = append(usernames, follow.Followee)
+	}
+	return s.page(ctx, usernames, cursor, limit)
+}
+
+// Global returns a page of activity from everyone.
+func (s *Service) Global(ctx context.Context, cursor string, limit int) (*Page, error) {
+	if s == nil || s.repo == nil {
+		return nil, errors.New("feed service unavailable")
+	}
+	return s.page(ctx, nil, cursor, limit)
+}
+
+// page merges the newest limit items of each kind after cursor. Every item
+// on the merged page is among the newest limit of its own kind, so reading
+// one extra item per kind is enough to know whether another page follows.
+func (s *Service) page(ctx context.Context, usernames []string, cursor string, limit int) (*Page, error) {
+	after, err := ParseCursor(strings.TrimSpace(cursor))
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 || limit > maxPageSize {
+		return nil, ErrInvalidInput
+	}
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	page := &Page{Items: []Item{}}
+	if usernames != nil && len(usernames) == 0 {
+		return page, nil
+	}
+
+	for _, kind := range kinds {
+		items, err := s.repo.ListActivity(ctx, Query{Kind: kind, Usernames: usernames, After: after, Limit: limit + 1})
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, items...)
+	}
+	sort.Slice(page.Items, func(i, j int) bool {
+		next := page.Items[j]
+		return CursorFor(page.Items[i]).After(next.OccurredAt, next.Kind, next.ID)
+	})
+	if len(page.Items) > limit {
+		page.Items = page.Items[:limit]
+		page.NextCursor = CursorFor(page.Items[limit-1]).String()
+	}
+	return page, nil
+}
+
+func (s *Service) ready(username string) error {
+	if s == nil || s.repo == nil || s.users == nil {
+		return errors.New("feed service unavailable")
+	}
+	if strings.TrimSpace(username) == "" {
+		return ErrInvalidInput
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package feed_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/feed"
+	dusers "socialpredict/internal/domain/users"
+)
+
+type memoryRepo struct {
+	follows  []*feed.Follow
+	activity []feed.Item
+	queries  []feed.Query
+}
+
+func (m *memoryRepo) AddFollow(_ context.Context, follow *feed.Follow) (bool, error) {
+	for _, existing := range m.follows {
+		if existing.Follower == follow.Follower && existing.Followee == follow.Followee {
+			*follow = *existing
+			return false, nil
+		}
+	}
+	follow.ID = int64(len(m.follows) + 1)
+	m.follows = append(m.follows, follow)
+	return true, nil
+}
+
+func (m *memoryRepo) RemoveFollow(_ context.Context, follower, followee string) error {
+	kept := m.follows[:0]
+	for _, follow := range m.follows {
+		if follow.Follower != follower || follow.Followee != followee {
+			kept = append(kept, follow)
+		}
+	}
+	m.follows = kept
+	return nil
+}
+
+func (m *memoryRepo) ListFollowing(_ context.Context, follower string) ([]*feed.Follow, error) {
+	var out []*feed.Follow
+	for _, follow := range m.follows {
+		if follow.Follower == follower {
+			out = append(out, follow)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) CountFollowing(ctx context.Context, follower string) (int64, error) {
+	following, _ := m.ListFollowing(ctx, follower)
+	return int64(len(following)), nil
+}
+
+func (m *memoryRepo) CountFollowers(_ context.Context, followee string) (int64, error) {
+	var count int64
+	for _, follow := range m.follows {
+		if follow.Followee == followee {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryRepo) ListActivity(_ context.Context, query feed.Query) ([]feed.Item, error) {
+	m.queries = append(m.queries, query)
+	allowed := map[string]bool{}
+	for _, username := range query.Usernames {
+		allowed[username] = true
+	}
+	var out []feed.Item
+	for _, item := range m.activity {
+		if item.Kind != query.Kind || (query.Usernames != nil && !allowed[item.Username]) {
+			continue
+		}
+		if query.After != nil && !query.After.After(item.OccurredAt, item.Kind, item.ID) {
+			continue
+		}
+		out = append(out, item)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return feed.CursorFor(out[i]).After(out[j].OccurredAt, out[j].Kind, out[j].ID)
+	})
+	if len(out) > query.Limit {
+		out = out[:query.Limit]
+	}
+	return out, nil
+}
+
+type users map[string]bool
+
+func (u users) GetUser(_ context.Context, username string) (*dusers.User, error) {
+	if !u[username] {
+		return nil, dusers.ErrUserNotFound
+	}
+	return &dusers.User{Username: username}, nil
+}
+
+var base = time.Date(2026, 7, 4, 9, 0, 0, 0, time.UTC)
+
+func newService() (*feed.Service, *memoryRepo) {
+	repo := &memoryRepo{}
+	now := func() time.Time { return base }
+	return feed.NewService(repo, users{"alice": true, "bob": true, "carol": true}, now), repo
+}
+
+func TestFollowValidatesTheFollowee(t *testing.T) {
+	svc, repo := newService()
+	ctx := context.Background()
+
+	follow, created, err := svc.Follow(ctx, "alice", " bob ")
+	if err != nil || !created || follow.Followee != "bob" || !follow.CreatedAt.Equal(base) {
+		t.Fatalf("Follow = %+v, %v, %v", follow, created, err)
+	}
+	if again, created, err := svc.Follow(ctx, "alice", "bob"); err != nil || created || again.ID != follow.ID {
+		t.Fatalf("expected the existing follow back, got %+v, %v, %v", again, created, err)
+	}
+	if _, _, err := svc.Follow(ctx, "alice", "alice"); !errors.Is(err, feed.ErrInvalidInput) {
+		t.Fatalf("expected following yourself to be rejected, got %v", err)
+	}
+	if _, _, err := svc.Follow(ctx, "alice", "nobody"); !errors.Is(err, feed.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if count, err := svc.FollowerCount(ctx, "bob"); err != nil || count != 1 {
+		t.Fatalf("FollowerCount = %d, %v", count, err)
+	}
+	if err := svc.Unfollow(ctx, "alice", "bob"); err != nil || len(repo.follows) != 0 {
+		t.Fatalf("Unfollow = %v (%d follows left)", err, len(repo.follows))
+	}
+}
+
+func TestFeedMergesKindsAndPagesWithCursor(t *testing.T) {
+	svc, repo := newService()
+	ctx := context.Background()
+	repo.activity = []feed.Item{
+		{Kind: feed.KindTrade, ID: 1, Username: "bob", OccurredAt: base.Add(time.Hour)},
+		{Kind: feed.KindMarketPublished, ID: 7, Username: "bob", OccurredAt: base.Add(2 * time.Hour)},
+		{Kind: feed.KindTrade, ID: 2, Username: "carol", OccurredAt: base.Add(2 * time.Hour)},
+		{Kind: feed.KindMarketResolved, ID: 7, Username: "bob", OccurredAt: base.Add(3 * time.Hour)},
+		{Kind: feed.KindTrade, ID: 3, Username: "bob", OccurredAt: base.Add(2 * time.Hour)},
+	}
+
+	empty, err := svc.Feed(ctx, "alice", "", 0)
+	if err != nil || len(empty.Items) != 0 || empty.NextCursor != "" || len(repo.queries) != 0 {
+		t.Fatalf("expected an empty feed without follows, got %+v, %v", empty, err)
+	}
+
+	if _, _, err := svc.Follow(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("Follow returned error: %v", err)
+	}
+	first, err := svc.Feed(ctx, "alice", "", 2)
+	if err != nil || len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("first page = %+v, %v", first, err)
+	}
+	if first.Items[0].Kind != feed.KindMarketResolved || first.Items[1].Kind != feed.KindMarketPublished {
+		t.Fatalf("unexpected first page order %+v", first.Items)
+	}
+	second, err := svc.Feed(ctx, "alice", first.NextCursor, 2)
+	if err != nil || second.NextCursor != "" || len(second.Items) != 2 || second.Items[0].ID != 3 || second.Items[1].ID != 1 {
+		t.Fatalf("second page = %+v, %v", second, err)
+	}
+
+	global, err := svc.Global(ctx, "", 10)
+	if err != nil || len(global.Items) != 5 || global.NextCursor != "" {
+		t.Fatalf("global = %+v, %v", global, err)
+	}
+
+	if _, err := svc.Global(ctx, "not-a-cursor", 0); !errors.Is(err, feed.ErrInvalidInput) {
+		t.Fatalf("expected a bad cursor to be rejected, got %v", err)
+	}
+	if _, err := svc.Global(ctx, "", 101); !errors.Is(err, feed.ErrInvalidInput) {
+		t.Fatalf("expected an oversized page to be rejected, got %v", err)
+	}
+}
+
+func TestCursorRoundTrips(t *testing.T) {
+	cursor := feed.Cursor{At: base.Add(1500 * time.Millisecond), Kind: feed.KindMarketPublished, ID: 42}
+	parsed, err := feed.ParseCursor(cursor.String())
+	if err != nil || parsed == nil || *parsed != cursor {
+		t.Fatalf("ParseCursor = %+v, %v", parsed, err)
+	}
+	if parsed, err := feed.ParseCursor(""); err != nil || parsed != nil {
+		t.Fatalf("expected an empty cursor to mean the start, got %+v, %v", parsed, err)
+	}
+}
@@ -0,0 +1,200 @@
+package livestream
+
+import (
+	"errors"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+)
+
+// SSE event names sent to clients.
+const (
+	// EventProbability carries a market's probability after a trade or on connect.
+	EventProbability = "probability"
+	// EventTrade carries one accepted buy or sale.
+	EventTrade = "trade"
+	// EventStatus carries a lifecycle change such as approval or resolution.
+	EventStatus = "status"
+	// EventReset tells the client it fell too far behind to resume and must
+	// refetch the market before continuing with the live stream.
+	EventReset = "reset"
+)
+
+var (
+	// ErrTooManyStreams is returned when the process or client stream limit is reached.
+	ErrTooManyStreams = errors.New("too many live streams")
+	// ErrClosed is returned once the server has started shutting streams down.
+	ErrClosed = errors.New("live streams are closed")
+)
+
+var tradeEvents = []devents.Type{
+	devents.BetPlaced,
+	devents.SharesSold,
+}
+
+var statusEvents = []devents.Type{
+	devents.MarketApproved,
+	devents.MarketRejected,
+	devents.MarketResolved,
+	devents.AmendmentApproved,
+	devents.MarketGroupApproved,
+	devents.MarketGroupRejected,
+	devents.MarketGroupResolved,
+	devents.AnswerAdded,
+}
+
+// Topic selects what a stream follows: one market, or a market group and
+// every answer market inside it.
+type Topic struct {
+	MarketID      int64
+	MarketGroupID int64
+}
+
+// MarketTopic follows a single market.
+func MarketTopic(marketID int64) Topic {
+	return Topic{MarketID: marketID}
+}
+
+// GroupTopic follows a market group and its answer markets.
+func GroupTopic(groupID int64) Topic {
+	return Topic{MarketGroupID: groupID}
+}
+
+// Matches reports whether an event scoped to marketID inside groupID belongs
+// on this topic. groupID is zero for standalone markets.
+func (t Topic) Matches(marketID, groupID int64) bool {
+	if t.MarketGroupID > 0 {
+		return groupID == t.MarketGroupID
+	}
+	return t.MarketID > 0 && marketID == t.MarketID
+}
+
+// Message is one SSE frame. ID is the outbox event ID the frame was derived
+// from, which clients echo back as Last-Event-ID to resume; it is zero when
+// nothing has been published yet.
+type Message struct {
+	ID    int64
+	Event string
+	Data  any
+}
+
+// Stream is one open live subscription.
+type Stream interface {
+	// Replay returns the frames to write before any live ones: events missed
+	// since Last-Event-ID followed by the current probability snapshot.
+	Replay() []Message
+	// ReplayedThrough is the outbox cursor covered by Replay; live frames at
+	// or below it are duplicates.
+	ReplayedThrough() int64
+	// Messages delivers live frames and is closed when the stream ends.
+	Messages() <-chan Message
+	// Close releases the stream. It is safe to call more than once.
+	Close()
+}
+
+// ProbabilityPayload is the data of a probability frame.
+type ProbabilityPayload struct {
+	MarketID    int64     `json:"marketId"`
+	Probability float64   `json:"probability"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// TradePayload is the data of a trade frame.
+type TradePayload struct {
+	MarketID   int64          `json:"marketId"`
+	Type       string         `json:"type"`
+	Username   string         `json:"username"`
+	Details    map[string]any `json:"details,omitempty"`
+	OccurredAt time.Time      `json:"occurredAt"`
+}
+
+// StatusPayload is the data of a status frame.
+type StatusPayload struct {
+	Type          string         `json:"type"`
+	MarketID      int64          `json:"marketId,omitempty"`
+	MarketGroupID int64          `json:"marketGroupId,omitempty"`
+	Details       map[string]any `json:"details,omitempty"`
+	OccurredAt    time.Time      `json:"occurredAt"`
+}
+
+// ResetPayload is the data of a reset frame.
+type ResetPayload struct {
+	Reason string `json:"reason"`
+}
+
+// StreamedTypes returns every event type that produces frames.
+func StreamedTypes() []devents.Type {
+	types := make([]devents.Type, 0, len(tradeEvents)+len(statusEvents))
+	types = append(types, tradeEvents...)
+	return append(types, statusEvents...)
+}
+
+// IsTrade reports whether eventType moves a market's probability.
+func IsTrade(eventType devents.Type) bool {
+	return containsType(tradeEvents, eventType)
+}
+
+// Streamed reports whether eventType produces frames.
+func Streamed(eventType devents.Type) bool {
+	return IsTrade(eventType) || containsType(statusEvents, eventType)
+}
+
+// FramesFor converts a committed event into frames. Trades become a trade
+// frame; lifecycle changes become a status frame; other events produce none.
+func FramesFor(event devents.Event) []Message {
+	switch {
+	case IsTrade(event.Type):
+		return []Message{{
+			ID:    event.ID,
+			Event: EventTrade,
+			Data: TradePayload{
+				MarketID:   event.MarketID,
+				Type:       string(event.Type),
+				Username:   event.Username,
+				Details:    event.Data,
+				OccurredAt: event.OccurredAt.UTC(),
+			},
+		}}
+	case containsType(statusEvents, event.Type):
+		return []Message{{
+			ID:    event.ID,
+			Event: EventStatus,
+			Data: StatusPayload{
+				Type:          string(event.Type),
+				MarketID:      event.MarketID,
+				MarketGroupID: event.MarketGroupID,
+				Details:       event.Data,
+				OccurredAt:    event.OccurredAt.UTC(),
+			},
+		}}
+	default:
+		return nil
+	}
+}
+
+// ProbabilityFrame builds a probability frame tagged with cursor.
+func ProbabilityFrame(cursor, marketID int64, probability float64, at time.Time) Message {
+	return Message{
+		ID:    cursor,
+		Event: EventProbability,
+		Data: ProbabilityPayload{
+			MarketID:    marketID,
+			Probability: probability,
+			Timestamp:   at.UTC(),
+		},
+	}
+}
+
+// ResetFrame builds the frame sent when a resume gap is too large to replay.
+func ResetFrame(cursor int64) Message {
+	return Message{ID: cursor, Event: EventReset, Data: ResetPayload{Reason: "replay_window_exceeded"}}
+}
+
+func containsType(types []devents.Type, eventType devents.Type) bool {
+	for _, candidate := range types {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
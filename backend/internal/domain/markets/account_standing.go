@@ -0,0 +1,33 @@
+package markets
+
+import (
+	"context"
+	"errors"
+
+	users "socialpredict/internal/domain/users"
+)
+
+// AccountStandingChecker is implemented by user services that enforce admin
+// suspensions and bans. Market creation and answer proposals consult it when
+// the configured user service provides it.
+type AccountStandingChecker interface {
+	EnsureAccountCanAct(ctx context.Context, username string) error
+}
+
+func (s *Service) ensureAccountCanAct(ctx context.Context, username string) error {
+	checker, ok := s.userService.(AccountStandingChecker)
+	if !ok {
+		return nil
+	}
+	err := checker.EnsureAccountCanAct(ctx, username)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, users.ErrAccountSuspended), errors.Is(err, users.ErrAccountBanned):
+		return ErrAccountSuspended
+	case errors.Is(err, users.ErrUserNotFound):
+		return ErrUserNotFound
+	default:
+		return err
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	devents "socialpredict/internal/domain/events"
 	users "socialpredict/internal/domain/users"
 )
 
@@ -200,11 +201,22 @@ func (s *Service) ReviewMarketDescriptionAmendment(ctx context.Context, amendmen
 	if status != DescriptionAmendmentStatusApproved && status != DescriptionAmendmentStatusRejected {
 		return nil, ErrInvalidInput
 	}
-	repo, err := s.descriptionAmendmentRepository()
+	var reviewed *MarketDescriptionAmendment
+	err := s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.descriptionAmendmentRepository()
+		if err != nil {
+			return err
+		}
+		reviewed, err = repo.ReviewMarketDescriptionAmendment(txCtx, amendmentID, status, actorUsername, reason, tx.clock.Now())
+		if err != nil || reviewed == nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, descriptionAmendmentReviewedEvent(*reviewed, actorUsername))
+	})
 	if err != nil {
 		return nil, err
 	}
-	return repo.ReviewMarketDescriptionAmendment(ctx, amendmentID, status, actorUsername, reason, s.clock.Now())
+	return reviewed, nil
 }
 
 func (s *Service) ReviewGroupedMarketDescriptionAmendments(ctx context.Context, amendmentIDs []int64, status string, actorUsername string, reason string) ([]MarketDescriptionAmendment, error) {
@@ -217,11 +229,43 @@ func (s *Service) ReviewGroupedMarketDescriptionAmendments(ctx context.Context,
 	if status != DescriptionAmendmentStatusApproved && status != DescriptionAmendmentStatusRejected {
 		return nil, ErrInvalidInput
 	}
-	repo, err := s.descriptionAmendmentRepository()
+	var reviewed []MarketDescriptionAmendment
+	err := s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.descriptionAmendmentRepository()
+		if err != nil {
+			return err
+		}
+		reviewed, err = repo.ReviewGroupedMarketDescriptionAmendments(txCtx, amendmentIDs, status, actorUsername, reason, tx.clock.Now())
+		if err != nil {
+			return err
+		}
+		reviewedEvents := make([]devents.Event, 0, len(reviewed))
+		for _, amendment := range reviewed {
+			reviewedEvents = append(reviewedEvents, descriptionAmendmentReviewedEvent(amendment, actorUsername))
+		}
+		return tx.recordEvents(txCtx, reviewedEvents...)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return repo.ReviewGroupedMarketDescriptionAmendments(ctx, amendmentIDs, status, actorUsername, reason, s.clock.Now())
+	return reviewed, nil
+}
+
+func descriptionAmendmentReviewedEvent(amendment MarketDescriptionAmendment, actorUsername string) devents.Event {
+	eventType := devents.AmendmentRejected
+	if NormalizeDescriptionAmendmentStatus(amendment.Status) == DescriptionAmendmentStatusApproved {
+		eventType = devents.AmendmentApproved
+	}
+	return devents.Event{
+		Type:     eventType,
+		MarketID: amendment.MarketID,
+		Username: actorUsername,
+		Data: map[string]any{
+			"amendmentId": amendment.ID,
+			"version":     amendment.Version,
+			"createdBy":   amendment.CreatedBy,
+		},
+	}
 }
 
 func (s *Service) GetMarketGovernanceSettings(ctx context.Context) (*MarketGovernanceSettings, error) {
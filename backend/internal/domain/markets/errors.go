@@ -44,6 +44,8 @@ var (
 	ErrInsufficientBalance MarketError = newDomainError("insufficient balance")
 	// ErrUnauthorized indicates that the actor is not allowed to perform the action.
 	ErrUnauthorized MarketError = newDomainError("unauthorized")
+	// ErrAccountSuspended indicates that an admin has suspended or banned the actor's account.
+	ErrAccountSuspended MarketError = newDomainError("account suspended")
 	// ErrInvalidInput indicates that one or more request inputs are invalid.
 	ErrInvalidInput MarketError = newDomainError("invalid input")
 	// ErrInvalidState indicates that the market state does not allow the requested action.
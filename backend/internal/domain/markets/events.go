@@ -0,0 +1,76 @@
+package markets
+
+import (
+	"context"
+
+	devents "socialpredict/internal/domain/events"
+)
+
+// inUnitOfWork runs fn with a service whose repository and user service are
+// scoped to one GroupedMarketTransaction, so state changes, balance updates
+// and the outbox events recorded through tx commit or roll back together.
+// Repositories without a unit of work run fn on s directly.
+func (s *Service) inUnitOfWork(ctx context.Context, fn func(txCtx context.Context, tx *Service) error) error {
+	uow, ok := s.groupedMarketUnitOfWork()
+	if !ok {
+		return fn(ctx, s)
+	}
+	if err := uow.GroupedMarketTransaction(ctx, func(txCtx context.Context, repo Repository, users UserService) error {
+		return fn(txCtx, s.withTransactionDependencies(repo, users))
+	}); err != nil {
+		return err
+	}
+	s.notifyEvents()
+	return nil
+}
+
+// notifyEvents wakes the event dispatcher once recorded events are durable.
+// Transaction-scoped clones leave that to the call that commits.
+func (s *Service) notifyEvents() {
+	if s == nil || s.inTransaction || s.eventNotifier == nil {
+		return
+	}
+	s.eventNotifier.Notify()
+}
+
+// recordEvents appends domain events through the market repository. Callers
+// run inside inUnitOfWork or a GroupedMarketTransaction, where s.repo is
+// transaction-scoped, so the events commit or roll back with the change.
+func (s *Service) recordEvents(ctx context.Context, events ...devents.Event) error {
+	if s == nil {
+		return nil
+	}
+	now := s.clock.Now()
+	for i := range events {
+		if events[i].OccurredAt.IsZero() {
+			events[i].OccurredAt = now
+		}
+	}
+	if err := devents.Record(ctx, s.repo, events...); err != nil {
+		return err
+	}
+	s.notifyEvents()
+	return nil
+}
+
+func marketCreatedEvent(market *Market, groupID int64) devents.Event {
+	return devents.Event{
+		Type:          devents.MarketCreated,
+		MarketID:      market.ID,
+		MarketGroupID: groupID,
+		Username:      market.CreatorUsername,
+		Data: map[string]any{
+			"questionTitle":   market.QuestionTitle,
+			"lifecycleStatus": market.LifecycleStatus,
+		},
+	}
+}
+
+func marketGroupEvent(eventType devents.Type, groupID int64, username string, data map[string]any) devents.Event {
+	return devents.Event{
+		Type:          eventType,
+		MarketGroupID: groupID,
+		Username:      username,
+		Data:          data,
+	}
+}
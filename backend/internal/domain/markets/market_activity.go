@@ -0,0 +1,38 @@
+package markets
+
+import (
+	"context"
+	"time"
+)
+
+const maxMostTradedMarkets = 50
+
+// MarketActivity summarizes trading in one open market over a window.
+type MarketActivity struct {
+	MarketID      int64
+	QuestionTitle string
+	Trades        int64
+	Volume        int64
+}
+
+// MarketActivityRepository is implemented by repositories that can rank open
+// markets by recent trading.
+type MarketActivityRepository interface {
+	ListMostTradedMarkets(ctx context.Context, since time.Time, limit int) ([]MarketActivity, error)
+}
+
+// ListMostTradedMarkets returns open public markets ordered by the number of
+// trades placed since the given time, busiest first.
+func (s *Service) ListMostTradedMarkets(ctx context.Context, since time.Time, limit int) ([]MarketActivity, error) {
+	if limit <= 0 || limit > maxMostTradedMarkets {
+		return nil, ErrInvalidInput
+	}
+	if s == nil || s.repo == nil {
+		return nil, ErrInvalidState
+	}
+	repo, ok := s.repo.(MarketActivityRepository)
+	if !ok {
+		return nil, ErrInvalidState
+	}
+	return repo.ListMostTradedMarkets(ctx, since, limit)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	devents "socialpredict/internal/domain/events"
 	users "socialpredict/internal/domain/users"
 )
 
@@ -39,13 +40,19 @@ func (s *Service) ApproveProposedMarket(ctx context.Context, marketID int64, act
 	market.ApprovedBy = actorUsername
 	market.ApprovedAt = &now
 
-	repo, err := s.approvalRepository()
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.approvalRepository()
+		if err != nil {
+			return err
+		}
+		if err := repo.ApproveMarket(txCtx, marketID, actorUsername, now); err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, devents.Event{Type: devents.MarketApproved, MarketID: marketID, Username: actorUsername})
+	})
 	if err != nil {
 		return nil, err
 	}
-	if err := repo.ApproveMarket(ctx, marketID, actorUsername, now); err != nil {
-		return nil, err
-	}
 	return market, nil
 }
 
@@ -70,22 +77,33 @@ func (s *Service) RejectProposedMarket(ctx context.Context, marketID int64, acto
 	market.RejectedAt = &now
 	market.RejectionReason = strings.TrimSpace(reason)
 
-	repo, err := s.approvalRepository()
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.approvalRepository()
+		if err != nil {
+			return err
+		}
+		if err := repo.RejectMarket(txCtx, marketID, actorUsername, now, market.RejectionReason); err != nil {
+			return err
+		}
+		refundAmount := market.ProposalCost
+		if refundAmount == 0 {
+			refundAmount = tx.config.CreateMarketCost
+		}
+		if refundAmount > 0 && tx.userService != nil {
+			if err := tx.userService.ApplyTransaction(txCtx, market.CreatorUsername, refundAmount, users.TransactionRefund); err != nil {
+				return err
+			}
+		}
+		return tx.recordEvents(txCtx, devents.Event{
+			Type:     devents.MarketRejected,
+			MarketID: marketID,
+			Username: actorUsername,
+			Data:     map[string]any{"reason": market.RejectionReason},
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
-	if err := repo.RejectMarket(ctx, marketID, actorUsername, now, market.RejectionReason); err != nil {
-		return nil, err
-	}
-	refundAmount := market.ProposalCost
-	if refundAmount == 0 {
-		refundAmount = s.config.CreateMarketCost
-	}
-	if refundAmount > 0 && s.userService != nil {
-		if err := s.userService.ApplyTransaction(ctx, market.CreatorUsername, refundAmount, users.TransactionRefund); err != nil {
-			return nil, err
-		}
-	}
 	return market, nil
 }
 
@@ -98,8 +116,7 @@ func (s *Service) ApproveProposedMarketGroup(ctx context.Context, groupID int64,
 	if err != nil {
 		return nil, err
 	}
-	groupWriteRepo, err := s.marketGroupApprovalRepository()
-	if err != nil {
+	if _, err := s.marketGroupApprovalRepository(); err != nil {
 		return nil, err
 	}
 	group, err := groupReadRepo.GetMarketGroup(ctx, groupID)
@@ -111,7 +128,17 @@ func (s *Service) ApproveProposedMarketGroup(ctx context.Context, groupID int64,
 	}
 
 	now := s.clock.Now()
-	if err := groupWriteRepo.ApproveMarketGroup(ctx, groupID, actorUsername, now); err != nil {
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		groupWriteRepo, err := tx.marketGroupApprovalRepository()
+		if err != nil {
+			return err
+		}
+		if err := groupWriteRepo.ApproveMarketGroup(txCtx, groupID, actorUsername, now); err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, marketGroupEvent(devents.MarketGroupApproved, groupID, actorUsername, nil))
+	})
+	if err != nil {
 		return nil, err
 	}
 	group.LifecycleStatus = MarketLifecyclePublished
@@ -133,8 +160,7 @@ func (s *Service) RejectProposedMarketGroup(ctx context.Context, groupID int64,
 	if err != nil {
 		return nil, err
 	}
-	groupWriteRepo, err := s.marketGroupApprovalRepository()
-	if err != nil {
+	if _, err := s.marketGroupApprovalRepository(); err != nil {
 		return nil, err
 	}
 	group, err := groupReadRepo.GetMarketGroup(ctx, groupID)
@@ -146,17 +172,27 @@ func (s *Service) RejectProposedMarketGroup(ctx context.Context, groupID int64,
 	}
 
 	now := s.clock.Now()
-	if err := groupWriteRepo.RejectMarketGroup(ctx, groupID, actorUsername, now, rejectionReason); err != nil {
-		return nil, err
-	}
-	refundAmount := group.ProposalCost
-	if refundAmount == 0 {
-		refundAmount = s.config.CreateMarketCost
-	}
-	if refundAmount > 0 && s.userService != nil {
-		if err := s.userService.ApplyTransaction(ctx, group.CreatorUsername, refundAmount, users.TransactionRefund); err != nil {
-			return nil, err
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		groupWriteRepo, err := tx.marketGroupApprovalRepository()
+		if err != nil {
+			return err
+		}
+		if err := groupWriteRepo.RejectMarketGroup(txCtx, groupID, actorUsername, now, rejectionReason); err != nil {
+			return err
+		}
+		refundAmount := group.ProposalCost
+		if refundAmount == 0 {
+			refundAmount = tx.config.CreateMarketCost
 		}
+		if refundAmount > 0 && tx.userService != nil {
+			if err := tx.userService.ApplyTransaction(txCtx, group.CreatorUsername, refundAmount, users.TransactionRefund); err != nil {
+				return err
+			}
+		}
+		return tx.recordEvents(txCtx, marketGroupEvent(devents.MarketGroupRejected, groupID, actorUsername, map[string]any{"reason": rejectionReason}))
+	})
+	if err != nil {
+		return nil, err
 	}
 	group.LifecycleStatus = MarketLifecycleRejected
 	group.RejectedBy = actorUsername
@@ -0,0 +1,159 @@
+package markets
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// GetMarketPositionsAsOf returns every user's position in the market as it
+// stood at asOf, replaying only bets placed at or before it. A market
+// resolved after asOf is treated as still open.
+func (s *Service) GetMarketPositionsAsOf(ctx context.Context, marketID int64, asOf time.Time) (MarketPositions, error) {
+	market, bets, err := s.marketAsOf(ctx, marketID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := s.positionCalculator.CalculateMarketPositions(marketSnapshotFromModel(market), ToBoundaryBets(bets))
+	if err != nil {
+		return nil, err
+	}
+	out := make(MarketPositions, 0, len(positions))
+	for _, pos := range positions {
+		out = append(out, &UserPosition{
+			Username:         pos.Username,
+			MarketID:         marketID,
+			YesSharesOwned:   pos.YesSharesOwned,
+			NoSharesOwned:    pos.NoSharesOwned,
+			Value:            pos.Value,
+			TotalSpent:       pos.TotalSpent,
+			TotalSpentInPlay: pos.TotalSpentInPlay,
+			IsResolved:       pos.IsResolved,
+			ResolutionResult: pos.ResolutionResult,
+		})
+	}
+	return out, nil
+}
+
+// GetMarketPositionsPageAsOf returns a display page of the positions held at
+// asOf, ordered like GetMarketPositionsPage.
+func (s *Service) GetMarketPositionsPageAsOf(ctx context.Context, marketID int64, asOf time.Time, p Page) (MarketPositions, error) {
+	positions, err := s.GetMarketPositionsAsOf(ctx, marketID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	positions = activeMarketPositions(positions)
+	sortMarketPositionsByTotalShares(positions)
+	p = s.statusPolicy.NormalizePage(p, 20, 100)
+	return paginateMarketPositions(positions, p), nil
+}
+
+// GetUserPositionInMarketAsOf returns a single user's position as it stood at
+// asOf. A user with no bets by then gets an empty position.
+func (s *Service) GetUserPositionInMarketAsOf(ctx context.Context, marketID int64, username string, asOf time.Time) (*UserPosition, error) {
+	if strings.TrimSpace(username) == "" {
+		return nil, ErrInvalidInput
+	}
+	positions, err := s.GetMarketPositionsAsOf(ctx, marketID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	for _, position := range positions {
+		if position.Username == username {
+			return position, nil
+		}
+	}
+	return &UserPosition{Username: username, MarketID: marketID}, nil
+}
+
+// GetMarketLeaderboardAsOf returns the market leaderboard as it stood at
+// asOf.
+func (s *Service) GetMarketLeaderboardAsOf(ctx context.Context, marketID int64, asOf time.Time, p Page) ([]*LeaderboardRow, error) {
+	market, bets, err := s.marketAsOf(ctx, marketID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	p = s.statusPolicy.NormalizePage(p, 100, 1000)
+	if len(bets) == 0 {
+		return []*LeaderboardRow{}, nil
+	}
+	profitability, err := s.leaderboardCalculator.Calculate(marketSnapshotFromModel(market), ToBoundaryBets(bets))
+	if err != nil {
+		return nil, err
+	}
+	return paginateLeaderboardRows(mapLeaderboardRows(profitability), p), nil
+}
+
+// GetMarketDetailsAsOf returns the market overview as it stood at asOf: the
+// probability track, volume and trader count up to then, and only the
+// description amendments approved by then.
+func (s *Service) GetMarketDetailsAsOf(ctx context.Context, marketID int64, asOf time.Time) (*MarketOverview, error) {
+	market, bets, err := s.marketAsOf(ctx, marketID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	accounting := NewMarketAccountingSnapshotCalculator(s.probabilityEngine, s.metricsCalculator, s.clock).
+		Calculate(market, ToBoundaryBets(bets))
+
+	var amendments []MarketDescriptionAmendment
+	for _, amendment := range s.approvedDescriptionAmendments(ctx, marketID) {
+		if amendment.ApprovedAt != nil && !amendment.ApprovedAt.After(asOf) {
+			amendments = append(amendments, amendment)
+		}
+	}
+	if amendments == nil {
+		amendments = []MarketDescriptionAmendment{}
+	}
+
+	return &MarketOverview{
+		Market:                market,
+		Creator:               s.buildCreatorSummary(ctx, market.CreatorUsername),
+		ProbabilityChanges:    accounting.ProbabilityChanges,
+		LastProbability:       accounting.LastProbability,
+		NumUsers:              accounting.UserCount,
+		TotalVolume:           accounting.VolumeWithDust,
+		MarketDust:            accounting.MarketDust,
+		DescriptionAmendments: amendments,
+	}, nil
+}
+
+// marketAsOf loads the market as it stood at asOf together with the bets
+// placed at or before it. asOf must not precede the market's creation.
+func (s *Service) marketAsOf(ctx context.Context, marketID int64, asOf time.Time) (*Market, []*Bet, error) {
+	if marketID <= 0 || asOf.IsZero() {
+		return nil, nil, ErrInvalidInput
+	}
+	market, err := s.repo.GetByID(ctx, marketID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if market == nil {
+		return nil, nil, ErrMarketNotFound
+	}
+	if asOf.Before(market.CreatedAt) {
+		return nil, nil, ErrInvalidInput
+	}
+	bets, err := s.repo.ListBetsForMarket(ctx, marketID)
+	if err != nil {
+		return nil, nil, err
+	}
+	placed := make([]*Bet, 0, len(bets))
+	for _, bet := range bets {
+		if bet != nil && !bet.PlacedAt.After(asOf) {
+			placed = append(placed, bet)
+		}
+	}
+
+	historical := *market
+	if market.IsResolved() && market.FinalResolutionDateTime.After(asOf) {
+		historical.ResolutionResult = ""
+		historical.FinalResolutionDateTime = time.Time{}
+		historical.Status = MarketStatusActive
+		historical.LifecycleStatus = MarketLifecyclePublished
+		if !asOf.Before(market.ResolutionDateTime) {
+			historical.Status = MarketStatusClosed
+			historical.LifecycleStatus = MarketLifecycleClosed
+		}
+	}
+	return &historical, placed, nil
+}
@@ -0,0 +1,124 @@
+package markets_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	markets "socialpredict/internal/domain/markets"
+	positionsmath "socialpredict/internal/domain/math/positions"
+	"socialpredict/models"
+)
+
+func TestGetMarketPositionsAsOfReplaysOnlyEarlierBets(t *testing.T) {
+	fixture := seedMarketDetailsFixture(t)
+	asOf := fixture.market.CreatedAt.Add(2 * time.Minute)
+
+	positions, err := fixture.service.GetMarketPositionsAsOf(context.Background(), fixture.market.ID, asOf)
+	if err != nil {
+		t.Fatalf("GetMarketPositionsAsOf: %v", err)
+	}
+
+	snapshot := positionsmath.MarketSnapshot{ID: fixture.market.ID, CreatedAt: fixture.market.CreatedAt}
+	want, err := positionsmath.CalculateMarketPositions_WPAM_DBPM(snapshot, fixture.bets[:2])
+	if err != nil {
+		t.Fatalf("expected positions: %v", err)
+	}
+	if len(positions) != len(want) {
+		t.Fatalf("positions = %d, want %d", len(positions), len(want))
+	}
+	byUser := make(map[string]*markets.UserPosition, len(positions))
+	for _, position := range positions {
+		byUser[position.Username] = position
+	}
+	for _, expected := range want {
+		got := byUser[expected.Username]
+		if got == nil || got.YesSharesOwned != expected.YesSharesOwned || got.NoSharesOwned != expected.NoSharesOwned ||
+			got.Value != expected.Value || got.TotalSpent != expected.TotalSpent {
+			t.Fatalf("position %s = %+v, want %+v", expected.Username, got, expected)
+		}
+	}
+
+	alice, err := fixture.service.GetUserPositionInMarketAsOf(context.Background(), fixture.market.ID, "alice", asOf)
+	if err != nil {
+		t.Fatalf("GetUserPositionInMarketAsOf: %v", err)
+	}
+	if alice.TotalSpent != 150 {
+		t.Fatalf("alice total spent = %d, want 150 before her sale", alice.TotalSpent)
+	}
+
+	nobody, err := fixture.service.GetUserPositionInMarketAsOf(context.Background(), fixture.market.ID, "carol", asOf)
+	if err != nil {
+		t.Fatalf("GetUserPositionInMarketAsOf carol: %v", err)
+	}
+	if nobody.Username != "carol" || nobody.TotalSpent != 0 || nobody.YesSharesOwned != 0 {
+		t.Fatalf("carol position = %+v, want empty", nobody)
+	}
+}
+
+func TestGetMarketDetailsAsOfUsesHistoricalStateAndBets(t *testing.T) {
+	fixture := seedMarketDetailsFixture(t)
+	asOf := fixture.market.CreatedAt.Add(150 * time.Second)
+
+	overview, err := fixture.service.GetMarketDetailsAsOf(context.Background(), fixture.market.ID, asOf)
+	if err != nil {
+		t.Fatalf("GetMarketDetailsAsOf: %v", err)
+	}
+	assertMarketDetailMetrics(t, overview, fixture.market, fixture.bets[:2], fixture.calculator)
+	if overview.TotalVolume != 240 {
+		t.Fatalf("total volume = %d, want 240 before alice's sale", overview.TotalVolume)
+	}
+}
+
+func TestMarketAsOfTreatsLaterResolutionAsOpen(t *testing.T) {
+	fixture := seedMarketDetailsFixture(t)
+	service, db := fixture.service, fixture.db
+
+	resolvedAt := fixture.market.CreatedAt.Add(10 * time.Minute)
+	if err := db.Model(&models.Market{}).Where("id = ?", fixture.market.ID).Updates(map[string]any{
+		"is_resolved":                true,
+		"resolution_result":          "YES",
+		"lifecycle_status":           markets.MarketLifecycleResolved,
+		"final_resolution_date_time": resolvedAt,
+	}).Error; err != nil {
+		t.Fatalf("resolve market: %v", err)
+	}
+
+	before, err := service.GetMarketDetailsAsOf(context.Background(), fixture.market.ID, resolvedAt.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("GetMarketDetailsAsOf before resolution: %v", err)
+	}
+	if before.Market.IsResolved() || before.Market.ResolutionResult != "" {
+		t.Fatalf("market before resolution = %+v, want unresolved", before.Market)
+	}
+
+	after, err := service.GetMarketDetailsAsOf(context.Background(), fixture.market.ID, resolvedAt)
+	if err != nil {
+		t.Fatalf("GetMarketDetailsAsOf at resolution: %v", err)
+	}
+	if !after.Market.IsResolved() || after.Market.ResolutionResult != "YES" {
+		t.Fatalf("market at resolution = %+v, want resolved YES", after.Market)
+	}
+
+	rows, err := service.GetMarketLeaderboardAsOf(context.Background(), fixture.market.ID, resolvedAt.Add(-time.Minute), markets.Page{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetMarketLeaderboardAsOf: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("leaderboard rows = %d, want 2", len(rows))
+	}
+}
+
+func TestMarketAsOfRejectsTimesBeforeCreation(t *testing.T) {
+	fixture := seedMarketDetailsFixture(t)
+
+	_, err := fixture.service.GetMarketPositionsAsOf(context.Background(), fixture.market.ID, fixture.market.CreatedAt.Add(-time.Second))
+	if !errors.Is(err, markets.ErrInvalidInput) {
+		t.Fatalf("err = %v, want ErrInvalidInput", err)
+	}
+	_, err = fixture.service.GetMarketLeaderboardAsOf(context.Background(), fixture.market.ID, time.Time{}, markets.Page{})
+	if !errors.Is(err, markets.ErrInvalidInput) {
+		t.Fatalf("zero asOf err = %v, want ErrInvalidInput", err)
+	}
+}
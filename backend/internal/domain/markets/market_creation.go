@@ -28,6 +28,9 @@ func (s *Service) CreateMarket(ctx context.Context, req MarketCreateRequest, cre
 	if err := s.userService.ValidateUserExists(ctx, creatorUsername); err != nil {
 		return nil, ErrUserNotFound
 	}
+	if err := s.ensureAccountCanAct(ctx, creatorUsername); err != nil {
+		return nil, err
+	}
 
 	if err := s.creationPolicy.ValidateResolutionTime(s.clock.Now(), req.ResolutionDateTime, s.config.MinimumFutureHours); err != nil {
 		return nil, err
@@ -39,15 +42,19 @@ func (s *Service) CreateMarket(ctx context.Context, req MarketCreateRequest, cre
 		return nil, err
 	}
 
-	if err := s.creationPolicy.EnsureCreateMarketBalance(ctx, s.userService, creatorUsername, s.config.CreateMarketCost, s.config.MaximumDebtAllowed); err != nil {
-		return nil, err
-	}
-
-	if err := s.repo.Create(ctx, market); err != nil {
-		return nil, err
-	}
-
-	if err := s.assignTagsToMarket(ctx, market, tagSlugs, creatorUsername); err != nil {
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		if err := tx.creationPolicy.EnsureCreateMarketBalance(txCtx, tx.userService, creatorUsername, tx.config.CreateMarketCost, tx.config.MaximumDebtAllowed); err != nil {
+			return err
+		}
+		if err := tx.repo.Create(txCtx, market); err != nil {
+			return err
+		}
+		if err := tx.assignTagsToMarket(txCtx, market, tagSlugs, creatorUsername); err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, marketCreatedEvent(market, 0))
+	})
+	if err != nil {
 		return nil, err
 	}
 
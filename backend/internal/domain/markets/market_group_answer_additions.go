@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	devents "socialpredict/internal/domain/events"
 	users "socialpredict/internal/domain/users"
 )
 
@@ -105,42 +106,58 @@ func (s *Service) ProposeMarketGroupAnswerAddition(ctx context.Context, groupID
 	if err != nil {
 		return nil, err
 	}
-	if err := s.ensureActiveModerator(ctx, actorUsername); err != nil {
+	if err := s.ensureAccountCanAct(ctx, actorUsername); err != nil {
 		return nil, err
 	}
-	group, err := s.validateGroupAllowsAnswerAddition(ctx, groupID, label, 0)
-	if err != nil {
+	if err := s.ensureActiveModerator(ctx, actorUsername); err != nil {
 		return nil, err
 	}
-	repo, err := s.marketGroupAnswerAdditionRepository()
+	group, err := s.validateGroupAllowsAnswerAddition(ctx, groupID, label, 0)
 	if err != nil {
 		return nil, err
 	}
+	policy := s.marketGroupAnswerAdditionApprovalPolicy(ctx)
 
-	now := s.clock.Now()
-	addition, err := repo.CreateMarketGroupAnswerAddition(ctx, MarketGroupAnswerAddition{
-		GroupID:      group.ID,
-		GroupTitle:   group.QuestionTitle,
-		AnswerLabel:  label,
-		Status:       MarketGroupAnswerAdditionStatusPending,
-		ProposedBy:   actorUsername,
-		AdditionCost: s.marketGroupAddAnswerCost(),
-		CreatedAt:    now,
-		UpdatedAt:    now,
+	var proposed *MarketGroupAnswerAddition
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.marketGroupAnswerAdditionRepository()
+		if err != nil {
+			return err
+		}
+		now := tx.clock.Now()
+		addition, err := repo.CreateMarketGroupAnswerAddition(txCtx, MarketGroupAnswerAddition{
+			GroupID:      group.ID,
+			GroupTitle:   group.QuestionTitle,
+			AnswerLabel:  label,
+			Status:       MarketGroupAnswerAdditionStatusPending,
+			ProposedBy:   actorUsername,
+			AdditionCost: tx.marketGroupAddAnswerCost(),
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if group.StewardedBy(actorUsername) {
+			proposed, err = tx.approveMarketGroupAnswerAddition(txCtx, addition.ID, actorUsername, true)
+			return err
+		}
+		if policy == MarketGroupAnswerAdditionApprovalPolicyAuto ||
+			(policy == MarketGroupAnswerAdditionApprovalPolicyModerator && group.AutoApproveAnswerAdditions) {
+			proposed, err = tx.approveMarketGroupAnswerAddition(txCtx, addition.ID, MarketGroupAnswerAdditionApprovedByAuto, true)
+			return err
+		}
+		proposed = addition
+		return tx.recordEvents(txCtx, marketGroupEvent(devents.AnswerProposed, group.ID, actorUsername, map[string]any{
+			"additionId":  addition.ID,
+			"answerLabel": addition.AnswerLabel,
+		}))
 	})
 	if err != nil {
 		return nil, err
 	}
-
-	if group.StewardedBy(actorUsername) {
-		return s.ApproveMarketGroupAnswerAddition(ctx, addition.ID, actorUsername, true)
-	}
-	policy := s.marketGroupAnswerAdditionApprovalPolicy(ctx)
-	if policy == MarketGroupAnswerAdditionApprovalPolicyAuto ||
-		(policy == MarketGroupAnswerAdditionApprovalPolicyModerator && group.AutoApproveAnswerAdditions) {
-		return s.ApproveMarketGroupAnswerAddition(ctx, addition.ID, MarketGroupAnswerAdditionApprovedByAuto, true)
-	}
-	return addition, nil
+	return proposed, nil
 }
 
 func (s *Service) ListMarketGroupAnswerAdditions(ctx context.Context, filters MarketGroupAnswerAdditionFilters) ([]MarketGroupAnswerAddition, error) {
@@ -181,19 +198,16 @@ func (s *Service) ListMarketGroupAnswerAdditionsForReviewer(ctx context.Context,
 }
 
 func (s *Service) ApproveMarketGroupAnswerAddition(ctx context.Context, additionID int64, actorUsername string, confirmed bool) (*MarketGroupAnswerAddition, error) {
-	if uow, ok := s.groupedMarketUnitOfWork(); ok {
-		var approved *MarketGroupAnswerAddition
-		err := uow.GroupedMarketTransaction(ctx, func(txCtx context.Context, repo Repository, users UserService) error {
-			var err error
-			approved, err = s.withTransactionDependencies(repo, users).approveMarketGroupAnswerAddition(txCtx, additionID, actorUsername, confirmed)
-			return err
-		})
-		if err != nil {
-			return nil, err
-		}
-		return approved, nil
+	var approved *MarketGroupAnswerAddition
+	err := s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		var err error
+		approved, err = tx.approveMarketGroupAnswerAddition(txCtx, additionID, actorUsername, confirmed)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return s.approveMarketGroupAnswerAddition(ctx, additionID, actorUsername, confirmed)
+	return approved, nil
 }
 
 func (s *Service) approveMarketGroupAnswerAddition(ctx context.Context, additionID int64, actorUsername string, confirmed bool) (*MarketGroupAnswerAddition, error) {
@@ -260,6 +274,19 @@ func (s *Service) approveMarketGroupAnswerAddition(ctx context.Context, addition
 	if err := s.createAnswerAdditionAmendments(ctx, group, addition, now, actorUsername); err != nil {
 		return nil, err
 	}
+	if err := s.recordEvents(ctx, devents.Event{
+		Type:          devents.AnswerAdded,
+		MarketID:      child.ID,
+		MarketGroupID: group.ID,
+		Username:      addition.ProposedBy,
+		Data: map[string]any{
+			"additionId":  addition.ID,
+			"answerLabel": addition.AnswerLabel,
+			"approvedBy":  actorUsername,
+		},
+	}); err != nil {
+		return nil, err
+	}
 	return reviewed, nil
 }
 
@@ -276,11 +303,26 @@ func (s *Service) RejectMarketGroupAnswerAddition(ctx context.Context, additionI
 	if additionID <= 0 || actorUsername == "" || reason == "" || len([]rune(reason)) > MaxDescriptionAmendmentReasonLength {
 		return nil, ErrInvalidInput
 	}
-	repo, err := s.marketGroupAnswerAdditionRepository()
+	var rejected *MarketGroupAnswerAddition
+	err := s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.marketGroupAnswerAdditionRepository()
+		if err != nil {
+			return err
+		}
+		rejected, err = repo.ReviewMarketGroupAnswerAddition(txCtx, additionID, MarketGroupAnswerAdditionStatusRejected, 0, actorUsername, reason, tx.clock.Now())
+		if err != nil || rejected == nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, marketGroupEvent(devents.AnswerRejected, rejected.GroupID, actorUsername, map[string]any{
+			"additionId":  rejected.ID,
+			"answerLabel": rejected.AnswerLabel,
+			"proposedBy":  rejected.ProposedBy,
+		}))
+	})
 	if err != nil {
 		return nil, err
 	}
-	return repo.ReviewMarketGroupAnswerAddition(ctx, additionID, MarketGroupAnswerAdditionStatusRejected, 0, actorUsername, reason, s.clock.Now())
+	return rejected, nil
 }
 
 func (s *Service) RejectMarketGroupAnswerAdditionForReviewer(ctx context.Context, additionID int64, actorUsername string, reason string) (*MarketGroupAnswerAddition, error) {
@@ -315,7 +357,22 @@ func (s *Service) UpdateMarketGroupAnswerAdditionSettings(ctx context.Context, g
 	if !group.ResolutionDateTime.IsZero() && !group.ResolutionDateTime.After(s.clock.Now()) {
 		return nil, ErrInvalidState
 	}
-	return groupRepo.UpdateMarketGroupAnswerAdditionAutoApproval(ctx, groupID, enabled, s.clock.Now())
+	var updated *MarketGroup
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		groupRepo, err := tx.marketGroupRepository()
+		if err != nil {
+			return err
+		}
+		updated, err = groupRepo.UpdateMarketGroupAnswerAdditionAutoApproval(txCtx, groupID, enabled, tx.clock.Now())
+		if err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, marketGroupEvent(devents.AnswerAdditionSettingsChanged, groupID, actorUsername, map[string]any{"autoApproveAnswerAdditions": enabled}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
 func (s *Service) ensureReviewerCanManageAnswerAddition(ctx context.Context, additionID int64, actorUsername string) error {
@@ -5,25 +5,27 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	devents "socialpredict/internal/domain/events"
 )
 
 // CreateMarketGroup creates a multiple-choice binary parent and normal binary
 // child markets. The parent charges one proposal cost; child proposal costs are
 // zero because they are implementation details of the group.
 func (s *Service) CreateMarketGroup(ctx context.Context, req MarketGroupCreateRequest, creatorUsername string) (*MarketGroup, error) {
-	if uow, ok := s.groupedMarketUnitOfWork(); ok {
-		var created *MarketGroup
-		err := uow.GroupedMarketTransaction(ctx, func(txCtx context.Context, repo Repository, users UserService) error {
-			var err error
-			created, err = s.withTransactionDependencies(repo, users).createMarketGroup(txCtx, req, creatorUsername)
-			return err
-		})
-		if err != nil {
-			return nil, err
-		}
-		return created, nil
+	if err := s.ensureAccountCanAct(ctx, creatorUsername); err != nil {
+		return nil, err
 	}
-	return s.createMarketGroup(ctx, req, creatorUsername)
+	var created *MarketGroup
+	err := s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		var err error
+		created, err = tx.createMarketGroup(txCtx, req, creatorUsername)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
 func (s *Service) createMarketGroup(ctx context.Context, req MarketGroupCreateRequest, creatorUsername string) (*MarketGroup, error) {
@@ -79,6 +81,7 @@ func (s *Service) createMarketGroup(ctx context.Context, req MarketGroupCreateRe
 	}
 
 	members := make([]MarketGroupMember, 0, len(req.AnswerLabels))
+	children := make([]*Market, 0, len(req.AnswerLabels))
 	for index, rawLabel := range req.AnswerLabels {
 		label := strings.TrimSpace(rawLabel)
 		child := s.creationPolicy.BuildMarketEntity(now, MarketCreateRequest{
@@ -102,6 +105,7 @@ func (s *Service) createMarketGroup(ctx context.Context, req MarketGroupCreateRe
 			return nil, err
 		}
 
+		children = append(children, child)
 		members = append(members, MarketGroupMember{
 			MarketID:     child.ID,
 			AnswerLabel:  label,
@@ -112,6 +116,17 @@ func (s *Service) createMarketGroup(ctx context.Context, req MarketGroupCreateRe
 	if err := groupRepo.CreateMarketGroup(ctx, group, members); err != nil {
 		return nil, err
 	}
+	created := make([]devents.Event, 0, len(children)+1)
+	for _, child := range children {
+		created = append(created, marketCreatedEvent(child, group.ID))
+	}
+	created = append(created, marketGroupEvent(devents.MarketGroupCreated, group.ID, creatorUsername, map[string]any{
+		"questionTitle":   group.QuestionTitle,
+		"lifecycleStatus": group.LifecycleStatus,
+	}))
+	if err := s.recordEvents(ctx, created...); err != nil {
+		return nil, err
+	}
 	return group, nil
 }
 
@@ -152,6 +167,26 @@ func (s *Service) GetMarketGroupOverview(ctx context.Context, groupID int64) (*M
 	}, nil
 }
 
+// GetMarketGroup returns a market group with its answer members, without
+// the per-answer overviews GetMarketGroupOverview computes.
+func (s *Service) GetMarketGroup(ctx context.Context, groupID int64) (*MarketGroup, error) {
+	if groupID <= 0 {
+		return nil, ErrInvalidInput
+	}
+	groupRepo, err := s.marketGroupRepository()
+	if err != nil {
+		return nil, err
+	}
+	group, err := groupRepo.GetMarketGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, ErrMarketGroupNotFound
+	}
+	return group, nil
+}
+
 // GetMarketGroupForMarket resolves a normal binary child market back to its
 // parent group for display binding. It is not used by transaction paths.
 func (s *Service) GetMarketGroupForMarket(ctx context.Context, marketID int64) (*MarketGroup, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	devents "socialpredict/internal/domain/events"
 	users "socialpredict/internal/domain/users"
 )
 
@@ -11,19 +12,16 @@ import (
 // The transaction boundary remains the child market; the parent is only marked
 // resolved after all children use the normal binary resolution path.
 func (s *Service) ResolveMarketGroup(ctx context.Context, groupID int64, req MarketGroupResolveRequest, username string) (*MarketGroup, error) {
-	if uow, ok := s.groupedMarketUnitOfWork(); ok {
-		var resolved *MarketGroup
-		err := uow.GroupedMarketTransaction(ctx, func(txCtx context.Context, repo Repository, users UserService) error {
-			var err error
-			resolved, err = s.withTransactionDependencies(repo, users).resolveMarketGroup(txCtx, groupID, req, username)
-			return err
-		})
-		if err != nil {
-			return nil, err
-		}
-		return resolved, nil
+	var resolved *MarketGroup
+	err := s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		var err error
+		resolved, err = tx.resolveMarketGroup(txCtx, groupID, req, username)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return s.resolveMarketGroup(ctx, groupID, req, username)
+	return resolved, nil
 }
 
 func (s *Service) resolveMarketGroup(ctx context.Context, groupID int64, req MarketGroupResolveRequest, username string) (*MarketGroup, error) {
@@ -71,6 +69,9 @@ func (s *Service) resolveMarketGroup(ctx context.Context, groupID int64, req Mar
 	}
 	group.LifecycleStatus = MarketLifecycleResolved
 	group.UpdatedAt = resolvedAt
+	if err := s.recordEvents(ctx, marketGroupEvent(devents.MarketGroupResolved, group.ID, username, map[string]any{"mode": strings.ToLower(strings.TrimSpace(req.Mode))})); err != nil {
+		return nil, err
+	}
 	if marketGroupResolutionPaysWorkProfit(resolutions) {
 		if err := s.applyMarketGroupWorkProfit(ctx, group, resolverUsername); err != nil {
 			return nil, err
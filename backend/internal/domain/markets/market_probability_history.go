@@ -0,0 +1,361 @@
+package markets
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/boundary"
+	"socialpredict/internal/domain/math/probabilities/wpam"
+)
+
+// CandleInterval is the bucket width of a probability history.
+type CandleInterval string
+
+const (
+	CandleInterval5m  CandleInterval = "5m"
+	CandleInterval15m CandleInterval = "15m"
+	CandleInterval1h  CandleInterval = "1h"
+	CandleInterval4h  CandleInterval = "4h"
+	CandleInterval1d  CandleInterval = "1d"
+	CandleInterval1w  CandleInterval = "1w"
+)
+
+// ParseCandleInterval accepts the interval query values; empty means 1h.
+func ParseCandleInterval(raw string) (CandleInterval, error) {
+	interval := CandleInterval(strings.ToLower(strings.TrimSpace(raw)))
+	if interval == "" {
+		return CandleInterval1h, nil
+	}
+	if interval.duration() == 0 {
+		return "", ErrInvalidInput
+	}
+	return interval, nil
+}
+
+func (i CandleInterval) duration() time.Duration {
+	switch i {
+	case CandleInterval5m:
+		return 5 * time.Minute
+	case CandleInterval15m:
+		return 15 * time.Minute
+	case CandleInterval1h:
+		return time.Hour
+	case CandleInterval4h:
+		return 4 * time.Hour
+	case CandleInterval1d:
+		return 24 * time.Hour
+	case CandleInterval1w:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Truncate returns the start of the bucket containing t, in UTC. Days start
+// at midnight UTC and weeks on Monday.
+func (i CandleInterval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case CandleInterval1d:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case CandleInterval1w:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return t.Truncate(i.duration())
+	}
+}
+
+// ProbabilityTrackState is the probability engine's state after a trade,
+// stored so a probability track can be continued without replaying earlier
+// bets.
+type ProbabilityTrackState struct {
+	Probability     float64
+	YesContribution int64
+	NoContribution  int64
+}
+
+// IncrementalProbabilityEngine is implemented by probability engines that can
+// continue a track from a stored state. Engines without it still get
+// probability history, recomputed from every bet on each request.
+type IncrementalProbabilityEngine interface {
+	InitialState() ProbabilityTrackState
+	Continue(state ProbabilityTrackState, bets []boundary.Bet) []ProbabilityTrackState
+}
+
+// ProbabilityCandle summarises one bucket of a market's probability track.
+// Open is the probability before the bucket's first trade, Volume the
+// absolute amount traded, and Traders the distinct users who traded. State
+// and CumulativeTrades are the engine state and trade count after the
+// bucket's last trade.
+type ProbabilityCandle struct {
+	BucketStart      time.Time
+	Open             float64
+	High             float64
+	Low              float64
+	Close            float64
+	Volume           int64
+	Trades           int64
+	Traders          int64
+	CumulativeTrades int64
+	State            ProbabilityTrackState
+}
+
+// ProbabilityHistory is a market's probability candles in [From, To).
+type ProbabilityHistory struct {
+	MarketID int64
+	Interval CandleInterval
+	From     time.Time
+	To       time.Time
+	Candles  []ProbabilityCandle
+}
+
+// MarketProbabilityCandleRepository persists the display-only candle cache.
+// Like MarketAccountingSnapshotRepository it is kept out of Repository so
+// transaction paths cannot depend on it.
+type MarketProbabilityCandleRepository interface {
+	// LatestMarketProbabilityCandles returns up to limit of the newest
+	// cached candles, newest first.
+	LatestMarketProbabilityCandles(ctx context.Context, marketID int64, interval CandleInterval, limit int) ([]ProbabilityCandle, error)
+	// ListMarketProbabilityCandles returns cached candles starting in
+	// [from, to), oldest first.
+	ListMarketProbabilityCandles(ctx context.Context, marketID int64, interval CandleInterval, from, to time.Time) ([]ProbabilityCandle, error)
+	// ReplaceMarketProbabilityCandles swaps every cached candle starting at or
+	// after from for candles.
+	ReplaceMarketProbabilityCandles(ctx context.Context, marketID int64, interval CandleInterval, from time.Time, candles []ProbabilityCandle) error
+	// ListBetsForMarketSince returns bets placed at or after since, in the
+	// order the probability track applies them.
+	ListBetsForMarketSince(ctx context.Context, marketID int64, since time.Time) ([]*Bet, error)
+	CountBetsForMarket(ctx context.Context, marketID int64) (int64, error)
+}
+
+// GetMarketProbabilityHistory returns the market's probability candles for
+// buckets starting in [from, to). A zero to means now and a zero from means
+// the market's creation. Buckets without trades are omitted; the previous
+// close carries over.
+//
+// Candles are cached per interval. Bets are only ever appended, so each call
+// recomputes just the newest cached bucket and any after it from the bets
+// placed since it started. If the cached trade count no longer matches the
+// market's, the cache is rebuilt from every bet.
+func (s *Service) GetMarketProbabilityHistory(ctx context.Context, marketID int64, interval CandleInterval, from, to time.Time) (*ProbabilityHistory, error) {
+	if marketID <= 0 || interval.duration() == 0 {
+		return nil, ErrInvalidInput
+	}
+	market, err := s.repo.GetByID(ctx, marketID)
+	if err != nil {
+		return nil, err
+	}
+	if market == nil {
+		return nil, ErrMarketNotFound
+	}
+	if to.IsZero() {
+		to = s.clock.Now()
+	}
+	if from.IsZero() {
+		from = market.CreatedAt
+	}
+	from, to = interval.Truncate(from), to.UTC()
+	if !to.After(from) {
+		return nil, ErrInvalidInput
+	}
+
+	history := &ProbabilityHistory{MarketID: marketID, Interval: interval, From: from, To: to}
+	candleRepo, cacheable := s.repo.(MarketProbabilityCandleRepository)
+	engine, incremental := s.probabilityEngine.(IncrementalProbabilityEngine)
+	if !cacheable || !incremental {
+		bets, err := s.repo.ListBetsForMarket(ctx, marketID)
+		if err != nil {
+			return nil, err
+		}
+		history.Candles = candlesInRange(s.rebuildProbabilityCandles(market, interval, bets), from, to)
+		return history, nil
+	}
+
+	if err := s.refreshProbabilityCandles(ctx, candleRepo, engine, market, interval); err != nil {
+		return nil, err
+	}
+	history.Candles, err = candleRepo.ListMarketProbabilityCandles(ctx, marketID, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (s *Service) refreshProbabilityCandles(ctx context.Context, repo MarketProbabilityCandleRepository, engine IncrementalProbabilityEngine, market *Market, interval CandleInterval) error {
+	latest, err := repo.LatestMarketProbabilityCandles(ctx, market.ID, interval, 2)
+	if err != nil {
+		return err
+	}
+	total, err := repo.CountBetsForMarket(ctx, market.ID)
+	if err != nil {
+		return err
+	}
+
+	// The creation bucket is always cached, so with fewer than two candles
+	// nothing before the newest one is worth keeping.
+	if len(latest) == 2 {
+		newest, previous := latest[0], latest[1]
+		bets, err := repo.ListBetsForMarketSince(ctx, market.ID, newest.BucketStart)
+		if err != nil {
+			return err
+		}
+		if previous.CumulativeTrades+int64(len(bets)) == total {
+			candles := foldProbabilityCandles(engine, interval, nil, previous.State, previous.CumulativeTrades, ToBoundaryBets(bets))
+			if len(candles) == 1 && sameProbabilityCandle(candles[0], newest) {
+				return nil
+			}
+			return repo.ReplaceMarketProbabilityCandles(ctx, market.ID, interval, newest.BucketStart, candles)
+		}
+	}
+
+	bets, err := repo.ListBetsForMarketSince(ctx, market.ID, time.Time{})
+	if err != nil {
+		return err
+	}
+	initial := engine.InitialState()
+	creation := &ProbabilityCandle{
+		BucketStart: interval.Truncate(market.CreatedAt),
+		Open:        initial.Probability,
+		High:        initial.Probability,
+		Low:         initial.Probability,
+		Close:       initial.Probability,
+		State:       initial,
+	}
+	candles := foldProbabilityCandles(engine, interval, creation, initial, 0, ToBoundaryBets(bets))
+	if len(latest) == 1 && len(candles) == 1 && sameProbabilityCandle(candles[0], latest[0]) {
+		return nil
+	}
+	return repo.ReplaceMarketProbabilityCandles(ctx, market.ID, interval, time.Time{}, candles)
+}
+
+// rebuildProbabilityCandles computes every candle from the full probability
+// track, for engines that cannot continue one.
+func (s *Service) rebuildProbabilityCandles(market *Market, interval CandleInterval, bets []*Bet) []ProbabilityCandle {
+	boundaryBets := ToBoundaryBets(bets)
+	changes := ensureProbabilityChanges(s.probabilityEngine.Calculate(market.CreatedAt, boundaryBets), market.CreatedAt)
+	engine := replayedProbabilityEngine{changes: changes}
+	initial := engine.InitialState()
+	creation := &ProbabilityCandle{
+		BucketStart: interval.Truncate(market.CreatedAt),
+		Open:        initial.Probability,
+		High:        initial.Probability,
+		Low:         initial.Probability,
+		Close:       initial.Probability,
+		State:       initial,
+	}
+	return foldProbabilityCandles(engine, interval, creation, initial, 0, boundaryBets)
+}
+
+// foldProbabilityCandles applies bets on top of state, starting a candle
+// whenever a bet falls in a new bucket. A non-nil current candle is continued
+// first.
+func foldProbabilityCandles(engine IncrementalProbabilityEngine, interval CandleInterval, current *ProbabilityCandle, state ProbabilityTrackState, trades int64, bets []boundary.Bet) []ProbabilityCandle {
+	var candles []ProbabilityCandle
+	states := engine.Continue(state, bets)
+	traders := map[string]bool{}
+	for i, bet := range bets {
+		bucket := interval.Truncate(bet.PlacedAt)
+		if current == nil || !current.BucketStart.Equal(bucket) {
+			if current != nil {
+				candles = append(candles, *current)
+			}
+			current = &ProbabilityCandle{
+				BucketStart: bucket,
+				Open:        state.Probability,
+				High:        state.Probability,
+				Low:         state.Probability,
+			}
+			traders = map[string]bool{}
+		}
+		state = states[i]
+		trades++
+		current.High = max(current.High, state.Probability)
+		current.Low = min(current.Low, state.Probability)
+		current.Close = state.Probability
+		current.Volume += absInt64(bet.Amount)
+		current.Trades++
+		if bet.Username != "" && !traders[bet.Username] {
+			traders[bet.Username] = true
+			current.Traders++
+		}
+		current.CumulativeTrades = trades
+		current.State = state
+	}
+	if current != nil {
+		candles = append(candles, *current)
+	}
+	return candles
+}
+
+func candlesInRange(candles []ProbabilityCandle, from, to time.Time) []ProbabilityCandle {
+	out := make([]ProbabilityCandle, 0, len(candles))
+	for _, candle := range candles {
+		if !candle.BucketStart.Before(from) && candle.BucketStart.Before(to) {
+			out = append(out, candle)
+		}
+	}
+	return out
+}
+
+func sameProbabilityCandle(a, b ProbabilityCandle) bool {
+	bucket := a.BucketStart.Equal(b.BucketStart)
+	a.BucketStart, b.BucketStart = time.Time{}, time.Time{}
+	return bucket && a == b
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// replayedProbabilityEngine replays a precomputed probability track, whose
+// first change is the market's initial probability.
+type replayedProbabilityEngine struct {
+	changes []ProbabilityChange
+}
+
+func (e replayedProbabilityEngine) InitialState() ProbabilityTrackState {
+	return ProbabilityTrackState{Probability: e.changes[0].Probability}
+}
+
+func (e replayedProbabilityEngine) Continue(_ ProbabilityTrackState, bets []boundary.Bet) []ProbabilityTrackState {
+	states := make([]ProbabilityTrackState, len(bets))
+	for i := range bets {
+		if i+1 < len(e.changes) {
+			states[i] = ProbabilityTrackState{Probability: e.changes[i+1].Probability}
+		}
+	}
+	return states
+}
+
+// InitialState implements IncrementalProbabilityEngine.
+func (e defaultProbabilityEngine) InitialState() ProbabilityTrackState {
+	calculator := e.ensureCalculator()
+	contributions := calculator.InitialContributions()
+	return ProbabilityTrackState{
+		Probability:     calculator.Seeds().InitialProbability,
+		YesContribution: contributions.Yes,
+		NoContribution:  contributions.No,
+	}
+}
+
+// Continue implements IncrementalProbabilityEngine.
+func (e defaultProbabilityEngine) Continue(state ProbabilityTrackState, bets []boundary.Bet) []ProbabilityTrackState {
+	calculator := e.ensureCalculator()
+	contributions := wpam.Contributions{Yes: state.YesContribution, No: state.NoContribution}
+	states := make([]ProbabilityTrackState, len(bets))
+	for i, bet := range bets {
+		var changes []wpam.ProbabilityChange
+		changes, contributions = calculator.ContinueMarketProbabilitiesWPAM(contributions, []boundary.Bet{bet})
+		states[i] = ProbabilityTrackState{
+			Probability:     changes[0].Probability,
+			YesContribution: contributions.Yes,
+			NoContribution:  contributions.No,
+		}
+	}
+	return states
+}
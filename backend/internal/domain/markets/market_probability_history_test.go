@@ -0,0 +1,130 @@
+package markets_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/boundary"
+	markets "socialpredict/internal/domain/markets"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGetMarketProbabilityHistoryBuildsAndExtendsCandles(t *testing.T) {
+	service, db, calculator := setupServiceWithDB(t)
+	ctx := context.Background()
+	created := time.Date(2026, 7, 1, 9, 10, 0, 0, time.UTC)
+	market := modelstesting.GenerateMarket(8090, "creator")
+	market.CreatedAt = created
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+	placeBets := func(bets ...models.Bet) {
+		t.Helper()
+		for i := range bets {
+			if err := db.Create(&bets[i]).Error; err != nil {
+				t.Fatalf("create bet %d: %v", i, err)
+			}
+		}
+	}
+	bet := func(amount int64, outcome, username string, at time.Time) models.Bet {
+		b := modelstesting.GenerateBet(amount, outcome, username, uint(market.ID), 0)
+		b.PlacedAt = at
+		return b
+	}
+	history := func() []markets.ProbabilityCandle {
+		t.Helper()
+		result, err := service.GetMarketProbabilityHistory(ctx, market.ID, markets.CandleInterval1h, time.Time{}, created.Add(24*time.Hour))
+		if err != nil {
+			t.Fatalf("GetMarketProbabilityHistory returned error: %v", err)
+		}
+		if !result.From.Equal(created.Truncate(time.Hour)) {
+			t.Fatalf("from = %s, want the creation hour", result.From)
+		}
+		return result.Candles
+	}
+	closeTo := func(got, want float64) bool { return math.Abs(got-want) < 1e-12 }
+
+	placeBets(
+		bet(100, "YES", "alice", created.Add(10*time.Minute)),
+		bet(50, "NO", "bob", created.Add(30*time.Minute)),
+		bet(20, "YES", "alice", created.Add(115*time.Minute)),
+	)
+	candles := history()
+	if len(candles) != 2 {
+		t.Fatalf("expected two hourly candles, got %+v", candles)
+	}
+	var all []models.Bet
+	db.Where("market_id = ?", market.ID).Order("placed_at ASC").Find(&all)
+	track := calculator.CalculateMarketProbabilitiesWPAM(created, modelBetsToBoundary(all))
+	first := candles[0]
+	if first.Open != track[0].Probability || !closeTo(first.Close, track[2].Probability) || first.Trades != 2 || first.Traders != 2 || first.Volume != 150 {
+		t.Fatalf("unexpected first candle %+v for track %+v", first, track)
+	}
+	if first.High != math.Max(track[0].Probability, math.Max(track[1].Probability, track[2].Probability)) {
+		t.Fatalf("high = %f, track %+v", first.High, track)
+	}
+	if second := candles[1]; second.Open != first.Close || !closeTo(second.Close, track[3].Probability) || second.CumulativeTrades != 3 {
+		t.Fatalf("unexpected second candle %+v", second)
+	}
+
+	// New bets only recompute the newest cached hour onwards.
+	placeBets(
+		bet(30, "NO", "carol", created.Add(125*time.Minute)),
+		bet(10, "YES", "bob", created.Add(4*time.Hour)),
+	)
+	candles = history()
+	if len(candles) != 3 || candles[1].Trades != 2 || candles[1].Traders != 2 || candles[2].Volume != 10 {
+		t.Fatalf("unexpected candles after more bets %+v", candles)
+	}
+	db.Where("market_id = ?", market.ID).Order("placed_at ASC").Find(&all)
+	track = calculator.CalculateMarketProbabilitiesWPAM(created, modelBetsToBoundary(all))
+	if !closeTo(candles[2].Close, track[len(track)-1].Probability) {
+		t.Fatalf("last close = %f, want %f", candles[2].Close, track[len(track)-1].Probability)
+	}
+	var cached int64
+	db.Model(&models.MarketProbabilityCandle{}).Where("market_id = ?", market.ID).Count(&cached)
+	if cached != 3 {
+		t.Fatalf("expected 3 cached candles, got %d", cached)
+	}
+
+	// A bet landing in an older bucket no longer matches the cached trade
+	// count, so the cache is rebuilt.
+	placeBets(bet(5, "YES", "dave", created.Add(40*time.Minute)))
+	candles = history()
+	if len(candles) != 3 || candles[0].Trades != 3 || candles[0].Traders != 3 || candles[2].CumulativeTrades != 6 {
+		t.Fatalf("unexpected candles after a backdated bet %+v", candles)
+	}
+}
+
+func TestGetMarketProbabilityHistoryRejectsBadInput(t *testing.T) {
+	service, db, _ := setupServiceWithDB(t)
+	market := modelstesting.GenerateMarket(8091, "creator")
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+	if _, err := markets.ParseCandleInterval("2h"); !errors.Is(err, markets.ErrInvalidInput) {
+		t.Fatalf("expected an unknown interval to be rejected, got %v", err)
+	}
+	if interval, err := markets.ParseCandleInterval(""); err != nil || interval != markets.CandleInterval1h {
+		t.Fatalf("ParseCandleInterval(\"\") = %q, %v", interval, err)
+	}
+	now := time.Now()
+	if _, err := service.GetMarketProbabilityHistory(context.Background(), market.ID, markets.CandleInterval1d, now, now.Add(-48*time.Hour)); !errors.Is(err, markets.ErrInvalidInput) {
+		t.Fatalf("expected a reversed range to be rejected, got %v", err)
+	}
+	if _, err := service.GetMarketProbabilityHistory(context.Background(), 999999, markets.CandleInterval1d, time.Time{}, now); !errors.Is(err, markets.ErrMarketNotFound) {
+		t.Fatalf("expected ErrMarketNotFound, got %v", err)
+	}
+}
+
+func modelBetsToBoundary(bets []models.Bet) []boundary.Bet {
+	out := make([]boundary.Bet, 0, len(bets))
+	for _, bet := range bets {
+		out = append(out, boundary.Bet{Username: bet.Username, MarketID: bet.MarketID, Amount: bet.Amount, Outcome: bet.Outcome, PlacedAt: bet.PlacedAt})
+	}
+	return out
+}
@@ -56,6 +56,29 @@ func (s *Service) ProjectProbability(ctx context.Context, req ProbabilityProject
 	return result, nil
 }
 
+// GetMarketProbability returns the market's latest probability point computed
+// from canonical bets, for live displays that cannot wait for a read model.
+func (s *Service) GetMarketProbability(ctx context.Context, marketID int64) (*ProbabilityPoint, error) {
+	if marketID <= 0 {
+		return nil, ErrInvalidInput
+	}
+	market, err := s.repo.GetByID(ctx, marketID)
+	if err != nil {
+		return nil, err
+	}
+	if market == nil {
+		return nil, ErrMarketNotFound
+	}
+	bets, err := s.repo.ListBetsForMarket(ctx, marketID)
+	if err != nil {
+		return nil, err
+	}
+	changes := ensureProbabilityChanges(s.probabilityEngine.Calculate(market.CreatedAt, convertToBoundaryBets(bets)), market.CreatedAt)
+	sortProbabilityChanges(changes)
+	latest := changes[len(changes)-1]
+	return &ProbabilityPoint{Probability: latest.Probability, Timestamp: latest.Timestamp}, nil
+}
+
 func normalizeOutcome(outcome string) string {
 	switch strings.ToUpper(strings.TrimSpace(outcome)) {
 	case "YES":
@@ -3,34 +3,56 @@ package markets
 import (
 	"context"
 
+	devents "socialpredict/internal/domain/events"
 	users "socialpredict/internal/domain/users"
 )
 
 // ResolveMarket resolves a market with a given outcome.
-// Resolution updates market state and user balances synchronously and remains
-// outside background execution or retry infrastructure.
+// The resolver is authorized first; market state, payouts, the steward's work
+// profit and the MarketResolved outbox event are then written in one unit of
+// work, synchronously, and remain outside background execution or retry
+// infrastructure.
 func (s *Service) ResolveMarket(ctx context.Context, marketID int64, resolution string, username string) error {
-	return s.resolveMarket(ctx, marketID, resolution, username, true)
+	if _, err := s.resolvableMarket(ctx, marketID, username); err != nil {
+		return err
+	}
+	return s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		return tx.resolveAuthorizedMarket(txCtx, marketID, resolution, username, true)
+	})
 }
 
 func (s *Service) resolveMarket(ctx context.Context, marketID int64, resolution string, username string, applyWorkProfit bool) error {
-	outcome, err := s.resolutionPolicy.NormalizeResolution(resolution)
-	if err != nil {
+	if _, err := s.resolvableMarket(ctx, marketID, username); err != nil {
 		return err
 	}
+	return s.resolveAuthorizedMarket(ctx, marketID, resolution, username, applyWorkProfit)
+}
 
+// resolvableMarket loads a market and checks username may govern it.
+func (s *Service) resolvableMarket(ctx context.Context, marketID int64, username string) (*Market, error) {
 	market, err := s.repo.GetByID(ctx, marketID)
-	if err != nil {
-		return ErrMarketNotFound
+	if err != nil || market == nil {
+		return nil, ErrMarketNotFound
 	}
-	if market == nil {
-		return ErrMarketNotFound
+	if err := s.ensureMarketGovernanceActor(ctx, market, username); err != nil {
+		return nil, err
 	}
+	return market, nil
+}
 
-	if err := s.ensureMarketGovernanceActor(ctx, market, username); err != nil {
+// resolveAuthorizedMarket resolves a market whose resolver has already been
+// authorized, re-reading it so the state checks see the current row.
+func (s *Service) resolveAuthorizedMarket(ctx context.Context, marketID int64, resolution string, username string, applyWorkProfit bool) error {
+	outcome, err := s.resolutionPolicy.NormalizeResolution(resolution)
+	if err != nil {
 		return err
 	}
 
+	market, err := s.repo.GetByID(ctx, marketID)
+	if err != nil || market == nil {
+		return ErrMarketNotFound
+	}
+
 	resolverUsername := username
 	if !market.StewardedBy(username) {
 		resolverUsername = market.CurrentStewardUsername()
@@ -42,6 +64,14 @@ func (s *Service) resolveMarket(ctx context.Context, marketID int64, resolution
 	if err := s.resolutionPolicy.Resolve(ctx, s.repo, s.userService, marketID, outcome); err != nil {
 		return err
 	}
+	if err := s.recordEvents(ctx, devents.Event{
+		Type:     devents.MarketResolved,
+		MarketID: marketID,
+		Username: username,
+		Data:     map[string]any{"resolution": outcome},
+	}); err != nil {
+		return err
+	}
 
 	if !applyWorkProfit {
 		return nil
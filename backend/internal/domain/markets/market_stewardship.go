@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/internal/domain/permissions"
 	users "socialpredict/internal/domain/users"
 )
 
@@ -53,13 +55,23 @@ func (s *Service) ReassignMarketSteward(ctx context.Context, marketID int64, new
 		return market, nil
 	}
 
-	repo, err := s.stewardshipRepository()
-	if err != nil {
-		return nil, err
-	}
-
 	now := s.clock.Now()
-	if err := repo.ReassignMarketSteward(ctx, marketID, fromSteward, newStewardUsername, actorUsername, reason, now); err != nil {
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.stewardshipRepository()
+		if err != nil {
+			return err
+		}
+		if err := repo.ReassignMarketSteward(txCtx, marketID, fromSteward, newStewardUsername, actorUsername, reason, now); err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, devents.Event{
+			Type:     devents.MarketStewardChanged,
+			MarketID: marketID,
+			Username: actorUsername,
+			Data:     map[string]any{"fromSteward": fromSteward, "toSteward": newStewardUsername},
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 	market.StewardUsername = newStewardUsername
@@ -111,12 +123,21 @@ func (s *Service) ReassignMarketGroupSteward(ctx context.Context, groupID int64,
 		return group, nil
 	}
 
-	groupStewardshipRepo, err := s.marketGroupStewardshipRepository()
-	if err != nil {
-		return nil, err
-	}
 	now := s.clock.Now()
-	if err := groupStewardshipRepo.ReassignMarketGroupSteward(ctx, groupID, fromSteward, newStewardUsername, actorUsername, reason, now); err != nil {
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		groupStewardshipRepo, err := tx.marketGroupStewardshipRepository()
+		if err != nil {
+			return err
+		}
+		if err := groupStewardshipRepo.ReassignMarketGroupSteward(txCtx, groupID, fromSteward, newStewardUsername, actorUsername, reason, now); err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, marketGroupEvent(devents.MarketGroupStewardChanged, groupID, actorUsername, map[string]any{
+			"fromSteward": fromSteward,
+			"toSteward":   newStewardUsername,
+		}))
+	})
+	if err != nil {
 		return nil, err
 	}
 	group.StewardUsername = newStewardUsername
@@ -151,11 +172,16 @@ func (s *Service) ensureMarketGovernanceActor(ctx context.Context, market *Marke
 		return ErrUnauthorized
 	}
 
-	userType := users.NormalizeUserType(actor.UserType)
-	moderatorStatus := users.NormalizeModeratorStatus(actor.UserType, string(actor.ModeratorStatus))
-	if userType == users.UserTypeAdmin {
+	canResolveAny, err := permissions.OrDefault(s.authorizer).Can(ctx, actor.PermissionSubject(), permissions.MarketsResolveAny)
+	if err != nil {
+		return err
+	}
+	if canResolveAny {
 		return nil
 	}
+
+	userType := users.NormalizeUserType(actor.UserType)
+	moderatorStatus := users.NormalizeModeratorStatus(actor.UserType, string(actor.ModeratorStatus))
 	if userType == users.UserTypeModerator && moderatorStatus == users.ModeratorStatusSuspended {
 		return ErrUnauthorized
 	}
@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	devents "socialpredict/internal/domain/events"
 )
 
 const (
@@ -70,11 +72,22 @@ func (s *Service) CreateMarketTag(ctx context.Context, req MarketTagRequest, act
 	if err != nil {
 		return nil, err
 	}
-	repo, err := s.marketTagRepository()
+	var created *MarketTag
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.marketTagRepository()
+		if err != nil {
+			return err
+		}
+		created, err = repo.CreateMarketTag(txCtx, tag)
+		if err != nil {
+			return err
+		}
+		return tx.recordTagCatalogChanged(txCtx, created, actorUsername)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return repo.CreateMarketTag(ctx, tag)
+	return created, nil
 }
 
 func (s *Service) UpdateMarketTag(ctx context.Context, slug string, req MarketTagRequest) (*MarketTag, error) {
@@ -93,11 +106,33 @@ func (s *Service) UpdateMarketTag(ctx context.Context, slug string, req MarketTa
 	req.DisplayName = strings.TrimSpace(req.DisplayName)
 	req.ColorKey = strings.TrimSpace(strings.ToLower(req.ColorKey))
 
-	repo, err := s.marketTagRepository()
+	var updated *MarketTag
+	err := s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.marketTagRepository()
+		if err != nil {
+			return err
+		}
+		updated, err = repo.UpdateMarketTag(txCtx, slug, req)
+		if err != nil {
+			return err
+		}
+		return tx.recordTagCatalogChanged(txCtx, updated, "")
+	})
 	if err != nil {
 		return nil, err
 	}
-	return repo.UpdateMarketTag(ctx, slug, req)
+	return updated, nil
+}
+
+func (s *Service) recordTagCatalogChanged(ctx context.Context, tag *MarketTag, actorUsername string) error {
+	if tag == nil {
+		return nil
+	}
+	return s.recordEvents(ctx, devents.Event{
+		Type:     devents.TagCatalogChanged,
+		Username: actorUsername,
+		Data:     map[string]any{"slug": tag.Slug},
+	})
 }
 
 func (s *Service) UpdateMarketTags(ctx context.Context, marketID int64, tagSlugs []string, actorUsername string) (*Market, error) {
@@ -120,11 +155,23 @@ func (s *Service) UpdateMarketTags(ctx context.Context, marketID int64, tagSlugs
 	if err != nil {
 		return nil, err
 	}
-	repo, err := s.marketTagRepository()
-	if err != nil {
-		return nil, err
-	}
-	tags, err := repo.SetMarketTags(ctx, marketID, normalized, actorUsername, MarketTagAssignmentSourceAdmin, s.clock.Now())
+	var tags []MarketTag
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		repo, err := tx.marketTagRepository()
+		if err != nil {
+			return err
+		}
+		tags, err = repo.SetMarketTags(txCtx, marketID, normalized, actorUsername, MarketTagAssignmentSourceAdmin, tx.clock.Now())
+		if err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, devents.Event{
+			Type:     devents.MarketTagsChanged,
+			MarketID: marketID,
+			Username: actorUsername,
+			Data:     map[string]any{"tags": normalized},
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -154,11 +201,18 @@ func (s *Service) UpdateMarketGroupTags(ctx context.Context, groupID int64, tagS
 	if err != nil {
 		return nil, err
 	}
-	tagRepo, err := s.marketTagRepository()
-	if err != nil {
-		return nil, err
-	}
-	tags, err := tagRepo.SetMarketGroupTags(ctx, groupID, normalized, actorUsername, MarketTagAssignmentSourceAdmin, s.clock.Now())
+	var tags []MarketTag
+	err = s.inUnitOfWork(ctx, func(txCtx context.Context, tx *Service) error {
+		tagRepo, err := tx.marketTagRepository()
+		if err != nil {
+			return err
+		}
+		tags, err = tagRepo.SetMarketGroupTags(txCtx, groupID, normalized, actorUsername, MarketTagAssignmentSourceAdmin, tx.clock.Now())
+		if err != nil {
+			return err
+		}
+		return tx.recordEvents(txCtx, marketGroupEvent(devents.MarketGroupTagsChanged, groupID, actorUsername, map[string]any{"tags": normalized}))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,158 @@
+package markets
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+)
+
+// List sort orders accepted by the sort query parameter.
+const (
+	ListSortNewest   = "newest"
+	ListSortTrending = "trending"
+)
+
+// Trending scores look back over TrendingWindow, and every trade, new trader,
+// probability move and comment counts half as much per TrendingHalfLife of
+// age. The weights put one new trader on a par with a ten-point probability
+// swing and four comments, with volume damped logarithmically so one whale
+// cannot outrank broad interest.
+const (
+	TrendingWindow   = 72 * time.Hour
+	TrendingHalfLife = 12 * time.Hour
+
+	trendingVolumeWeight   = 1.0
+	trendingTraderWeight   = 2.0
+	trendingMovementWeight = 20.0
+	trendingCommentWeight  = 0.5
+)
+
+// ParseListSort turns a sort query value into ListFilters.Sort. Newest
+// first is the default and maps to the empty value.
+func ParseListSort(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", ListSortNewest:
+		return "", nil
+	case ListSortTrending:
+		return ListSortTrending, nil
+	default:
+		return "", ErrInvalidInput
+	}
+}
+
+// MarketTrendingScore is a market's materialized trending score. The raw
+// figures cover the whole window; only Score is time-decayed.
+type MarketTrendingScore struct {
+	MarketID            int64
+	Score               float64
+	Volume              int64
+	NewTraders          int64
+	ProbabilityMovement float64
+	Comments            int64
+	ComputedAt          time.Time
+}
+
+// MarketTrendingActivity is what a trending score is computed from: every
+// bet on the market, in placement order, so first trades and probability
+// moves can be told apart, and the times of visible comments posted since
+// the window opened.
+type MarketTrendingActivity struct {
+	Market   *Market
+	Bets     []*Bet
+	Comments []time.Time
+}
+
+// MarketTrendingRepository persists the display-only trending scores. Like
+// MarketAccountingSnapshotRepository it is kept out of Repository so
+// transaction paths cannot depend on it.
+type MarketTrendingRepository interface {
+	// ListMarketTrendingActivity returns active markets traded or commented
+	// on at or after since.
+	ListMarketTrendingActivity(ctx context.Context, since time.Time) ([]MarketTrendingActivity, error)
+	// ReplaceMarketTrendingScores swaps every stored score for scores.
+	ReplaceMarketTrendingScores(ctx context.Context, scores []MarketTrendingScore) error
+}
+
+// RefreshTrendingScores recomputes the trending score of every market with
+// activity in the window ending at now and replaces the stored scores, so
+// markets that went quiet drop out. It returns the stored scores.
+func (s *Service) RefreshTrendingScores(ctx context.Context, now time.Time) ([]MarketTrendingScore, error) {
+	repo, ok := s.repo.(MarketTrendingRepository)
+	if !ok {
+		return nil, ErrInvalidState
+	}
+	now = now.UTC()
+	activity, err := repo.ListMarketTrendingActivity(ctx, now.Add(-TrendingWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]MarketTrendingScore, 0, len(activity))
+	for _, item := range activity {
+		if item.Market == nil {
+			continue
+		}
+		score := s.scoreMarketTrending(item, now)
+		if score.Score > 0 {
+			scores = append(scores, score)
+		}
+	}
+	if err := repo.ReplaceMarketTrendingScores(ctx, scores); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+func (s *Service) scoreMarketTrending(activity MarketTrendingActivity, now time.Time) MarketTrendingScore {
+	market := activity.Market
+	score := MarketTrendingScore{MarketID: market.ID, ComputedAt: now}
+	since := now.Add(-TrendingWindow)
+
+	changes := ensureProbabilityChanges(s.probabilityEngine.Calculate(market.CreatedAt, ToBoundaryBets(activity.Bets)), market.CreatedAt)
+	var volume, traders, movement, comments float64
+	seen := make(map[string]bool)
+	for i, bet := range activity.Bets {
+		if bet == nil {
+			continue
+		}
+		first := bet.Username != "" && !seen[bet.Username]
+		seen[bet.Username] = true
+		if bet.PlacedAt.Before(since) || bet.PlacedAt.After(now) {
+			continue
+		}
+
+		weight := trendingDecay(now.Sub(bet.PlacedAt))
+		score.Volume += absInt64(bet.Amount)
+		volume += float64(absInt64(bet.Amount)) * weight
+		if first {
+			score.NewTraders++
+			traders += weight
+		}
+		if i+1 < len(changes) {
+			move := math.Abs(changes[i+1].Probability - changes[i].Probability)
+			score.ProbabilityMovement += move
+			movement += move * weight
+		}
+	}
+	for _, at := range activity.Comments {
+		if at.Before(since) || at.After(now) {
+			continue
+		}
+		score.Comments++
+		comments += trendingDecay(now.Sub(at))
+	}
+
+	score.Score = trendingVolumeWeight*math.Log1p(volume) +
+		trendingTraderWeight*traders +
+		trendingMovementWeight*movement +
+		trendingCommentWeight*comments
+	return score
+}
+
+func trendingDecay(age time.Duration) float64 {
+	if age <= 0 {
+		return 1
+	}
+	return math.Exp2(-float64(age) / float64(TrendingHalfLife))
+}
@@ -0,0 +1,106 @@
+package markets_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	markets "socialpredict/internal/domain/markets"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestRefreshTrendingScoresRanksRecentActivity(t *testing.T) {
+	service, db, _ := setupServiceWithDB(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	createMarket := func(id int64, createdAt time.Time) {
+		t.Helper()
+		market := modelstesting.GenerateMarket(id, "creator")
+		market.CreatedAt = createdAt
+		if err := db.Create(&market).Error; err != nil {
+			t.Fatalf("create market %d: %v", id, err)
+		}
+	}
+	placeBet := func(marketID int64, username string, amount int64, outcome string, at time.Time) {
+		t.Helper()
+		bet := modelstesting.GenerateBet(amount, outcome, username, uint(marketID), 0)
+		bet.PlacedAt = at
+		if err := db.Create(&bet).Error; err != nil {
+			t.Fatalf("create bet: %v", err)
+		}
+	}
+
+	// 9201 only traded before the window, 9202 is busy now, 9203 had one
+	// trade two and a half days ago and 9204 is new but untraded.
+	createMarket(9201, now.Add(-10*24*time.Hour))
+	createMarket(9202, now.Add(-9*24*time.Hour))
+	createMarket(9203, now.Add(-8*24*time.Hour))
+	createMarket(9204, now.Add(-time.Hour))
+	placeBet(9201, "alice", 50, "YES", now.Add(-5*24*time.Hour))
+	placeBet(9202, "alice", 20, "YES", now.Add(-6*24*time.Hour))
+	placeBet(9202, "alice", 10, "YES", now.Add(-3*time.Hour))
+	placeBet(9202, "bob", 30, "NO", now.Add(-2*time.Hour))
+	placeBet(9202, "carol", 15, "YES", now.Add(-time.Hour))
+	placeBet(9203, "dave", 40, "YES", now.Add(-60*time.Hour))
+	comment := models.Comment{MarketID: 9202, Username: "bob", Body: "Close call", BodyHTML: "<p>Close call</p>", CreatedAt: now.Add(-30 * time.Minute)}
+	if err := db.Create(&comment).Error; err != nil {
+		t.Fatalf("create comment: %v", err)
+	}
+
+	scores, err := service.RefreshTrendingScores(ctx, now)
+	if err != nil {
+		t.Fatalf("RefreshTrendingScores: %v", err)
+	}
+	byID := map[int64]markets.MarketTrendingScore{}
+	for _, score := range scores {
+		byID[score.MarketID] = score
+	}
+	if len(scores) != 2 {
+		t.Fatalf("scores = %+v, want 9202 and 9203", scores)
+	}
+	hot, warm := byID[9202], byID[9203]
+	if hot.Volume != 55 || hot.NewTraders != 2 || hot.Comments != 1 || hot.ProbabilityMovement <= 0 {
+		t.Fatalf("hot score = %+v", hot)
+	}
+	if warm.Volume != 40 || warm.NewTraders != 1 || !(hot.Score > warm.Score) || warm.Score <= 0 {
+		t.Fatalf("warm score = %+v, hot = %+v", warm, hot)
+	}
+
+	listed, err := service.ListMarkets(ctx, markets.ListFilters{Status: markets.MarketStatusActive, Sort: markets.ListSortTrending, Limit: 10})
+	if err != nil {
+		t.Fatalf("ListMarkets: %v", err)
+	}
+	if got := marketIDs(listed); len(got) != 4 || got[0] != 9202 || got[1] != 9203 || got[2] != 9204 || got[3] != 9201 {
+		t.Fatalf("trending order = %v, want scored markets first then newest", got)
+	}
+
+	page, err := service.ListMarketDiscovery(ctx, markets.ListFilters{Status: markets.MarketStatusActive, Sort: markets.ListSortTrending, TrendingOnly: true, Limit: 5})
+	if err != nil {
+		t.Fatalf("ListMarketDiscovery: %v", err)
+	}
+	if page.Total != 2 || page.Rows[0].Market.ID != 9202 || page.Rows[1].Market.ID != 9203 {
+		t.Fatalf("trending section = %+v", page)
+	}
+
+	if scores, err = service.RefreshTrendingScores(ctx, now.Add(trendingQuietPeriod)); err != nil || len(scores) != 0 {
+		t.Fatalf("refresh after the window: scores=%+v err=%v", scores, err)
+	}
+	page, err = service.ListMarketDiscovery(ctx, markets.ListFilters{Status: markets.MarketStatusActive, TrendingOnly: true, Limit: 5})
+	if err != nil || page.Total != 0 {
+		t.Fatalf("expected quiet markets to drop out, page=%+v err=%v", page, err)
+	}
+}
+
+// trendingQuietPeriod is long enough for every seeded trade to leave the
+// trending window.
+const trendingQuietPeriod = markets.TrendingWindow + 24*time.Hour
+
+func marketIDs(list []*markets.Market) []int64 {
+	ids := make([]int64, 0, len(list))
+	for _, market := range list {
+		ids = append(ids, market.ID)
+	}
+	return ids
+}
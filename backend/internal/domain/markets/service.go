@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"socialpredict/internal/domain/boundary"
+	devents "socialpredict/internal/domain/events"
 	positionsmath "socialpredict/internal/domain/math/positions"
+	"socialpredict/internal/domain/permissions"
 	users "socialpredict/internal/domain/users"
 )
 
@@ -180,6 +182,12 @@ type LeaderboardCalculator interface {
 	Calculate(snapshot positionsmath.MarketSnapshot, bets []boundary.Bet) ([]positionsmath.UserProfitability, error)
 }
 
+// PositionCalculator replays bets into user positions. Point-in-time queries
+// use it on the bets placed up to the requested moment.
+type PositionCalculator interface {
+	CalculateMarketPositions(snapshot positionsmath.MarketSnapshot, bets []boundary.Bet) ([]positionsmath.MarketPosition, error)
+}
+
 // StatusPolicy validates status filters and pagination rules.
 type StatusPolicy interface {
 	ValidateStatus(status string) error
@@ -193,8 +201,12 @@ type ListFilters struct {
 	CreatedBy string
 	OwnedBy   string
 	TagSlug   string
-	Limit     int
-	Offset    int
+	// Sort is empty for newest first or ListSortTrending.
+	Sort string
+	// TrendingOnly keeps only markets with a positive trending score.
+	TrendingOnly bool
+	Limit        int
+	Offset       int
 }
 
 // SearchFilters represents filters for searching markets.
@@ -262,7 +274,13 @@ type Service struct {
 	searchPolicy          SearchPolicy
 	metricsCalculator     MetricsCalculator
 	leaderboardCalculator LeaderboardCalculator
+	positionCalculator    PositionCalculator
 	statusPolicy          StatusPolicy
+	authorizer            permissions.Authorizer
+	eventNotifier         devents.Notifier
+	// inTransaction marks a clone scoped to a unit of work, whose events
+	// are only announced once the outer call commits.
+	inTransaction bool
 }
 
 // ServiceOption configures the markets service strategies.
@@ -300,6 +318,10 @@ func defaultLeaderboardCalculatorStrategy() LeaderboardCalculator {
 	return defaultLeaderboardCalculator{}
 }
 
+func defaultPositionCalculatorStrategy() PositionCalculator {
+	return positionsmath.NewPositionCalculator()
+}
+
 func defaultStatusPolicyStrategy() StatusPolicy {
 	return defaultStatusPolicy{}
 }
@@ -360,6 +382,13 @@ func leaderboardCalculatorOrDefault(calculator LeaderboardCalculator) Leaderboar
 	return calculator
 }
 
+func positionCalculatorOrDefault(calculator PositionCalculator) PositionCalculator {
+	if calculator == nil {
+		return defaultPositionCalculatorStrategy()
+	}
+	return calculator
+}
+
 func statusPolicyOrDefault(policy StatusPolicy) StatusPolicy {
 	if policy == nil {
 		return defaultStatusPolicyStrategy()
@@ -430,6 +459,25 @@ func WithLeaderboardCalculator(c LeaderboardCalculator) ServiceOption {
 	}
 }
 
+// WithPositionCalculator overrides the position calculator.
+func WithPositionCalculator(c PositionCalculator) ServiceOption {
+	return func(s *Service) {
+		if s != nil {
+			s.positionCalculator = positionCalculatorOrDefault(c)
+		}
+	}
+}
+
+// WithEventNotifier announces committed outbox events to n, typically the
+// event dispatcher.
+func WithEventNotifier(n devents.Notifier) ServiceOption {
+	return func(s *Service) {
+		if s != nil {
+			s.eventNotifier = n
+		}
+	}
+}
+
 // WithStatusPolicy overrides the status policy.
 func WithStatusPolicy(p StatusPolicy) ServiceOption {
 	return func(s *Service) {
@@ -448,6 +496,16 @@ func WithClock(clock Clock) ServiceOption {
 	}
 }
 
+// WithAuthorizer sets how governance permissions such as markets.resolve.any
+// are resolved. Without one, only admins hold them.
+func WithAuthorizer(authorizer permissions.Authorizer) ServiceOption {
+	return func(s *Service) {
+		if s != nil {
+			s.authorizer = authorizer
+		}
+	}
+}
+
 // NewService creates a new markets service.
 func NewService(repo Repository, userService UserService, clock Clock, config Config, opts ...ServiceOption) *Service {
 	s := &Service{
@@ -479,6 +537,7 @@ func (s *Service) ensureDefaults() {
 	s.searchPolicy = searchPolicyOrDefault(s.searchPolicy)
 	s.metricsCalculator = metricsCalculatorOrDefault(s.metricsCalculator)
 	s.leaderboardCalculator = leaderboardCalculatorOrDefault(s.leaderboardCalculator)
+	s.positionCalculator = positionCalculatorOrDefault(s.positionCalculator)
 	s.statusPolicy = statusPolicyOrDefault(s.statusPolicy)
 }
 
@@ -497,6 +556,7 @@ func (s *Service) withTransactionDependencies(repo Repository, userService UserS
 	clone := *s
 	clone.repo = repo
 	clone.userService = userService
+	clone.inTransaction = true
 	return &clone
 }
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	devents "socialpredict/internal/domain/events"
 	markets "socialpredict/internal/domain/markets"
 	dusers "socialpredict/internal/domain/users"
 )
@@ -160,3 +161,23 @@ func TestReviewProposedMarketRejectsWrongStateAndMissingConfirmation(t *testing.
 		t.Fatalf("RejectProposedMarket missing reason error = %v, want ErrInvalidInput", err)
 	}
 }
+
+func TestApproveProposedMarketRecordsApprovedEvent(t *testing.T) {
+	now := marketsTestTime()
+	market := proposedMarket(79, now)
+	repo := newProjectionRepo(withProjectionRepoMarket(market), func(repo *projectionRepo) {
+		repo.approveMarketFunc = func(context.Context, int64, string, time.Time) error { return nil }
+	})
+	service := markets.NewService(repo, newNoopUserService(), newFixedClock(now), markets.Config{})
+
+	if _, err := service.ApproveProposedMarket(context.Background(), market.ID, "admin", true); err != nil {
+		t.Fatalf("ApproveProposedMarket returned error: %v", err)
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(repo.events))
+	}
+	event := repo.events[0]
+	if event.Type != devents.MarketApproved || event.MarketID != market.ID || event.Username != "admin" || !event.OccurredAt.Equal(now) {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+}
@@ -10,10 +10,13 @@ import (
 	marketmath "socialpredict/internal/domain/math/market"
 	"socialpredict/internal/domain/math/probabilities/wpam"
 	"socialpredict/models/modelstesting"
+
+	"gorm.io/gorm"
 )
 
 type marketDetailsFixture struct {
 	service    *markets.Service
+	db         *gorm.DB
 	bets       []boundary.Bet
 	market     *markets.Market
 	calculator wpam.ProbabilityCalculator
@@ -54,6 +57,7 @@ func seedMarketDetailsFixture(t *testing.T) marketDetailsFixture {
 
 	return marketDetailsFixture{
 		service: service,
+		db:      db,
 		bets:    bets,
 		market: &markets.Market{
 			ID:                 market.ID,
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"socialpredict/internal/domain/boundary"
+	devents "socialpredict/internal/domain/events"
 	markets "socialpredict/internal/domain/markets"
 	"socialpredict/internal/domain/math/probabilities/wpam"
 )
@@ -46,6 +47,8 @@ type projectionRepo struct {
 	getMarketGroupForMarketFunc                     func(context.Context, int64) (*markets.MarketGroup, error)
 	markMarketGroupResolvedFunc                     func(context.Context, int64, time.Time) error
 	updateMarketGroupAnswerAdditionAutoApprovalFunc func(context.Context, int64, bool, time.Time) (*markets.MarketGroup, error)
+
+	events []devents.Event
 }
 
 func newProjectionRepo(opts ...func(*projectionRepo)) *projectionRepo {
@@ -170,6 +173,13 @@ func withProjectionRepoBets(bets []*markets.Bet) func(*projectionRepo) {
 	}
 }
 
+// RecordEvents keeps outbox events so tests can inspect what a write
+// would have published.
+func (r *projectionRepo) RecordEvents(_ context.Context, events ...devents.Event) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
 func (r *projectionRepo) Create(ctx context.Context, market *markets.Market) error {
 	if r.createFunc == nil {
 		return errUnexpectedMarketsTestCall
@@ -476,6 +486,30 @@ func TestProjectProbability_InvalidOutcome(t *testing.T) {
 	}
 }
 
+func TestGetMarketProbability_ReturnsLatestPoint(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bets := []*markets.Bet{
+		{Username: "alice", MarketID: 55, Amount: 100, Outcome: "YES", PlacedAt: createdAt.Add(5 * time.Minute), CreatedAt: createdAt.Add(5 * time.Minute)},
+		{Username: "bob", MarketID: 55, Amount: 40, Outcome: "NO", PlacedAt: createdAt.Add(10 * time.Minute), CreatedAt: createdAt.Add(10 * time.Minute)},
+	}
+	repo := newProjectionRepo(
+		withProjectionRepoMarket(&markets.Market{ID: 55, Status: "active", CreatedAt: createdAt, ResolutionDateTime: createdAt.Add(48 * time.Hour)}),
+		withProjectionRepoBets(bets),
+	)
+	svc := markets.NewService(repo, nil, newProjectionClock(createdAt.Add(20*time.Minute)), markets.Config{})
+
+	point, err := svc.GetMarketProbability(context.Background(), 55)
+	if err != nil {
+		t.Fatalf("GetMarketProbability returned error: %v", err)
+	}
+
+	track := wpam.CalculateMarketProbabilitiesWPAM(createdAt, marketsToBoundaryBets(bets))
+	want := track[len(track)-1]
+	if absDiff(point.Probability, want.Probability) > 1e-9 || !point.Timestamp.Equal(createdAt.Add(10*time.Minute)) {
+		t.Fatalf("point = %+v, want %+v", point, want)
+	}
+}
+
 // helpers for tests
 
 func marketsToBoundaryBets(bets []*markets.Bet) []boundary.Bet {
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	devents "socialpredict/internal/domain/events"
 	markets "socialpredict/internal/domain/markets"
 	users "socialpredict/internal/domain/users"
 )
@@ -30,6 +31,8 @@ type resolveRepo struct {
 	listBetsForMarketFunc        func(context.Context, int64) ([]*markets.Bet, error)
 	calculatePayoutPositionsFunc func(context.Context, int64) ([]*markets.PayoutPosition, error)
 	getPublicMarketFunc          func(context.Context, int64) (*markets.PublicMarket, error)
+
+	events []devents.Event
 }
 
 func newResolveRepo(opts ...func(*resolveRepo)) *resolveRepo {
@@ -105,6 +108,13 @@ func withResolveRepoResolve(fn func(context.Context, int64, string) error) func(
 	}
 }
 
+// RecordEvents keeps outbox events so tests can inspect what a write
+// would have published.
+func (r *resolveRepo) RecordEvents(_ context.Context, events ...devents.Event) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
 func (r *resolveRepo) Create(ctx context.Context, market *markets.Market) error {
 	if r.createFunc == nil {
 		return errUnexpectedMarketsTestCall
@@ -129,25 +129,41 @@ func CalculateMarketProbabilitiesWPAM(marketCreatedAtTime time.Time, bets []boun
 func (c ProbabilityCalculator) CalculateMarketProbabilitiesWPAM(marketCreatedAtTime time.Time, bets []boundary.Bet) []ProbabilityChange {
 	calculator := c.withDefaults()
 	seeds := calculator.seeds.Seeds()
-	var probabilityChanges []ProbabilityChange
+	probabilityChanges := []ProbabilityChange{{Probability: seeds.InitialProbability, Timestamp: marketCreatedAtTime}}
 
-	P_initial := seeds.InitialProbability
+	// Calculate probabilities after each bet
+	changes, _ := calculator.ContinueMarketProbabilitiesWPAM(calculator.InitialContributions(), bets)
+	return append(probabilityChanges, changes...)
+}
 
-	probabilityChanges = append(probabilityChanges, ProbabilityChange{Probability: P_initial, Timestamp: marketCreatedAtTime})
+// Contributions are the running YES and NO totals a WPAM probability is
+// calculated from. Storing them lets a probability track be continued
+// without replaying earlier bets.
+type Contributions struct {
+	Yes int64
+	No  int64
+}
 
-	// Calculate probabilities after each bet
-	contributions := marketContributions{
-		yes: seeds.InitialYesContribution,
-		no:  seeds.InitialNoContribution,
-	}
+// InitialContributions returns the contributions before any bet.
+func (c ProbabilityCalculator) InitialContributions() Contributions {
+	seeds := c.withDefaults().seeds.Seeds()
+	return Contributions{Yes: seeds.InitialYesContribution, No: seeds.InitialNoContribution}
+}
+
+// ContinueMarketProbabilitiesWPAM applies bets on top of start and returns the
+// probability after each bet along with the contributions after the last.
+func (c ProbabilityCalculator) ContinueMarketProbabilitiesWPAM(start Contributions, bets []boundary.Bet) ([]ProbabilityChange, Contributions) {
+	calculator := c.withDefaults()
+	seeds := calculator.seeds.Seeds()
+	contributions := marketContributions{yes: start.Yes, no: start.No}
+	probabilityChanges := make([]ProbabilityChange, 0, len(bets))
 	for _, bet := range bets {
 		calculator.applyContribution(&contributions, bet)
 
 		newProbability := calculator.formula.Calculate(seeds, contributions.yes, contributions.no)
 		probabilityChanges = append(probabilityChanges, ProbabilityChange{Probability: newProbability, Timestamp: bet.PlacedAt})
 	}
-
-	return probabilityChanges
+	return probabilityChanges, Contributions{Yes: contributions.yes, No: contributions.no}
 }
 
 func ProjectNewProbabilityWPAM(marketCreatedAtTime time.Time, currentBets []boundary.Bet, newBet boundary.Bet) ProjectedProbability {
@@ -199,3 +199,17 @@ func TestNewProbabilityCalculatorWithOptions(t *testing.T) {
 		t.Fatalf("expected injected formula probability 0.8, got %f", changes[1].Probability)
 	}
 }
+
+func TestContinueMarketProbabilitiesWPAMMatchesFullTrack(t *testing.T) {
+	calculator := newWPAMTestCalculator()
+
+	for _, tc := range TestCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			split := len(tc.Bets) / 2
+			first, contributions := calculator.ContinueMarketProbabilitiesWPAM(calculator.InitialContributions(), tc.Bets[:split])
+			rest, _ := calculator.ContinueMarketProbabilitiesWPAM(contributions, tc.Bets[split:])
+			continued := append([]wpam.ProbabilityChange{tc.ProbabilityChanges[0]}, append(first, rest...)...)
+			assertProbabilityChangesEqual(t, continued, tc.ProbabilityChanges)
+		})
+	}
+}
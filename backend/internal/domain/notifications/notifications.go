@@ -0,0 +1,146 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	dwatchlists "socialpredict/internal/domain/watchlists"
+)
+
+// Type groups notifications for display and for per-user preferences.
+type Type string
+
+const (
+	// TypeMarketApproved tells a creator their market or market group was published.
+	TypeMarketApproved Type = "market_approved"
+	// TypeMarketRejected tells a creator their market or market group was rejected.
+	TypeMarketRejected Type = "market_rejected"
+	// TypeAmendmentReviewed tells an author their description amendment was approved or rejected.
+	TypeAmendmentReviewed Type = "amendment_reviewed"
+	// TypeAnswerReviewed tells a proposer their answer addition was approved or rejected.
+	TypeAnswerReviewed Type = "answer_reviewed"
+	// TypeMarketResolved tells a holder a market they have shares in resolved.
+	TypeMarketResolved Type = "market_resolved"
+	// TypeStewardAssigned tells a user they were made steward of a market or group.
+	TypeStewardAssigned Type = "steward_assigned"
+	// TypeReportReviewed tells a reporter a moderator reviewed their content report.
+	TypeReportReviewed Type = "report_reviewed"
+	// TypeWatchlistActivity tells a follower about news on a market, market
+	// group, or tag they follow.
+	TypeWatchlistActivity Type = "watchlist_activity"
+)
+
+var types = []TypeInfo{
+	{Type: TypeMarketApproved, Description: "Your market or market group was approved."},
+	{Type: TypeMarketRejected, Description: "Your market or market group was rejected."},
+	{Type: TypeAmendmentReviewed, Description: "Your description amendment was reviewed."},
+	{Type: TypeAnswerReviewed, Description: "Your proposed answer was reviewed."},
+	{Type: TypeMarketResolved, Description: "A market you hold shares in resolved."},
+	{Type: TypeStewardAssigned, Description: "You were made steward of a market or market group."},
+	{Type: TypeReportReviewed, Description: "A moderator reviewed content you reported."},
+	{Type: TypeWatchlistActivity, Description: "Something you follow resolved, changed, or gained a new market."},
+}
+
+// ErrInvalidInput indicates an unknown notification type or a malformed request.
+var ErrInvalidInput = errors.New("invalid notification request")
+
+// TypeInfo describes a notification type for the preferences screen.
+type TypeInfo struct {
+	Type        Type
+	Description string
+}
+
+// Types lists every notification type in display order.
+func Types() []TypeInfo {
+	return append([]TypeInfo(nil), types...)
+}
+
+// Known reports whether t is a notification type.
+func Known(t Type) bool {
+	for _, info := range types {
+		if info.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is one in-app message for a user.
+type Notification struct {
+	ID            int64
+	Username      string
+	EventKey      string
+	Type          Type
+	Title         string
+	Body          string
+	MarketID      int64
+	MarketGroupID int64
+	ReadAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// Read reports whether the user has marked the notification read.
+func (n *Notification) Read() bool {
+	return n != nil && n.ReadAt != nil
+}
+
+// Preference is a user's choice for one notification type.
+type Preference struct {
+	Type        Type
+	Description string
+	Enabled     bool
+}
+
+// ListFilters narrows a notification listing.
+type ListFilters struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// Page is one page of a user's notifications, newest first.
+type Page struct {
+	Notifications []*Notification
+	Total         int64
+	UnreadCount   int64
+	Limit         int
+	Offset        int
+}
+
+// Repository persists notifications and preferences.
+type Repository interface {
+	// CreateNotification inserts the notification unless the user already has
+	// one for the same event key, and reports whether it was inserted.
+	CreateNotification(ctx context.Context, notification *Notification) (bool, error)
+	ListNotifications(ctx context.Context, username string, filters ListFilters) ([]*Notification, int64, error)
+	CountUnread(ctx context.Context, username string) (int64, error)
+	// MarkRead marks the given notifications read and returns how many changed.
+	// IDs that belong to another user or are already read are ignored.
+	MarkRead(ctx context.Context, username string, ids []int64, at time.Time) (int64, error)
+	MarkAllRead(ctx context.Context, username string, at time.Time) (int64, error)
+	// ListPreferences returns the stored choices; missing types are enabled.
+	ListPreferences(ctx context.Context, username string) (map[Type]bool, error)
+	SavePreferences(ctx context.Context, username string, preferences map[Type]bool, at time.Time) error
+}
+
+// Markets is the market read surface used to address and describe
+// notifications.
+type Markets interface {
+	GetMarket(ctx context.Context, id int64) (*dmarkets.Market, error)
+	GetMarketGroup(ctx context.Context, groupID int64) (*dmarkets.MarketGroup, error)
+	GetMarketPositions(ctx context.Context, marketID int64) (dmarkets.MarketPositions, error)
+}
+
+// Forwarder receives each addressed notification so another channel, such
+// as email, can reach the same recipients. Delivery is at-least-once, so a
+// forwarder must tolerate seeing the same event key twice.
+type Forwarder interface {
+	Forward(ctx context.Context, notification *Notification) error
+}
+
+// Followers lists who follows markets, market groups, and tags.
+type Followers interface {
+	Followers(ctx context.Context, targets ...dwatchlists.Target) ([]string, error)
+}
@@ -0,0 +1,517 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dmarkets "socialpredict/internal/domain/markets"
+	dwatchlists "socialpredict/internal/domain/watchlists"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	maxMarkReadIDs   = 200
+)
+
+// Service generates notifications from domain events and serves each user's
+// inbox and preferences.
+type Service struct {
+	repo      Repository
+	markets   Markets
+	forwarder Forwarder
+	followers Followers
+	now       func() time.Time
+}
+
+// NewService constructs a notifications service.
+func NewService(repo Repository, markets Markets, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{repo: repo, markets: markets, now: now}
+}
+
+// SetForwarder hands every addressed notification to forwarder as well,
+// whether or not the recipient keeps that type in their inbox.
+func (s *Service) SetForwarder(forwarder Forwarder) {
+	if s != nil {
+		s.forwarder = forwarder
+	}
+}
+
+// SetFollowers lets events about followed markets, market groups, and tags
+// reach their followers.
+func (s *Service) SetFollowers(followers Followers) {
+	if s != nil {
+		s.followers = followers
+	}
+}
+
+// List returns a page of the user's notifications, newest first, with the
+// user's unread count.
+func (s *Service) List(ctx context.Context, username string, filters ListFilters) (*Page, error) {
+	if err := s.ready(username); err != nil {
+		return nil, err
+	}
+	if filters.Limit <= 0 {
+		filters.Limit = defaultListLimit
+	}
+	if filters.Limit > maxListLimit {
+		filters.Limit = maxListLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+	items, total, err := s.repo.ListNotifications(ctx, username, filters)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return &Page{Notifications: items, Total: total, UnreadCount: unread, Limit: filters.Limit, Offset: filters.Offset}, nil
+}
+
+// UnreadCount returns how many of the user's notifications are unread.
+func (s *Service) UnreadCount(ctx context.Context, username string) (int64, error) {
+	if err := s.ready(username); err != nil {
+		return 0, err
+	}
+	return s.repo.CountUnread(ctx, username)
+}
+
+// MarkRead marks the given notifications read and returns the remaining
+// unread count. IDs the user does not own are ignored.
+func (s *Service) MarkRead(ctx context.Context, username string, ids []int64) (int64, error) {
+	if err := s.ready(username); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 || len(ids) > maxMarkReadIDs {
+		return 0, ErrInvalidInput
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return 0, ErrInvalidInput
+		}
+	}
+	if _, err := s.repo.MarkRead(ctx, username, ids, s.now().UTC()); err != nil {
+		return 0, err
+	}
+	return s.repo.CountUnread(ctx, username)
+}
+
+// MarkAllRead marks every notification the user has read.
+func (s *Service) MarkAllRead(ctx context.Context, username string) error {
+	if err := s.ready(username); err != nil {
+		return err
+	}
+	_, err := s.repo.MarkAllRead(ctx, username, s.now().UTC())
+	return err
+}
+
+// Preferences returns the user's choice for every notification type.
+func (s *Service) Preferences(ctx context.Context, username string) ([]Preference, error) {
+	if err := s.ready(username); err != nil {
+		return nil, err
+	}
+	stored, err := s.repo.ListPreferences(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	preferences := make([]Preference, 0, len(types))
+	for _, info := range types {
+		enabled, ok := stored[info.Type]
+		preferences = append(preferences, Preference{Type: info.Type, Description: info.Description, Enabled: enabled || !ok})
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences stores the given choices and returns the full set.
+// Types not mentioned keep their current setting.
+func (s *Service) UpdatePreferences(ctx context.Context, username string, changes map[Type]bool) ([]Preference, error) {
+	if err := s.ready(username); err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, ErrInvalidInput
+	}
+	for t := range changes {
+		if !Known(t) {
+			return nil, ErrInvalidInput
+		}
+	}
+	if err := s.repo.SavePreferences(ctx, username, changes, s.now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.Preferences(ctx, username)
+}
+
+// HandleEvent subscribes the service to the domain event bus. Recipients who
+// turned the type off are skipped, and nobody is notified about their own
+// action. Inserts are idempotent per user and event, so redelivery is safe.
+func (s *Service) HandleEvent(ctx context.Context, event devents.Event) error {
+	if s == nil || s.repo == nil || s.markets == nil || event.ID <= 0 {
+		return nil
+	}
+	drafts, err := s.draftsFor(ctx, event)
+	if err != nil {
+		if errors.Is(err, dmarkets.ErrMarketNotFound) || errors.Is(err, dmarkets.ErrMarketGroupNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	createdAt := event.OccurredAt
+	if createdAt.IsZero() {
+		createdAt = s.now()
+	}
+	eventKey := "evt_" + strconv.FormatInt(event.ID, 10)
+	for _, draft := range drafts {
+		draft.EventKey = eventKey
+		draft.CreatedAt = createdAt.UTC()
+		if s.forwarder != nil {
+			if err := s.forwarder.Forward(ctx, draft); err != nil {
+				return err
+			}
+		}
+		enabled, err := s.enabled(ctx, draft.Username, draft.Type)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			continue
+		}
+		if _, err := s.repo.CreateNotification(ctx, draft); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) enabled(ctx context.Context, username string, t Type) (bool, error) {
+	stored, err := s.repo.ListPreferences(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	enabled, ok := stored[t]
+	return enabled || !ok, nil
+}
+
+// draftsFor decides who hears about event and what they are told.
+func (s *Service) draftsFor(ctx context.Context, event devents.Event) ([]*Notification, error) {
+	switch event.Type {
+	case devents.MarketApproved, devents.MarketRejected:
+		market, err := s.markets.GetMarket(ctx, event.MarketID)
+		if err != nil {
+			return nil, err
+		}
+		if event.Type == devents.MarketApproved {
+			drafts := draft(event.Username, market.CreatorUsername, &Notification{
+				Type:     TypeMarketApproved,
+				Title:    "Your market was approved",
+				Body:     market.QuestionTitle,
+				MarketID: market.ID,
+			})
+			tagged, err := s.followerDrafts(ctx, event.Username, skip(market.CreatorUsername), Notification{
+				Type:     TypeWatchlistActivity,
+				Title:    "New market in a tag you follow",
+				Body:     market.QuestionTitle,
+				MarketID: market.ID,
+			}, tagTargets(market.Tags)...)
+			if err != nil {
+				return nil, err
+			}
+			return append(drafts, tagged...), nil
+		}
+		return draft(event.Username, market.CreatorUsername, &Notification{
+			Type:     TypeMarketRejected,
+			Title:    "Your market was rejected",
+			Body:     withReason(market.QuestionTitle, dataString(event, "reason")),
+			MarketID: market.ID,
+		}), nil
+
+	case devents.MarketGroupApproved, devents.MarketGroupRejected:
+		group, err := s.markets.GetMarketGroup(ctx, event.MarketGroupID)
+		if err != nil {
+			return nil, err
+		}
+		if event.Type == devents.MarketGroupApproved {
+			drafts := draft(event.Username, group.CreatorUsername, &Notification{
+				Type:          TypeMarketApproved,
+				Title:         "Your market group was approved",
+				Body:          group.QuestionTitle,
+				MarketGroupID: group.ID,
+			})
+			var tags []dmarkets.MarketTag
+			for _, member := range group.Members {
+				market, err := s.markets.GetMarket(ctx, member.MarketID)
+				if err != nil {
+					return nil, err
+				}
+				tags = append(tags, market.Tags...)
+			}
+			tagged, err := s.followerDrafts(ctx, event.Username, skip(group.CreatorUsername), Notification{
+				Type:          TypeWatchlistActivity,
+				Title:         "New market group in a tag you follow",
+				Body:          group.QuestionTitle,
+				MarketGroupID: group.ID,
+			}, tagTargets(tags)...)
+			if err != nil {
+				return nil, err
+			}
+			return append(drafts, tagged...), nil
+		}
+		return draft(event.Username, group.CreatorUsername, &Notification{
+			Type:          TypeMarketRejected,
+			Title:         "Your market group was rejected",
+			Body:          withReason(group.QuestionTitle, dataString(event, "reason")),
+			MarketGroupID: group.ID,
+		}), nil
+
+	case devents.AmendmentApproved, devents.AmendmentRejected:
+		market, err := s.markets.GetMarket(ctx, event.MarketID)
+		if err != nil {
+			return nil, err
+		}
+		outcome := "approved"
+		if event.Type == devents.AmendmentRejected {
+			outcome = "rejected"
+		}
+		drafts := draft(event.Username, dataString(event, "createdBy"), &Notification{
+			Type:     TypeAmendmentReviewed,
+			Title:    "Your description amendment was " + outcome,
+			Body:     market.QuestionTitle,
+			MarketID: market.ID,
+		})
+		if event.Type == devents.AmendmentRejected {
+			return drafts, nil
+		}
+		following, err := s.followerDrafts(ctx, event.Username, skip(dataString(event, "createdBy")), Notification{
+			Type:     TypeWatchlistActivity,
+			Title:    "A market you follow updated its description",
+			Body:     market.QuestionTitle,
+			MarketID: market.ID,
+		}, dwatchlists.MarketTarget(market.ID))
+		if err != nil {
+			return nil, err
+		}
+		return append(drafts, following...), nil
+
+	case devents.AnswerAdded, devents.AnswerRejected:
+		group, err := s.markets.GetMarketGroup(ctx, event.MarketGroupID)
+		if err != nil {
+			return nil, err
+		}
+		label := dataString(event, "answerLabel")
+		if event.Type == devents.AnswerAdded {
+			// AnswerAdded is attributed to the proposer; the reviewer is in the data.
+			drafts := draft(dataString(event, "approvedBy"), event.Username, &Notification{
+				Type:          TypeAnswerReviewed,
+				Title:         "Your answer was added",
+				Body:          fmt.Sprintf("%q was added to %s", label, group.QuestionTitle),
+				MarketID:      event.MarketID,
+				MarketGroupID: group.ID,
+			})
+			following, err := s.followerDrafts(ctx, dataString(event, "approvedBy"), skip(event.Username), Notification{
+				Type:          TypeWatchlistActivity,
+				Title:         "A market group you follow has a new answer",
+				Body:          fmt.Sprintf("%q was added to %s", label, group.QuestionTitle),
+				MarketID:      event.MarketID,
+				MarketGroupID: group.ID,
+			}, dwatchlists.MarketGroupTarget(group.ID))
+			if err != nil {
+				return nil, err
+			}
+			return append(drafts, following...), nil
+		}
+		return draft(event.Username, dataString(event, "proposedBy"), &Notification{
+			Type:          TypeAnswerReviewed,
+			Title:         "Your answer was rejected",
+			Body:          fmt.Sprintf("%q was not added to %s", label, group.QuestionTitle),
+			MarketGroupID: group.ID,
+		}), nil
+
+	case devents.MarketResolved:
+		return s.resolutionDrafts(ctx, event)
+
+	case devents.MarketGroupResolved:
+		group, err := s.markets.GetMarketGroup(ctx, event.MarketGroupID)
+		if err != nil {
+			return nil, err
+		}
+		return s.followerDrafts(ctx, event.Username, nil, Notification{
+			Type:          TypeWatchlistActivity,
+			Title:         "A market group you follow resolved",
+			Body:          group.QuestionTitle,
+			MarketGroupID: group.ID,
+		}, dwatchlists.MarketGroupTarget(group.ID))
+
+	case devents.MarketStewardChanged:
+		market, err := s.markets.GetMarket(ctx, event.MarketID)
+		if err != nil {
+			return nil, err
+		}
+		return draft(event.Username, dataString(event, "toSteward"), &Notification{
+			Type:     TypeStewardAssigned,
+			Title:    "You are now the steward of a market",
+			Body:     market.QuestionTitle,
+			MarketID: market.ID,
+		}), nil
+
+	case devents.MarketGroupStewardChanged:
+		group, err := s.markets.GetMarketGroup(ctx, event.MarketGroupID)
+		if err != nil {
+			return nil, err
+		}
+		return draft(event.Username, dataString(event, "toSteward"), &Notification{
+			Type:          TypeStewardAssigned,
+			Title:         "You are now the steward of a market group",
+			Body:          group.QuestionTitle,
+			MarketGroupID: group.ID,
+		}), nil
+
+	case devents.ReportResolved:
+		// Reporters learn the outcome, not the moderator's reason.
+		body := "A moderator reviewed it and took action."
+		if dataString(event, "status") == "dismissed" {
+			body = "A moderator reviewed it and found no action was needed."
+		}
+		return draft(event.Username, dataString(event, "reporter"), &Notification{
+			Type:  TypeReportReviewed,
+			Title: fmt.Sprintf("Your report on a %s was reviewed", dataString(event, "targetType")),
+			Body:  body,
+		}), nil
+	}
+	return nil, nil
+}
+
+// resolutionDrafts notifies everyone still holding shares when the market
+// resolved, and then its followers who hold nothing, except the resolver.
+func (s *Service) resolutionDrafts(ctx context.Context, event devents.Event) ([]*Notification, error) {
+	market, err := s.markets.GetMarket(ctx, event.MarketID)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := s.markets.GetMarketPositions(ctx, event.MarketID)
+	if err != nil {
+		return nil, err
+	}
+	body := market.QuestionTitle
+	if resolution := dataString(event, "resolution"); resolution != "" {
+		body = fmt.Sprintf("%s resolved %s", market.QuestionTitle, resolution)
+	}
+
+	var drafts []*Notification
+	seen := map[string]bool{}
+	for _, position := range positions {
+		if position == nil || (position.YesSharesOwned <= 0 && position.NoSharesOwned <= 0) || seen[position.Username] {
+			continue
+		}
+		seen[position.Username] = true
+		drafts = append(drafts, draft(event.Username, position.Username, &Notification{
+			Type:          TypeMarketResolved,
+			Title:         "A market you hold resolved",
+			Body:          body,
+			MarketID:      market.ID,
+			MarketGroupID: event.MarketGroupID,
+		})...)
+	}
+	following, err := s.followerDrafts(ctx, event.Username, seen, Notification{
+		Type:          TypeWatchlistActivity,
+		Title:         "A market you follow resolved",
+		Body:          body,
+		MarketID:      market.ID,
+		MarketGroupID: event.MarketGroupID,
+	}, dwatchlists.MarketTarget(market.ID))
+	if err != nil {
+		return nil, err
+	}
+	return append(drafts, following...), nil
+}
+
+// followerDrafts addresses a copy of notification to everyone following any
+// of targets, except the actor and anyone in skipped.
+func (s *Service) followerDrafts(ctx context.Context, actor string, skipped map[string]bool, notification Notification, targets ...dwatchlists.Target) ([]*Notification, error) {
+	if s.followers == nil || len(targets) == 0 {
+		return nil, nil
+	}
+	usernames, err := s.followers.Followers(ctx, targets...)
+	if err != nil {
+		return nil, err
+	}
+	var drafts []*Notification
+	for _, username := range usernames {
+		if skipped[username] {
+			continue
+		}
+		copied := notification
+		drafts = append(drafts, draft(actor, username, &copied)...)
+	}
+	return drafts, nil
+}
+
+func skip(usernames ...string) map[string]bool {
+	skipped := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		if username = strings.TrimSpace(username); username != "" {
+			skipped[username] = true
+		}
+	}
+	return skipped
+}
+
+func tagTargets(tags []dmarkets.MarketTag) []dwatchlists.Target {
+	targets := make([]dwatchlists.Target, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		if tag.Slug == "" || seen[tag.Slug] {
+			continue
+		}
+		seen[tag.Slug] = true
+		targets = append(targets, dwatchlists.TagTarget(tag.Slug))
+	}
+	return targets
+}
+
+// draft addresses notification to recipient unless there is no recipient or
+// the recipient is the actor.
+func draft(actor, recipient string, notification *Notification) []*Notification {
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" || recipient == strings.TrimSpace(actor) {
+		return nil
+	}
+	notification.Username = recipient
+	return []*Notification{notification}
+}
+
+func withReason(title, reason string) string {
+	if strings.TrimSpace(reason) == "" {
+		return title
+	}
+	return fmt.Sprintf("%s (reason: %s)", title, strings.TrimSpace(reason))
+}
+
+func dataString(event devents.Event, key string) string {
+	value, ok := event.Data[key].(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+func (s *Service) ready(username string) error {
+	if s == nil || s.repo == nil {
+		return errors.New("notifications service unavailable")
+	}
+	if strings.TrimSpace(username) == "" {
+		return ErrInvalidInput
+	}
+	return nil
+}
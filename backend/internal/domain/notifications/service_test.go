@@ -0,0 +1,366 @@
+package notifications_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/notifications"
+	dwatchlists "socialpredict/internal/domain/watchlists"
+)
+
+type memoryRepo struct {
+	notifications []*notifications.Notification
+	preferences   map[string]map[notifications.Type]bool
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{preferences: map[string]map[notifications.Type]bool{}}
+}
+
+func (m *memoryRepo) CreateNotification(_ context.Context, notification *notifications.Notification) (bool, error) {
+	for _, existing := range m.notifications {
+		if existing.Username == notification.Username && existing.EventKey == notification.EventKey {
+			return false, nil
+		}
+	}
+	notification.ID = int64(len(m.notifications) + 1)
+	clone := *notification
+	m.notifications = append(m.notifications, &clone)
+	return true, nil
+}
+
+func (m *memoryRepo) ListNotifications(_ context.Context, username string, filters notifications.ListFilters) ([]*notifications.Notification, int64, error) {
+	var out []*notifications.Notification
+	for i := len(m.notifications) - 1; i >= 0; i-- {
+		n := m.notifications[i]
+		if n.Username == username && (!filters.UnreadOnly || !n.Read()) {
+			out = append(out, n)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *memoryRepo) CountUnread(_ context.Context, username string) (int64, error) {
+	var count int64
+	for _, n := range m.notifications {
+		if n.Username == username && !n.Read() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryRepo) MarkRead(_ context.Context, username string, ids []int64, at time.Time) (int64, error) {
+	var changed int64
+	for _, n := range m.notifications {
+		for _, id := range ids {
+			if n.ID == id && n.Username == username && !n.Read() {
+				n.ReadAt = &at
+				changed++
+			}
+		}
+	}
+	return changed, nil
+}
+
+func (m *memoryRepo) MarkAllRead(_ context.Context, username string, at time.Time) (int64, error) {
+	var changed int64
+	for _, n := range m.notifications {
+		if n.Username == username && !n.Read() {
+			n.ReadAt = &at
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+func (m *memoryRepo) ListPreferences(_ context.Context, username string) (map[notifications.Type]bool, error) {
+	out := map[notifications.Type]bool{}
+	for t, enabled := range m.preferences[username] {
+		out[t] = enabled
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) SavePreferences(_ context.Context, username string, preferences map[notifications.Type]bool, _ time.Time) error {
+	if m.preferences[username] == nil {
+		m.preferences[username] = map[notifications.Type]bool{}
+	}
+	for t, enabled := range preferences {
+		m.preferences[username][t] = enabled
+	}
+	return nil
+}
+
+type fakeMarkets struct {
+	markets   map[int64]*dmarkets.Market
+	groups    map[int64]*dmarkets.MarketGroup
+	positions map[int64]dmarkets.MarketPositions
+}
+
+func (f *fakeMarkets) GetMarket(_ context.Context, id int64) (*dmarkets.Market, error) {
+	market, ok := f.markets[id]
+	if !ok {
+		return nil, dmarkets.ErrMarketNotFound
+	}
+	return market, nil
+}
+
+func (f *fakeMarkets) GetMarketGroup(_ context.Context, groupID int64) (*dmarkets.MarketGroup, error) {
+	group, ok := f.groups[groupID]
+	if !ok {
+		return nil, dmarkets.ErrMarketGroupNotFound
+	}
+	return group, nil
+}
+
+func (f *fakeMarkets) GetMarketPositions(_ context.Context, marketID int64) (dmarkets.MarketPositions, error) {
+	return f.positions[marketID], nil
+}
+
+func newTestService() (*notifications.Service, *memoryRepo) {
+	repo := newMemoryRepo()
+	markets := &fakeMarkets{
+		markets: map[int64]*dmarkets.Market{
+			7: {ID: 7, QuestionTitle: "Will it rain?", CreatorUsername: "carol"},
+		},
+		groups: map[int64]*dmarkets.MarketGroup{
+			4: {ID: 4, QuestionTitle: "Who wins?", CreatorUsername: "dave"},
+		},
+		positions: map[int64]dmarkets.MarketPositions{
+			7: {
+				{Username: "alice", YesSharesOwned: 10},
+				{Username: "bob", NoSharesOwned: 3},
+				{Username: "erin"},
+				{Username: "admin", YesSharesOwned: 1},
+			},
+		},
+	}
+	now := func() time.Time { return time.Date(2026, 6, 29, 9, 0, 0, 0, time.UTC) }
+	return notifications.NewService(repo, markets, now), repo
+}
+
+func recipients(repo *memoryRepo, t notifications.Type) []string {
+	var out []string
+	for _, n := range repo.notifications {
+		if n.Type == t {
+			out = append(out, n.Username)
+		}
+	}
+	return out
+}
+
+func TestHandleEventAddressesTheAffectedUsers(t *testing.T) {
+	svc, repo := newTestService()
+	ctx := context.Background()
+	events := []devents.Event{
+		{ID: 1, Type: devents.MarketApproved, MarketID: 7, Username: "admin"},
+		{ID: 2, Type: devents.MarketGroupRejected, MarketGroupID: 4, Username: "admin", Data: map[string]any{"reason": "duplicate"}},
+		{ID: 3, Type: devents.AmendmentRejected, MarketID: 7, Username: "carol", Data: map[string]any{"createdBy": "frank"}},
+		{ID: 4, Type: devents.AnswerAdded, MarketID: 12, MarketGroupID: 4, Username: "gina", Data: map[string]any{"answerLabel": "Blue", "approvedBy": "dave"}},
+		{ID: 5, Type: devents.MarketResolved, MarketID: 7, Username: "admin", Data: map[string]any{"resolution": "YES"}},
+		{ID: 6, Type: devents.MarketGroupStewardChanged, MarketGroupID: 4, Username: "admin", Data: map[string]any{"fromSteward": "dave", "toSteward": "hank"}},
+		{ID: 7, Type: devents.BetPlaced, MarketID: 7, Username: "alice"},
+		{ID: 8, Type: devents.ReportResolved, Username: "mod", Data: map[string]any{"reporter": "ivy", "targetType": "comment", "status": "dismissed"}},
+	}
+	for _, event := range events {
+		if err := svc.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("HandleEvent(%s) returned error: %v", event.Type, err)
+		}
+	}
+
+	checks := map[notifications.Type][]string{
+		notifications.TypeMarketApproved:    {"carol"},
+		notifications.TypeMarketRejected:    {"dave"},
+		notifications.TypeAmendmentReviewed: {"frank"},
+		notifications.TypeAnswerReviewed:    {"gina"},
+		notifications.TypeMarketResolved:    {"alice", "bob"},
+		notifications.TypeStewardAssigned:   {"hank"},
+		notifications.TypeReportReviewed:    {"ivy"},
+	}
+	for notificationType, want := range checks {
+		got := recipients(repo, notificationType)
+		if len(got) != len(want) {
+			t.Fatalf("%s recipients = %v, want %v", notificationType, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("%s recipients = %v, want %v", notificationType, got, want)
+			}
+		}
+	}
+	if len(repo.notifications) != 8 {
+		t.Fatalf("expected 8 notifications, got %d", len(repo.notifications))
+	}
+	if body := repo.notifications[7].Body; body != "A moderator reviewed it and found no action was needed." {
+		t.Fatalf("report body = %q", body)
+	}
+	if body := repo.notifications[1].Body; body != "Who wins? (reason: duplicate)" {
+		t.Fatalf("rejection body = %q", body)
+	}
+}
+
+func TestHandleEventSkipsSelfActionsDisabledTypesAndRedelivery(t *testing.T) {
+	svc, repo := newTestService()
+	ctx := context.Background()
+
+	if err := svc.HandleEvent(ctx, devents.Event{ID: 1, Type: devents.MarketApproved, MarketID: 7, Username: "carol"}); err != nil {
+		t.Fatalf("HandleEvent returned error: %v", err)
+	}
+	if len(repo.notifications) != 0 {
+		t.Fatalf("creator approving their own market should not be notified: %+v", repo.notifications)
+	}
+
+	if _, err := svc.UpdatePreferences(ctx, "bob", map[notifications.Type]bool{notifications.TypeMarketResolved: false}); err != nil {
+		t.Fatalf("UpdatePreferences returned error: %v", err)
+	}
+	resolved := devents.Event{ID: 2, Type: devents.MarketResolved, MarketID: 7, Username: "admin", Data: map[string]any{"resolution": "NO"}}
+	for i := 0; i < 2; i++ {
+		if err := svc.HandleEvent(ctx, resolved); err != nil {
+			t.Fatalf("HandleEvent returned error: %v", err)
+		}
+	}
+	if got := recipients(repo, notifications.TypeMarketResolved); len(got) != 1 || got[0] != "alice" {
+		t.Fatalf("resolution recipients = %v, want only alice once", got)
+	}
+
+	if err := svc.HandleEvent(ctx, devents.Event{ID: 3, Type: devents.MarketApproved, MarketID: 99, Username: "admin"}); err != nil {
+		t.Fatalf("missing market should be skipped, got %v", err)
+	}
+}
+
+func TestInboxListingMarkReadAndPreferences(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+	for id := int64(1); id <= 3; id++ {
+		event := devents.Event{ID: id, Type: devents.MarketResolved, MarketID: 7, Username: "admin"}
+		if err := svc.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("HandleEvent returned error: %v", err)
+		}
+	}
+
+	page, err := svc.List(ctx, "alice", notifications.ListFilters{Limit: 500})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if page.Total != 3 || page.UnreadCount != 3 || page.Limit != 100 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	unread, err := svc.MarkRead(ctx, "alice", []int64{page.Notifications[0].ID})
+	if err != nil || unread != 2 {
+		t.Fatalf("MarkRead = %d, %v", unread, err)
+	}
+	if _, err := svc.MarkRead(ctx, "alice", []int64{0}); !errors.Is(err, notifications.ErrInvalidInput) {
+		t.Fatalf("MarkRead with bad id error = %v", err)
+	}
+	if err := svc.MarkAllRead(ctx, "alice"); err != nil {
+		t.Fatalf("MarkAllRead returned error: %v", err)
+	}
+	if count, err := svc.UnreadCount(ctx, "alice"); err != nil || count != 0 {
+		t.Fatalf("UnreadCount = %d, %v", count, err)
+	}
+
+	if _, err := svc.UpdatePreferences(ctx, "alice", map[notifications.Type]bool{"bogus": true}); !errors.Is(err, notifications.ErrInvalidInput) {
+		t.Fatalf("unknown type error = %v", err)
+	}
+	preferences, err := svc.UpdatePreferences(ctx, "alice", map[notifications.Type]bool{notifications.TypeStewardAssigned: false})
+	if err != nil {
+		t.Fatalf("UpdatePreferences returned error: %v", err)
+	}
+	if len(preferences) != len(notifications.Types()) {
+		t.Fatalf("preferences should cover every type: %+v", preferences)
+	}
+	for _, preference := range preferences {
+		if want := preference.Type != notifications.TypeStewardAssigned; preference.Enabled != want {
+			t.Fatalf("preference %s enabled=%v, want %v", preference.Type, preference.Enabled, want)
+		}
+	}
+}
+
+type recordingForwarder struct {
+	forwarded []*notifications.Notification
+}
+
+func (r *recordingForwarder) Forward(_ context.Context, notification *notifications.Notification) error {
+	r.forwarded = append(r.forwarded, notification)
+	return nil
+}
+
+func TestHandleEventForwardsAddressedNotificationsEvenWhenInboxTypeIsOff(t *testing.T) {
+	svc, repo := newTestService()
+	forwarder := &recordingForwarder{}
+	svc.SetForwarder(forwarder)
+	ctx := context.Background()
+	if _, err := svc.UpdatePreferences(ctx, "bob", map[notifications.Type]bool{notifications.TypeMarketResolved: false}); err != nil {
+		t.Fatalf("UpdatePreferences returned error: %v", err)
+	}
+
+	if err := svc.HandleEvent(ctx, devents.Event{ID: 9, Type: devents.MarketResolved, MarketID: 7, Username: "admin"}); err != nil {
+		t.Fatalf("HandleEvent returned error: %v", err)
+	}
+	if len(forwarder.forwarded) != 2 || forwarder.forwarded[0].EventKey != "evt_9" || forwarder.forwarded[1].Username != "bob" {
+		t.Fatalf("unexpected forwarded notifications: %+v", forwarder.forwarded)
+	}
+	if got := recipients(repo, notifications.TypeMarketResolved); len(got) != 1 || got[0] != "alice" {
+		t.Fatalf("inbox recipients = %v, want only alice", got)
+	}
+}
+
+type followerIndex map[dwatchlists.Target][]string
+
+func (f followerIndex) Followers(_ context.Context, targets ...dwatchlists.Target) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, target := range targets {
+		for _, username := range f[target] {
+			if !seen[username] {
+				seen[username] = true
+				out = append(out, username)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestHandleEventReachesFollowers(t *testing.T) {
+	svc, repo := newTestService()
+	svc.SetFollowers(followerIndex{
+		dwatchlists.MarketTarget(7):      {"alice", "jack", "carol"},
+		dwatchlists.MarketGroupTarget(4): {"kim", "gina"},
+	})
+	ctx := context.Background()
+	events := []devents.Event{
+		// alice holds shares and already hears about the resolution as a holder.
+		{ID: 1, Type: devents.MarketResolved, MarketID: 7, Username: "admin", Data: map[string]any{"resolution": "NO"}},
+		// gina proposed the answer and hears about it as the proposer.
+		{ID: 2, Type: devents.AnswerAdded, MarketID: 12, MarketGroupID: 4, Username: "gina", Data: map[string]any{"answerLabel": "Blue", "approvedBy": "dave"}},
+		// carol approved the amendment and is skipped as the actor.
+		{ID: 3, Type: devents.AmendmentApproved, MarketID: 7, Username: "carol", Data: map[string]any{"createdBy": "frank"}},
+	}
+	for _, event := range events {
+		if err := svc.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("HandleEvent(%s) returned error: %v", event.Type, err)
+		}
+	}
+
+	var got []string
+	for _, n := range repo.notifications {
+		if n.Type == notifications.TypeWatchlistActivity {
+			got = append(got, n.EventKey+":"+n.Username)
+		}
+	}
+	want := []string{"evt_1:jack", "evt_1:carol", "evt_2:kim", "evt_3:alice", "evt_3:jack"}
+	if len(got) != len(want) {
+		t.Fatalf("watchlist notifications = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("watchlist notifications = %v, want %v", got, want)
+		}
+	}
+}
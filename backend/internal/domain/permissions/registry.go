@@ -0,0 +1,127 @@
+package permissions
+
+import (
+	"sort"
+	"strings"
+)
+
+// Permission names a single capability that can be granted to a role.
+type Permission string
+
+const (
+	// MarketsApprove allows reviewing proposed markets, market groups, and answer additions.
+	MarketsApprove Permission = "markets.approve"
+	// MarketsResolveAny allows resolving any market regardless of stewardship.
+	MarketsResolveAny Permission = "markets.resolve.any"
+	// MarketsStewardAssign allows reassigning market and market group stewards.
+	MarketsStewardAssign Permission = "markets.steward.assign"
+	// MarketsAmendmentsReview allows reviewing description amendments and their settings.
+	MarketsAmendmentsReview Permission = "markets.amendments.review"
+	// TagsManage allows editing the tag catalog and the tags attached to markets.
+	TagsManage Permission = "tags.manage"
+	// UsersCreate allows creating accounts directly and minting moderator invites.
+	UsersCreate Permission = "users.create"
+	// UsersManage allows listing accounts, changing roles, and handling login lockouts.
+	UsersManage Permission = "users.manage"
+	// RolesManage allows editing role grants and user role assignments.
+	RolesManage Permission = "roles.manage"
+	// CMSEdit allows editing homepage, discovery, share, and reporting content.
+	CMSEdit Permission = "cms.edit"
+	// WebhooksManage allows registering outbound webhooks and inspecting their deliveries.
+	WebhooksManage Permission = "webhooks.manage"
+	// CommentsModerate allows hiding and removing other people's comments.
+	CommentsModerate Permission = "comments.moderate"
+	// ReportsReview allows working the content report queue and dismissing reports.
+	ReportsReview Permission = "reports.review"
+	// SeasonsManage allows defining seasons and rolling them over, including balance resets.
+	SeasonsManage Permission = "seasons.manage"
+	// TeamsManage allows creating teams, adding members directly, and managing any team.
+	TeamsManage Permission = "teams.manage"
+	// EconomyView allows charting and exporting the daily economy history and
+	// running the money reconciliation report.
+	EconomyView Permission = "economy.view"
+)
+
+// Definition describes a registered permission. ReadOnly permissions only
+// expose data, so suspended and banned accounts keep them.
+type Definition struct {
+	Name        Permission
+	Description string
+	ReadOnly    bool
+}
+
+var registry = []Definition{
+	{Name: MarketsApprove, Description: "Approve or reject proposed markets, market groups, and answer additions."},
+	{Name: MarketsResolveAny, Description: "Resolve any market regardless of stewardship."},
+	{Name: MarketsStewardAssign, Description: "Reassign market and market group stewards."},
+	{Name: MarketsAmendmentsReview, Description: "Review description amendments and change amendment settings."},
+	{Name: TagsManage, Description: "Edit the tag catalog and the tags attached to markets and groups."},
+	{Name: UsersCreate, Description: "Create accounts directly and mint moderator invites."},
+	{Name: UsersManage, Description: "List accounts, change user roles, suspend moderators, and clear login lockouts."},
+	{Name: RolesManage, Description: "Edit role permission grants and user role assignments."},
+	{Name: CMSEdit, Description: "Edit homepage, market discovery, social share, and reporting visibility content."},
+	{Name: WebhooksManage, Description: "Register outbound webhooks, send test events, and inspect or retry deliveries."},
+	{Name: CommentsModerate, Description: "Hide, unhide, and remove comments on markets and market groups."},
+	{Name: ReportsReview, Description: "Review the content report queue, dismiss reports, and record moderation actions."},
+	{Name: SeasonsManage, Description: "Define seasons and roll them over, freezing standings and resetting balances."},
+	{Name: TeamsManage, Description: "Create teams, add members without invitation, and manage any team's profile, members, and team-only markets."},
+	{Name: EconomyView, Description: "View and export the daily history of money supply, debt, fees, and trading activity, and run the money reconciliation report.", ReadOnly: true},
+}
+
+// Registry returns every registered permission in a stable order.
+func Registry() []Definition {
+	return append([]Definition(nil), registry...)
+}
+
+// Lookup resolves a permission name against the registry.
+func Lookup(name string) (Permission, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, definition := range registry {
+		if string(definition.Name) == name {
+			return definition.Name, true
+		}
+	}
+	return "", false
+}
+
+// IsReadOnly reports whether the permission only exposes data.
+func IsReadOnly(permission Permission) bool {
+	for _, definition := range registry {
+		if definition.Name == permission {
+			return definition.ReadOnly
+		}
+	}
+	return false
+}
+
+// All returns every registered permission name.
+func All() []Permission {
+	all := make([]Permission, 0, len(registry))
+	for _, definition := range registry {
+		all = append(all, definition.Name)
+	}
+	return all
+}
+
+// Normalize validates, de-duplicates, and sorts a list of permission names.
+func Normalize(names []string) ([]Permission, error) {
+	seen := make(map[Permission]struct{}, len(names))
+	result := make([]Permission, 0, len(names))
+	for _, name := range names {
+		permission, ok := Lookup(name)
+		if !ok {
+			return nil, ErrUnknownPermission
+		}
+		if _, dup := seen[permission]; dup {
+			continue
+		}
+		seen[permission] = struct{}{}
+		result = append(result, permission)
+	}
+	sortPermissions(result)
+	return result, nil
+}
+
+func sortPermissions(values []Permission) {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+}
@@ -0,0 +1,399 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// RoleAdmin is the built-in superuser role; it implicitly holds every permission.
+	RoleAdmin = "ADMIN"
+	// RoleModerator is the built-in role carried by moderator accounts.
+	RoleModerator = "MODERATOR"
+	// RoleRegular is the built-in role carried by every other account.
+	RoleRegular = "REGULAR"
+)
+
+var (
+	// ErrPermissionDenied indicates that the subject lacks the required permission.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrUnknownPermission indicates that a permission name is not in the registry.
+	ErrUnknownPermission = errors.New("unknown permission")
+	// ErrInvalidRole indicates that a role name is malformed or cannot be used that way.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrImmutableRole indicates an attempt to edit the grants of the ADMIN role.
+	ErrImmutableRole = errors.New("role grants cannot be edited")
+	// ErrRoleNotFound indicates that the requested role does not exist.
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrUserNotFound indicates that the referenced account does not exist.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
+
+// Subject is the identity an authorization decision is made for.
+type Subject struct {
+	Username string
+	// Role is the account's built-in role (its user type).
+	Role string
+	// Suspended marks a suspended moderator whose built-in grants are withheld.
+	Suspended bool
+	// Restricted marks a suspended or banned account; it keeps only read-only
+	// grants, whatever its roles.
+	Restricted bool
+}
+
+// allows applies the subject's account restriction to a granted permission.
+func (s Subject) allows(permission Permission) bool {
+	return !s.Restricted || IsReadOnly(permission)
+}
+
+func (s Subject) role() string {
+	switch role := strings.ToUpper(strings.TrimSpace(s.Role)); role {
+	case "", "USER":
+		return RoleRegular
+	default:
+		return role
+	}
+}
+
+// Role is a named permission set. Built-in roles follow user types; custom
+// roles are assigned to individual accounts on top of their built-in role.
+type Role struct {
+	Name        string
+	Description string
+	BuiltIn     bool
+	Superuser   bool
+	Permissions []Permission
+	UpdatedBy   string
+	UpdatedAt   time.Time
+}
+
+// RoleUpdate replaces a role's description and permission grants.
+type RoleUpdate struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// UserRoles lists the custom roles assigned to an account.
+type UserRoles struct {
+	Username string
+	Roles    []string
+}
+
+// Repository persists roles, their grants, and user role assignments.
+type Repository interface {
+	ListRoles(ctx context.Context) ([]*Role, error)
+	GetRole(ctx context.Context, name string) (*Role, error)
+	SaveRole(ctx context.Context, role *Role) error
+	ListUserRoles(ctx context.Context, username string) ([]string, error)
+	ReplaceUserRoles(ctx context.Context, username string, roles []string, grantedBy string, grantedAt time.Time) error
+	PermissionsForRoles(ctx context.Context, roles []string) ([]Permission, error)
+	UserExists(ctx context.Context, username string) (bool, error)
+}
+
+// Authorizer answers whether a subject holds a permission.
+type Authorizer interface {
+	Can(ctx context.Context, subject Subject, permission Permission) (bool, error)
+}
+
+// Require returns ErrPermissionDenied unless the subject holds the permission.
+func Require(ctx context.Context, authorizer Authorizer, subject Subject, permission Permission) error {
+	allowed, err := OrDefault(authorizer).Can(ctx, subject, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+type adminOnlyAuthorizer struct{}
+
+func (adminOnlyAuthorizer) Can(_ context.Context, subject Subject, permission Permission) (bool, error) {
+	return strings.TrimSpace(subject.Username) != "" && subject.role() == RoleAdmin && subject.allows(permission), nil
+}
+
+// AdminOnly returns an authorizer that grants every permission to admins and
+// nothing to anyone else. It matches the behaviour before grants were stored.
+func AdminOnly() Authorizer {
+	return adminOnlyAuthorizer{}
+}
+
+// OrDefault returns the authorizer, or AdminOnly when it is nil.
+func OrDefault(authorizer Authorizer) Authorizer {
+	if authorizer == nil {
+		return AdminOnly()
+	}
+	return authorizer
+}
+
+// Service resolves permissions from stored role grants and manages those grants.
+type Service struct {
+	repo Repository
+}
+
+var _ Authorizer = (*Service)(nil)
+
+// NewService constructs a permissions service backed by the repository.
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Can reports whether the subject holds the permission through its built-in
+// role or any assigned custom role. Admins hold every permission unless their
+// account is restricted.
+func (s *Service) Can(ctx context.Context, subject Subject, permission Permission) (bool, error) {
+	if strings.TrimSpace(subject.Username) == "" || !subject.allows(permission) {
+		return false, nil
+	}
+	if subject.role() == RoleAdmin {
+		return true, nil
+	}
+	granted, err := s.EffectivePermissions(ctx, subject)
+	if err != nil {
+		return false, err
+	}
+	for _, candidate := range granted {
+		if candidate == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EffectivePermissions returns every permission the subject currently holds.
+func (s *Service) EffectivePermissions(ctx context.Context, subject Subject) ([]Permission, error) {
+	if strings.TrimSpace(subject.Username) == "" {
+		return []Permission{}, nil
+	}
+	if subject.role() == RoleAdmin {
+		return subject.withheld(All()), nil
+	}
+	if s == nil || s.repo == nil {
+		return []Permission{}, nil
+	}
+
+	roles, err := s.repo.ListUserRoles(ctx, subject.Username)
+	if err != nil {
+		return nil, err
+	}
+	if !subject.Suspended {
+		roles = append(roles, subject.role())
+	}
+	granted, err := s.repo.PermissionsForRoles(ctx, roles)
+	if err != nil {
+		return nil, err
+	}
+	return subject.withheld(granted), nil
+}
+
+// withheld drops the grants a restricted subject may not use.
+func (s Subject) withheld(granted []Permission) []Permission {
+	kept := make([]Permission, 0, len(granted))
+	for _, permission := range granted {
+		if s.allows(permission) {
+			kept = append(kept, permission)
+		}
+	}
+	return kept
+}
+
+// ListRoles returns every role with its grants, including the built-in roles.
+func (s *Service) ListRoles(ctx context.Context, actor Subject) ([]*Role, error) {
+	if err := s.requireRepository(); err != nil {
+		return nil, err
+	}
+	if err := Require(ctx, s, actor, RolesManage); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]*Role{}
+	for _, role := range stored {
+		if role != nil {
+			byName[role.Name] = role
+		}
+	}
+	for _, name := range []string{RoleModerator, RoleRegular} {
+		if _, ok := byName[name]; !ok {
+			byName[name] = &Role{Name: name, Permissions: []Permission{}}
+		}
+	}
+	byName[RoleAdmin] = &Role{Name: RoleAdmin, Description: "Holds every permission.", Permissions: All()}
+
+	roles := make([]*Role, 0, len(byName))
+	for _, role := range byName {
+		decorateRole(role)
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].BuiltIn != roles[j].BuiltIn {
+			return roles[i].BuiltIn
+		}
+		return roles[i].Name < roles[j].Name
+	})
+	return roles, nil
+}
+
+// SaveRole creates a custom role or replaces an existing role's grants.
+func (s *Service) SaveRole(ctx context.Context, actor Subject, update RoleUpdate, now time.Time) (*Role, error) {
+	if err := s.requireRepository(); err != nil {
+		return nil, err
+	}
+	if err := Require(ctx, s, actor, RolesManage); err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeRoleName(update.Name)
+	if err != nil {
+		return nil, err
+	}
+	if name == RoleAdmin {
+		return nil, ErrImmutableRole
+	}
+	granted, err := Normalize(update.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	role := &Role{
+		Name:        name,
+		Description: strings.TrimSpace(update.Description),
+		Permissions: granted,
+		UpdatedBy:   actor.Username,
+		UpdatedAt:   now,
+	}
+	if len(role.Description) > 200 {
+		return nil, ErrInvalidRole
+	}
+	if err := s.repo.SaveRole(ctx, role); err != nil {
+		return nil, err
+	}
+	decorateRole(role)
+	return role, nil
+}
+
+// UserRoles returns the custom roles assigned to an account.
+func (s *Service) UserRoles(ctx context.Context, actor Subject, username string) (*UserRoles, error) {
+	if err := s.requireRepository(); err != nil {
+		return nil, err
+	}
+	if err := Require(ctx, s, actor, RolesManage); err != nil {
+		return nil, err
+	}
+	if err := s.requireUser(ctx, username); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.repo.ListUserRoles(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	sort.Strings(roles)
+	return &UserRoles{Username: username, Roles: roles}, nil
+}
+
+// SetUserRoles replaces the custom roles assigned to an account. Built-in
+// roles follow the account's user type and cannot be assigned here.
+func (s *Service) SetUserRoles(ctx context.Context, actor Subject, username string, roles []string, now time.Time) (*UserRoles, error) {
+	if err := s.requireRepository(); err != nil {
+		return nil, err
+	}
+	if err := Require(ctx, s, actor, RolesManage); err != nil {
+		return nil, err
+	}
+	if err := s.requireUser(ctx, username); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(roles))
+	assigned := make([]string, 0, len(roles))
+	for _, raw := range roles {
+		name, err := normalizeRoleName(raw)
+		if err != nil {
+			return nil, err
+		}
+		if isBuiltInRole(name) {
+			return nil, ErrInvalidRole
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		if _, err := s.repo.GetRole(ctx, name); err != nil {
+			return nil, err
+		}
+		seen[name] = struct{}{}
+		assigned = append(assigned, name)
+	}
+	sort.Strings(assigned)
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	if err := s.repo.ReplaceUserRoles(ctx, username, assigned, actor.Username, now); err != nil {
+		return nil, err
+	}
+	return &UserRoles{Username: username, Roles: assigned}, nil
+}
+
+func (s *Service) requireRepository() error {
+	if s == nil || s.repo == nil {
+		return errors.New("permissions repository not configured")
+	}
+	return nil
+}
+
+func (s *Service) requireUser(ctx context.Context, username string) error {
+	if strings.TrimSpace(username) == "" {
+		return ErrUserNotFound
+	}
+	exists, err := s.repo.UserExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func normalizeRoleName(value string) (string, error) {
+	name := strings.ToUpper(strings.TrimSpace(value))
+	if !roleNamePattern.MatchString(name) {
+		return "", ErrInvalidRole
+	}
+	return name, nil
+}
+
+func isBuiltInRole(name string) bool {
+	switch name {
+	case RoleAdmin, RoleModerator, RoleRegular:
+		return true
+	default:
+		return false
+	}
+}
+
+func decorateRole(role *Role) {
+	role.BuiltIn = isBuiltInRole(role.Name)
+	role.Superuser = role.Name == RoleAdmin
+	if role.Permissions == nil {
+		role.Permissions = []Permission{}
+	}
+}
@@ -0,0 +1,234 @@
+package permissions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+	users "socialpredict/internal/domain/users"
+)
+
+type fakeRepository struct {
+	roles     map[string]*permissions.Role
+	userRoles map[string][]string
+	users     map[string]bool
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		roles: map[string]*permissions.Role{
+			permissions.RoleModerator: {Name: permissions.RoleModerator, Permissions: []permissions.Permission{permissions.TagsManage}},
+			"CMS_EDITOR":              {Name: "CMS_EDITOR", Permissions: []permissions.Permission{permissions.CMSEdit}},
+		},
+		userRoles: map[string][]string{},
+		users:     map[string]bool{"ada": true, "mod": true, "root": true},
+	}
+}
+
+func (f *fakeRepository) ListRoles(context.Context) ([]*permissions.Role, error) {
+	roles := make([]*permissions.Role, 0, len(f.roles))
+	for _, role := range f.roles {
+		copy := *role
+		roles = append(roles, &copy)
+	}
+	return roles, nil
+}
+
+func (f *fakeRepository) GetRole(_ context.Context, name string) (*permissions.Role, error) {
+	role, ok := f.roles[name]
+	if !ok {
+		return nil, permissions.ErrRoleNotFound
+	}
+	copy := *role
+	return &copy, nil
+}
+
+func (f *fakeRepository) SaveRole(_ context.Context, role *permissions.Role) error {
+	copy := *role
+	f.roles[role.Name] = &copy
+	return nil
+}
+
+func (f *fakeRepository) ListUserRoles(_ context.Context, username string) ([]string, error) {
+	return append([]string(nil), f.userRoles[username]...), nil
+}
+
+func (f *fakeRepository) ReplaceUserRoles(_ context.Context, username string, roles []string, _ string, _ time.Time) error {
+	f.userRoles[username] = append([]string(nil), roles...)
+	return nil
+}
+
+func (f *fakeRepository) PermissionsForRoles(_ context.Context, roles []string) ([]permissions.Permission, error) {
+	var granted []permissions.Permission
+	for _, name := range roles {
+		if role, ok := f.roles[name]; ok {
+			granted = append(granted, role.Permissions...)
+		}
+	}
+	return granted, nil
+}
+
+func (f *fakeRepository) UserExists(_ context.Context, username string) (bool, error) {
+	return f.users[username], nil
+}
+
+var admin = permissions.Subject{Username: "root", Role: permissions.RoleAdmin}
+
+func TestServiceCanResolvesBuiltInAndCustomRoles(t *testing.T) {
+	repo := newFakeRepository()
+	repo.userRoles["ada"] = []string{"CMS_EDITOR"}
+	svc := permissions.NewService(repo)
+	ctx := context.Background()
+
+	cases := []struct {
+		name       string
+		subject    permissions.Subject
+		permission permissions.Permission
+		want       bool
+	}{
+		{"admin holds everything", admin, permissions.RolesManage, true},
+		{"custom role grant", permissions.Subject{Username: "ada", Role: "REGULAR"}, permissions.CMSEdit, true},
+		{"custom role does not leak", permissions.Subject{Username: "ada", Role: "REGULAR"}, permissions.MarketsApprove, false},
+		{"built-in moderator grant", permissions.Subject{Username: "mod", Role: "MODERATOR"}, permissions.TagsManage, true},
+		{"suspended moderator loses built-in grant", permissions.Subject{Username: "mod", Role: "MODERATOR", Suspended: true}, permissions.TagsManage, false},
+		{"anonymous subject", permissions.Subject{Role: permissions.RoleAdmin}, permissions.CMSEdit, false},
+	}
+	for _, tc := range cases {
+		got, err := svc.Can(ctx, tc.subject, tc.permission)
+		if err != nil {
+			t.Fatalf("%s: Can returned error: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: Can = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestServiceCanWithholdsWriteGrantsFromSuspendedAccounts(t *testing.T) {
+	repo := newFakeRepository()
+	repo.roles[permissions.RoleModerator].Permissions = []permissions.Permission{permissions.MarketsApprove, permissions.EconomyView}
+	svc := permissions.NewService(repo)
+	ctx := context.Background()
+
+	moderator := &users.User{Username: "mod", UserType: string(users.UserTypeModerator), ModeratorStatus: users.ModeratorStatusActive}
+	if allowed, err := svc.Can(ctx, moderator.PermissionSubject(), permissions.MarketsApprove); err != nil || !allowed {
+		t.Fatalf("active moderator Can(markets.approve) = %v, %v; want true", allowed, err)
+	}
+
+	// The moderator role itself is untouched; only the account is suspended.
+	moderator.AccountStatus = users.AccountStatusSuspended
+	if allowed, err := svc.Can(ctx, moderator.PermissionSubject(), permissions.MarketsApprove); err != nil || allowed {
+		t.Fatalf("suspended moderator Can(markets.approve) = %v, %v; want false", allowed, err)
+	}
+	if allowed, _ := svc.Can(ctx, moderator.PermissionSubject(), permissions.EconomyView); !allowed {
+		t.Fatalf("suspended moderator should keep read-only economy.view")
+	}
+
+	banned := &users.User{Username: "root", UserType: string(users.UserTypeAdmin), AccountStatus: users.AccountStatusBanned}
+	granted, err := svc.EffectivePermissions(ctx, banned.PermissionSubject())
+	if err != nil {
+		t.Fatalf("EffectivePermissions returned error: %v", err)
+	}
+	if len(granted) != 1 || granted[0] != permissions.EconomyView {
+		t.Fatalf("banned admin permissions = %v, want only economy.view", granted)
+	}
+	if err := permissions.Require(ctx, nil, banned.PermissionSubject(), permissions.UsersManage); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("default authorizer error = %v, want ErrPermissionDenied for a banned admin", err)
+	}
+}
+
+func TestAdminOnlyAuthorizerMatchesLegacyAdminCheck(t *testing.T) {
+	ctx := context.Background()
+	if err := permissions.Require(ctx, nil, permissions.Subject{Username: "root", Role: "admin"}, permissions.CMSEdit); err != nil {
+		t.Fatalf("admin should pass default authorizer: %v", err)
+	}
+	if err := permissions.Require(ctx, nil, permissions.Subject{Username: "mod", Role: "MODERATOR"}, permissions.CMSEdit); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("moderator error = %v, want ErrPermissionDenied", err)
+	}
+}
+
+func TestServiceSaveRoleValidatesGrants(t *testing.T) {
+	repo := newFakeRepository()
+	svc := permissions.NewService(repo)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 25, 9, 0, 0, 0, time.UTC)
+
+	if _, err := svc.SaveRole(ctx, permissions.Subject{Username: "ada", Role: "REGULAR"}, permissions.RoleUpdate{Name: "X_ROLE"}, now); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("non-admin error = %v, want ErrPermissionDenied", err)
+	}
+	if _, err := svc.SaveRole(ctx, admin, permissions.RoleUpdate{Name: "admin", Permissions: []string{"cms.edit"}}, now); !errors.Is(err, permissions.ErrImmutableRole) {
+		t.Fatalf("admin role error = %v, want ErrImmutableRole", err)
+	}
+	if _, err := svc.SaveRole(ctx, admin, permissions.RoleUpdate{Name: "TAGGER", Permissions: []string{"tags.delete"}}, now); !errors.Is(err, permissions.ErrUnknownPermission) {
+		t.Fatalf("unknown permission error = %v, want ErrUnknownPermission", err)
+	}
+	if _, err := svc.SaveRole(ctx, admin, permissions.RoleUpdate{Name: "bad name"}, now); !errors.Is(err, permissions.ErrInvalidRole) {
+		t.Fatalf("bad name error = %v, want ErrInvalidRole", err)
+	}
+
+	role, err := svc.SaveRole(ctx, admin, permissions.RoleUpdate{Name: "tagger", Description: " Tag curators ", Permissions: []string{"tags.manage", "TAGS.MANAGE", "markets.approve"}}, now)
+	if err != nil {
+		t.Fatalf("SaveRole returned error: %v", err)
+	}
+	if role.Name != "TAGGER" || role.Description != "Tag curators" || role.BuiltIn || role.UpdatedBy != "root" {
+		t.Fatalf("unexpected role: %+v", role)
+	}
+	if len(role.Permissions) != 2 || role.Permissions[0] != permissions.MarketsApprove || role.Permissions[1] != permissions.TagsManage {
+		t.Fatalf("unexpected grants: %v", role.Permissions)
+	}
+}
+
+func TestServiceListRolesIncludesBuiltIns(t *testing.T) {
+	svc := permissions.NewService(newFakeRepository())
+
+	roles, err := svc.ListRoles(context.Background(), admin)
+	if err != nil {
+		t.Fatalf("ListRoles returned error: %v", err)
+	}
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	want := []string{"ADMIN", "MODERATOR", "REGULAR", "CMS_EDITOR"}
+	if len(names) != len(want) {
+		t.Fatalf("roles = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("roles = %v, want %v", names, want)
+		}
+	}
+	if !roles[0].Superuser || len(roles[0].Permissions) != len(permissions.All()) {
+		t.Fatalf("ADMIN should list every permission: %+v", roles[0])
+	}
+}
+
+func TestServiceSetUserRolesAssignsOnlyCustomRoles(t *testing.T) {
+	repo := newFakeRepository()
+	svc := permissions.NewService(repo)
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, err := svc.SetUserRoles(ctx, admin, "ada", []string{"MODERATOR"}, now); !errors.Is(err, permissions.ErrInvalidRole) {
+		t.Fatalf("built-in role error = %v, want ErrInvalidRole", err)
+	}
+	if _, err := svc.SetUserRoles(ctx, admin, "ada", []string{"GHOST_ROLE"}, now); !errors.Is(err, permissions.ErrRoleNotFound) {
+		t.Fatalf("missing role error = %v, want ErrRoleNotFound", err)
+	}
+	if _, err := svc.SetUserRoles(ctx, admin, "nobody", []string{"CMS_EDITOR"}, now); !errors.Is(err, permissions.ErrUserNotFound) {
+		t.Fatalf("missing user error = %v, want ErrUserNotFound", err)
+	}
+
+	assigned, err := svc.SetUserRoles(ctx, admin, "ada", []string{"cms_editor", "CMS_EDITOR"}, now)
+	if err != nil {
+		t.Fatalf("SetUserRoles returned error: %v", err)
+	}
+	if len(assigned.Roles) != 1 || assigned.Roles[0] != "CMS_EDITOR" {
+		t.Fatalf("unexpected assignment: %+v", assigned)
+	}
+	if allowed, _ := svc.Can(ctx, permissions.Subject{Username: "ada"}, permissions.CMSEdit); !allowed {
+		t.Fatalf("assigned role should grant cms.edit")
+	}
+}
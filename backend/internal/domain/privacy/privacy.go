@@ -0,0 +1,50 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// AnonymousUsername replaces the name of an anonymous trader in public bet
+// and position lists. Usernames are lower case, so it never matches one.
+const AnonymousUsername = "Anonymous"
+
+var (
+	// ErrInvalidInput indicates a missing username.
+	ErrInvalidInput = errors.New("invalid privacy request")
+	// ErrPortfolioHidden indicates the owner has hidden their portfolio from
+	// the viewer.
+	ErrPortfolioHidden = errors.New("portfolio is hidden")
+)
+
+// Settings are one user's trading privacy choices. HidePortfolio keeps the
+// portfolio to its owner and admins; AnonymousTrades shows the owner's bets
+// and positions as AnonymousUsername to everyone else. Neither setting
+// changes probabilities, volumes, or admin views.
+type Settings struct {
+	HidePortfolio   bool
+	AnonymousTrades bool
+	UpdatedAt       time.Time
+}
+
+// Mask is the set of traders whose names a viewer may not see.
+type Mask map[string]bool
+
+// Name returns username, or AnonymousUsername when it is masked.
+func (m Mask) Name(username string) string {
+	if m[username] {
+		return AnonymousUsername
+	}
+	return username
+}
+
+// Repository persists privacy settings.
+type Repository interface {
+	// GetSettings returns the user's settings, or the zero value when the
+	// user never changed them.
+	GetSettings(ctx context.Context, username string) (Settings, error)
+	SaveSettings(ctx context.Context, username string, settings Settings) error
+	// ListAnonymousTraders returns which of usernames trade anonymously.
+	ListAnonymousTraders(ctx context.Context, usernames []string) ([]string, error)
+}
@@ -0,0 +1,138 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+)
+
+// Service stores trading privacy settings and decides what each viewer may
+// see. Holders of users.manage see through every setting.
+type Service struct {
+	repo       Repository
+	authorizer permissions.Authorizer
+	now        func() time.Time
+}
+
+// NewService constructs a privacy service.
+func NewService(repo Repository, authorizer permissions.Authorizer, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{repo: repo, authorizer: authorizer, now: now}
+}
+
+// Settings returns the user's privacy settings.
+func (s *Service) Settings(ctx context.Context, username string) (Settings, error) {
+	if err := s.ready(username); err != nil {
+		return Settings{}, err
+	}
+	return s.repo.GetSettings(ctx, username)
+}
+
+// UpdateSettings replaces the user's privacy settings.
+func (s *Service) UpdateSettings(ctx context.Context, username string, settings Settings) (Settings, error) {
+	if err := s.ready(username); err != nil {
+		return Settings{}, err
+	}
+	settings.UpdatedAt = s.now().UTC()
+	if err := s.repo.SaveSettings(ctx, username, settings); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
+
+// AnonymousTraders returns which of usernames trade anonymously, for
+// displays that look the same to every viewer such as live streams.
+func (s *Service) AnonymousTraders(ctx context.Context, usernames []string) (map[string]bool, error) {
+	if s == nil || s.repo == nil {
+		return nil, errors.New("privacy service unavailable")
+	}
+	anonymous := make(map[string]bool)
+	if len(usernames) == 0 {
+		return anonymous, nil
+	}
+	names, err := s.repo.ListAnonymousTraders(ctx, unique(usernames))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		anonymous[name] = true
+	}
+	return anonymous, nil
+}
+
+// Mask returns which of usernames viewer must see as anonymous. Viewers
+// always see themselves, and holders of users.manage see everyone; an empty
+// subject is a signed-out visitor.
+func (s *Service) Mask(ctx context.Context, viewer permissions.Subject, usernames []string) (Mask, error) {
+	seesAll, err := s.seesAll(ctx, viewer)
+	if err != nil || seesAll {
+		return Mask{}, err
+	}
+	anonymous, err := s.AnonymousTraders(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	delete(anonymous, viewer.Username)
+	return Mask(anonymous), nil
+}
+
+// CheckPortfolio returns ErrPortfolioHidden when username hid their
+// portfolio and viewer is neither them nor a holder of users.manage.
+func (s *Service) CheckPortfolio(ctx context.Context, viewer permissions.Subject, username string) error {
+	if err := s.ready(username); err != nil {
+		return err
+	}
+	if viewer.Username == username {
+		return nil
+	}
+	settings, err := s.repo.GetSettings(ctx, username)
+	if err != nil || !settings.HidePortfolio {
+		return err
+	}
+	seesAll, err := s.seesAll(ctx, viewer)
+	if err != nil {
+		return err
+	}
+	if !seesAll {
+		return ErrPortfolioHidden
+	}
+	return nil
+}
+
+func (s *Service) seesAll(ctx context.Context, viewer permissions.Subject) (bool, error) {
+	if s == nil || s.repo == nil {
+		return false, errors.New("privacy service unavailable")
+	}
+	if strings.TrimSpace(viewer.Username) == "" {
+		return false, nil
+	}
+	return permissions.OrDefault(s.authorizer).Can(ctx, viewer, permissions.UsersManage)
+}
+
+func (s *Service) ready(username string) error {
+	if s == nil || s.repo == nil {
+		return errors.New("privacy service unavailable")
+	}
+	if strings.TrimSpace(username) == "" {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+func unique(usernames []string) []string {
+	seen := make(map[string]bool, len(usernames))
+	out := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		out = append(out, username)
+	}
+	return out
+}
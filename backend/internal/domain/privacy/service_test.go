@@ -0,0 +1,97 @@
+package privacy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+	"socialpredict/internal/domain/privacy"
+)
+
+type memoryRepo struct {
+	settings map[string]privacy.Settings
+}
+
+func (m *memoryRepo) GetSettings(_ context.Context, username string) (privacy.Settings, error) {
+	return m.settings[username], nil
+}
+
+func (m *memoryRepo) SaveSettings(_ context.Context, username string, settings privacy.Settings) error {
+	m.settings[username] = settings
+	return nil
+}
+
+func (m *memoryRepo) ListAnonymousTraders(_ context.Context, usernames []string) ([]string, error) {
+	var out []string
+	for _, username := range usernames {
+		if m.settings[username].AnonymousTrades {
+			out = append(out, username)
+		}
+	}
+	return out, nil
+}
+
+var savedAt = time.Date(2026, 7, 5, 9, 0, 0, 0, time.UTC)
+
+func newService() *privacy.Service {
+	repo := &memoryRepo{settings: map[string]privacy.Settings{}}
+	return privacy.NewService(repo, nil, func() time.Time { return savedAt })
+}
+
+func TestMaskHidesAnonymousTradersFromOthersOnly(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	if _, err := svc.UpdateSettings(ctx, "alice", privacy.Settings{AnonymousTrades: true}); err != nil {
+		t.Fatalf("UpdateSettings returned error: %v", err)
+	}
+	traders := []string{"alice", "bob", "alice"}
+
+	for name, viewer := range map[string]permissions.Subject{
+		"signed out": {},
+		"other user": {Username: "bob", Role: "REGULAR"},
+	} {
+		mask, err := svc.Mask(ctx, viewer, traders)
+		if err != nil || mask.Name("alice") != privacy.AnonymousUsername || mask.Name("bob") != "bob" {
+			t.Fatalf("%s: unexpected mask %v, %v", name, mask, err)
+		}
+	}
+	for name, viewer := range map[string]permissions.Subject{
+		"self":  {Username: "alice", Role: "REGULAR"},
+		"admin": {Username: "root", Role: permissions.RoleAdmin},
+	} {
+		mask, err := svc.Mask(ctx, viewer, traders)
+		if err != nil || mask.Name("alice") != "alice" {
+			t.Fatalf("%s: expected alice unmasked, got %v, %v", name, mask, err)
+		}
+	}
+}
+
+func TestCheckPortfolioRespectsHidePortfolio(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	settings, err := svc.UpdateSettings(ctx, "alice", privacy.Settings{HidePortfolio: true})
+	if err != nil || !settings.UpdatedAt.Equal(savedAt) {
+		t.Fatalf("UpdateSettings = %+v, %v", settings, err)
+	}
+
+	if err := svc.CheckPortfolio(ctx, permissions.Subject{Username: "bob"}, "alice"); !errors.Is(err, privacy.ErrPortfolioHidden) {
+		t.Fatalf("expected ErrPortfolioHidden for another user, got %v", err)
+	}
+	if err := svc.CheckPortfolio(ctx, permissions.Subject{}, "alice"); !errors.Is(err, privacy.ErrPortfolioHidden) {
+		t.Fatalf("expected ErrPortfolioHidden when signed out, got %v", err)
+	}
+	if err := svc.CheckPortfolio(ctx, permissions.Subject{Username: "alice"}, "alice"); err != nil {
+		t.Fatalf("expected the owner to see their portfolio, got %v", err)
+	}
+	if err := svc.CheckPortfolio(ctx, permissions.Subject{Username: "root", Role: permissions.RoleAdmin}, "alice"); err != nil {
+		t.Fatalf("expected an admin to see the portfolio, got %v", err)
+	}
+	if err := svc.CheckPortfolio(ctx, permissions.Subject{}, "bob"); err != nil {
+		t.Fatalf("expected a default portfolio to be public, got %v", err)
+	}
+	if _, err := svc.Settings(ctx, " "); !errors.Is(err, privacy.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dcomments "socialpredict/internal/domain/comments"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+)
+
+// TargetType names what kind of content a report is about.
+type TargetType string
+
+const (
+	TargetMarket  TargetType = "market"
+	TargetComment TargetType = "comment"
+	TargetUser    TargetType = "user"
+)
+
+// Category is the reporter's reason for flagging content.
+type Category string
+
+const (
+	CategorySpam          Category = "spam"
+	CategoryHarassment    Category = "harassment"
+	CategoryMisleading    Category = "misleading"
+	CategoryInappropriate Category = "inappropriate"
+	CategoryOther         Category = "other"
+)
+
+// Categories lists every report category in display order.
+func Categories() []Category {
+	return []Category{CategorySpam, CategoryHarassment, CategoryMisleading, CategoryInappropriate, CategoryOther}
+}
+
+// Status is where a report is in review.
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusDismissed Status = "dismissed"
+	StatusActioned  Status = "actioned"
+)
+
+// Action is what a moderator did about reported content.
+type Action string
+
+const (
+	// ActionDismiss closes the reports without touching the content.
+	ActionDismiss Action = "dismiss"
+	// ActionHideContent hides a reported comment.
+	ActionHideContent Action = "hide_content"
+	// ActionYankMarket resolves a reported market N/A, refunding every bet.
+	ActionYankMarket Action = "yank_market"
+	// ActionSuspendUser suspends the reported user, or the author of the
+	// reported comment or market.
+	ActionSuspendUser Action = "suspend_user"
+)
+
+var (
+	// ErrInvalidInput indicates an unknown target type, category, status, or action, or a malformed request.
+	ErrInvalidInput = errors.New("invalid report request")
+	// ErrTargetNotFound indicates that the reported market, comment, or user does not exist.
+	ErrTargetNotFound = errors.New("reported content not found")
+	// ErrActionNotApplicable indicates an action that does not fit the target, such as hiding a market.
+	ErrActionNotApplicable = errors.New("action does not apply to this content")
+)
+
+// Target identifies reported content and, once resolved, describes it for
+// moderators.
+type Target struct {
+	Type TargetType
+	ID   string
+	// Label is the market question, the comment text, or the username.
+	Label string
+	// OwnerUsername is the account a suspension would apply to.
+	OwnerUsername string
+	// MarketID links the target to a market page when there is one.
+	MarketID      int64
+	MarketGroupID int64
+}
+
+// Key combines the target type and ID, for example "comment:12".
+func (t Target) Key() string {
+	return string(t.Type) + ":" + t.ID
+}
+
+// Report is one user's flag on a target.
+type Report struct {
+	ID               int64
+	TargetType       TargetType
+	TargetID         string
+	ReporterUsername string
+	Category         Category
+	Details          string
+	Status           Status
+	Outcome          Action
+	ReviewedBy       string
+	ReviewedAt       *time.Time
+	CreatedAt        time.Time
+}
+
+// Target returns the reported content's identity.
+func (r *Report) Target() Target {
+	return Target{Type: r.TargetType, ID: r.TargetID}
+}
+
+// QueueItem aggregates the reports on one target.
+type QueueItem struct {
+	Target          Target
+	ReportCount     int
+	Categories      map[Category]int
+	FirstReportedAt time.Time
+	LastReportedAt  time.Time
+	// Reports holds the most recent reports, newest first.
+	Reports []*Report
+}
+
+// ModerationAction records one decision on reported content.
+type ModerationAction struct {
+	ID            int64
+	TargetType    TargetType
+	TargetID      string
+	Action        Action
+	ActorUsername string
+	Reason        string
+	ReportsClosed int
+	CreatedAt     time.Time
+}
+
+// ActionInput is a moderator's decision on a target.
+type ActionInput struct {
+	TargetType TargetType
+	TargetID   string
+	Action     Action
+	Reason     string
+	// ExpiresAt optionally ends a suspension.
+	ExpiresAt *time.Time
+}
+
+// Repository persists reports and moderation actions.
+type Repository interface {
+	// FindOpenReport returns the reporter's open report on target, or nil.
+	FindOpenReport(ctx context.Context, reporter string, target Target) (*Report, error)
+	CreateReport(ctx context.Context, report *Report) error
+	ListReporterReports(ctx context.Context, reporter string, limit, offset int) ([]*Report, int64, error)
+	// ListQueue groups reports with status by target, most reported first,
+	// keeping up to sampleSize of the newest reports on each item.
+	ListQueue(ctx context.Context, status Status, limit, offset, sampleSize int) ([]*QueueItem, int64, error)
+	// CloseReports marks the target's open reports with status and the
+	// action's outcome, stores the action with the number closed, and records
+	// a ReportResolved event per closed report, all in one transaction.
+	CloseReports(ctx context.Context, action *ModerationAction, status Status) error
+	ListActions(ctx context.Context, target Target, limit, offset int) ([]*ModerationAction, error)
+}
+
+// Markets resolves reported markets and yanks them.
+type Markets interface {
+	GetMarket(ctx context.Context, id int64) (*dmarkets.Market, error)
+	ResolveMarket(ctx context.Context, marketID int64, resolution string, username string) error
+}
+
+// Comments resolves reported comments and hides them.
+type Comments interface {
+	Get(ctx context.Context, id int64) (*dcomments.Comment, error)
+	Hide(ctx context.Context, actor permissions.Subject, id int64, reason string) (*dcomments.Comment, error)
+}
+
+// Users resolves reported profiles and suspends accounts.
+type Users interface {
+	GetUser(ctx context.Context, username string) (*dusers.User, error)
+	SetAccountStatus(ctx context.Context, actor *dusers.User, username string, change dusers.AccountStatusChange) (*dusers.User, error)
+}
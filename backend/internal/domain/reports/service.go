@@ -0,0 +1,326 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	dcomments "socialpredict/internal/domain/comments"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	queueSampleSize  = 10
+	maxDetailsLength = 1000
+	maxReasonLength  = 500
+	maxLabelLength   = 200
+)
+
+// Service takes user reports on markets, comments, and profiles and lets
+// moderators work them as a queue. Every decision is recorded with its actor
+// and reason, and reporters hear back through a ReportResolved event.
+type Service struct {
+	repo       Repository
+	markets    Markets
+	comments   Comments
+	users      Users
+	authorizer permissions.Authorizer
+	now        func() time.Time
+}
+
+// NewService constructs a reports service.
+func NewService(repo Repository, markets Markets, comments Comments, users Users, authorizer permissions.Authorizer, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{repo: repo, markets: markets, comments: comments, users: users, authorizer: authorizer, now: now}
+}
+
+// Submit files a report. A reporter who already has an open report on the
+// same content gets that report back instead of a duplicate, with created
+// false.
+func (s *Service) Submit(ctx context.Context, reporter string, targetType TargetType, targetID string, category Category, details string) (*Report, bool, error) {
+	if err := s.ready(); err != nil {
+		return nil, false, err
+	}
+	details = strings.TrimSpace(details)
+	if strings.TrimSpace(reporter) == "" || !knownCategory(category) || utf8.RuneCountInString(details) > maxDetailsLength {
+		return nil, false, ErrInvalidInput
+	}
+	if category == CategoryOther && details == "" {
+		return nil, false, ErrInvalidInput
+	}
+	target, err := s.resolve(ctx, targetType, targetID)
+	if err != nil {
+		return nil, false, err
+	}
+	if target.Type == TargetUser && target.ID == reporter {
+		return nil, false, ErrInvalidInput
+	}
+
+	existing, err := s.repo.FindOpenReport(ctx, reporter, target)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+	report := &Report{
+		TargetType:       target.Type,
+		TargetID:         target.ID,
+		ReporterUsername: reporter,
+		Category:         category,
+		Details:          details,
+		Status:           StatusOpen,
+		CreatedAt:        s.now().UTC(),
+	}
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, false, err
+	}
+	return report, true, nil
+}
+
+// MyReports returns the reporter's reports newest first, with their outcome.
+func (s *Service) MyReports(ctx context.Context, reporter string, limit, offset int) ([]*Report, int64, error) {
+	if err := s.ready(); err != nil {
+		return nil, 0, err
+	}
+	if strings.TrimSpace(reporter) == "" {
+		return nil, 0, ErrInvalidInput
+	}
+	limit, offset = page(limit, offset)
+	return s.repo.ListReporterReports(ctx, reporter, limit, offset)
+}
+
+// Queue returns reported content grouped by target, most reported first.
+// Requires reports.review.
+func (s *Service) Queue(ctx context.Context, actor permissions.Subject, status Status, limit, offset int) ([]*QueueItem, int64, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, 0, err
+	}
+	if status == "" {
+		status = StatusOpen
+	}
+	if !knownStatus(status) {
+		return nil, 0, ErrInvalidInput
+	}
+	limit, offset = page(limit, offset)
+	items, total, err := s.repo.ListQueue(ctx, status, limit, offset, queueSampleSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, item := range items {
+		// Content removed since it was reported still shows in the queue.
+		target, err := s.resolve(ctx, item.Target.Type, item.Target.ID)
+		if err != nil && !errors.Is(err, ErrTargetNotFound) {
+			return nil, 0, err
+		}
+		if err == nil {
+			item.Target = target
+		}
+	}
+	return items, total, nil
+}
+
+// Act carries out a moderator's decision on reported content and closes its
+// open reports. Requires reports.review; hiding, yanking, and suspending also
+// need the permission the underlying change requires.
+func (s *Service) Act(ctx context.Context, actor *dusers.User, input ActionInput) (*ModerationAction, error) {
+	if actor == nil {
+		return nil, permissions.ErrPermissionDenied
+	}
+	if err := s.require(ctx, actor.PermissionSubject()); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxReasonLength {
+		return nil, ErrInvalidInput
+	}
+	target, err := s.resolve(ctx, input.TargetType, input.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+
+	status := StatusActioned
+	switch input.Action {
+	case ActionDismiss:
+		status = StatusDismissed
+	case ActionHideContent:
+		if target.Type != TargetComment {
+			return nil, ErrActionNotApplicable
+		}
+		id, _ := strconv.ParseInt(target.ID, 10, 64)
+		if _, err := s.comments.Hide(ctx, actor.PermissionSubject(), id, reason); err != nil {
+			return nil, err
+		}
+	case ActionYankMarket:
+		if target.Type != TargetMarket {
+			return nil, ErrActionNotApplicable
+		}
+		if err := s.markets.ResolveMarket(ctx, target.MarketID, "N/A", actor.Username); err != nil {
+			return nil, err
+		}
+	case ActionSuspendUser:
+		if target.OwnerUsername == "" {
+			return nil, ErrActionNotApplicable
+		}
+		change := dusers.AccountStatusChange{Status: dusers.AccountStatusSuspended, Reason: reason, ExpiresAt: input.ExpiresAt, Now: now}
+		if _, err := s.users.SetAccountStatus(ctx, actor, target.OwnerUsername, change); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidInput
+	}
+
+	action := &ModerationAction{
+		TargetType:    target.Type,
+		TargetID:      target.ID,
+		Action:        input.Action,
+		ActorUsername: actor.Username,
+		Reason:        reason,
+		CreatedAt:     now,
+	}
+	if err := s.repo.CloseReports(ctx, action, status); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// Actions returns the moderation history of one target, newest first.
+// Requires reports.review.
+func (s *Service) Actions(ctx context.Context, actor permissions.Subject, targetType TargetType, targetID string, limit, offset int) ([]*ModerationAction, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	target := Target{Type: targetType, ID: strings.TrimSpace(targetID)}
+	if !knownTargetType(target.Type) || target.ID == "" {
+		return nil, ErrInvalidInput
+	}
+	limit, offset = page(limit, offset)
+	return s.repo.ListActions(ctx, target, limit, offset)
+}
+
+// resolve checks that the target exists and describes it.
+func (s *Service) resolve(ctx context.Context, targetType TargetType, targetID string) (Target, error) {
+	target := Target{Type: targetType, ID: strings.TrimSpace(targetID)}
+	switch targetType {
+	case TargetMarket:
+		id, err := strconv.ParseInt(target.ID, 10, 64)
+		if err != nil || id <= 0 {
+			return Target{}, ErrInvalidInput
+		}
+		market, err := s.markets.GetMarket(ctx, id)
+		if errors.Is(err, dmarkets.ErrMarketNotFound) {
+			return Target{}, ErrTargetNotFound
+		}
+		if err != nil {
+			return Target{}, err
+		}
+		target.ID = strconv.FormatInt(market.ID, 10)
+		target.Label = market.QuestionTitle
+		target.OwnerUsername = market.CreatorUsername
+		target.MarketID = market.ID
+	case TargetComment:
+		id, err := strconv.ParseInt(target.ID, 10, 64)
+		if err != nil || id <= 0 {
+			return Target{}, ErrInvalidInput
+		}
+		comment, err := s.comments.Get(ctx, id)
+		if errors.Is(err, dcomments.ErrCommentNotFound) {
+			return Target{}, ErrTargetNotFound
+		}
+		if err != nil {
+			return Target{}, err
+		}
+		target.ID = strconv.FormatInt(comment.ID, 10)
+		target.Label = truncate(comment.Body)
+		target.OwnerUsername = comment.Username
+		target.MarketID = comment.MarketID
+		target.MarketGroupID = comment.MarketGroupID
+	case TargetUser:
+		if target.ID == "" {
+			return Target{}, ErrInvalidInput
+		}
+		user, err := s.users.GetUser(ctx, target.ID)
+		if errors.Is(err, dusers.ErrUserNotFound) {
+			return Target{}, ErrTargetNotFound
+		}
+		if err != nil {
+			return Target{}, err
+		}
+		target.Label = user.DisplayName
+		target.OwnerUsername = user.Username
+	default:
+		return Target{}, ErrInvalidInput
+	}
+	return target, nil
+}
+
+func (s *Service) require(ctx context.Context, actor permissions.Subject) error {
+	if err := s.ready(); err != nil {
+		return err
+	}
+	return permissions.Require(ctx, s.authorizer, actor, permissions.ReportsReview)
+}
+
+func (s *Service) ready() error {
+	if s == nil || s.repo == nil || s.markets == nil || s.comments == nil || s.users == nil {
+		return errors.New("reports service unavailable")
+	}
+	return nil
+}
+
+func page(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func truncate(text string) string {
+	if utf8.RuneCountInString(text) <= maxLabelLength {
+		return text
+	}
+	return string([]rune(text)[:maxLabelLength]) + "…"
+}
+
+func knownCategory(category Category) bool {
+	for _, known := range Categories() {
+		if known == category {
+			return true
+		}
+	}
+	return false
+}
+
+func knownStatus(status Status) bool {
+	switch status {
+	case StatusOpen, StatusDismissed, StatusActioned:
+		return true
+	default:
+		return false
+	}
+}
+
+func knownTargetType(targetType TargetType) bool {
+	switch targetType {
+	case TargetMarket, TargetComment, TargetUser:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,251 @@
+package reports_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dcomments "socialpredict/internal/domain/comments"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	"socialpredict/internal/domain/reports"
+	dusers "socialpredict/internal/domain/users"
+)
+
+type memoryRepo struct {
+	reports []*reports.Report
+	actions []*reports.ModerationAction
+}
+
+func (m *memoryRepo) FindOpenReport(_ context.Context, reporter string, target reports.Target) (*reports.Report, error) {
+	for _, report := range m.reports {
+		if report.ReporterUsername == reporter && report.Target().Key() == target.Key() && report.Status == reports.StatusOpen {
+			return report, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryRepo) CreateReport(_ context.Context, report *reports.Report) error {
+	report.ID = int64(len(m.reports) + 1)
+	m.reports = append(m.reports, report)
+	return nil
+}
+
+func (m *memoryRepo) ListReporterReports(_ context.Context, reporter string, _, _ int) ([]*reports.Report, int64, error) {
+	var out []*reports.Report
+	for _, report := range m.reports {
+		if report.ReporterUsername == reporter {
+			out = append(out, report)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *memoryRepo) ListQueue(_ context.Context, status reports.Status, _, _, _ int) ([]*reports.QueueItem, int64, error) {
+	byKey := map[string]*reports.QueueItem{}
+	var items []*reports.QueueItem
+	for _, report := range m.reports {
+		if report.Status != status {
+			continue
+		}
+		item := byKey[report.Target().Key()]
+		if item == nil {
+			item = &reports.QueueItem{Target: report.Target(), Categories: map[reports.Category]int{}}
+			byKey[report.Target().Key()] = item
+			items = append(items, item)
+		}
+		item.ReportCount++
+		item.Categories[report.Category]++
+	}
+	return items, int64(len(items)), nil
+}
+
+func (m *memoryRepo) CloseReports(_ context.Context, action *reports.ModerationAction, status reports.Status) error {
+	key := reports.Target{Type: action.TargetType, ID: action.TargetID}.Key()
+	for _, report := range m.reports {
+		if report.Target().Key() == key && report.Status == reports.StatusOpen {
+			report.Status = status
+			report.Outcome = action.Action
+			action.ReportsClosed++
+		}
+	}
+	action.ID = int64(len(m.actions) + 1)
+	m.actions = append(m.actions, action)
+	return nil
+}
+
+func (m *memoryRepo) ListActions(context.Context, reports.Target, int, int) ([]*reports.ModerationAction, error) {
+	return m.actions, nil
+}
+
+type fakeMarkets struct{ yanked []int64 }
+
+func (f *fakeMarkets) GetMarket(_ context.Context, id int64) (*dmarkets.Market, error) {
+	if id != 7 {
+		return nil, dmarkets.ErrMarketNotFound
+	}
+	return &dmarkets.Market{ID: 7, QuestionTitle: "Will it rain?", CreatorUsername: "carol"}, nil
+}
+
+func (f *fakeMarkets) ResolveMarket(_ context.Context, id int64, resolution string, _ string) error {
+	if resolution != "N/A" {
+		return errors.New("unexpected resolution")
+	}
+	f.yanked = append(f.yanked, id)
+	return nil
+}
+
+type fakeComments struct{ hidden map[int64]string }
+
+func (f *fakeComments) Get(_ context.Context, id int64) (*dcomments.Comment, error) {
+	if id != 3 {
+		return nil, dcomments.ErrCommentNotFound
+	}
+	return &dcomments.Comment{ID: 3, MarketID: 7, Username: "dave", Body: "buy my course"}, nil
+}
+
+func (f *fakeComments) Hide(_ context.Context, actor permissions.Subject, id int64, reason string) (*dcomments.Comment, error) {
+	if actor.Role != "ADMIN" && actor.Role != "MODERATOR" {
+		return nil, permissions.ErrPermissionDenied
+	}
+	f.hidden[id] = reason
+	return &dcomments.Comment{ID: id}, nil
+}
+
+type fakeUsers struct{ suspended map[string]string }
+
+func (f *fakeUsers) GetUser(_ context.Context, username string) (*dusers.User, error) {
+	switch username {
+	case "alice", "dave", "carol":
+		return &dusers.User{Username: username, DisplayName: username}, nil
+	}
+	return nil, dusers.ErrUserNotFound
+}
+
+func (f *fakeUsers) SetAccountStatus(_ context.Context, actor *dusers.User, username string, change dusers.AccountStatusChange) (*dusers.User, error) {
+	if actor.UserType != "ADMIN" {
+		return nil, permissions.ErrPermissionDenied
+	}
+	f.suspended[username] = change.Reason
+	return &dusers.User{Username: username}, nil
+}
+
+type reviewers map[string]bool
+
+func (r reviewers) Can(_ context.Context, subject permissions.Subject, permission permissions.Permission) (bool, error) {
+	return permission == permissions.ReportsReview && r[subject.Username], nil
+}
+
+var (
+	moderator = &dusers.User{Username: "mod", UserType: "MODERATOR"}
+	admin     = &dusers.User{Username: "root", UserType: "ADMIN"}
+)
+
+func newService() (*reports.Service, *memoryRepo, *fakeMarkets, *fakeComments, *fakeUsers) {
+	repo := &memoryRepo{}
+	markets := &fakeMarkets{}
+	comments := &fakeComments{hidden: map[int64]string{}}
+	users := &fakeUsers{suspended: map[string]string{}}
+	now := func() time.Time { return time.Date(2026, 7, 2, 9, 0, 0, 0, time.UTC) }
+	return reports.NewService(repo, markets, comments, users, reviewers{"mod": true, "root": true}, now), repo, markets, comments, users
+}
+
+func TestSubmitDeduplicatesOpenReportsAndValidates(t *testing.T) {
+	svc, repo, _, _, _ := newService()
+	ctx := context.Background()
+
+	first, created, err := svc.Submit(ctx, "alice", reports.TargetComment, "3", reports.CategorySpam, "")
+	if err != nil || !created || first.Status != reports.StatusOpen {
+		t.Fatalf("Submit = %+v, %v, %v", first, created, err)
+	}
+	again, created, err := svc.Submit(ctx, "alice", reports.TargetComment, " 3 ", reports.CategoryHarassment, "")
+	if err != nil || created || again.ID != first.ID || len(repo.reports) != 1 {
+		t.Fatalf("expected the open report back, got %+v, %v, %v", again, created, err)
+	}
+
+	tests := []struct {
+		name       string
+		targetType reports.TargetType
+		targetID   string
+		category   reports.Category
+		details    string
+		want       error
+	}{
+		{name: "unknown category", targetType: reports.TargetMarket, targetID: "7", category: "rude", want: reports.ErrInvalidInput},
+		{name: "other without details", targetType: reports.TargetMarket, targetID: "7", category: reports.CategoryOther, want: reports.ErrInvalidInput},
+		{name: "unknown target type", targetType: "bet", targetID: "7", category: reports.CategorySpam, want: reports.ErrInvalidInput},
+		{name: "missing market", targetType: reports.TargetMarket, targetID: "8", category: reports.CategorySpam, want: reports.ErrTargetNotFound},
+		{name: "missing user", targetType: reports.TargetUser, targetID: "nobody", category: reports.CategorySpam, want: reports.ErrTargetNotFound},
+		{name: "self", targetType: reports.TargetUser, targetID: "alice", category: reports.CategorySpam, want: reports.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.Submit(ctx, "alice", tt.targetType, tt.targetID, tt.category, tt.details); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestQueueRequiresReviewPermissionAndDescribesTargets(t *testing.T) {
+	svc, _, _, _, _ := newService()
+	ctx := context.Background()
+	if _, _, err := svc.Submit(ctx, "alice", reports.TargetMarket, "7", reports.CategoryMisleading, "resolution criteria changed"); err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+
+	if _, _, err := svc.Queue(ctx, permissions.Subject{Username: "alice", Role: "REGULAR"}, "", 0, 0); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	items, total, err := svc.Queue(ctx, moderator.PermissionSubject(), "", 0, 0)
+	if err != nil || total != 1 {
+		t.Fatalf("Queue = %+v, %d, %v", items, total, err)
+	}
+	if target := items[0].Target; target.Label != "Will it rain?" || target.OwnerUsername != "carol" || target.MarketID != 7 {
+		t.Fatalf("unexpected target %+v", target)
+	}
+}
+
+func TestActAppliesEnforcementAndClosesReports(t *testing.T) {
+	svc, repo, markets, comments, users := newService()
+	ctx := context.Background()
+	for _, reporter := range []string{"alice", "carol"} {
+		if _, _, err := svc.Submit(ctx, reporter, reports.TargetComment, "3", reports.CategorySpam, ""); err != nil {
+			t.Fatalf("Submit returned error: %v", err)
+		}
+	}
+
+	if _, err := svc.Act(ctx, moderator, reports.ActionInput{TargetType: reports.TargetComment, TargetID: "3", Action: reports.ActionHideContent}); !errors.Is(err, reports.ErrInvalidInput) {
+		t.Fatalf("expected a reason to be required, got %v", err)
+	}
+	if _, err := svc.Act(ctx, moderator, reports.ActionInput{TargetType: reports.TargetComment, TargetID: "3", Action: reports.ActionYankMarket, Reason: "x"}); !errors.Is(err, reports.ErrActionNotApplicable) {
+		t.Fatalf("expected yanking a comment to be rejected, got %v", err)
+	}
+	if _, err := svc.Act(ctx, moderator, reports.ActionInput{TargetType: reports.TargetComment, TargetID: "3", Action: reports.ActionSuspendUser, Reason: "spammer"}); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected suspension to need users.manage, got %v", err)
+	}
+	if repo.reports[0].Status != reports.StatusOpen {
+		t.Fatalf("failed enforcement must leave reports open")
+	}
+
+	action, err := svc.Act(ctx, moderator, reports.ActionInput{TargetType: reports.TargetComment, TargetID: "3", Action: reports.ActionHideContent, Reason: "link spam"})
+	if err != nil || action.ReportsClosed != 2 || action.ActorUsername != "mod" || comments.hidden[3] != "link spam" {
+		t.Fatalf("Act = %+v, %v (hidden=%v)", action, err, comments.hidden)
+	}
+	if repo.reports[1].Status != reports.StatusActioned || repo.reports[1].Outcome != reports.ActionHideContent {
+		t.Fatalf("unexpected report after action %+v", repo.reports[1])
+	}
+
+	if _, err := svc.Act(ctx, admin, reports.ActionInput{TargetType: reports.TargetComment, TargetID: "3", Action: reports.ActionSuspendUser, Reason: "spammer"}); err != nil || users.suspended["dave"] != "spammer" {
+		t.Fatalf("expected the comment author to be suspended, got %v (%v)", err, users.suspended)
+	}
+	if _, err := svc.Act(ctx, admin, reports.ActionInput{TargetType: reports.TargetMarket, TargetID: "7", Action: reports.ActionYankMarket, Reason: "duplicate"}); err != nil || len(markets.yanked) != 1 {
+		t.Fatalf("expected the market to be yanked, got %v (%v)", err, markets.yanked)
+	}
+	dismissed, err := svc.Act(ctx, moderator, reports.ActionInput{TargetType: reports.TargetUser, TargetID: "dave", Action: reports.ActionDismiss, Reason: "fine"})
+	if err != nil || dismissed.ReportsClosed != 0 {
+		t.Fatalf("dismiss = %+v, %v", dismissed, err)
+	}
+}
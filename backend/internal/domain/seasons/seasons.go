@@ -0,0 +1,174 @@
+package seasons
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	danalytics "socialpredict/internal/domain/analytics"
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+// PositionPolicy says what a balance-resetting rollover does with open
+// positions.
+type PositionPolicy string
+
+const (
+	// PositionsCarry leaves open positions in place across the reset; they
+	// pay out into the new season when their markets resolve.
+	PositionsCarry PositionPolicy = "carry"
+	// PositionsLiquidate sells every open position in a market still trading
+	// at its value before balances reset. All positions are valued at one
+	// snapshot, so what a holder receives does not depend on sale order.
+	PositionsLiquidate PositionPolicy = "liquidate"
+)
+
+// Status is where a season is relative to now.
+type Status string
+
+const (
+	StatusUpcoming Status = "upcoming"
+	StatusActive   Status = "active"
+	// StatusEnded is a season past its end that has not been rolled over.
+	StatusEnded    Status = "ended"
+	StatusArchived Status = "archived"
+)
+
+var (
+	// ErrInvalidInput indicates a missing name, an empty period, or an
+	// unknown position policy.
+	ErrInvalidInput = errors.New("invalid season")
+	// ErrSeasonNotFound indicates the season does not exist.
+	ErrSeasonNotFound = errors.New("season not found")
+	// ErrSeasonOverlap indicates the period overlaps another season.
+	ErrSeasonOverlap = errors.New("season overlaps another season")
+	// ErrSeasonNotEnded indicates a rollover before the season's end.
+	ErrSeasonNotEnded = errors.New("season has not ended")
+	// ErrSeasonArchived indicates the season was already rolled over.
+	ErrSeasonArchived = errors.New("season is archived")
+	// ErrPositionsMoved indicates a trade or resolution touched a liquidated
+	// market between valuing its positions and archiving the season.
+	ErrPositionsMoved = errors.New("positions changed during season rollover")
+)
+
+// Season is a contest period. Standings count profit made between StartsAt
+// and EndsAt; rolling the season over freezes them and, when ResetBalances is
+// set, resets every balance to the account's initial balance.
+type Season struct {
+	ID                  int64
+	Name                string
+	StartsAt            time.Time
+	EndsAt              time.Time
+	ResetBalances       bool
+	PositionPolicy      PositionPolicy
+	CreatedBy           string
+	ArchivedAt          *time.Time
+	ArchivedBy          string
+	PositionsLiquidated int
+	PositionsCarried    int
+	BalancesReset       int
+	CreatedAt           time.Time
+}
+
+// Status reports where the season is at now.
+func (s Season) Status(now time.Time) Status {
+	switch {
+	case s.ArchivedAt != nil:
+		return StatusArchived
+	case now.Before(s.StartsAt):
+		return StatusUpcoming
+	case now.Before(s.EndsAt):
+		return StatusActive
+	default:
+		return StatusEnded
+	}
+}
+
+// Input is an admin's season definition.
+type Input struct {
+	Name           string
+	StartsAt       time.Time
+	EndsAt         time.Time
+	ResetBalances  bool
+	PositionPolicy PositionPolicy
+}
+
+// Standing is one user's result in a season. FinalBalance is the balance
+// frozen at rollover, before any reset, and is zero for live standings.
+type Standing struct {
+	Rank            int
+	Username        string
+	Profit          int64
+	Spent           int64
+	CurrentValue    int64
+	ActiveMarkets   int
+	ResolvedMarkets int
+	FinalBalance    int64
+}
+
+// Liquidation is one position a rollover sells: the shares held on one side
+// of a market and the credits they are worth at the rollover's snapshot.
+type Liquidation struct {
+	Username string
+	MarketID int64
+	Outcome  string
+	Shares   int64
+	Value    int64
+}
+
+// OpenMarket is an unresolved market with bets. Positions in a market past
+// its ClosesAt can no longer be sold, so a rollover carries them.
+type OpenMarket struct {
+	ID       int64
+	ClosesAt time.Time
+}
+
+// Archive is what a rollover commits in one step. Liquidations were valued
+// from bets up to SnapshotBetID; the repository refuses them with
+// ErrPositionsMoved if a liquidated market has moved since.
+type Archive struct {
+	SeasonID            int64
+	Standings           []Standing
+	ResetBalances       bool
+	Liquidations        []Liquidation
+	SnapshotBetID       uint
+	PositionsLiquidated int
+	PositionsCarried    int
+	ArchivedBy          string
+	ArchivedAt          time.Time
+}
+
+// Repository persists seasons and their archives.
+type Repository interface {
+	CreateSeason(ctx context.Context, season *Season) error
+	UpdateSeason(ctx context.Context, season *Season) error
+	GetSeason(ctx context.Context, id int64) (*Season, error)
+	ListSeasons(ctx context.Context) ([]Season, error)
+	// Overlaps reports whether any season other than excludeID shares part
+	// of [startsAt, endsAt).
+	Overlaps(ctx context.Context, startsAt, endsAt time.Time, excludeID int64) (bool, error)
+	// ListOpenMarkets returns unresolved markets that have bets.
+	ListOpenMarkets(ctx context.Context) ([]OpenMarket, error)
+	// LatestBetID returns the newest bet ID, or zero when there are no bets.
+	LatestBetID(ctx context.Context) (uint, error)
+	// ArchiveSeason sells the liquidated positions, stores the standings with
+	// each user's balance after those sales, resets balances when asked,
+	// marks the season archived, and records a SeasonArchived event, all in
+	// one transaction that holds trading off the affected accounts and
+	// markets. It returns ErrSeasonArchived when the season was archived
+	// first.
+	ArchiveSeason(ctx context.Context, archive Archive) (*Season, error)
+	ListStandings(ctx context.Context, seasonID int64, limit, offset int) ([]Standing, int64, error)
+}
+
+// Leaderboard computes profit standings for a period.
+type Leaderboard interface {
+	ComputeScopedLeaderboardSnapshot(ctx context.Context, scope danalytics.LeaderboardScope) (*danalytics.GlobalLeaderboardSnapshot, error)
+}
+
+// Positions reads every open position in a market, valued together, and the
+// market's current probability so each side can be priced on its own.
+type Positions interface {
+	GetMarketPositions(ctx context.Context, marketID int64) (dmarkets.MarketPositions, error)
+	GetMarketProbability(ctx context.Context, marketID int64) (*dmarkets.ProbabilityPoint, error)
+}
@@ -0,0 +1,346 @@
+package seasons
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	danalytics "socialpredict/internal/domain/analytics"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	maxNameLength    = 120
+)
+
+// Service lets admins define seasons and roll them over, and lets anyone read
+// season standings: live while a season runs, frozen once it is archived.
+type Service struct {
+	repo        Repository
+	leaderboard Leaderboard
+	positions   Positions
+	authorizer  permissions.Authorizer
+	now         func() time.Time
+}
+
+// NewService constructs a seasons service.
+func NewService(repo Repository, leaderboard Leaderboard, positions Positions, authorizer permissions.Authorizer, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{repo: repo, leaderboard: leaderboard, positions: positions, authorizer: authorizer, now: now}
+}
+
+// List returns every season, newest first.
+func (s *Service) List(ctx context.Context) ([]Season, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSeasons(ctx)
+}
+
+// Get returns one season.
+func (s *Service) Get(ctx context.Context, id int64) (*Season, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, ErrSeasonNotFound
+	}
+	return s.repo.GetSeason(ctx, id)
+}
+
+// Create defines a season.
+func (s *Service) Create(ctx context.Context, actor permissions.Subject, input Input) (*Season, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	input, err := s.validate(ctx, input, 0)
+	if err != nil {
+		return nil, err
+	}
+	season := &Season{
+		Name:           input.Name,
+		StartsAt:       input.StartsAt,
+		EndsAt:         input.EndsAt,
+		ResetBalances:  input.ResetBalances,
+		PositionPolicy: input.PositionPolicy,
+		CreatedBy:      actor.Username,
+		CreatedAt:      s.now().UTC(),
+	}
+	if err := s.repo.CreateSeason(ctx, season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// Update redefines a season that has not been rolled over.
+func (s *Service) Update(ctx context.Context, actor permissions.Subject, id int64, input Input) (*Season, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	season, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if season.ArchivedAt != nil {
+		return nil, ErrSeasonArchived
+	}
+	input, err = s.validate(ctx, input, id)
+	if err != nil {
+		return nil, err
+	}
+	season.Name = input.Name
+	season.StartsAt = input.StartsAt
+	season.EndsAt = input.EndsAt
+	season.ResetBalances = input.ResetBalances
+	season.PositionPolicy = input.PositionPolicy
+	if err := s.repo.UpdateSeason(ctx, season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// Standings returns a page of a season's standings. Archived seasons serve
+// the standings frozen at rollover; other seasons are ranked live on profit
+// made since the season started, up to its end.
+func (s *Service) Standings(ctx context.Context, id int64, limit, offset int) ([]Standing, int64, error) {
+	season, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit, offset = page(limit, offset)
+	if season.ArchivedAt != nil {
+		return s.repo.ListStandings(ctx, season.ID, limit, offset)
+	}
+	if season.Status(s.now()) == StatusUpcoming {
+		return []Standing{}, 0, nil
+	}
+	standings, err := s.liveStandings(ctx, season)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := int64(len(standings))
+	if offset >= len(standings) {
+		return []Standing{}, total, nil
+	}
+	end := offset + limit
+	if end > len(standings) {
+		end = len(standings)
+	}
+	return standings[offset:end], total, nil
+}
+
+// Rollover closes an ended season. It freezes the standings as of the
+// season's end and, when the season resets balances under
+// PositionsLiquidate, values every open position from one snapshot. The
+// repository then sells those positions, archives the standings and resets
+// balances in one step. Positions in closed markets, or worth nothing, are
+// carried.
+func (s *Service) Rollover(ctx context.Context, actor permissions.Subject, id int64) (*Season, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	season, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	switch season.Status(now) {
+	case StatusArchived:
+		return nil, ErrSeasonArchived
+	case StatusUpcoming, StatusActive:
+		return nil, ErrSeasonNotEnded
+	}
+
+	standings, err := s.liveStandings(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+	archive := Archive{
+		SeasonID:      season.ID,
+		Standings:     standings,
+		ResetBalances: season.ResetBalances,
+		ArchivedBy:    actor.Username,
+		ArchivedAt:    now,
+	}
+	if season.ResetBalances && season.PositionPolicy == PositionsLiquidate {
+		if err := s.liquidate(ctx, &archive); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ArchiveSeason(ctx, archive)
+}
+
+func (s *Service) liveStandings(ctx context.Context, season *Season) ([]Standing, error) {
+	if s.leaderboard == nil {
+		return nil, errors.New("season leaderboard unavailable")
+	}
+	snapshot, err := s.leaderboard.ComputeScopedLeaderboardSnapshot(ctx, danalytics.LeaderboardScope{From: season.StartsAt, Until: season.EndsAt})
+	if err != nil {
+		return nil, err
+	}
+	entries := snapshot.Result()
+	standings := make([]Standing, 0, len(entries))
+	for _, entry := range entries {
+		standings = append(standings, Standing{
+			Rank:            entry.Rank,
+			Username:        entry.Username,
+			Profit:          entry.TotalProfit,
+			Spent:           entry.TotalSpent,
+			CurrentValue:    entry.TotalCurrentValue,
+			ActiveMarkets:   entry.ActiveMarkets,
+			ResolvedMarkets: entry.ResolvedMarkets,
+		})
+	}
+	return standings, nil
+}
+
+// liquidate values every open position at the market's current state and
+// adds the sales to the archive. No position is sold here, so every holder
+// of a market is valued at the same probability whatever order they are
+// listed in. A holder's value is split between their sides by what each is
+// worth at that probability, YES shares at p and NO shares at 1-p.
+func (s *Service) liquidate(ctx context.Context, archive *Archive) error {
+	if s.positions == nil {
+		return errors.New("season liquidation unavailable")
+	}
+	snapshotBetID, err := s.repo.LatestBetID(ctx)
+	if err != nil {
+		return err
+	}
+	markets, err := s.repo.ListOpenMarkets(ctx)
+	if err != nil {
+		return err
+	}
+	archive.SnapshotBetID = snapshotBetID
+	for _, market := range markets {
+		positions, err := s.positions.GetMarketPositions(ctx, market.ID)
+		if err != nil {
+			return err
+		}
+		trading := market.ClosesAt.After(archive.ArchivedAt)
+		var probability float64
+		if trading {
+			point, err := s.positions.GetMarketProbability(ctx, market.ID)
+			if err != nil {
+				return err
+			}
+			probability = point.Probability
+		}
+		for _, position := range positions {
+			if position == nil {
+				continue
+			}
+			if !trading || position.Value <= 0 {
+				archive.PositionsCarried += sides(position)
+				continue
+			}
+			yesValue := splitYesValue(position, probability)
+			for _, side := range []Liquidation{
+				{Outcome: "YES", Shares: position.YesSharesOwned, Value: yesValue},
+				{Outcome: "NO", Shares: position.NoSharesOwned, Value: position.Value - yesValue},
+			} {
+				if side.Shares <= 0 {
+					continue
+				}
+				if side.Value <= 0 {
+					archive.PositionsCarried++
+					continue
+				}
+				side.Username = position.Username
+				side.MarketID = market.ID
+				archive.Liquidations = append(archive.Liquidations, side)
+			}
+		}
+	}
+	archive.PositionsLiquidated = len(archive.Liquidations)
+	return nil
+}
+
+// splitYesValue returns the part of a position's value that belongs to its
+// YES shares, rounded to the nearest credit; the NO side gets the rest.
+func splitYesValue(position *dmarkets.UserPosition, probability float64) int64 {
+	yesWorth := float64(max(position.YesSharesOwned, 0)) * probability
+	noWorth := float64(max(position.NoSharesOwned, 0)) * (1 - probability)
+	if yesWorth+noWorth <= 0 {
+		if position.NoSharesOwned > 0 {
+			return 0
+		}
+		return position.Value
+	}
+	return int64(math.Round(float64(position.Value) * yesWorth / (yesWorth + noWorth)))
+}
+
+func sides(position *dmarkets.UserPosition) int {
+	count := 0
+	if position.YesSharesOwned > 0 {
+		count++
+	}
+	if position.NoSharesOwned > 0 {
+		count++
+	}
+	return count
+}
+
+func (s *Service) validate(ctx context.Context, input Input, excludeID int64) (Input, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || utf8.RuneCountInString(input.Name) > maxNameLength {
+		return Input{}, ErrInvalidInput
+	}
+	if input.StartsAt.IsZero() || !input.EndsAt.After(input.StartsAt) {
+		return Input{}, ErrInvalidInput
+	}
+	input.StartsAt, input.EndsAt = input.StartsAt.UTC(), input.EndsAt.UTC()
+	switch input.PositionPolicy {
+	case "":
+		input.PositionPolicy = PositionsCarry
+	case PositionsCarry, PositionsLiquidate:
+	default:
+		return Input{}, ErrInvalidInput
+	}
+	if !input.ResetBalances {
+		input.PositionPolicy = PositionsCarry
+	}
+	overlaps, err := s.repo.Overlaps(ctx, input.StartsAt, input.EndsAt, excludeID)
+	if err != nil {
+		return Input{}, err
+	}
+	if overlaps {
+		return Input{}, ErrSeasonOverlap
+	}
+	return input, nil
+}
+
+func (s *Service) require(ctx context.Context, actor permissions.Subject) error {
+	if err := s.ready(); err != nil {
+		return err
+	}
+	return permissions.Require(ctx, s.authorizer, actor, permissions.SeasonsManage)
+}
+
+func (s *Service) ready() error {
+	if s == nil || s.repo == nil {
+		return errors.New("seasons service unavailable")
+	}
+	return nil
+}
+
+func page(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
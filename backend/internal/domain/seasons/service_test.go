@@ -0,0 +1,272 @@
+package seasons_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	danalytics "socialpredict/internal/domain/analytics"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	"socialpredict/internal/domain/seasons"
+)
+
+type memoryRepo struct {
+	seasons  []*seasons.Season
+	archives []seasons.Archive
+	open     []seasons.OpenMarket
+	latestID uint
+}
+
+func (m *memoryRepo) CreateSeason(_ context.Context, season *seasons.Season) error {
+	season.ID = int64(len(m.seasons) + 1)
+	copied := *season
+	m.seasons = append(m.seasons, &copied)
+	return nil
+}
+
+func (m *memoryRepo) UpdateSeason(_ context.Context, season *seasons.Season) error {
+	copied := *season
+	m.seasons[season.ID-1] = &copied
+	return nil
+}
+
+func (m *memoryRepo) GetSeason(_ context.Context, id int64) (*seasons.Season, error) {
+	if id < 1 || int(id) > len(m.seasons) {
+		return nil, seasons.ErrSeasonNotFound
+	}
+	copied := *m.seasons[id-1]
+	return &copied, nil
+}
+
+func (m *memoryRepo) ListSeasons(context.Context) ([]seasons.Season, error) {
+	out := make([]seasons.Season, 0, len(m.seasons))
+	for _, season := range m.seasons {
+		out = append(out, *season)
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) Overlaps(_ context.Context, startsAt, endsAt time.Time, excludeID int64) (bool, error) {
+	for _, season := range m.seasons {
+		if season.ID != excludeID && season.StartsAt.Before(endsAt) && season.EndsAt.After(startsAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryRepo) ListOpenMarkets(context.Context) ([]seasons.OpenMarket, error) {
+	return m.open, nil
+}
+
+func (m *memoryRepo) LatestBetID(context.Context) (uint, error) {
+	return m.latestID, nil
+}
+
+func (m *memoryRepo) ArchiveSeason(_ context.Context, archive seasons.Archive) (*seasons.Season, error) {
+	season := m.seasons[archive.SeasonID-1]
+	if season.ArchivedAt != nil {
+		return nil, seasons.ErrSeasonArchived
+	}
+	archivedAt := archive.ArchivedAt
+	season.ArchivedAt = &archivedAt
+	season.PositionsLiquidated = archive.PositionsLiquidated
+	season.PositionsCarried = archive.PositionsCarried
+	m.archives = append(m.archives, archive)
+	copied := *season
+	return &copied, nil
+}
+
+func (m *memoryRepo) ListStandings(_ context.Context, seasonID int64, _, _ int) ([]seasons.Standing, int64, error) {
+	for _, archive := range m.archives {
+		if archive.SeasonID == seasonID {
+			return archive.Standings, int64(len(archive.Standings)), nil
+		}
+	}
+	return nil, 0, nil
+}
+
+type fakeLeaderboard struct{ scopes []danalytics.LeaderboardScope }
+
+func (f *fakeLeaderboard) ComputeScopedLeaderboardSnapshot(_ context.Context, scope danalytics.LeaderboardScope) (*danalytics.GlobalLeaderboardSnapshot, error) {
+	f.scopes = append(f.scopes, scope)
+	return &danalytics.GlobalLeaderboardSnapshot{Entries: []danalytics.GlobalUserProfitability{
+		{Username: "alice", TotalProfit: 300, Rank: 1},
+		{Username: "bob", TotalProfit: -50, Rank: 2},
+	}}, nil
+}
+
+type fakePositions struct {
+	err error
+}
+
+func (f fakePositions) GetMarketPositions(_ context.Context, marketID int64) (dmarkets.MarketPositions, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return dmarkets.MarketPositions{
+		{Username: "alice", MarketID: marketID, YesSharesOwned: 10, Value: 8},
+		{Username: "bob", MarketID: marketID, NoSharesOwned: 4, Value: 1},
+		{Username: "carol", MarketID: marketID, NoSharesOwned: 2},
+		{Username: "dave", MarketID: marketID, YesSharesOwned: 10, NoSharesOwned: 10, Value: 10},
+	}, nil
+}
+
+// GetMarketProbability skews every market towards YES so a mixed holder's
+// sides are worth different amounts per share.
+func (f fakePositions) GetMarketProbability(context.Context, int64) (*dmarkets.ProbabilityPoint, error) {
+	return &dmarkets.ProbabilityPoint{Probability: 0.8}, nil
+}
+
+type managers map[string]bool
+
+func (m managers) Can(_ context.Context, subject permissions.Subject, permission permissions.Permission) (bool, error) {
+	return permission == permissions.SeasonsManage && m[subject.Username], nil
+}
+
+var (
+	admin   = permissions.Subject{Username: "root", Role: "ADMIN"}
+	regular = permissions.Subject{Username: "alice", Role: "REGULAR"}
+	q3Start = time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newService(now time.Time, positions fakePositions) (*seasons.Service, *memoryRepo, *fakeLeaderboard) {
+	repo := &memoryRepo{
+		open:     []seasons.OpenMarket{{ID: 7, ClosesAt: now.AddDate(0, 1, 0)}, {ID: 8, ClosesAt: now.Add(-time.Hour)}},
+		latestID: 42,
+	}
+	leaderboard := &fakeLeaderboard{}
+	svc := seasons.NewService(repo, leaderboard, positions, managers{"root": true}, func() time.Time { return now })
+	return svc, repo, leaderboard
+}
+
+func TestCreateValidatesAndRejectsOverlap(t *testing.T) {
+	svc, _, _ := newService(q3Start, fakePositions{})
+	ctx := context.Background()
+	q3 := seasons.Input{Name: " Q3 ", StartsAt: q3Start, EndsAt: q3Start.AddDate(0, 3, 0), PositionPolicy: seasons.PositionsLiquidate}
+
+	if _, err := svc.Create(ctx, regular, q3); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	season, err := svc.Create(ctx, admin, q3)
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if season.Name != "Q3" || season.CreatedBy != "root" || season.PositionPolicy != seasons.PositionsCarry {
+		t.Fatalf("liquidation without a reset should normalize to carry, got %+v", season)
+	}
+
+	tests := []struct {
+		name  string
+		input seasons.Input
+		want  error
+	}{
+		{name: "blank name", input: seasons.Input{Name: " ", StartsAt: q3Start.AddDate(1, 0, 0), EndsAt: q3Start.AddDate(1, 1, 0)}, want: seasons.ErrInvalidInput},
+		{name: "empty period", input: seasons.Input{Name: "x", StartsAt: q3Start.AddDate(1, 0, 0), EndsAt: q3Start.AddDate(1, 0, 0)}, want: seasons.ErrInvalidInput},
+		{name: "unknown policy", input: seasons.Input{Name: "x", StartsAt: q3Start.AddDate(1, 0, 0), EndsAt: q3Start.AddDate(1, 1, 0), PositionPolicy: "burn"}, want: seasons.ErrInvalidInput},
+		{name: "overlap", input: seasons.Input{Name: "x", StartsAt: q3Start.AddDate(0, 2, 0), EndsAt: q3Start.AddDate(0, 4, 0)}, want: seasons.ErrSeasonOverlap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Create(ctx, admin, tt.input); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := svc.Update(ctx, admin, season.ID, seasons.Input{Name: "Q3 contest", StartsAt: q3Start, EndsAt: q3Start.AddDate(0, 3, 0)}); err != nil {
+		t.Fatalf("updating a season should not overlap itself, got %v", err)
+	}
+}
+
+func TestRolloverFreezesStandingsAndLiquidates(t *testing.T) {
+	end := q3Start.AddDate(0, 3, 0)
+	svc, repo, leaderboard := newService(end.Add(time.Hour), fakePositions{})
+	ctx := context.Background()
+
+	season, err := svc.Create(ctx, admin, seasons.Input{Name: "Q3", StartsAt: q3Start, EndsAt: end, ResetBalances: true, PositionPolicy: seasons.PositionsLiquidate})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	live, total, err := svc.Standings(ctx, season.ID, 1, 0)
+	if err != nil || total != 2 || len(live) != 1 || live[0].Username != "alice" {
+		t.Fatalf("live Standings = %+v total=%d err=%v", live, total, err)
+	}
+	if scope := leaderboard.scopes[0]; !scope.From.Equal(q3Start) || !scope.Until.Equal(end) {
+		t.Fatalf("standings should be scoped to the season, got %+v", scope)
+	}
+
+	if _, err := svc.Rollover(ctx, regular, season.ID); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	archived, err := svc.Rollover(ctx, admin, season.ID)
+	if err != nil {
+		t.Fatalf("Rollover returned error: %v", err)
+	}
+	// Market 7 is trading at 80% YES, so alice's and bob's positions are
+	// sold and dave's value is split 8:2 between their YES and NO sides rather
+	// than by share count; carol's is worthless and market 8 has closed, so
+	// those six sides are carried.
+	if archived.Status(end.Add(time.Hour)) != seasons.StatusArchived || archived.PositionsLiquidated != 4 || archived.PositionsCarried != 6 {
+		t.Fatalf("unexpected archived season %+v", archived)
+	}
+	archive := repo.archives[0]
+	if !archive.ResetBalances || len(archive.Standings) != 2 || archive.ArchivedBy != "root" || archive.SnapshotBetID != 42 {
+		t.Fatalf("unexpected archive %+v", archive)
+	}
+	want := []seasons.Liquidation{
+		{Username: "alice", MarketID: 7, Outcome: "YES", Shares: 10, Value: 8},
+		{Username: "bob", MarketID: 7, Outcome: "NO", Shares: 4, Value: 1},
+		{Username: "dave", MarketID: 7, Outcome: "YES", Shares: 10, Value: 8},
+		{Username: "dave", MarketID: 7, Outcome: "NO", Shares: 10, Value: 2},
+	}
+	if len(archive.Liquidations) != len(want) {
+		t.Fatalf("liquidations = %+v, want %+v", archive.Liquidations, want)
+	}
+	for i := range want {
+		if archive.Liquidations[i] != want[i] {
+			t.Fatalf("liquidation %d = %+v, want %+v", i, archive.Liquidations[i], want[i])
+		}
+	}
+
+	if _, err := svc.Rollover(ctx, admin, season.ID); !errors.Is(err, seasons.ErrSeasonArchived) {
+		t.Fatalf("expected ErrSeasonArchived, got %v", err)
+	}
+	if _, err := svc.Update(ctx, admin, season.ID, seasons.Input{Name: "Q3", StartsAt: q3Start, EndsAt: end}); !errors.Is(err, seasons.ErrSeasonArchived) {
+		t.Fatalf("expected ErrSeasonArchived, got %v", err)
+	}
+}
+
+func TestRolloverWaitsForSeasonEnd(t *testing.T) {
+	svc, repo, _ := newService(q3Start.AddDate(0, 1, 0), fakePositions{})
+	ctx := context.Background()
+	season, err := svc.Create(ctx, admin, seasons.Input{Name: "Q3", StartsAt: q3Start, EndsAt: q3Start.AddDate(0, 3, 0), ResetBalances: true, PositionPolicy: seasons.PositionsLiquidate})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if _, err := svc.Rollover(ctx, admin, season.ID); !errors.Is(err, seasons.ErrSeasonNotEnded) {
+		t.Fatalf("expected ErrSeasonNotEnded, got %v", err)
+	}
+	if len(repo.archives) != 0 {
+		t.Fatalf("an early rollover must not archive, got %+v", repo.archives)
+	}
+}
+
+func TestRolloverReturnsValuationErrors(t *testing.T) {
+	end := q3Start.AddDate(0, 3, 0)
+	valuationErr := errors.New("positions unavailable")
+	svc, repo, _ := newService(end.Add(time.Hour), fakePositions{err: valuationErr})
+	ctx := context.Background()
+	season, err := svc.Create(ctx, admin, seasons.Input{Name: "Q3", StartsAt: q3Start, EndsAt: end, ResetBalances: true, PositionPolicy: seasons.PositionsLiquidate})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if _, err := svc.Rollover(ctx, admin, season.ID); !errors.Is(err, valuationErr) {
+		t.Fatalf("expected the valuation error, got %v", err)
+	}
+	if len(repo.archives) != 0 {
+		t.Fatalf("a failed valuation must not archive, got %+v", repo.archives)
+	}
+}
@@ -0,0 +1,549 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"socialpredict/internal/domain/analytics"
+	dbets "socialpredict/internal/domain/bets"
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+)
+
+const (
+	defaultListLimit     = 20
+	maxListLimit         = 100
+	maxSlugLength        = 64
+	maxNameLength        = 120
+	maxDescriptionLength = 2000
+)
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Service manages teams and scores them from their members' results.
+type Service struct {
+	repo       Repository
+	users      Users
+	markets    Markets
+	analytics  Analytics
+	authorizer permissions.Authorizer
+	now        func() time.Time
+}
+
+var _ dbets.TradeGuard = (*Service)(nil)
+
+// NewService constructs a teams service.
+func NewService(repo Repository, users Users, markets Markets, analytics Analytics, authorizer permissions.Authorizer, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{repo: repo, users: users, markets: markets, analytics: analytics, authorizer: authorizer, now: now}
+}
+
+// List returns every team by name.
+func (s *Service) List(ctx context.Context) ([]Team, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTeams(ctx)
+}
+
+// Create defines a team with its first owner. Only holders of teams.manage
+// create teams; everyone else joins by invitation.
+func (s *Service) Create(ctx context.Context, actor permissions.Subject, input Input) (*Team, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if err := permissions.Require(ctx, s.authorizer, actor, permissions.TeamsManage); err != nil {
+		return nil, err
+	}
+	input, err := normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	owner := strings.TrimSpace(input.Owner)
+	if owner == "" {
+		return nil, ErrInvalidInput
+	}
+	if _, err := s.requireUser(ctx, owner); err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	team := &Team{Slug: input.Slug, Name: input.Name, Description: input.Description, CreatedBy: actor.Username, CreatedAt: now, MemberCount: 1}
+	if err := s.repo.CreateTeam(ctx, team, Member{Username: owner, Role: RoleOwner, JoinedAt: now}); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// Update changes a team's name and description. Owners and holders of
+// teams.manage may update; the slug never changes.
+func (s *Service) Update(ctx context.Context, actor permissions.Subject, slug string, input Input) (*Team, error) {
+	team, err := s.team(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireOwner(ctx, actor, team.ID); err != nil {
+		return nil, err
+	}
+	input.Slug = team.Slug
+	input, err = normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	team.Name = input.Name
+	team.Description = input.Description
+	if err := s.repo.UpdateTeam(ctx, team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// Profile returns a team with each member's contribution, the aggregated
+// stats, and its team-only markets.
+func (s *Service) Profile(ctx context.Context, slug string) (*Profile, error) {
+	team, err := s.team(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListMembers(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	calibration, err := s.calibration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, memberStats, err := s.stats(ctx, members, calibration)
+	if err != nil {
+		return nil, err
+	}
+	marketIDs, err := s.repo.ListTeamMarketIDs(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Profile{Team: *team, Members: memberStats, Stats: stats, MarketIDs: marketIDs}, nil
+}
+
+// Leaderboard ranks teams by members' total profit, breaking ties by the
+// better Brier score and then by name. It returns the page and the number
+// of ranked teams.
+func (s *Service) Leaderboard(ctx context.Context, limit, offset int) ([]LeaderboardEntry, int, error) {
+	teams, err := s.List(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	calibration, err := s.calibration(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := make([]LeaderboardEntry, 0, len(teams))
+	for _, team := range teams {
+		members, err := s.repo.ListMembers(ctx, team.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(members) == 0 {
+			continue
+		}
+		stats, _, err := s.stats(ctx, members, calibration)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, LeaderboardEntry{Team: team, Stats: stats})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Stats, entries[j].Stats
+		if a.TotalProfits != b.TotalProfits {
+			return a.TotalProfits > b.TotalProfits
+		}
+		if (a.BrierScore == nil) != (b.BrierScore == nil) {
+			return a.BrierScore != nil
+		}
+		if a.BrierScore != nil && *a.BrierScore != *b.BrierScore {
+			return *a.BrierScore < *b.BrierScore
+		}
+		return entries[i].Team.Name < entries[j].Team.Name
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	limit, offset = page(limit, offset)
+	total := len(entries)
+	if offset >= total {
+		return []LeaderboardEntry{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return entries[offset:end], total, nil
+}
+
+// Invite asks a user to join a team. Owners and holders of teams.manage may
+// invite. An existing pending invite is returned as is.
+func (s *Service) Invite(ctx context.Context, actor permissions.Subject, slug, username string) (*Invite, bool, error) {
+	team, err := s.team(ctx, slug)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.requireOwner(ctx, actor, team.ID); err != nil {
+		return nil, false, err
+	}
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, false, ErrInvalidInput
+	}
+	if _, err := s.requireUser(ctx, username); err != nil {
+		return nil, false, err
+	}
+	if err := s.requireTeamless(ctx, username); err != nil {
+		return nil, false, err
+	}
+	existing, err := s.repo.FindPendingInvite(ctx, team.ID, username)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+	invite := &Invite{
+		TeamID:    team.ID,
+		TeamSlug:  team.Slug,
+		TeamName:  team.Name,
+		Username:  username,
+		InvitedBy: actor.Username,
+		Status:    InvitePending,
+		CreatedAt: s.now().UTC(),
+	}
+	if err := s.repo.CreateInvite(ctx, invite); err != nil {
+		return nil, false, err
+	}
+	return invite, true, nil
+}
+
+// MyInvites returns the user's pending invitations.
+func (s *Service) MyInvites(ctx context.Context, username string) ([]Invite, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPendingInvites(ctx, username)
+}
+
+// AnswerInvite accepts or declines one of the user's pending invitations.
+// Accepting fails with ErrAlreadyInTeam while the user is in another team.
+func (s *Service) AnswerInvite(ctx context.Context, username string, inviteID int64, accept bool) (*Invite, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	status := InviteDeclined
+	if accept {
+		status = InviteAccepted
+		if err := s.requireTeamless(ctx, username); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.AnswerInvite(ctx, inviteID, username, status, s.now().UTC())
+}
+
+// AddMember puts a user straight into a team. Only holders of teams.manage
+// may skip the invitation.
+func (s *Service) AddMember(ctx context.Context, actor permissions.Subject, slug, username string, role Role) (*Member, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if err := permissions.Require(ctx, s.authorizer, actor, permissions.TeamsManage); err != nil {
+		return nil, err
+	}
+	team, err := s.team(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = RoleMember
+	}
+	if role != RoleMember && role != RoleOwner {
+		return nil, ErrInvalidInput
+	}
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, ErrInvalidInput
+	}
+	if _, err := s.requireUser(ctx, username); err != nil {
+		return nil, err
+	}
+	member := Member{TeamID: team.ID, Username: username, Role: role, JoinedAt: s.now().UTC()}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveMember takes a user out of a team. Members may leave, owners may
+// remove anyone, and holders of teams.manage may remove anyone. A team's
+// last owner cannot leave while other members remain.
+func (s *Service) RemoveMember(ctx context.Context, actor permissions.Subject, slug, username string) error {
+	team, err := s.team(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if actor.Username != username {
+		if err := s.requireOwner(ctx, actor, team.ID); err != nil {
+			return err
+		}
+	}
+	members, err := s.repo.ListMembers(ctx, team.ID)
+	if err != nil {
+		return err
+	}
+	var target *Member
+	owners := 0
+	for i := range members {
+		if members[i].Role == RoleOwner {
+			owners++
+		}
+		if members[i].Username == username {
+			target = &members[i]
+		}
+	}
+	if target == nil {
+		return ErrNotMember
+	}
+	if target.Role == RoleOwner && owners == 1 && len(members) > 1 {
+		return ErrInvalidState
+	}
+	return s.repo.RemoveMember(ctx, team.ID, username)
+}
+
+// RestrictMarket makes a market team-only, so only the team's members can
+// buy into it. Holders of teams.manage may restrict any market; owners may
+// restrict markets they created. Markets with bets from non-members cannot
+// be restricted.
+func (s *Service) RestrictMarket(ctx context.Context, actor permissions.Subject, slug string, marketID int64) error {
+	team, err := s.marketTeamAccess(ctx, actor, slug, marketID)
+	if err != nil {
+		return err
+	}
+	return s.repo.RestrictMarket(ctx, team.ID, marketID, actor.Username, s.now().UTC())
+}
+
+// UnrestrictMarket opens a team-only market to everyone.
+func (s *Service) UnrestrictMarket(ctx context.Context, actor permissions.Subject, slug string, marketID int64) error {
+	team, err := s.marketTeamAccess(ctx, actor, slug, marketID)
+	if err != nil {
+		return err
+	}
+	return s.repo.UnrestrictMarket(ctx, team.ID, marketID)
+}
+
+// CanBuy refuses buyers outside a team-only market's team.
+func (s *Service) CanBuy(ctx context.Context, username string, marketID int64) error {
+	if s == nil || s.repo == nil {
+		return nil
+	}
+	teamID, err := s.repo.MarketTeam(ctx, marketID)
+	if err != nil || teamID == 0 {
+		return err
+	}
+	member, err := s.repo.GetMembership(ctx, username)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.TeamID != teamID {
+		return dbets.ErrTradeRestricted
+	}
+	return nil
+}
+
+func (s *Service) marketTeamAccess(ctx context.Context, actor permissions.Subject, slug string, marketID int64) (*Team, error) {
+	team, err := s.team(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if s.markets == nil {
+		return nil, errors.New("teams markets unavailable")
+	}
+	market, err := s.markets.GetMarket(ctx, marketID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := permissions.OrDefault(s.authorizer).Can(ctx, actor, permissions.TeamsManage)
+	if err != nil {
+		return nil, err
+	}
+	if allowed {
+		return team, nil
+	}
+	if err := s.requireOwner(ctx, actor, team.ID); err != nil {
+		return nil, err
+	}
+	if market.CreatorUsername != actor.Username {
+		return nil, permissions.ErrPermissionDenied
+	}
+	return team, nil
+}
+
+// stats sums members' financial snapshots and weights their forecasting
+// scores by scored markets.
+func (s *Service) stats(ctx context.Context, members []Member, calibration *analytics.CalibrationSnapshot) (Stats, []MemberStats, error) {
+	if s.users == nil || s.analytics == nil {
+		return Stats{}, nil, errors.New("teams analytics unavailable")
+	}
+	stats := Stats{Members: len(members)}
+	memberStats := make([]MemberStats, 0, len(members))
+	var brier, logScore float64
+	for _, member := range members {
+		user, err := s.requireUser(ctx, member.Username)
+		if err != nil {
+			return Stats{}, nil, err
+		}
+		financial, err := s.analytics.ComputeUserFinancials(ctx, analytics.FinancialSnapshotRequest{
+			Username:       member.Username,
+			AccountBalance: user.AccountBalance,
+		})
+		if err != nil {
+			return Stats{}, nil, err
+		}
+		contribution := MemberStats{Member: member}
+		if financial != nil {
+			stats.AccountBalance += financial.AccountBalance
+			stats.Equity += financial.Equity
+			stats.AmountInPlay += financial.AmountInPlay
+			stats.TotalSpent += financial.TotalSpent
+			stats.TradingProfits += financial.TradingProfits
+			stats.WorkProfits += financial.WorkProfits
+			stats.TotalProfits += financial.TotalProfits
+			contribution.TotalProfits = financial.TotalProfits
+		}
+		if scored := calibration.User(member.Username); scored != nil {
+			n := float64(scored.ResolvedMarkets)
+			brier += scored.BrierScore * n
+			logScore += scored.LogScore * n
+			stats.ResolvedMarkets += scored.ResolvedMarkets
+			contribution.ResolvedMarkets = scored.ResolvedMarkets
+			score := scored.BrierScore
+			contribution.BrierScore = &score
+		}
+		memberStats = append(memberStats, contribution)
+	}
+	if stats.ResolvedMarkets > 0 {
+		n := float64(stats.ResolvedMarkets)
+		brier, logScore = brier/n, logScore/n
+		stats.BrierScore, stats.LogScore = &brier, &logScore
+	}
+	return stats, memberStats, nil
+}
+
+// calibration reads the stored calibration snapshot, computing it when none
+// has been stored yet.
+func (s *Service) calibration(ctx context.Context) (*analytics.CalibrationSnapshot, error) {
+	if s.analytics == nil {
+		return nil, errors.New("teams analytics unavailable")
+	}
+	readModel, err := s.analytics.GetCalibrationReadModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if readModel != nil {
+		return &readModel.Snapshot, nil
+	}
+	return s.analytics.ComputeCalibrationSnapshot(ctx)
+}
+
+func (s *Service) team(ctx context.Context, slug string) (*Team, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug == "" {
+		return nil, ErrTeamNotFound
+	}
+	return s.repo.GetTeamBySlug(ctx, slug)
+}
+
+// requireOwner passes holders of teams.manage and the team's owners.
+func (s *Service) requireOwner(ctx context.Context, actor permissions.Subject, teamID int64) error {
+	allowed, err := permissions.OrDefault(s.authorizer).Can(ctx, actor, permissions.TeamsManage)
+	if err != nil || allowed {
+		return err
+	}
+	member, err := s.repo.GetMembership(ctx, actor.Username)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.TeamID != teamID || member.Role != RoleOwner {
+		return permissions.ErrPermissionDenied
+	}
+	return nil
+}
+
+func (s *Service) requireTeamless(ctx context.Context, username string) error {
+	member, err := s.repo.GetMembership(ctx, username)
+	if err != nil {
+		return err
+	}
+	if member != nil {
+		return ErrAlreadyInTeam
+	}
+	return nil
+}
+
+func (s *Service) requireUser(ctx context.Context, username string) (*dusers.User, error) {
+	if s.users == nil {
+		return nil, errors.New("teams users unavailable")
+	}
+	user, err := s.users.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, dusers.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *Service) ready() error {
+	if s == nil || s.repo == nil {
+		return errors.New("teams service unavailable")
+	}
+	return nil
+}
+
+// normalizeInput trims the profile and derives a missing slug from the name.
+func normalizeInput(input Input) (Input, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Description = strings.TrimSpace(input.Description)
+	input.Slug = strings.ToLower(strings.TrimSpace(input.Slug))
+	if input.Slug == "" {
+		input.Slug = strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(input.Name), "-"), "-")
+	}
+	if input.Name == "" || utf8.RuneCountInString(input.Name) > maxNameLength || utf8.RuneCountInString(input.Description) > maxDescriptionLength {
+		return Input{}, ErrInvalidInput
+	}
+	if len(input.Slug) > maxSlugLength || !slugPattern.MatchString(input.Slug) {
+		return Input{}, ErrInvalidInput
+	}
+	return input, nil
+}
+
+func page(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,426 @@
+package teams_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	danalytics "socialpredict/internal/domain/analytics"
+	dbets "socialpredict/internal/domain/bets"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/permissions"
+	"socialpredict/internal/domain/teams"
+	dusers "socialpredict/internal/domain/users"
+)
+
+type memoryRepo struct {
+	teams   []*teams.Team
+	members []teams.Member
+	invites []*teams.Invite
+	markets map[int64]int64
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{markets: map[int64]int64{}}
+}
+
+func (m *memoryRepo) CreateTeam(ctx context.Context, team *teams.Team, owner teams.Member) error {
+	for _, existing := range m.teams {
+		if existing.Slug == team.Slug {
+			return teams.ErrSlugTaken
+		}
+	}
+	team.ID = int64(len(m.teams) + 1)
+	copied := *team
+	m.teams = append(m.teams, &copied)
+	owner.TeamID = team.ID
+	return m.AddMember(ctx, owner)
+}
+
+func (m *memoryRepo) UpdateTeam(_ context.Context, team *teams.Team) error {
+	copied := *team
+	m.teams[team.ID-1] = &copied
+	return nil
+}
+
+func (m *memoryRepo) GetTeamBySlug(_ context.Context, slug string) (*teams.Team, error) {
+	for _, team := range m.teams {
+		if team.Slug == slug {
+			copied := *team
+			copied.MemberCount = m.count(team.ID)
+			return &copied, nil
+		}
+	}
+	return nil, teams.ErrTeamNotFound
+}
+
+func (m *memoryRepo) ListTeams(context.Context) ([]teams.Team, error) {
+	out := make([]teams.Team, 0, len(m.teams))
+	for _, team := range m.teams {
+		copied := *team
+		copied.MemberCount = m.count(team.ID)
+		out = append(out, copied)
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) ListMembers(_ context.Context, teamID int64) ([]teams.Member, error) {
+	var out []teams.Member
+	for _, member := range m.members {
+		if member.TeamID == teamID {
+			out = append(out, member)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) GetMembership(_ context.Context, username string) (*teams.Member, error) {
+	for _, member := range m.members {
+		if member.Username == username {
+			copied := member
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryRepo) AddMember(ctx context.Context, member teams.Member) error {
+	if existing, _ := m.GetMembership(ctx, member.Username); existing != nil {
+		return teams.ErrAlreadyInTeam
+	}
+	m.members = append(m.members, member)
+	return nil
+}
+
+func (m *memoryRepo) RemoveMember(_ context.Context, teamID int64, username string) error {
+	for i, member := range m.members {
+		if member.TeamID == teamID && member.Username == username {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+			return nil
+		}
+	}
+	return teams.ErrNotMember
+}
+
+func (m *memoryRepo) CreateInvite(_ context.Context, invite *teams.Invite) error {
+	invite.ID = int64(len(m.invites) + 1)
+	copied := *invite
+	m.invites = append(m.invites, &copied)
+	return nil
+}
+
+func (m *memoryRepo) FindPendingInvite(_ context.Context, teamID int64, username string) (*teams.Invite, error) {
+	for _, invite := range m.invites {
+		if invite.TeamID == teamID && invite.Username == username && invite.Status == teams.InvitePending {
+			copied := *invite
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryRepo) ListPendingInvites(_ context.Context, username string) ([]teams.Invite, error) {
+	var out []teams.Invite
+	for _, invite := range m.invites {
+		if invite.Username == username && invite.Status == teams.InvitePending {
+			out = append(out, *invite)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) AnswerInvite(ctx context.Context, inviteID int64, username string, status teams.InviteStatus, at time.Time) (*teams.Invite, error) {
+	if inviteID < 1 || int(inviteID) > len(m.invites) {
+		return nil, teams.ErrInviteNotFound
+	}
+	invite := m.invites[inviteID-1]
+	if invite.Username != username || invite.Status != teams.InvitePending {
+		return nil, teams.ErrInviteNotFound
+	}
+	invite.Status = status
+	invite.RespondedAt = &at
+	if status == teams.InviteAccepted {
+		if err := m.AddMember(ctx, teams.Member{TeamID: invite.TeamID, Username: username, Role: teams.RoleMember, JoinedAt: at}); err != nil {
+			return nil, err
+		}
+	}
+	copied := *invite
+	return &copied, nil
+}
+
+func (m *memoryRepo) RestrictMarket(_ context.Context, teamID, marketID int64, _ string, _ time.Time) error {
+	if owner, ok := m.markets[marketID]; ok && owner != teamID {
+		return teams.ErrInvalidState
+	}
+	m.markets[marketID] = teamID
+	return nil
+}
+
+func (m *memoryRepo) UnrestrictMarket(_ context.Context, teamID, marketID int64) error {
+	if m.markets[marketID] != teamID {
+		return teams.ErrInvalidState
+	}
+	delete(m.markets, marketID)
+	return nil
+}
+
+func (m *memoryRepo) MarketTeam(_ context.Context, marketID int64) (int64, error) {
+	return m.markets[marketID], nil
+}
+
+func (m *memoryRepo) ListTeamMarketIDs(_ context.Context, teamID int64) ([]int64, error) {
+	ids := []int64{}
+	for marketID, owner := range m.markets {
+		if owner == teamID {
+			ids = append(ids, marketID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memoryRepo) count(teamID int64) int {
+	n := 0
+	for _, member := range m.members {
+		if member.TeamID == teamID {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeUsers map[string]int64
+
+func (f fakeUsers) GetUser(_ context.Context, username string) (*dusers.User, error) {
+	balance, ok := f[username]
+	if !ok {
+		return nil, dusers.ErrUserNotFound
+	}
+	return &dusers.User{Username: username, AccountBalance: balance}, nil
+}
+
+type fakeMarkets map[int64]string
+
+func (f fakeMarkets) GetMarket(_ context.Context, id int64) (*dmarkets.Market, error) {
+	creator, ok := f[id]
+	if !ok {
+		return nil, dmarkets.ErrMarketNotFound
+	}
+	return &dmarkets.Market{ID: id, CreatorUsername: creator}, nil
+}
+
+type fakeAnalytics struct {
+	profits     map[string]int64
+	calibration danalytics.CalibrationSnapshot
+}
+
+func (f fakeAnalytics) ComputeUserFinancials(_ context.Context, req danalytics.FinancialSnapshotRequest) (*danalytics.FinancialSnapshot, error) {
+	return &danalytics.FinancialSnapshot{
+		AccountBalance: req.AccountBalance,
+		Equity:         req.AccountBalance,
+		TotalProfits:   f.profits[req.Username],
+	}, nil
+}
+
+func (f fakeAnalytics) GetCalibrationReadModel(context.Context) (*danalytics.CalibrationReadModel, error) {
+	return nil, nil
+}
+
+func (f fakeAnalytics) ComputeCalibrationSnapshot(context.Context) (*danalytics.CalibrationSnapshot, error) {
+	snapshot := f.calibration
+	return &snapshot, nil
+}
+
+type managers map[string]bool
+
+func (m managers) Can(_ context.Context, subject permissions.Subject, permission permissions.Permission) (bool, error) {
+	return permission == permissions.TeamsManage && m[subject.Username], nil
+}
+
+var (
+	admin = permissions.Subject{Username: "root", Role: "ADMIN"}
+	alice = permissions.Subject{Username: "alice", Role: "REGULAR"}
+	bob   = permissions.Subject{Username: "bob", Role: "REGULAR"}
+	now   = time.Date(2026, 7, 7, 9, 0, 0, 0, time.UTC)
+)
+
+func newService() (*teams.Service, *memoryRepo) {
+	repo := newMemoryRepo()
+	users := fakeUsers{"root": 0, "alice": 1000, "bob": 500, "carol": 800, "dave": 900}
+	markets := fakeMarkets{1: "alice", 2: "carol"}
+	analytics := fakeAnalytics{
+		profits: map[string]int64{"alice": 300, "bob": -100, "carol": 200, "dave": 50},
+		calibration: danalytics.CalibrationSnapshot{Users: []danalytics.UserCalibration{
+			{Username: "alice", ResolvedMarkets: 3, BrierScore: 0.1, LogScore: 0.3},
+			{Username: "bob", ResolvedMarkets: 1, BrierScore: 0.5, LogScore: 0.7},
+			{Username: "carol", ResolvedMarkets: 2, BrierScore: 0.15, LogScore: 0.4},
+		}},
+	}
+	svc := teams.NewService(repo, users, markets, analytics, managers{"root": true}, func() time.Time { return now })
+	return svc, repo
+}
+
+func TestCreateAndInvitationFlow(t *testing.T) {
+	svc, _ := newService()
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, alice, teams.Input{Name: "Risk", Owner: "alice"}); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	for _, input := range []teams.Input{
+		{Name: " ", Owner: "alice"},
+		{Name: "Risk", Slug: "Not A Slug!", Owner: "alice"},
+		{Name: "Risk"},
+	} {
+		if _, err := svc.Create(ctx, admin, input); !errors.Is(err, teams.ErrInvalidInput) {
+			t.Fatalf("Create(%+v) = %v, want ErrInvalidInput", input, err)
+		}
+	}
+	if _, err := svc.Create(ctx, admin, teams.Input{Name: "Risk", Owner: "nobody"}); !errors.Is(err, dusers.ErrUserNotFound) {
+		t.Fatalf("unknown owner error = %v", err)
+	}
+	team, err := svc.Create(ctx, admin, teams.Input{Name: " Risk & Compliance ", Owner: "alice"})
+	if err != nil || team.Slug != "risk-compliance" || team.CreatedBy != "root" {
+		t.Fatalf("Create = %+v, %v", team, err)
+	}
+
+	if _, _, err := svc.Invite(ctx, bob, team.Slug, "carol"); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("non-owner invite error = %v", err)
+	}
+	invite, created, err := svc.Invite(ctx, alice, team.Slug, "bob")
+	if err != nil || !created || invite.InvitedBy != "alice" {
+		t.Fatalf("Invite = %+v, %v, %v", invite, created, err)
+	}
+	again, created, err := svc.Invite(ctx, alice, team.Slug, "bob")
+	if err != nil || created || again.ID != invite.ID {
+		t.Fatalf("repeat Invite = %+v, %v, %v", again, created, err)
+	}
+	if _, _, err := svc.Invite(ctx, alice, team.Slug, "alice"); !errors.Is(err, teams.ErrAlreadyInTeam) {
+		t.Fatalf("inviting a member error = %v", err)
+	}
+
+	if _, err := svc.AnswerInvite(ctx, "carol", invite.ID, true); !errors.Is(err, teams.ErrInviteNotFound) {
+		t.Fatalf("answering another user's invite error = %v", err)
+	}
+	accepted, err := svc.AnswerInvite(ctx, "bob", invite.ID, true)
+	if err != nil || accepted.Status != teams.InviteAccepted {
+		t.Fatalf("AnswerInvite = %+v, %v", accepted, err)
+	}
+
+	if _, err := svc.Update(ctx, bob, team.Slug, teams.Input{Name: "Renamed"}); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("member update error = %v", err)
+	}
+	updated, err := svc.Update(ctx, alice, team.Slug, teams.Input{Name: "Risk", Slug: "ignored", Description: "Second line"})
+	if err != nil || updated.Slug != "risk-compliance" || updated.Name != "Risk" {
+		t.Fatalf("Update = %+v, %v", updated, err)
+	}
+}
+
+func TestRemoveMemberKeepsAnOwner(t *testing.T) {
+	svc, _ := newService()
+	ctx := context.Background()
+	team, _ := svc.Create(ctx, admin, teams.Input{Name: "Risk", Owner: "alice"})
+	if _, err := svc.AddMember(ctx, alice, team.Slug, "bob", teams.RoleMember); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("owner AddMember error = %v", err)
+	}
+	if _, err := svc.AddMember(ctx, admin, team.Slug, "bob", "captain"); !errors.Is(err, teams.ErrInvalidInput) {
+		t.Fatalf("unknown role error = %v", err)
+	}
+	if _, err := svc.AddMember(ctx, admin, team.Slug, "bob", ""); err != nil {
+		t.Fatalf("AddMember = %v", err)
+	}
+
+	if err := svc.RemoveMember(ctx, alice, team.Slug, "alice"); !errors.Is(err, teams.ErrInvalidState) {
+		t.Fatalf("last owner leaving error = %v", err)
+	}
+	if err := svc.RemoveMember(ctx, bob, team.Slug, "alice"); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("member removing owner error = %v", err)
+	}
+	if err := svc.RemoveMember(ctx, bob, team.Slug, "bob"); err != nil {
+		t.Fatalf("leaving = %v", err)
+	}
+	if err := svc.RemoveMember(ctx, alice, team.Slug, "bob"); !errors.Is(err, teams.ErrNotMember) {
+		t.Fatalf("removing a non-member error = %v", err)
+	}
+	if err := svc.RemoveMember(ctx, alice, team.Slug, "alice"); err != nil {
+		t.Fatalf("sole owner leaving an empty team = %v", err)
+	}
+}
+
+func TestProfileAndLeaderboardAggregateMembers(t *testing.T) {
+	svc, _ := newService()
+	ctx := context.Background()
+	red, _ := svc.Create(ctx, admin, teams.Input{Name: "Red", Owner: "alice"})
+	blue, _ := svc.Create(ctx, admin, teams.Input{Name: "Blue", Owner: "carol"})
+	_, _ = svc.Create(ctx, admin, teams.Input{Name: "Green", Owner: "dave"})
+	_, _ = svc.AddMember(ctx, admin, red.Slug, "bob", teams.RoleMember)
+
+	profile, err := svc.Profile(ctx, red.Slug)
+	if err != nil {
+		t.Fatalf("Profile returned error: %v", err)
+	}
+	stats := profile.Stats
+	if stats.Members != 2 || stats.TotalProfits != 200 || stats.AccountBalance != 1500 || stats.ResolvedMarkets != 4 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.BrierScore == nil || *stats.BrierScore < 0.1999 || *stats.BrierScore > 0.2001 {
+		t.Fatalf("Brier score should weight members by scored markets, got %v", stats.BrierScore)
+	}
+	if len(profile.Members) != 2 || profile.Members[1].TotalProfits != -100 || profile.Members[1].BrierScore == nil {
+		t.Fatalf("unexpected member stats %+v", profile.Members)
+	}
+
+	// Red and Blue tie on profit; Blue's better Brier score ranks it first.
+	// Green trails with no scored markets.
+	entries, total, err := svc.Leaderboard(ctx, 2, 0)
+	if err != nil || total != 3 || len(entries) != 2 {
+		t.Fatalf("Leaderboard = %+v, %d, %v", entries, total, err)
+	}
+	if entries[0].Team.Slug != blue.Slug || entries[1].Team.Slug != red.Slug {
+		t.Fatalf("unexpected order %s, %s", entries[0].Team.Slug, entries[1].Team.Slug)
+	}
+	rest, _, _ := svc.Leaderboard(ctx, 2, 2)
+	if len(rest) != 1 || rest[0].Rank != 3 || rest[0].Stats.BrierScore != nil {
+		t.Fatalf("unexpected last page %+v", rest)
+	}
+}
+
+func TestTeamOnlyMarketsGuardBuyers(t *testing.T) {
+	svc, _ := newService()
+	ctx := context.Background()
+	red, _ := svc.Create(ctx, admin, teams.Input{Name: "Red", Owner: "alice"})
+	_, _ = svc.AddMember(ctx, admin, red.Slug, "bob", teams.RoleMember)
+
+	if err := svc.RestrictMarket(ctx, alice, red.Slug, 2); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("restricting another creator's market error = %v", err)
+	}
+	if err := svc.RestrictMarket(ctx, bob, red.Slug, 1); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("member restricting error = %v", err)
+	}
+	if err := svc.RestrictMarket(ctx, alice, red.Slug, 9); !errors.Is(err, dmarkets.ErrMarketNotFound) {
+		t.Fatalf("missing market error = %v", err)
+	}
+	if err := svc.RestrictMarket(ctx, alice, red.Slug, 1); err != nil {
+		t.Fatalf("RestrictMarket = %v", err)
+	}
+	if err := svc.RestrictMarket(ctx, admin, red.Slug, 2); err != nil {
+		t.Fatalf("admin RestrictMarket = %v", err)
+	}
+
+	if err := svc.CanBuy(ctx, "bob", 1); err != nil {
+		t.Fatalf("member CanBuy = %v", err)
+	}
+	if err := svc.CanBuy(ctx, "carol", 1); !errors.Is(err, dbets.ErrTradeRestricted) {
+		t.Fatalf("outsider CanBuy error = %v", err)
+	}
+	if err := svc.CanBuy(ctx, "carol", 3); err != nil {
+		t.Fatalf("open market CanBuy = %v", err)
+	}
+
+	if err := svc.UnrestrictMarket(ctx, alice, red.Slug, 1); err != nil {
+		t.Fatalf("UnrestrictMarket = %v", err)
+	}
+	if err := svc.CanBuy(ctx, "carol", 1); err != nil {
+		t.Fatalf("reopened market CanBuy = %v", err)
+	}
+}
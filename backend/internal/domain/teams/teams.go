@@ -0,0 +1,179 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"socialpredict/internal/domain/analytics"
+	dmarkets "socialpredict/internal/domain/markets"
+	dusers "socialpredict/internal/domain/users"
+)
+
+// Role is a member's standing in a team.
+type Role string
+
+const (
+	// RoleOwner may invite and remove members and manage the team's profile
+	// and team-only markets.
+	RoleOwner  Role = "owner"
+	RoleMember Role = "member"
+)
+
+// InviteStatus tracks an invitation.
+type InviteStatus string
+
+const (
+	InvitePending  InviteStatus = "pending"
+	InviteAccepted InviteStatus = "accepted"
+	InviteDeclined InviteStatus = "declined"
+)
+
+var (
+	// ErrInvalidInput indicates a malformed slug, name, role, or username.
+	ErrInvalidInput = errors.New("invalid team input")
+	// ErrTeamNotFound indicates the team does not exist.
+	ErrTeamNotFound = errors.New("team not found")
+	// ErrSlugTaken indicates another team already uses the slug.
+	ErrSlugTaken = errors.New("team slug already taken")
+	// ErrAlreadyInTeam indicates the user already belongs to a team.
+	ErrAlreadyInTeam = errors.New("user already belongs to a team")
+	// ErrNotMember indicates the user is not a member of the team.
+	ErrNotMember = errors.New("user is not a member of the team")
+	// ErrInviteNotFound indicates the invitation does not exist, is not the
+	// caller's, or was already answered.
+	ErrInviteNotFound = errors.New("team invite not found")
+	// ErrInvalidState indicates the change would leave a team without an
+	// owner, or a market cannot become team-only.
+	ErrInvalidState = errors.New("invalid team state")
+)
+
+// Team is a team's profile.
+type Team struct {
+	ID          int64
+	Slug        string
+	Name        string
+	Description string
+	CreatedBy   string
+	CreatedAt   time.Time
+	MemberCount int
+}
+
+// Member is one user's membership.
+type Member struct {
+	TeamID   int64
+	Username string
+	Role     Role
+	JoinedAt time.Time
+}
+
+// Invite is an invitation to join a team.
+type Invite struct {
+	ID          int64
+	TeamID      int64
+	TeamSlug    string
+	TeamName    string
+	Username    string
+	InvitedBy   string
+	Status      InviteStatus
+	CreatedAt   time.Time
+	RespondedAt *time.Time
+}
+
+// Input defines or redefines a team. Owner is only read on creation.
+type Input struct {
+	Slug        string
+	Name        string
+	Description string
+	Owner       string
+}
+
+// Stats aggregates members' financial snapshots and forecasting scores.
+// BrierScore and LogScore weight each member by their scored markets and are
+// nil until some member has a scored market.
+type Stats struct {
+	Members         int
+	AccountBalance  int64
+	Equity          int64
+	AmountInPlay    int64
+	TotalSpent      int64
+	TradingProfits  int64
+	WorkProfits     int64
+	TotalProfits    int64
+	ResolvedMarkets int
+	BrierScore      *float64
+	LogScore        *float64
+}
+
+// MemberStats is one member's contribution to the team's stats.
+type MemberStats struct {
+	Member
+	TotalProfits    int64
+	ResolvedMarkets int
+	BrierScore      *float64
+}
+
+// Profile is a team with its members, stats, and team-only markets.
+type Profile struct {
+	Team      Team
+	Members   []MemberStats
+	Stats     Stats
+	MarketIDs []int64
+}
+
+// LeaderboardEntry ranks one team.
+type LeaderboardEntry struct {
+	Rank  int
+	Team  Team
+	Stats Stats
+}
+
+// Repository persists teams, memberships, invitations, and team-only
+// markets.
+type Repository interface {
+	// CreateTeam inserts the team with owner as its first member. It returns
+	// ErrSlugTaken or ErrAlreadyInTeam on conflicts.
+	CreateTeam(ctx context.Context, team *Team, owner Member) error
+	UpdateTeam(ctx context.Context, team *Team) error
+	GetTeamBySlug(ctx context.Context, slug string) (*Team, error)
+	// ListTeams returns every team with its member count, by name.
+	ListTeams(ctx context.Context) ([]Team, error)
+	ListMembers(ctx context.Context, teamID int64) ([]Member, error)
+	// GetMembership returns the user's membership, or nil.
+	GetMembership(ctx context.Context, username string) (*Member, error)
+	// AddMember returns ErrAlreadyInTeam when the user is in a team.
+	AddMember(ctx context.Context, member Member) error
+	RemoveMember(ctx context.Context, teamID int64, username string) error
+	CreateInvite(ctx context.Context, invite *Invite) error
+	// FindPendingInvite returns the user's pending invite to the team, or nil.
+	FindPendingInvite(ctx context.Context, teamID int64, username string) (*Invite, error)
+	ListPendingInvites(ctx context.Context, username string) ([]Invite, error)
+	// AnswerInvite records the answer to a pending invite of username's and,
+	// when accepted, adds the membership in the same transaction. It returns
+	// ErrInviteNotFound when no such pending invite exists.
+	AnswerInvite(ctx context.Context, inviteID int64, username string, status InviteStatus, at time.Time) (*Invite, error)
+	// RestrictMarket makes the market team-only. It returns ErrInvalidState
+	// when the market belongs to another team or has bets from non-members.
+	RestrictMarket(ctx context.Context, teamID, marketID int64, by string, at time.Time) error
+	UnrestrictMarket(ctx context.Context, teamID, marketID int64) error
+	// MarketTeam returns the team a market is restricted to, or 0.
+	MarketTeam(ctx context.Context, marketID int64) (int64, error)
+	ListTeamMarketIDs(ctx context.Context, teamID int64) ([]int64, error)
+}
+
+// Users reads accounts and balances.
+type Users interface {
+	GetUser(ctx context.Context, username string) (*dusers.User, error)
+}
+
+// Markets reads markets for team-only restrictions.
+type Markets interface {
+	GetMarket(ctx context.Context, id int64) (*dmarkets.Market, error)
+}
+
+// Analytics computes members' financial snapshots and forecasting scores.
+type Analytics interface {
+	ComputeUserFinancials(ctx context.Context, req analytics.FinancialSnapshotRequest) (*analytics.FinancialSnapshot, error)
+	GetCalibrationReadModel(ctx context.Context) (*analytics.CalibrationReadModel, error)
+	ComputeCalibrationSnapshot(ctx context.Context) (*analytics.CalibrationSnapshot, error)
+}
@@ -0,0 +1,269 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+)
+
+const maxAccountStatusReasonLength = 500
+
+// AccountStatus is the admin-imposed standing of an account.
+type AccountStatus string
+
+const (
+	// AccountStatusActive is an account in good standing.
+	AccountStatusActive AccountStatus = "active"
+	// AccountStatusSuspended is a read-only account: it can log in but cannot
+	// trade, create markets, or propose answers.
+	AccountStatusSuspended AccountStatus = "suspended"
+	// AccountStatusBanned is an account that cannot authenticate at all.
+	AccountStatusBanned AccountStatus = "banned"
+)
+
+// AccountStatusAction names one recorded account status change.
+type AccountStatusAction string
+
+const (
+	AccountStatusActionSuspend   AccountStatusAction = "suspend"
+	AccountStatusActionBan       AccountStatusAction = "ban"
+	AccountStatusActionReinstate AccountStatusAction = "reinstate"
+)
+
+// AccountStatusChange is an admin request to suspend, ban, or reinstate an account.
+type AccountStatusChange struct {
+	Status    AccountStatus
+	Reason    string
+	ExpiresAt *time.Time
+	Now       time.Time
+}
+
+// AccountStatusAuditRecord captures one suspension, ban, or reinstatement.
+type AccountStatusAuditRecord struct {
+	ID            int64
+	Username      string
+	ActorUsername string
+	Action        AccountStatusAction
+	FromStatus    AccountStatus
+	ToStatus      AccountStatus
+	Reason        string
+	ExpiresAt     *time.Time
+	CreatedAt     time.Time
+}
+
+// AccountStatusUpdateFunc applies a status change to a user loaded inside the
+// repository's unit of work and returns the audit record to store with it.
+type AccountStatusUpdateFunc func(user *User) (*AccountStatusAuditRecord, error)
+
+// AccountStatusAuditRepository persists account status changes and their audit trail.
+type AccountStatusAuditRepository interface {
+	UpdateAccountStatus(ctx context.Context, username string, update AccountStatusUpdateFunc) (*User, error)
+	ListAccountStatusAudits(ctx context.Context, username string, limit, offset int) ([]*AccountStatusAuditRecord, error)
+}
+
+// NormalizeAccountStatus maps stored values onto the known statuses; empty
+// values predate account standing and are treated as active.
+func NormalizeAccountStatus(value string) AccountStatus {
+	switch status := AccountStatus(strings.ToLower(strings.TrimSpace(value))); status {
+	case "":
+		return AccountStatusActive
+	default:
+		return status
+	}
+}
+
+func (s AccountStatus) valid() bool {
+	switch s {
+	case AccountStatusActive, AccountStatusSuspended, AccountStatusBanned:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s AccountStatus) action() AccountStatusAction {
+	switch s {
+	case AccountStatusSuspended:
+		return AccountStatusActionSuspend
+	case AccountStatusBanned:
+		return AccountStatusActionBan
+	default:
+		return AccountStatusActionReinstate
+	}
+}
+
+// EffectiveAccountStatus returns the account's standing at now. Suspensions
+// and bans with an expiry lapse back to active once it has passed.
+func (u *User) EffectiveAccountStatus(now time.Time) AccountStatus {
+	if u == nil {
+		return AccountStatusActive
+	}
+	status := NormalizeAccountStatus(string(u.AccountStatus))
+	if status == AccountStatusActive {
+		return status
+	}
+	if u.AccountStatusExpiresAt != nil && !now.Before(*u.AccountStatusExpiresAt) {
+		return AccountStatusActive
+	}
+	return status
+}
+
+// EnsureCanAuthenticate rejects banned accounts.
+func (u *User) EnsureCanAuthenticate(now time.Time) error {
+	if u.EffectiveAccountStatus(now) == AccountStatusBanned {
+		return ErrAccountBanned
+	}
+	return nil
+}
+
+// EnsureCanAct rejects suspended and banned accounts from write actions such
+// as trading, creating markets, and proposing answers.
+func (u *User) EnsureCanAct(now time.Time) error {
+	switch u.EffectiveAccountStatus(now) {
+	case AccountStatusBanned:
+		return ErrAccountBanned
+	case AccountStatusSuspended:
+		return ErrAccountSuspended
+	default:
+		return nil
+	}
+}
+
+func (u *User) applyAccountStatus(actorUsername string, change AccountStatusChange) {
+	if change.Status == AccountStatusActive {
+		u.AccountStatus = AccountStatusActive
+		u.AccountStatusReason = ""
+		u.AccountStatusBy = ""
+		u.AccountStatusAt = nil
+		u.AccountStatusExpiresAt = nil
+		return
+	}
+	at := change.Now
+	u.AccountStatus = change.Status
+	u.AccountStatusReason = change.Reason
+	u.AccountStatusBy = actorUsername
+	u.AccountStatusAt = &at
+	u.AccountStatusExpiresAt = cloneTime(change.ExpiresAt)
+}
+
+// SetAccountStatus suspends, bans, or reinstates an account and records the
+// change in the account status audit trail in the same transaction. Requires users.manage. Admins
+// cannot be targeted, and actors cannot change their own standing.
+func (s *Service) SetAccountStatus(ctx context.Context, actor *User, username string, change AccountStatusChange) (*User, error) {
+	if err := s.requirePermission(ctx, actor, permissions.UsersManage); err != nil {
+		return nil, err
+	}
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	if username == actor.Username {
+		return nil, ErrUnauthorized
+	}
+	change, err := s.normalizeAccountStatusChange(change)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := s.accountStatusAuditRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.UpdateAccountStatus(ctx, username, func(user *User) (*AccountStatusAuditRecord, error) {
+		if NormalizeUserType(user.UserType) == UserTypeAdmin {
+			return nil, ErrUnauthorized
+		}
+		audit := &AccountStatusAuditRecord{
+			Username:      user.Username,
+			ActorUsername: actor.Username,
+			Action:        change.Status.action(),
+			FromStatus:    user.EffectiveAccountStatus(change.Now),
+			ToStatus:      change.Status,
+			Reason:        change.Reason,
+			ExpiresAt:     cloneTime(change.ExpiresAt),
+			CreatedAt:     change.Now,
+		}
+		user.applyAccountStatus(actor.Username, change)
+		return audit, nil
+	})
+}
+
+// ListAccountStatusAudits returns an account's status changes newest first. Requires users.manage.
+func (s *Service) ListAccountStatusAudits(ctx context.Context, actor *User, username string, limit, offset int) ([]*AccountStatusAuditRecord, error) {
+	if err := s.requirePermission(ctx, actor, permissions.UsersManage); err != nil {
+		return nil, err
+	}
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	repo, err := s.accountStatusAuditRepository()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.requireUser(ctx, username); err != nil {
+		return nil, err
+	}
+	records, err := repo.ListAccountStatusAudits(ctx, username, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		return []*AccountStatusAuditRecord{}, nil
+	}
+	return records, nil
+}
+
+// EnsureAccountCanAct returns ErrAccountSuspended or ErrAccountBanned when
+// username may not perform write actions right now.
+func (s *Service) EnsureAccountCanAct(ctx context.Context, username string) error {
+	if err := validateUsername(username); err != nil {
+		return err
+	}
+	user, err := s.requireUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	return user.EnsureCanAct(s.currentTime())
+}
+
+func (s *Service) normalizeAccountStatusChange(change AccountStatusChange) (AccountStatusChange, error) {
+	change.Status = NormalizeAccountStatus(string(change.Status))
+	if !change.Status.valid() {
+		return AccountStatusChange{}, ErrInvalidUserData
+	}
+	if change.Now.IsZero() {
+		change.Now = s.currentTime()
+	}
+	change.Reason = strings.TrimSpace(change.Reason)
+	if len(change.Reason) > maxAccountStatusReasonLength {
+		return AccountStatusChange{}, ErrInvalidUserData
+	}
+
+	if change.Status == AccountStatusActive {
+		change.ExpiresAt = nil
+		return change, nil
+	}
+	if change.Reason == "" {
+		return AccountStatusChange{}, ErrInvalidUserData
+	}
+	if change.ExpiresAt != nil && !change.ExpiresAt.After(change.Now) {
+		return AccountStatusChange{}, ErrInvalidUserData
+	}
+	return change, nil
+}
+
+func (s *Service) accountStatusAuditRepository() (AccountStatusAuditRepository, error) {
+	if s == nil || s.accountStatusAudit == nil {
+		return nil, ErrInvalidUserData
+	}
+	return s.accountStatusAudit, nil
+}
+
+func cloneTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	copy := *value
+	return &copy
+}
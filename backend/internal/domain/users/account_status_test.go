@@ -0,0 +1,197 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	users "socialpredict/internal/domain/users"
+)
+
+// fakeAccountStatusAuditRepository stores the user and its audit record
+// together, or neither when the update fails.
+type fakeAccountStatusAuditRepository struct {
+	users   *fakeRepository
+	records []*users.AccountStatusAuditRecord
+}
+
+func (f *fakeAccountStatusAuditRepository) UpdateAccountStatus(_ context.Context, username string, update users.AccountStatusUpdateFunc) (*users.User, error) {
+	user, err := f.users.storedUser(username)
+	if err != nil {
+		return nil, err
+	}
+	record, err := update(user)
+	if err != nil {
+		return nil, err
+	}
+	f.users.persistUser(user)
+	record.ID = int64(len(f.records) + 1)
+	f.records = append(f.records, record)
+	return user, nil
+}
+
+func (f *fakeAccountStatusAuditRepository) ListAccountStatusAudits(_ context.Context, username string, _, _ int) ([]*users.AccountStatusAuditRecord, error) {
+	var matched []*users.AccountStatusAuditRecord
+	for i := len(f.records) - 1; i >= 0; i-- {
+		if f.records[i].Username == username {
+			matched = append(matched, f.records[i])
+		}
+	}
+	return matched, nil
+}
+
+func newAccountStatusTestService(target *users.User) (*users.Service, *fakeRepository, *fakeAccountStatusAuditRepository) {
+	repo := &fakeRepository{user: target}
+	audits := &fakeAccountStatusAuditRepository{users: repo}
+	service := users.NewServiceWithDependencies(users.ServiceDependencies{
+		Reader:        repo,
+		Writer:        repo,
+		AccountStatus: audits,
+	}, nil, nil)
+	return service, repo, audits
+}
+
+func TestServiceSetAccountStatusSuspendsBansAndReinstates(t *testing.T) {
+	service, repo, audits := newAccountStatusTestService(seededUser("troll"))
+	admin := &users.User{Username: "admin", UserType: "ADMIN"}
+	ctx := context.Background()
+	now := time.Date(2026, 6, 26, 9, 0, 0, 0, time.UTC)
+	expires := now.Add(72 * time.Hour)
+
+	suspended, err := service.SetAccountStatus(ctx, admin, "troll", users.AccountStatusChange{
+		Status: users.AccountStatusSuspended, Reason: " spam ", ExpiresAt: &expires, Now: now,
+	})
+	if err != nil {
+		t.Fatalf("suspend returned error: %v", err)
+	}
+	if suspended.AccountStatus != users.AccountStatusSuspended || suspended.AccountStatusReason != "spam" || suspended.AccountStatusBy != "admin" {
+		t.Fatalf("unexpected suspended user: %+v", suspended)
+	}
+	if err := repo.user.EnsureCanAct(now); !errors.Is(err, users.ErrAccountSuspended) {
+		t.Fatalf("EnsureCanAct while suspended = %v, want ErrAccountSuspended", err)
+	}
+	if err := repo.user.EnsureCanAuthenticate(now); err != nil {
+		t.Fatalf("suspended users should still authenticate: %v", err)
+	}
+	if err := repo.user.EnsureCanAct(expires); err != nil {
+		t.Fatalf("suspension should lapse at expiry: %v", err)
+	}
+
+	if _, err := service.SetAccountStatus(ctx, admin, "troll", users.AccountStatusChange{
+		Status: users.AccountStatusBanned, Reason: "repeat abuse", Now: now.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("ban returned error: %v", err)
+	}
+	if err := repo.user.EnsureCanAuthenticate(now.Add(24 * 365 * time.Hour)); !errors.Is(err, users.ErrAccountBanned) {
+		t.Fatalf("EnsureCanAuthenticate while banned = %v, want ErrAccountBanned", err)
+	}
+
+	reinstated, err := service.SetAccountStatus(ctx, admin, "troll", users.AccountStatusChange{Status: users.AccountStatusActive, Now: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("reinstate returned error: %v", err)
+	}
+	if reinstated.AccountStatus != users.AccountStatusActive || reinstated.AccountStatusReason != "" || reinstated.AccountStatusExpiresAt != nil {
+		t.Fatalf("unexpected reinstated user: %+v", reinstated)
+	}
+
+	if len(audits.records) != 3 {
+		t.Fatalf("expected 3 audit records, got %d", len(audits.records))
+	}
+	ban := audits.records[1]
+	if ban.Action != users.AccountStatusActionBan || ban.FromStatus != users.AccountStatusSuspended || ban.ToStatus != users.AccountStatusBanned || ban.ActorUsername != "admin" {
+		t.Fatalf("unexpected ban audit: %+v", ban)
+	}
+	if audits.records[0].ExpiresAt == nil || !audits.records[0].ExpiresAt.Equal(expires) {
+		t.Fatalf("suspension audit should carry expiry: %+v", audits.records[0])
+	}
+
+	history, err := service.ListAccountStatusAudits(ctx, admin, "troll", 10, 0)
+	if err != nil {
+		t.Fatalf("ListAccountStatusAudits returned error: %v", err)
+	}
+	if len(history) != 3 || history[0].Action != users.AccountStatusActionReinstate {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+}
+
+func TestServiceSetAccountStatusRejectsInvalidChanges(t *testing.T) {
+	now := time.Date(2026, 6, 26, 9, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	admin := &users.User{Username: "admin", UserType: "ADMIN"}
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		actor  *users.User
+		target *users.User
+		change users.AccountStatusChange
+		want   error
+	}{
+		{name: "non admin actor", actor: &users.User{Username: "mod", UserType: "MODERATOR"}, target: seededUser("troll"), change: users.AccountStatusChange{Status: users.AccountStatusSuspended, Reason: "spam", Now: now}, want: users.ErrUnauthorized},
+		{name: "missing reason", actor: admin, target: seededUser("troll"), change: users.AccountStatusChange{Status: users.AccountStatusBanned, Now: now}, want: users.ErrInvalidUserData},
+		{name: "unknown status", actor: admin, target: seededUser("troll"), change: users.AccountStatusChange{Status: "frozen", Reason: "spam", Now: now}, want: users.ErrInvalidUserData},
+		{name: "expiry in the past", actor: admin, target: seededUser("troll"), change: users.AccountStatusChange{Status: users.AccountStatusSuspended, Reason: "spam", ExpiresAt: &past, Now: now}, want: users.ErrInvalidUserData},
+		{name: "admin target", actor: admin, target: &users.User{Username: "other-admin", UserType: "ADMIN"}, change: users.AccountStatusChange{Status: users.AccountStatusBanned, Reason: "spam", Now: now}, want: users.ErrUnauthorized},
+		{name: "self target", actor: &users.User{Username: "admin", UserType: "ADMIN"}, target: &users.User{Username: "admin", UserType: "ADMIN"}, change: users.AccountStatusChange{Status: users.AccountStatusSuspended, Reason: "oops", Now: now}, want: users.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, audits := newAccountStatusTestService(tt.target)
+			if _, err := service.SetAccountStatus(ctx, tt.actor, tt.target.Username, tt.change); !errors.Is(err, tt.want) {
+				t.Fatalf("SetAccountStatus error = %v, want %v", err, tt.want)
+			}
+			if len(audits.records) != 0 {
+				t.Fatalf("rejected change should not be audited: %+v", audits.records)
+			}
+		})
+	}
+}
+
+func TestServiceEnsureAccountCanActReflectsStoredStatus(t *testing.T) {
+	target := seededUser("troll")
+	target.AccountStatus = users.AccountStatusSuspended
+	service, _, _ := newAccountStatusTestService(target)
+
+	if err := service.EnsureAccountCanAct(context.Background(), "troll"); !errors.Is(err, users.ErrAccountSuspended) {
+		t.Fatalf("EnsureAccountCanAct = %v, want ErrAccountSuspended", err)
+	}
+
+	profile := target.ToPrivateProfile()
+	if profile.AccountStatus != users.AccountStatusSuspended {
+		t.Fatalf("private profile should expose suspension, got %q", profile.AccountStatus)
+	}
+}
+
+func TestServiceAccountStatusUsesTheServiceClock(t *testing.T) {
+	now := time.Date(2026, 6, 26, 9, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{user: seededUser("troll")}
+	audits := &fakeAccountStatusAuditRepository{users: repo}
+	service := users.NewServiceWithDependencies(users.ServiceDependencies{
+		Reader:        repo,
+		Writer:        repo,
+		AccountStatus: audits,
+		Clock:         func() time.Time { return now },
+	}, nil, nil)
+	admin := &users.User{Username: "admin", UserType: "ADMIN"}
+	ctx := context.Background()
+
+	expires := now.Add(time.Hour)
+	if _, err := service.SetAccountStatus(ctx, admin, "troll", users.AccountStatusChange{
+		Status: users.AccountStatusSuspended, Reason: "cool off", ExpiresAt: &expires,
+	}); err != nil {
+		t.Fatalf("suspend returned error: %v", err)
+	}
+	if got := audits.records[0].CreatedAt; !got.Equal(now) {
+		t.Fatalf("audit recorded at %v, want service clock %v", got, now)
+	}
+	if err := service.EnsureAccountCanAct(ctx, "troll"); !errors.Is(err, users.ErrAccountSuspended) {
+		t.Fatalf("EnsureAccountCanAct before expiry = %v, want ErrAccountSuspended", err)
+	}
+
+	now = expires.Add(time.Minute)
+	if err := service.EnsureAccountCanAct(ctx, "troll"); err != nil {
+		t.Fatalf("EnsureAccountCanAct after expiry = %v, want nil", err)
+	}
+}
@@ -0,0 +1,73 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+)
+
+// PermissionSubject describes the user for authorization decisions. A
+// suspended or banned account is restricted to read-only grants.
+func (u *User) PermissionSubject() permissions.Subject {
+	if u == nil {
+		return permissions.Subject{}
+	}
+	return permissions.Subject{
+		Username:   u.Username,
+		Role:       string(NormalizeUserType(u.UserType)),
+		Suspended:  u.IsSuspendedModerator(),
+		Restricted: u.EffectiveAccountStatus(time.Now()) != AccountStatusActive,
+	}
+}
+
+// PermissionSubject describes the public user for authorization decisions.
+// Its AccountStatus was already resolved when it was built from the User.
+func (u *PublicUser) PermissionSubject() permissions.Subject {
+	if u == nil {
+		return permissions.Subject{}
+	}
+	return permissions.Subject{
+		Username:   u.Username,
+		Role:       string(NormalizeUserType(u.UserType)),
+		Suspended:  NormalizeModeratorStatus(u.UserType, string(u.ModeratorStatus)) == ModeratorStatusSuspended,
+		Restricted: NormalizeAccountStatus(string(u.AccountStatus)) != AccountStatusActive,
+	}
+}
+
+// SetAuthorizer configures how the service resolves permissions. Without one,
+// only admins hold permissions.
+func (s *Service) SetAuthorizer(authorizer permissions.Authorizer) {
+	if s != nil {
+		s.authorizer = authorizer
+	}
+}
+
+func (s *Service) can(ctx context.Context, actor *User, permission permissions.Permission) (bool, error) {
+	if actor == nil {
+		return false, nil
+	}
+	var authorizer permissions.Authorizer
+	if s != nil {
+		authorizer = s.authorizer
+	}
+	return permissions.OrDefault(authorizer).Can(ctx, actor.PermissionSubject(), permission)
+}
+
+func (s *Service) requirePermission(ctx context.Context, actor *User, permission permissions.Permission) error {
+	if actor == nil || actor.Username == "" {
+		return ErrUnauthorized
+	}
+	allowed, err := s.can(ctx, actor, permission)
+	if err != nil {
+		if errors.Is(err, permissions.ErrPermissionDenied) {
+			return ErrUnauthorized
+		}
+		return err
+	}
+	if !allowed {
+		return ErrUnauthorized
+	}
+	return nil
+}
@@ -46,4 +46,8 @@ var (
 	ErrInvalidTransactionType UserError = newDomainError("invalid transaction type")
 	// ErrInvalidModeratorState indicates that a moderator-only role/status transition is invalid.
 	ErrInvalidModeratorState UserError = newDomainError("invalid moderator state")
+	// ErrAccountSuspended indicates that a suspended account attempted a write action.
+	ErrAccountSuspended UserError = newDomainError("account suspended")
+	// ErrAccountBanned indicates that a banned account attempted to authenticate or act.
+	ErrAccountBanned UserError = newDomainError("account banned")
 )
@@ -0,0 +1,378 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+)
+
+const maxExternalUsernameAttempts = 50
+
+var (
+	// ErrExternalEmailUnverified indicates that the identity provider did not vouch for the email address.
+	ErrExternalEmailUnverified UserError = newDomainError("external identity email is not verified")
+	// ErrExternalIdentityNotAllowed indicates that the sign-in policy forbids linking or provisioning an account for the identity.
+	ErrExternalIdentityNotAllowed UserError = newDomainError("external identity is not allowed to sign in")
+	// ErrExternalIdentityLinkRequired indicates that an account already uses the email but its
+	// address was never verified, so the owner must sign in and link the identity from settings.
+	ErrExternalIdentityLinkRequired UserError = newDomainError("sign in and link this identity from your account settings")
+	// ErrExternalIdentityAlreadyLinked indicates that the provider subject belongs to another account.
+	ErrExternalIdentityAlreadyLinked UserError = newDomainError("external identity is linked to another account")
+)
+
+// ExternalIdentity links a local account to a subject at an external identity provider.
+type ExternalIdentity struct {
+	ID          int64
+	Issuer      string
+	Subject     string
+	Username    string
+	Email       string
+	LastLoginAt *time.Time
+	CreatedAt   time.Time
+}
+
+// ExternalSignInPolicy controls which identities may be linked to an existing
+// account by verified email or provisioned on first sign-in.
+type ExternalSignInPolicy struct {
+	AutoProvision       bool
+	AllowedEmailDomains []string
+	AllowedEmails       []string
+}
+
+// AllowsProvisioning reports whether an account may be created for email.
+// Provisioning with an empty allow-list is refused so enabling it is always an explicit choice.
+func (p ExternalSignInPolicy) AllowsProvisioning(email string) bool {
+	return p.AutoProvision && p.AllowsLinking(email)
+}
+
+// AllowsLinking reports whether an existing account with the verified email
+// may be linked automatically. Like provisioning, an empty allow-list refuses.
+func (p ExternalSignInPolicy) AllowsLinking(email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return false
+	}
+	for _, allowed := range p.AllowedEmails {
+		if strings.EqualFold(strings.TrimSpace(allowed), email) {
+			return true
+		}
+	}
+	domain := email[at+1:]
+	for _, allowed := range p.AllowedEmailDomains {
+		allowed = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(allowed)), "@")
+		if allowed != "" && domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ExternalSignInRequest carries the verified claims of an external sign-in.
+type ExternalSignInRequest struct {
+	Issuer                       string
+	Subject                      string
+	Email                        string
+	EmailVerified                bool
+	PreferredUsername            string
+	Name                         string
+	Policy                       ExternalSignInPolicy
+	DefaultInitialAccountBalance int64
+	Now                          time.Time
+}
+
+// ExternalLinkRequest carries the verified claims of an identity the signed-in
+// user is connecting to their account.
+type ExternalLinkRequest struct {
+	Issuer  string
+	Subject string
+	Email   string
+	Now     time.Time
+}
+
+// ExternalIdentityRepository persists identity links and provisions linked accounts.
+type ExternalIdentityRepository interface {
+	GetUserByExternalIdentity(ctx context.Context, issuer, subject string) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	LinkExternalIdentity(ctx context.Context, identity *ExternalIdentity) error
+	TouchExternalIdentity(ctx context.Context, issuer, subject string, at time.Time) error
+	CreateUserWithExternalIdentity(ctx context.Context, user *User, identity *ExternalIdentity) error
+}
+
+// SignInWithExternalIdentity resolves the local account for an identity
+// provider subject. An already linked subject signs straight in; otherwise an
+// existing REGULAR account without extra permissions is linked when the
+// policy allows the email and both the provider and the account have verified
+// it, and failing that a new REGULAR account is provisioned when the policy
+// allows provisioning. Privileged accounts are never linked automatically:
+// taking one over would only need control of its mailbox at the identity
+// provider. Accounts whose email was only typed in return
+// ErrExternalIdentityLinkRequired; anyone could have registered that address.
+func (s *Service) SignInWithExternalIdentity(ctx context.Context, req ExternalSignInRequest) (*User, error) {
+	req.Issuer = strings.TrimSpace(req.Issuer)
+	req.Subject = strings.TrimSpace(req.Subject)
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if req.Issuer == "" || req.Subject == "" {
+		return nil, ErrInvalidUserData
+	}
+	if req.Now.IsZero() {
+		req.Now = s.currentTime()
+	}
+
+	repo, err := s.externalIdentityRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := repo.GetUserByExternalIdentity(ctx, req.Issuer, req.Subject)
+	if err == nil {
+		if err := repo.TouchExternalIdentity(ctx, req.Issuer, req.Subject, req.Now); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return nil, err
+	}
+
+	if req.Email == "" || !req.EmailVerified {
+		return nil, ErrExternalEmailUnverified
+	}
+
+	identity := &ExternalIdentity{
+		Issuer:      req.Issuer,
+		Subject:     req.Subject,
+		Email:       req.Email,
+		LastLoginAt: &req.Now,
+	}
+
+	user, err = repo.GetUserByEmail(ctx, req.Email)
+	if err == nil {
+		if !req.Policy.AllowsLinking(req.Email) {
+			return nil, ErrExternalIdentityNotAllowed
+		}
+		privileged, err := s.isPrivilegedAccount(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if privileged {
+			return nil, ErrExternalIdentityNotAllowed
+		}
+		if user.EmailVerifiedAt == nil {
+			return nil, ErrExternalIdentityLinkRequired
+		}
+		identity.Username = user.Username
+		if err := repo.LinkExternalIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return nil, err
+	}
+
+	if !req.Policy.AllowsProvisioning(req.Email) {
+		return nil, ErrExternalIdentityNotAllowed
+	}
+
+	user, err = s.newExternallyProvisionedUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	identity.Username = user.Username
+	if err := repo.CreateUserWithExternalIdentity(ctx, user, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// isPrivilegedAccount reports whether the account is an admin or moderator or
+// holds any permission through an assigned role.
+func (s *Service) isPrivilegedAccount(ctx context.Context, user *User) (bool, error) {
+	if NormalizeUserType(user.UserType) != UserTypeRegular {
+		return true, nil
+	}
+	for _, permission := range permissions.All() {
+		allowed, err := s.can(ctx, user, permission)
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LinkExternalIdentityToAccount connects an identity provider subject to the
+// signed-in actor's account. Signing in first proves ownership of the account,
+// so unlike SignInWithExternalIdentity neither the email nor the account type
+// matters. Linking a subject the actor already holds is a no-op.
+func (s *Service) LinkExternalIdentityToAccount(ctx context.Context, actor *User, req ExternalLinkRequest) (*ExternalIdentity, error) {
+	if actor == nil || actor.Username == "" {
+		return nil, ErrUnauthorized
+	}
+	req.Issuer = strings.TrimSpace(req.Issuer)
+	req.Subject = strings.TrimSpace(req.Subject)
+	if req.Issuer == "" || req.Subject == "" {
+		return nil, ErrInvalidUserData
+	}
+	if req.Now.IsZero() {
+		req.Now = s.currentTime()
+	}
+
+	repo, err := s.externalIdentityRepository()
+	if err != nil {
+		return nil, err
+	}
+	identity := &ExternalIdentity{
+		Issuer:      req.Issuer,
+		Subject:     req.Subject,
+		Username:    actor.Username,
+		Email:       strings.ToLower(strings.TrimSpace(req.Email)),
+		LastLoginAt: &req.Now,
+	}
+
+	linked, err := repo.GetUserByExternalIdentity(ctx, req.Issuer, req.Subject)
+	if err == nil {
+		if linked.Username != actor.Username {
+			return nil, ErrExternalIdentityAlreadyLinked
+		}
+		return identity, nil
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return nil, err
+	}
+	if err := repo.LinkExternalIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *Service) newExternallyProvisionedUser(ctx context.Context, req ExternalSignInRequest) (*User, error) {
+	uniqueness, err := s.userUniquenessRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := uniqueExternalUsername(ctx, uniqueness, req.PreferredUsername, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	displayName, err := s.externalDisplayName(ctx, uniqueness, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := uniqueAPIKey(ctx, uniqueness)
+	if err != nil {
+		return nil, err
+	}
+
+	// Provisioned accounts sign in through the identity provider, so the local
+	// password is a random secret nobody knows.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate password: %w", err)
+	}
+	passwordHash, err := hashPassword(hex.EncodeToString(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	// The provider vouched for the address this account is created with.
+	verifiedAt := req.Now
+	return &User{
+		Username:              username,
+		DisplayName:           displayName,
+		Email:                 req.Email,
+		EmailVerifiedAt:       &verifiedAt,
+		APIKey:                apiKey,
+		PasswordHash:          passwordHash,
+		UserType:              string(UserTypeRegular),
+		InitialAccountBalance: req.DefaultInitialAccountBalance,
+		AccountBalance:        req.DefaultInitialAccountBalance,
+		PersonalEmoji:         randomEmoji(),
+		MustChangePassword:    false,
+	}, nil
+}
+
+func (s *Service) externalDisplayName(ctx context.Context, uniqueness UserUniquenessRepository, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || s.sanitizer == nil {
+		return uniqueDisplayName(ctx, uniqueness)
+	}
+	displayName, err := s.sanitizeDisplayName(name)
+	if err != nil {
+		return uniqueDisplayName(ctx, uniqueness)
+	}
+	exists, err := uniqueness.DisplayNameExists(ctx, displayName)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return uniqueDisplayName(ctx, uniqueness)
+	}
+	return displayName, nil
+}
+
+// uniqueExternalUsername derives a lowercase alphanumeric username from the
+// provider's preferred username or the email local part, adding a numeric
+// suffix when the base is already taken.
+func uniqueExternalUsername(ctx context.Context, uniqueness UserUniquenessRepository, preferred, email string) (string, error) {
+	base := externalUsernameBase(preferred)
+	if base == "" {
+		local := email
+		if at := strings.Index(local, "@"); at >= 0 {
+			local = local[:at]
+		}
+		base = externalUsernameBase(local)
+	}
+	if len(base) < 3 {
+		base += "user"
+	}
+
+	for attempt := 0; attempt < maxExternalUsernameAttempts; attempt++ {
+		candidate := base
+		if attempt > 0 {
+			suffix := fmt.Sprintf("%d", attempt+1)
+			if len(candidate)+len(suffix) > 30 {
+				candidate = candidate[:30-len(suffix)]
+			}
+			candidate += suffix
+		}
+		exists, err := uniqueness.UsernameExists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", ErrUserAlreadyExists
+}
+
+func externalUsernameBase(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+		if b.Len() == 30 {
+			break
+		}
+	}
+	return b.String()
+}
+
+func (s *Service) externalIdentityRepository() (ExternalIdentityRepository, error) {
+	if s == nil || s.externalIdentities == nil {
+		return nil, ErrInvalidUserData
+	}
+	return s.externalIdentities, nil
+}
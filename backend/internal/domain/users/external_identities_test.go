@@ -0,0 +1,260 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+	users "socialpredict/internal/domain/users"
+	"socialpredict/security"
+)
+
+type fakeExternalIdentityRepository struct {
+	usersByName map[string]*users.User
+	links       map[string]string
+	touched     int
+}
+
+func newFakeExternalIdentityRepository(existing ...*users.User) *fakeExternalIdentityRepository {
+	repo := &fakeExternalIdentityRepository{usersByName: map[string]*users.User{}, links: map[string]string{}}
+	for _, user := range existing {
+		repo.usersByName[user.Username] = user
+	}
+	return repo
+}
+
+func (f *fakeExternalIdentityRepository) GetUserByExternalIdentity(_ context.Context, issuer, subject string) (*users.User, error) {
+	username, ok := f.links[issuer+"|"+subject]
+	if !ok {
+		return nil, users.ErrUserNotFound
+	}
+	return f.usersByName[username], nil
+}
+
+func (f *fakeExternalIdentityRepository) GetUserByEmail(_ context.Context, email string) (*users.User, error) {
+	for _, user := range f.usersByName {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, users.ErrUserNotFound
+}
+
+func (f *fakeExternalIdentityRepository) LinkExternalIdentity(_ context.Context, identity *users.ExternalIdentity) error {
+	f.links[identity.Issuer+"|"+identity.Subject] = identity.Username
+	return nil
+}
+
+func (f *fakeExternalIdentityRepository) TouchExternalIdentity(context.Context, string, string, time.Time) error {
+	f.touched++
+	return nil
+}
+
+func (f *fakeExternalIdentityRepository) CreateUserWithExternalIdentity(ctx context.Context, user *users.User, identity *users.ExternalIdentity) error {
+	f.usersByName[user.Username] = user
+	return f.LinkExternalIdentity(ctx, identity)
+}
+
+func (f *fakeExternalIdentityRepository) UsernameExists(_ context.Context, username string) (bool, error) {
+	_, ok := f.usersByName[username]
+	return ok, nil
+}
+
+func (f *fakeExternalIdentityRepository) DisplayNameExists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeExternalIdentityRepository) EmailExists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeExternalIdentityRepository) APIKeyExists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeExternalIdentityRepository) AnyUserIdentityExists(context.Context, string, string, string, string) (bool, error) {
+	return false, nil
+}
+
+func newExternalIdentityTestService(repo *fakeExternalIdentityRepository) *users.Service {
+	return users.NewServiceWithDependencies(users.ServiceDependencies{
+		Uniqueness:  repo,
+		ExternalIDs: repo,
+	}, nil, security.NewSecurityService().Sanitizer)
+}
+
+func TestServiceSignInWithExternalIdentityLinksExistingAccountByVerifiedEmail(t *testing.T) {
+	verifiedAt := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := newFakeExternalIdentityRepository(&users.User{Username: "ada", Email: "ada@example.com", EmailVerifiedAt: &verifiedAt, UserType: "REGULAR"})
+	service := newExternalIdentityTestService(repo)
+	ctx := context.Background()
+	req := users.ExternalSignInRequest{
+		Issuer:        "https://idp.example.com",
+		Subject:       "sub-1",
+		Email:         "Ada@Example.com",
+		EmailVerified: true,
+		Policy:        users.ExternalSignInPolicy{AllowedEmailDomains: []string{"example.com"}},
+	}
+
+	user, err := service.SignInWithExternalIdentity(ctx, req)
+	if err != nil {
+		t.Fatalf("SignInWithExternalIdentity returned error: %v", err)
+	}
+	if user.Username != "ada" || repo.links["https://idp.example.com|sub-1"] != "ada" {
+		t.Fatalf("expected link to existing account, got user=%+v links=%v", user, repo.links)
+	}
+
+	// Subsequent sign-ins resolve through the link even if the email changes.
+	req.Email = "ada.lovelace@example.com"
+	user, err = service.SignInWithExternalIdentity(ctx, req)
+	if err != nil || user.Username != "ada" || repo.touched != 1 {
+		t.Fatalf("expected linked sign-in, got user=%+v err=%v touched=%d", user, err, repo.touched)
+	}
+}
+
+type grantingAuthorizer map[string]bool
+
+func (g grantingAuthorizer) Can(_ context.Context, subject permissions.Subject, _ permissions.Permission) (bool, error) {
+	return g[subject.Username], nil
+}
+
+func TestServiceSignInWithExternalIdentityRequiresSettingsLinkForUnverifiedLocalEmail(t *testing.T) {
+	// An attacker registered first with the victim's address, which nobody verified.
+	repo := newFakeExternalIdentityRepository(&users.User{Username: "squatter", Email: "victim@example.com", UserType: "REGULAR"})
+	service := newExternalIdentityTestService(repo)
+	ctx := context.Background()
+
+	_, err := service.SignInWithExternalIdentity(ctx, users.ExternalSignInRequest{
+		Issuer:        "https://idp.example.com",
+		Subject:       "victim-sub",
+		Email:         "victim@example.com",
+		EmailVerified: true,
+		Policy:        users.ExternalSignInPolicy{AutoProvision: true, AllowedEmailDomains: []string{"example.com"}},
+	})
+	if !errors.Is(err, users.ErrExternalIdentityLinkRequired) {
+		t.Fatalf("error = %v, want ErrExternalIdentityLinkRequired", err)
+	}
+	if len(repo.links) != 0 {
+		t.Fatalf("expected no links, got %v", repo.links)
+	}
+
+	// The rightful owner of an account links while signed in instead.
+	owner := &users.User{Username: "squatter"}
+	if _, err := service.LinkExternalIdentityToAccount(ctx, owner, users.ExternalLinkRequest{Issuer: "https://idp.example.com", Subject: "owner-sub"}); err != nil {
+		t.Fatalf("LinkExternalIdentityToAccount returned error: %v", err)
+	}
+	if repo.links["https://idp.example.com|owner-sub"] != "squatter" {
+		t.Fatalf("expected settings link, got %v", repo.links)
+	}
+	other := &users.User{Username: "someone"}
+	if _, err := service.LinkExternalIdentityToAccount(ctx, other, users.ExternalLinkRequest{Issuer: "https://idp.example.com", Subject: "owner-sub"}); !errors.Is(err, users.ErrExternalIdentityAlreadyLinked) {
+		t.Fatalf("linking another account's subject error = %v, want ErrExternalIdentityAlreadyLinked", err)
+	}
+}
+
+func TestServiceSignInWithExternalIdentityRefusesLinkingOutsidePolicyOrToPrivilegedAccounts(t *testing.T) {
+	repo := newFakeExternalIdentityRepository(
+		&users.User{Username: "ada", Email: "ada@example.com", UserType: "REGULAR"},
+		&users.User{Username: "root", Email: "root@example.com", UserType: "ADMIN"},
+		&users.User{Username: "mod", Email: "mod@example.com", UserType: "MODERATOR"},
+		&users.User{Username: "editor", Email: "editor@example.com", UserType: "REGULAR"},
+	)
+	service := newExternalIdentityTestService(repo)
+	service.SetAuthorizer(grantingAuthorizer{"editor": true})
+	policy := users.ExternalSignInPolicy{AllowedEmailDomains: []string{"example.com"}}
+
+	cases := []struct {
+		name   string
+		email  string
+		policy users.ExternalSignInPolicy
+	}{
+		{name: "empty allow-list", email: "ada@example.com"},
+		{name: "unlisted domain", email: "ada@example.com", policy: users.ExternalSignInPolicy{AllowedEmailDomains: []string{"example.org"}}},
+		{name: "admin", email: "root@example.com", policy: policy},
+		{name: "moderator", email: "mod@example.com", policy: policy},
+		{name: "custom role holder", email: "editor@example.com", policy: policy},
+	}
+	for _, tc := range cases {
+		_, err := service.SignInWithExternalIdentity(context.Background(), users.ExternalSignInRequest{
+			Issuer: "https://idp.example.com", Subject: "sub-" + tc.name, Email: tc.email, EmailVerified: true, Policy: tc.policy,
+		})
+		if !errors.Is(err, users.ErrExternalIdentityNotAllowed) {
+			t.Fatalf("%s: error = %v, want ErrExternalIdentityNotAllowed", tc.name, err)
+		}
+	}
+	if len(repo.links) != 0 {
+		t.Fatalf("expected no links, got %v", repo.links)
+	}
+}
+
+func TestServiceSignInWithExternalIdentityRequiresVerifiedEmail(t *testing.T) {
+	repo := newFakeExternalIdentityRepository(&users.User{Username: "ada", Email: "ada@example.com"})
+	service := newExternalIdentityTestService(repo)
+
+	_, err := service.SignInWithExternalIdentity(context.Background(), users.ExternalSignInRequest{
+		Issuer:  "https://idp.example.com",
+		Subject: "sub-1",
+		Email:   "ada@example.com",
+	})
+	if !errors.Is(err, users.ErrExternalEmailUnverified) {
+		t.Fatalf("error = %v, want ErrExternalEmailUnverified", err)
+	}
+	if len(repo.links) != 0 {
+		t.Fatalf("expected no links, got %v", repo.links)
+	}
+}
+
+func TestServiceSignInWithExternalIdentityProvisionsOnlyAllowedEmails(t *testing.T) {
+	repo := newFakeExternalIdentityRepository(&users.User{Username: "grace"})
+	service := newExternalIdentityTestService(repo)
+	ctx := context.Background()
+	policy := users.ExternalSignInPolicy{AutoProvision: true, AllowedEmailDomains: []string{"example.com"}, AllowedEmails: []string{"guest@partner.org"}}
+
+	_, err := service.SignInWithExternalIdentity(ctx, users.ExternalSignInRequest{
+		Issuer: "https://idp.example.com", Subject: "outsider", Email: "eve@evil.test", EmailVerified: true, Policy: policy,
+	})
+	if !errors.Is(err, users.ErrExternalIdentityNotAllowed) {
+		t.Fatalf("error = %v, want ErrExternalIdentityNotAllowed", err)
+	}
+
+	user, err := service.SignInWithExternalIdentity(ctx, users.ExternalSignInRequest{
+		Issuer:                       "https://idp.example.com",
+		Subject:                      "sub-grace",
+		Email:                        "grace@example.com",
+		EmailVerified:                true,
+		PreferredUsername:            "Grace.Hopper",
+		Name:                         "Grace Hopper",
+		Policy:                       policy,
+		DefaultInitialAccountBalance: 0,
+	})
+	if err != nil {
+		t.Fatalf("SignInWithExternalIdentity returned error: %v", err)
+	}
+	if user.Username != "gracehopper" || user.UserType != "REGULAR" || user.MustChangePassword || user.DisplayName != "Grace Hopper" || user.EmailVerifiedAt == nil {
+		t.Fatalf("unexpected provisioned user: %+v", user)
+	}
+
+	user, err = service.SignInWithExternalIdentity(ctx, users.ExternalSignInRequest{
+		Issuer: "https://idp.example.com", Subject: "sub-guest", Email: "guest@partner.org", EmailVerified: true, PreferredUsername: "grace", Policy: policy,
+	})
+	if err != nil {
+		t.Fatalf("allow-listed email sign-in returned error: %v", err)
+	}
+	if user.Username != "grace2" {
+		t.Fatalf("expected collision suffix, got %q", user.Username)
+	}
+}
+
+func TestExternalSignInPolicyRequiresExplicitAllowList(t *testing.T) {
+	if (users.ExternalSignInPolicy{AutoProvision: true}).AllowsProvisioning("ada@example.com") {
+		t.Fatalf("empty allow-list must not provision")
+	}
+	if (users.ExternalSignInPolicy{AllowedEmailDomains: []string{"example.com"}}).AllowsProvisioning("ada@example.com") {
+		t.Fatalf("disabled provisioning must not provision")
+	}
+	if (users.ExternalSignInPolicy{AutoProvision: true, AllowedEmailDomains: []string{"example.com"}}).AllowsProvisioning("ada@sub.example.com") {
+		t.Fatalf("domain allow-list must match exactly")
+	}
+}
@@ -0,0 +1,445 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+)
+
+const (
+	inviteCodeBytes      = 10
+	inviteCodePrefixSize = 4
+	maxInviteUses        = 10000
+	maxInviteNoteLength  = 500
+)
+
+var (
+	// ErrInviteNotFound indicates that no invite matches the supplied code or ID.
+	ErrInviteNotFound UserError = newDomainError("invite not found")
+	// ErrInviteUnavailable indicates that an invite is expired, revoked, or fully used.
+	ErrInviteUnavailable UserError = newDomainError("invite is no longer available")
+)
+
+// Invite is a registration code that admins and moderators mint so visitors
+// can create their own accounts.
+type Invite struct {
+	ID                    int64
+	CodeHash              string
+	CodePrefix            string
+	CreatedBy             string
+	UserType              string
+	InitialAccountBalance *int64
+	MaxUses               int64
+	UseCount              int64
+	ExpiresAt             *time.Time
+	RevokedAt             *time.Time
+	RevokedBy             string
+	Note                  string
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// RemainingUses reports how many more accounts the invite can create.
+func (i *Invite) RemainingUses() int64 {
+	if i == nil || i.UseCount >= i.MaxUses {
+		return 0
+	}
+	return i.MaxUses - i.UseCount
+}
+
+// CheckRedeemable reports whether the invite can create another account at now.
+func (i *Invite) CheckRedeemable(now time.Time) error {
+	if i == nil {
+		return ErrInviteNotFound
+	}
+	if i.RevokedAt != nil {
+		return ErrInviteUnavailable
+	}
+	if i.ExpiresAt != nil && !now.Before(*i.ExpiresAt) {
+		return ErrInviteUnavailable
+	}
+	if i.RemainingUses() <= 0 {
+		return ErrInviteUnavailable
+	}
+	return nil
+}
+
+// InviteCreateRequest describes a new invite minted by an admin or moderator.
+type InviteCreateRequest struct {
+	UserType              string
+	InitialAccountBalance *int64
+	MaxUses               int64
+	ExpiresAt             *time.Time
+	Note                  string
+	Now                   time.Time
+}
+
+// InviteCreateResult returns the stored invite with its one-time plaintext code.
+type InviteCreateResult struct {
+	Invite *Invite
+	Code   string
+}
+
+// InviteListFilters narrows admin invite listings.
+type InviteListFilters struct {
+	CreatedBy string
+	Limit     int
+	Offset    int
+}
+
+// InvitePage is one page of invites with the number matching the filters.
+type InvitePage struct {
+	Invites []*Invite
+	Total   int64
+}
+
+// InviteRegistrationRequest contains the self-registration fields supplied with an invite code.
+type InviteRegistrationRequest struct {
+	Code                         string
+	Username                     string
+	Password                     string
+	DisplayName                  string
+	Email                        string
+	DefaultInitialAccountBalance int64
+	Now                          time.Time
+}
+
+// InviteRedemptionFunc builds the account to create once the invite row has
+// been loaded inside the redemption unit of work.
+type InviteRedemptionFunc func(invite *Invite) (*User, error)
+
+// InviteRepository persists invites and redeems them atomically with account creation.
+type InviteRepository interface {
+	CreateInvite(ctx context.Context, invite *Invite) error
+	ListInvites(ctx context.Context, filters InviteListFilters) ([]*Invite, error)
+	CountInvites(ctx context.Context, filters InviteListFilters) (int64, error)
+	GetInvite(ctx context.Context, id int64) (*Invite, error)
+	RevokeInvite(ctx context.Context, id int64, actorUsername string, revokedAt time.Time) error
+	RedeemInvite(ctx context.Context, codeHash string, build InviteRedemptionFunc) (*User, error)
+}
+
+// CreateInvite mints a new invite code. Holders of users.create may pre-assign
+// the MODERATOR role and a starting balance; active moderators may only mint
+// REGULAR invites that use the configured starting balance.
+func (s *Service) CreateInvite(ctx context.Context, actor *User, req InviteCreateRequest) (*InviteCreateResult, error) {
+	privileged, err := s.inviteAccess(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	invite, err := newInviteFromRequest(actor, req, privileged)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.inviteRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	invite.CodeHash = HashInviteCode(code)
+	invite.CodePrefix = code[:inviteCodePrefixSize]
+
+	if err := repo.CreateInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+	return &InviteCreateResult{Invite: invite, Code: code}, nil
+}
+
+// ListInvites returns a page of invites visible to the actor and how many
+// there are in total. Moderators only see invites they minted.
+func (s *Service) ListInvites(ctx context.Context, actor *User, filters InviteListFilters) (*InvitePage, error) {
+	privileged, err := s.inviteAccess(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !privileged {
+		filters.CreatedBy = actor.Username
+	}
+
+	repo, err := s.inviteRepository()
+	if err != nil {
+		return nil, err
+	}
+	invites, err := repo.ListInvites(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	total, err := repo.CountInvites(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if invites == nil {
+		invites = []*Invite{}
+	}
+	return &InvitePage{Invites: invites, Total: total}, nil
+}
+
+// RevokeInvite disables an invite. Moderators may only revoke invites they minted.
+func (s *Service) RevokeInvite(ctx context.Context, actor *User, inviteID int64, revokedAt time.Time) (*Invite, error) {
+	privileged, err := s.inviteAccess(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if inviteID <= 0 {
+		return nil, ErrInvalidUserData
+	}
+
+	repo, err := s.inviteRepository()
+	if err != nil {
+		return nil, err
+	}
+	invite, err := repo.GetInvite(ctx, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	if !privileged && invite.CreatedBy != actor.Username {
+		return nil, ErrUnauthorized
+	}
+	if invite.RevokedAt != nil {
+		return invite, nil
+	}
+
+	if revokedAt.IsZero() {
+		revokedAt = s.currentTime()
+	}
+	if err := repo.RevokeInvite(ctx, inviteID, actor.Username, revokedAt); err != nil {
+		return nil, err
+	}
+	invite.RevokedAt = &revokedAt
+	invite.RevokedBy = actor.Username
+	return invite, nil
+}
+
+// RegisterWithInvite consumes one use of an invite code and creates the
+// caller's account with the password they chose.
+func (s *Service) RegisterWithInvite(ctx context.Context, req InviteRegistrationRequest) (*User, error) {
+	code := normalizeInviteCode(req.Code)
+	if code == "" {
+		return nil, ErrInviteNotFound
+	}
+	if err := validateUsername(req.Username); err != nil {
+		return nil, err
+	}
+	if req.Password == "" {
+		return nil, fmt.Errorf("password is required")
+	}
+	if req.Now.IsZero() {
+		req.Now = s.currentTime()
+	}
+
+	password, err := s.sanitizeNewPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueness, err := s.userUniquenessRepository()
+	if err != nil {
+		return nil, err
+	}
+	if exists, err := uniqueness.UsernameExists(ctx, req.Username); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, ErrUserAlreadyExists
+	}
+
+	displayName, err := s.registrationDisplayName(ctx, uniqueness, req.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+	email, err := registrationEmail(ctx, uniqueness, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := uniqueAPIKey(ctx, uniqueness)
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.inviteRepository()
+	if err != nil {
+		return nil, err
+	}
+	return repo.RedeemInvite(ctx, HashInviteCode(code), func(invite *Invite) (*User, error) {
+		if err := invite.CheckRedeemable(req.Now); err != nil {
+			return nil, err
+		}
+
+		balance := req.DefaultInitialAccountBalance
+		if invite.InitialAccountBalance != nil {
+			balance = *invite.InitialAccountBalance
+		}
+
+		user := &User{
+			Username:              req.Username,
+			DisplayName:           displayName,
+			Email:                 email,
+			APIKey:                apiKey,
+			PasswordHash:          passwordHash,
+			UserType:              invite.UserType,
+			InitialAccountBalance: balance,
+			AccountBalance:        balance,
+			PersonalEmoji:         randomEmoji(),
+			MustChangePassword:    false,
+		}
+		if NormalizeUserType(user.UserType) == UserTypeModerator {
+			if err := user.PromoteToModerator(); err != nil {
+				return nil, err
+			}
+		}
+		user.NormalizeRoleState()
+		return user, nil
+	})
+}
+
+// HashInviteCode returns the storage key for an invite code.
+func HashInviteCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeInviteCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeInviteCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
+
+func generateInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate invite code: %w", err)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf), nil
+}
+
+// inviteAccess reports whether the actor may manage invites and whether it
+// holds users.create, which unlocks moderator invites and other actors' invites.
+// Active moderators without it may only mint and manage their own regular invites.
+func (s *Service) inviteAccess(ctx context.Context, actor *User) (bool, error) {
+	if actor == nil || actor.Username == "" {
+		return false, ErrUnauthorized
+	}
+	privileged, err := s.can(ctx, actor, permissions.UsersCreate)
+	if err != nil {
+		return false, err
+	}
+	if privileged {
+		return true, nil
+	}
+	if actor.IsActiveModerator() {
+		return false, nil
+	}
+	return false, ErrUnauthorized
+}
+
+func newInviteFromRequest(actor *User, req InviteCreateRequest, isAdmin bool) (*Invite, error) {
+
+	userType := NormalizeUserType(req.UserType)
+	switch userType {
+	case UserTypeRegular:
+	case UserTypeModerator:
+		if !isAdmin {
+			return nil, ErrUnauthorized
+		}
+	default:
+		return nil, ErrInvalidUserData
+	}
+
+	if req.InitialAccountBalance != nil {
+		if !isAdmin {
+			return nil, ErrUnauthorized
+		}
+		if *req.InitialAccountBalance < 0 {
+			return nil, ErrInvalidUserData
+		}
+	}
+
+	maxUses := req.MaxUses
+	if maxUses == 0 {
+		maxUses = 1
+	}
+	if maxUses < 0 || maxUses > maxInviteUses {
+		return nil, ErrInvalidUserData
+	}
+
+	now := req.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, ErrInvalidUserData
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if len(note) > maxInviteNoteLength {
+		return nil, ErrInvalidUserData
+	}
+
+	invite := &Invite{
+		CreatedBy: actor.Username,
+		UserType:  string(userType),
+		MaxUses:   maxUses,
+		Note:      note,
+	}
+	if req.InitialAccountBalance != nil {
+		balance := *req.InitialAccountBalance
+		invite.InitialAccountBalance = &balance
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.UTC()
+		invite.ExpiresAt = &expiresAt
+	}
+	return invite, nil
+}
+
+func (s *Service) registrationDisplayName(ctx context.Context, uniqueness UserUniquenessRepository, requested string) (string, error) {
+	if strings.TrimSpace(requested) == "" {
+		return uniqueDisplayName(ctx, uniqueness)
+	}
+	if err := profileFieldSpecs["display_name"].validate(requested); err != nil {
+		return "", err
+	}
+	displayName, err := s.sanitizeDisplayName(requested)
+	if err != nil {
+		return "", err
+	}
+	if exists, err := uniqueness.DisplayNameExists(ctx, displayName); err != nil {
+		return "", err
+	} else if exists {
+		return "", ErrUserAlreadyExists
+	}
+	return displayName, nil
+}
+
+func registrationEmail(ctx context.Context, uniqueness UserUniquenessRepository, requested string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(requested))
+	if email == "" {
+		return uniqueEmail(ctx, uniqueness)
+	}
+	if exists, err := uniqueness.EmailExists(ctx, email); err != nil {
+		return "", err
+	} else if exists {
+		return "", ErrUserAlreadyExists
+	}
+	return email, nil
+}
+
+func (s *Service) inviteRepository() (InviteRepository, error) {
+	if s == nil || s.invites == nil {
+		return nil, ErrInvalidUserData
+	}
+	return s.invites, nil
+}
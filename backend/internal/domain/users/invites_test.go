@@ -0,0 +1,260 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	users "socialpredict/internal/domain/users"
+	"socialpredict/security"
+)
+
+type fakeInviteRepository struct {
+	invites []*users.Invite
+	created []*users.User
+	taken   map[string]bool
+}
+
+func newFakeInviteRepository() *fakeInviteRepository {
+	return &fakeInviteRepository{taken: map[string]bool{}}
+}
+
+func (f *fakeInviteRepository) CreateInvite(_ context.Context, invite *users.Invite) error {
+	invite.ID = int64(len(f.invites) + 1)
+	f.invites = append(f.invites, invite)
+	return nil
+}
+
+func (f *fakeInviteRepository) ListInvites(_ context.Context, filters users.InviteListFilters) ([]*users.Invite, error) {
+	var out []*users.Invite
+	for _, invite := range f.invites {
+		if filters.CreatedBy != "" && invite.CreatedBy != filters.CreatedBy {
+			continue
+		}
+		out = append(out, invite)
+	}
+	return out, nil
+}
+
+func (f *fakeInviteRepository) CountInvites(ctx context.Context, filters users.InviteListFilters) (int64, error) {
+	invites, err := f.ListInvites(ctx, users.InviteListFilters{CreatedBy: filters.CreatedBy})
+	return int64(len(invites)), err
+}
+
+func (f *fakeInviteRepository) GetInvite(_ context.Context, id int64) (*users.Invite, error) {
+	for _, invite := range f.invites {
+		if invite.ID == id {
+			copy := *invite
+			return &copy, nil
+		}
+	}
+	return nil, users.ErrInviteNotFound
+}
+
+func (f *fakeInviteRepository) RevokeInvite(_ context.Context, id int64, actorUsername string, revokedAt time.Time) error {
+	for _, invite := range f.invites {
+		if invite.ID == id && invite.RevokedAt == nil {
+			invite.RevokedAt = &revokedAt
+			invite.RevokedBy = actorUsername
+			return nil
+		}
+	}
+	return users.ErrInviteNotFound
+}
+
+func (f *fakeInviteRepository) RedeemInvite(_ context.Context, codeHash string, build users.InviteRedemptionFunc) (*users.User, error) {
+	for _, invite := range f.invites {
+		if invite.CodeHash != codeHash {
+			continue
+		}
+		copy := *invite
+		user, err := build(&copy)
+		if err != nil {
+			return nil, err
+		}
+		invite.UseCount++
+		f.created = append(f.created, user)
+		f.taken[user.Username] = true
+		return user, nil
+	}
+	return nil, users.ErrInviteNotFound
+}
+
+func (f *fakeInviteRepository) UsernameExists(_ context.Context, username string) (bool, error) {
+	return f.taken[username], nil
+}
+
+func (f *fakeInviteRepository) DisplayNameExists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeInviteRepository) EmailExists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeInviteRepository) APIKeyExists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeInviteRepository) AnyUserIdentityExists(context.Context, string, string, string, string) (bool, error) {
+	return false, nil
+}
+
+func newInviteTestService(repo *fakeInviteRepository) *users.Service {
+	return users.NewServiceWithDependencies(users.ServiceDependencies{
+		Uniqueness: repo,
+		Invites:    repo,
+	}, nil, security.NewSecurityService().Sanitizer)
+}
+
+func inviteAdmin() *users.User {
+	return &users.User{Username: "admin", UserType: string(users.UserTypeAdmin)}
+}
+
+func inviteModerator() *users.User {
+	return &users.User{
+		Username:        "mod",
+		UserType:        string(users.UserTypeModerator),
+		ModeratorStatus: users.ModeratorStatusActive,
+	}
+}
+
+func TestServiceRegisterWithInviteConsumesSingleUse(t *testing.T) {
+	repo := newFakeInviteRepository()
+	service := newInviteTestService(repo)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 22, 9, 0, 0, 0, time.UTC)
+
+	minted, err := service.CreateInvite(ctx, inviteAdmin(), users.InviteCreateRequest{Now: now})
+	if err != nil {
+		t.Fatalf("CreateInvite returned error: %v", err)
+	}
+	if minted.Code == "" || minted.Invite.CodeHash == minted.Code || minted.Invite.MaxUses != 1 {
+		t.Fatalf("unexpected invite result: %+v", minted.Invite)
+	}
+
+	user, err := service.RegisterWithInvite(ctx, users.InviteRegistrationRequest{
+		Code:                         minted.Code,
+		Username:                     "newcomer",
+		Password:                     "Sup3rSecret!Pass",
+		DefaultInitialAccountBalance: 100,
+		Now:                          now,
+	})
+	if err != nil {
+		t.Fatalf("RegisterWithInvite returned error: %v", err)
+	}
+	if user.UserType != string(users.UserTypeRegular) || user.AccountBalance != 100 || user.MustChangePassword {
+		t.Fatalf("unexpected registered user: %+v", user)
+	}
+	if user.PasswordHash == "" || user.APIKey == "" || user.DisplayName == "" {
+		t.Fatalf("expected generated credentials and identity, got %+v", user)
+	}
+
+	_, err = service.RegisterWithInvite(ctx, users.InviteRegistrationRequest{
+		Code:     minted.Code,
+		Username: "latecomer",
+		Password: "Sup3rSecret!Pass",
+		Now:      now,
+	})
+	if !errors.Is(err, users.ErrInviteUnavailable) {
+		t.Fatalf("second redemption error = %v, want ErrInviteUnavailable", err)
+	}
+}
+
+func TestServiceRegisterWithInviteRejectsExpiredAndRevokedCodes(t *testing.T) {
+	repo := newFakeInviteRepository()
+	service := newInviteTestService(repo)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 22, 9, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+
+	expiring, err := service.CreateInvite(ctx, inviteAdmin(), users.InviteCreateRequest{ExpiresAt: &expiresAt, Now: now})
+	if err != nil {
+		t.Fatalf("CreateInvite returned error: %v", err)
+	}
+	_, err = service.RegisterWithInvite(ctx, users.InviteRegistrationRequest{
+		Code:     expiring.Code,
+		Username: "toolate",
+		Password: "Sup3rSecret!Pass",
+		Now:      expiresAt,
+	})
+	if !errors.Is(err, users.ErrInviteUnavailable) {
+		t.Fatalf("expired redemption error = %v, want ErrInviteUnavailable", err)
+	}
+
+	revoked, err := service.CreateInvite(ctx, inviteAdmin(), users.InviteCreateRequest{MaxUses: 5, Now: now})
+	if err != nil {
+		t.Fatalf("CreateInvite returned error: %v", err)
+	}
+	if _, err := service.RevokeInvite(ctx, inviteAdmin(), revoked.Invite.ID, now); err != nil {
+		t.Fatalf("RevokeInvite returned error: %v", err)
+	}
+	_, err = service.RegisterWithInvite(ctx, users.InviteRegistrationRequest{
+		Code:     revoked.Code,
+		Username: "revokeduser",
+		Password: "Sup3rSecret!Pass",
+		Now:      now,
+	})
+	if !errors.Is(err, users.ErrInviteUnavailable) {
+		t.Fatalf("revoked redemption error = %v, want ErrInviteUnavailable", err)
+	}
+
+	_, err = service.RegisterWithInvite(ctx, users.InviteRegistrationRequest{
+		Code:     "NOT-A-REAL-CODE",
+		Username: "guesser",
+		Password: "Sup3rSecret!Pass",
+		Now:      now,
+	})
+	if !errors.Is(err, users.ErrInviteNotFound) {
+		t.Fatalf("unknown code error = %v, want ErrInviteNotFound", err)
+	}
+	if len(repo.created) != 0 {
+		t.Fatalf("expected no accounts created, got %d", len(repo.created))
+	}
+}
+
+func TestServiceModeratorInvitesAreScopedToRegularRole(t *testing.T) {
+	repo := newFakeInviteRepository()
+	service := newInviteTestService(repo)
+	ctx := context.Background()
+	balance := int64(5000)
+
+	if _, err := service.CreateInvite(ctx, inviteModerator(), users.InviteCreateRequest{UserType: "MODERATOR"}); !errors.Is(err, users.ErrUnauthorized) {
+		t.Fatalf("moderator minting MODERATOR invite error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := service.CreateInvite(ctx, inviteModerator(), users.InviteCreateRequest{InitialAccountBalance: &balance}); !errors.Is(err, users.ErrUnauthorized) {
+		t.Fatalf("moderator overriding balance error = %v, want ErrUnauthorized", err)
+	}
+
+	adminInvite, err := service.CreateInvite(ctx, inviteAdmin(), users.InviteCreateRequest{UserType: "MODERATOR", InitialAccountBalance: &balance})
+	if err != nil {
+		t.Fatalf("admin CreateInvite returned error: %v", err)
+	}
+	if _, err := service.CreateInvite(ctx, inviteModerator(), users.InviteCreateRequest{}); err != nil {
+		t.Fatalf("moderator CreateInvite returned error: %v", err)
+	}
+
+	visible, err := service.ListInvites(ctx, inviteModerator(), users.InviteListFilters{})
+	if err != nil {
+		t.Fatalf("ListInvites returned error: %v", err)
+	}
+	if len(visible.Invites) != 1 || visible.Invites[0].CreatedBy != "mod" || visible.Total != 1 {
+		t.Fatalf("expected moderator to see only their invite, got %+v", visible)
+	}
+	if _, err := service.RevokeInvite(ctx, inviteModerator(), adminInvite.Invite.ID, time.Now()); !errors.Is(err, users.ErrUnauthorized) {
+		t.Fatalf("moderator revoking admin invite error = %v, want ErrUnauthorized", err)
+	}
+
+	user, err := service.RegisterWithInvite(ctx, users.InviteRegistrationRequest{
+		Code:     adminInvite.Code,
+		Username: "newmod",
+		Password: "Sup3rSecret!Pass",
+	})
+	if err != nil {
+		t.Fatalf("RegisterWithInvite returned error: %v", err)
+	}
+	if !user.IsActiveModerator() || user.AccountBalance != balance {
+		t.Fatalf("expected active moderator with preset balance, got %+v", user)
+	}
+}
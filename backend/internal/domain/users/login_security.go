@@ -0,0 +1,444 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+)
+
+const (
+	defaultLoginFreeAttempts     = 3
+	defaultLoginBaseDelay        = time.Second
+	defaultLoginMaxDelay         = 5 * time.Minute
+	defaultLoginLockoutThreshold = 10
+	defaultLoginLockoutDuration  = 15 * time.Minute
+	minLoginThrottleRetention    = 24 * time.Hour
+	maxLoginAuditUserAgentLength = 512
+	maxLoginAuditReasonLength    = 200
+)
+
+// LoginOutcome classifies one recorded login event.
+type LoginOutcome string
+
+const (
+	// LoginOutcomeSuccess is a login that issued a session.
+	LoginOutcomeSuccess LoginOutcome = "success"
+	// LoginOutcomeFailure is a rejected credential check.
+	LoginOutcomeFailure LoginOutcome = "failure"
+	// LoginOutcomeThrottled is an attempt refused before credentials were
+	// checked because the account is in a delay window or locked out.
+	LoginOutcomeThrottled LoginOutcome = "throttled"
+	// LoginOutcomeUnlocked is an admin clearing an account's failure counters.
+	LoginOutcomeUnlocked LoginOutcome = "unlocked"
+)
+
+// Login methods recorded on audit events.
+const (
+	LoginMethodPassword = "password"
+	LoginMethodOIDC     = "oidc"
+	LoginMethodAdmin    = "admin"
+)
+
+// LoginThrottlePolicy controls progressive delays and temporary lockout after
+// repeated failed password logins for one account.
+//
+// The first FreeAttempts failures are not delayed. Each further failure makes
+// the account wait BaseDelay, doubling per failure up to MaxDelay, before the
+// next attempt is checked. Reaching LockoutThreshold failures locks the account
+// for LockoutDuration regardless of the delay schedule. Counters with no
+// failure for a day (or LockoutDuration, when longer) are forgotten and their
+// rows pruned, so guessing at many unknown usernames cannot grow the table
+// without bound.
+type LoginThrottlePolicy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+}
+
+// DefaultLoginThrottlePolicy returns the secure-default throttle schedule.
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		FreeAttempts:     defaultLoginFreeAttempts,
+		BaseDelay:        defaultLoginBaseDelay,
+		MaxDelay:         defaultLoginMaxDelay,
+		LockoutThreshold: defaultLoginLockoutThreshold,
+		LockoutDuration:  defaultLoginLockoutDuration,
+	}
+}
+
+func (p LoginThrottlePolicy) normalized() LoginThrottlePolicy {
+	defaults := DefaultLoginThrottlePolicy()
+	if p.FreeAttempts < 0 {
+		p.FreeAttempts = 0
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	if p.LockoutThreshold <= 0 {
+		p.LockoutThreshold = defaults.LockoutThreshold
+	}
+	if p.LockoutDuration <= 0 {
+		p.LockoutDuration = defaults.LockoutDuration
+	}
+	return p
+}
+
+// retention is how long after its last failure a throttle row is kept.
+func (p LoginThrottlePolicy) retention() time.Duration {
+	retention := minLoginThrottleRetention
+	if p.LockoutDuration > retention {
+		retention = p.LockoutDuration
+	}
+	if p.MaxDelay > retention {
+		retention = p.MaxDelay
+	}
+	return retention
+}
+
+// delayAfter returns how long the account must wait after its failures-th consecutive failure.
+func (p LoginThrottlePolicy) delayAfter(failures int) time.Duration {
+	excess := failures - p.FreeAttempts
+	if excess <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < excess; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// LoginThrottle is the persisted failed-attempt state for one username.
+type LoginThrottle struct {
+	Username       string
+	FailedAttempts int
+	LastFailedAt   *time.Time
+	NextAttemptAt  *time.Time
+	LockedUntil    *time.Time
+	UpdatedAt      time.Time
+}
+
+// Locked reports whether the account is inside a lockout window at now.
+func (t *LoginThrottle) Locked(now time.Time) bool {
+	return t != nil && t.LockedUntil != nil && now.Before(*t.LockedUntil)
+}
+
+// LoginDecision tells the login boundary whether a credential check may run.
+type LoginDecision struct {
+	Allowed    bool
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func decideLogin(throttle *LoginThrottle, now time.Time) LoginDecision {
+	if throttle == nil {
+		return LoginDecision{Allowed: true}
+	}
+	if throttle.Locked(now) {
+		return LoginDecision{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+	if throttle.NextAttemptAt != nil && now.Before(*throttle.NextAttemptAt) {
+		return LoginDecision{RetryAfter: throttle.NextAttemptAt.Sub(now)}
+	}
+	return LoginDecision{Allowed: true}
+}
+
+// applyFailure advances the throttle state for one more failed attempt.
+func (p LoginThrottlePolicy) applyFailure(throttle *LoginThrottle, now time.Time) {
+	if throttle.LockedUntil != nil && !now.Before(*throttle.LockedUntil) {
+		// An expired lockout starts a fresh schedule rather than relocking on the next miss.
+		throttle.FailedAttempts = 0
+		throttle.LockedUntil = nil
+	}
+	if throttle.LastFailedAt != nil && !now.Before(throttle.LastFailedAt.Add(p.retention())) {
+		// A row past retention may already have been pruned; start over either way.
+		throttle.FailedAttempts = 0
+	}
+
+	throttle.FailedAttempts++
+	failedAt := now
+	throttle.LastFailedAt = &failedAt
+	throttle.NextAttemptAt = nil
+
+	if throttle.FailedAttempts >= p.LockoutThreshold {
+		lockedUntil := now.Add(p.LockoutDuration)
+		throttle.LockedUntil = &lockedUntil
+		return
+	}
+	if delay := p.delayAfter(throttle.FailedAttempts); delay > 0 {
+		nextAttempt := now.Add(delay)
+		throttle.NextAttemptAt = &nextAttempt
+	}
+}
+
+// LoginAuditEvent is one recorded login-related event.
+type LoginAuditEvent struct {
+	ID            int64
+	Username      string
+	Outcome       LoginOutcome
+	Method        string
+	Reason        string
+	IPAddress     string
+	UserAgent     string
+	ActorUsername string
+	CreatedAt     time.Time
+}
+
+// LoginAuditFilters narrows admin login event listings.
+type LoginAuditFilters struct {
+	Username  string
+	Outcome   LoginOutcome
+	IPAddress string
+	Since     *time.Time
+	Limit     int
+	Offset    int
+}
+
+// LoginAttempt describes one login attempt observed at the HTTP boundary.
+type LoginAttempt struct {
+	Username  string
+	Outcome   LoginOutcome
+	Method    string
+	Reason    string
+	IPAddress string
+	UserAgent string
+	Policy    LoginThrottlePolicy
+	// Reserved marks a failure already counted by ReserveLoginAttempt, so
+	// recording it only appends the audit event.
+	Reserved bool
+	Now      time.Time
+}
+
+// LoginThrottleUpdateFunc mutates a throttle row loaded inside the repository's unit of work.
+type LoginThrottleUpdateFunc func(throttle *LoginThrottle) error
+
+// LoginSecurityRepository persists per-account failure counters and the login audit trail.
+type LoginSecurityRepository interface {
+	GetLoginThrottle(ctx context.Context, username string) (*LoginThrottle, error)
+	UpdateLoginThrottle(ctx context.Context, username string, update LoginThrottleUpdateFunc) (*LoginThrottle, error)
+	ClearLoginThrottle(ctx context.Context, username string) error
+	PruneLoginThrottles(ctx context.Context, failedBefore, now time.Time) error
+	CreateLoginAuditEvent(ctx context.Context, event *LoginAuditEvent) error
+	ListLoginAuditEvents(ctx context.Context, filters LoginAuditFilters) ([]*LoginAuditEvent, error)
+}
+
+// CheckLoginAllowed reports whether a password login for username may be
+// attempted at now. Unknown usernames are tracked the same way as real ones
+// so throttling does not reveal which accounts exist.
+func (s *Service) CheckLoginAllowed(ctx context.Context, username string, now time.Time) (LoginDecision, error) {
+	if err := validateUsername(username); err != nil {
+		return LoginDecision{}, err
+	}
+	repo, err := s.loginSecurityRepository()
+	if err != nil {
+		return LoginDecision{}, err
+	}
+	throttle, err := repo.GetLoginThrottle(ctx, username)
+	if err != nil {
+		return LoginDecision{}, err
+	}
+	return decideLogin(throttle, now), nil
+}
+
+// ReserveLoginAttempt decides whether a password login for username may be
+// attempted at now and, when it may, counts it as a failure in the same
+// locked update. Concurrent guesses therefore each see the ones before them
+// and cannot all slip past the threshold while their credential checks run.
+// A successful login clears the reservation by recording LoginOutcomeSuccess.
+func (s *Service) ReserveLoginAttempt(ctx context.Context, username string, policy LoginThrottlePolicy, now time.Time) (LoginDecision, error) {
+	if err := validateUsername(username); err != nil {
+		return LoginDecision{}, err
+	}
+	repo, err := s.loginSecurityRepository()
+	if err != nil {
+		return LoginDecision{}, err
+	}
+	if now.IsZero() {
+		now = s.currentTime()
+	}
+
+	policy = policy.normalized()
+	var decision LoginDecision
+	if _, err := repo.UpdateLoginThrottle(ctx, username, func(throttle *LoginThrottle) error {
+		decision = decideLogin(throttle, now)
+		if decision.Allowed {
+			policy.applyFailure(throttle, now)
+		}
+		return nil
+	}); err != nil {
+		return LoginDecision{}, err
+	}
+	return decision, nil
+}
+
+// RecordLoginAttempt appends the attempt to the login audit trail and updates
+// the account's failure counters: failures advance the delay and lockout
+// schedule (unless already reserved) and prune rows past retention, successes
+// clear it, and throttled attempts leave it unchanged.
+// The returned decision describes when the next attempt may be made.
+func (s *Service) RecordLoginAttempt(ctx context.Context, attempt LoginAttempt) (LoginDecision, error) {
+	if err := validateUsername(attempt.Username); err != nil {
+		return LoginDecision{}, err
+	}
+	repo, err := s.loginSecurityRepository()
+	if err != nil {
+		return LoginDecision{}, err
+	}
+	if attempt.Now.IsZero() {
+		attempt.Now = s.currentTime()
+	}
+
+	decision := LoginDecision{Allowed: true}
+	switch attempt.Outcome {
+	case LoginOutcomeFailure:
+		policy := attempt.Policy.normalized()
+		throttle, err := repo.UpdateLoginThrottle(ctx, attempt.Username, func(throttle *LoginThrottle) error {
+			if !attempt.Reserved {
+				policy.applyFailure(throttle, attempt.Now)
+			}
+			return nil
+		})
+		if err != nil {
+			return LoginDecision{}, err
+		}
+		if err := repo.PruneLoginThrottles(ctx, attempt.Now.Add(-policy.retention()), attempt.Now); err != nil {
+			return LoginDecision{}, err
+		}
+		decision = decideLogin(throttle, attempt.Now)
+	case LoginOutcomeSuccess:
+		if err := repo.ClearLoginThrottle(ctx, attempt.Username); err != nil {
+			return LoginDecision{}, err
+		}
+	case LoginOutcomeThrottled:
+		throttle, err := repo.GetLoginThrottle(ctx, attempt.Username)
+		if err != nil {
+			return LoginDecision{}, err
+		}
+		decision = decideLogin(throttle, attempt.Now)
+	default:
+		return LoginDecision{}, ErrInvalidUserData
+	}
+
+	event := &LoginAuditEvent{
+		Username:  attempt.Username,
+		Outcome:   attempt.Outcome,
+		Method:    attempt.Method,
+		Reason:    truncateAuditField(attempt.Reason, maxLoginAuditReasonLength),
+		IPAddress: strings.TrimSpace(attempt.IPAddress),
+		UserAgent: truncateAuditField(attempt.UserAgent, maxLoginAuditUserAgentLength),
+		CreatedAt: attempt.Now,
+	}
+	if err := repo.CreateLoginAuditEvent(ctx, event); err != nil {
+		return LoginDecision{}, err
+	}
+	return decision, nil
+}
+
+// GetLoginThrottle returns the stored failure counters for username. Requires users.manage.
+// Accounts with no recorded failures return an empty throttle.
+func (s *Service) GetLoginThrottle(ctx context.Context, actor *User, username string) (*LoginThrottle, error) {
+	if err := s.requirePermission(ctx, actor, permissions.UsersManage); err != nil {
+		return nil, err
+	}
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	repo, err := s.loginSecurityRepository()
+	if err != nil {
+		return nil, err
+	}
+	throttle, err := repo.GetLoginThrottle(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if throttle == nil {
+		return &LoginThrottle{Username: username}, nil
+	}
+	return throttle, nil
+}
+
+// UnlockLogin clears the failure counters and any lockout for username and
+// records the unlock in the login audit trail. Requires users.manage.
+func (s *Service) UnlockLogin(ctx context.Context, actor *User, username, reason string, now time.Time) (*LoginThrottle, error) {
+	if err := s.requirePermission(ctx, actor, permissions.UsersManage); err != nil {
+		return nil, err
+	}
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	if _, err := s.requireUser(ctx, username); err != nil {
+		return nil, err
+	}
+	repo, err := s.loginSecurityRepository()
+	if err != nil {
+		return nil, err
+	}
+	if now.IsZero() {
+		now = s.currentTime()
+	}
+
+	if err := repo.ClearLoginThrottle(ctx, username); err != nil {
+		return nil, err
+	}
+	event := &LoginAuditEvent{
+		Username:      username,
+		Outcome:       LoginOutcomeUnlocked,
+		Method:        LoginMethodAdmin,
+		Reason:        truncateAuditField(reason, maxLoginAuditReasonLength),
+		ActorUsername: actor.Username,
+		CreatedAt:     now,
+	}
+	if err := repo.CreateLoginAuditEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	return &LoginThrottle{Username: username, UpdatedAt: now}, nil
+}
+
+// ListLoginAuditEvents returns login events newest first. Requires users.manage.
+func (s *Service) ListLoginAuditEvents(ctx context.Context, actor *User, filters LoginAuditFilters) ([]*LoginAuditEvent, error) {
+	if err := s.requirePermission(ctx, actor, permissions.UsersManage); err != nil {
+		return nil, err
+	}
+	switch filters.Outcome {
+	case "", LoginOutcomeSuccess, LoginOutcomeFailure, LoginOutcomeThrottled, LoginOutcomeUnlocked:
+	default:
+		return nil, ErrInvalidUserData
+	}
+	repo, err := s.loginSecurityRepository()
+	if err != nil {
+		return nil, err
+	}
+	events, err := repo.ListLoginAuditEvents(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		return []*LoginAuditEvent{}, nil
+	}
+	return events, nil
+}
+
+func truncateAuditField(value string, limit int) string {
+	value = strings.TrimSpace(value)
+	if len(value) <= limit {
+		return value
+	}
+	return strings.ToValidUTF8(value[:limit], "")
+}
+
+func (s *Service) loginSecurityRepository() (LoginSecurityRepository, error) {
+	if s == nil || s.loginSecurity == nil {
+		return nil, ErrInvalidUserData
+	}
+	return s.loginSecurity, nil
+}
@@ -0,0 +1,267 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/permissions"
+	users "socialpredict/internal/domain/users"
+)
+
+type fakeLoginSecurityRepository struct {
+	throttles map[string]*users.LoginThrottle
+	events    []*users.LoginAuditEvent
+}
+
+func newFakeLoginSecurityRepository() *fakeLoginSecurityRepository {
+	return &fakeLoginSecurityRepository{throttles: map[string]*users.LoginThrottle{}}
+}
+
+func (f *fakeLoginSecurityRepository) GetLoginThrottle(_ context.Context, username string) (*users.LoginThrottle, error) {
+	throttle, ok := f.throttles[username]
+	if !ok {
+		return nil, nil
+	}
+	copy := *throttle
+	return &copy, nil
+}
+
+func (f *fakeLoginSecurityRepository) UpdateLoginThrottle(_ context.Context, username string, update users.LoginThrottleUpdateFunc) (*users.LoginThrottle, error) {
+	throttle, ok := f.throttles[username]
+	if !ok {
+		throttle = &users.LoginThrottle{Username: username}
+	}
+	if err := update(throttle); err != nil {
+		return nil, err
+	}
+	f.throttles[username] = throttle
+	copy := *throttle
+	return &copy, nil
+}
+
+func (f *fakeLoginSecurityRepository) ClearLoginThrottle(_ context.Context, username string) error {
+	delete(f.throttles, username)
+	return nil
+}
+
+func (f *fakeLoginSecurityRepository) PruneLoginThrottles(_ context.Context, failedBefore, now time.Time) error {
+	for username, throttle := range f.throttles {
+		if throttle.LastFailedAt != nil && throttle.LastFailedAt.Before(failedBefore) && !throttle.Locked(now) {
+			delete(f.throttles, username)
+		}
+	}
+	return nil
+}
+
+func (f *fakeLoginSecurityRepository) CreateLoginAuditEvent(_ context.Context, event *users.LoginAuditEvent) error {
+	event.ID = int64(len(f.events) + 1)
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeLoginSecurityRepository) ListLoginAuditEvents(context.Context, users.LoginAuditFilters) ([]*users.LoginAuditEvent, error) {
+	return f.events, nil
+}
+
+func newLoginSecurityTestService(repo *fakeLoginSecurityRepository, existing *users.User) *users.Service {
+	reader := &fakeRepository{user: existing}
+	return users.NewServiceWithDependencies(users.ServiceDependencies{
+		Reader:        reader,
+		LoginSecurity: repo,
+	}, nil, nil)
+}
+
+func TestServiceRecordLoginAttemptDelaysThenLocksOut(t *testing.T) {
+	repo := newFakeLoginSecurityRepository()
+	service := newLoginSecurityTestService(repo, nil)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 24, 9, 0, 0, 0, time.UTC)
+	policy := users.LoginThrottlePolicy{FreeAttempts: 2, BaseDelay: time.Second, MaxDelay: 4 * time.Second, LockoutThreshold: 5, LockoutDuration: time.Hour}
+
+	fail := func() users.LoginDecision {
+		t.Helper()
+		decision, err := service.RecordLoginAttempt(ctx, users.LoginAttempt{
+			Username: "ada", Outcome: users.LoginOutcomeFailure, Method: users.LoginMethodPassword, IPAddress: "203.0.113.9", Policy: policy, Now: now,
+		})
+		if err != nil {
+			t.Fatalf("RecordLoginAttempt returned error: %v", err)
+		}
+		return decision
+	}
+
+	for i := 0; i < 2; i++ {
+		if decision := fail(); !decision.Allowed {
+			t.Fatalf("free attempt %d should not be delayed: %+v", i+1, decision)
+		}
+	}
+	if decision := fail(); decision.Allowed || decision.RetryAfter != time.Second {
+		t.Fatalf("third failure decision = %+v, want 1s delay", decision)
+	}
+	if decision, _ := service.CheckLoginAllowed(ctx, "ada", now.Add(500*time.Millisecond)); decision.Allowed {
+		t.Fatalf("attempt inside delay window should be refused")
+	}
+	now = now.Add(time.Second)
+	if decision := fail(); decision.RetryAfter != 2*time.Second {
+		t.Fatalf("fourth failure decision = %+v, want 2s delay", decision)
+	}
+	now = now.Add(2 * time.Second)
+	decision := fail()
+	if !decision.Locked || decision.RetryAfter != time.Hour {
+		t.Fatalf("fifth failure decision = %+v, want 1h lockout", decision)
+	}
+
+	if len(repo.events) != 5 || repo.events[0].IPAddress != "203.0.113.9" || repo.events[0].Outcome != users.LoginOutcomeFailure {
+		t.Fatalf("unexpected audit events: %+v", repo.events)
+	}
+
+	if decision, _ := service.CheckLoginAllowed(ctx, "ada", now.Add(time.Hour)); !decision.Allowed {
+		t.Fatalf("lockout should expire: %+v", decision)
+	}
+}
+
+func TestServiceReserveLoginAttemptCountsBeforeCredentialCheck(t *testing.T) {
+	repo := newFakeLoginSecurityRepository()
+	service := newLoginSecurityTestService(repo, nil)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 24, 9, 0, 0, 0, time.UTC)
+	policy := users.LoginThrottlePolicy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Minute, LockoutThreshold: 10, LockoutDuration: time.Hour}
+
+	// Two reservations are outstanding before either credential check finishes.
+	for i := 0; i < 2; i++ {
+		if decision, err := service.ReserveLoginAttempt(ctx, "ada", policy, now); err != nil || !decision.Allowed {
+			t.Fatalf("reservation %d = %+v, %v; want allowed", i+1, decision, err)
+		}
+	}
+	if decision, _ := service.ReserveLoginAttempt(ctx, "ada", policy, now); decision.Allowed || decision.RetryAfter != time.Minute {
+		t.Fatalf("third reservation = %+v, want 1m delay", decision)
+	}
+	if got := repo.throttles["ada"].FailedAttempts; got != 2 {
+		t.Fatalf("failed attempts = %d, want 2 (refused reservations are not counted)", got)
+	}
+
+	// Recording the reserved failures audits them without counting them again.
+	for i := 0; i < 2; i++ {
+		if _, err := service.RecordLoginAttempt(ctx, users.LoginAttempt{Username: "ada", Outcome: users.LoginOutcomeFailure, Reserved: true, Policy: policy, Now: now}); err != nil {
+			t.Fatalf("RecordLoginAttempt returned error: %v", err)
+		}
+	}
+	if got := repo.throttles["ada"].FailedAttempts; got != 2 || len(repo.events) != 2 {
+		t.Fatalf("after recording: failed attempts = %d, events = %d; want 2 and 2", got, len(repo.events))
+	}
+}
+
+func TestServiceRecordLoginAttemptPrunesStaleThrottles(t *testing.T) {
+	repo := newFakeLoginSecurityRepository()
+	service := newLoginSecurityTestService(repo, nil)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 24, 9, 0, 0, 0, time.UTC)
+	policy := users.LoginThrottlePolicy{FreeAttempts: 0, BaseDelay: time.Second, MaxDelay: time.Minute, LockoutThreshold: 2, LockoutDuration: 48 * time.Hour}
+
+	fail := func(username string) {
+		t.Helper()
+		if _, err := service.RecordLoginAttempt(ctx, users.LoginAttempt{
+			Username: username, Outcome: users.LoginOutcomeFailure, Method: users.LoginMethodPassword, Policy: policy, Now: now,
+		}); err != nil {
+			t.Fatalf("RecordLoginAttempt returned error: %v", err)
+		}
+	}
+
+	for _, username := range []string{"ghost1", "ghost2", "ghost3"} {
+		fail(username)
+	}
+	fail("ada")
+	fail("ada")
+	if len(repo.throttles) != 4 || !repo.throttles["ada"].Locked(now) {
+		t.Fatalf("expected four throttles with ada locked, got %+v", repo.throttles)
+	}
+
+	// Past the lockout-length retention every earlier row is stale.
+	now = now.Add(48*time.Hour + time.Minute)
+	fail("ghost4")
+	if len(repo.throttles) != 1 || repo.throttles["ghost4"] == nil {
+		t.Fatalf("expected only the fresh throttle to remain, got %+v", repo.throttles)
+	}
+}
+
+func TestServiceRecordLoginAttemptSuccessClearsCounters(t *testing.T) {
+	repo := newFakeLoginSecurityRepository()
+	service := newLoginSecurityTestService(repo, nil)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 24, 9, 0, 0, 0, time.UTC)
+
+	if _, err := service.RecordLoginAttempt(ctx, users.LoginAttempt{Username: "ada", Outcome: users.LoginOutcomeFailure, Now: now}); err != nil {
+		t.Fatalf("record failure: %v", err)
+	}
+	if _, err := service.RecordLoginAttempt(ctx, users.LoginAttempt{Username: "ada", Outcome: users.LoginOutcomeSuccess, UserAgent: "test-agent", Now: now}); err != nil {
+		t.Fatalf("record success: %v", err)
+	}
+	if _, ok := repo.throttles["ada"]; ok {
+		t.Fatalf("expected success to clear throttle")
+	}
+	if last := repo.events[len(repo.events)-1]; last.Outcome != users.LoginOutcomeSuccess || last.UserAgent != "test-agent" {
+		t.Fatalf("unexpected success event: %+v", last)
+	}
+}
+
+func TestServiceUnlockLoginRequiresAdminAndAudits(t *testing.T) {
+	repo := newFakeLoginSecurityRepository()
+	service := newLoginSecurityTestService(repo, &users.User{Username: "ada", UserType: "REGULAR"})
+	ctx := context.Background()
+	locked := time.Now().Add(time.Hour)
+	repo.throttles["ada"] = &users.LoginThrottle{Username: "ada", FailedAttempts: 10, LockedUntil: &locked}
+
+	if _, err := service.UnlockLogin(ctx, &users.User{Username: "mod", UserType: "MODERATOR"}, "ada", "", time.Now()); !errors.Is(err, users.ErrUnauthorized) {
+		t.Fatalf("non-admin unlock error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := service.UnlockLogin(ctx, &users.User{Username: "root", UserType: "ADMIN"}, "ghost", "", time.Now()); !errors.Is(err, users.ErrUserNotFound) {
+		t.Fatalf("unknown user unlock error = %v, want ErrUserNotFound", err)
+	}
+
+	throttle, err := service.UnlockLogin(ctx, &users.User{Username: "root", UserType: "ADMIN"}, "ada", "verified by phone", time.Now())
+	if err != nil {
+		t.Fatalf("UnlockLogin returned error: %v", err)
+	}
+	if throttle.FailedAttempts != 0 || throttle.Locked(time.Now()) {
+		t.Fatalf("expected cleared throttle, got %+v", throttle)
+	}
+	if _, ok := repo.throttles["ada"]; ok {
+		t.Fatalf("expected stored throttle to be cleared")
+	}
+	event := repo.events[len(repo.events)-1]
+	if event.Outcome != users.LoginOutcomeUnlocked || event.ActorUsername != "root" || event.Reason != "verified by phone" {
+		t.Fatalf("unexpected unlock event: %+v", event)
+	}
+}
+
+func TestServiceListLoginAuditEventsRejectsUnknownOutcome(t *testing.T) {
+	service := newLoginSecurityTestService(newFakeLoginSecurityRepository(), nil)
+	admin := &users.User{Username: "root", UserType: "ADMIN"}
+
+	if _, err := service.ListLoginAuditEvents(context.Background(), admin, users.LoginAuditFilters{Outcome: "maybe"}); !errors.Is(err, users.ErrInvalidUserData) {
+		t.Fatalf("error = %v, want ErrInvalidUserData", err)
+	}
+	if _, err := service.ListLoginAuditEvents(context.Background(), &users.User{Username: "ada", UserType: "REGULAR"}, users.LoginAuditFilters{}); !errors.Is(err, users.ErrUnauthorized) {
+		t.Fatalf("error = %v, want ErrUnauthorized", err)
+	}
+}
+
+type grantAuthorizer map[permissions.Permission]bool
+
+func (g grantAuthorizer) Can(_ context.Context, _ permissions.Subject, permission permissions.Permission) (bool, error) {
+	return g[permission], nil
+}
+
+func TestServiceLoginSecurityHonoursDelegatedPermission(t *testing.T) {
+	service := newLoginSecurityTestService(newFakeLoginSecurityRepository(), nil)
+	support := &users.User{Username: "support", UserType: "REGULAR"}
+
+	if _, err := service.ListLoginAuditEvents(context.Background(), support, users.LoginAuditFilters{}); !errors.Is(err, users.ErrUnauthorized) {
+		t.Fatalf("error without grant = %v, want ErrUnauthorized", err)
+	}
+	service.SetAuthorizer(grantAuthorizer{permissions.UsersManage: true})
+	if _, err := service.ListLoginAuditEvents(context.Background(), support, users.LoginAuditFilters{}); err != nil {
+		t.Fatalf("users.manage holder should list events: %v", err)
+	}
+}
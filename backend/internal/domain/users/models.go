@@ -17,6 +17,7 @@ type User struct {
 	Username                  string
 	DisplayName               string
 	Email                     string
+	EmailVerifiedAt           *time.Time
 	PasswordHash              string
 	UserType                  string
 	InitialAccountBalance     int64
@@ -33,6 +34,11 @@ type User struct {
 	ModeratorSuspensionReason string
 	ModeratorSuspendedBy      string
 	ModeratorSuspendedAt      *time.Time
+	AccountStatus             AccountStatus
+	AccountStatusReason       string
+	AccountStatusBy           string
+	AccountStatusAt           *time.Time
+	AccountStatusExpiresAt    *time.Time
 	CreatedAt                 time.Time
 	UpdatedAt                 time.Time
 }
@@ -66,6 +72,7 @@ func copyPublicUser(target *PublicUser, user *User) *PublicUser {
 		PersonalEmoji:         user.PersonalEmoji,
 		Description:           user.Description,
 		ModeratorStatus:       NormalizeModeratorStatus(user.UserType, string(user.ModeratorStatus)),
+		AccountStatus:         user.EffectiveAccountStatus(time.Now()),
 	}
 	user.PersonalLinks().ApplyTo(target)
 	return target
@@ -101,9 +108,14 @@ func copyPrivateProfile(target *PrivateProfile, user *User) *PrivateProfile {
 		ModeratorSuspensionReason: user.ModeratorSuspensionReason,
 		ModeratorSuspendedBy:      user.ModeratorSuspendedBy,
 		ModeratorSuspendedAt:      user.ModeratorSuspendedAt,
+		AccountStatus:             user.EffectiveAccountStatus(time.Now()),
 		CreatedAt:                 user.CreatedAt,
 		UpdatedAt:                 user.UpdatedAt,
 	}
+	if target.AccountStatus != AccountStatusActive {
+		target.AccountStatusReason = user.AccountStatusReason
+		target.AccountStatusExpiresAt = user.AccountStatusExpiresAt
+	}
 	user.PersonalLinks().ApplyTo(target)
 	return target
 }
@@ -154,6 +166,7 @@ type PublicUser struct {
 	PersonalLink3         string
 	PersonalLink4         string
 	ModeratorStatus       ModeratorStatus
+	AccountStatus         AccountStatus
 }
 
 // UserCreateRequest represents the data needed to create a new user
@@ -358,6 +371,9 @@ type PrivateProfile struct {
 	ModeratorSuspensionReason string
 	ModeratorSuspendedBy      string
 	ModeratorSuspendedAt      *time.Time
+	AccountStatus             AccountStatus
+	AccountStatusReason       string
+	AccountStatusExpiresAt    *time.Time
 	CreatedAt                 time.Time
 	UpdatedAt                 time.Time
 }
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	analytics "socialpredict/internal/domain/analytics"
+	"socialpredict/internal/domain/permissions"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -105,6 +107,12 @@ type ServiceDependencies struct {
 	Markets        UserMarketsRepository
 	Credentials    CredentialsRepository
 	ModeratorAudit ModeratorAuditWriter
+	Invites        InviteRepository
+	ExternalIDs    ExternalIdentityRepository
+	LoginSecurity  LoginSecurityRepository
+	AccountStatus  AccountStatusAuditRepository
+	// Clock supplies the current time; nil uses time.Now.
+	Clock func() time.Time
 }
 
 // ListFilters represents filters for listing users
@@ -131,17 +139,23 @@ type AnalyticsService interface {
 
 // Service implements the core user business logic
 type Service struct {
-	reader         UserReader
-	balanceRepo    UserBalanceRepository
-	writer         UserWriter
-	uniqueness     UserUniquenessRepository
-	lister         UserLister
-	portfolio      UserPortfolioRepository
-	markets        UserMarketsRepository
-	credentials    CredentialsRepository
-	moderatorAudit ModeratorAuditWriter
-	analytics      AnalyticsService
-	sanitizer      Sanitizer
+	reader             UserReader
+	balanceRepo        UserBalanceRepository
+	writer             UserWriter
+	uniqueness         UserUniquenessRepository
+	lister             UserLister
+	portfolio          UserPortfolioRepository
+	markets            UserMarketsRepository
+	credentials        CredentialsRepository
+	moderatorAudit     ModeratorAuditWriter
+	invites            InviteRepository
+	externalIdentities ExternalIdentityRepository
+	loginSecurity      LoginSecurityRepository
+	accountStatusAudit AccountStatusAuditRepository
+	authorizer         permissions.Authorizer
+	analytics          AnalyticsService
+	sanitizer          Sanitizer
+	now                func() time.Time
 }
 
 type profileMutation func(*User) error
@@ -253,24 +267,57 @@ func NewService(repo Repository, analyticsSvc AnalyticsService, sanitizer Saniti
 	if moderatorAudit, ok := repo.(ModeratorAuditWriter); ok {
 		deps.ModeratorAudit = moderatorAudit
 	}
+	if invites, ok := repo.(InviteRepository); ok {
+		deps.Invites = invites
+	}
+	if externalIDs, ok := repo.(ExternalIdentityRepository); ok {
+		deps.ExternalIDs = externalIDs
+	}
+	if loginSecurity, ok := repo.(LoginSecurityRepository); ok {
+		deps.LoginSecurity = loginSecurity
+	}
+	if accountStatus, ok := repo.(AccountStatusAuditRepository); ok {
+		deps.AccountStatus = accountStatus
+	}
 	return NewServiceWithDependencies(deps, analyticsSvc, sanitizer)
 }
 
 // NewServiceWithDependencies creates a new users service from explicit ports.
 func NewServiceWithDependencies(deps ServiceDependencies, analyticsSvc AnalyticsService, sanitizer Sanitizer) *Service {
 	return &Service{
-		reader:         deps.Reader,
-		balanceRepo:    deps.BalanceRepo,
-		writer:         deps.Writer,
-		uniqueness:     deps.Uniqueness,
-		lister:         deps.Lister,
-		portfolio:      deps.Portfolio,
-		markets:        deps.Markets,
-		credentials:    deps.Credentials,
-		moderatorAudit: deps.ModeratorAudit,
-		analytics:      analyticsSvc,
-		sanitizer:      sanitizer,
-	}
+		reader:             deps.Reader,
+		balanceRepo:        deps.BalanceRepo,
+		writer:             deps.Writer,
+		uniqueness:         deps.Uniqueness,
+		lister:             deps.Lister,
+		portfolio:          deps.Portfolio,
+		markets:            deps.Markets,
+		credentials:        deps.Credentials,
+		moderatorAudit:     deps.ModeratorAudit,
+		invites:            deps.Invites,
+		externalIdentities: deps.ExternalIDs,
+		loginSecurity:      deps.LoginSecurity,
+		accountStatusAudit: deps.AccountStatus,
+		analytics:          analyticsSvc,
+		sanitizer:          sanitizer,
+		now:                deps.Clock,
+	}
+}
+
+// SetClock configures the service's source of the current time. Without one,
+// time.Now is used.
+func (s *Service) SetClock(now func() time.Time) {
+	if s != nil {
+		s.now = now
+	}
+}
+
+// currentTime returns the service clock's time in UTC.
+func (s *Service) currentTime() time.Time {
+	if s == nil || s.now == nil {
+		return time.Now().UTC()
+	}
+	return s.now().UTC()
 }
 
 // ValidateUserExists checks if a user exists
@@ -0,0 +1,299 @@
+package watchlists
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+const (
+	// maxFollows caps one user's watchlist.
+	maxFollows = 500
+	// tagMarketLimit caps how many active markets each followed tag shows.
+	tagMarketLimit = 10
+)
+
+// Service lets users follow markets, market groups, and tags, and resolves
+// their watchlist to live markets.
+type Service struct {
+	repo    Repository
+	markets Markets
+	now     func() time.Time
+}
+
+// NewService constructs a watchlists service.
+func NewService(repo Repository, markets Markets, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{repo: repo, markets: markets, now: now}
+}
+
+// Follow adds target to the user's watchlist. Following something already on
+// the list returns the existing entry with created false. Only published,
+// closed, and resolved markets and groups, and active tags, can be followed.
+func (s *Service) Follow(ctx context.Context, username string, target Target) (*Follow, bool, error) {
+	if err := s.ready(username); err != nil {
+		return nil, false, err
+	}
+	target, err := Normalize(target)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.checkFollowable(ctx, target); err != nil {
+		return nil, false, err
+	}
+	count, err := s.repo.CountFollows(ctx, username)
+	if err != nil {
+		return nil, false, err
+	}
+	if count >= maxFollows {
+		// Re-following is still fine; AddFollow reports it as not created.
+		follows, err := s.repo.ListFollows(ctx, username)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, follow := range follows {
+			if follow.Target == target {
+				return follow, false, nil
+			}
+		}
+		return nil, false, ErrWatchlistFull
+	}
+	follow := &Follow{Username: username, Target: target, CreatedAt: s.now().UTC()}
+	created, err := s.repo.AddFollow(ctx, follow)
+	if err != nil {
+		return nil, false, err
+	}
+	return follow, created, nil
+}
+
+// Unfollow removes target from the user's watchlist. Removing something that
+// is not on the list, or no longer exists, is not an error.
+func (s *Service) Unfollow(ctx context.Context, username string, target Target) error {
+	if err := s.ready(username); err != nil {
+		return err
+	}
+	target, err := Normalize(target)
+	if err != nil {
+		return err
+	}
+	return s.repo.RemoveFollow(ctx, username, target)
+}
+
+// Watchlist returns what the user follows with the markets behind each
+// follow.
+func (s *Service) Watchlist(ctx context.Context, username string) (*Watchlist, error) {
+	if err := s.ready(username); err != nil {
+		return nil, err
+	}
+	follows, err := s.repo.ListFollows(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	watchlist := &Watchlist{Follows: follows, Rows: []dmarkets.MarketDiscoveryRow{}, Tags: []TagWatch{}}
+
+	var tags map[string]dmarkets.MarketTag
+	for _, follow := range follows {
+		switch follow.Target.Type {
+		case TargetMarket:
+			id, _ := strconv.ParseInt(follow.Target.ID, 10, 64)
+			market, err := s.markets.GetMarket(ctx, id)
+			if errors.Is(err, dmarkets.ErrMarketNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if visible(market.LifecycleStatus) {
+				watchlist.Rows = append(watchlist.Rows, dmarkets.MarketDiscoveryRow{Market: market})
+			}
+		case TargetMarketGroup:
+			id, _ := strconv.ParseInt(follow.Target.ID, 10, 64)
+			row, ok, err := s.groupRow(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				watchlist.Rows = append(watchlist.Rows, row)
+			}
+		case TargetTag:
+			if tags == nil {
+				if tags, err = s.activeTags(ctx); err != nil {
+					return nil, err
+				}
+			}
+			tag, ok := tags[follow.Target.ID]
+			if !ok {
+				continue
+			}
+			page, err := s.markets.ListMarketDiscovery(ctx, dmarkets.ListFilters{Status: dmarkets.MarketStatusActive, TagSlug: tag.Slug, Limit: tagMarketLimit})
+			if err != nil {
+				return nil, err
+			}
+			rows := []dmarkets.MarketDiscoveryRow{}
+			if page != nil && page.Rows != nil {
+				rows = page.Rows
+			}
+			watchlist.Tags = append(watchlist.Tags, TagWatch{Tag: tag, Rows: rows})
+		}
+	}
+	return watchlist, nil
+}
+
+// FollowerCount returns how many users follow target.
+func (s *Service) FollowerCount(ctx context.Context, target Target) (int64, error) {
+	if s == nil || s.repo == nil {
+		return 0, errors.New("watchlists service unavailable")
+	}
+	target, err := Normalize(target)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.CountFollowers(ctx, target)
+}
+
+// Followers returns everyone who follows at least one of targets.
+func (s *Service) Followers(ctx context.Context, targets ...Target) ([]string, error) {
+	if s == nil || s.repo == nil {
+		return nil, errors.New("watchlists service unavailable")
+	}
+	normalized := make([]Target, 0, len(targets))
+	for _, target := range targets {
+		target, err := Normalize(target)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, target)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return s.repo.ListFollowers(ctx, normalized)
+}
+
+func (s *Service) checkFollowable(ctx context.Context, target Target) error {
+	switch target.Type {
+	case TargetMarket:
+		id, _ := strconv.ParseInt(target.ID, 10, 64)
+		market, err := s.markets.GetMarket(ctx, id)
+		if errors.Is(err, dmarkets.ErrMarketNotFound) {
+			return ErrTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !visible(market.LifecycleStatus) {
+			return ErrTargetNotFound
+		}
+	case TargetMarketGroup:
+		id, _ := strconv.ParseInt(target.ID, 10, 64)
+		group, err := s.markets.GetMarketGroup(ctx, id)
+		if errors.Is(err, dmarkets.ErrMarketGroupNotFound) {
+			return ErrTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !visible(group.LifecycleStatus) {
+			return ErrTargetNotFound
+		}
+	case TargetTag:
+		tags, err := s.activeTags(ctx)
+		if err != nil {
+			return err
+		}
+		if _, ok := tags[target.ID]; !ok {
+			return ErrTargetNotFound
+		}
+	}
+	return nil
+}
+
+// groupRow loads a followed group with its answer markets in display order.
+func (s *Service) groupRow(ctx context.Context, groupID int64) (dmarkets.MarketDiscoveryRow, bool, error) {
+	group, err := s.markets.GetMarketGroup(ctx, groupID)
+	if errors.Is(err, dmarkets.ErrMarketGroupNotFound) {
+		return dmarkets.MarketDiscoveryRow{}, false, nil
+	}
+	if err != nil {
+		return dmarkets.MarketDiscoveryRow{}, false, err
+	}
+	if !visible(group.LifecycleStatus) {
+		return dmarkets.MarketDiscoveryRow{}, false, nil
+	}
+	row := dmarkets.MarketDiscoveryRow{Group: group}
+	for _, member := range dmarkets.OrderedMarketGroupMembers(group.Members) {
+		market, err := s.markets.GetMarket(ctx, member.MarketID)
+		if errors.Is(err, dmarkets.ErrMarketNotFound) {
+			continue
+		}
+		if err != nil {
+			return dmarkets.MarketDiscoveryRow{}, false, err
+		}
+		if row.Market == nil {
+			row.Market = market
+		}
+		row.Children = append(row.Children, market)
+	}
+	return row, len(row.Children) > 0, nil
+}
+
+func (s *Service) activeTags(ctx context.Context) (map[string]dmarkets.MarketTag, error) {
+	tags, err := s.markets.ListMarketTags(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]dmarkets.MarketTag, len(tags))
+	for _, tag := range tags {
+		if tag.IsActive {
+			bySlug[tag.Slug] = tag
+		}
+	}
+	return bySlug, nil
+}
+
+func (s *Service) ready(username string) error {
+	if s == nil || s.repo == nil || s.markets == nil {
+		return errors.New("watchlists service unavailable")
+	}
+	if strings.TrimSpace(username) == "" {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// Normalize validates target and puts its ID in canonical form: a market
+// or group ID without padding, or a lower-case tag slug.
+func Normalize(target Target) (Target, error) {
+	target.ID = strings.TrimSpace(target.ID)
+	switch target.Type {
+	case TargetMarket, TargetMarketGroup:
+		id, err := strconv.ParseInt(target.ID, 10, 64)
+		if err != nil || id <= 0 {
+			return Target{}, ErrInvalidInput
+		}
+		target.ID = strconv.FormatInt(id, 10)
+	case TargetTag:
+		target.ID = strings.ToLower(target.ID)
+		if target.ID == "" || len(target.ID) > 64 {
+			return Target{}, ErrInvalidInput
+		}
+	default:
+		return Target{}, ErrInvalidInput
+	}
+	return target, nil
+}
+
+func visible(lifecycle string) bool {
+	switch dmarkets.NormalizeLifecycleStatus(lifecycle) {
+	case dmarkets.MarketLifecyclePublished, dmarkets.MarketLifecycleClosed, dmarkets.MarketLifecycleResolved:
+		return true
+	default:
+		return false
+	}
+}
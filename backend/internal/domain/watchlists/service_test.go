@@ -0,0 +1,190 @@
+package watchlists_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/internal/domain/watchlists"
+)
+
+type memoryRepo struct {
+	follows []*watchlists.Follow
+}
+
+func (m *memoryRepo) AddFollow(_ context.Context, follow *watchlists.Follow) (bool, error) {
+	for _, existing := range m.follows {
+		if existing.Username == follow.Username && existing.Target == follow.Target {
+			*follow = *existing
+			return false, nil
+		}
+	}
+	follow.ID = int64(len(m.follows) + 1)
+	m.follows = append(m.follows, follow)
+	return true, nil
+}
+
+func (m *memoryRepo) RemoveFollow(_ context.Context, username string, target watchlists.Target) error {
+	kept := m.follows[:0]
+	for _, follow := range m.follows {
+		if follow.Username != username || follow.Target != target {
+			kept = append(kept, follow)
+		}
+	}
+	m.follows = kept
+	return nil
+}
+
+func (m *memoryRepo) ListFollows(_ context.Context, username string) ([]*watchlists.Follow, error) {
+	var out []*watchlists.Follow
+	for i := len(m.follows) - 1; i >= 0; i-- {
+		if m.follows[i].Username == username {
+			out = append(out, m.follows[i])
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) CountFollows(ctx context.Context, username string) (int64, error) {
+	follows, _ := m.ListFollows(ctx, username)
+	return int64(len(follows)), nil
+}
+
+func (m *memoryRepo) CountFollowers(_ context.Context, target watchlists.Target) (int64, error) {
+	var count int64
+	for _, follow := range m.follows {
+		if follow.Target == target {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryRepo) ListFollowers(_ context.Context, targets []watchlists.Target) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, follow := range m.follows {
+		for _, target := range targets {
+			if follow.Target == target && !seen[follow.Username] {
+				seen[follow.Username] = true
+				out = append(out, follow.Username)
+			}
+		}
+	}
+	return out, nil
+}
+
+type fakeMarkets struct {
+	tagFilter string
+}
+
+func (f *fakeMarkets) GetMarket(_ context.Context, id int64) (*dmarkets.Market, error) {
+	switch id {
+	case 7:
+		return &dmarkets.Market{ID: 7, QuestionTitle: "Will it rain?", LifecycleStatus: dmarkets.MarketLifecyclePublished}, nil
+	case 8:
+		return &dmarkets.Market{ID: 8, QuestionTitle: "Draft", LifecycleStatus: dmarkets.MarketLifecycleProposed}, nil
+	case 21, 22:
+		return &dmarkets.Market{ID: id, LifecycleStatus: dmarkets.MarketLifecyclePublished}, nil
+	}
+	return nil, dmarkets.ErrMarketNotFound
+}
+
+func (f *fakeMarkets) GetMarketGroup(_ context.Context, id int64) (*dmarkets.MarketGroup, error) {
+	if id != 2 {
+		return nil, dmarkets.ErrMarketGroupNotFound
+	}
+	return &dmarkets.MarketGroup{ID: 2, LifecycleStatus: dmarkets.MarketLifecyclePublished, Members: []dmarkets.MarketGroupMember{
+		{MarketID: 22, DisplayOrder: 2},
+		{MarketID: 21, DisplayOrder: 1},
+	}}, nil
+}
+
+func (f *fakeMarkets) ListMarketTags(context.Context, bool) ([]dmarkets.MarketTag, error) {
+	return []dmarkets.MarketTag{{Slug: "politics", DisplayName: "Politics", IsActive: true}}, nil
+}
+
+func (f *fakeMarkets) ListMarketDiscovery(_ context.Context, filters dmarkets.ListFilters) (*dmarkets.MarketDiscoveryPage, error) {
+	f.tagFilter = filters.TagSlug
+	return &dmarkets.MarketDiscoveryPage{Rows: []dmarkets.MarketDiscoveryRow{{Market: &dmarkets.Market{ID: 30}}}, Total: 1}, nil
+}
+
+func newService() (*watchlists.Service, *memoryRepo, *fakeMarkets) {
+	repo := &memoryRepo{}
+	markets := &fakeMarkets{}
+	now := func() time.Time { return time.Date(2026, 7, 3, 9, 0, 0, 0, time.UTC) }
+	return watchlists.NewService(repo, markets, now), repo, markets
+}
+
+func TestFollowValidatesTargets(t *testing.T) {
+	svc, repo, _ := newService()
+	ctx := context.Background()
+
+	follow, created, err := svc.Follow(ctx, "alice", watchlists.Target{Type: watchlists.TargetMarket, ID: " 7 "})
+	if err != nil || !created || follow.Target != watchlists.MarketTarget(7) {
+		t.Fatalf("Follow = %+v, %v, %v", follow, created, err)
+	}
+	if _, created, err := svc.Follow(ctx, "alice", watchlists.MarketTarget(7)); err != nil || created || len(repo.follows) != 1 {
+		t.Fatalf("expected re-following to be a no-op, got created=%v err=%v", created, err)
+	}
+
+	tests := []struct {
+		name   string
+		target watchlists.Target
+		want   error
+	}{
+		{name: "proposed market", target: watchlists.MarketTarget(8), want: watchlists.ErrTargetNotFound},
+		{name: "missing group", target: watchlists.MarketGroupTarget(3), want: watchlists.ErrTargetNotFound},
+		{name: "inactive tag", target: watchlists.TagTarget("sports"), want: watchlists.ErrTargetNotFound},
+		{name: "bad id", target: watchlists.Target{Type: watchlists.TargetMarket, ID: "x"}, want: watchlists.ErrInvalidInput},
+		{name: "unknown type", target: watchlists.Target{Type: "user", ID: "bob"}, want: watchlists.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.Follow(ctx, "alice", tt.target); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := svc.Unfollow(ctx, "alice", watchlists.MarketTarget(7)); err != nil || len(repo.follows) != 0 {
+		t.Fatalf("Unfollow = %v (%d left)", err, len(repo.follows))
+	}
+}
+
+func TestWatchlistResolvesFollows(t *testing.T) {
+	svc, repo, markets := newService()
+	ctx := context.Background()
+	for _, target := range []watchlists.Target{watchlists.MarketTarget(7), watchlists.MarketGroupTarget(2), watchlists.TagTarget("Politics")} {
+		if _, _, err := svc.Follow(ctx, "alice", target); err != nil {
+			t.Fatalf("Follow(%+v) returned error: %v", target, err)
+		}
+	}
+	// A market that has since disappeared stays listed but has no row.
+	repo.follows = append(repo.follows, &watchlists.Follow{ID: 9, Username: "alice", Target: watchlists.MarketTarget(99)})
+
+	watchlist, err := svc.Watchlist(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Watchlist returned error: %v", err)
+	}
+	if len(watchlist.Follows) != 4 || len(watchlist.Rows) != 2 || len(watchlist.Tags) != 1 {
+		t.Fatalf("unexpected watchlist %+v", watchlist)
+	}
+	group := watchlist.Rows[0]
+	if group.Group == nil || len(group.Children) != 2 || group.Children[0].ID != 21 || group.Market.ID != 21 {
+		t.Fatalf("expected the group with answers in display order, got %+v", group)
+	}
+	if watchlist.Rows[1].Market.ID != 7 {
+		t.Fatalf("expected the followed market second, got %+v", watchlist.Rows[1])
+	}
+	if markets.tagFilter != "politics" || watchlist.Tags[0].Tag.DisplayName != "Politics" || len(watchlist.Tags[0].Rows) != 1 {
+		t.Fatalf("unexpected tag watch %+v (filter %q)", watchlist.Tags[0], markets.tagFilter)
+	}
+
+	followers, err := svc.Followers(ctx, watchlists.MarketTarget(7), watchlists.TagTarget("politics"))
+	if err != nil || len(followers) != 1 || followers[0] != "alice" {
+		t.Fatalf("Followers = %v, %v", followers, err)
+	}
+}
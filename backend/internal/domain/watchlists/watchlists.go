@@ -0,0 +1,99 @@
+package watchlists
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+)
+
+// TargetType names what a user follows.
+type TargetType string
+
+const (
+	TargetMarket      TargetType = "market"
+	TargetMarketGroup TargetType = "market_group"
+	TargetTag         TargetType = "tag"
+)
+
+var (
+	// ErrInvalidInput indicates an unknown target type or a malformed ID.
+	ErrInvalidInput = errors.New("invalid watchlist request")
+	// ErrTargetNotFound indicates the market, group, or tag does not exist
+	// or cannot be followed.
+	ErrTargetNotFound = errors.New("watchlist target not found")
+	// ErrWatchlistFull indicates the user already follows the maximum number
+	// of targets.
+	ErrWatchlistFull = errors.New("watchlist is full")
+)
+
+// Target identifies a followed market, market group, or tag. ID is the
+// decimal market or group ID, or the tag slug.
+type Target struct {
+	Type TargetType
+	ID   string
+}
+
+// MarketTarget returns the target for a market.
+func MarketTarget(id int64) Target {
+	return Target{Type: TargetMarket, ID: strconv.FormatInt(id, 10)}
+}
+
+// MarketGroupTarget returns the target for a market group.
+func MarketGroupTarget(id int64) Target {
+	return Target{Type: TargetMarketGroup, ID: strconv.FormatInt(id, 10)}
+}
+
+// TagTarget returns the target for a tag.
+func TagTarget(slug string) Target {
+	return Target{Type: TargetTag, ID: slug}
+}
+
+// Follow is one entry on a user's watchlist.
+type Follow struct {
+	ID        int64
+	Username  string
+	Target    Target
+	CreatedAt time.Time
+}
+
+// Watchlist is what a user follows, resolved to live markets. Followed
+// markets and market groups share Rows, most recently followed first; a
+// followed group is one row with every answer as a child. Targets that no
+// longer exist or are no longer public are left out of Rows and Tags but
+// stay in Follows so they can be removed.
+type Watchlist struct {
+	Follows []*Follow
+	Rows    []dmarkets.MarketDiscoveryRow
+	Tags    []TagWatch
+}
+
+// TagWatch is a followed tag with its active markets.
+type TagWatch struct {
+	Tag  dmarkets.MarketTag
+	Rows []dmarkets.MarketDiscoveryRow
+}
+
+// Repository persists watchlist entries.
+type Repository interface {
+	// AddFollow inserts the follow unless the user already has it, sets its
+	// ID and CreatedAt from the stored row, and reports whether it was new.
+	AddFollow(ctx context.Context, follow *Follow) (bool, error)
+	RemoveFollow(ctx context.Context, username string, target Target) error
+	// ListFollows returns the user's follows, most recent first.
+	ListFollows(ctx context.Context, username string) ([]*Follow, error)
+	CountFollows(ctx context.Context, username string) (int64, error)
+	CountFollowers(ctx context.Context, target Target) (int64, error)
+	// ListFollowers returns each user who follows any of targets once.
+	ListFollowers(ctx context.Context, targets []Target) ([]string, error)
+}
+
+// Markets is the market read surface used to check and resolve follows.
+type Markets interface {
+	GetMarket(ctx context.Context, id int64) (*dmarkets.Market, error)
+	GetMarketGroup(ctx context.Context, groupID int64) (*dmarkets.MarketGroup, error)
+	ListMarketTags(ctx context.Context, includeInactive bool) ([]dmarkets.MarketTag, error)
+	ListMarketDiscovery(ctx context.Context, filters dmarkets.ListFilters) (*dmarkets.MarketDiscoveryPage, error)
+}
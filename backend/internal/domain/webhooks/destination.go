@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"net/netip"
+	"strings"
+)
+
+var (
+	// sharedAddressSpace is the carrier-grade NAT range from RFC 6598.
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+	// thisNetwork is 0.0.0.0/8, which some stacks route to the local host.
+	thisNetwork = netip.MustParsePrefix("0.0.0.0/8")
+)
+
+// PublicAddress reports whether addr is routable on the public internet.
+// Loopback, private, link-local (including the 169.254.169.254 cloud metadata
+// service), shared, unspecified, and multicast addresses are not, so signed
+// payloads can never be pointed at the deployment's own network.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+	return !sharedAddressSpace.Contains(addr) && !thisNetwork.Contains(addr)
+}
+
+// publicHost reports whether a URL host may be a webhook destination. Names
+// are only checked for the localhost family here; the sender checks the
+// addresses they resolve to when it dials.
+func publicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(addr)
+	}
+	return true
+}
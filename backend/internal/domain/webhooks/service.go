@@ -0,0 +1,501 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/internal/domain/permissions"
+)
+
+const (
+	maxURLLength         = 2048
+	maxDescriptionLength = 200
+	secretBytes          = 24
+	maxErrorLength       = 500
+
+	defaultMaxAttempts = 8
+	defaultBaseBackoff = 30 * time.Second
+	defaultMaxBackoff  = 6 * time.Hour
+	defaultBatchSize   = 50
+	defaultLease       = 2 * time.Minute
+)
+
+// Config tunes delivery retries. Zero values use the defaults.
+// AllowInsecureTargets accepts plain http URLs and loopback or private hosts;
+// it is only meant for local development against receivers on the same machine.
+type Config struct {
+	MaxAttempts          int
+	BaseBackoff          time.Duration
+	MaxBackoff           time.Duration
+	BatchSize            int
+	Lease                time.Duration
+	AllowInsecureTargets bool
+}
+
+func normalizeConfig(config Config) Config {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultBaseBackoff
+	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+	return config
+}
+
+// Service manages webhook endpoints and delivers events to them. It
+// subscribes to the domain event bus to queue deliveries and sends them from
+// DeliverDue, so a slow or failing receiver never holds up other subscribers.
+type Service struct {
+	repo       Repository
+	sender     Sender
+	authorizer permissions.Authorizer
+	config     Config
+	now        func() time.Time
+}
+
+// NewService constructs a webhooks service.
+func NewService(repo Repository, sender Sender, authorizer permissions.Authorizer, config Config, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{
+		repo:       repo,
+		sender:     sender,
+		authorizer: authorizer,
+		config:     normalizeConfig(config),
+		now:        now,
+	}
+}
+
+// ListEndpoints returns every registered endpoint.
+func (s *Service) ListEndpoints(ctx context.Context, actor permissions.Subject) ([]*Endpoint, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.ListEndpoints(ctx)
+}
+
+// GetEndpoint returns one endpoint.
+func (s *Service) GetEndpoint(ctx context.Context, actor permissions.Subject, id int64) (*Endpoint, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEndpoint(ctx, id)
+}
+
+// CreateEndpoint registers an endpoint with a freshly generated signing
+// secret. The returned endpoint is the only place the secret is exposed
+// until it is rotated.
+func (s *Service) CreateEndpoint(ctx context.Context, actor permissions.Subject, input EndpointInput) (*Endpoint, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	if input.URL == nil {
+		return nil, ErrInvalidEndpoint
+	}
+	now := s.now().UTC()
+	endpoint := &Endpoint{Active: true, CreatedBy: actor.Username, CreatedAt: now}
+	if err := applyEndpointInput(endpoint, input, s.config.AllowInsecureTargets); err != nil {
+		return nil, err
+	}
+	if len(endpoint.EventTypes) == 0 {
+		return nil, ErrInvalidEndpoint
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = secret
+	endpoint.UpdatedBy = actor.Username
+	endpoint.UpdatedAt = now
+	if err := s.repo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// UpdateEndpoint edits an endpoint and optionally rotates its secret.
+func (s *Service) UpdateEndpoint(ctx context.Context, actor permissions.Subject, id int64, input EndpointInput) (*Endpoint, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	endpoint, err := s.repo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyEndpointInput(endpoint, input, s.config.AllowInsecureTargets); err != nil {
+		return nil, err
+	}
+	if input.RotateSecret {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Secret = secret
+	}
+	endpoint.UpdatedBy = actor.Username
+	endpoint.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint removes an endpoint and its delivery log.
+func (s *Service) DeleteEndpoint(ctx context.Context, actor permissions.Subject, id int64) error {
+	if err := s.require(ctx, actor); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetEndpoint(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteEndpoint(ctx, id)
+}
+
+// ListDeliveries returns an endpoint's delivery log, newest first.
+func (s *Service) ListDeliveries(ctx context.Context, actor permissions.Subject, endpointID int64, filters DeliveryFilters) ([]*Delivery, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	switch filters.Status {
+	case "", DeliveryPending, DeliverySucceeded, DeliveryDead:
+	default:
+		return nil, ErrInvalidEndpoint
+	}
+	if _, err := s.repo.GetEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, endpointID, filters)
+}
+
+// SendTestEvent sends a webhook.test event to the endpoint immediately and
+// returns the logged delivery. Test deliveries are attempted once, even when
+// the endpoint is paused, so a misconfiguration shows up right away.
+func (s *Service) SendTestEvent(ctx context.Context, actor permissions.Subject, endpointID int64) (*Delivery, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	endpoint, err := s.repo.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	suffix, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	event := devents.Event{
+		Type:       TestEventType,
+		Username:   actor.Username,
+		Data:       map[string]any{"message": "Test event from SocialPredict."},
+		OccurredAt: now,
+	}
+	delivery, err := newDelivery(endpoint.ID, "test_"+suffix, event, now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.EnqueueDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return s.attempt(ctx, endpoint, delivery)
+}
+
+// RetryDelivery requeues a dead delivery with a fresh attempt budget and
+// attempts it immediately.
+func (s *Service) RetryDelivery(ctx context.Context, actor permissions.Subject, endpointID, deliveryID int64) (*Delivery, error) {
+	if err := s.require(ctx, actor); err != nil {
+		return nil, err
+	}
+	endpoint, err := s.repo.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.GetDelivery(ctx, endpointID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != DeliveryDead {
+		return nil, ErrDeliveryNotRetryable
+	}
+	delivery.Status = DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = s.now().UTC()
+	return s.attempt(ctx, endpoint, delivery)
+}
+
+// HandleEvent queues a delivery for every active endpoint subscribed to the
+// event. Queueing is idempotent per endpoint and event, so redelivery of the
+// same outbox event by the bus does not send twice.
+func (s *Service) HandleEvent(ctx context.Context, event devents.Event) error {
+	if s == nil || s.repo == nil || !Subscribable(event.Type) {
+		return nil
+	}
+	endpoints, err := s.repo.ListEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	for _, endpoint := range endpoints {
+		if !endpoint.Active || !endpoint.SubscribedTo(event.Type) {
+			continue
+		}
+		delivery, err := newDelivery(endpoint.ID, "evt_"+strconv.FormatInt(event.ID, 10), event, now)
+		if err != nil {
+			return err
+		}
+		if _, err := s.repo.EnqueueDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue attempts one batch of due deliveries and returns how many were
+// claimed. Receiver failures are recorded on the delivery, not returned.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	if s == nil || s.repo == nil {
+		return 0, nil
+	}
+	claimed, err := s.repo.ClaimDueDeliveries(ctx, s.now().UTC(), s.config.Lease, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	endpoints := map[int64]*Endpoint{}
+	var errs []error
+	for _, delivery := range claimed {
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			endpoint, err = s.repo.GetEndpoint(ctx, delivery.EndpointID)
+			if err != nil && !errors.Is(err, ErrEndpointNotFound) {
+				errs = append(errs, err)
+				continue
+			}
+			endpoints[delivery.EndpointID] = endpoint
+		}
+		if _, err := s.attempt(ctx, endpoint, delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(claimed), errors.Join(errs...)
+}
+
+// attempt sends the delivery once and records the outcome. Failed attempts
+// back off exponentially until MaxAttempts, then the delivery is dead.
+func (s *Service) attempt(ctx context.Context, endpoint *Endpoint, delivery *Delivery) (*Delivery, error) {
+	now := s.now().UTC()
+	isTest := delivery.EventType == TestEventType
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = 0
+
+	var failure error
+	switch {
+	case endpoint == nil:
+		failure = ErrEndpointNotFound
+	case !endpoint.Active && !isTest:
+		failure = errors.New("endpoint is paused")
+	case s.sender == nil:
+		failure = errors.New("webhook sender unavailable")
+	default:
+		body := []byte(delivery.Payload)
+		status, err := s.sender.Send(ctx, Request{
+			URL: endpoint.URL,
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				EventHeader:     string(delivery.EventType),
+				DeliveryHeader:  strconv.FormatInt(delivery.ID, 10),
+				SignatureHeader: Sign(endpoint.Secret, now, body),
+			},
+			Body: body,
+		})
+		delivery.ResponseStatus = status
+		switch {
+		case err != nil:
+			failure = err
+		case status < 200 || status > 299:
+			failure = fmt.Errorf("receiver responded with HTTP %d", status)
+		}
+	}
+
+	switch {
+	case failure == nil:
+		delivery.Status = DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case isTest || delivery.Attempts >= s.config.MaxAttempts || endpoint == nil:
+		delivery.Status = DeliveryDead
+		delivery.LastError = truncateError(failure)
+	default:
+		delivery.Status = DeliveryPending
+		delivery.LastError = truncateError(failure)
+		delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+	}
+	if err := s.repo.SaveDeliveryOutcome(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.config.MaxBackoff {
+			return s.config.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func (s *Service) require(ctx context.Context, actor permissions.Subject) error {
+	if s == nil || s.repo == nil {
+		return errors.New("webhooks service unavailable")
+	}
+	return permissions.Require(ctx, s.authorizer, actor, permissions.WebhooksManage)
+}
+
+type payload struct {
+	ID            string         `json:"id"`
+	Type          string         `json:"type"`
+	OccurredAt    string         `json:"occurredAt"`
+	MarketID      int64          `json:"marketId,omitempty"`
+	MarketGroupID int64          `json:"marketGroupId,omitempty"`
+	Actor         string         `json:"actor,omitempty"`
+	Data          map[string]any `json:"data,omitempty"`
+}
+
+func newDelivery(endpointID int64, eventKey string, event devents.Event, now time.Time) (*Delivery, error) {
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+	body, err := json.Marshal(payload{
+		ID:            eventKey,
+		Type:          string(event.Type),
+		OccurredAt:    occurredAt.UTC().Format(time.RFC3339),
+		MarketID:      event.MarketID,
+		MarketGroupID: event.MarketGroupID,
+		Actor:         event.Username,
+		Data:          event.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Delivery{
+		EndpointID:    endpointID,
+		EventKey:      eventKey,
+		EventType:     event.Type,
+		Payload:       string(body),
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+func applyEndpointInput(endpoint *Endpoint, input EndpointInput, allowInsecure bool) error {
+	if input.URL != nil {
+		normalized, err := normalizeURL(*input.URL, allowInsecure)
+		if err != nil {
+			return err
+		}
+		endpoint.URL = normalized
+	}
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		if len(description) > maxDescriptionLength {
+			return ErrInvalidEndpoint
+		}
+		endpoint.Description = description
+	}
+	if input.EventTypes != nil {
+		eventTypes, err := normalizeEventTypes(input.EventTypes)
+		if err != nil {
+			return err
+		}
+		endpoint.EventTypes = eventTypes
+	}
+	if input.Active != nil {
+		endpoint.Active = *input.Active
+	}
+	return nil
+}
+
+// normalizeURL requires an https URL to a public host unless insecure targets
+// are allowed. Hostnames that resolve to internal addresses are refused again
+// by the sender when it dials.
+func normalizeURL(raw string, allowInsecure bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxURLLength {
+		return "", ErrInvalidEndpoint
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", ErrInvalidEndpoint
+	}
+	if !allowInsecure && (parsed.Scheme != "https" || !publicHost(parsed.Hostname())) {
+		return "", ErrInvalidEndpoint
+	}
+	return parsed.String(), nil
+}
+
+func normalizeEventTypes(names []string) ([]devents.Type, error) {
+	if len(names) == 0 {
+		return nil, ErrInvalidEndpoint
+	}
+	seen := map[devents.Type]struct{}{}
+	result := make([]devents.Type, 0, len(names))
+	for _, name := range names {
+		eventType := devents.Type(strings.TrimSpace(name))
+		if !Subscribable(eventType) {
+			return nil, ErrInvalidEndpoint
+		}
+		if _, dup := seen[eventType]; dup {
+			continue
+		}
+		seen[eventType] = struct{}{}
+		result = append(result, eventType)
+	}
+	return result, nil
+}
+
+func generateSecret() (string, error) {
+	value, err := randomHex(secretBytes)
+	if err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + value, nil
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
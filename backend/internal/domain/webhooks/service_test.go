@@ -0,0 +1,326 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"sort"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/internal/domain/permissions"
+	"socialpredict/internal/domain/webhooks"
+)
+
+type memoryRepo struct {
+	endpoints  map[int64]*webhooks.Endpoint
+	deliveries map[int64]*webhooks.Delivery
+	nextID     int64
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{endpoints: map[int64]*webhooks.Endpoint{}, deliveries: map[int64]*webhooks.Delivery{}}
+}
+
+func (m *memoryRepo) id() int64 {
+	m.nextID++
+	return m.nextID
+}
+
+func (m *memoryRepo) CreateEndpoint(_ context.Context, endpoint *webhooks.Endpoint) error {
+	endpoint.ID = m.id()
+	clone := *endpoint
+	m.endpoints[endpoint.ID] = &clone
+	return nil
+}
+
+func (m *memoryRepo) UpdateEndpoint(_ context.Context, endpoint *webhooks.Endpoint) error {
+	clone := *endpoint
+	m.endpoints[endpoint.ID] = &clone
+	return nil
+}
+
+func (m *memoryRepo) DeleteEndpoint(_ context.Context, id int64) error {
+	delete(m.endpoints, id)
+	return nil
+}
+
+func (m *memoryRepo) GetEndpoint(_ context.Context, id int64) (*webhooks.Endpoint, error) {
+	endpoint, ok := m.endpoints[id]
+	if !ok {
+		return nil, webhooks.ErrEndpointNotFound
+	}
+	clone := *endpoint
+	return &clone, nil
+}
+
+func (m *memoryRepo) ListEndpoints(context.Context) ([]*webhooks.Endpoint, error) {
+	endpoints := make([]*webhooks.Endpoint, 0, len(m.endpoints))
+	for _, endpoint := range m.endpoints {
+		clone := *endpoint
+		endpoints = append(endpoints, &clone)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ID < endpoints[j].ID })
+	return endpoints, nil
+}
+
+func (m *memoryRepo) EnqueueDelivery(_ context.Context, delivery *webhooks.Delivery) (bool, error) {
+	for _, existing := range m.deliveries {
+		if existing.EndpointID == delivery.EndpointID && existing.EventKey == delivery.EventKey {
+			return false, nil
+		}
+	}
+	delivery.ID = m.id()
+	clone := *delivery
+	m.deliveries[delivery.ID] = &clone
+	return true, nil
+}
+
+func (m *memoryRepo) ClaimDueDeliveries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*webhooks.Delivery, error) {
+	var claimed []*webhooks.Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == webhooks.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(claimed) < limit {
+			delivery.NextAttemptAt = now.Add(lease)
+			clone := *delivery
+			claimed = append(claimed, &clone)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *memoryRepo) SaveDeliveryOutcome(_ context.Context, delivery *webhooks.Delivery) error {
+	clone := *delivery
+	m.deliveries[delivery.ID] = &clone
+	return nil
+}
+
+func (m *memoryRepo) GetDelivery(_ context.Context, endpointID, deliveryID int64) (*webhooks.Delivery, error) {
+	delivery, ok := m.deliveries[deliveryID]
+	if !ok || delivery.EndpointID != endpointID {
+		return nil, webhooks.ErrDeliveryNotFound
+	}
+	clone := *delivery
+	return &clone, nil
+}
+
+func (m *memoryRepo) ListDeliveries(_ context.Context, endpointID int64, _ webhooks.DeliveryFilters) ([]*webhooks.Delivery, error) {
+	var deliveries []*webhooks.Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.EndpointID == endpointID {
+			clone := *delivery
+			deliveries = append(deliveries, &clone)
+		}
+	}
+	return deliveries, nil
+}
+
+type recordingSender struct {
+	requests []webhooks.Request
+	status   int
+	err      error
+}
+
+func (s *recordingSender) Send(_ context.Context, request webhooks.Request) (int, error) {
+	s.requests = append(s.requests, request)
+	return s.status, s.err
+}
+
+var admin = permissions.Subject{Username: "root", Role: permissions.RoleAdmin}
+
+func stringPtr(value string) *string { return &value }
+
+func newTestService(repo *memoryRepo, sender webhooks.Sender, now *time.Time) *webhooks.Service {
+	return webhooks.NewService(repo, sender, nil, webhooks.Config{MaxAttempts: 3, BaseBackoff: time.Minute}, func() time.Time { return *now })
+}
+
+func createEndpoint(t *testing.T, svc *webhooks.Service, eventTypes ...string) *webhooks.Endpoint {
+	t.Helper()
+	endpoint, err := svc.CreateEndpoint(context.Background(), admin, webhooks.EndpointInput{
+		URL:        stringPtr(" https://hooks.example.com/socialpredict "),
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint returned error: %v", err)
+	}
+	return endpoint
+}
+
+func TestCreateEndpointValidatesAndGeneratesSecret(t *testing.T) {
+	now := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+	svc := newTestService(newMemoryRepo(), &recordingSender{}, &now)
+
+	endpoint := createEndpoint(t, svc, "market.resolved", "market.resolved")
+	if endpoint.URL != "https://hooks.example.com/socialpredict" || len(endpoint.EventTypes) != 1 || !endpoint.Active {
+		t.Fatalf("unexpected endpoint: %+v", endpoint)
+	}
+	if len(endpoint.Secret) < 20 || endpoint.CreatedBy != "root" {
+		t.Fatalf("expected generated secret and creator: %+v", endpoint)
+	}
+
+	invalid := []webhooks.EndpointInput{
+		{URL: stringPtr("ftp://hooks.example.com"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("http://hooks.example.com"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://localhost:8443/hook"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://127.0.0.1/hook"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://10.0.0.5/hook"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://192.168.1.20/hook"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://169.254.169.254/latest/meta-data"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://[::1]/hook"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://[::ffff:10.0.0.1]/hook"), EventTypes: []string{"market.resolved"}},
+		{URL: stringPtr("https://hooks.example.com")},
+		{URL: stringPtr("https://hooks.example.com"), EventTypes: []string{"tags.catalog_changed"}},
+	}
+	for _, input := range invalid {
+		if _, err := svc.CreateEndpoint(context.Background(), admin, input); !errors.Is(err, webhooks.ErrInvalidEndpoint) {
+			t.Fatalf("CreateEndpoint(%+v) error = %v, want ErrInvalidEndpoint", input, err)
+		}
+	}
+
+	regular := permissions.Subject{Username: "ada", Role: permissions.RoleRegular}
+	if _, err := svc.ListEndpoints(context.Background(), regular); !errors.Is(err, permissions.ErrPermissionDenied) {
+		t.Fatalf("ListEndpoints as regular user error = %v, want ErrPermissionDenied", err)
+	}
+}
+
+func TestCreateEndpointAcceptsLocalReceiversOnlyWhenInsecureTargetsAllowed(t *testing.T) {
+	now := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+	svc := webhooks.NewService(newMemoryRepo(), &recordingSender{}, nil, webhooks.Config{AllowInsecureTargets: true}, func() time.Time { return now })
+
+	endpoint, err := svc.CreateEndpoint(context.Background(), admin, webhooks.EndpointInput{
+		URL:        stringPtr("http://localhost:9000/hook"),
+		EventTypes: []string{"market.resolved"},
+	})
+	if err != nil || endpoint.URL != "http://localhost:9000/hook" {
+		t.Fatalf("CreateEndpoint in development = %+v, %v", endpoint, err)
+	}
+}
+
+func TestPublicAddressRejectsInternalRanges(t *testing.T) {
+	for _, raw := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.0.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:192.168.0.1", "224.0.0.1"} {
+		if webhooks.PublicAddress(netip.MustParseAddr(raw)) {
+			t.Fatalf("PublicAddress(%s) = true, want false", raw)
+		}
+	}
+	for _, raw := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		if !webhooks.PublicAddress(netip.MustParseAddr(raw)) {
+			t.Fatalf("PublicAddress(%s) = false, want true", raw)
+		}
+	}
+}
+
+func TestHandleEventQueuesSignedDeliveriesOncePerEndpoint(t *testing.T) {
+	now := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+	repo := newMemoryRepo()
+	sender := &recordingSender{status: 204}
+	svc := newTestService(repo, sender, &now)
+	subscribed := createEndpoint(t, svc, "market.resolved")
+	createEndpoint(t, svc, "bet.placed")
+
+	event := devents.Event{ID: 42, Type: devents.MarketResolved, MarketID: 7, Username: "steward", Data: map[string]any{"resolution": "YES"}, OccurredAt: now}
+	for i := 0; i < 2; i++ {
+		if err := svc.HandleEvent(context.Background(), event); err != nil {
+			t.Fatalf("HandleEvent returned error: %v", err)
+		}
+	}
+	if err := svc.HandleEvent(context.Background(), devents.Event{ID: 43, Type: devents.TagCatalogChanged}); err != nil {
+		t.Fatalf("HandleEvent for unsubscribable event returned error: %v", err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("queued %d deliveries, want 1", len(repo.deliveries))
+	}
+
+	claimed, err := svc.DeliverDue(context.Background())
+	if err != nil || claimed != 1 {
+		t.Fatalf("DeliverDue = %d, %v", claimed, err)
+	}
+	if len(sender.requests) != 1 {
+		t.Fatalf("sent %d requests, want 1", len(sender.requests))
+	}
+	request := sender.requests[0]
+	if request.URL != subscribed.URL || request.Headers[webhooks.EventHeader] != "market.resolved" {
+		t.Fatalf("unexpected request: %+v", request)
+	}
+	if !webhooks.VerifySignature(subscribed.Secret, request.Headers[webhooks.SignatureHeader], request.Body) {
+		t.Fatalf("signature did not verify: %s", request.Headers[webhooks.SignatureHeader])
+	}
+	if webhooks.VerifySignature("wrong", request.Headers[webhooks.SignatureHeader], request.Body) {
+		t.Fatalf("signature verified with the wrong secret")
+	}
+	var body map[string]any
+	if err := json.Unmarshal(request.Body, &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["id"] != "evt_42" || body["type"] != "market.resolved" || body["marketId"] != float64(7) {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.Status != webhooks.DeliverySucceeded || delivery.DeliveredAt == nil || delivery.ResponseStatus != 204 {
+			t.Fatalf("unexpected delivery outcome: %+v", delivery)
+		}
+	}
+}
+
+func TestDeliverDueBacksOffThenDeadLettersAndRetryRequeues(t *testing.T) {
+	now := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+	repo := newMemoryRepo()
+	sender := &recordingSender{status: 500}
+	svc := newTestService(repo, sender, &now)
+	endpoint := createEndpoint(t, svc, "bet.placed")
+	if err := svc.HandleEvent(context.Background(), devents.Event{ID: 1, Type: devents.BetPlaced, MarketID: 3}); err != nil {
+		t.Fatalf("HandleEvent returned error: %v", err)
+	}
+
+	var deliveryID int64
+	for attempt, wait := range []time.Duration{time.Minute, 2 * time.Minute, 0} {
+		if _, err := svc.DeliverDue(context.Background()); err != nil {
+			t.Fatalf("DeliverDue returned error: %v", err)
+		}
+		for id, delivery := range repo.deliveries {
+			deliveryID = id
+			if attempt < 2 {
+				if delivery.Status != webhooks.DeliveryPending || !delivery.NextAttemptAt.Equal(now.Add(wait)) {
+					t.Fatalf("attempt %d: unexpected retry schedule: %+v", attempt+1, delivery)
+				}
+			} else if delivery.Status != webhooks.DeliveryDead || delivery.Attempts != 3 || delivery.LastError == "" {
+				t.Fatalf("expected dead delivery after max attempts: %+v", delivery)
+			}
+		}
+		now = now.Add(wait)
+	}
+
+	sender.status = 200
+	retried, err := svc.RetryDelivery(context.Background(), admin, endpoint.ID, deliveryID)
+	if err != nil {
+		t.Fatalf("RetryDelivery returned error: %v", err)
+	}
+	if retried.Status != webhooks.DeliverySucceeded || retried.Attempts != 1 {
+		t.Fatalf("unexpected retried delivery: %+v", retried)
+	}
+	if _, err := svc.RetryDelivery(context.Background(), admin, endpoint.ID, deliveryID); !errors.Is(err, webhooks.ErrDeliveryNotRetryable) {
+		t.Fatalf("second retry error = %v, want ErrDeliveryNotRetryable", err)
+	}
+}
+
+func TestSendTestEventAttemptsOnceEvenWhenPaused(t *testing.T) {
+	now := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+	sender := &recordingSender{err: errors.New("connection refused")}
+	svc := newTestService(newMemoryRepo(), sender, &now)
+	endpoint := createEndpoint(t, svc, "market.created")
+	paused := false
+	if _, err := svc.UpdateEndpoint(context.Background(), admin, endpoint.ID, webhooks.EndpointInput{Active: &paused}); err != nil {
+		t.Fatalf("UpdateEndpoint returned error: %v", err)
+	}
+
+	delivery, err := svc.SendTestEvent(context.Background(), admin, endpoint.ID)
+	if err != nil {
+		t.Fatalf("SendTestEvent returned error: %v", err)
+	}
+	if delivery.EventType != webhooks.TestEventType || delivery.Status != webhooks.DeliveryDead || delivery.LastError != "connection refused" {
+		t.Fatalf("unexpected test delivery: %+v", delivery)
+	}
+	if len(sender.requests) != 1 {
+		t.Fatalf("sent %d requests, want 1", len(sender.requests))
+	}
+}
@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>". The MAC
+	// covers "<t>.<raw body>" keyed with the endpoint secret.
+	SignatureHeader = "X-SocialPredict-Signature"
+	// EventHeader carries the event type.
+	EventHeader = "X-SocialPredict-Event"
+	// DeliveryHeader carries the delivery ID, stable across retries.
+	DeliveryHeader = "X-SocialPredict-Delivery"
+)
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + signatureDigest(secret, unix, body)
+}
+
+// VerifySignature checks a signature header against body. Receivers should
+// also reject timestamps outside their tolerance to limit replays.
+func VerifySignature(secret, header string, body []byte) bool {
+	var unix, digest string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			digest = value
+		}
+	}
+	if unix == "" || digest == "" {
+		return false
+	}
+	return hmac.Equal([]byte(digest), []byte(signatureDigest(secret, unix, body)))
+}
+
+func signatureDigest(secret, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+)
+
+// TestEventType is the event type sent by "send test event". It is never
+// recorded in the outbox and cannot be subscribed to.
+const TestEventType devents.Type = "webhook.test"
+
+// DeliveryStatus is the lifecycle state of a queued delivery.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries are waiting for their first attempt or a retry.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded deliveries were acknowledged with a 2xx response.
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead deliveries exhausted their attempts and wait for a manual retry.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+var (
+	// ErrEndpointNotFound indicates that the webhook endpoint does not exist.
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	// ErrDeliveryNotFound indicates that the delivery does not exist for the endpoint.
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrInvalidEndpoint indicates an unusable URL, description, or event list.
+	ErrInvalidEndpoint = errors.New("invalid webhook endpoint")
+	// ErrDeliveryNotRetryable indicates a retry request for a delivery that is not dead.
+	ErrDeliveryNotRetryable = errors.New("only dead deliveries can be retried")
+)
+
+var subscribableEvents = []devents.Type{
+	devents.MarketCreated,
+	devents.MarketApproved,
+	devents.MarketResolved,
+	devents.MarketGroupResolved,
+	devents.BetPlaced,
+	devents.SharesSold,
+	devents.AmendmentApproved,
+}
+
+// EventTypes lists the domain events an endpoint may subscribe to.
+func EventTypes() []devents.Type {
+	return append([]devents.Type(nil), subscribableEvents...)
+}
+
+// Subscribable reports whether endpoints may subscribe to eventType.
+func Subscribable(eventType devents.Type) bool {
+	for _, candidate := range subscribableEvents {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoint is a registered webhook receiver.
+type Endpoint struct {
+	ID          int64
+	URL         string
+	Description string
+	Secret      string
+	EventTypes  []devents.Type
+	Active      bool
+	CreatedBy   string
+	UpdatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SubscribedTo reports whether the endpoint wants eventType.
+func (e *Endpoint) SubscribedTo(eventType devents.Type) bool {
+	if e == nil {
+		return false
+	}
+	for _, candidate := range e.EventTypes {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// EndpointInput carries the editable endpoint fields. Nil fields are left
+// unchanged on update.
+type EndpointInput struct {
+	URL          *string
+	Description  *string
+	EventTypes   []string
+	Active       *bool
+	RotateSecret bool
+}
+
+// Delivery is one event queued for one endpoint and the outcome of its
+// latest attempt.
+type Delivery struct {
+	ID             int64
+	EndpointID     int64
+	EventKey       string
+	EventType      devents.Type
+	Payload        string
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus int
+	LastError      string
+	LastAttemptAt  *time.Time
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+// DeliveryFilters narrows a delivery log listing.
+type DeliveryFilters struct {
+	Status DeliveryStatus
+	Limit  int
+	Offset int
+}
+
+// Repository persists endpoints and their deliveries.
+type Repository interface {
+	CreateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	UpdateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	DeleteEndpoint(ctx context.Context, id int64) error
+	GetEndpoint(ctx context.Context, id int64) (*Endpoint, error)
+	ListEndpoints(ctx context.Context) ([]*Endpoint, error)
+	// EnqueueDelivery inserts the delivery unless one already exists for the
+	// same endpoint and event key, and reports whether it was inserted.
+	EnqueueDelivery(ctx context.Context, delivery *Delivery) (bool, error)
+	// ClaimDueDeliveries leases up to limit pending deliveries that are due.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error)
+	SaveDeliveryOutcome(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, endpointID, deliveryID int64) (*Delivery, error)
+	ListDeliveries(ctx context.Context, endpointID int64, filters DeliveryFilters) ([]*Delivery, error)
+}
+
+// Request is one signed HTTP POST to an endpoint.
+type Request struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// Sender performs the HTTP call and returns the response status code. A
+// transport failure returns an error and no status.
+type Sender interface {
+	Send(ctx context.Context, request Request) (int, error)
+}
@@ -9,8 +9,12 @@ import (
 type (
 	AnalyticsReadModelSnapshot            = domainanalytics.AnalyticsReadModelSnapshot
 	AnalyticsReadModelSnapshotRepository  = domainanalytics.AnalyticsReadModelSnapshotRepository
+	CalibrationRepository                 = domainanalytics.CalibrationRepository
 	Config                                = domainanalytics.Config
 	DebtRepository                        = domainanalytics.DebtRepository
+	EconomyActivity                       = domainanalytics.EconomyActivity
+	EconomyDay                            = domainanalytics.EconomyDay
+	EconomyHistoryRepository              = domainanalytics.EconomyHistoryRepository
 	FeeRepository                         = domainanalytics.FeeRepository
 	FinancialSnapshot                     = domainanalytics.FinancialSnapshot
 	FinancialSnapshotRequest              = domainanalytics.FinancialSnapshotRequest
@@ -18,26 +22,39 @@ type (
 	GlobalLeaderboardReadModel            = domainanalytics.GlobalLeaderboardReadModel
 	GlobalUserProfitability               = domainanalytics.GlobalUserProfitability
 	Int64MetricReader                     = domainanalytics.Int64MetricReader
+	LeaderboardMarketRecord               = domainanalytics.LeaderboardMarketRecord
 	LeaderboardRepository                 = domainanalytics.LeaderboardRepository
+	LeaderboardScope                      = domainanalytics.LeaderboardScope
 	MarketGroupFeeRepository              = domainanalytics.MarketGroupFeeRepository
 	MarketGroupFinancialsRepository       = domainanalytics.MarketGroupFinancialsRepository
 	MarketPositionCalculator              = domainanalytics.MarketPositionCalculator
 	MarketRecord                          = domainanalytics.MarketRecord
+	ReconciliationAdjustment              = domainanalytics.ReconciliationAdjustment
+	ReconciliationSaleCredit              = domainanalytics.ReconciliationSaleCredit
+	ReconciliationMarket                  = domainanalytics.ReconciliationMarket
+	ReconciliationRepository              = domainanalytics.ReconciliationRepository
+	ReconciliationUser                    = domainanalytics.ReconciliationUser
 	Repository                            = domainanalytics.Repository
+	ResolvedMarketRecord                  = domainanalytics.ResolvedMarketRecord
+	ScopedLeaderboardRepository           = domainanalytics.ScopedLeaderboardRepository
 	Service                               = domainanalytics.Service
 	ServiceOption                         = domainanalytics.ServiceOption
 	StatsRepository                       = domainanalytics.StatsRepository
+	StoredMarketAccounting                = domainanalytics.StoredMarketAccounting
 	SystemMetrics                         = domainanalytics.SystemMetrics
 	SystemMetricsReadModel                = domainanalytics.SystemMetricsReadModel
 	UserAccount                           = domainanalytics.UserAccount
 	UserFinancialMetricSnapshot           = domainanalytics.UserFinancialMetricSnapshot
 	UserFinancialMetricSnapshotRepository = domainanalytics.UserFinancialMetricSnapshotRepository
+	UserFinancialHistoryPoint             = domainanalytics.UserFinancialHistoryPoint
+	UserFinancialHistoryRepository        = domainanalytics.UserFinancialHistoryRepository
 	VolumeRepository                      = domainanalytics.VolumeRepository
 	WorkProfitMarketGroupRecord           = domainanalytics.WorkProfitMarketGroupRecord
 	WorkProfitMarketRecord                = domainanalytics.WorkProfitMarketRecord
 )
 
 var (
+	ErrLeaderboardTagNotFound   = domainanalytics.ErrLeaderboardTagNotFound
 	NewMarketPositionCalculator = domainanalytics.NewMarketPositionCalculator
 	NewService                  = domainanalytics.NewService
 	WithPositionCalculator      = domainanalytics.WithPositionCalculator
@@ -46,8 +63,11 @@ var (
 const (
 	AnalyticsSnapshotKindSystemMetrics     = domainanalytics.AnalyticsSnapshotKindSystemMetrics
 	AnalyticsSnapshotKindGlobalLeaderboard = domainanalytics.AnalyticsSnapshotKindGlobalLeaderboard
+	AnalyticsSnapshotKindCalibration       = domainanalytics.AnalyticsSnapshotKindCalibration
+	AnalyticsSnapshotKindScopedLeaderboard = domainanalytics.AnalyticsSnapshotKindScopedLeaderboard
 	SystemMetricsSnapshotKey               = domainanalytics.SystemMetricsSnapshotKey
 	GlobalLeaderboardSnapshotKey           = domainanalytics.GlobalLeaderboardSnapshotKey
+	CalibrationSnapshotKey                 = domainanalytics.CalibrationSnapshotKey
 )
 
 type defaultMarketPositionCalculator struct{}
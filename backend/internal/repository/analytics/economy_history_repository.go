@@ -0,0 +1,161 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ EconomyHistoryRepository = (*GormRepository)(nil)
+
+// CountEconomyActivity counts bets placed, markets created and markets
+// resolved in [from, to). Volume is the absolute amount traded.
+func (r *GormRepository) CountEconomyActivity(ctx context.Context, from, to time.Time) (EconomyActivity, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return EconomyActivity{}, err
+	}
+	from, to = from.UTC(), to.UTC()
+
+	var trades struct {
+		Volume        int64
+		Trades        int64
+		ActiveTraders int64
+	}
+	if err := db.Table("bets").
+		Select("COALESCE(SUM(ABS(amount)), 0) AS volume, COUNT(*) AS trades, COUNT(DISTINCT username) AS active_traders").
+		Where("placed_at >= ? AND placed_at < ?", from, to).
+		Scan(&trades).Error; err != nil {
+		return EconomyActivity{}, err
+	}
+
+	activity := EconomyActivity{
+		Volume:        trades.Volume,
+		Trades:        trades.Trades,
+		ActiveTraders: trades.ActiveTraders,
+	}
+	if err := db.Model(&models.Market{}).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Count(&activity.NewMarkets).Error; err != nil {
+		return EconomyActivity{}, err
+	}
+	if err := db.Model(&models.Market{}).
+		Where("is_resolved = ? AND final_resolution_date_time >= ? AND final_resolution_date_time < ?", true, from, to).
+		Count(&activity.Resolutions).Error; err != nil {
+		return EconomyActivity{}, err
+	}
+	return activity, nil
+}
+
+// GetEconomyDay returns the stored day starting at day, or nil.
+func (r *GormRepository) GetEconomyDay(ctx context.Context, day time.Time) (*EconomyDay, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var row models.EconomyDailyMetric
+	if err := db.Where("day = ?", day.UTC()).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	result := economyDayFromModel(row)
+	return &result, nil
+}
+
+// UpsertEconomyDay stores the day, replacing an earlier sample of it.
+func (r *GormRepository) UpsertEconomyDay(ctx context.Context, day EconomyDay) error {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	row := models.EconomyDailyMetric{
+		Day:                day.Day.UTC(),
+		MoneySupply:        day.MoneySupply,
+		NumUsers:           day.NumUsers,
+		DebtUtilized:       day.DebtUtilized,
+		UnusedDebt:         day.UnusedDebt,
+		ActiveBetVolume:    day.ActiveBetVolume,
+		MarketCreationFees: day.MarketCreationFees,
+		ParticipationFees:  day.ParticipationFees,
+		BonusesPaid:        day.BonusesPaid,
+		Surplus:            day.Surplus,
+		Volume:             day.Volume,
+		Trades:             day.Trades,
+		ActiveTraders:      day.ActiveTraders,
+		NewMarkets:         day.NewMarkets,
+		Resolutions:        day.Resolutions,
+		Final:              day.Final,
+		RecordedAt:         day.RecordedAt.UTC(),
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"money_supply",
+			"num_users",
+			"debt_utilized",
+			"unused_debt",
+			"active_bet_volume",
+			"market_creation_fees",
+			"participation_fees",
+			"bonuses_paid",
+			"surplus",
+			"volume",
+			"trades",
+			"active_traders",
+			"new_markets",
+			"resolutions",
+			"final",
+			"recorded_at",
+		}),
+	}).Create(&row).Error
+}
+
+// ListEconomyDays returns the days starting in [from, to), oldest first.
+func (r *GormRepository) ListEconomyDays(ctx context.Context, from, to time.Time) ([]EconomyDay, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []models.EconomyDailyMetric
+	if err := db.Where("day >= ? AND day < ?", from.UTC(), to.UTC()).
+		Order("day ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	days := make([]EconomyDay, 0, len(rows))
+	for _, row := range rows {
+		days = append(days, economyDayFromModel(row))
+	}
+	return days, nil
+}
+
+func economyDayFromModel(row models.EconomyDailyMetric) EconomyDay {
+	return EconomyDay{
+		Day:                row.Day.UTC(),
+		MoneySupply:        row.MoneySupply,
+		NumUsers:           row.NumUsers,
+		DebtUtilized:       row.DebtUtilized,
+		UnusedDebt:         row.UnusedDebt,
+		ActiveBetVolume:    row.ActiveBetVolume,
+		MarketCreationFees: row.MarketCreationFees,
+		ParticipationFees:  row.ParticipationFees,
+		BonusesPaid:        row.BonusesPaid,
+		Surplus:            row.Surplus,
+		EconomyActivity: EconomyActivity{
+			Volume:        row.Volume,
+			Trades:        row.Trades,
+			ActiveTraders: row.ActiveTraders,
+			NewMarkets:    row.NewMarkets,
+			Resolutions:   row.Resolutions,
+		},
+		Final:      row.Final,
+		RecordedAt: row.RecordedAt.UTC(),
+	}
+}
@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestRecordEconomyDayRecountsThePreviousDayOnce(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econ := modelstesting.GenerateEconomicConfig()
+	alice := modelstesting.GenerateUser("alice", 0)
+	bob := modelstesting.GenerateUser("bob", 0)
+	for _, user := range []*models.User{&alice, &bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	day := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	market := modelstesting.GenerateMarket(9300, "alice")
+	market.CreatedAt = day.Add(9 * time.Hour)
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+	for _, seed := range []struct {
+		username string
+		amount   int64
+		at       time.Duration
+	}{
+		{"alice", 100, 10 * time.Hour},
+		{"bob", 50, 11 * time.Hour},
+		{"alice", -40, 12 * time.Hour},
+		{"bob", 30, 23*time.Hour + 30*time.Minute},
+	} {
+		bet := modelstesting.GenerateBet(seed.amount, "YES", seed.username, uint(market.ID), 0)
+		bet.PlacedAt = day.Add(seed.at)
+		bet.CreatedAt = bet.PlacedAt
+		if err := db.Create(&bet).Error; err != nil {
+			t.Fatalf("create bet: %v", err)
+		}
+	}
+
+	service := newAnalyticsService(t, db, econ)
+	ctx := context.Background()
+	if err := service.RecordEconomyDay(ctx, day.Add(20*time.Hour)); err != nil {
+		t.Fatalf("RecordEconomyDay(day 1): %v", err)
+	}
+
+	if err := db.Model(&models.Market{}).Where("id = ?", market.ID).Updates(map[string]any{
+		"is_resolved":                true,
+		"resolution_result":          "YES",
+		"final_resolution_date_time": day.Add(34 * time.Hour),
+	}).Error; err != nil {
+		t.Fatalf("resolve market: %v", err)
+	}
+	if err := service.RecordEconomyDay(ctx, day.Add(36*time.Hour)); err != nil {
+		t.Fatalf("RecordEconomyDay(day 2): %v", err)
+	}
+
+	days, err := service.GetEconomyHistory(ctx, day, day.Add(36*time.Hour), day.Add(36*time.Hour))
+	if err != nil {
+		t.Fatalf("GetEconomyHistory: %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("days = %d, want 2: %+v", len(days), days)
+	}
+
+	first, second := days[0], days[1]
+	if !first.Day.Equal(day) || !first.Final || !first.RecordedAt.Equal(day.Add(20*time.Hour)) {
+		t.Fatalf("first day = %+v, want final day sampled at 20:00", first)
+	}
+	if first.Trades != 4 || first.Volume != 220 || first.ActiveTraders != 2 || first.NewMarkets != 1 || first.Resolutions != 0 {
+		t.Fatalf("first day activity = %+v, want the late trade recounted", first.EconomyActivity)
+	}
+	if first.NumUsers != 2 || first.MoneySupply != 2*econ.Economics.User.MaximumDebtAllowed {
+		t.Fatalf("first day money = supply %d users %d", first.MoneySupply, first.NumUsers)
+	}
+	if first.DebtUtilized != first.MoneySupply-first.UnusedDebt {
+		t.Fatalf("debt utilized = %d, want supply minus unused debt", first.DebtUtilized)
+	}
+	if second.Final || second.Trades != 0 || second.Resolutions != 1 {
+		t.Fatalf("second day = %+v, want an open day with one resolution", second)
+	}
+}
@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestServiceScopedLeaderboardsCountOnlyWindowAndTagProfit(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econ := modelstesting.GenerateEconomicConfig()
+	day := 24 * time.Hour
+
+	tagged := modelstesting.GenerateMarket(9090, "creator")
+	tagged.CreatedAt = time.Now().Add(-20 * day)
+	untagged := modelstesting.GenerateMarket(9091, "creator")
+	untagged.CreatedAt = time.Now().Add(-30 * day)
+	for _, market := range []*models.Market{&tagged, &untagged} {
+		if err := db.Create(market).Error; err != nil {
+			t.Fatalf("create market: %v", err)
+		}
+	}
+	tag := models.MarketTag{Slug: "sports", DisplayName: "Sports", IsActive: true}
+	if err := db.Create(&tag).Error; err != nil {
+		t.Fatalf("create tag: %v", err)
+	}
+	if err := db.Create(&models.MarketTagAssignment{MarketID: tagged.ID, TagID: tag.ID}).Error; err != nil {
+		t.Fatalf("assign tag: %v", err)
+	}
+	bets := []models.Bet{
+		modelstesting.GenerateBet(100, "YES", "alice", uint(tagged.ID), -15*day),
+		modelstesting.GenerateBet(100, "NO", "bob", uint(tagged.ID), -2*day),
+		modelstesting.GenerateBet(50, "YES", "carol", uint(untagged.ID), -10*day),
+	}
+	for i := range bets {
+		if err := db.Create(&bets[i]).Error; err != nil {
+			t.Fatalf("create bet %d: %v", i, err)
+		}
+	}
+
+	service := newAnalyticsService(t, db, econ)
+	ctx := context.Background()
+	byUser := func(scope LeaderboardScope) map[string]GlobalUserProfitability {
+		t.Helper()
+		snapshot, err := service.ComputeScopedLeaderboardSnapshot(ctx, scope)
+		if err != nil {
+			t.Fatalf("ComputeScopedLeaderboardSnapshot(%+v) returned error: %v", scope, err)
+		}
+		entries := make(map[string]GlobalUserProfitability)
+		for _, entry := range snapshot.Result() {
+			entries[entry.Username] = entry
+		}
+		return entries
+	}
+
+	week := byUser(LeaderboardScope{Window: "7d"})
+	if _, ok := week["carol"]; ok || len(week) != 2 {
+		t.Fatalf("expected only alice and bob in the 7d window, got %+v", week)
+	}
+	if alice := week["alice"]; alice.TotalSpent != 0 || alice.TotalProfit == 0 {
+		t.Fatalf("expected alice's held position to move without spending in the window, got %+v", alice)
+	}
+	if bob := week["bob"]; bob.TotalSpent != 100 {
+		t.Fatalf("expected bob's in-window spend to count, got %+v", bob)
+	}
+	if month := byUser(LeaderboardScope{Window: "30d"}); len(month) != 3 {
+		t.Fatalf("expected everyone in the 30d window, got %+v", month)
+	}
+	if sports := byUser(LeaderboardScope{TagSlug: "sports"}); len(sports) != 2 || sports["carol"].Username != "" {
+		t.Fatalf("expected the sports board to skip untagged markets, got %+v", sports)
+	}
+	if _, err := service.ComputeScopedLeaderboardSnapshot(ctx, LeaderboardScope{TagSlug: "missing"}); !errors.Is(err, ErrLeaderboardTagNotFound) {
+		t.Fatalf("expected ErrLeaderboardTagNotFound, got %v", err)
+	}
+
+	scope := LeaderboardScope{Window: "7d", TagSlug: "sports"}
+	if _, err := service.RefreshScopedLeaderboardSnapshot(ctx, scope); err != nil {
+		t.Fatalf("RefreshScopedLeaderboardSnapshot returned error: %v", err)
+	}
+	if err := service.MarkAnalyticsReadModelsStale(ctx, "bet placed"); err != nil {
+		t.Fatalf("MarkAnalyticsReadModelsStale returned error: %v", err)
+	}
+	stored, err := service.GetScopedLeaderboardReadModel(ctx, scope, 20, 0)
+	if err != nil || stored == nil || len(stored.Entries) != 2 || !stored.Freshness.IsStale {
+		t.Fatalf("expected a stale stored sports 7d board, got %+v, %v", stored, err)
+	}
+}
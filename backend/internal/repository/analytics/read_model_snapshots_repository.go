@@ -80,6 +80,27 @@ func (r *GormRepository) MarkAnalyticsReadModelSnapshotStale(ctx context.Context
 		}).Error
 }
 
+// MarkAnalyticsReadModelSnapshotsStaleByKind marks every snapshot of one kind
+// stale, for kinds keyed per scope such as scoped leaderboards.
+func (r *GormRepository) MarkAnalyticsReadModelSnapshotsStaleByKind(ctx context.Context, kind string, reason string) error {
+	if kind == "" {
+		return errors.New("snapshot kind is required")
+	}
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	return db.Model(&models.AnalyticsReadModelSnapshot{}).
+		Where("kind = ?", kind).
+		Updates(map[string]interface{}{
+			"is_stale":        true,
+			"stale_reason":    reason,
+			"marked_stale_at": now,
+			"updated_at":      now,
+		}).Error
+}
+
 func domainAnalyticsReadModelSnapshotToModel(snapshot AnalyticsReadModelSnapshot) models.AnalyticsReadModelSnapshot {
 	source := snapshot.Source
 	if source == "" {
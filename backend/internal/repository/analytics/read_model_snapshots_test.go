@@ -102,3 +102,59 @@ func TestServiceGlobalLeaderboardSnapshotPagesStoredReadModel(t *testing.T) {
 		t.Fatalf("paged entry mismatch:\ngot  %+v\nwant %+v", got, want)
 	}
 }
+
+func TestServiceCalibrationSnapshotRoundTripsAndGoesStale(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econ := modelstesting.GenerateEconomicConfig()
+	now := time.Now().UTC()
+
+	resolved := modelstesting.GenerateMarket(8080, "creator")
+	resolved.CreatedAt = now.Add(-time.Hour)
+	resolved.IsResolved = true
+	resolved.ResolutionResult = "YES"
+	resolved.FinalResolutionDateTime = now.Add(time.Hour)
+	refunded := modelstesting.GenerateMarket(8081, "creator")
+	refunded.IsResolved = true
+	refunded.ResolutionResult = "N/A"
+	for _, market := range []*models.Market{&resolved, &refunded} {
+		if err := db.Create(market).Error; err != nil {
+			t.Fatalf("create market: %v", err)
+		}
+	}
+	bets := []models.Bet{
+		modelstesting.GenerateBet(100, "YES", "alice", uint(resolved.ID), time.Minute),
+		modelstesting.GenerateBet(75, "NO", "bob", uint(resolved.ID), 2*time.Minute),
+		modelstesting.GenerateBet(40, "YES", "carol", uint(refunded.ID), time.Minute),
+	}
+	for i := range bets {
+		if err := db.Create(&bets[i]).Error; err != nil {
+			t.Fatalf("create bet %d: %v", i, err)
+		}
+	}
+
+	service := newAnalyticsService(t, db, econ)
+	ctx := context.Background()
+	refreshed, err := service.RefreshCalibrationSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("RefreshCalibrationSnapshot returned error: %v", err)
+	}
+	if len(refreshed.Snapshot.Users) != 2 || refreshed.Snapshot.User("carol") != nil {
+		t.Fatalf("expected alice and bob scored from the YES market only, got %+v", refreshed.Snapshot.Users)
+	}
+
+	stored, err := service.GetCalibrationReadModel(ctx)
+	if err != nil || stored == nil {
+		t.Fatalf("GetCalibrationReadModel = %+v, %v", stored, err)
+	}
+	alice := stored.Snapshot.User("alice")
+	if alice == nil || alice.BrierScore != refreshed.Snapshot.User("alice").BrierScore || alice.BrierScore >= stored.Snapshot.User("bob").BrierScore {
+		t.Fatalf("expected alice's YES call to beat bob's NO, got %+v", stored.Snapshot.Users)
+	}
+
+	if err := service.MarkAnalyticsReadModelsStale(ctx, "bet placed"); err != nil {
+		t.Fatalf("MarkAnalyticsReadModelsStale returned error: %v", err)
+	}
+	if stale, err := service.GetCalibrationReadModel(ctx); err != nil || !stale.Freshness.IsStale {
+		t.Fatalf("expected the calibration snapshot to be stale, got %+v, %v", stale, err)
+	}
+}
@@ -0,0 +1,137 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"socialpredict/models"
+)
+
+var _ ReconciliationRepository = (*GormRepository)(nil)
+
+type reconciliationMarketRow struct {
+	ID               uint
+	CreatorUsername  string
+	StewardUsername  string
+	LifecycleStatus  string
+	CreatedAt        time.Time
+	IsResolved       bool
+	ResolutionResult string
+	ProposalCost     int64
+}
+
+// ListReconciliationUsers returns every user's initial and current balance.
+func (r *GormRepository) ListReconciliationUsers(ctx context.Context) ([]ReconciliationUser, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var users []ReconciliationUser
+	if err := db.Table("users").
+		Select("username", "initial_account_balance", "account_balance").
+		Order("username ASC").
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListReconciliationMarkets returns every market, deleted ones included,
+// since their creation charge and trades still moved money.
+func (r *GormRepository) ListReconciliationMarkets(ctx context.Context) ([]ReconciliationMarket, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []reconciliationMarketRow
+	if err := db.Table("markets").
+		Select("id", "creator_username", "steward_username", "lifecycle_status", "created_at", "is_resolved", "resolution_result", "proposal_cost").
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	markets := make([]ReconciliationMarket, len(rows))
+	for i, row := range rows {
+		markets[i] = ReconciliationMarket(row)
+	}
+	return markets, nil
+}
+
+// ListAnswerAdditionCharges returns the cost of each approved answer addition
+// as a debit of its proposer.
+func (r *GormRepository) ListAnswerAdditionCharges(ctx context.Context) ([]ReconciliationAdjustment, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var charges []ReconciliationAdjustment
+	if err := db.Model(&models.MarketGroupAnswerAddition{}).
+		Select("proposed_by AS username, -addition_cost AS amount").
+		Where("status = ? AND addition_cost > 0", "approved").
+		Order("id ASC").
+		Scan(&charges).Error; err != nil {
+		return nil, err
+	}
+	return charges, nil
+}
+
+// ListSeasonBalanceResets returns the balance change each season reset made.
+func (r *GormRepository) ListSeasonBalanceResets(ctx context.Context) ([]ReconciliationAdjustment, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var resets []ReconciliationAdjustment
+	if err := db.Model(&models.SeasonBalanceReset{}).
+		Select("username, balance_after - balance_before AS amount").
+		Order("id ASC").
+		Scan(&resets).Error; err != nil {
+		return nil, err
+	}
+	return resets, nil
+}
+
+// ListSeasonLiquidationCredits returns the credit of each sale bet a season
+// rollover wrote.
+func (r *GormRepository) ListSeasonLiquidationCredits(ctx context.Context) ([]ReconciliationSaleCredit, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var credits []ReconciliationSaleCredit
+	if err := db.Model(&models.SeasonLiquidation{}).
+		Select("bet_id, value AS amount").
+		Order("id ASC").
+		Scan(&credits).Error; err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// ListMarketAccountingSnapshots returns every stored market accounting
+// snapshot.
+func (r *GormRepository) ListMarketAccountingSnapshots(ctx context.Context) ([]StoredMarketAccounting, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []models.MarketAccountingSnapshot
+	if err := db.Order("market_id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	snapshots := make([]StoredMarketAccounting, len(rows))
+	for i, row := range rows {
+		snapshots[i] = StoredMarketAccounting{
+			MarketID:           uint(row.MarketID),
+			LastProbability:    row.LastProbability,
+			NetBetVolume:       row.NetBetVolume,
+			MarketDust:         row.MarketDust,
+			VolumeWithDust:     row.VolumeWithDust,
+			UserCount:          row.UserCount,
+			BetCount:           row.BetCount,
+			LastProcessedBetID: row.LastProcessedBetID,
+			IsStale:            row.IsStale,
+		}
+	}
+	return snapshots, nil
+}
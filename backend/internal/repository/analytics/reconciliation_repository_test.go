@@ -0,0 +1,107 @@
+package analytics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"socialpredict/internal/app"
+	"socialpredict/internal/domain/analytics"
+	dbets "socialpredict/internal/domain/bets"
+	configsvc "socialpredict/internal/service/config"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestReconcileBalancesLedgerAndFlagsTampering(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econConfig, _ := modelstesting.UseStandardTestEconomics(t)
+	ctx := context.Background()
+
+	users := []models.User{
+		modelstesting.GenerateUser("alice", 0),
+		modelstesting.GenerateUser("bob", 0),
+		modelstesting.GenerateUser("carol", 0),
+	}
+	users[0].UserType = "MODERATOR"
+	users[0].ModeratorStatus = "active"
+	for i := range users {
+		if err := db.Create(&users[i]).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	market := modelstesting.GenerateMarket(7101, "alice")
+	market.IsResolved = false
+	market.StewardUsername = market.CreatorUsername
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+	if err := modelstesting.AdjustUserBalance(db, "alice", -econConfig.Economics.MarketIncentives.CreateMarketCost); err != nil {
+		t.Fatalf("apply creation fee: %v", err)
+	}
+
+	container := app.BuildApplicationWithConfigService(db, configsvc.NewStaticService(econConfig))
+	betsService := container.GetBetsService()
+	for _, bet := range []struct {
+		username string
+		amount   int64
+		outcome  string
+	}{
+		{"bob", 20, "YES"},
+		{"carol", 30, "NO"},
+		{"bob", 10, "YES"},
+	} {
+		if _, err := betsService.Place(ctx, dbets.PlaceRequest{Username: bet.username, MarketID: uint(market.ID), Amount: bet.amount, Outcome: bet.outcome}); err != nil {
+			t.Fatalf("place bet for %s: %v", bet.username, err)
+		}
+	}
+	if _, err := betsService.Sell(ctx, dbets.SellRequest{Username: "bob", MarketID: uint(market.ID), Amount: 5, Outcome: "YES"}); err != nil {
+		t.Fatalf("sell: %v", err)
+	}
+
+	marketsService := container.GetMarketsService()
+	if err := marketsService.ResolveMarket(ctx, market.ID, "YES", "alice"); err != nil {
+		t.Fatalf("ResolveMarket: %v", err)
+	}
+	if _, err := marketsService.RefreshMarketAccountingSnapshot(ctx, market.ID); err != nil {
+		t.Fatalf("RefreshMarketAccountingSnapshot: %v", err)
+	}
+
+	analyticsService := container.GetAnalyticsService()
+	now := time.Date(2026, 7, 11, 9, 0, 0, 0, time.UTC)
+	report, err := analyticsService.Reconcile(ctx, now)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if !report.Balanced || len(report.Discrepancies) != 0 {
+		t.Fatalf("report = %+v, want a balanced ledger", report)
+	}
+	if report.UsersChecked != 3 || report.MarketsChecked != 1 || report.SnapshotsChecked != 1 || !report.GeneratedAt.Equal(now) {
+		t.Fatalf("report counts = %+v", report)
+	}
+
+	if err := modelstesting.AdjustUserBalance(db, "carol", 7); err != nil {
+		t.Fatalf("tamper balance: %v", err)
+	}
+	if err := db.Model(&models.MarketAccountingSnapshot{}).
+		Where("market_id = ?", market.ID).
+		Update("net_bet_volume", 1).Error; err != nil {
+		t.Fatalf("tamper snapshot: %v", err)
+	}
+
+	report, err = analyticsService.Reconcile(ctx, now)
+	if err != nil {
+		t.Fatalf("Reconcile after tampering: %v", err)
+	}
+	if report.Balanced || len(report.Discrepancies) != 2 {
+		t.Fatalf("discrepancies = %+v, want the balance and the snapshot", report.Discrepancies)
+	}
+	balance, snapshot := report.Discrepancies[0], report.Discrepancies[1]
+	if balance.Check != analytics.ReconciliationCheckUserBalance || balance.Username != "carol" || balance.Actual-balance.Expected != 7 {
+		t.Fatalf("balance discrepancy = %+v", balance)
+	}
+	if snapshot.Check != analytics.ReconciliationCheckMarketSnapshot || snapshot.Field != "netBetVolume" || snapshot.Actual != 1 || snapshot.MarketID != uint(market.ID) {
+		t.Fatalf("snapshot discrepancy = %+v", snapshot)
+	}
+}
@@ -127,6 +127,83 @@ func (r *GormRepository) ListBetsForMarket(ctx context.Context, marketID uint) (
 	return mapBets(bets), nil
 }
 
+var _ CalibrationRepository = (*GormRepository)(nil)
+
+// ListResolvedBinaryMarkets returns markets resolved YES or NO with the
+// instant each resolved, for calibration scoring.
+func (r *GormRepository) ListResolvedBinaryMarkets(ctx context.Context) ([]ResolvedMarketRecord, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []analyticsResolvedMarketRow
+	if err := db.Table("markets").
+		Select("id", "created_at", "final_resolution_date_time", "resolution_result").
+		Where("deleted_at IS NULL AND is_resolved = ? AND UPPER(resolution_result) IN ?", true, []string{"YES", "NO"}).
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	markets := make([]ResolvedMarketRecord, len(rows))
+	for i, row := range rows {
+		markets[i] = ResolvedMarketRecord{
+			ID:               row.ID,
+			CreatedAt:        row.CreatedAt,
+			ResolvedAt:       row.FinalResolutionDateTime,
+			ResolutionResult: row.ResolutionResult,
+		}
+	}
+	return markets, nil
+}
+
+var _ ScopedLeaderboardRepository = (*GormRepository)(nil)
+
+// ListLeaderboardMarkets returns markets with the instant each resolved,
+// limited to those tagged tagSlug when it is set.
+func (r *GormRepository) ListLeaderboardMarkets(ctx context.Context, tagSlug string) ([]LeaderboardMarketRecord, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := db.Table("markets").
+		Select("markets.id", "markets.created_at", "markets.is_resolved", "markets.resolution_result", "markets.proposal_cost", "markets.final_resolution_date_time").
+		Where("markets.deleted_at IS NULL")
+	if tagSlug != "" {
+		var tagIDs []int64
+		if err := db.Table("market_tags").
+			Where("slug = ? AND is_active = ? AND deleted_at IS NULL", tagSlug, true).
+			Pluck("id", &tagIDs).Error; err != nil {
+			return nil, err
+		}
+		if len(tagIDs) == 0 {
+			return nil, ErrLeaderboardTagNotFound
+		}
+		query = query.
+			Joins("JOIN market_tag_assignments ON market_tag_assignments.market_id = markets.id AND market_tag_assignments.deleted_at IS NULL").
+			Where("market_tag_assignments.tag_id = ?", tagIDs[0])
+	}
+	var rows []analyticsLeaderboardMarketRow
+	if err := query.Order("markets.id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	markets := make([]LeaderboardMarketRecord, len(rows))
+	for i, row := range rows {
+		markets[i] = LeaderboardMarketRecord{
+			MarketRecord: MarketRecord{
+				ID:               row.ID,
+				CreatedAt:        row.CreatedAt,
+				IsResolved:       row.IsResolved,
+				ResolutionResult: row.ResolutionResult,
+				ProposalCost:     row.ProposalCost,
+			},
+		}
+		if row.IsResolved {
+			markets[i].ResolvedAt = row.FinalResolutionDateTime
+		}
+	}
+	return markets, nil
+}
+
 func (r *GormRepository) ListBetsOrdered(ctx context.Context) ([]boundary.Bet, error) {
 	db, err := r.dbWithContext(ctx)
 	if err != nil {
@@ -404,6 +481,22 @@ type analyticsMarketRow struct {
 	ProposalCost     int64
 }
 
+type analyticsLeaderboardMarketRow struct {
+	ID                      uint
+	CreatedAt               time.Time
+	IsResolved              bool
+	ResolutionResult        string
+	ProposalCost            int64
+	FinalResolutionDateTime time.Time
+}
+
+type analyticsResolvedMarketRow struct {
+	ID                      uint
+	CreatedAt               time.Time
+	FinalResolutionDateTime time.Time
+	ResolutionResult        string
+}
+
 type analyticsWorkProfitMarketRow struct {
 	ID               uint
 	CreatorUsername  string
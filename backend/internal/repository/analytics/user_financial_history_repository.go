@@ -0,0 +1,176 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	historyGranularityHour = "hour"
+	historyGranularityDay  = "day"
+	historyCompactionChunk = 500
+)
+
+var _ UserFinancialHistoryRepository = (*GormRepository)(nil)
+
+// ListUserAccounts returns the named users' balances, or every user's when
+// usernames is empty.
+func (r *GormRepository) ListUserAccounts(ctx context.Context, usernames []string) ([]UserAccount, error) {
+	if len(usernames) == 0 {
+		return r.ListUsers(ctx)
+	}
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var users []analyticsUserRow
+	if err := db.Table("users").
+		Select("username", "account_balance").
+		Where("username IN ?", usernames).
+		Order("username ASC").
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return mapUsers(users), nil
+}
+
+// UpsertUserFinancialHistory stores each point in its hour, replacing an
+// earlier sample from the same hour.
+func (r *GormRepository) UpsertUserFinancialHistory(ctx context.Context, points []UserFinancialHistoryPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([]models.UserFinancialHistoryPoint, 0, len(points))
+	for _, point := range points {
+		if point.Username == "" {
+			return errors.New("username is required")
+		}
+		at := point.At.UTC()
+		rows = append(rows, models.UserFinancialHistoryPoint{
+			Username:          point.Username,
+			BucketStart:       at.Truncate(time.Hour),
+			Granularity:       historyGranularityHour,
+			AccountBalance:    point.AccountBalance,
+			Equity:            point.Equity,
+			AmountInPlay:      point.AmountInPlay,
+			RealizedProfits:   point.RealizedProfits,
+			UnrealizedProfits: point.UnrealizedProfits,
+			RecordedAt:        at,
+		})
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "username"}, {Name: "bucket_start"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"account_balance",
+			"equity",
+			"amount_in_play",
+			"realized_profits",
+			"unrealized_profits",
+			"recorded_at",
+		}),
+	}).Create(&rows).Error
+}
+
+// ListUserFinancialHistory returns the user's samples taken in [from, to),
+// oldest first.
+func (r *GormRepository) ListUserFinancialHistory(ctx context.Context, username string, from, to time.Time) ([]UserFinancialHistoryPoint, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []models.UserFinancialHistoryPoint
+	if err := db.Where("username = ? AND recorded_at >= ? AND recorded_at < ?", username, from.UTC(), to.UTC()).
+		Order("recorded_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	points := make([]UserFinancialHistoryPoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, UserFinancialHistoryPoint{
+			Username:          row.Username,
+			At:                row.RecordedAt.UTC(),
+			AccountBalance:    row.AccountBalance,
+			Equity:            row.Equity,
+			AmountInPlay:      row.AmountInPlay,
+			RealizedProfits:   row.RealizedProfits,
+			UnrealizedProfits: row.UnrealizedProfits,
+		})
+	}
+	return points, nil
+}
+
+// CompactUserFinancialHistory keeps only the last hourly sample of each UTC
+// day before cutoff, marking it daily, and returns how many were removed.
+// Daily samples are never revisited, so each run only reads the hours that
+// aged out since the last one.
+func (r *GormRepository) CompactUserFinancialHistory(ctx context.Context, cutoff time.Time) (int64, error) {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var rows []models.UserFinancialHistoryPoint
+	if err := db.Select("id", "username", "bucket_start").
+		Where("granularity = ? AND bucket_start < ?", historyGranularityHour, cutoff.UTC()).
+		Order("username ASC, bucket_start ASC").
+		Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	var keep, drop []int64
+	for i, row := range rows {
+		last := i == len(rows)-1
+		if !last {
+			next := rows[i+1]
+			last = next.Username != row.Username || !sameUTCDay(next.BucketStart, row.BucketStart)
+		}
+		if last {
+			keep = append(keep, row.ID)
+		} else {
+			drop = append(drop, row.ID)
+		}
+	}
+
+	var removed int64
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(drop); start += historyCompactionChunk {
+			end := min(start+historyCompactionChunk, len(drop))
+			result := tx.Where("id IN ?", drop[start:end]).Delete(&models.UserFinancialHistoryPoint{})
+			if result.Error != nil {
+				return result.Error
+			}
+			removed += result.RowsAffected
+		}
+		for start := 0; start < len(keep); start += historyCompactionChunk {
+			end := min(start+historyCompactionChunk, len(keep))
+			if err := tx.Model(&models.UserFinancialHistoryPoint{}).
+				Where("id IN ?", keep[start:end]).
+				Update("granularity", historyGranularityDay).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+func sameUTCDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestRecordUserFinancialHistoryReplacesSamplesInTheSameHour(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econ := modelstesting.GenerateEconomicConfig()
+	alice := modelstesting.GenerateUser("alice", 500)
+	bob := modelstesting.GenerateUser("bob", 0)
+	for _, user := range []*models.User{&alice, &bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	market := modelstesting.GenerateMarket(9200, "creator")
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+	bet := modelstesting.GenerateBet(100, "YES", "alice", uint(market.ID), -time.Hour)
+	if err := db.Create(&bet).Error; err != nil {
+		t.Fatalf("create bet: %v", err)
+	}
+
+	service := newAnalyticsService(t, db, econ)
+	ctx := context.Background()
+	hour := time.Date(2026, 7, 8, 9, 0, 0, 0, time.UTC)
+	if recorded, err := service.RecordUserFinancialHistory(ctx, nil, hour.Add(5*time.Minute)); err != nil || recorded != 2 {
+		t.Fatalf("RecordUserFinancialHistory(all) = %d, %v", recorded, err)
+	}
+	if recorded, err := service.RecordUserFinancialHistory(ctx, []string{"alice"}, hour.Add(50*time.Minute)); err != nil || recorded != 1 {
+		t.Fatalf("RecordUserFinancialHistory(alice) = %d, %v", recorded, err)
+	}
+
+	var rows []models.UserFinancialHistoryPoint
+	if err := db.Where("username = ?", "alice").Find(&rows).Error; err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(rows) != 1 || !rows[0].RecordedAt.Equal(hour.Add(50*time.Minute)) || !rows[0].BucketStart.Equal(hour) {
+		t.Fatalf("expected one sample for the hour from the later record, got %+v", rows)
+	}
+	if rows[0].AccountBalance != 500 || rows[0].AmountInPlay != 100 || rows[0].Granularity != "hour" {
+		t.Fatalf("unexpected sample %+v", rows[0])
+	}
+
+	points, err := service.GetUserFinancialHistory(ctx, "alice", hour, hour.Add(time.Hour), "hour", hour)
+	if err != nil || len(points) != 1 || points[0].AmountInPlay != 100 {
+		t.Fatalf("GetUserFinancialHistory = %+v, %v", points, err)
+	}
+}
+
+func TestCompactUserFinancialHistoryKeepsLastSamplePerDay(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	var points []UserFinancialHistoryPoint
+	for _, username := range []string{"alice", "bob"} {
+		for _, offset := range []time.Duration{1, 5, 23, 25, 26} {
+			points = append(points, UserFinancialHistoryPoint{Username: username, At: day.Add(offset * time.Hour), Equity: int64(offset)})
+		}
+	}
+	// A sample after the cutoff stays hourly.
+	points = append(points, UserFinancialHistoryPoint{Username: "alice", At: day.Add(49 * time.Hour), Equity: 49})
+	if err := repo.UpsertUserFinancialHistory(ctx, points); err != nil {
+		t.Fatalf("UpsertUserFinancialHistory returned error: %v", err)
+	}
+
+	removed, err := repo.CompactUserFinancialHistory(ctx, day.Add(48*time.Hour))
+	if err != nil || removed != 6 {
+		t.Fatalf("CompactUserFinancialHistory = %d, %v; want 6 removed", removed, err)
+	}
+	var rows []models.UserFinancialHistoryPoint
+	if err := db.Where("username = ?", "alice").Order("bucket_start ASC").Find(&rows).Error; err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 samples left for alice, got %+v", rows)
+	}
+	want := []struct {
+		equity      int64
+		granularity string
+	}{{23, "day"}, {26, "day"}, {49, "hour"}}
+	for i, row := range rows {
+		if row.Equity != want[i].equity || row.Granularity != want[i].granularity {
+			t.Fatalf("sample %d = %+v, want %+v", i, row, want[i])
+		}
+	}
+
+	if removed, err := repo.CompactUserFinancialHistory(ctx, day.Add(48*time.Hour)); err != nil || removed != 0 {
+		t.Fatalf("second compaction = %d, %v; want nothing to do", removed, err)
+	}
+}
@@ -99,6 +99,23 @@ func (r *GormRepository) MarkUserFinancialMetricSnapshotStale(ctx context.Contex
 		}).Error
 }
 
+// MarkUserFinancialMetricSnapshotsStale marks every display snapshot stale.
+func (r *GormRepository) MarkUserFinancialMetricSnapshotsStale(ctx context.Context, reason string) error {
+	db, err := r.dbWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	return db.Model(&models.UserFinancialMetricSnapshot{}).
+		Where("is_stale = ?", false).
+		Updates(map[string]interface{}{
+			"is_stale":        true,
+			"stale_reason":    reason,
+			"marked_stale_at": now,
+			"updated_at":      now,
+		}).Error
+}
+
 func domainUserFinancialMetricSnapshotToModel(snapshot UserFinancialMetricSnapshot) models.UserFinancialMetricSnapshot {
 	source := snapshot.Source
 	if source == "" {
@@ -0,0 +1,16 @@
+package bets
+
+import (
+	"context"
+
+	devents "socialpredict/internal/domain/events"
+	revents "socialpredict/internal/repository/events"
+)
+
+var _ devents.Recorder = (*GormRepository)(nil)
+
+// RecordEvents appends domain events to the outbox on this repository's
+// handle, so inside PlaceBetTransaction or SellBetTransaction they commit with the trade.
+func (r *GormRepository) RecordEvents(ctx context.Context, events ...devents.Event) error {
+	return revents.Append(ctx, r.db, events...)
+}
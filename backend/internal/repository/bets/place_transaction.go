@@ -70,23 +70,25 @@ func modelUserToDomain(user *models.User) *dusers.User {
 		return nil
 	}
 	return &dusers.User{
-		ID:                    user.ID,
-		Username:              user.Username,
-		DisplayName:           user.DisplayName,
-		Email:                 user.Email,
-		PasswordHash:          user.Password,
-		UserType:              user.UserType,
-		InitialAccountBalance: user.InitialAccountBalance,
-		AccountBalance:        user.AccountBalance,
-		PersonalEmoji:         user.PersonalEmoji,
-		Description:           user.Description,
-		PersonalLink1:         user.PersonalLink1,
-		PersonalLink2:         user.PersonalLink2,
-		PersonalLink3:         user.PersonalLink3,
-		PersonalLink4:         user.PersonalLink4,
-		APIKey:                user.APIKey,
-		MustChangePassword:    user.MustChangePassword,
-		CreatedAt:             user.CreatedAt,
-		UpdatedAt:             user.UpdatedAt,
+		ID:                     user.ID,
+		Username:               user.Username,
+		DisplayName:            user.DisplayName,
+		Email:                  user.Email,
+		PasswordHash:           user.Password,
+		UserType:               user.UserType,
+		InitialAccountBalance:  user.InitialAccountBalance,
+		AccountBalance:         user.AccountBalance,
+		PersonalEmoji:          user.PersonalEmoji,
+		Description:            user.Description,
+		PersonalLink1:          user.PersonalLink1,
+		PersonalLink2:          user.PersonalLink2,
+		PersonalLink3:          user.PersonalLink3,
+		PersonalLink4:          user.PersonalLink4,
+		APIKey:                 user.APIKey,
+		MustChangePassword:     user.MustChangePassword,
+		AccountStatus:          dusers.NormalizeAccountStatus(user.AccountStatus),
+		AccountStatusExpiresAt: user.AccountStatusExpiresAt,
+		CreatedAt:              user.CreatedAt,
+		UpdatedAt:              user.UpdatedAt,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	dbets "socialpredict/internal/domain/bets"
+	devents "socialpredict/internal/domain/events"
 	dusers "socialpredict/internal/domain/users"
 	"socialpredict/models"
 	"socialpredict/models/modelstesting"
@@ -97,3 +98,40 @@ func loadPlacementInputs(ctx context.Context, repo dbets.Repository, users dbets
 	}
 	return user, hasBet, nil
 }
+
+func TestGormRepositoryPlaceBetTransactionCommitsOutboxEventsWithTheChange(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	event := devents.Event{Type: devents.BetPlaced, MarketID: 1, Username: "bettor"}
+
+	rollbackErr := errors.New("forced rollback")
+	err := repo.PlaceBetTransaction(ctx, func(ctx context.Context, txRepo dbets.Repository, _ dbets.UserService) error {
+		if err := devents.Record(ctx, txRepo, event); err != nil {
+			return err
+		}
+		return rollbackErr
+	})
+	if !errors.Is(err, rollbackErr) {
+		t.Fatalf("expected forced rollback error, got %v", err)
+	}
+	assertOutboxCount(t, db, 0)
+
+	if err := repo.PlaceBetTransaction(ctx, func(ctx context.Context, txRepo dbets.Repository, _ dbets.UserService) error {
+		return devents.Record(ctx, txRepo, event)
+	}); err != nil {
+		t.Fatalf("PlaceBetTransaction returned error: %v", err)
+	}
+	assertOutboxCount(t, db, 1)
+}
+
+func assertOutboxCount(t *testing.T, db *gorm.DB, want int64) {
+	t.Helper()
+	var count int64
+	if err := db.Model(&models.OutboxEvent{}).Where("event_type = ?", string(devents.BetPlaced)).Count(&count).Error; err != nil {
+		t.Fatalf("count outbox events: %v", err)
+	}
+	if count != want {
+		t.Fatalf("outbox events = %d, want %d", count, want)
+	}
+}
@@ -0,0 +1,149 @@
+package comments
+
+import (
+	"context"
+	"errors"
+
+	dcomments "socialpredict/internal/domain/comments"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// GormRepository implements the comments domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dcomments.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based comments repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// CreateComment inserts the comment and sets its ID.
+func (r *GormRepository) CreateComment(ctx context.Context, comment *dcomments.Comment) error {
+	row := commentToModel(comment)
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	comment.ID = row.ID
+	return nil
+}
+
+// GetComment loads one comment by ID.
+func (r *GormRepository) GetComment(ctx context.Context, id int64) (*dcomments.Comment, error) {
+	var row models.Comment
+	if err := r.db.WithContext(ctx).First(&row, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dcomments.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return modelToComment(&row), nil
+}
+
+// ListRoots returns a page of the target's top-level comments oldest first.
+func (r *GormRepository) ListRoots(ctx context.Context, target dcomments.Target, limit, offset int) ([]*dcomments.Comment, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).
+		Where("market_id = ? AND market_group_id = ? AND root_id = 0", target.MarketID, target.MarketGroupID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []models.Comment
+	if err := query.Order("created_at ASC, id ASC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return modelsToComments(rows), total, nil
+}
+
+// ListReplies returns every reply under the given roots oldest first.
+func (r *GormRepository) ListReplies(ctx context.Context, rootIDs []int64) ([]*dcomments.Comment, error) {
+	if len(rootIDs) == 0 {
+		return nil, nil
+	}
+	var rows []models.Comment
+	if err := r.db.WithContext(ctx).
+		Where("root_id IN ?", rootIDs).
+		Order("created_at ASC, id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return modelsToComments(rows), nil
+}
+
+// UpdateComment saves the mutable fields of an existing comment.
+func (r *GormRepository) UpdateComment(ctx context.Context, comment *dcomments.Comment) error {
+	result := r.db.WithContext(ctx).Model(&models.Comment{}).
+		Where("id = ?", comment.ID).
+		Updates(map[string]any{
+			"body":          comment.Body,
+			"body_html":     comment.BodyHTML,
+			"edited_at":     comment.EditedAt,
+			"deleted_at":    comment.DeletedAt,
+			"deleted_by":    comment.DeletedBy,
+			"hidden_at":     comment.HiddenAt,
+			"hidden_by":     comment.HiddenBy,
+			"hidden_reason": comment.HiddenReason,
+			"updated_at":    comment.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dcomments.ErrCommentNotFound
+	}
+	return nil
+}
+
+func commentToModel(comment *dcomments.Comment) models.Comment {
+	return models.Comment{
+		ID:            comment.ID,
+		MarketID:      comment.MarketID,
+		MarketGroupID: comment.MarketGroupID,
+		RootID:        comment.RootID,
+		ParentID:      comment.ParentID,
+		Username:      comment.Username,
+		Body:          comment.Body,
+		BodyHTML:      comment.BodyHTML,
+		EditedAt:      comment.EditedAt,
+		DeletedAt:     comment.DeletedAt,
+		DeletedBy:     comment.DeletedBy,
+		HiddenAt:      comment.HiddenAt,
+		HiddenBy:      comment.HiddenBy,
+		HiddenReason:  comment.HiddenReason,
+		CreatedAt:     comment.CreatedAt,
+		UpdatedAt:     comment.UpdatedAt,
+	}
+}
+
+func modelToComment(row *models.Comment) *dcomments.Comment {
+	return &dcomments.Comment{
+		ID:            row.ID,
+		MarketID:      row.MarketID,
+		MarketGroupID: row.MarketGroupID,
+		RootID:        row.RootID,
+		ParentID:      row.ParentID,
+		Username:      row.Username,
+		Body:          row.Body,
+		BodyHTML:      row.BodyHTML,
+		EditedAt:      row.EditedAt,
+		DeletedAt:     row.DeletedAt,
+		DeletedBy:     row.DeletedBy,
+		HiddenAt:      row.HiddenAt,
+		HiddenBy:      row.HiddenBy,
+		HiddenReason:  row.HiddenReason,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
+
+func modelsToComments(rows []models.Comment) []*dcomments.Comment {
+	comments := make([]*dcomments.Comment, 0, len(rows))
+	for i := range rows {
+		comments = append(comments, modelToComment(&rows[i]))
+	}
+	return comments
+}
@@ -0,0 +1,61 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dcomments "socialpredict/internal/domain/comments"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryThreads(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	create := func(target dcomments.Target, rootID, parentID int64, body string, offset time.Duration) *dcomments.Comment {
+		t.Helper()
+		comment := &dcomments.Comment{MarketID: target.MarketID, MarketGroupID: target.MarketGroupID, RootID: rootID, ParentID: parentID, Username: "alice", Body: body, BodyHTML: "<p>" + body + "</p>", CreatedAt: now.Add(offset), UpdatedAt: now.Add(offset)}
+		if err := repo.CreateComment(ctx, comment); err != nil || comment.ID == 0 {
+			t.Fatalf("CreateComment(%s) = %v", body, err)
+		}
+		return comment
+	}
+
+	market := dcomments.MarketTarget(7)
+	first := create(market, 0, 0, "first", 0)
+	second := create(market, 0, 0, "second", time.Minute)
+	reply := create(market, first.ID, first.ID, "reply", 2*time.Minute)
+	create(market, first.ID, reply.ID, "nested", 3*time.Minute)
+	create(dcomments.GroupTarget(7), 0, 0, "group", 0)
+
+	roots, total, err := repo.ListRoots(ctx, market, 1, 1)
+	if err != nil || total != 2 || len(roots) != 1 || roots[0].ID != second.ID {
+		t.Fatalf("ListRoots = %+v total=%d err=%v", roots, total, err)
+	}
+	replies, err := repo.ListReplies(ctx, []int64{first.ID, second.ID})
+	if err != nil || len(replies) != 2 || replies[0].Body != "reply" || replies[1].ParentID != reply.ID {
+		t.Fatalf("ListReplies = %+v err=%v", replies, err)
+	}
+
+	hiddenAt := now.Add(time.Hour)
+	second.HiddenAt = &hiddenAt
+	second.HiddenBy = "mod"
+	second.HiddenReason = "spam"
+	if err := repo.UpdateComment(ctx, second); err != nil {
+		t.Fatalf("UpdateComment returned error: %v", err)
+	}
+	loaded, err := repo.GetComment(ctx, second.ID)
+	if err != nil || loaded.HiddenAt == nil || loaded.HiddenReason != "spam" || loaded.Target() != market {
+		t.Fatalf("GetComment = %+v err=%v", loaded, err)
+	}
+	if _, err := repo.GetComment(ctx, 999); !errors.Is(err, dcomments.ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+	if err := repo.UpdateComment(ctx, &dcomments.Comment{ID: 999}); !errors.Is(err, dcomments.ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound on update, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	demail "socialpredict/internal/domain/email"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements the email domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ demail.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based email repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// EnqueueMessage inserts the message unless the user already has one with
+// the same dedupe key.
+func (r *GormRepository) EnqueueMessage(ctx context.Context, message *demail.Message) (bool, error) {
+	row := messageToModel(message)
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&row)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	message.ID = row.ID
+	return true, nil
+}
+
+// ClaimDueMessages leases due pending messages oldest first using a
+// conditional update per row, so concurrent workers never share a lease.
+func (r *GormRepository) ClaimDueMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*demail.Message, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	now = now.UTC()
+	var candidates []models.EmailMessage
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.EmailMessagePending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*demail.Message, 0, len(candidates))
+	for i := range candidates {
+		leaseUntil := now.Add(lease)
+		result := r.db.WithContext(ctx).Model(&models.EmailMessage{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", candidates[i].ID, models.EmailMessagePending, now).
+			Update("next_attempt_at", leaseUntil)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		candidates[i].NextAttemptAt = leaseUntil
+		claimed = append(claimed, modelToMessage(&candidates[i]))
+	}
+	return claimed, nil
+}
+
+// SaveMessageOutcome records the result of an attempt.
+func (r *GormRepository) SaveMessageOutcome(ctx context.Context, message *demail.Message) error {
+	return r.db.WithContext(ctx).Model(&models.EmailMessage{}).
+		Where("id = ?", message.ID).
+		Updates(map[string]any{
+			"status":          string(message.Status),
+			"attempts":        message.Attempts,
+			"next_attempt_at": message.NextAttemptAt.UTC(),
+			"last_error":      message.LastError,
+			"sent_at":         message.SentAt,
+		}).Error
+}
+
+// ListPreferences returns the user's stored per-kind choices.
+func (r *GormRepository) ListPreferences(ctx context.Context, username string) (map[demail.Kind]bool, error) {
+	var rows []models.EmailPreference
+	if err := r.db.WithContext(ctx).Where("username = ?", username).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	preferences := make(map[demail.Kind]bool, len(rows))
+	for _, row := range rows {
+		preferences[demail.Kind(row.Kind)] = row.Enabled
+	}
+	return preferences, nil
+}
+
+// SavePreferences upserts one row per given kind.
+func (r *GormRepository) SavePreferences(ctx context.Context, username string, preferences map[demail.Kind]bool, at time.Time) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	rows := make([]models.EmailPreference, 0, len(preferences))
+	for kind, enabled := range preferences {
+		rows = append(rows, models.EmailPreference{Username: username, Kind: string(kind), Enabled: enabled, UpdatedAt: at.UTC()})
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "username"}, {Name: "kind"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).
+		Create(&rows).Error
+}
+
+// ListOptedIn returns the usernames that enabled kind, alphabetically.
+func (r *GormRepository) ListOptedIn(ctx context.Context, kind demail.Kind) ([]string, error) {
+	var usernames []string
+	err := r.db.WithContext(ctx).Model(&models.EmailPreference{}).
+		Where("kind = ? AND enabled = ?", string(kind), true).
+		Order("username ASC").
+		Pluck("username", &usernames).Error
+	return usernames, err
+}
+
+// EnsureUnsubscribeToken stores candidate unless the user already has a
+// token and returns the stored token.
+func (r *GormRepository) EnsureUnsubscribeToken(ctx context.Context, username, candidate string) (string, error) {
+	row := models.EmailUnsubscribeToken{Username: username, Token: candidate}
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "username"}}, DoNothing: true}).
+		Create(&row).Error; err != nil {
+		return "", err
+	}
+	var stored models.EmailUnsubscribeToken
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&stored).Error; err != nil {
+		return "", err
+	}
+	return stored.Token, nil
+}
+
+// UsernameForToken resolves an unsubscribe token.
+func (r *GormRepository) UsernameForToken(ctx context.Context, token string) (string, error) {
+	var row models.EmailUnsubscribeToken
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", demail.ErrInvalidToken
+		}
+		return "", err
+	}
+	return row.Username, nil
+}
+
+// LatestDigestRun returns the user's most recent digest run, or nil.
+func (r *GormRepository) LatestDigestRun(ctx context.Context, username string) (*demail.DigestRun, error) {
+	var rows []models.EmailDigestRun
+	if err := r.db.WithContext(ctx).Where("username = ?", username).
+		Order("created_at DESC, id DESC").Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &demail.DigestRun{Username: rows[0].Username, Week: rows[0].Week, Equity: rows[0].Equity, CreatedAt: rows[0].CreatedAt}, nil
+}
+
+// RecordDigestRun inserts the run unless the user already has one for the week.
+func (r *GormRepository) RecordDigestRun(ctx context.Context, run *demail.DigestRun) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.EmailDigestRun{Username: run.Username, Week: run.Week, Equity: run.Equity, CreatedAt: run.CreatedAt.UTC()}).Error
+}
+
+func messageToModel(message *demail.Message) models.EmailMessage {
+	return models.EmailMessage{
+		ID:             message.ID,
+		Username:       message.Username,
+		DedupeKey:      message.DedupeKey,
+		ToAddress:      message.ToAddress,
+		Kind:           string(message.Kind),
+		Subject:        message.Subject,
+		TextBody:       message.TextBody,
+		HTMLBody:       message.HTMLBody,
+		UnsubscribeURL: message.UnsubscribeURL,
+		Status:         string(message.Status),
+		NextAttemptAt:  message.NextAttemptAt.UTC(),
+		Attempts:       message.Attempts,
+		LastError:      message.LastError,
+		SentAt:         message.SentAt,
+		CreatedAt:      message.CreatedAt,
+	}
+}
+
+func modelToMessage(row *models.EmailMessage) *demail.Message {
+	return &demail.Message{
+		ID:             row.ID,
+		Username:       row.Username,
+		ToAddress:      row.ToAddress,
+		Kind:           demail.Kind(row.Kind),
+		DedupeKey:      row.DedupeKey,
+		Subject:        row.Subject,
+		TextBody:       row.TextBody,
+		HTMLBody:       row.HTMLBody,
+		UnsubscribeURL: row.UnsubscribeURL,
+		Status:         demail.Status(row.Status),
+		Attempts:       row.Attempts,
+		NextAttemptAt:  row.NextAttemptAt,
+		LastError:      row.LastError,
+		SentAt:         row.SentAt,
+		CreatedAt:      row.CreatedAt,
+	}
+}
@@ -0,0 +1,95 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	demail "socialpredict/internal/domain/email"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryQueueClaimAndOutcome(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 30, 9, 0, 0, 0, time.UTC)
+
+	message := &demail.Message{Username: "alice", ToAddress: "alice@example.com", Kind: demail.KindWeeklyDigest, DedupeKey: "digest:2026-W27", Subject: "Your week", TextBody: "hi", Status: demail.StatusPending, NextAttemptAt: now, CreatedAt: now}
+	if inserted, err := repo.EnqueueMessage(ctx, message); err != nil || !inserted || message.ID == 0 {
+		t.Fatalf("EnqueueMessage = %v, %v", inserted, err)
+	}
+	duplicate := *message
+	duplicate.ID = 0
+	if inserted, err := repo.EnqueueMessage(ctx, &duplicate); err != nil || inserted {
+		t.Fatalf("duplicate dedupe key should be skipped, got %v, %v", inserted, err)
+	}
+
+	claimed, err := repo.ClaimDueMessages(ctx, now, time.Minute, 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDueMessages = %d, %v", len(claimed), err)
+	}
+	if again, err := repo.ClaimDueMessages(ctx, now, time.Minute, 10); err != nil || len(again) != 0 {
+		t.Fatalf("leased message should not be claimed twice, got %d, %v", len(again), err)
+	}
+
+	sentAt := now.Add(time.Second)
+	claimed[0].Status = demail.StatusSent
+	claimed[0].Attempts = 1
+	claimed[0].SentAt = &sentAt
+	if err := repo.SaveMessageOutcome(ctx, claimed[0]); err != nil {
+		t.Fatalf("SaveMessageOutcome returned error: %v", err)
+	}
+	if later, err := repo.ClaimDueMessages(ctx, now.Add(time.Hour), time.Minute, 10); err != nil || len(later) != 0 {
+		t.Fatalf("sent message should not be claimed, got %d, %v", len(later), err)
+	}
+}
+
+func TestGormRepositoryPreferencesTokensAndDigestRuns(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 30, 9, 0, 0, 0, time.UTC)
+
+	if err := repo.SavePreferences(ctx, "bob", map[demail.Kind]bool{demail.KindWeeklyDigest: true}, now); err != nil {
+		t.Fatalf("SavePreferences returned error: %v", err)
+	}
+	if err := repo.SavePreferences(ctx, "alice", map[demail.Kind]bool{demail.KindWeeklyDigest: true, demail.KindMarketResolved: false}, now); err != nil {
+		t.Fatalf("SavePreferences returned error: %v", err)
+	}
+	if err := repo.SavePreferences(ctx, "bob", map[demail.Kind]bool{demail.KindWeeklyDigest: false}, now); err != nil {
+		t.Fatalf("SavePreferences update returned error: %v", err)
+	}
+	optedIn, err := repo.ListOptedIn(ctx, demail.KindWeeklyDigest)
+	if err != nil || len(optedIn) != 1 || optedIn[0] != "alice" {
+		t.Fatalf("ListOptedIn = %v, %v", optedIn, err)
+	}
+
+	token, err := repo.EnsureUnsubscribeToken(ctx, "alice", "first")
+	if err != nil || token != "first" {
+		t.Fatalf("EnsureUnsubscribeToken = %q, %v", token, err)
+	}
+	if token, err := repo.EnsureUnsubscribeToken(ctx, "alice", "second"); err != nil || token != "first" {
+		t.Fatalf("existing token should be kept, got %q, %v", token, err)
+	}
+	if username, err := repo.UsernameForToken(ctx, "first"); err != nil || username != "alice" {
+		t.Fatalf("UsernameForToken = %q, %v", username, err)
+	}
+	if _, err := repo.UsernameForToken(ctx, "second"); !errors.Is(err, demail.ErrInvalidToken) {
+		t.Fatalf("unknown token error = %v", err)
+	}
+
+	if run, err := repo.LatestDigestRun(ctx, "alice"); err != nil || run != nil {
+		t.Fatalf("LatestDigestRun before any run = %+v, %v", run, err)
+	}
+	for i, week := range []string{"2026-W26", "2026-W27", "2026-W27"} {
+		if err := repo.RecordDigestRun(ctx, &demail.DigestRun{Username: "alice", Week: week, Equity: int64(100 * (i + 1)), CreatedAt: now.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("RecordDigestRun(%s) returned error: %v", week, err)
+		}
+	}
+	run, err := repo.LatestDigestRun(ctx, "alice")
+	if err != nil || run == nil || run.Week != "2026-W27" || run.Equity != 200 {
+		t.Fatalf("LatestDigestRun = %+v, %v", run, err)
+	}
+}
@@ -0,0 +1,263 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// GormRepository persists domain events in the transactional outbox.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ devents.Recorder = (*GormRepository)(nil)
+var _ devents.OutboxStore = (*GormRepository)(nil)
+var _ devents.Log = (*GormRepository)(nil)
+
+// NewGormRepository creates an outbox repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// RecordEvents appends events outside any caller transaction. Prefer the
+// tx-scoped recorders on the market and bet repositories for domain writes.
+func (r *GormRepository) RecordEvents(ctx context.Context, events ...devents.Event) error {
+	return Append(ctx, r.db, events...)
+}
+
+// Append writes events to the outbox through db, which may be a transaction
+// handle; the rows then commit or roll back with the caller's change.
+func Append(ctx context.Context, db *gorm.DB, events ...devents.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	rows := make([]models.OutboxEvent, 0, len(events))
+	for _, event := range events {
+		payload, err := encodeData(event.Data)
+		if err != nil {
+			return err
+		}
+		occurredAt := event.OccurredAt
+		if occurredAt.IsZero() {
+			occurredAt = now
+		}
+		rows = append(rows, models.OutboxEvent{
+			EventType:     string(event.Type),
+			MarketID:      event.MarketID,
+			MarketGroupID: event.MarketGroupID,
+			Username:      event.Username,
+			Payload:       payload,
+			OccurredAt:    occurredAt.UTC(),
+			Status:        models.OutboxStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+	return db.WithContext(ctx).Create(&rows).Error
+}
+
+// ClaimDueEvents leases due pending events oldest first. Each row is claimed
+// with a conditional update so concurrent dispatchers never share a lease.
+// A row whose payload cannot be decoded is parked as dead with the decode
+// error rather than failing the rest of the batch.
+func (r *GormRepository) ClaimDueEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]devents.PendingEvent, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	now = now.UTC()
+	var candidates []models.OutboxEvent
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.OutboxStatusPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make([]devents.PendingEvent, 0, len(candidates))
+	for _, row := range candidates {
+		result := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", row.ID, models.OutboxStatusPending, now).
+			Update("next_attempt_at", now.Add(lease))
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		event, err := eventFromModel(row)
+		if err != nil {
+			if err := r.MarkEventDead(ctx, row.ID, row.Attempts+1, "decode payload: "+err.Error()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		pending, err := r.pendingEvent(ctx, row, event)
+		if err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, pending)
+	}
+	return claimed, nil
+}
+
+// MarkSubscriberDelivered records that subscriber handled eventID. Recording
+// the same delivery twice is harmless.
+func (r *GormRepository) MarkSubscriberDelivered(ctx context.Context, eventID int64, subscriber string, at time.Time) error {
+	var existing int64
+	if err := r.db.WithContext(ctx).Model(&models.OutboxDelivery{}).
+		Where("event_id = ? AND subscriber = ?", eventID, subscriber).
+		Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&models.OutboxDelivery{
+		EventID:     eventID,
+		Subscriber:  subscriber,
+		DeliveredAt: at.UTC(),
+	}).Error
+}
+
+// MarkEventDelivered settles an event once every subscriber has handled it.
+func (r *GormRepository) MarkEventDelivered(ctx context.Context, eventID int64, attempts int, at time.Time) error {
+	deliveredAt := at.UTC()
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]any{
+			"status":       models.OutboxStatusDelivered,
+			"attempts":     attempts,
+			"last_error":   "",
+			"delivered_at": &deliveredAt,
+		}).Error
+}
+
+// ScheduleEventRetry releases the lease and schedules the next attempt.
+func (r *GormRepository) ScheduleEventRetry(ctx context.Context, eventID int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]any{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt.UTC(),
+			"last_error":      lastError,
+		}).Error
+}
+
+// MarkEventDead parks an event that exhausted its retries.
+func (r *GormRepository) MarkEventDead(ctx context.Context, eventID int64, attempts int, lastError string) error {
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]any{
+			"status":     models.OutboxStatusDead,
+			"attempts":   attempts,
+			"last_error": lastError,
+		}).Error
+}
+
+// LatestEventID returns the highest recorded event ID, or zero when the
+// outbox is empty.
+func (r *GormRepository) LatestEventID(ctx context.Context) (int64, error) {
+	var latest int64
+	if err := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&latest).Error; err != nil {
+		return 0, err
+	}
+	return latest, nil
+}
+
+// ListEventsAfter returns up to limit events with an ID above afterID in ID
+// order, whatever their delivery status.
+func (r *GormRepository) ListEventsAfter(ctx context.Context, afterID int64, filter devents.Filter, limit int) ([]devents.Event, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	query := r.db.WithContext(ctx).Where("id > ?", afterID)
+	if filter.MarketID > 0 {
+		query = query.Where("market_id = ?", filter.MarketID)
+	}
+	if filter.MarketGroupID > 0 {
+		members := r.db.Model(&models.MarketGroupMember{}).Select("market_id").Where("group_id = ?", filter.MarketGroupID)
+		query = query.Where("market_group_id = ? OR market_id IN (?)", filter.MarketGroupID, members)
+	}
+	if len(filter.Types) > 0 {
+		types := make([]string, 0, len(filter.Types))
+		for _, eventType := range filter.Types {
+			types = append(types, string(eventType))
+		}
+		query = query.Where("event_type IN ?", types)
+	}
+	var rows []models.OutboxEvent
+	if err := query.Order("id ASC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	events := make([]devents.Event, 0, len(rows))
+	for _, row := range rows {
+		event, err := eventFromModel(row)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *GormRepository) pendingEvent(ctx context.Context, row models.OutboxEvent, event devents.Event) (devents.PendingEvent, error) {
+	var delivered []string
+	if err := r.db.WithContext(ctx).Model(&models.OutboxDelivery{}).
+		Where("event_id = ?", row.ID).
+		Order("id ASC").
+		Pluck("subscriber", &delivered).Error; err != nil {
+		return devents.PendingEvent{}, err
+	}
+	return devents.PendingEvent{
+		Event:     event,
+		Attempts:  row.Attempts,
+		Delivered: delivered,
+	}, nil
+}
+
+func eventFromModel(row models.OutboxEvent) (devents.Event, error) {
+	data, err := decodeData(row.Payload)
+	if err != nil {
+		return devents.Event{}, err
+	}
+	return devents.Event{
+		ID:            row.ID,
+		Type:          devents.Type(row.EventType),
+		MarketID:      row.MarketID,
+		MarketGroupID: row.MarketGroupID,
+		Username:      row.Username,
+		Data:          data,
+		OccurredAt:    row.OccurredAt,
+	}, nil
+}
+
+func encodeData(data map[string]any) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func decodeData(payload string) (map[string]any, error) {
+	if payload == "" {
+		return nil, nil
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
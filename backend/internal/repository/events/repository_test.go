@@ -0,0 +1,194 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryClaimsLeasesAndSettlesEvents(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	occurredAt := time.Date(2026, 6, 27, 9, 0, 0, 0, time.UTC)
+
+	if err := repo.RecordEvents(ctx,
+		devents.Event{Type: devents.BetPlaced, MarketID: 7, Username: "alice", Data: map[string]any{"amount": 10}, OccurredAt: occurredAt},
+		devents.Event{Type: devents.TagCatalogChanged, Username: "admin"},
+	); err != nil {
+		t.Fatalf("RecordEvents returned error: %v", err)
+	}
+
+	now := time.Now().UTC().Add(time.Second)
+	claimed, err := repo.ClaimDueEvents(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueEvents returned error: %v", err)
+	}
+	if len(claimed) != 2 {
+		t.Fatalf("claimed %d events, want 2", len(claimed))
+	}
+	first := claimed[0]
+	if first.Type != devents.BetPlaced || first.MarketID != 7 || first.Username != "alice" || !first.OccurredAt.Equal(occurredAt) {
+		t.Fatalf("unexpected first event: %+v", first.Event)
+	}
+	if amount, ok := first.Data["amount"].(float64); !ok || amount != 10 {
+		t.Fatalf("unexpected payload: %+v", first.Data)
+	}
+
+	again, err := repo.ClaimDueEvents(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("second ClaimDueEvents returned error: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("leased events were claimed again: %+v", again)
+	}
+
+	if err := repo.MarkSubscriberDelivered(ctx, first.ID, "read_models", now); err != nil {
+		t.Fatalf("MarkSubscriberDelivered returned error: %v", err)
+	}
+	if err := repo.MarkSubscriberDelivered(ctx, first.ID, "read_models", now); err != nil {
+		t.Fatalf("repeated MarkSubscriberDelivered returned error: %v", err)
+	}
+	if err := repo.ScheduleEventRetry(ctx, first.ID, 1, now, "webhooks: timeout"); err != nil {
+		t.Fatalf("ScheduleEventRetry returned error: %v", err)
+	}
+	if err := repo.MarkEventDead(ctx, claimed[1].ID, 10, "boom"); err != nil {
+		t.Fatalf("MarkEventDead returned error: %v", err)
+	}
+
+	retried, err := repo.ClaimDueEvents(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("retry ClaimDueEvents returned error: %v", err)
+	}
+	if len(retried) != 1 || retried[0].ID != first.ID || retried[0].Attempts != 1 {
+		t.Fatalf("unexpected retried events: %+v", retried)
+	}
+	if !retried[0].DeliveredTo("read_models") || retried[0].DeliveredTo("webhooks") {
+		t.Fatalf("unexpected delivery ledger: %+v", retried[0].Delivered)
+	}
+
+	if err := repo.MarkEventDelivered(ctx, first.ID, 2, now); err != nil {
+		t.Fatalf("MarkEventDelivered returned error: %v", err)
+	}
+	var rows []models.OutboxEvent
+	if err := db.Order("id ASC").Find(&rows).Error; err != nil {
+		t.Fatalf("load outbox rows: %v", err)
+	}
+	if rows[0].Status != models.OutboxStatusDelivered || rows[0].Attempts != 2 || rows[0].LastError != "" || rows[0].DeliveredAt == nil {
+		t.Fatalf("unexpected delivered row: %+v", rows[0])
+	}
+	if rows[1].Status != models.OutboxStatusDead || rows[1].LastError != "boom" {
+		t.Fatalf("unexpected dead row: %+v", rows[1])
+	}
+	var deliveries int64
+	if err := db.Model(&models.OutboxDelivery{}).Count(&deliveries).Error; err != nil {
+		t.Fatalf("count deliveries: %v", err)
+	}
+	if deliveries != 1 {
+		t.Fatalf("deliveries = %d, want 1", deliveries)
+	}
+}
+
+func TestClaimDueEventsDeadLettersUndecodablePayloads(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+
+	if err := repo.RecordEvents(ctx,
+		devents.Event{Type: devents.BetPlaced, MarketID: 7, Username: "alice"},
+		devents.Event{Type: devents.SharesSold, MarketID: 7, Username: "bob"},
+	); err != nil {
+		t.Fatalf("RecordEvents returned error: %v", err)
+	}
+	var rows []models.OutboxEvent
+	if err := db.Order("id ASC").Find(&rows).Error; err != nil {
+		t.Fatalf("load outbox rows: %v", err)
+	}
+	if err := db.Model(&models.OutboxEvent{}).Where("id = ?", rows[0].ID).Update("payload", "{not json").Error; err != nil {
+		t.Fatalf("corrupt payload: %v", err)
+	}
+
+	claimed, err := repo.ClaimDueEvents(ctx, time.Now().UTC().Add(time.Second), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueEvents returned error: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != rows[1].ID || claimed[0].Type != devents.SharesSold {
+		t.Fatalf("claimed = %+v, want only the decodable event", claimed)
+	}
+
+	var dead models.OutboxEvent
+	if err := db.First(&dead, rows[0].ID).Error; err != nil {
+		t.Fatalf("reload corrupt row: %v", err)
+	}
+	if dead.Status != models.OutboxStatusDead || dead.Attempts != 1 || !strings.Contains(dead.LastError, "decode payload") {
+		t.Fatalf("unexpected dead-lettered row: %+v", dead)
+	}
+}
+
+func TestAppendJoinsCallerTransaction(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	ctx := context.Background()
+
+	tx := db.Begin()
+	if err := Append(ctx, tx, devents.Event{Type: devents.MarketResolved, MarketID: 3}); err != nil {
+		t.Fatalf("Append returned error: %v", err)
+	}
+	if err := tx.Rollback().Error; err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.OutboxEvent{}).Count(&count).Error; err != nil {
+		t.Fatalf("count outbox rows: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("rolled back events were kept: %d", count)
+	}
+}
+
+func TestListEventsAfterFiltersByMarketAndGroup(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+
+	if latest, err := repo.LatestEventID(ctx); err != nil || latest != 0 {
+		t.Fatalf("LatestEventID on empty outbox = %d, %v", latest, err)
+	}
+	if err := db.Create(&models.MarketGroupMember{GroupID: 4, MarketID: 11, AnswerLabel: "A"}).Error; err != nil {
+		t.Fatalf("create group member: %v", err)
+	}
+	if err := repo.RecordEvents(ctx,
+		devents.Event{Type: devents.BetPlaced, MarketID: 7, Username: "alice"},
+		devents.Event{Type: devents.BetPlaced, MarketID: 11, Username: "bob"},
+		devents.Event{Type: devents.TagCatalogChanged, Username: "admin"},
+		devents.Event{Type: devents.MarketGroupResolved, MarketGroupID: 4, Username: "admin"},
+		devents.Event{Type: devents.MarketResolved, MarketID: 7, Username: "admin"},
+	); err != nil {
+		t.Fatalf("RecordEvents returned error: %v", err)
+	}
+	latest, err := repo.LatestEventID(ctx)
+	if err != nil {
+		t.Fatalf("LatestEventID returned error: %v", err)
+	}
+
+	market, err := repo.ListEventsAfter(ctx, 0, devents.Filter{MarketID: 7}, 10)
+	if err != nil || len(market) != 2 || market[0].Type != devents.BetPlaced || market[1].ID != latest {
+		t.Fatalf("market events = %+v, %v", market, err)
+	}
+	resumed, err := repo.ListEventsAfter(ctx, market[0].ID, devents.Filter{MarketID: 7, Types: []devents.Type{devents.MarketResolved}}, 10)
+	if err != nil || len(resumed) != 1 || resumed[0].Type != devents.MarketResolved {
+		t.Fatalf("resumed events = %+v, %v", resumed, err)
+	}
+	group, err := repo.ListEventsAfter(ctx, 0, devents.Filter{MarketGroupID: 4}, 10)
+	if err != nil || len(group) != 2 || group[0].Username != "bob" || group[1].Type != devents.MarketGroupResolved {
+		t.Fatalf("group events = %+v, %v", group, err)
+	}
+	if limited, _ := repo.ListEventsAfter(ctx, 0, devents.Filter{}, 3); len(limited) != 3 {
+		t.Fatalf("limit ignored: %d events", len(limited))
+	}
+}
@@ -0,0 +1,207 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	dfeed "socialpredict/internal/domain/feed"
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// visibleLifecycles are the market lifecycle states whose activity anyone
+// may see; proposals and rejected or cancelled markets stay private.
+var visibleLifecycles = []string{
+	dmarkets.MarketLifecyclePublished,
+	dmarkets.MarketLifecycleClosed,
+	dmarkets.MarketLifecycleResolved,
+}
+
+// standaloneMarkets leaves out market group answers, whose publication and
+// resolution belong to the group rather than to each answer.
+const standaloneMarkets = "markets.id NOT IN (SELECT market_id FROM market_group_members WHERE deleted_at IS NULL)"
+
+// namedTrades leaves out trades by users who trade anonymously; a feed item
+// is about its trader, so masking the name would leave nothing to show.
+const namedTrades = "bets.username NOT IN (SELECT username FROM trading_privacy_settings WHERE anonymous_trades = ?)"
+
+// GormRepository implements the feed domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dfeed.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based feed repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// AddFollow inserts the follow unless it already exists and fills in the
+// stored ID and creation time either way.
+func (r *GormRepository) AddFollow(ctx context.Context, follow *dfeed.Follow) (bool, error) {
+	row := models.UserFollow{
+		FollowerUsername: follow.Follower,
+		FolloweeUsername: follow.Followee,
+		CreatedAt:        follow.CreatedAt,
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "follower_username"}, {Name: "followee_username"}}, DoNothing: true}).
+		Create(&row)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		var existing models.UserFollow
+		if err := r.db.WithContext(ctx).
+			Where("follower_username = ? AND followee_username = ?", follow.Follower, follow.Followee).
+			First(&existing).Error; err != nil {
+			return false, err
+		}
+		follow.ID = existing.ID
+		follow.CreatedAt = existing.CreatedAt
+		return false, nil
+	}
+	follow.ID = row.ID
+	return true, nil
+}
+
+// RemoveFollow deletes the follow if it exists.
+func (r *GormRepository) RemoveFollow(ctx context.Context, follower, followee string) error {
+	return r.db.WithContext(ctx).
+		Where("follower_username = ? AND followee_username = ?", follower, followee).
+		Delete(&models.UserFollow{}).Error
+}
+
+// ListFollowing returns who follower follows, most recent first.
+func (r *GormRepository) ListFollowing(ctx context.Context, follower string) ([]*dfeed.Follow, error) {
+	var rows []models.UserFollow
+	if err := r.db.WithContext(ctx).
+		Where("follower_username = ?", follower).
+		Order("created_at DESC, id DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	follows := make([]*dfeed.Follow, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		follows = append(follows, &dfeed.Follow{
+			ID:        row.ID,
+			Follower:  row.FollowerUsername,
+			Followee:  row.FolloweeUsername,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return follows, nil
+}
+
+// CountFollowing returns how many people follower follows.
+func (r *GormRepository) CountFollowing(ctx context.Context, follower string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.UserFollow{}).Where("follower_username = ?", follower).Count(&count).Error
+	return count, err
+}
+
+// CountFollowers returns how many users follow followee.
+func (r *GormRepository) CountFollowers(ctx context.Context, followee string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.UserFollow{}).Where("followee_username = ?", followee).Count(&count).Error
+	return count, err
+}
+
+type activityRow struct {
+	ID            int64
+	Username      string
+	MarketID      int64
+	QuestionTitle string
+	Amount        int64
+	Outcome       string
+	Resolution    string
+	ApprovedAt    *time.Time
+	OccurredAt    time.Time
+}
+
+// ListActivity reads one kind of activity straight from bets and markets,
+// newest first.
+func (r *GormRepository) ListActivity(ctx context.Context, query dfeed.Query) ([]dfeed.Item, error) {
+	if query.Usernames != nil && len(query.Usernames) == 0 {
+		return []dfeed.Item{}, nil
+	}
+	var (
+		db         = r.db.WithContext(ctx)
+		atColumn   string
+		idColumn   string
+		userColumn string
+	)
+	switch query.Kind {
+	case dfeed.KindTrade:
+		atColumn, idColumn, userColumn = "bets.placed_at", "bets.id", "bets.username"
+		db = db.Table("bets").
+			Select("bets.id, bets.username, bets.market_id, markets.question_title, bets.amount, bets.outcome, bets.placed_at AS occurred_at").
+			Joins("JOIN markets ON markets.id = bets.market_id AND markets.deleted_at IS NULL").
+			Where("bets.deleted_at IS NULL").
+			Where(namedTrades, true)
+	case dfeed.KindMarketPublished:
+		atColumn, idColumn, userColumn = "COALESCE(markets.approved_at, markets.created_at)", "markets.id", "markets.creator_username"
+		db = db.Table("markets").
+			Select("markets.id, markets.creator_username AS username, markets.id AS market_id, markets.question_title, markets.approved_at, markets.created_at AS occurred_at").
+			Where("markets.deleted_at IS NULL").
+			Where(standaloneMarkets)
+	case dfeed.KindMarketResolved:
+		atColumn, idColumn, userColumn = "markets.final_resolution_date_time", "markets.id", "markets.creator_username"
+		db = db.Table("markets").
+			Select("markets.id, markets.creator_username AS username, markets.id AS market_id, markets.question_title, markets.resolution_result AS resolution, markets.final_resolution_date_time AS occurred_at").
+			Where("markets.deleted_at IS NULL AND markets.is_resolved = ?", true).
+			Where(standaloneMarkets)
+	default:
+		return nil, dfeed.ErrInvalidInput
+	}
+	db = db.Where("markets.lifecycle_status IN ?", visibleLifecycles)
+	if query.Usernames != nil {
+		db = db.Where(userColumn+" IN ?", query.Usernames)
+	}
+	if after := query.After; after != nil {
+		switch rank := query.Kind.Rank(); {
+		case rank < after.Kind.Rank():
+			db = db.Where(atColumn+" <= ?", after.At)
+		case rank > after.Kind.Rank():
+			db = db.Where(atColumn+" < ?", after.At)
+		default:
+			db = db.Where("("+atColumn+" < ? OR ("+atColumn+" = ? AND "+idColumn+" < ?))", after.At, after.At, after.ID)
+		}
+	}
+
+	var rows []activityRow
+	if err := db.Order(atColumn + " DESC, " + idColumn + " DESC").Limit(query.Limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	items := make([]dfeed.Item, 0, len(rows))
+	for _, row := range rows {
+		item := dfeed.Item{
+			Kind:        query.Kind,
+			ID:          row.ID,
+			Username:    row.Username,
+			MarketID:    row.MarketID,
+			MarketTitle: row.QuestionTitle,
+			Resolution:  row.Resolution,
+			OccurredAt:  row.OccurredAt,
+		}
+		if query.Kind == dfeed.KindMarketPublished && row.ApprovedAt != nil {
+			// Reviewed markets go live when approved, not when proposed.
+			item.OccurredAt = *row.ApprovedAt
+		}
+		if query.Kind == dfeed.KindTrade {
+			item.Outcome = row.Outcome
+			item.Action, item.Amount = dfeed.ActionBuy, row.Amount
+			if row.Amount < 0 {
+				// Sales are stored as negative share counts.
+				item.Action, item.Amount = dfeed.ActionSell, -row.Amount
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,132 @@
+package feed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dfeed "socialpredict/internal/domain/feed"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryFollows(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 7, 4, 9, 0, 0, 0, time.UTC)
+
+	first := &dfeed.Follow{Follower: "alice", Followee: "bob", CreatedAt: now}
+	if created, err := repo.AddFollow(ctx, first); err != nil || !created || first.ID == 0 {
+		t.Fatalf("AddFollow = %v, %v (id %d)", created, err, first.ID)
+	}
+	again := &dfeed.Follow{Follower: "alice", Followee: "bob", CreatedAt: now.Add(time.Hour)}
+	if created, err := repo.AddFollow(ctx, again); err != nil || created || again.ID != first.ID || !again.CreatedAt.Equal(now) {
+		t.Fatalf("expected the existing follow back, got %+v created=%v err=%v", again, created, err)
+	}
+	if _, err := repo.AddFollow(ctx, &dfeed.Follow{Follower: "alice", Followee: "carol", CreatedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("AddFollow returned error: %v", err)
+	}
+	if _, err := repo.AddFollow(ctx, &dfeed.Follow{Follower: "dave", Followee: "bob", CreatedAt: now}); err != nil {
+		t.Fatalf("AddFollow returned error: %v", err)
+	}
+
+	following, err := repo.ListFollowing(ctx, "alice")
+	if err != nil || len(following) != 2 || following[0].Followee != "carol" {
+		t.Fatalf("ListFollowing = %+v, %v", following, err)
+	}
+	if count, err := repo.CountFollowers(ctx, "bob"); err != nil || count != 2 {
+		t.Fatalf("CountFollowers = %d, %v", count, err)
+	}
+	if err := repo.RemoveFollow(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("RemoveFollow returned error: %v", err)
+	}
+	if count, err := repo.CountFollowing(ctx, "alice"); err != nil || count != 1 {
+		t.Fatalf("CountFollowing = %d, %v", count, err)
+	}
+}
+
+func TestGormRepositoryListActivity(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	base := time.Date(2026, 7, 4, 9, 0, 0, 0, time.UTC)
+
+	public := modelstesting.GenerateMarket(1, "bob")
+	public.QuestionTitle = "Will it rain?"
+	public.CreatedAt = base
+	approvedAt := base.Add(30 * time.Minute)
+	public.ApprovedAt = &approvedAt
+	resolved := modelstesting.GenerateMarket(2, "carol")
+	resolved.CreatedAt = base.Add(time.Minute)
+	resolved.IsResolved = true
+	resolved.ResolutionResult = "YES"
+	resolved.LifecycleStatus = "resolved"
+	resolved.FinalResolutionDateTime = base.Add(3 * time.Hour)
+	proposed := modelstesting.GenerateMarket(3, "bob")
+	proposed.LifecycleStatus = "proposed"
+	proposed.CreatedAt = base.Add(2 * time.Minute)
+	answer := modelstesting.GenerateMarket(4, "bob")
+	answer.CreatedAt = base.Add(3 * time.Minute)
+	for _, market := range []*models.Market{&public, &resolved, &proposed, &answer} {
+		if err := db.Create(market).Error; err != nil {
+			t.Fatalf("create market: %v", err)
+		}
+	}
+	if err := db.Create(&models.MarketGroupMember{GroupID: 1, MarketID: 4, AnswerLabel: "A"}).Error; err != nil {
+		t.Fatalf("create member: %v", err)
+	}
+	for i, bet := range []models.Bet{
+		{Username: "bob", MarketID: 1, Amount: 20, Outcome: "YES", PlacedAt: base.Add(time.Hour)},
+		{Username: "bob", MarketID: 1, Amount: -5, Outcome: "YES", PlacedAt: base.Add(2 * time.Hour)},
+		{Username: "carol", MarketID: 2, Amount: 10, Outcome: "NO", PlacedAt: base.Add(2 * time.Hour)},
+	} {
+		bet := bet
+		if err := db.Create(&bet).Error; err != nil {
+			t.Fatalf("create bet %d: %v", i, err)
+		}
+	}
+
+	trades, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindTrade, Usernames: []string{"bob"}, Limit: 10})
+	if err != nil || len(trades) != 2 {
+		t.Fatalf("trades = %+v, %v", trades, err)
+	}
+	if sale := trades[0]; sale.Action != dfeed.ActionSell || sale.Amount != 5 || sale.MarketTitle != "Will it rain?" || !sale.OccurredAt.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("unexpected sale %+v", sale)
+	}
+
+	published, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindMarketPublished, Limit: 10})
+	if err != nil || len(published) != 2 || published[0].MarketID != 1 || published[0].Username != "bob" || !published[0].OccurredAt.Equal(approvedAt) {
+		t.Fatalf("expected only the two public standalone markets, got %+v, %v", published, err)
+	}
+	resolutions, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindMarketResolved, Limit: 10})
+	if err != nil || len(resolutions) != 1 || resolutions[0].Resolution != "YES" || resolutions[0].Username != "carol" {
+		t.Fatalf("resolutions = %+v, %v", resolutions, err)
+	}
+
+	// Carol's trade shares the sale's timestamp but has a higher ID, so it
+	// sorts before the sale and is not repeated after it.
+	after := dfeed.CursorFor(trades[0])
+	rest, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindTrade, After: &after, Limit: 10})
+	if err != nil || len(rest) != 1 || rest[0].Action != dfeed.ActionBuy {
+		t.Fatalf("after cursor = %+v, %v", rest, err)
+	}
+	resolvedCursor := dfeed.CursorFor(resolutions[0])
+	sameInstant := dfeed.Cursor{At: base.Add(2 * time.Hour), Kind: dfeed.KindMarketResolved, ID: 1}
+	if tied, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindTrade, After: &sameInstant, Limit: 10}); err != nil || len(tied) != 3 {
+		t.Fatalf("trades at the cursor instant sort after a resolution, got %+v, %v", tied, err)
+	}
+	if none, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindMarketResolved, After: &resolvedCursor, Limit: 10}); err != nil || len(none) != 0 {
+		t.Fatalf("expected nothing after the only resolution, got %+v, %v", none, err)
+	}
+	if none, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindTrade, Usernames: []string{}, Limit: 10}); err != nil || len(none) != 0 {
+		t.Fatalf("expected no activity for an empty user list, got %+v, %v", none, err)
+	}
+
+	if err := db.Create(&models.TradingPrivacySetting{Username: "carol", AnonymousTrades: true, UpdatedAt: base}).Error; err != nil {
+		t.Fatalf("create privacy setting: %v", err)
+	}
+	if named, err := repo.ListActivity(ctx, dfeed.Query{Kind: dfeed.KindTrade, Limit: 10}); err != nil || len(named) != 2 || named[0].Username != "bob" {
+		t.Fatalf("expected carol's anonymous trade left out, got %+v, %v", named, err)
+	}
+}
@@ -0,0 +1,16 @@
+package markets
+
+import (
+	"context"
+
+	devents "socialpredict/internal/domain/events"
+	revents "socialpredict/internal/repository/events"
+)
+
+var _ devents.Recorder = (*GormRepository)(nil)
+
+// RecordEvents appends domain events to the outbox on this repository's
+// handle, so inside GroupedMarketTransaction they commit with the group write.
+func (r *GormRepository) RecordEvents(ctx context.Context, events ...devents.Event) error {
+	return revents.Append(ctx, r.db, events...)
+}
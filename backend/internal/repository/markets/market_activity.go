@@ -0,0 +1,44 @@
+package markets
+
+import (
+	"context"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/models"
+)
+
+var _ dmarkets.MarketActivityRepository = (*GormRepository)(nil)
+
+// ListMostTradedMarkets ranks open public markets by trades placed since the
+// given time. Volume counts buys and sales alike.
+func (r *GormRepository) ListMostTradedMarkets(ctx context.Context, since time.Time, limit int) ([]dmarkets.MarketActivity, error) {
+	var rows []struct {
+		MarketID      int64
+		QuestionTitle string
+		Trades        int64
+		Volume        int64
+	}
+	query := r.db.WithContext(ctx).Model(&models.Market{}).
+		Select("markets.id AS market_id, markets.question_title, COUNT(bets.id) AS trades, COALESCE(SUM(ABS(bets.amount)), 0) AS volume").
+		Joins("JOIN bets ON bets.market_id = markets.id AND bets.deleted_at IS NULL").
+		Where("bets.placed_at >= ?", since.UTC())
+	query = applyStatusByResolution(query, dmarkets.MarketStatusActive, time.Now())
+	if err := query.
+		Group("markets.id, markets.question_title").
+		Order("trades DESC, volume DESC, markets.id ASC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	activity := make([]dmarkets.MarketActivity, 0, len(rows))
+	for _, row := range rows {
+		activity = append(activity, dmarkets.MarketActivity{
+			MarketID:      row.MarketID,
+			QuestionTitle: row.QuestionTitle,
+			Trades:        row.Trades,
+			Volume:        row.Volume,
+		})
+	}
+	return activity, nil
+}
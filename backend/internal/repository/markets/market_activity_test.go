@@ -0,0 +1,52 @@
+package markets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryListMostTradedMarkets(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+
+	bettor := modelstesting.GenerateUser("bettor", 1000)
+	if err := db.Create(&bettor).Error; err != nil {
+		t.Fatalf("seed bettor: %v", err)
+	}
+	quiet := modelstesting.GenerateMarket(301, "creator")
+	busy := modelstesting.GenerateMarket(302, "creator")
+	resolved := modelstesting.GenerateMarket(303, "creator")
+	resolved.IsResolved = true
+	for _, market := range []*models.Market{&quiet, &busy, &resolved} {
+		if err := db.Create(market).Error; err != nil {
+			t.Fatalf("seed market %d: %v", market.ID, err)
+		}
+	}
+	bets := []models.Bet{
+		modelstesting.GenerateBet(10, "YES", "bettor", 301, -time.Hour),
+		modelstesting.GenerateBet(10, "YES", "bettor", 302, -time.Hour),
+		modelstesting.GenerateBet(-5, "YES", "bettor", 302, -30*time.Minute),
+		modelstesting.GenerateBet(50, "NO", "bettor", 301, -10*24*time.Hour),
+		modelstesting.GenerateBet(10, "YES", "bettor", 303, -time.Hour),
+		modelstesting.GenerateBet(10, "NO", "bettor", 303, -time.Hour),
+		modelstesting.GenerateBet(10, "NO", "bettor", 303, -time.Hour),
+	}
+	for i := range bets {
+		if err := db.Create(&bets[i]).Error; err != nil {
+			t.Fatalf("seed bet: %v", err)
+		}
+	}
+
+	activity, err := repo.ListMostTradedMarkets(ctx, time.Now().Add(-7*24*time.Hour), 5)
+	if err != nil {
+		t.Fatalf("ListMostTradedMarkets returned error: %v", err)
+	}
+	if len(activity) != 2 || activity[0].MarketID != 302 || activity[0].Trades != 2 || activity[0].Volume != 15 || activity[1].MarketID != 301 || activity[1].Trades != 1 {
+		t.Fatalf("unexpected activity: %+v", activity)
+	}
+}
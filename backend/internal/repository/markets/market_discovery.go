@@ -22,7 +22,7 @@ func (r *GormRepository) ListMarketDiscovery(ctx context.Context, filters dmarke
 	query = applyCreatedByFilter(query, filters.CreatedBy)
 	query = applyOwnedByFilter(query, filters.OwnedBy)
 	query = applyTagSlugFilter(query, filters.TagSlug)
-	query = query.Distinct("markets.*").Order("markets.created_at DESC")
+	query = applyListSort(query.Distinct("markets.*"), filters)
 
 	var dbMarkets []models.Market
 	if err := query.Find(&dbMarkets).Error; err != nil {
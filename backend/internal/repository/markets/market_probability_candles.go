@@ -0,0 +1,152 @@
+package markets
+
+import (
+	"context"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ dmarkets.MarketProbabilityCandleRepository = (*GormRepository)(nil)
+
+// LatestMarketProbabilityCandles returns up to limit of the newest cached
+// candles, newest first.
+func (r *GormRepository) LatestMarketProbabilityCandles(ctx context.Context, marketID int64, interval dmarkets.CandleInterval, limit int) ([]dmarkets.ProbabilityCandle, error) {
+	var rows []models.MarketProbabilityCandle
+	if err := r.db.WithContext(ctx).
+		Where("market_id = ? AND bucket_interval = ?", marketID, string(interval)).
+		Order("bucket_start DESC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return modelProbabilityCandlesToDomain(rows), nil
+}
+
+// ListMarketProbabilityCandles returns cached candles starting in [from, to),
+// oldest first.
+func (r *GormRepository) ListMarketProbabilityCandles(ctx context.Context, marketID int64, interval dmarkets.CandleInterval, from, to time.Time) ([]dmarkets.ProbabilityCandle, error) {
+	var rows []models.MarketProbabilityCandle
+	if err := r.db.WithContext(ctx).
+		Where("market_id = ? AND bucket_interval = ? AND bucket_start >= ? AND bucket_start < ?", marketID, string(interval), from.UTC(), to.UTC()).
+		Order("bucket_start ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return modelProbabilityCandlesToDomain(rows), nil
+}
+
+// ReplaceMarketProbabilityCandles swaps every cached candle starting at or
+// after from for candles in one transaction.
+func (r *GormRepository) ReplaceMarketProbabilityCandles(ctx context.Context, marketID int64, interval dmarkets.CandleInterval, from time.Time, candles []dmarkets.ProbabilityCandle) error {
+	if marketID <= 0 {
+		return dmarkets.ErrInvalidInput
+	}
+	now := time.Now().UTC()
+	rows := make([]models.MarketProbabilityCandle, 0, len(candles))
+	for _, candle := range candles {
+		rows = append(rows, models.MarketProbabilityCandle{
+			MarketID:         marketID,
+			BucketInterval:   string(interval),
+			BucketStart:      candle.BucketStart.UTC(),
+			OpenProbability:  candle.Open,
+			HighProbability:  candle.High,
+			LowProbability:   candle.Low,
+			CloseProbability: candle.Close,
+			Volume:           candle.Volume,
+			Trades:           candle.Trades,
+			Traders:          candle.Traders,
+			CumulativeTrades: candle.CumulativeTrades,
+			YesContribution:  candle.State.YesContribution,
+			NoContribution:   candle.State.NoContribution,
+			UpdatedAt:        now,
+		})
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("market_id = ? AND bucket_interval = ? AND bucket_start >= ?", marketID, string(interval), from.UTC()).
+			Delete(&models.MarketProbabilityCandle{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		// A concurrent refresh may have written the same buckets; both are
+		// computed from the same bets, so the later write simply wins.
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "market_id"}, {Name: "bucket_interval"}, {Name: "bucket_start"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"open_probability",
+				"high_probability",
+				"low_probability",
+				"close_probability",
+				"volume",
+				"trades",
+				"traders",
+				"cumulative_trades",
+				"yes_contribution",
+				"no_contribution",
+				"updated_at",
+			}),
+		}).Create(&rows).Error
+	})
+}
+
+// ListBetsForMarketSince returns bets placed at or after since in placement
+// order. A zero since returns every bet.
+func (r *GormRepository) ListBetsForMarketSince(ctx context.Context, marketID int64, since time.Time) ([]*dmarkets.Bet, error) {
+	query := r.db.WithContext(ctx).Model(&models.Bet{}).Where("market_id = ?", marketID)
+	if !since.IsZero() {
+		query = query.Where("placed_at >= ?", since.UTC())
+	}
+	var bets []models.Bet
+	if err := query.Order("placed_at ASC, id ASC").Find(&bets).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*dmarkets.Bet, len(bets))
+	for i := range bets {
+		result[i] = &dmarkets.Bet{
+			ID:        bets[i].ID,
+			Username:  bets[i].Username,
+			MarketID:  bets[i].MarketID,
+			Amount:    bets[i].Amount,
+			Outcome:   bets[i].Outcome,
+			PlacedAt:  bets[i].PlacedAt,
+			CreatedAt: bets[i].CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+// CountBetsForMarket returns how many bets the market has.
+func (r *GormRepository) CountBetsForMarket(ctx context.Context, marketID int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Bet{}).Where("market_id = ?", marketID).Count(&count).Error
+	return count, err
+}
+
+func modelProbabilityCandlesToDomain(rows []models.MarketProbabilityCandle) []dmarkets.ProbabilityCandle {
+	candles := make([]dmarkets.ProbabilityCandle, 0, len(rows))
+	for _, row := range rows {
+		candles = append(candles, dmarkets.ProbabilityCandle{
+			BucketStart:      row.BucketStart.UTC(),
+			Open:             row.OpenProbability,
+			High:             row.HighProbability,
+			Low:              row.LowProbability,
+			Close:            row.CloseProbability,
+			Volume:           row.Volume,
+			Trades:           row.Trades,
+			Traders:          row.Traders,
+			CumulativeTrades: row.CumulativeTrades,
+			State: dmarkets.ProbabilityTrackState{
+				Probability:     row.CloseProbability,
+				YesContribution: row.YesContribution,
+				NoContribution:  row.NoContribution,
+			},
+		})
+	}
+	return candles
+}
@@ -0,0 +1,126 @@
+package markets
+
+import (
+	"context"
+	"time"
+
+	dmarkets "socialpredict/internal/domain/markets"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+var _ dmarkets.MarketTrendingRepository = (*GormRepository)(nil)
+
+// ListMarketTrendingActivity returns active markets with bets or visible
+// market comments at or after since, with every bet in placement order.
+func (r *GormRepository) ListMarketTrendingActivity(ctx context.Context, since time.Time) ([]dmarkets.MarketTrendingActivity, error) {
+	since = since.UTC()
+	db := r.db.WithContext(ctx)
+
+	traded := db.Model(&models.Bet{}).Select("market_id").Where("placed_at >= ?", since)
+	commented := visibleMarketComments(db, since).Select("market_id")
+
+	query := db.Model(&models.Market{}).
+		Where("markets.id IN (?) OR markets.id IN (?)", traded, commented)
+	query = applyListStatusFilter(query, dmarkets.MarketStatusActive)
+
+	var dbMarkets []models.Market
+	if err := query.Order("markets.id ASC").Find(&dbMarkets).Error; err != nil {
+		return nil, err
+	}
+	if len(dbMarkets) == 0 {
+		return []dmarkets.MarketTrendingActivity{}, nil
+	}
+
+	markets := r.mapMarkets(dbMarkets)
+	marketIDs := make([]int64, 0, len(markets))
+	activity := make([]dmarkets.MarketTrendingActivity, 0, len(markets))
+	byID := make(map[int64]int, len(markets))
+	for _, market := range markets {
+		byID[market.ID] = len(activity)
+		marketIDs = append(marketIDs, market.ID)
+		activity = append(activity, dmarkets.MarketTrendingActivity{Market: market, Bets: []*dmarkets.Bet{}})
+	}
+
+	var bets []models.Bet
+	if err := db.Where("market_id IN ?", marketIDs).Order("placed_at ASC, id ASC").Find(&bets).Error; err != nil {
+		return nil, err
+	}
+	for i := range bets {
+		index := byID[int64(bets[i].MarketID)]
+		activity[index].Bets = append(activity[index].Bets, &dmarkets.Bet{
+			ID:        bets[i].ID,
+			Username:  bets[i].Username,
+			MarketID:  bets[i].MarketID,
+			Amount:    bets[i].Amount,
+			Outcome:   bets[i].Outcome,
+			PlacedAt:  bets[i].PlacedAt,
+			CreatedAt: bets[i].CreatedAt,
+		})
+	}
+
+	var comments []models.Comment
+	if err := visibleMarketComments(db, since).
+		Select("market_id", "created_at").
+		Where("market_id IN ?", marketIDs).
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		index := byID[comment.MarketID]
+		activity[index].Comments = append(activity[index].Comments, comment.CreatedAt)
+	}
+	return activity, nil
+}
+
+// ReplaceMarketTrendingScores swaps every stored trending score for scores
+// in one transaction.
+func (r *GormRepository) ReplaceMarketTrendingScores(ctx context.Context, scores []dmarkets.MarketTrendingScore) error {
+	rows := make([]models.MarketTrendingScore, 0, len(scores))
+	for _, score := range scores {
+		rows = append(rows, models.MarketTrendingScore{
+			MarketID:            score.MarketID,
+			Score:               score.Score,
+			Volume:              score.Volume,
+			NewTraders:          score.NewTraders,
+			ProbabilityMovement: score.ProbabilityMovement,
+			Comments:            score.Comments,
+			ComputedAt:          score.ComputedAt.UTC(),
+		})
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.MarketTrendingScore{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// visibleMarketComments selects comments on market threads posted at or
+// after since that are neither deleted nor hidden.
+func visibleMarketComments(db *gorm.DB, since time.Time) *gorm.DB {
+	return db.Model(&models.Comment{}).
+		Where("market_id > 0 AND created_at >= ? AND deleted_at IS NULL AND hidden_at IS NULL", since)
+}
+
+// applyListSort orders a market listing. Trending puts the highest stored
+// trending score first, with unscored markets after them newest first;
+// the score is selected so DISTINCT listings can order by it.
+func applyListSort(query *gorm.DB, filters dmarkets.ListFilters) *gorm.DB {
+	if filters.Sort != dmarkets.ListSortTrending && !filters.TrendingOnly {
+		return query.Order("markets.created_at DESC")
+	}
+	join := "LEFT JOIN market_trending_scores ON market_trending_scores.market_id = markets.id"
+	if filters.TrendingOnly {
+		join = "JOIN market_trending_scores ON market_trending_scores.market_id = markets.id AND market_trending_scores.score > 0"
+	}
+	return query.
+		Joins(join).
+		Distinct("markets.*", "COALESCE(market_trending_scores.score, 0) AS trending_score").
+		Order("trending_score DESC").
+		Order("markets.created_at DESC")
+}
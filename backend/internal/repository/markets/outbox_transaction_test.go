@@ -0,0 +1,163 @@
+package markets_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/internal/app"
+	dbets "socialpredict/internal/domain/bets"
+	devents "socialpredict/internal/domain/events"
+	dmarkets "socialpredict/internal/domain/markets"
+	configsvc "socialpredict/internal/service/config"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+
+	"gorm.io/gorm"
+)
+
+func TestResolveMarketRollsBackWhenOutboxWriteFails(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econConfig, _ := modelstesting.UseStandardTestEconomics(t)
+	ctx := context.Background()
+
+	steward := groupedTransactionModerator("outbox_steward", 0)
+	bettor := modelstesting.GenerateUser("outbox_bettor", 0)
+	for _, user := range []*models.User{&steward, &bettor} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("seed user %s: %v", user.Username, err)
+		}
+	}
+	market := modelstesting.GenerateMarket(7301, steward.Username)
+	market.IsResolved = false
+	market.StewardUsername = steward.Username
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("seed market: %v", err)
+	}
+
+	container := app.BuildApplicationWithConfigService(db, configsvc.NewStaticService(econConfig))
+	if _, err := container.GetBetsService().Place(ctx, dbets.PlaceRequest{Username: bettor.Username, MarketID: uint(market.ID), Amount: 20, Outcome: "YES"}); err != nil {
+		t.Fatalf("place bet: %v", err)
+	}
+	balances := map[string]int64{}
+	for _, username := range []string{steward.Username, bettor.Username} {
+		var user models.User
+		if err := db.Where("username = ?", username).First(&user).Error; err != nil {
+			t.Fatalf("load %s: %v", username, err)
+		}
+		balances[username] = user.AccountBalance
+	}
+
+	outboxErr := errors.New("forced outbox failure")
+	registerOutboxEventFailure(t, db, "outbox_fail_market_resolved", devents.MarketResolved, outboxErr)
+
+	if err := container.GetMarketsService().ResolveMarket(ctx, market.ID, "YES", steward.Username); !errors.Is(err, outboxErr) {
+		t.Fatalf("ResolveMarket error = %v, want forced outbox error", err)
+	}
+
+	var reloaded models.Market
+	if err := db.First(&reloaded, market.ID).Error; err != nil {
+		t.Fatalf("reload market: %v", err)
+	}
+	if reloaded.IsResolved || reloaded.ResolutionResult != "" {
+		t.Fatalf("market should stay unresolved after rollback: %+v", reloaded)
+	}
+	for username, want := range balances {
+		assertGroupedTransactionUserBalance(t, db, username, want)
+	}
+	var resolvedEvents int64
+	if err := db.Model(&models.OutboxEvent{}).Where("event_type = ?", string(devents.MarketResolved)).Count(&resolvedEvents).Error; err != nil {
+		t.Fatalf("count outbox events: %v", err)
+	}
+	if resolvedEvents != 0 {
+		t.Fatalf("resolved events = %d, want 0", resolvedEvents)
+	}
+}
+
+func TestCreateMarketRollsBackWhenOutboxWriteFails(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econConfig, _ := modelstesting.UseStandardTestEconomics(t)
+
+	creator := groupedTransactionModerator("outbox_creator", 1000)
+	if err := db.Create(&creator).Error; err != nil {
+		t.Fatalf("seed creator: %v", err)
+	}
+
+	outboxErr := errors.New("forced outbox failure")
+	registerOutboxEventFailure(t, db, "outbox_fail_market_created", devents.MarketCreated, outboxErr)
+
+	container := app.BuildApplicationWithConfigService(db, configsvc.NewStaticService(econConfig))
+	_, err := container.GetMarketsService().CreateMarket(context.Background(), dmarkets.MarketCreateRequest{
+		QuestionTitle:      "Outbox rollback market",
+		Description:        "Outbox rollback market",
+		OutcomeType:        "BINARY",
+		ResolutionDateTime: time.Now().UTC().Add(48 * time.Hour),
+	}, creator.Username)
+	if !errors.Is(err, outboxErr) {
+		t.Fatalf("CreateMarket error = %v, want forced outbox error", err)
+	}
+
+	assertGroupedTransactionMarketCount(t, db, "Outbox rollback market", 0)
+	assertGroupedTransactionUserBalance(t, db, creator.Username, 1000)
+}
+
+func TestReassignMarketStewardRollsBackWhenOutboxWriteFails(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	econConfig, _ := modelstesting.UseStandardTestEconomics(t)
+
+	steward := groupedTransactionModerator("outbox_old_steward", 0)
+	successor := groupedTransactionModerator("outbox_new_steward", 0)
+	for _, user := range []*models.User{&steward, &successor} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("seed user %s: %v", user.Username, err)
+		}
+	}
+	market := modelstesting.GenerateMarket(7302, steward.Username)
+	market.IsResolved = false
+	market.StewardUsername = steward.Username
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("seed market: %v", err)
+	}
+
+	outboxErr := errors.New("forced outbox failure")
+	registerOutboxEventFailure(t, db, "outbox_fail_steward_changed", devents.MarketStewardChanged, outboxErr)
+
+	container := app.BuildApplicationWithConfigService(db, configsvc.NewStaticService(econConfig))
+	if _, err := container.GetMarketsService().ReassignMarketSteward(context.Background(), market.ID, successor.Username, "admin", "handover"); !errors.Is(err, outboxErr) {
+		t.Fatalf("ReassignMarketSteward error = %v, want forced outbox error", err)
+	}
+
+	var reloaded models.Market
+	if err := db.First(&reloaded, market.ID).Error; err != nil {
+		t.Fatalf("reload market: %v", err)
+	}
+	if reloaded.StewardUsername != steward.Username {
+		t.Fatalf("steward = %q, want %q after rollback", reloaded.StewardUsername, steward.Username)
+	}
+	var audits int64
+	if err := db.Model(&models.MarketStewardshipAudit{}).Where("market_id = ?", market.ID).Count(&audits).Error; err != nil {
+		t.Fatalf("count stewardship audits: %v", err)
+	}
+	if audits != 0 {
+		t.Fatalf("stewardship audits = %d, want 0", audits)
+	}
+}
+
+// registerOutboxEventFailure fails any outbox insert that carries an event
+// of the given type.
+func registerOutboxEventFailure(t *testing.T, db *gorm.DB, name string, eventType devents.Type, err error) {
+	t.Helper()
+	registerGroupedTransactionCreateFailure(t, db, name, func(tx *gorm.DB) bool {
+		rows, ok := tx.Statement.Dest.(*[]models.OutboxEvent)
+		if !ok {
+			return false
+		}
+		for _, row := range *rows {
+			if row.EventType == string(eventType) {
+				return true
+			}
+		}
+		return false
+	}, err)
+}
@@ -123,7 +123,7 @@ func (r *GormRepository) List(ctx context.Context, filters dmarkets.ListFilters)
 	query = applyOwnedByFilter(query, filters.OwnedBy)
 	query = applyTagSlugFilter(query, filters.TagSlug)
 	query = applyPagination(query, filters.Limit, filters.Offset)
-	query = query.Order("markets.created_at DESC")
+	query = applyListSort(query, filters)
 
 	var dbMarkets []models.Market
 	if err := query.Find(&dbMarkets).Error; err != nil {
@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	dnotifications "socialpredict/internal/domain/notifications"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements the notifications domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dnotifications.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based notifications repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// CreateNotification inserts the notification unless the user already has
+// one for the same event key.
+func (r *GormRepository) CreateNotification(ctx context.Context, notification *dnotifications.Notification) (bool, error) {
+	row := notificationToModel(notification)
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&row)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	notification.ID = row.ID
+	return true, nil
+}
+
+// ListNotifications returns a page of the user's notifications newest first
+// and the total matching the filters.
+func (r *GormRepository) ListNotifications(ctx context.Context, username string, filters dnotifications.ListFilters) ([]*dnotifications.Notification, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("username = ?", username)
+	if filters.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []models.Notification
+	if err := query.Order("created_at DESC, id DESC").Limit(filters.Limit).Offset(filters.Offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	notifications := make([]*dnotifications.Notification, 0, len(rows))
+	for i := range rows {
+		notifications = append(notifications, modelToNotification(&rows[i]))
+	}
+	return notifications, total, nil
+}
+
+// CountUnread returns how many of the user's notifications are unread.
+func (r *GormRepository) CountUnread(ctx context.Context, username string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("username = ? AND read_at IS NULL", username).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks the user's unread notifications among ids read.
+func (r *GormRepository) MarkRead(ctx context.Context, username string, ids []int64, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("username = ? AND id IN ? AND read_at IS NULL", username, ids).
+		Update("read_at", at.UTC())
+	return result.RowsAffected, result.Error
+}
+
+// MarkAllRead marks every unread notification of the user read.
+func (r *GormRepository) MarkAllRead(ctx context.Context, username string, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("username = ? AND read_at IS NULL", username).
+		Update("read_at", at.UTC())
+	return result.RowsAffected, result.Error
+}
+
+// ListPreferences returns the user's stored per-type choices.
+func (r *GormRepository) ListPreferences(ctx context.Context, username string) (map[dnotifications.Type]bool, error) {
+	var rows []models.NotificationPreference
+	if err := r.db.WithContext(ctx).Where("username = ?", username).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	preferences := make(map[dnotifications.Type]bool, len(rows))
+	for _, row := range rows {
+		preferences[dnotifications.Type(row.Type)] = row.Enabled
+	}
+	return preferences, nil
+}
+
+// SavePreferences upserts one row per given type.
+func (r *GormRepository) SavePreferences(ctx context.Context, username string, preferences map[dnotifications.Type]bool, at time.Time) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	rows := make([]models.NotificationPreference, 0, len(preferences))
+	for t, enabled := range preferences {
+		rows = append(rows, models.NotificationPreference{Username: username, Type: string(t), Enabled: enabled, UpdatedAt: at.UTC()})
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "username"}, {Name: "type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).
+		Create(&rows).Error
+}
+
+func notificationToModel(notification *dnotifications.Notification) models.Notification {
+	return models.Notification{
+		ID:            notification.ID,
+		Username:      notification.Username,
+		EventKey:      notification.EventKey,
+		Type:          string(notification.Type),
+		Title:         notification.Title,
+		Body:          notification.Body,
+		MarketID:      notification.MarketID,
+		MarketGroupID: notification.MarketGroupID,
+		ReadAt:        notification.ReadAt,
+		CreatedAt:     notification.CreatedAt,
+	}
+}
+
+func modelToNotification(row *models.Notification) *dnotifications.Notification {
+	return &dnotifications.Notification{
+		ID:            row.ID,
+		Username:      row.Username,
+		EventKey:      row.EventKey,
+		Type:          dnotifications.Type(row.Type),
+		Title:         row.Title,
+		Body:          row.Body,
+		MarketID:      row.MarketID,
+		MarketGroupID: row.MarketGroupID,
+		ReadAt:        row.ReadAt,
+		CreatedAt:     row.CreatedAt,
+	}
+}
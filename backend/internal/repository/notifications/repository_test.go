@@ -0,0 +1,71 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dnotifications "socialpredict/internal/domain/notifications"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryNotificationsAndPreferences(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 29, 9, 0, 0, 0, time.UTC)
+
+	for i, key := range []string{"evt_1", "evt_2", "evt_3"} {
+		notification := &dnotifications.Notification{Username: "alice", EventKey: key, Type: dnotifications.TypeMarketResolved, Title: "resolved", MarketID: 7, CreatedAt: now.Add(time.Duration(i) * time.Minute)}
+		inserted, err := repo.CreateNotification(ctx, notification)
+		if err != nil || !inserted || notification.ID == 0 {
+			t.Fatalf("CreateNotification(%s) = %v, %v", key, inserted, err)
+		}
+	}
+	if inserted, err := repo.CreateNotification(ctx, &dnotifications.Notification{Username: "alice", EventKey: "evt_1", Type: dnotifications.TypeMarketResolved, Title: "dup", CreatedAt: now}); err != nil || inserted {
+		t.Fatalf("duplicate event key should be skipped, got %v, %v", inserted, err)
+	}
+	if _, err := repo.CreateNotification(ctx, &dnotifications.Notification{Username: "bob", EventKey: "evt_1", Type: dnotifications.TypeMarketResolved, Title: "bob", CreatedAt: now}); err != nil {
+		t.Fatalf("same event for another user: %v", err)
+	}
+
+	items, total, err := repo.ListNotifications(ctx, "alice", dnotifications.ListFilters{Limit: 2})
+	if err != nil || total != 3 || len(items) != 2 || items[0].EventKey != "evt_3" {
+		t.Fatalf("ListNotifications = %+v total=%d err=%v", items, total, err)
+	}
+
+	changed, err := repo.MarkRead(ctx, "bob", []int64{items[0].ID}, now)
+	if err != nil || changed != 0 {
+		t.Fatalf("another user's notification should not be marked, changed=%d err=%v", changed, err)
+	}
+	if changed, err := repo.MarkRead(ctx, "alice", []int64{items[0].ID}, now); err != nil || changed != 1 {
+		t.Fatalf("MarkRead changed=%d err=%v", changed, err)
+	}
+	unread, _, err := repo.ListNotifications(ctx, "alice", dnotifications.ListFilters{UnreadOnly: true, Limit: 10})
+	if err != nil || len(unread) != 2 {
+		t.Fatalf("unread listing = %d, %v", len(unread), err)
+	}
+	if changed, err := repo.MarkAllRead(ctx, "alice", now); err != nil || changed != 2 {
+		t.Fatalf("MarkAllRead changed=%d err=%v", changed, err)
+	}
+	if count, err := repo.CountUnread(ctx, "alice"); err != nil || count != 0 {
+		t.Fatalf("CountUnread = %d, %v", count, err)
+	}
+	if count, err := repo.CountUnread(ctx, "bob"); err != nil || count != 1 {
+		t.Fatalf("bob CountUnread = %d, %v", count, err)
+	}
+
+	if err := repo.SavePreferences(ctx, "alice", map[dnotifications.Type]bool{dnotifications.TypeMarketResolved: false}, now); err != nil {
+		t.Fatalf("SavePreferences returned error: %v", err)
+	}
+	if err := repo.SavePreferences(ctx, "alice", map[dnotifications.Type]bool{dnotifications.TypeMarketResolved: true, dnotifications.TypeStewardAssigned: false}, now); err != nil {
+		t.Fatalf("SavePreferences update returned error: %v", err)
+	}
+	preferences, err := repo.ListPreferences(ctx, "alice")
+	if err != nil {
+		t.Fatalf("ListPreferences returned error: %v", err)
+	}
+	if len(preferences) != 2 || !preferences[dnotifications.TypeMarketResolved] || preferences[dnotifications.TypeStewardAssigned] {
+		t.Fatalf("unexpected preferences: %+v", preferences)
+	}
+}
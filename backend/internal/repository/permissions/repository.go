@@ -0,0 +1,188 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	dpermissions "socialpredict/internal/domain/permissions"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements the permissions domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dpermissions.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based permissions repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// ListRoles returns every stored role with its grants.
+func (r *GormRepository) ListRoles(ctx context.Context) ([]*dpermissions.Role, error) {
+	var rows []models.AccessRole
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	grants, err := r.grantsByRole(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*dpermissions.Role, 0, len(rows))
+	for i := range rows {
+		roles = append(roles, modelRoleToDomain(&rows[i], grants[rows[i].Name]))
+	}
+	return roles, nil
+}
+
+// GetRole returns a stored role with its grants.
+func (r *GormRepository) GetRole(ctx context.Context, name string) (*dpermissions.Role, error) {
+	var row models.AccessRole
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dpermissions.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	grants, err := r.grantsByRole(ctx, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	return modelRoleToDomain(&row, grants[name]), nil
+}
+
+// SaveRole upserts the role and replaces its grants in one transaction.
+func (r *GormRepository) SaveRole(ctx context.Context, role *dpermissions.Role) error {
+	if role == nil {
+		return dpermissions.ErrInvalidRole
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := models.AccessRole{
+			Name:        role.Name,
+			Description: role.Description,
+			UpdatedBy:   role.UpdatedBy,
+			CreatedAt:   role.UpdatedAt,
+			UpdatedAt:   role.UpdatedAt,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description", "updated_by", "updated_at"}),
+		}).Create(&row).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("role = ?", role.Name).Delete(&models.AccessRolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(role.Permissions) == 0 {
+			return nil
+		}
+		grants := make([]models.AccessRolePermission, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			grants = append(grants, models.AccessRolePermission{Role: role.Name, Permission: string(permission), CreatedAt: role.UpdatedAt})
+		}
+		return tx.Create(&grants).Error
+	})
+}
+
+// ListUserRoles returns the custom roles assigned to username.
+func (r *GormRepository) ListUserRoles(ctx context.Context, username string) ([]string, error) {
+	var roles []string
+	if err := r.db.WithContext(ctx).
+		Model(&models.UserRoleAssignment{}).
+		Where("username = ?", username).
+		Order("role ASC").
+		Pluck("role", &roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// ReplaceUserRoles swaps the account's custom role assignments for roles.
+func (r *GormRepository) ReplaceUserRoles(ctx context.Context, username string, roles []string, grantedBy string, grantedAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("username = ?", username).Delete(&models.UserRoleAssignment{}).Error; err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return nil
+		}
+		rows := make([]models.UserRoleAssignment, 0, len(roles))
+		for _, role := range roles {
+			rows = append(rows, models.UserRoleAssignment{Username: username, Role: role, GrantedBy: grantedBy, CreatedAt: grantedAt})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// PermissionsForRoles returns the distinct registered permissions granted to any of roles.
+func (r *GormRepository) PermissionsForRoles(ctx context.Context, roles []string) ([]dpermissions.Permission, error) {
+	if len(roles) == 0 {
+		return []dpermissions.Permission{}, nil
+	}
+	var names []string
+	if err := r.db.WithContext(ctx).
+		Model(&models.AccessRolePermission{}).
+		Where("role IN ?", roles).
+		Distinct("permission").
+		Order("permission ASC").
+		Pluck("permission", &names).Error; err != nil {
+		return nil, err
+	}
+	return registeredPermissions(names), nil
+}
+
+// UserExists reports whether an account with username exists.
+func (r *GormRepository) UserExists(ctx context.Context, username string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *GormRepository) grantsByRole(ctx context.Context, roles []string) (map[string][]string, error) {
+	query := r.db.WithContext(ctx).Model(&models.AccessRolePermission{})
+	if roles != nil {
+		query = query.Where("role IN ?", roles)
+	}
+	var rows []models.AccessRolePermission
+	if err := query.Order("permission ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	grants := make(map[string][]string)
+	for _, row := range rows {
+		grants[row.Role] = append(grants[row.Role], row.Permission)
+	}
+	return grants, nil
+}
+
+func modelRoleToDomain(row *models.AccessRole, grants []string) *dpermissions.Role {
+	return &dpermissions.Role{
+		Name:        row.Name,
+		Description: row.Description,
+		Permissions: registeredPermissions(grants),
+		UpdatedBy:   row.UpdatedBy,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+// registeredPermissions drops stored grants that are no longer in the registry.
+func registeredPermissions(names []string) []dpermissions.Permission {
+	result := make([]dpermissions.Permission, 0, len(names))
+	for _, name := range names {
+		if permission, ok := dpermissions.Lookup(name); ok {
+			result = append(result, permission)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
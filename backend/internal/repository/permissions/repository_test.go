@@ -0,0 +1,89 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dpermissions "socialpredict/internal/domain/permissions"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryRoleGrantsAndAssignments(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 25, 9, 0, 0, 0, time.UTC)
+
+	seeded, err := repo.GetRole(ctx, "CMS_EDITOR")
+	if err != nil {
+		t.Fatalf("GetRole(CMS_EDITOR) returned error: %v", err)
+	}
+	if len(seeded.Permissions) != 1 || seeded.Permissions[0] != dpermissions.CMSEdit {
+		t.Fatalf("unexpected seeded grants: %+v", seeded.Permissions)
+	}
+	if _, err := repo.GetRole(ctx, "MISSING"); !errors.Is(err, dpermissions.ErrRoleNotFound) {
+		t.Fatalf("missing role error = %v, want ErrRoleNotFound", err)
+	}
+
+	if err := repo.SaveRole(ctx, &dpermissions.Role{Name: "TAGGER", Permissions: []dpermissions.Permission{dpermissions.TagsManage, dpermissions.MarketsApprove}, UpdatedBy: "root", UpdatedAt: now}); err != nil {
+		t.Fatalf("SaveRole returned error: %v", err)
+	}
+	if err := repo.SaveRole(ctx, &dpermissions.Role{Name: "TAGGER", Description: "curators", Permissions: []dpermissions.Permission{dpermissions.TagsManage}, UpdatedBy: "root", UpdatedAt: now}); err != nil {
+		t.Fatalf("SaveRole replace returned error: %v", err)
+	}
+	tagger, err := repo.GetRole(ctx, "TAGGER")
+	if err != nil {
+		t.Fatalf("GetRole(TAGGER) returned error: %v", err)
+	}
+	if tagger.Description != "curators" || len(tagger.Permissions) != 1 || tagger.Permissions[0] != dpermissions.TagsManage {
+		t.Fatalf("expected grants to be replaced, got %+v", tagger)
+	}
+
+	if err := repo.ReplaceUserRoles(ctx, "ada", []string{"CMS_EDITOR", "TAGGER"}, "root", now); err != nil {
+		t.Fatalf("ReplaceUserRoles returned error: %v", err)
+	}
+	if err := repo.ReplaceUserRoles(ctx, "ada", []string{"TAGGER"}, "root", now); err != nil {
+		t.Fatalf("ReplaceUserRoles second call returned error: %v", err)
+	}
+	roles, err := repo.ListUserRoles(ctx, "ada")
+	if err != nil {
+		t.Fatalf("ListUserRoles returned error: %v", err)
+	}
+	if len(roles) != 1 || roles[0] != "TAGGER" {
+		t.Fatalf("unexpected user roles: %v", roles)
+	}
+
+	granted, err := repo.PermissionsForRoles(ctx, []string{"CMS_EDITOR", "TAGGER", "REGULAR"})
+	if err != nil {
+		t.Fatalf("PermissionsForRoles returned error: %v", err)
+	}
+	if len(granted) != 2 || granted[0] != dpermissions.CMSEdit || granted[1] != dpermissions.TagsManage {
+		t.Fatalf("unexpected permissions: %v", granted)
+	}
+
+	all, err := repo.ListRoles(ctx)
+	if err != nil {
+		t.Fatalf("ListRoles returned error: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("expected 4 stored roles, got %d", len(all))
+	}
+}
+
+func TestGormRepositoryUserExists(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	user := modelstesting.GenerateUser("ada", 0)
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+	repo := NewGormRepository(db)
+
+	if exists, err := repo.UserExists(context.Background(), "ada"); err != nil || !exists {
+		t.Fatalf("UserExists(ada) = %v, %v", exists, err)
+	}
+	if exists, err := repo.UserExists(context.Background(), "ghost"); err != nil || exists {
+		t.Fatalf("UserExists(ghost) = %v, %v", exists, err)
+	}
+}
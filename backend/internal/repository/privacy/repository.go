@@ -0,0 +1,71 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+
+	dprivacy "socialpredict/internal/domain/privacy"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements the privacy domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dprivacy.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based privacy repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// GetSettings loads the user's settings, defaulting when none are stored.
+func (r *GormRepository) GetSettings(ctx context.Context, username string) (dprivacy.Settings, error) {
+	var row models.TradingPrivacySetting
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dprivacy.Settings{}, nil
+	}
+	if err != nil {
+		return dprivacy.Settings{}, err
+	}
+	return dprivacy.Settings{
+		HidePortfolio:   row.HidePortfolio,
+		AnonymousTrades: row.AnonymousTrades,
+		UpdatedAt:       row.UpdatedAt,
+	}, nil
+}
+
+// SaveSettings inserts or replaces the user's settings.
+func (r *GormRepository) SaveSettings(ctx context.Context, username string, settings dprivacy.Settings) error {
+	row := models.TradingPrivacySetting{
+		Username:        username,
+		HidePortfolio:   settings.HidePortfolio,
+		AnonymousTrades: settings.AnonymousTrades,
+		UpdatedAt:       settings.UpdatedAt,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "username"}},
+			DoUpdates: clause.AssignmentColumns([]string{"hide_portfolio", "anonymous_trades", "updated_at"}),
+		}).
+		Create(&row).Error
+}
+
+// ListAnonymousTraders returns the subset of usernames trading anonymously.
+func (r *GormRepository) ListAnonymousTraders(ctx context.Context, usernames []string) ([]string, error) {
+	var names []string
+	if len(usernames) == 0 {
+		return names, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.TradingPrivacySetting{}).
+		Where("anonymous_trades = ? AND username IN ?", true, usernames).
+		Order("username ASC").
+		Pluck("username", &names).Error
+	return names, err
+}
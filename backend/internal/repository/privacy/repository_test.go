@@ -0,0 +1,39 @@
+package privacy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dprivacy "socialpredict/internal/domain/privacy"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositorySettings(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 7, 5, 9, 0, 0, 0, time.UTC)
+
+	if settings, err := repo.GetSettings(ctx, "alice"); err != nil || settings.HidePortfolio || settings.AnonymousTrades {
+		t.Fatalf("expected default settings, got %+v, %v", settings, err)
+	}
+	if err := repo.SaveSettings(ctx, "alice", dprivacy.Settings{HidePortfolio: true, AnonymousTrades: true, UpdatedAt: now}); err != nil {
+		t.Fatalf("SaveSettings returned error: %v", err)
+	}
+	if err := repo.SaveSettings(ctx, "alice", dprivacy.Settings{AnonymousTrades: true, UpdatedAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("SaveSettings returned error: %v", err)
+	}
+	if err := repo.SaveSettings(ctx, "bob", dprivacy.Settings{HidePortfolio: true, UpdatedAt: now}); err != nil {
+		t.Fatalf("SaveSettings returned error: %v", err)
+	}
+
+	settings, err := repo.GetSettings(ctx, "alice")
+	if err != nil || settings.HidePortfolio || !settings.AnonymousTrades || !settings.UpdatedAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the second save to replace the first, got %+v, %v", settings, err)
+	}
+	names, err := repo.ListAnonymousTraders(ctx, []string{"alice", "bob", "carol"})
+	if err != nil || len(names) != 1 || names[0] != "alice" {
+		t.Fatalf("ListAnonymousTraders = %v, %v", names, err)
+	}
+}
@@ -0,0 +1,266 @@
+package reports
+
+import (
+	"context"
+	"errors"
+
+	devents "socialpredict/internal/domain/events"
+	dreports "socialpredict/internal/domain/reports"
+	revents "socialpredict/internal/repository/events"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// GormRepository implements the reports domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dreports.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based reports repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// FindOpenReport returns the reporter's open report on target, or nil.
+func (r *GormRepository) FindOpenReport(ctx context.Context, reporter string, target dreports.Target) (*dreports.Report, error) {
+	var row models.ContentReport
+	err := r.db.WithContext(ctx).
+		Where("reporter_username = ? AND target_key = ? AND status = ?", reporter, target.Key(), string(dreports.StatusOpen)).
+		First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToReport(&row), nil
+}
+
+// CreateReport inserts the report and sets its ID.
+func (r *GormRepository) CreateReport(ctx context.Context, report *dreports.Report) error {
+	row := reportToModel(report)
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	report.ID = row.ID
+	return nil
+}
+
+// ListReporterReports returns a page of the reporter's reports newest first.
+func (r *GormRepository) ListReporterReports(ctx context.Context, reporter string, limit, offset int) ([]*dreports.Report, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ContentReport{}).Where("reporter_username = ?", reporter)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []models.ContentReport
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return modelsToReports(rows), total, nil
+}
+
+type queueRow struct {
+	TargetKey   string
+	ReportCount int
+	LastID      int64
+}
+
+// ListQueue groups reports with status by target, most reported first and
+// then most recently reported.
+func (r *GormRepository) ListQueue(ctx context.Context, status dreports.Status, limit, offset, sampleSize int) ([]*dreports.QueueItem, int64, error) {
+	db := r.db.WithContext(ctx)
+	var total int64
+	if err := db.Model(&models.ContentReport{}).
+		Where("status = ?", string(status)).
+		Distinct("target_key").
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var groups []queueRow
+	if err := db.Model(&models.ContentReport{}).
+		Select("target_key, COUNT(*) AS report_count, MAX(id) AS last_id").
+		Where("status = ?", string(status)).
+		Group("target_key").
+		Order("report_count DESC, last_id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&groups).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(groups) == 0 {
+		return []*dreports.QueueItem{}, total, nil
+	}
+
+	keys := make([]string, 0, len(groups))
+	for _, group := range groups {
+		keys = append(keys, group.TargetKey)
+	}
+	var rows []models.ContentReport
+	if err := db.Where("status = ? AND target_key IN ?", string(status), keys).
+		Order("created_at DESC, id DESC").
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]*dreports.QueueItem, 0, len(groups))
+	byKey := make(map[string]*dreports.QueueItem, len(groups))
+	for _, group := range groups {
+		item := &dreports.QueueItem{ReportCount: group.ReportCount, Categories: map[dreports.Category]int{}}
+		items = append(items, item)
+		byKey[group.TargetKey] = item
+	}
+	for i := range rows {
+		item := byKey[rows[i].TargetKey]
+		if item == nil {
+			continue
+		}
+		report := modelToReport(&rows[i])
+		item.Target = report.Target()
+		item.Categories[report.Category]++
+		if item.LastReportedAt.IsZero() || report.CreatedAt.After(item.LastReportedAt) {
+			item.LastReportedAt = report.CreatedAt
+		}
+		if item.FirstReportedAt.IsZero() || report.CreatedAt.Before(item.FirstReportedAt) {
+			item.FirstReportedAt = report.CreatedAt
+		}
+		if len(item.Reports) < sampleSize {
+			item.Reports = append(item.Reports, report)
+		}
+	}
+	return items, total, nil
+}
+
+// CloseReports settles the target's open reports and stores the action in
+// one transaction, recording a ReportResolved event for each closed report.
+func (r *GormRepository) CloseReports(ctx context.Context, action *dreports.ModerationAction, status dreports.Status) error {
+	target := dreports.Target{Type: action.TargetType, ID: action.TargetID}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var open []models.ContentReport
+		if err := tx.Where("target_key = ? AND status = ?", target.Key(), string(dreports.StatusOpen)).
+			Order("id ASC").
+			Find(&open).Error; err != nil {
+			return err
+		}
+		events := make([]devents.Event, 0, len(open))
+		if len(open) > 0 {
+			ids := make([]int64, 0, len(open))
+			for _, report := range open {
+				ids = append(ids, report.ID)
+				events = append(events, devents.Event{
+					Type:     devents.ReportResolved,
+					Username: action.ActorUsername,
+					Data: map[string]any{
+						"reportId":   report.ID,
+						"reporter":   report.ReporterUsername,
+						"targetType": report.TargetType,
+						"targetId":   report.TargetID,
+						"status":     string(status),
+						"outcome":    string(action.Action),
+					},
+					OccurredAt: action.CreatedAt,
+				})
+			}
+			if err := tx.Model(&models.ContentReport{}).
+				Where("id IN ?", ids).
+				Updates(map[string]any{
+					"status":      string(status),
+					"outcome":     string(action.Action),
+					"reviewed_by": action.ActorUsername,
+					"reviewed_at": action.CreatedAt,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		row := models.ModerationAction{
+			TargetKey:     target.Key(),
+			TargetType:    string(action.TargetType),
+			TargetID:      action.TargetID,
+			Action:        string(action.Action),
+			ActorUsername: action.ActorUsername,
+			Reason:        action.Reason,
+			ReportsClosed: len(open),
+			CreatedAt:     action.CreatedAt,
+		}
+		if err := tx.Create(&row).Error; err != nil {
+			return err
+		}
+		action.ID = row.ID
+		action.ReportsClosed = row.ReportsClosed
+		return revents.Append(ctx, tx, events...)
+	})
+}
+
+// ListActions returns the target's moderation actions newest first.
+func (r *GormRepository) ListActions(ctx context.Context, target dreports.Target, limit, offset int) ([]*dreports.ModerationAction, error) {
+	var rows []models.ModerationAction
+	if err := r.db.WithContext(ctx).
+		Where("target_key = ?", target.Key()).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	actions := make([]*dreports.ModerationAction, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		actions = append(actions, &dreports.ModerationAction{
+			ID:            row.ID,
+			TargetType:    dreports.TargetType(row.TargetType),
+			TargetID:      row.TargetID,
+			Action:        dreports.Action(row.Action),
+			ActorUsername: row.ActorUsername,
+			Reason:        row.Reason,
+			ReportsClosed: row.ReportsClosed,
+			CreatedAt:     row.CreatedAt,
+		})
+	}
+	return actions, nil
+}
+
+func reportToModel(report *dreports.Report) models.ContentReport {
+	return models.ContentReport{
+		ID:               report.ID,
+		TargetKey:        report.Target().Key(),
+		TargetType:       string(report.TargetType),
+		TargetID:         report.TargetID,
+		ReporterUsername: report.ReporterUsername,
+		Category:         string(report.Category),
+		Details:          report.Details,
+		Status:           string(report.Status),
+		Outcome:          string(report.Outcome),
+		ReviewedBy:       report.ReviewedBy,
+		ReviewedAt:       report.ReviewedAt,
+		CreatedAt:        report.CreatedAt,
+	}
+}
+
+func modelToReport(row *models.ContentReport) *dreports.Report {
+	return &dreports.Report{
+		ID:               row.ID,
+		TargetType:       dreports.TargetType(row.TargetType),
+		TargetID:         row.TargetID,
+		ReporterUsername: row.ReporterUsername,
+		Category:         dreports.Category(row.Category),
+		Details:          row.Details,
+		Status:           dreports.Status(row.Status),
+		Outcome:          dreports.Action(row.Outcome),
+		ReviewedBy:       row.ReviewedBy,
+		ReviewedAt:       row.ReviewedAt,
+		CreatedAt:        row.CreatedAt,
+	}
+}
+
+func modelsToReports(rows []models.ContentReport) []*dreports.Report {
+	reports := make([]*dreports.Report, 0, len(rows))
+	for i := range rows {
+		reports = append(reports, modelToReport(&rows[i]))
+	}
+	return reports
+}
@@ -0,0 +1,73 @@
+package reports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dreports "socialpredict/internal/domain/reports"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryQueueAndClose(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 7, 2, 9, 0, 0, 0, time.UTC)
+
+	file := func(targetType dreports.TargetType, targetID, reporter string, category dreports.Category, offset time.Duration) *dreports.Report {
+		t.Helper()
+		report := &dreports.Report{TargetType: targetType, TargetID: targetID, ReporterUsername: reporter, Category: category, Status: dreports.StatusOpen, CreatedAt: now.Add(offset)}
+		if err := repo.CreateReport(ctx, report); err != nil || report.ID == 0 {
+			t.Fatalf("CreateReport = %v", err)
+		}
+		return report
+	}
+	file(dreports.TargetMarket, "7", "alice", dreports.CategoryMisleading, 0)
+	file(dreports.TargetComment, "3", "alice", dreports.CategorySpam, time.Minute)
+	file(dreports.TargetComment, "3", "bob", dreports.CategorySpam, 2*time.Minute)
+	file(dreports.TargetComment, "3", "carol", dreports.CategoryHarassment, 3*time.Minute)
+
+	existing, err := repo.FindOpenReport(ctx, "bob", dreports.Target{Type: dreports.TargetComment, ID: "3"})
+	if err != nil || existing == nil || existing.ReporterUsername != "bob" {
+		t.Fatalf("FindOpenReport = %+v, %v", existing, err)
+	}
+
+	items, total, err := repo.ListQueue(ctx, dreports.StatusOpen, 10, 0, 2)
+	if err != nil || total != 2 || len(items) != 2 {
+		t.Fatalf("ListQueue = %+v total=%d err=%v", items, total, err)
+	}
+	top := items[0]
+	if top.Target.Key() != "comment:3" || top.ReportCount != 3 || top.Categories[dreports.CategorySpam] != 2 || len(top.Reports) != 2 || top.Reports[0].ReporterUsername != "carol" {
+		t.Fatalf("unexpected top item %+v", top)
+	}
+	if !top.FirstReportedAt.Equal(now.Add(time.Minute)) || !top.LastReportedAt.Equal(now.Add(3*time.Minute)) {
+		t.Fatalf("unexpected report window %v - %v", top.FirstReportedAt, top.LastReportedAt)
+	}
+
+	action := &dreports.ModerationAction{TargetType: dreports.TargetComment, TargetID: "3", Action: dreports.ActionHideContent, ActorUsername: "mod", Reason: "spam", CreatedAt: now.Add(time.Hour)}
+	if err := repo.CloseReports(ctx, action, dreports.StatusActioned); err != nil {
+		t.Fatalf("CloseReports returned error: %v", err)
+	}
+	if action.ID == 0 || action.ReportsClosed != 3 {
+		t.Fatalf("unexpected action %+v", action)
+	}
+	if _, total, _ := repo.ListQueue(ctx, dreports.StatusOpen, 10, 0, 2); total != 1 {
+		t.Fatalf("expected one open target left, got %d", total)
+	}
+	mine, _, err := repo.ListReporterReports(ctx, "alice", 10, 0)
+	if err != nil || len(mine) != 2 || mine[0].Status != dreports.StatusActioned || mine[0].Outcome != dreports.ActionHideContent {
+		t.Fatalf("ListReporterReports = %+v, %v", mine, err)
+	}
+	var events int64
+	db.Model(&models.OutboxEvent{}).Where("event_type = ?", string(devents.ReportResolved)).Count(&events)
+	if events != 3 {
+		t.Fatalf("expected one event per closed report, got %d", events)
+	}
+	actions, err := repo.ListActions(ctx, dreports.Target{Type: dreports.TargetComment, ID: "3"}, 10, 0)
+	if err != nil || len(actions) != 1 || actions[0].Reason != "spam" {
+		t.Fatalf("ListActions = %+v, %v", actions, err)
+	}
+}
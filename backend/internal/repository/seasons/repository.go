@@ -0,0 +1,401 @@
+package seasons
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dseasons "socialpredict/internal/domain/seasons"
+	revents "socialpredict/internal/repository/events"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errBalanceMoved aborts a rollover when a balance changes between reading it
+// and resetting it, so every reset row matches the balance it replaced.
+var errBalanceMoved = errors.New("account balance changed during season rollover")
+
+// GormRepository implements the seasons domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dseasons.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based seasons repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// CreateSeason inserts the season and sets its ID.
+func (r *GormRepository) CreateSeason(ctx context.Context, season *dseasons.Season) error {
+	row := seasonToModel(season)
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	season.ID = row.ID
+	season.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// UpdateSeason saves the season's definition.
+func (r *GormRepository) UpdateSeason(ctx context.Context, season *dseasons.Season) error {
+	result := r.db.WithContext(ctx).Model(&models.Season{}).
+		Where("id = ? AND archived_at IS NULL", season.ID).
+		Updates(map[string]any{
+			"name":            season.Name,
+			"starts_at":       season.StartsAt,
+			"ends_at":         season.EndsAt,
+			"reset_balances":  season.ResetBalances,
+			"position_policy": string(season.PositionPolicy),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dseasons.ErrSeasonArchived
+	}
+	return nil
+}
+
+// GetSeason returns the season or ErrSeasonNotFound.
+func (r *GormRepository) GetSeason(ctx context.Context, id int64) (*dseasons.Season, error) {
+	var row models.Season
+	err := r.db.WithContext(ctx).First(&row, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, dseasons.ErrSeasonNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToSeason(&row), nil
+}
+
+// ListSeasons returns every season, latest start first.
+func (r *GormRepository) ListSeasons(ctx context.Context) ([]dseasons.Season, error) {
+	var rows []models.Season
+	if err := r.db.WithContext(ctx).Order("starts_at DESC, id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	seasons := make([]dseasons.Season, 0, len(rows))
+	for i := range rows {
+		seasons = append(seasons, *modelToSeason(&rows[i]))
+	}
+	return seasons, nil
+}
+
+// Overlaps reports whether another season shares part of [startsAt, endsAt).
+func (r *GormRepository) Overlaps(ctx context.Context, startsAt, endsAt time.Time, excludeID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Season{}).
+		Where("starts_at < ? AND ends_at > ? AND id <> ?", endsAt, startsAt, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListOpenMarkets returns unresolved markets that have bets.
+func (r *GormRepository) ListOpenMarkets(ctx context.Context) ([]dseasons.OpenMarket, error) {
+	var rows []models.Market
+	err := r.db.WithContext(ctx).Model(&models.Market{}).
+		Select("id", "resolution_date_time").
+		Where("is_resolved = ?", false).
+		Where("EXISTS (SELECT 1 FROM bets WHERE bets.market_id = markets.id)").
+		Order("id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	markets := make([]dseasons.OpenMarket, 0, len(rows))
+	for _, row := range rows {
+		markets = append(markets, dseasons.OpenMarket{ID: row.ID, ClosesAt: row.ResolutionDateTime})
+	}
+	return markets, nil
+}
+
+// LatestBetID returns the newest bet ID, or zero when there are no bets.
+func (r *GormRepository) LatestBetID(ctx context.Context) (uint, error) {
+	var id uint
+	err := r.db.WithContext(ctx).Model(&models.Bet{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+// ArchiveSeason sells the liquidated positions, freezes the standings, resets
+// balances when asked, and marks the season archived in one transaction. On
+// Postgres it locks every user row and each liquidated market first, which
+// holds bets and sales off until the rollover commits.
+func (r *GormRepository) ArchiveSeason(ctx context.Context, archive dseasons.Archive) (*dseasons.Season, error) {
+	var season models.Season
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&season, archive.SeasonID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dseasons.ErrSeasonNotFound
+			}
+			return err
+		}
+		if season.ArchivedAt != nil {
+			return dseasons.ErrSeasonArchived
+		}
+
+		var users []models.User
+		if err := lockRows(tx).Select("username", "account_balance", "initial_account_balance").Order("username ASC").Find(&users).Error; err != nil {
+			return err
+		}
+		if err := liquidate(ctx, tx, archive, users); err != nil {
+			return err
+		}
+		balances := make(map[string]models.User, len(users))
+		for _, user := range users {
+			balances[user.Username] = user
+		}
+
+		if len(archive.Standings) > 0 {
+			rows := make([]models.SeasonStanding, 0, len(archive.Standings))
+			for _, standing := range archive.Standings {
+				rows = append(rows, models.SeasonStanding{
+					SeasonID:        archive.SeasonID,
+					Username:        standing.Username,
+					Rank:            standing.Rank,
+					Profit:          standing.Profit,
+					Spent:           standing.Spent,
+					CurrentValue:    standing.CurrentValue,
+					ActiveMarkets:   standing.ActiveMarkets,
+					ResolvedMarkets: standing.ResolvedMarkets,
+					FinalBalance:    balances[standing.Username].AccountBalance,
+				})
+			}
+			if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		reset := 0
+		if archive.ResetBalances {
+			var rows []models.SeasonBalanceReset
+			for _, user := range users {
+				if user.AccountBalance == user.InitialAccountBalance {
+					continue
+				}
+				rows = append(rows, models.SeasonBalanceReset{
+					SeasonID:      archive.SeasonID,
+					Username:      user.Username,
+					BalanceBefore: user.AccountBalance,
+					BalanceAfter:  user.InitialAccountBalance,
+					CreatedAt:     archive.ArchivedAt,
+				})
+			}
+			if len(rows) > 0 {
+				if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+					return err
+				}
+				for _, row := range rows {
+					result := tx.Model(&models.User{}).
+						Where("username = ? AND account_balance = ?", row.Username, row.BalanceBefore).
+						Update("account_balance", row.BalanceAfter)
+					if result.Error != nil {
+						return result.Error
+					}
+					if result.RowsAffected == 0 {
+						return errBalanceMoved
+					}
+				}
+			}
+			reset = len(rows)
+		}
+
+		archivedAt := archive.ArchivedAt
+		season.ArchivedAt = &archivedAt
+		season.ArchivedBy = archive.ArchivedBy
+		season.PositionsLiquidated = archive.PositionsLiquidated
+		season.PositionsCarried = archive.PositionsCarried
+		season.BalancesReset = reset
+		if err := tx.Model(&models.Season{}).Where("id = ?", season.ID).Updates(map[string]any{
+			"archived_at":          season.ArchivedAt,
+			"archived_by":          season.ArchivedBy,
+			"positions_liquidated": season.PositionsLiquidated,
+			"positions_carried":    season.PositionsCarried,
+			"balances_reset":       season.BalancesReset,
+		}).Error; err != nil {
+			return err
+		}
+
+		return revents.Append(ctx, tx, devents.Event{
+			Type:     devents.SeasonArchived,
+			Username: archive.ArchivedBy,
+			Data: map[string]any{
+				"seasonId":            season.ID,
+				"standings":           len(archive.Standings),
+				"resetBalances":       archive.ResetBalances,
+				"balancesReset":       reset,
+				"positionsLiquidated": archive.PositionsLiquidated,
+				"positionsCarried":    archive.PositionsCarried,
+			},
+			OccurredAt: archive.ArchivedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return modelToSeason(&season), nil
+}
+
+// liquidate writes a sale bet and a SeasonLiquidation row for each
+// liquidation, credits its value, and records the sale like any other. It
+// refuses with ErrPositionsMoved when a liquidated market has resolved or
+// taken a bet since the positions were valued. users is updated in place
+// with the credited balances.
+func liquidate(ctx context.Context, tx *gorm.DB, archive dseasons.Archive, users []models.User) error {
+	if len(archive.Liquidations) == 0 {
+		return nil
+	}
+	marketIDs := make([]int64, 0)
+	seen := make(map[int64]bool)
+	for _, liquidation := range archive.Liquidations {
+		if !seen[liquidation.MarketID] {
+			seen[liquidation.MarketID] = true
+			marketIDs = append(marketIDs, liquidation.MarketID)
+		}
+	}
+	var open []int64
+	if err := lockRows(tx).Model(&models.Market{}).
+		Where("id IN ? AND is_resolved = ?", marketIDs, false).
+		Order("id ASC").
+		Pluck("id", &open).Error; err != nil {
+		return err
+	}
+	var moved int64
+	if err := tx.Model(&models.Bet{}).
+		Where("market_id IN ? AND id > ?", marketIDs, archive.SnapshotBetID).
+		Count(&moved).Error; err != nil {
+		return err
+	}
+	if len(open) != len(marketIDs) || moved > 0 {
+		return dseasons.ErrPositionsMoved
+	}
+
+	index := make(map[string]int, len(users))
+	for i, user := range users {
+		index[user.Username] = i
+	}
+	events := make([]devents.Event, 0, len(archive.Liquidations))
+	for _, liquidation := range archive.Liquidations {
+		i, ok := index[liquidation.Username]
+		if !ok {
+			return dseasons.ErrPositionsMoved
+		}
+		bet := models.Bet{
+			Username: liquidation.Username,
+			MarketID: uint(liquidation.MarketID),
+			Amount:   -liquidation.Shares,
+			Outcome:  liquidation.Outcome,
+			PlacedAt: archive.ArchivedAt,
+		}
+		if err := tx.Create(&bet).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.SeasonLiquidation{
+			SeasonID:  archive.SeasonID,
+			Username:  liquidation.Username,
+			MarketID:  liquidation.MarketID,
+			BetID:     bet.ID,
+			Outcome:   liquidation.Outcome,
+			Shares:    liquidation.Shares,
+			Value:     liquidation.Value,
+			CreatedAt: archive.ArchivedAt,
+		}).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.User{}).
+			Where("username = ? AND account_balance = ?", liquidation.Username, users[i].AccountBalance).
+			Update("account_balance", users[i].AccountBalance+liquidation.Value)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errBalanceMoved
+		}
+		users[i].AccountBalance += liquidation.Value
+		events = append(events, devents.Event{
+			Type:     devents.SharesSold,
+			MarketID: liquidation.MarketID,
+			Username: liquidation.Username,
+			Data: map[string]any{
+				"outcome":     liquidation.Outcome,
+				"sharesSold":  liquidation.Shares,
+				"saleValue":   liquidation.Value,
+				"netProceeds": liquidation.Value,
+				"seasonId":    archive.SeasonID,
+			},
+			OccurredAt: archive.ArchivedAt,
+		})
+	}
+	return revents.Append(ctx, tx, events...)
+}
+
+// lockRows adds FOR UPDATE on Postgres; sqlite serializes writers already.
+func lockRows(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == "postgres" {
+		return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	return tx
+}
+
+// ListStandings returns a page of an archived season's standings by rank.
+func (r *GormRepository) ListStandings(ctx context.Context, seasonID int64, limit, offset int) ([]dseasons.Standing, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.SeasonStanding{}).Where("season_id = ?", seasonID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []models.SeasonStanding
+	if err := query.Order(clause.OrderByColumn{Column: clause.Column{Name: "rank"}}).Order("username ASC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	standings := make([]dseasons.Standing, 0, len(rows))
+	for _, row := range rows {
+		standings = append(standings, dseasons.Standing{
+			Rank:            row.Rank,
+			Username:        row.Username,
+			Profit:          row.Profit,
+			Spent:           row.Spent,
+			CurrentValue:    row.CurrentValue,
+			ActiveMarkets:   row.ActiveMarkets,
+			ResolvedMarkets: row.ResolvedMarkets,
+			FinalBalance:    row.FinalBalance,
+		})
+	}
+	return standings, total, nil
+}
+
+func seasonToModel(season *dseasons.Season) models.Season {
+	return models.Season{
+		ID:             season.ID,
+		Name:           season.Name,
+		StartsAt:       season.StartsAt,
+		EndsAt:         season.EndsAt,
+		ResetBalances:  season.ResetBalances,
+		PositionPolicy: string(season.PositionPolicy),
+		CreatedBy:      season.CreatedBy,
+		CreatedAt:      season.CreatedAt,
+	}
+}
+
+func modelToSeason(row *models.Season) *dseasons.Season {
+	return &dseasons.Season{
+		ID:                  row.ID,
+		Name:                row.Name,
+		StartsAt:            row.StartsAt,
+		EndsAt:              row.EndsAt,
+		ResetBalances:       row.ResetBalances,
+		PositionPolicy:      dseasons.PositionPolicy(row.PositionPolicy),
+		CreatedBy:           row.CreatedBy,
+		ArchivedAt:          row.ArchivedAt,
+		ArchivedBy:          row.ArchivedBy,
+		PositionsLiquidated: row.PositionsLiquidated,
+		PositionsCarried:    row.PositionsCarried,
+		BalancesReset:       row.BalancesReset,
+		CreatedAt:           row.CreatedAt,
+	}
+}
@@ -0,0 +1,289 @@
+package seasons
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/internal/app"
+	dbets "socialpredict/internal/domain/bets"
+	devents "socialpredict/internal/domain/events"
+	"socialpredict/internal/domain/permissions"
+	dseasons "socialpredict/internal/domain/seasons"
+	configsvc "socialpredict/internal/service/config"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+
+	"gorm.io/gorm"
+)
+
+func TestGormRepositoryArchiveSeason(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	alice := modelstesting.GenerateUser("alice", 1000)
+	alice.AccountBalance = 1400
+	bob := modelstesting.GenerateUser("bob", 1000)
+	for _, user := range []*models.User{&alice, &bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	season := &dseasons.Season{Name: "Q3", StartsAt: start, EndsAt: start.AddDate(0, 3, 0), ResetBalances: true, PositionPolicy: dseasons.PositionsCarry, CreatedBy: "admin"}
+	if err := repo.CreateSeason(ctx, season); err != nil || season.ID == 0 {
+		t.Fatalf("CreateSeason = %v", err)
+	}
+	if overlaps, err := repo.Overlaps(ctx, start.AddDate(0, 2, 0), start.AddDate(0, 4, 0), 0); err != nil || !overlaps {
+		t.Fatalf("Overlaps = %v, %v; want true", overlaps, err)
+	}
+	if overlaps, err := repo.Overlaps(ctx, start.AddDate(0, 3, 0), start.AddDate(0, 6, 0), 0); err != nil || overlaps {
+		t.Fatalf("adjacent season Overlaps = %v, %v; want false", overlaps, err)
+	}
+	if overlaps, _ := repo.Overlaps(ctx, start, start.AddDate(0, 3, 0), season.ID); overlaps {
+		t.Fatal("a season should not overlap itself")
+	}
+
+	archive := dseasons.Archive{
+		SeasonID:         season.ID,
+		ResetBalances:    true,
+		PositionsCarried: 2,
+		ArchivedBy:       "admin",
+		ArchivedAt:       start.AddDate(0, 3, 1),
+		Standings: []dseasons.Standing{
+			{Rank: 1, Username: "alice", Profit: 400},
+			{Rank: 2, Username: "bob", Profit: 0},
+		},
+	}
+	archived, err := repo.ArchiveSeason(ctx, archive)
+	if err != nil {
+		t.Fatalf("ArchiveSeason returned error: %v", err)
+	}
+	if archived.ArchivedAt == nil || archived.BalancesReset != 1 || archived.PositionsCarried != 2 {
+		t.Fatalf("unexpected archived season %+v", archived)
+	}
+
+	var reloaded models.User
+	db.Where("username = ?", "alice").First(&reloaded)
+	if reloaded.AccountBalance != 1000 {
+		t.Fatalf("alice balance = %d, want reset to 1000", reloaded.AccountBalance)
+	}
+	var resets []models.SeasonBalanceReset
+	db.Where("season_id = ?", season.ID).Find(&resets)
+	if len(resets) != 1 || resets[0].Username != "alice" || resets[0].BalanceBefore != 1400 || resets[0].BalanceAfter != 1000 {
+		t.Fatalf("unexpected resets %+v", resets)
+	}
+
+	standings, total, err := repo.ListStandings(ctx, season.ID, 10, 0)
+	if err != nil || total != 2 || len(standings) != 2 {
+		t.Fatalf("ListStandings = %+v total=%d err=%v", standings, total, err)
+	}
+	if standings[0].Username != "alice" || standings[0].FinalBalance != 1400 || standings[1].FinalBalance != 1000 {
+		t.Fatalf("standings should freeze pre-reset balances, got %+v", standings)
+	}
+
+	var events int64
+	db.Model(&models.OutboxEvent{}).Where("event_type = ?", string(devents.SeasonArchived)).Count(&events)
+	if events != 1 {
+		t.Fatalf("expected one archive event, got %d", events)
+	}
+
+	if _, err := repo.ArchiveSeason(ctx, archive); !errors.Is(err, dseasons.ErrSeasonArchived) {
+		t.Fatalf("second ArchiveSeason = %v, want ErrSeasonArchived", err)
+	}
+	if err := repo.UpdateSeason(ctx, archived); !errors.Is(err, dseasons.ErrSeasonArchived) {
+		t.Fatalf("UpdateSeason on archived season = %v, want ErrSeasonArchived", err)
+	}
+}
+
+type seasonManagers struct{}
+
+func (seasonManagers) Can(context.Context, permissions.Subject, permissions.Permission) (bool, error) {
+	return true, nil
+}
+
+// seedOpposingHolders places a YES bet for alice and a NO bet for bob on one
+// market and returns the market ID with the container used to place them.
+func seedOpposingHolders(t *testing.T) (*gorm.DB, *app.Container, int64) {
+	t.Helper()
+	db := modelstesting.NewFakeDB(t)
+	econConfig, _ := modelstesting.UseStandardTestEconomics(t)
+
+	creator := modelstesting.GenerateUser("season_creator", 1000)
+	alice := modelstesting.GenerateUser("alice", 1000)
+	bob := modelstesting.GenerateUser("bob", 1000)
+	for _, user := range []*models.User{&creator, &alice, &bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	market := modelstesting.GenerateMarket(8101, creator.Username)
+	market.IsResolved = false
+	if err := db.Create(&market).Error; err != nil {
+		t.Fatalf("create market: %v", err)
+	}
+
+	container := app.BuildApplicationWithConfigService(db, configsvc.NewStaticService(econConfig))
+	for _, req := range []dbets.PlaceRequest{
+		{Username: "alice", MarketID: uint(market.ID), Amount: 30, Outcome: "YES"},
+		{Username: "bob", MarketID: uint(market.ID), Amount: 20, Outcome: "NO"},
+	} {
+		if _, err := container.GetBetsService().Place(context.Background(), req); err != nil {
+			t.Fatalf("place %s bet: %v", req.Outcome, err)
+		}
+	}
+	return db, container, market.ID
+}
+
+func userBalance(t *testing.T, db *gorm.DB, username string) int64 {
+	t.Helper()
+	var user models.User
+	if err := db.Where("username = ?", username).First(&user).Error; err != nil {
+		t.Fatalf("load %s: %v", username, err)
+	}
+	return user.AccountBalance
+}
+
+func TestRolloverLiquidatesOpposingHoldersAtOneSnapshot(t *testing.T) {
+	db, container, marketID := seedOpposingHolders(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	before, err := container.GetMarketsService().GetMarketPositions(ctx, marketID)
+	if err != nil {
+		t.Fatalf("GetMarketPositions: %v", err)
+	}
+	values := make(map[string]int64)
+	balances := make(map[string]int64)
+	for _, position := range before {
+		values[position.Username] = position.Value
+		balances[position.Username] = userBalance(t, db, position.Username)
+	}
+	if values["alice"] <= 0 || values["bob"] <= 0 {
+		t.Fatalf("both holders should hold value, got %+v", values)
+	}
+
+	repo := NewGormRepository(db)
+	svc := dseasons.NewService(repo, container.GetAnalyticsService(), container.GetMarketsService(), seasonManagers{}, func() time.Time { return now })
+	actor := permissions.Subject{Username: "admin", Role: "ADMIN"}
+	season, err := svc.Create(ctx, actor, dseasons.Input{Name: "Q3", StartsAt: now.AddDate(0, 0, -2), EndsAt: now.Add(-time.Minute), ResetBalances: true, PositionPolicy: dseasons.PositionsLiquidate})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	archived, err := svc.Rollover(ctx, actor, season.ID)
+	if err != nil {
+		t.Fatalf("Rollover: %v", err)
+	}
+	if archived.PositionsLiquidated != 2 || archived.PositionsCarried != 0 {
+		t.Fatalf("unexpected archived season %+v", archived)
+	}
+
+	// Each holder is credited the value read before the rollover, whichever
+	// side of the market they held and whichever sale was written first.
+	var liquidations []models.SeasonLiquidation
+	db.Where("season_id = ?", season.ID).Order("id ASC").Find(&liquidations)
+	if len(liquidations) != 2 {
+		t.Fatalf("liquidations = %+v, want 2", liquidations)
+	}
+	for _, liquidation := range liquidations {
+		if liquidation.Value != values[liquidation.Username] || liquidation.MarketID != marketID {
+			t.Fatalf("liquidation %+v, want value %d", liquidation, values[liquidation.Username])
+		}
+	}
+	standings, _, err := repo.ListStandings(ctx, season.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("ListStandings: %v", err)
+	}
+	for _, standing := range standings {
+		if want := balances[standing.Username] + values[standing.Username]; standing.Username != "season_creator" && standing.FinalBalance != want {
+			t.Fatalf("%s final balance = %d, want %d", standing.Username, standing.FinalBalance, want)
+		}
+	}
+
+	after, err := container.GetMarketsService().GetMarketPositions(ctx, marketID)
+	if err != nil {
+		t.Fatalf("GetMarketPositions after rollover: %v", err)
+	}
+	for _, position := range after {
+		if position.YesSharesOwned != 0 || position.NoSharesOwned != 0 {
+			t.Fatalf("position should be closed after liquidation, got %+v", position)
+		}
+	}
+	for _, username := range []string{"alice", "bob"} {
+		if balance := userBalance(t, db, username); balance != 1000 {
+			t.Fatalf("%s balance = %d, want reset to 1000", username, balance)
+		}
+	}
+	// The seeded market skipped its creation charge, so only the holders'
+	// balances are expected to reconcile.
+	report, err := container.GetAnalyticsService().Reconcile(ctx, now)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	for _, discrepancy := range report.Discrepancies {
+		if discrepancy.Username == "alice" || discrepancy.Username == "bob" {
+			t.Fatalf("liquidation credit should reconcile, got %+v", discrepancy)
+		}
+	}
+}
+
+func TestArchiveSeasonRollsBackLiquidations(t *testing.T) {
+	db, container, marketID := seedOpposingHolders(t)
+	ctx := context.Background()
+	repo := NewGormRepository(db)
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	season := &dseasons.Season{Name: "Q3", StartsAt: start, EndsAt: start.AddDate(0, 3, 0), ResetBalances: true, PositionPolicy: dseasons.PositionsLiquidate, CreatedBy: "admin"}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason: %v", err)
+	}
+	snapshotBetID, err := repo.LatestBetID(ctx)
+	if err != nil || snapshotBetID == 0 {
+		t.Fatalf("LatestBetID = %d, %v", snapshotBetID, err)
+	}
+	aliceBalance, bobBalance := userBalance(t, db, "alice"), userBalance(t, db, "bob")
+	archive := dseasons.Archive{
+		SeasonID:      season.ID,
+		ResetBalances: true,
+		SnapshotBetID: snapshotBetID,
+		Liquidations: []dseasons.Liquidation{
+			{Username: "alice", MarketID: marketID, Outcome: "YES", Shares: 1, Value: 1},
+			{Username: "bob", MarketID: marketID, Outcome: "NO", Shares: 1, Value: 1},
+		},
+		ArchivedBy: "admin",
+		ArchivedAt: start.AddDate(0, 3, 1),
+		// The duplicate standing fails the insert after the liquidations.
+		Standings: []dseasons.Standing{{Rank: 1, Username: "alice"}, {Rank: 1, Username: "alice"}},
+	}
+	if _, err := repo.ArchiveSeason(ctx, archive); err == nil {
+		t.Fatal("ArchiveSeason with duplicate standings should fail")
+	}
+
+	if _, err := container.GetBetsService().Place(ctx, dbets.PlaceRequest{Username: "bob", MarketID: uint(marketID), Amount: 5, Outcome: "YES"}); err != nil {
+		t.Fatalf("place late bet: %v", err)
+	}
+	archive.Standings = nil
+	if _, err := repo.ArchiveSeason(ctx, archive); !errors.Is(err, dseasons.ErrPositionsMoved) {
+		t.Fatalf("ArchiveSeason after a later bet = %v, want ErrPositionsMoved", err)
+	}
+
+	var liquidations, sales int64
+	db.Model(&models.SeasonLiquidation{}).Count(&liquidations)
+	db.Model(&models.Bet{}).Where("amount < 0").Count(&sales)
+	if liquidations != 0 || sales != 0 {
+		t.Fatalf("failed rollovers left %d liquidations and %d sale bets", liquidations, sales)
+	}
+	if got := userBalance(t, db, "alice"); got != aliceBalance {
+		t.Fatalf("alice balance = %d, want %d", got, aliceBalance)
+	}
+	if got := userBalance(t, db, "bob"); got >= bobBalance {
+		t.Fatalf("bob balance = %d, want only the late bet's debit from %d", got, bobBalance)
+	}
+	reloaded, err := repo.GetSeason(ctx, season.ID)
+	if err != nil || reloaded.ArchivedAt != nil {
+		t.Fatalf("season should stay unarchived, got %+v err=%v", reloaded, err)
+	}
+}
@@ -0,0 +1,354 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dteams "socialpredict/internal/domain/teams"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// GormRepository implements the teams domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dteams.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based teams repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// CreateTeam inserts the team and its first owner together.
+func (r *GormRepository) CreateTeam(ctx context.Context, team *dteams.Team, owner dteams.Member) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		if err := tx.Model(&models.Team{}).Where("slug = ?", team.Slug).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return dteams.ErrSlugTaken
+		}
+		row := models.Team{Slug: team.Slug, Name: team.Name, Description: team.Description, CreatedBy: team.CreatedBy, CreatedAt: team.CreatedAt}
+		if err := tx.Create(&row).Error; err != nil {
+			return err
+		}
+		team.ID = row.ID
+		owner.TeamID = row.ID
+		return addMember(tx, owner)
+	})
+}
+
+// UpdateTeam saves the team's name and description.
+func (r *GormRepository) UpdateTeam(ctx context.Context, team *dteams.Team) error {
+	return r.db.WithContext(ctx).Model(&models.Team{}).Where("id = ?", team.ID).Updates(map[string]any{
+		"name":        team.Name,
+		"description": team.Description,
+	}).Error
+}
+
+// GetTeamBySlug returns the team with its member count or ErrTeamNotFound.
+func (r *GormRepository) GetTeamBySlug(ctx context.Context, slug string) (*dteams.Team, error) {
+	var row models.Team
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, dteams.ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	counts, err := r.memberCounts(ctx, row.ID)
+	if err != nil {
+		return nil, err
+	}
+	team := modelToTeam(&row, counts[row.ID])
+	return &team, nil
+}
+
+// ListTeams returns every team with its member count, by name.
+func (r *GormRepository) ListTeams(ctx context.Context) ([]dteams.Team, error) {
+	var rows []models.Team
+	if err := r.db.WithContext(ctx).Order("name ASC, id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts, err := r.memberCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]dteams.Team, 0, len(rows))
+	for i := range rows {
+		teams = append(teams, modelToTeam(&rows[i], counts[rows[i].ID]))
+	}
+	return teams, nil
+}
+
+// ListMembers returns the team's members, owners first.
+func (r *GormRepository) ListMembers(ctx context.Context, teamID int64) ([]dteams.Member, error) {
+	var rows []models.TeamMember
+	if err := r.db.WithContext(ctx).Where("team_id = ?", teamID).
+		Order("CASE WHEN role = 'owner' THEN 0 ELSE 1 END, joined_at ASC, id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	members := make([]dteams.Member, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, modelToMember(row))
+	}
+	return members, nil
+}
+
+// GetMembership returns the user's membership, or nil.
+func (r *GormRepository) GetMembership(ctx context.Context, username string) (*dteams.Member, error) {
+	var row models.TeamMember
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	member := modelToMember(row)
+	return &member, nil
+}
+
+// AddMember inserts the membership.
+func (r *GormRepository) AddMember(ctx context.Context, member dteams.Member) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return addMember(tx, member)
+	})
+}
+
+// RemoveMember deletes the membership.
+func (r *GormRepository) RemoveMember(ctx context.Context, teamID int64, username string) error {
+	result := r.db.WithContext(ctx).Where("team_id = ? AND username = ?", teamID, username).Delete(&models.TeamMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dteams.ErrNotMember
+	}
+	return nil
+}
+
+// CreateInvite inserts the invite and sets its ID.
+func (r *GormRepository) CreateInvite(ctx context.Context, invite *dteams.Invite) error {
+	row := models.TeamInvite{
+		TeamID:    invite.TeamID,
+		Username:  invite.Username,
+		InvitedBy: invite.InvitedBy,
+		Status:    string(invite.Status),
+		CreatedAt: invite.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	invite.ID = row.ID
+	return nil
+}
+
+// FindPendingInvite returns the user's pending invite to the team, or nil.
+func (r *GormRepository) FindPendingInvite(ctx context.Context, teamID int64, username string) (*dteams.Invite, error) {
+	invites, err := r.invites(r.db.WithContext(ctx).Where("team_invites.team_id = ? AND team_invites.username = ? AND team_invites.status = ?", teamID, username, string(dteams.InvitePending)))
+	if err != nil || len(invites) == 0 {
+		return nil, err
+	}
+	return &invites[0], nil
+}
+
+// ListPendingInvites returns the user's pending invites, newest first.
+func (r *GormRepository) ListPendingInvites(ctx context.Context, username string) ([]dteams.Invite, error) {
+	return r.invites(r.db.WithContext(ctx).Where("team_invites.username = ? AND team_invites.status = ?", username, string(dteams.InvitePending)))
+}
+
+// AnswerInvite records the answer and, when accepted, adds the membership.
+func (r *GormRepository) AnswerInvite(ctx context.Context, inviteID int64, username string, status dteams.InviteStatus, at time.Time) (*dteams.Invite, error) {
+	var row models.TeamInvite
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND username = ? AND status = ?", inviteID, username, string(dteams.InvitePending)).First(&row).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dteams.ErrInviteNotFound
+			}
+			return err
+		}
+		row.Status = string(status)
+		row.RespondedAt = &at
+		if err := tx.Model(&models.TeamInvite{}).Where("id = ?", row.ID).Updates(map[string]any{
+			"status":       row.Status,
+			"responded_at": at,
+		}).Error; err != nil {
+			return err
+		}
+		if status != dteams.InviteAccepted {
+			return nil
+		}
+		if err := addMember(tx, dteams.Member{TeamID: row.TeamID, Username: username, Role: dteams.RoleMember, JoinedAt: at}); err != nil {
+			return err
+		}
+		// Joining a team settles the user's other open invitations.
+		return tx.Model(&models.TeamInvite{}).
+			Where("username = ? AND status = ? AND id <> ?", username, string(dteams.InvitePending), row.ID).
+			Updates(map[string]any{"status": string(dteams.InviteDeclined), "responded_at": at}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	invites, err := r.invites(r.db.WithContext(ctx).Where("team_invites.id = ?", row.ID))
+	if err != nil || len(invites) == 0 {
+		return nil, err
+	}
+	return &invites[0], nil
+}
+
+// RestrictMarket makes the market team-only.
+func (r *GormRepository) RestrictMarket(ctx context.Context, teamID, marketID int64, by string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.TeamMarket
+		err := tx.Where("market_id = ?", marketID).First(&existing).Error
+		if err == nil {
+			if existing.TeamID == teamID {
+				return nil
+			}
+			return dteams.ErrInvalidState
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		var outsiders int64
+		if err := tx.Model(&models.Bet{}).
+			Where("market_id = ?", marketID).
+			Where("username NOT IN (?)", tx.Model(&models.TeamMember{}).Select("username").Where("team_id = ?", teamID)).
+			Count(&outsiders).Error; err != nil {
+			return err
+		}
+		if outsiders > 0 {
+			return dteams.ErrInvalidState
+		}
+		return tx.Create(&models.TeamMarket{MarketID: marketID, TeamID: teamID, CreatedBy: by, CreatedAt: at}).Error
+	})
+}
+
+// UnrestrictMarket removes the team-only restriction.
+func (r *GormRepository) UnrestrictMarket(ctx context.Context, teamID, marketID int64) error {
+	result := r.db.WithContext(ctx).Where("team_id = ? AND market_id = ?", teamID, marketID).Delete(&models.TeamMarket{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dteams.ErrInvalidState
+	}
+	return nil
+}
+
+// MarketTeam returns the team a market is restricted to, or 0.
+func (r *GormRepository) MarketTeam(ctx context.Context, marketID int64) (int64, error) {
+	var ids []int64
+	if err := r.db.WithContext(ctx).Model(&models.TeamMarket{}).Where("market_id = ?", marketID).Limit(1).Pluck("team_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// ListTeamMarketIDs returns the team's team-only markets.
+func (r *GormRepository) ListTeamMarketIDs(ctx context.Context, teamID int64) ([]int64, error) {
+	ids := []int64{}
+	err := r.db.WithContext(ctx).Model(&models.TeamMarket{}).Where("team_id = ?", teamID).Order("market_id ASC").Pluck("market_id", &ids).Error
+	return ids, err
+}
+
+type inviteRow struct {
+	models.TeamInvite
+	TeamSlug string
+	TeamName string
+}
+
+func (r *GormRepository) invites(query *gorm.DB) ([]dteams.Invite, error) {
+	var rows []inviteRow
+	if err := query.Table("team_invites").
+		Select("team_invites.*, teams.slug AS team_slug, teams.name AS team_name").
+		Joins("JOIN teams ON teams.id = team_invites.team_id").
+		Order("team_invites.created_at DESC, team_invites.id DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	invites := make([]dteams.Invite, 0, len(rows))
+	for _, row := range rows {
+		invites = append(invites, dteams.Invite{
+			ID:          row.ID,
+			TeamID:      row.TeamID,
+			TeamSlug:    row.TeamSlug,
+			TeamName:    row.TeamName,
+			Username:    row.Username,
+			InvitedBy:   row.InvitedBy,
+			Status:      dteams.InviteStatus(row.Status),
+			CreatedAt:   row.CreatedAt,
+			RespondedAt: row.RespondedAt,
+		})
+	}
+	return invites, nil
+}
+
+func (r *GormRepository) memberCounts(ctx context.Context, teamIDs ...int64) (map[int64]int, error) {
+	type countRow struct {
+		TeamID int64
+		Count  int
+	}
+	query := r.db.WithContext(ctx).Model(&models.TeamMember{}).Select("team_id, COUNT(*) AS count").Group("team_id")
+	if len(teamIDs) > 0 {
+		query = query.Where("team_id IN ?", teamIDs)
+	}
+	var rows []countRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]int, len(rows))
+	for _, row := range rows {
+		counts[row.TeamID] = row.Count
+	}
+	return counts, nil
+}
+
+// addMember inserts a membership, reporting an existing one as
+// ErrAlreadyInTeam.
+func addMember(tx *gorm.DB, member dteams.Member) error {
+	var existing int64
+	if err := tx.Model(&models.TeamMember{}).Where("username = ?", member.Username).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return dteams.ErrAlreadyInTeam
+	}
+	return tx.Create(&models.TeamMember{
+		TeamID:   member.TeamID,
+		Username: member.Username,
+		Role:     string(member.Role),
+		JoinedAt: member.JoinedAt,
+	}).Error
+}
+
+func modelToTeam(row *models.Team, members int) dteams.Team {
+	return dteams.Team{
+		ID:          row.ID,
+		Slug:        row.Slug,
+		Name:        row.Name,
+		Description: row.Description,
+		CreatedBy:   row.CreatedBy,
+		CreatedAt:   row.CreatedAt,
+		MemberCount: members,
+	}
+}
+
+func modelToMember(row models.TeamMember) dteams.Member {
+	return dteams.Member{
+		TeamID:   row.TeamID,
+		Username: row.Username,
+		Role:     dteams.Role(row.Role),
+		JoinedAt: row.JoinedAt,
+	}
+}
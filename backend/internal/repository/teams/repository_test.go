@@ -0,0 +1,152 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dteams "socialpredict/internal/domain/teams"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryMembershipAndInvites(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 7, 7, 9, 0, 0, 0, time.UTC)
+
+	red := &dteams.Team{Slug: "red", Name: "Red", CreatedBy: "admin", CreatedAt: now}
+	if err := repo.CreateTeam(ctx, red, dteams.Member{Username: "alice", Role: dteams.RoleOwner, JoinedAt: now}); err != nil || red.ID == 0 {
+		t.Fatalf("CreateTeam = %v", err)
+	}
+	if err := repo.CreateTeam(ctx, &dteams.Team{Slug: "red", Name: "Again", CreatedBy: "admin", CreatedAt: now}, dteams.Member{Username: "bob", Role: dteams.RoleOwner}); !errors.Is(err, dteams.ErrSlugTaken) {
+		t.Fatalf("duplicate slug error = %v", err)
+	}
+	blue := &dteams.Team{Slug: "blue", Name: "Blue", CreatedBy: "admin", CreatedAt: now}
+	if err := repo.CreateTeam(ctx, blue, dteams.Member{Username: "alice", Role: dteams.RoleOwner}); !errors.Is(err, dteams.ErrAlreadyInTeam) {
+		t.Fatalf("owner already in a team error = %v", err)
+	}
+	if err := repo.CreateTeam(ctx, blue, dteams.Member{Username: "carol", Role: dteams.RoleOwner, JoinedAt: now}); err != nil {
+		t.Fatalf("CreateTeam blue = %v", err)
+	}
+
+	toRed := &dteams.Invite{TeamID: red.ID, Username: "bob", InvitedBy: "alice", Status: dteams.InvitePending, CreatedAt: now}
+	toBlue := &dteams.Invite{TeamID: blue.ID, Username: "bob", InvitedBy: "carol", Status: dteams.InvitePending, CreatedAt: now.Add(time.Minute)}
+	for _, invite := range []*dteams.Invite{toRed, toBlue} {
+		if err := repo.CreateInvite(ctx, invite); err != nil {
+			t.Fatalf("CreateInvite = %v", err)
+		}
+	}
+	pending, err := repo.ListPendingInvites(ctx, "bob")
+	if err != nil || len(pending) != 2 || pending[0].TeamSlug != "blue" || pending[1].TeamName != "Red" {
+		t.Fatalf("ListPendingInvites = %+v, %v", pending, err)
+	}
+	if found, _ := repo.FindPendingInvite(ctx, red.ID, "bob"); found == nil || found.ID != toRed.ID {
+		t.Fatalf("FindPendingInvite = %+v", found)
+	}
+
+	if _, err := repo.AnswerInvite(ctx, toRed.ID, "mallory", dteams.InviteAccepted, now); !errors.Is(err, dteams.ErrInviteNotFound) {
+		t.Fatalf("answering someone else's invite error = %v", err)
+	}
+	accepted, err := repo.AnswerInvite(ctx, toRed.ID, "bob", dteams.InviteAccepted, now.Add(time.Hour))
+	if err != nil || accepted.Status != dteams.InviteAccepted || accepted.RespondedAt == nil || accepted.TeamSlug != "red" {
+		t.Fatalf("AnswerInvite = %+v, %v", accepted, err)
+	}
+	if pending, _ := repo.ListPendingInvites(ctx, "bob"); len(pending) != 0 {
+		t.Fatalf("joining a team should settle other invites, got %+v", pending)
+	}
+	if _, err := repo.AnswerInvite(ctx, toBlue.ID, "bob", dteams.InviteAccepted, now); !errors.Is(err, dteams.ErrInviteNotFound) {
+		t.Fatalf("settled invite error = %v", err)
+	}
+
+	membership, err := repo.GetMembership(ctx, "bob")
+	if err != nil || membership == nil || membership.TeamID != red.ID || membership.Role != dteams.RoleMember {
+		t.Fatalf("GetMembership = %+v, %v", membership, err)
+	}
+	members, err := repo.ListMembers(ctx, red.ID)
+	if err != nil || len(members) != 2 || members[0].Username != "alice" {
+		t.Fatalf("ListMembers = %+v, %v", members, err)
+	}
+	teams, err := repo.ListTeams(ctx)
+	if err != nil || len(teams) != 2 || teams[0].Slug != "blue" || teams[1].MemberCount != 2 {
+		t.Fatalf("ListTeams = %+v, %v", teams, err)
+	}
+
+	if err := repo.RemoveMember(ctx, red.ID, "bob"); err != nil {
+		t.Fatalf("RemoveMember = %v", err)
+	}
+	if err := repo.RemoveMember(ctx, red.ID, "bob"); !errors.Is(err, dteams.ErrNotMember) {
+		t.Fatalf("removing twice error = %v", err)
+	}
+	if membership, _ := repo.GetMembership(ctx, "bob"); membership != nil {
+		t.Fatalf("expected no membership, got %+v", membership)
+	}
+}
+
+func TestGormRepositoryRestrictMarket(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 7, 7, 9, 0, 0, 0, time.UTC)
+
+	red := &dteams.Team{Slug: "red", Name: "Red", CreatedBy: "admin", CreatedAt: now}
+	if err := repo.CreateTeam(ctx, red, dteams.Member{Username: "alice", Role: dteams.RoleOwner, JoinedAt: now}); err != nil {
+		t.Fatalf("CreateTeam = %v", err)
+	}
+	blue := &dteams.Team{Slug: "blue", Name: "Blue", CreatedBy: "admin", CreatedAt: now}
+	if err := repo.CreateTeam(ctx, blue, dteams.Member{Username: "carol", Role: dteams.RoleOwner, JoinedAt: now}); err != nil {
+		t.Fatalf("CreateTeam = %v", err)
+	}
+
+	for _, username := range []string{"alice", "carol"} {
+		user := modelstesting.GenerateUser(username, 1000)
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	for _, id := range []int64{1, 2} {
+		market := modelstesting.GenerateMarket(id, "alice")
+		if err := db.Create(&market).Error; err != nil {
+			t.Fatalf("create market: %v", err)
+		}
+	}
+	for _, bet := range []models.Bet{
+		modelstesting.GenerateBet(10, "YES", "alice", 1, time.Minute),
+		modelstesting.GenerateBet(10, "YES", "carol", 2, time.Minute),
+	} {
+		if err := db.Create(&bet).Error; err != nil {
+			t.Fatalf("create bet: %v", err)
+		}
+	}
+
+	if err := repo.RestrictMarket(ctx, red.ID, 2, "alice", now); !errors.Is(err, dteams.ErrInvalidState) {
+		t.Fatalf("market with outside bets error = %v", err)
+	}
+	if err := repo.RestrictMarket(ctx, red.ID, 1, "alice", now); err != nil {
+		t.Fatalf("RestrictMarket = %v", err)
+	}
+	if err := repo.RestrictMarket(ctx, red.ID, 1, "alice", now); err != nil {
+		t.Fatalf("restricting again should be a no-op, got %v", err)
+	}
+	if err := repo.RestrictMarket(ctx, blue.ID, 1, "carol", now); !errors.Is(err, dteams.ErrInvalidState) {
+		t.Fatalf("another team's market error = %v", err)
+	}
+	if teamID, err := repo.MarketTeam(ctx, 1); err != nil || teamID != red.ID {
+		t.Fatalf("MarketTeam = %d, %v", teamID, err)
+	}
+	if ids, err := repo.ListTeamMarketIDs(ctx, red.ID); err != nil || len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("ListTeamMarketIDs = %v, %v", ids, err)
+	}
+
+	if err := repo.UnrestrictMarket(ctx, blue.ID, 1); !errors.Is(err, dteams.ErrInvalidState) {
+		t.Fatalf("unrestricting another team's market error = %v", err)
+	}
+	if err := repo.UnrestrictMarket(ctx, red.ID, 1); err != nil {
+		t.Fatalf("UnrestrictMarket = %v", err)
+	}
+	if teamID, _ := repo.MarketTeam(ctx, 1); teamID != 0 {
+		t.Fatalf("expected open market, got team %d", teamID)
+	}
+}
@@ -0,0 +1,115 @@
+package users
+
+import (
+	"context"
+	"errors"
+
+	dusers "socialpredict/internal/domain/users"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ dusers.AccountStatusAuditRepository = (*GormRepository)(nil)
+
+// UpdateAccountStatus loads the user, lets the domain apply the status
+// change, and saves the user together with the returned audit record in one
+// transaction so neither commits without the other.
+func (r *GormRepository) UpdateAccountStatus(ctx context.Context, username string, update dusers.AccountStatusUpdateFunc) (*dusers.User, error) {
+	if update == nil {
+		return nil, dusers.ErrInvalidUserData
+	}
+
+	var updated *dusers.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("username = ?", username)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		var row models.User
+		if err := query.First(&row).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dusers.ErrUserNotFound
+			}
+			return err
+		}
+
+		user := r.modelToDomain(&row)
+		record, err := update(user)
+		if err != nil {
+			return err
+		}
+		dbUser := r.domainToModel(user)
+		if err := tx.Save(&dbUser).Error; err != nil {
+			return err
+		}
+		if err := NewGormRepository(tx).CreateAccountStatusAudit(ctx, record); err != nil {
+			return err
+		}
+
+		updated = user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// CreateAccountStatusAudit appends one suspension, ban, or reinstatement record.
+func (r *GormRepository) CreateAccountStatusAudit(ctx context.Context, record *dusers.AccountStatusAuditRecord) error {
+	if record == nil {
+		return dusers.ErrInvalidUserData
+	}
+
+	row := models.AccountStatusAudit{
+		Username:      record.Username,
+		ActorUsername: record.ActorUsername,
+		Action:        string(record.Action),
+		FromStatus:    string(record.FromStatus),
+		ToStatus:      string(record.ToStatus),
+		Reason:        record.Reason,
+		ExpiresAt:     cloneTimePtr(record.ExpiresAt),
+		CreatedAt:     record.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+
+	record.ID = row.ID
+	record.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// ListAccountStatusAudits returns an account's status changes newest first.
+func (r *GormRepository) ListAccountStatusAudits(ctx context.Context, username string, limit, offset int) ([]*dusers.AccountStatusAuditRecord, error) {
+	query := r.db.WithContext(ctx).Model(&models.AccountStatusAudit{}).Where("username = ?", username)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var rows []models.AccountStatusAudit
+	if err := query.Order("created_at DESC").Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]*dusers.AccountStatusAuditRecord, len(rows))
+	for i := range rows {
+		records[i] = &dusers.AccountStatusAuditRecord{
+			ID:            rows[i].ID,
+			Username:      rows[i].Username,
+			ActorUsername: rows[i].ActorUsername,
+			Action:        dusers.AccountStatusAction(rows[i].Action),
+			FromStatus:    dusers.AccountStatus(rows[i].FromStatus),
+			ToStatus:      dusers.AccountStatus(rows[i].ToStatus),
+			Reason:        rows[i].Reason,
+			ExpiresAt:     cloneTimePtr(rows[i].ExpiresAt),
+			CreatedAt:     rows[i].CreatedAt,
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,91 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	dusers "socialpredict/internal/domain/users"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+var _ dusers.ExternalIdentityRepository = (*GormRepository)(nil)
+
+// GetUserByExternalIdentity loads the account linked to an identity provider subject.
+func (r *GormRepository) GetUserByExternalIdentity(ctx context.Context, issuer, subject string) (*dusers.User, error) {
+	var identity models.UserExternalIdentity
+	err := r.db.WithContext(ctx).
+		Where("issuer = ? AND subject = ?", issuer, subject).
+		First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dusers.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return r.GetByUsername(ctx, identity.Username)
+}
+
+// GetUserByEmail loads an account by case-insensitive email address.
+func (r *GormRepository) GetUserByEmail(ctx context.Context, email string) (*dusers.User, error) {
+	var dbUser models.User
+	err := r.db.WithContext(ctx).
+		Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).
+		First(&dbUser).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dusers.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return r.modelToDomain(&dbUser), nil
+}
+
+// LinkExternalIdentity records a new link between an account and a provider subject.
+func (r *GormRepository) LinkExternalIdentity(ctx context.Context, identity *dusers.ExternalIdentity) error {
+	if identity == nil {
+		return dusers.ErrInvalidUserData
+	}
+	row := domainExternalIdentityToModel(identity)
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	identity.ID = row.ID
+	identity.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// TouchExternalIdentity records the latest sign-in through a linked identity.
+func (r *GormRepository) TouchExternalIdentity(ctx context.Context, issuer, subject string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.UserExternalIdentity{}).
+		Where("issuer = ? AND subject = ?", issuer, subject).
+		Update("last_login_at", at).Error
+}
+
+// CreateUserWithExternalIdentity provisions an account and its identity link in one transaction.
+func (r *GormRepository) CreateUserWithExternalIdentity(ctx context.Context, user *dusers.User, identity *dusers.ExternalIdentity) error {
+	if user == nil || identity == nil {
+		return dusers.ErrInvalidUserData
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := NewGormRepository(tx).Create(ctx, user); err != nil {
+			return err
+		}
+		identity.Username = user.Username
+		return NewGormRepository(tx).LinkExternalIdentity(ctx, identity)
+	})
+}
+
+func domainExternalIdentityToModel(identity *dusers.ExternalIdentity) models.UserExternalIdentity {
+	return models.UserExternalIdentity{
+		ID:          identity.ID,
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		Username:    identity.Username,
+		Email:       identity.Email,
+		LastLoginAt: cloneTimePtr(identity.LastLoginAt),
+	}
+}
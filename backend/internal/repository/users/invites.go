@@ -0,0 +1,201 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dusers "socialpredict/internal/domain/users"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ dusers.InviteRepository = (*GormRepository)(nil)
+
+// CreateInvite persists a newly minted invite.
+func (r *GormRepository) CreateInvite(ctx context.Context, invite *dusers.Invite) error {
+	if invite == nil {
+		return dusers.ErrInvalidUserData
+	}
+
+	dbInvite := domainInviteToModel(invite)
+	if err := r.db.WithContext(ctx).Create(&dbInvite).Error; err != nil {
+		return err
+	}
+
+	invite.ID = dbInvite.ID
+	invite.CreatedAt = dbInvite.CreatedAt
+	invite.UpdatedAt = dbInvite.UpdatedAt
+	return nil
+}
+
+// ListInvites returns invites newest first.
+func (r *GormRepository) ListInvites(ctx context.Context, filters dusers.InviteListFilters) ([]*dusers.Invite, error) {
+	query := r.inviteQuery(ctx, filters)
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	var rows []models.UserInvite
+	if err := query.Order("created_at DESC").Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	invites := make([]*dusers.Invite, len(rows))
+	for i := range rows {
+		invites[i] = modelInviteToDomain(&rows[i])
+	}
+	return invites, nil
+}
+
+// CountInvites returns how many invites match the filters, ignoring paging.
+func (r *GormRepository) CountInvites(ctx context.Context, filters dusers.InviteListFilters) (int64, error) {
+	var total int64
+	if err := r.inviteQuery(ctx, filters).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *GormRepository) inviteQuery(ctx context.Context, filters dusers.InviteListFilters) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.UserInvite{})
+	if filters.CreatedBy != "" {
+		query = query.Where("created_by = ?", filters.CreatedBy)
+	}
+	return query
+}
+
+// GetInvite loads one invite by ID.
+func (r *GormRepository) GetInvite(ctx context.Context, id int64) (*dusers.Invite, error) {
+	var row models.UserInvite
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dusers.ErrInviteNotFound
+		}
+		return nil, err
+	}
+	return modelInviteToDomain(&row), nil
+}
+
+// RevokeInvite marks an invite as revoked.
+func (r *GormRepository) RevokeInvite(ctx context.Context, id int64, actorUsername string, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.UserInvite{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{
+			"revoked_at": revokedAt,
+			"revoked_by": actorUsername,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dusers.ErrInviteNotFound
+	}
+	return nil
+}
+
+// RedeemInvite loads the invite, lets the domain build the account, and then
+// creates the user, consumes one use, and records the redemption in a single
+// transaction.
+func (r *GormRepository) RedeemInvite(ctx context.Context, codeHash string, build dusers.InviteRedemptionFunc) (*dusers.User, error) {
+	if build == nil {
+		return nil, dusers.ErrInvalidUserData
+	}
+
+	var created *dusers.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("code_hash = ?", codeHash)
+		if tx.Dialector.Name() == "postgres" {
+			// Lock the invite row so concurrent registrations cannot both take the last use.
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+
+		var row models.UserInvite
+		if err := query.First(&row).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dusers.ErrInviteNotFound
+			}
+			return err
+		}
+
+		user, err := build(modelInviteToDomain(&row))
+		if err != nil {
+			return err
+		}
+
+		consumed := tx.Model(&models.UserInvite{}).
+			Where("id = ? AND use_count < max_uses AND revoked_at IS NULL", row.ID).
+			Update("use_count", gorm.Expr("use_count + 1"))
+		if consumed.Error != nil {
+			return consumed.Error
+		}
+		if consumed.RowsAffected == 0 {
+			return dusers.ErrInviteUnavailable
+		}
+
+		if err := NewGormRepository(tx).Create(ctx, user); err != nil {
+			return err
+		}
+
+		redemption := models.UserInviteRedemption{InviteID: row.ID, Username: user.Username}
+		if err := tx.Create(&redemption).Error; err != nil {
+			return err
+		}
+
+		created = user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func domainInviteToModel(invite *dusers.Invite) models.UserInvite {
+	return models.UserInvite{
+		ID:                    invite.ID,
+		CodeHash:              invite.CodeHash,
+		CodePrefix:            invite.CodePrefix,
+		CreatedBy:             invite.CreatedBy,
+		UserType:              invite.UserType,
+		InitialAccountBalance: cloneInt64Ptr(invite.InitialAccountBalance),
+		MaxUses:               invite.MaxUses,
+		UseCount:              invite.UseCount,
+		ExpiresAt:             cloneTimePtr(invite.ExpiresAt),
+		RevokedAt:             cloneTimePtr(invite.RevokedAt),
+		RevokedBy:             invite.RevokedBy,
+		Note:                  invite.Note,
+	}
+}
+
+func modelInviteToDomain(row *models.UserInvite) *dusers.Invite {
+	return &dusers.Invite{
+		ID:                    row.ID,
+		CodeHash:              row.CodeHash,
+		CodePrefix:            row.CodePrefix,
+		CreatedBy:             row.CreatedBy,
+		UserType:              row.UserType,
+		InitialAccountBalance: cloneInt64Ptr(row.InitialAccountBalance),
+		MaxUses:               row.MaxUses,
+		UseCount:              row.UseCount,
+		ExpiresAt:             cloneTimePtr(row.ExpiresAt),
+		RevokedAt:             cloneTimePtr(row.RevokedAt),
+		RevokedBy:             row.RevokedBy,
+		Note:                  row.Note,
+		CreatedAt:             row.CreatedAt,
+		UpdatedAt:             row.UpdatedAt,
+	}
+}
+
+func cloneInt64Ptr(value *int64) *int64 {
+	if value == nil {
+		return nil
+	}
+	copy := *value
+	return &copy
+}
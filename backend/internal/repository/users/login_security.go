@@ -0,0 +1,167 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dusers "socialpredict/internal/domain/users"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ dusers.LoginSecurityRepository = (*GormRepository)(nil)
+
+// GetLoginThrottle returns the stored failure counters for username, or nil when none exist.
+func (r *GormRepository) GetLoginThrottle(ctx context.Context, username string) (*dusers.LoginThrottle, error) {
+	var row models.UserLoginThrottle
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return modelLoginThrottleToDomain(&row), nil
+}
+
+// UpdateLoginThrottle creates the throttle row if needed, then lets the domain
+// mutate it and saves the result in one transaction so concurrent failures
+// for the same username are all counted.
+func (r *GormRepository) UpdateLoginThrottle(ctx context.Context, username string, update dusers.LoginThrottleUpdateFunc) (*dusers.LoginThrottle, error) {
+	if update == nil {
+		return nil, dusers.ErrInvalidUserData
+	}
+
+	var updated *dusers.LoginThrottle
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seed := models.UserLoginThrottle{Username: username}
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "username"}}, DoNothing: true}).Create(&seed).Error; err != nil {
+			return err
+		}
+
+		query := tx.Where("username = ?", username)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		var row models.UserLoginThrottle
+		if err := query.First(&row).Error; err != nil {
+			return err
+		}
+
+		throttle := modelLoginThrottleToDomain(&row)
+		if err := update(throttle); err != nil {
+			return err
+		}
+
+		row.FailedAttempts = throttle.FailedAttempts
+		row.LastFailedAt = cloneTimePtr(throttle.LastFailedAt)
+		row.NextAttemptAt = cloneTimePtr(throttle.NextAttemptAt)
+		row.LockedUntil = cloneTimePtr(throttle.LockedUntil)
+		if err := tx.Save(&row).Error; err != nil {
+			return err
+		}
+
+		updated = modelLoginThrottleToDomain(&row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// ClearLoginThrottle removes the failure counters for username.
+func (r *GormRepository) ClearLoginThrottle(ctx context.Context, username string) error {
+	return r.db.WithContext(ctx).Where("username = ?", username).Delete(&models.UserLoginThrottle{}).Error
+}
+
+// PruneLoginThrottles deletes counters whose last failure is before
+// failedBefore, keeping any still inside a lockout at now.
+func (r *GormRepository) PruneLoginThrottles(ctx context.Context, failedBefore, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("last_failed_at < ?", failedBefore).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Delete(&models.UserLoginThrottle{}).Error
+}
+
+// CreateLoginAuditEvent appends one login audit event.
+func (r *GormRepository) CreateLoginAuditEvent(ctx context.Context, event *dusers.LoginAuditEvent) error {
+	if event == nil {
+		return dusers.ErrInvalidUserData
+	}
+
+	row := models.LoginAuditEvent{
+		Username:      event.Username,
+		Outcome:       string(event.Outcome),
+		Method:        event.Method,
+		Reason:        event.Reason,
+		IPAddress:     event.IPAddress,
+		UserAgent:     event.UserAgent,
+		ActorUsername: event.ActorUsername,
+		CreatedAt:     event.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+
+	event.ID = row.ID
+	event.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// ListLoginAuditEvents returns login events newest first.
+func (r *GormRepository) ListLoginAuditEvents(ctx context.Context, filters dusers.LoginAuditFilters) ([]*dusers.LoginAuditEvent, error) {
+	query := r.db.WithContext(ctx).Model(&models.LoginAuditEvent{})
+	if filters.Username != "" {
+		query = query.Where("username = ?", filters.Username)
+	}
+	if filters.Outcome != "" {
+		query = query.Where("outcome = ?", string(filters.Outcome))
+	}
+	if filters.IPAddress != "" {
+		query = query.Where("ip_address = ?", filters.IPAddress)
+	}
+	if filters.Since != nil {
+		query = query.Where("created_at >= ?", *filters.Since)
+	}
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	var rows []models.LoginAuditEvent
+	if err := query.Order("created_at DESC").Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*dusers.LoginAuditEvent, len(rows))
+	for i := range rows {
+		events[i] = &dusers.LoginAuditEvent{
+			ID:            rows[i].ID,
+			Username:      rows[i].Username,
+			Outcome:       dusers.LoginOutcome(rows[i].Outcome),
+			Method:        rows[i].Method,
+			Reason:        rows[i].Reason,
+			IPAddress:     rows[i].IPAddress,
+			UserAgent:     rows[i].UserAgent,
+			ActorUsername: rows[i].ActorUsername,
+			CreatedAt:     rows[i].CreatedAt,
+		}
+	}
+	return events, nil
+}
+
+func modelLoginThrottleToDomain(row *models.UserLoginThrottle) *dusers.LoginThrottle {
+	return &dusers.LoginThrottle{
+		Username:       row.Username,
+		FailedAttempts: row.FailedAttempts,
+		LastFailedAt:   cloneTimePtr(row.LastFailedAt),
+		NextAttemptAt:  cloneTimePtr(row.NextAttemptAt),
+		LockedUntil:    cloneTimePtr(row.LockedUntil),
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
@@ -20,11 +20,13 @@ type GormRepository struct {
 }
 
 type authenticatedUserRow struct {
-	Username           string
-	UserType           string
-	ModeratorStatus    string
-	Password           string
-	MustChangePassword bool
+	Username               string
+	UserType               string
+	ModeratorStatus        string
+	Password               string
+	MustChangePassword     bool
+	AccountStatus          string
+	AccountStatusExpiresAt *time.Time
 }
 
 // NewGormRepository creates a new GORM-based users repository
@@ -326,7 +328,7 @@ func (r *GormRepository) FindAuthenticatedUser(ctx context.Context, username str
 	var user authenticatedUserRow
 	if err := r.db.WithContext(ctx).
 		Table("users").
-		Select("username", "user_type", "moderator_status", "password", "must_change_password").
+		Select("username", "user_type", "moderator_status", "password", "must_change_password", "account_status", "account_status_expires_at").
 		Where("username = ?", username).
 		Take(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -336,11 +338,13 @@ func (r *GormRepository) FindAuthenticatedUser(ctx context.Context, username str
 	}
 
 	return &boundary.AuthenticatedUser{
-		Username:           user.Username,
-		UserType:           user.UserType,
-		ModeratorStatus:    string(dusers.NormalizeModeratorStatus(user.UserType, user.ModeratorStatus)),
-		PasswordHash:       user.Password,
-		MustChangePassword: user.MustChangePassword,
+		Username:               user.Username,
+		UserType:               user.UserType,
+		ModeratorStatus:        string(dusers.NormalizeModeratorStatus(user.UserType, user.ModeratorStatus)),
+		PasswordHash:           user.Password,
+		MustChangePassword:     user.MustChangePassword,
+		AccountStatus:          string(dusers.NormalizeAccountStatus(user.AccountStatus)),
+		AccountStatusExpiresAt: cloneTimePtr(user.AccountStatusExpiresAt),
 	}, nil
 }
 
@@ -402,9 +406,10 @@ func (r *GormRepository) domainToModel(user *dusers.User) models.User {
 			PersonalLink4:         user.PersonalLink4,
 		},
 		PrivateUser: models.PrivateUser{
-			Email:    user.Email,
-			APIKey:   user.APIKey,
-			Password: user.PasswordHash,
+			Email:           user.Email,
+			APIKey:          user.APIKey,
+			Password:        user.PasswordHash,
+			EmailVerifiedAt: cloneTimePtr(user.EmailVerifiedAt),
 		},
 		ModeratorGovernance: models.ModeratorGovernance{
 			ModeratorStatus:           string(dusers.NormalizeModeratorStatus(user.UserType, string(user.ModeratorStatus))),
@@ -412,6 +417,13 @@ func (r *GormRepository) domainToModel(user *dusers.User) models.User {
 			ModeratorSuspendedBy:      user.ModeratorSuspendedBy,
 			ModeratorSuspendedAt:      user.ModeratorSuspendedAt,
 		},
+		AccountStanding: models.AccountStanding{
+			AccountStatus:          string(dusers.NormalizeAccountStatus(string(user.AccountStatus))),
+			AccountStatusReason:    user.AccountStatusReason,
+			AccountStatusBy:        user.AccountStatusBy,
+			AccountStatusAt:        user.AccountStatusAt,
+			AccountStatusExpiresAt: user.AccountStatusExpiresAt,
+		},
 		MustChangePassword: user.MustChangePassword,
 	}
 }
@@ -423,6 +435,7 @@ func (r *GormRepository) modelToDomain(dbUser *models.User) *dusers.User {
 		Username:                  dbUser.Username,
 		DisplayName:               dbUser.DisplayName,
 		Email:                     dbUser.Email,
+		EmailVerifiedAt:           cloneTimePtr(dbUser.EmailVerifiedAt),
 		PasswordHash:              dbUser.Password,
 		UserType:                  dbUser.UserType,
 		InitialAccountBalance:     dbUser.InitialAccountBalance,
@@ -439,6 +452,11 @@ func (r *GormRepository) modelToDomain(dbUser *models.User) *dusers.User {
 		ModeratorSuspensionReason: dbUser.ModeratorSuspensionReason,
 		ModeratorSuspendedBy:      dbUser.ModeratorSuspendedBy,
 		ModeratorSuspendedAt:      cloneTimePtr(dbUser.ModeratorSuspendedAt),
+		AccountStatus:             dusers.NormalizeAccountStatus(dbUser.AccountStatus),
+		AccountStatusReason:       dbUser.AccountStatusReason,
+		AccountStatusBy:           dbUser.AccountStatusBy,
+		AccountStatusAt:           cloneTimePtr(dbUser.AccountStatusAt),
+		AccountStatusExpiresAt:    cloneTimePtr(dbUser.AccountStatusExpiresAt),
 		CreatedAt:                 dbUser.CreatedAt,
 		UpdatedAt:                 dbUser.UpdatedAt,
 	}
@@ -3,6 +3,7 @@ package users
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -337,3 +338,233 @@ func TestGormRepositoryModeratorGovernanceRoundTrip(t *testing.T) {
 		t.Fatalf("unexpected stored audit: %+v", stored)
 	}
 }
+
+func TestGormRepositoryCountInvitesIgnoresPaging(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+
+	for i, creator := range []string{"admin", "admin", "admin", "mod"} {
+		invite := &dusers.Invite{
+			CodeHash:   fmt.Sprintf("hash-%d", i),
+			CodePrefix: fmt.Sprintf("p%d", i),
+			CreatedBy:  creator,
+			UserType:   string(dusers.UserTypeRegular),
+			MaxUses:    1,
+		}
+		if err := repo.CreateInvite(ctx, invite); err != nil {
+			t.Fatalf("CreateInvite returned error: %v", err)
+		}
+	}
+
+	filters := dusers.InviteListFilters{CreatedBy: "admin", Limit: 2, Offset: 1}
+	page, err := repo.ListInvites(ctx, filters)
+	if err != nil || len(page) != 2 {
+		t.Fatalf("ListInvites = %d invites, err=%v; want 2", len(page), err)
+	}
+	total, err := repo.CountInvites(ctx, filters)
+	if err != nil || total != 3 {
+		t.Fatalf("CountInvites = %d, err=%v; want 3", total, err)
+	}
+}
+
+func TestGormRepositoryLoginThrottleLifecycle(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+
+	if throttle, err := repo.GetLoginThrottle(ctx, "alice"); err != nil || throttle != nil {
+		t.Fatalf("expected no throttle, got %+v err=%v", throttle, err)
+	}
+
+	lockedUntil := time.Now().Add(time.Hour).UTC()
+	for i := 0; i < 2; i++ {
+		_, err := repo.UpdateLoginThrottle(ctx, "alice", func(throttle *dusers.LoginThrottle) error {
+			throttle.FailedAttempts++
+			throttle.LockedUntil = &lockedUntil
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateLoginThrottle returned error: %v", err)
+		}
+	}
+
+	throttle, err := repo.GetLoginThrottle(ctx, "alice")
+	if err != nil || throttle == nil || throttle.FailedAttempts != 2 || throttle.LockedUntil == nil {
+		t.Fatalf("unexpected throttle: %+v err=%v", throttle, err)
+	}
+
+	if err := repo.ClearLoginThrottle(ctx, "alice"); err != nil {
+		t.Fatalf("ClearLoginThrottle returned error: %v", err)
+	}
+	if throttle, _ := repo.GetLoginThrottle(ctx, "alice"); throttle != nil {
+		t.Fatalf("expected cleared throttle, got %+v", throttle)
+	}
+}
+
+func TestGormRepositoryPruneLoginThrottlesKeepsRecentAndLockedRows(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 24, 9, 0, 0, 0, time.UTC)
+
+	seed := func(username string, failedAt time.Time, lockedUntil *time.Time) {
+		t.Helper()
+		_, err := repo.UpdateLoginThrottle(ctx, username, func(throttle *dusers.LoginThrottle) error {
+			throttle.FailedAttempts = 1
+			throttle.LastFailedAt = &failedAt
+			throttle.LockedUntil = lockedUntil
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateLoginThrottle(%s) returned error: %v", username, err)
+		}
+	}
+	stillLocked := now.Add(time.Hour)
+	seed("stale", now.Add(-48*time.Hour), nil)
+	seed("locked", now.Add(-48*time.Hour), &stillLocked)
+	seed("recent", now.Add(-time.Hour), nil)
+
+	if err := repo.PruneLoginThrottles(ctx, now.Add(-24*time.Hour), now); err != nil {
+		t.Fatalf("PruneLoginThrottles returned error: %v", err)
+	}
+
+	var remaining []string
+	db.Model(&models.UserLoginThrottle{}).Order("username").Pluck("username", &remaining)
+	if len(remaining) != 2 || remaining[0] != "locked" || remaining[1] != "recent" {
+		t.Fatalf("remaining throttles = %v, want [locked recent]", remaining)
+	}
+}
+
+func TestGormRepositoryListLoginAuditEventsFilters(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	base := time.Date(2026, 6, 24, 9, 0, 0, 0, time.UTC)
+
+	events := []*dusers.LoginAuditEvent{
+		{Username: "alice", Outcome: dusers.LoginOutcomeFailure, Method: dusers.LoginMethodPassword, IPAddress: "198.51.100.1", CreatedAt: base},
+		{Username: "alice", Outcome: dusers.LoginOutcomeSuccess, Method: dusers.LoginMethodPassword, IPAddress: "198.51.100.1", CreatedAt: base.Add(time.Minute)},
+		{Username: "bob", Outcome: dusers.LoginOutcomeFailure, Method: dusers.LoginMethodPassword, IPAddress: "198.51.100.2", CreatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, event := range events {
+		if err := repo.CreateLoginAuditEvent(ctx, event); err != nil {
+			t.Fatalf("CreateLoginAuditEvent returned error: %v", err)
+		}
+	}
+
+	got, err := repo.ListLoginAuditEvents(ctx, dusers.LoginAuditFilters{Outcome: dusers.LoginOutcomeFailure})
+	if err != nil {
+		t.Fatalf("ListLoginAuditEvents returned error: %v", err)
+	}
+	if len(got) != 2 || got[0].Username != "bob" {
+		t.Fatalf("expected newest failure first, got %+v", got)
+	}
+
+	since := base.Add(30 * time.Second)
+	got, err = repo.ListLoginAuditEvents(ctx, dusers.LoginAuditFilters{Username: "alice", Since: &since})
+	if err != nil || len(got) != 1 || got[0].Outcome != dusers.LoginOutcomeSuccess {
+		t.Fatalf("unexpected filtered events: %+v err=%v", got, err)
+	}
+}
+
+func TestGormRepositoryAccountStatusRoundTrip(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	at := time.Date(2026, 6, 26, 9, 0, 0, 0, time.UTC)
+	expires := at.Add(48 * time.Hour)
+
+	user := &dusers.User{
+		Username:               "suspended_user",
+		DisplayName:            "Suspended User",
+		Email:                  "suspended@example.com",
+		APIKey:                 "api-suspended",
+		PasswordHash:           "hash",
+		UserType:               string(dusers.UserTypeRegular),
+		AccountStatus:          dusers.AccountStatusSuspended,
+		AccountStatusReason:    "spam",
+		AccountStatusBy:        "admin",
+		AccountStatusAt:        &at,
+		AccountStatusExpiresAt: &expires,
+	}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	got, err := repo.GetByUsername(ctx, user.Username)
+	if err != nil {
+		t.Fatalf("GetByUsername returned error: %v", err)
+	}
+	if got.AccountStatus != dusers.AccountStatusSuspended || got.AccountStatusReason != "spam" || got.AccountStatusBy != "admin" {
+		t.Fatalf("unexpected account status: %+v", got)
+	}
+	if got.AccountStatusExpiresAt == nil || !got.AccountStatusExpiresAt.Equal(expires) {
+		t.Fatalf("expiry not preserved: %+v", got.AccountStatusExpiresAt)
+	}
+
+	authUser, err := repo.FindAuthenticatedUser(ctx, user.Username)
+	if err != nil {
+		t.Fatalf("FindAuthenticatedUser returned error: %v", err)
+	}
+	if authUser.AccountStatus != string(dusers.AccountStatusSuspended) || authUser.AccountStatusExpiresAt == nil {
+		t.Fatalf("login user should carry account status: %+v", authUser)
+	}
+
+	for i, action := range []dusers.AccountStatusAction{dusers.AccountStatusActionSuspend, dusers.AccountStatusActionReinstate} {
+		record := &dusers.AccountStatusAuditRecord{
+			Username:      user.Username,
+			ActorUsername: "admin",
+			Action:        action,
+			FromStatus:    dusers.AccountStatusActive,
+			ToStatus:      dusers.AccountStatusSuspended,
+			Reason:        "spam",
+			ExpiresAt:     &expires,
+			CreatedAt:     at.Add(time.Duration(i) * time.Minute),
+		}
+		if err := repo.CreateAccountStatusAudit(ctx, record); err != nil {
+			t.Fatalf("CreateAccountStatusAudit returned error: %v", err)
+		}
+		if record.ID == 0 {
+			t.Fatalf("expected audit ID, got %+v", record)
+		}
+	}
+
+	records, err := repo.ListAccountStatusAudits(ctx, user.Username, 10, 0)
+	if err != nil {
+		t.Fatalf("ListAccountStatusAudits returned error: %v", err)
+	}
+	if len(records) != 2 || records[0].Action != dusers.AccountStatusActionReinstate {
+		t.Fatalf("expected newest-first audits, got %+v", records)
+	}
+	if records[1].ExpiresAt == nil || !records[1].ExpiresAt.Equal(expires) {
+		t.Fatalf("audit expiry not preserved: %+v", records[1])
+	}
+}
+
+func TestGormRepositoryUpdateAccountStatusRollsBackWithoutAudit(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+
+	user := modelstesting.GenerateUser("status_rollback", 0)
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+
+	// A nil audit record cannot be stored, so the status change must not commit either.
+	if _, err := repo.UpdateAccountStatus(ctx, user.Username, func(u *dusers.User) (*dusers.AccountStatusAuditRecord, error) {
+		u.AccountStatus = dusers.AccountStatusBanned
+		return nil, nil
+	}); !errors.Is(err, dusers.ErrInvalidUserData) {
+		t.Fatalf("UpdateAccountStatus error = %v, want ErrInvalidUserData", err)
+	}
+
+	got, err := repo.GetByUsername(ctx, user.Username)
+	if err != nil {
+		t.Fatalf("GetByUsername returned error: %v", err)
+	}
+	if got.EffectiveAccountStatus(time.Now()) != dusers.AccountStatusActive {
+		t.Fatalf("status = %q, want active after rollback", got.AccountStatus)
+	}
+}
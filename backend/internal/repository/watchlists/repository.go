@@ -0,0 +1,120 @@
+package watchlists
+
+import (
+	"context"
+
+	dwatchlists "socialpredict/internal/domain/watchlists"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements the watchlists domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dwatchlists.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based watchlists repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// AddFollow inserts the follow unless the user already has it and fills in
+// the stored ID and creation time either way.
+func (r *GormRepository) AddFollow(ctx context.Context, follow *dwatchlists.Follow) (bool, error) {
+	row := models.WatchlistEntry{
+		Username:   follow.Username,
+		TargetType: string(follow.Target.Type),
+		TargetID:   follow.Target.ID,
+		CreatedAt:  follow.CreatedAt,
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "username"}, {Name: "target_type"}, {Name: "target_id"}}, DoNothing: true}).
+		Create(&row)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		var existing models.WatchlistEntry
+		if err := r.db.WithContext(ctx).
+			Where("username = ? AND target_type = ? AND target_id = ?", follow.Username, string(follow.Target.Type), follow.Target.ID).
+			First(&existing).Error; err != nil {
+			return false, err
+		}
+		follow.ID = existing.ID
+		follow.CreatedAt = existing.CreatedAt
+		return false, nil
+	}
+	follow.ID = row.ID
+	return true, nil
+}
+
+// RemoveFollow deletes the follow if it exists.
+func (r *GormRepository) RemoveFollow(ctx context.Context, username string, target dwatchlists.Target) error {
+	return r.db.WithContext(ctx).
+		Where("username = ? AND target_type = ? AND target_id = ?", username, string(target.Type), target.ID).
+		Delete(&models.WatchlistEntry{}).Error
+}
+
+// ListFollows returns the user's follows, most recent first.
+func (r *GormRepository) ListFollows(ctx context.Context, username string) ([]*dwatchlists.Follow, error) {
+	var rows []models.WatchlistEntry
+	if err := r.db.WithContext(ctx).
+		Where("username = ?", username).
+		Order("created_at DESC, id DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	follows := make([]*dwatchlists.Follow, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		follows = append(follows, &dwatchlists.Follow{
+			ID:        row.ID,
+			Username:  row.Username,
+			Target:    dwatchlists.Target{Type: dwatchlists.TargetType(row.TargetType), ID: row.TargetID},
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return follows, nil
+}
+
+// CountFollows returns how many targets the user follows.
+func (r *GormRepository) CountFollows(ctx context.Context, username string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.WatchlistEntry{}).Where("username = ?", username).Count(&count).Error
+	return count, err
+}
+
+// CountFollowers returns how many users follow target.
+func (r *GormRepository) CountFollowers(ctx context.Context, target dwatchlists.Target) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.WatchlistEntry{}).
+		Where("target_type = ? AND target_id = ?", string(target.Type), target.ID).
+		Count(&count).Error
+	return count, err
+}
+
+// ListFollowers returns each user who follows any of targets once, in
+// username order.
+func (r *GormRepository) ListFollowers(ctx context.Context, targets []dwatchlists.Target) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	query := r.db.WithContext(ctx).Model(&models.WatchlistEntry{})
+	matches := r.db.WithContext(ctx)
+	for i, target := range targets {
+		if i == 0 {
+			matches = matches.Where("target_type = ? AND target_id = ?", string(target.Type), target.ID)
+			continue
+		}
+		matches = matches.Or("target_type = ? AND target_id = ?", string(target.Type), target.ID)
+	}
+	var usernames []string
+	if err := query.Where(matches).Distinct().Order("username ASC").Pluck("username", &usernames).Error; err != nil {
+		return nil, err
+	}
+	return usernames, nil
+}
@@ -0,0 +1,58 @@
+package watchlists
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	dwatchlists "socialpredict/internal/domain/watchlists"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryFollowsAndFollowers(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 7, 3, 9, 0, 0, 0, time.UTC)
+
+	add := func(username string, target dwatchlists.Target, offset time.Duration) (*dwatchlists.Follow, bool) {
+		t.Helper()
+		follow := &dwatchlists.Follow{Username: username, Target: target, CreatedAt: now.Add(offset)}
+		created, err := repo.AddFollow(ctx, follow)
+		if err != nil || follow.ID == 0 {
+			t.Fatalf("AddFollow = %v (id %d)", err, follow.ID)
+		}
+		return follow, created
+	}
+	first, created := add("alice", dwatchlists.MarketTarget(7), 0)
+	if !created {
+		t.Fatalf("expected a new follow")
+	}
+	again, created := add("alice", dwatchlists.MarketTarget(7), time.Hour)
+	if created || again.ID != first.ID || !again.CreatedAt.Equal(now) {
+		t.Fatalf("expected the existing follow back, got %+v created=%v", again, created)
+	}
+	add("alice", dwatchlists.TagTarget("politics"), time.Minute)
+	add("bob", dwatchlists.MarketTarget(7), 0)
+	add("carol", dwatchlists.MarketGroupTarget(2), 0)
+
+	follows, err := repo.ListFollows(ctx, "alice")
+	if err != nil || len(follows) != 2 || follows[0].Target != dwatchlists.TagTarget("politics") {
+		t.Fatalf("ListFollows = %+v, %v", follows, err)
+	}
+	if count, err := repo.CountFollowers(ctx, dwatchlists.MarketTarget(7)); err != nil || count != 2 {
+		t.Fatalf("CountFollowers = %d, %v", count, err)
+	}
+	followers, err := repo.ListFollowers(ctx, []dwatchlists.Target{dwatchlists.MarketTarget(7), dwatchlists.MarketGroupTarget(2), dwatchlists.TagTarget("politics")})
+	if err != nil || !reflect.DeepEqual(followers, []string{"alice", "bob", "carol"}) {
+		t.Fatalf("ListFollowers = %v, %v", followers, err)
+	}
+
+	if err := repo.RemoveFollow(ctx, "alice", dwatchlists.MarketTarget(7)); err != nil {
+		t.Fatalf("RemoveFollow returned error: %v", err)
+	}
+	if count, _ := repo.CountFollows(ctx, "alice"); count != 1 {
+		t.Fatalf("expected one follow left, got %d", count)
+	}
+}
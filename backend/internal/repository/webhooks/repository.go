@@ -0,0 +1,289 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dwebhooks "socialpredict/internal/domain/webhooks"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxDeliveriesPage = 200
+
+// GormRepository implements the webhooks domain repository using GORM.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+var _ dwebhooks.Repository = (*GormRepository)(nil)
+
+// NewGormRepository creates a new GORM-based webhooks repository.
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// CreateEndpoint inserts an endpoint and assigns its ID.
+func (r *GormRepository) CreateEndpoint(ctx context.Context, endpoint *dwebhooks.Endpoint) error {
+	row := endpointToModel(endpoint)
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	endpoint.ID = row.ID
+	return nil
+}
+
+// UpdateEndpoint saves every editable endpoint field.
+func (r *GormRepository) UpdateEndpoint(ctx context.Context, endpoint *dwebhooks.Endpoint) error {
+	result := r.db.WithContext(ctx).Model(&models.WebhookEndpoint{}).
+		Where("id = ?", endpoint.ID).
+		Updates(map[string]any{
+			"url":         endpoint.URL,
+			"description": endpoint.Description,
+			"secret":      endpoint.Secret,
+			"event_types": joinEventTypes(endpoint.EventTypes),
+			"active":      endpoint.Active,
+			"updated_by":  endpoint.UpdatedBy,
+			"updated_at":  endpoint.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dwebhooks.ErrEndpointNotFound
+	}
+	return nil
+}
+
+// DeleteEndpoint removes the endpoint and its delivery log in one transaction.
+func (r *GormRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookEndpoint{}, id).Error
+	})
+}
+
+// GetEndpoint returns one endpoint.
+func (r *GormRepository) GetEndpoint(ctx context.Context, id int64) (*dwebhooks.Endpoint, error) {
+	var row models.WebhookEndpoint
+	if err := r.db.WithContext(ctx).First(&row, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dwebhooks.ErrEndpointNotFound
+		}
+		return nil, err
+	}
+	return modelToEndpoint(&row), nil
+}
+
+// ListEndpoints returns every endpoint in creation order.
+func (r *GormRepository) ListEndpoints(ctx context.Context) ([]*dwebhooks.Endpoint, error) {
+	var rows []models.WebhookEndpoint
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	endpoints := make([]*dwebhooks.Endpoint, 0, len(rows))
+	for i := range rows {
+		endpoints = append(endpoints, modelToEndpoint(&rows[i]))
+	}
+	return endpoints, nil
+}
+
+// EnqueueDelivery inserts the delivery unless the endpoint already has one
+// for the same event key.
+func (r *GormRepository) EnqueueDelivery(ctx context.Context, delivery *dwebhooks.Delivery) (bool, error) {
+	row := deliveryToModel(delivery)
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&row)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.ID = row.ID
+	return true, nil
+}
+
+// ClaimDueDeliveries leases due pending deliveries oldest first using a
+// conditional update per row, so concurrent workers never share a lease.
+func (r *GormRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*dwebhooks.Delivery, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	now = now.UTC()
+	var candidates []models.WebhookDelivery
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*dwebhooks.Delivery, 0, len(candidates))
+	for i := range candidates {
+		leaseUntil := now.Add(lease)
+		result := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", candidates[i].ID, models.WebhookDeliveryPending, now).
+			Update("next_attempt_at", leaseUntil)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		candidates[i].NextAttemptAt = leaseUntil
+		claimed = append(claimed, modelToDelivery(&candidates[i]))
+	}
+	return claimed, nil
+}
+
+// SaveDeliveryOutcome records the result of an attempt.
+func (r *GormRepository) SaveDeliveryOutcome(ctx context.Context, delivery *dwebhooks.Delivery) error {
+	return r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]any{
+			"status":          string(delivery.Status),
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt.UTC(),
+			"response_status": delivery.ResponseStatus,
+			"last_error":      delivery.LastError,
+			"last_attempt_at": delivery.LastAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+}
+
+// GetDelivery returns one delivery belonging to endpointID.
+func (r *GormRepository) GetDelivery(ctx context.Context, endpointID, deliveryID int64) (*dwebhooks.Delivery, error) {
+	var row models.WebhookDelivery
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND endpoint_id = ?", deliveryID, endpointID).
+		First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dwebhooks.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return modelToDelivery(&row), nil
+}
+
+// ListDeliveries returns an endpoint's deliveries newest first.
+func (r *GormRepository) ListDeliveries(ctx context.Context, endpointID int64, filters dwebhooks.DeliveryFilters) ([]*dwebhooks.Delivery, error) {
+	limit := filters.Limit
+	if limit <= 0 || limit > maxDeliveriesPage {
+		limit = maxDeliveriesPage
+	}
+	offset := filters.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	query := r.db.WithContext(ctx).Where("endpoint_id = ?", endpointID)
+	if filters.Status != "" {
+		query = query.Where("status = ?", string(filters.Status))
+	}
+	var rows []models.WebhookDelivery
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	deliveries := make([]*dwebhooks.Delivery, 0, len(rows))
+	for i := range rows {
+		deliveries = append(deliveries, modelToDelivery(&rows[i]))
+	}
+	return deliveries, nil
+}
+
+func endpointToModel(endpoint *dwebhooks.Endpoint) models.WebhookEndpoint {
+	return models.WebhookEndpoint{
+		ID:          endpoint.ID,
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		Secret:      endpoint.Secret,
+		EventTypes:  joinEventTypes(endpoint.EventTypes),
+		Active:      endpoint.Active,
+		CreatedBy:   endpoint.CreatedBy,
+		UpdatedBy:   endpoint.UpdatedBy,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+}
+
+func modelToEndpoint(row *models.WebhookEndpoint) *dwebhooks.Endpoint {
+	return &dwebhooks.Endpoint{
+		ID:          row.ID,
+		URL:         row.URL,
+		Description: row.Description,
+		Secret:      row.Secret,
+		EventTypes:  splitEventTypes(row.EventTypes),
+		Active:      row.Active,
+		CreatedBy:   row.CreatedBy,
+		UpdatedBy:   row.UpdatedBy,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+func deliveryToModel(delivery *dwebhooks.Delivery) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:             delivery.ID,
+		EndpointID:     delivery.EndpointID,
+		EventKey:       delivery.EventKey,
+		EventType:      string(delivery.EventType),
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		NextAttemptAt:  delivery.NextAttemptAt.UTC(),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func modelToDelivery(row *models.WebhookDelivery) *dwebhooks.Delivery {
+	return &dwebhooks.Delivery{
+		ID:             row.ID,
+		EndpointID:     row.EndpointID,
+		EventKey:       row.EventKey,
+		EventType:      devents.Type(row.EventType),
+		Payload:        row.Payload,
+		Status:         dwebhooks.DeliveryStatus(row.Status),
+		Attempts:       row.Attempts,
+		NextAttemptAt:  row.NextAttemptAt,
+		ResponseStatus: row.ResponseStatus,
+		LastError:      row.LastError,
+		LastAttemptAt:  row.LastAttemptAt,
+		DeliveredAt:    row.DeliveredAt,
+		CreatedAt:      row.CreatedAt,
+	}
+}
+
+func joinEventTypes(eventTypes []devents.Type) string {
+	names := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		names = append(names, string(eventType))
+	}
+	return strings.Join(names, ",")
+}
+
+func splitEventTypes(raw string) []devents.Type {
+	if strings.TrimSpace(raw) == "" {
+		return []devents.Type{}
+	}
+	parts := strings.Split(raw, ",")
+	eventTypes := make([]devents.Type, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			eventTypes = append(eventTypes, devents.Type(part))
+		}
+	}
+	return eventTypes
+}
@@ -0,0 +1,94 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	devents "socialpredict/internal/domain/events"
+	dwebhooks "socialpredict/internal/domain/webhooks"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestGormRepositoryEndpointsAndDeliveries(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 28, 9, 0, 0, 0, time.UTC)
+
+	endpoint := &dwebhooks.Endpoint{
+		URL:        "https://hooks.example.com",
+		Secret:     "whsec_test",
+		EventTypes: []devents.Type{devents.MarketResolved, devents.BetPlaced},
+		Active:     true,
+		CreatedBy:  "root",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := repo.CreateEndpoint(ctx, endpoint); err != nil {
+		t.Fatalf("CreateEndpoint returned error: %v", err)
+	}
+	endpoint.Active = false
+	endpoint.EventTypes = []devents.Type{devents.BetPlaced}
+	if err := repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		t.Fatalf("UpdateEndpoint returned error: %v", err)
+	}
+	loaded, err := repo.GetEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		t.Fatalf("GetEndpoint returned error: %v", err)
+	}
+	if loaded.Active || len(loaded.EventTypes) != 1 || loaded.EventTypes[0] != devents.BetPlaced || loaded.Secret != "whsec_test" {
+		t.Fatalf("unexpected endpoint: %+v", loaded)
+	}
+
+	delivery := &dwebhooks.Delivery{EndpointID: endpoint.ID, EventKey: "evt_1", EventType: devents.BetPlaced, Payload: `{"id":"evt_1"}`, Status: dwebhooks.DeliveryPending, NextAttemptAt: now, CreatedAt: now}
+	inserted, err := repo.EnqueueDelivery(ctx, delivery)
+	if err != nil || !inserted || delivery.ID == 0 {
+		t.Fatalf("EnqueueDelivery = %v, %v (id %d)", inserted, err, delivery.ID)
+	}
+	duplicate := *delivery
+	duplicate.ID = 0
+	if inserted, err := repo.EnqueueDelivery(ctx, &duplicate); err != nil || inserted {
+		t.Fatalf("duplicate EnqueueDelivery = %v, %v", inserted, err)
+	}
+
+	claimed, err := repo.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDueDeliveries = %d, %v", len(claimed), err)
+	}
+	if again, _ := repo.ClaimDueDeliveries(ctx, now, time.Minute, 10); len(again) != 0 {
+		t.Fatalf("leased delivery was claimed again")
+	}
+
+	claimed[0].Status = dwebhooks.DeliveryDead
+	claimed[0].Attempts = 8
+	claimed[0].ResponseStatus = 503
+	claimed[0].LastError = "receiver responded with HTTP 503"
+	claimed[0].LastAttemptAt = &now
+	if err := repo.SaveDeliveryOutcome(ctx, claimed[0]); err != nil {
+		t.Fatalf("SaveDeliveryOutcome returned error: %v", err)
+	}
+	dead, err := repo.ListDeliveries(ctx, endpoint.ID, dwebhooks.DeliveryFilters{Status: dwebhooks.DeliveryDead})
+	if err != nil || len(dead) != 1 || dead[0].ResponseStatus != 503 || dead[0].Attempts != 8 {
+		t.Fatalf("ListDeliveries(dead) = %+v, %v", dead, err)
+	}
+	if _, err := repo.GetDelivery(ctx, endpoint.ID+1, delivery.ID); !errors.Is(err, dwebhooks.ErrDeliveryNotFound) {
+		t.Fatalf("GetDelivery for another endpoint error = %v", err)
+	}
+
+	if err := repo.DeleteEndpoint(ctx, endpoint.ID); err != nil {
+		t.Fatalf("DeleteEndpoint returned error: %v", err)
+	}
+	if _, err := repo.GetEndpoint(ctx, endpoint.ID); !errors.Is(err, dwebhooks.ErrEndpointNotFound) {
+		t.Fatalf("GetEndpoint after delete error = %v", err)
+	}
+	var remaining int64
+	if err := db.Model(&models.WebhookDelivery{}).Count(&remaining).Error; err != nil {
+		t.Fatalf("count deliveries: %v", err)
+	}
+	if remaining != 0 {
+		t.Fatalf("deliveries left after endpoint delete: %d", remaining)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	dusers "socialpredict/internal/domain/users"
 
@@ -134,6 +135,9 @@ func validateTokenAndGetUser(ctx context.Context, tokenString string, svc dusers
 			}
 			return nil, newAuthError(ErrorKindUserLoadFailed)
 		}
+		if err := user.EnsureCanAuthenticate(time.Now().UTC()); err != nil {
+			return nil, newAuthError(ErrorKindAccountBanned)
+		}
 		return user, nil
 	}
 	return nil, newAuthError(ErrorKindInvalidToken)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"socialpredict/internal/domain/permissions"
 	dusers "socialpredict/internal/domain/users"
 )
 
@@ -20,6 +21,7 @@ type Authenticator interface {
 type AuthService struct {
 	users         dusers.ServiceInterface
 	jwtSigningKey []byte
+	authorizer    permissions.Authorizer
 }
 
 // NewAuthService constructs a façade that uses the provided users service for
@@ -3,22 +3,25 @@ package auth
 import (
 	"errors"
 	"net/http"
+
+	"socialpredict/internal/domain/permissions"
 )
 
 // ValidateAdminToken uses the provided authenticator to ensure the request is
-// made by an admin user. Prefer calling auth.RequireAdmin directly; this helper
-// exists for backwards compatibility with legacy call sites.
+// made by a user granted users.manage. Prefer calling RequirePermission
+// directly; this helper exists for backwards compatibility with legacy call
+// sites.
 func ValidateAdminToken(r *http.Request, auth Authenticator) error {
 	if auth == nil {
 		return errors.New("authenticator is required")
 	}
 
-	user, authErr := auth.RequireAdmin(r)
+	user, authErr := RequirePermission(auth, r, permissions.UsersManage)
 	if authErr != nil {
 		return errors.New(authErr.Message)
 	}
 
-	// Extra guard: RequireAdmin already checks admin, but ensure status handling.
+	// Extra guard: RequirePermission already checks the grant, but ensure status handling.
 	if user == nil {
 		return errors.New("unauthorized")
 	}
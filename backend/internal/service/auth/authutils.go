@@ -17,6 +17,8 @@ const (
 	ErrorKindUserLoadFailed         ErrorKind = "user_load_failed"
 	ErrorKindPasswordChangeRequired ErrorKind = "password_change_required"
 	ErrorKindAdminRequired          ErrorKind = "admin_required"
+	ErrorKindPermissionDenied       ErrorKind = "permission_denied"
+	ErrorKindAccountBanned          ErrorKind = "account_banned"
 	ErrorKindServiceUnavailable     ErrorKind = "service_unavailable"
 )
 
@@ -50,6 +52,10 @@ func (k ErrorKind) defaultMessage() string {
 		return "Password change required"
 	case ErrorKindAdminRequired:
 		return "admin privileges required"
+	case ErrorKindPermissionDenied:
+		return "permission denied"
+	case ErrorKindAccountBanned:
+		return "account banned"
 	case ErrorKindServiceUnavailable:
 		return "authentication service unavailable"
 	default:
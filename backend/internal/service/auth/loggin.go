@@ -34,9 +34,16 @@ type loginResponse struct {
 	UserType           string `json:"usertype"`
 	ModeratorStatus    string `json:"moderatorStatus"`
 	MustChangePassword bool   `json:"mustChangePassword"`
+	AccountStatus      string `json:"accountStatus"`
 }
 
 func LoginHandler(users LoginUserRepository, securityService *security.SecurityService, jwtSigningKey ...[]byte) http.HandlerFunc {
+	return ProtectedLoginHandler(users, LoginProtection{}, securityService, jwtSigningKey...)
+}
+
+// ProtectedLoginHandler is LoginHandler with per-account failure throttling
+// and login audit recording applied before and after the credential check.
+func ProtectedLoginHandler(users LoginUserRepository, protection LoginProtection, securityService *security.SecurityService, jwtSigningKey ...[]byte) http.HandlerFunc {
 	key := currentJWTSigningKey()
 	if len(jwtSigningKey) > 0 {
 		key = jwtSigningKey[0]
@@ -65,8 +72,23 @@ func LoginHandler(users LoginUserRepository, securityService *security.SecurityS
 			return
 		}
 
+		now := protection.now()
+		if !protection.allow(w, r, req.Username, now) {
+			return
+		}
+
 		user, loginErr := authenticateUser(r.Context(), users, req)
 		if loginErr != nil {
+			if loginErr.statusCode == http.StatusUnauthorized {
+				protection.record(r, dusers.LoginAttempt{
+					Username: req.Username,
+					Outcome:  dusers.LoginOutcomeFailure,
+					Method:   dusers.LoginMethodPassword,
+					Reason:   loginErr.reason,
+					Reserved: true,
+					Now:      now,
+				})
+			}
 			_ = writeLoginFailure(w, loginErr.statusCode, loginFailureReason(loginErr.statusCode))
 			return
 		}
@@ -82,6 +104,12 @@ func LoginHandler(users LoginUserRepository, securityService *security.SecurityS
 			return
 		}
 
+		protection.record(r, dusers.LoginAttempt{
+			Username: req.Username,
+			Outcome:  dusers.LoginOutcomeSuccess,
+			Method:   dusers.LoginMethodPassword,
+			Now:      now,
+		})
 		_ = writeLoginResponse(w, user, tokenString)
 	}
 }
@@ -131,6 +159,7 @@ func validateAndSanitizeLogin(securityService *security.SecurityService, req log
 
 type loginError struct {
 	statusCode int
+	reason     string
 }
 
 func authenticateUser(ctx context.Context, users LoginUserRepository, req loginRequest) (boundary.AuthenticatedUser, *loginError) {
@@ -141,18 +170,31 @@ func authenticateUser(ctx context.Context, users LoginUserRepository, req loginR
 	user, err := findUserByUsername(ctx, users, req.Username)
 	if err != nil {
 		if errors.Is(err, dusers.ErrUserNotFound) {
-			return boundary.AuthenticatedUser{}, &loginError{statusCode: http.StatusUnauthorized}
+			return boundary.AuthenticatedUser{}, &loginError{statusCode: http.StatusUnauthorized, reason: "unknown_user"}
 		}
 		return boundary.AuthenticatedUser{}, &loginError{statusCode: http.StatusInternalServerError}
 	}
 
 	if !user.CheckPasswordHash(req.Password) {
-		return boundary.AuthenticatedUser{}, &loginError{statusCode: http.StatusUnauthorized}
+		return boundary.AuthenticatedUser{}, &loginError{statusCode: http.StatusUnauthorized, reason: "invalid_password"}
+	}
+	if authenticatedAccountStatus(user, time.Now().UTC()) == dusers.AccountStatusBanned {
+		return boundary.AuthenticatedUser{}, &loginError{statusCode: http.StatusForbidden, reason: "account_banned"}
 	}
 
 	return user, nil
 }
 
+// authenticatedAccountStatus resolves the login user's standing at now,
+// treating expired suspensions and bans as active.
+func authenticatedAccountStatus(user boundary.AuthenticatedUser, now time.Time) dusers.AccountStatus {
+	standing := dusers.User{
+		AccountStatus:          dusers.AccountStatus(user.AccountStatus),
+		AccountStatusExpiresAt: user.AccountStatusExpiresAt,
+	}
+	return standing.EffectiveAccountStatus(now)
+}
+
 func findUserByUsername(ctx context.Context, users LoginUserRepository, username string) (boundary.AuthenticatedUser, error) {
 	if users == nil {
 		return boundary.AuthenticatedUser{}, fmt.Errorf("database connection is not initialized")
@@ -191,6 +233,7 @@ func writeLoginResponse(w http.ResponseWriter, user boundary.AuthenticatedUser,
 		UserType:           user.UserType,
 		ModeratorStatus:    user.ModeratorStatus,
 		MustChangePassword: user.MustChangePassword,
+		AccountStatus:      string(authenticatedAccountStatus(user, time.Now().UTC())),
 	})
 }
 
@@ -202,6 +245,8 @@ func loginFailureReason(statusCode int) handlers.FailureReason {
 	switch statusCode {
 	case http.StatusUnauthorized:
 		return handlers.ReasonAuthorizationDenied
+	case http.StatusForbidden:
+		return handlers.ReasonAccountBanned
 	default:
 		return handlers.ReasonInternalError
 	}
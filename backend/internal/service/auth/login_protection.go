@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"socialpredict/handlers"
+	dusers "socialpredict/internal/domain/users"
+	"socialpredict/logger"
+	"socialpredict/security"
+)
+
+// LoginAttemptGuard tracks login attempts per account so repeated password
+// failures are delayed and eventually locked out across processes and restarts.
+type LoginAttemptGuard interface {
+	ReserveLoginAttempt(ctx context.Context, username string, policy dusers.LoginThrottlePolicy, now time.Time) (dusers.LoginDecision, error)
+	RecordLoginAttempt(ctx context.Context, attempt dusers.LoginAttempt) (dusers.LoginDecision, error)
+}
+
+// LoginProtection configures per-account throttling and audit recording for
+// login handlers. The zero value disables both.
+type LoginProtection struct {
+	Guard          LoginAttemptGuard
+	Policy         dusers.LoginThrottlePolicy
+	ClientIdentity security.ClientIdentityExtractor
+	Now            func() time.Time
+}
+
+func (p LoginProtection) now() time.Time {
+	if p.Now == nil {
+		return time.Now().UTC()
+	}
+	return p.Now().UTC()
+}
+
+// allow refuses the request with 429 when the account is delayed or locked.
+// An allowed attempt is counted as a failure before the password is checked;
+// recording a success clears it again.
+func (p LoginProtection) allow(w http.ResponseWriter, r *http.Request, username string, now time.Time) bool {
+	if p.Guard == nil {
+		return true
+	}
+
+	decision, err := p.Guard.ReserveLoginAttempt(r.Context(), username, p.Policy, now)
+	if err != nil {
+		logger.LogError("Login", "ReserveLoginAttempt", err)
+		_ = writeLoginFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+		return false
+	}
+	if decision.Allowed {
+		return true
+	}
+
+	reason := "delayed"
+	if decision.Locked {
+		reason = "locked"
+	}
+	p.record(r, dusers.LoginAttempt{
+		Username: username,
+		Outcome:  dusers.LoginOutcomeThrottled,
+		Method:   dusers.LoginMethodPassword,
+		Reason:   reason,
+		Now:      now,
+	})
+	writeLoginThrottled(w, decision.RetryAfter)
+	return false
+}
+
+// record appends the attempt to the audit trail. Recording failures are logged
+// rather than surfaced so the login response itself is never changed by them.
+func (p LoginProtection) record(r *http.Request, attempt dusers.LoginAttempt) {
+	if p.Guard == nil {
+		return
+	}
+	attempt.IPAddress = p.ClientIdentity.Extract(r)
+	attempt.UserAgent = r.UserAgent()
+	attempt.Policy = p.Policy
+	if _, err := p.Guard.RecordLoginAttempt(r.Context(), attempt); err != nil {
+		logger.LogError("Login", "RecordLoginAttempt", err)
+	}
+}
+
+func writeLoginThrottled(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	_ = writeLoginFailure(w, http.StatusTooManyRequests, handlers.ReasonLoginRateLimited)
+}
@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"socialpredict/internal/domain/boundary"
+	dusers "socialpredict/internal/domain/users"
+	rusers "socialpredict/internal/repository/users"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+	"socialpredict/security"
+)
+
+func TestProtectedLoginHandlerLocksAccountAcrossClients(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	user := modelstesting.GenerateUser("ada", 0)
+	if err := user.HashPassword("password123"); err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+
+	repo := rusers.NewGormRepository(db)
+	now := time.Date(2026, 6, 24, 9, 0, 0, 0, time.UTC)
+	handler := ProtectedLoginHandler(repo, LoginProtection{
+		Guard:  dusers.NewService(repo, nil, nil),
+		Policy: dusers.LoginThrottlePolicy{FreeAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second, LockoutThreshold: 3, LockoutDuration: 15 * time.Minute},
+		Now:    func() time.Time { return now },
+	}, security.NewSecurityService(), []byte("test-secret-key-for-testing"))
+
+	login := func(password, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v0/login", bytes.NewBufferString(`{"username":"ada","password":"`+password+`"}`))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", "guesser/1.0")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	// Each guess comes from a different address, so IP rate limiting alone would not stop it.
+	for i, addr := range []string{"198.51.100.1:1000", "198.51.100.2:1000", "198.51.100.3:1000"} {
+		if rec := login("wrong", addr); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d status = %d, want 401", i+1, rec.Code)
+		}
+	}
+
+	rec := login("password123", "203.0.113.7:443")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("locked login status = %d, want 429 body=%s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") != "900" {
+		t.Fatalf("Retry-After = %q, want 900", rec.Header().Get("Retry-After"))
+	}
+
+	now = now.Add(16 * time.Minute)
+	if rec := login("password123", "203.0.113.7:443"); rec.Code != http.StatusOK {
+		t.Fatalf("login after lockout status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+
+	var events []models.LoginAuditEvent
+	if err := db.Order("id ASC").Find(&events).Error; err != nil {
+		t.Fatalf("load audit events: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("expected 5 audit events, got %d", len(events))
+	}
+	if events[0].Outcome != string(dusers.LoginOutcomeFailure) || events[0].IPAddress != "198.51.100.1" || events[0].UserAgent != "guesser/1.0" || events[0].Reason != "invalid_password" {
+		t.Fatalf("unexpected failure event: %+v", events[0])
+	}
+	if events[3].Outcome != string(dusers.LoginOutcomeThrottled) || events[3].Reason != "locked" {
+		t.Fatalf("unexpected throttled event: %+v", events[3])
+	}
+	if events[4].Outcome != string(dusers.LoginOutcomeSuccess) {
+		t.Fatalf("unexpected success event: %+v", events[4])
+	}
+
+	var throttles int64
+	db.Model(&models.UserLoginThrottle{}).Count(&throttles)
+	if throttles != 0 {
+		t.Fatalf("expected success to clear counters, found %d rows", throttles)
+	}
+}
+
+func TestProtectedLoginHandlerThrottlesUnknownUsernames(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := rusers.NewGormRepository(db)
+	handler := ProtectedLoginHandler(repo, LoginProtection{
+		Guard:  dusers.NewService(repo, nil, nil),
+		Policy: dusers.LoginThrottlePolicy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Minute, LockoutThreshold: 10, LockoutDuration: time.Hour},
+	}, security.NewSecurityService(), []byte("test-secret-key-for-testing"))
+
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/v0/login", bytes.NewBufferString(`{"username":"ghost","password":"guess"}`)))
+		codes = append(codes, rec.Code)
+	}
+
+	want := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Fatalf("status codes = %v, want %v", codes, want)
+		}
+	}
+}
+
+// countingLoginUsers counts how many attempts reached the credential check.
+type countingLoginUsers struct {
+	LoginUserRepository
+	lookups atomic.Int64
+}
+
+func (c *countingLoginUsers) FindAuthenticatedUser(ctx context.Context, username string) (*boundary.AuthenticatedUser, error) {
+	c.lookups.Add(1)
+	return c.LoginUserRepository.FindAuthenticatedUser(ctx, username)
+}
+
+func TestProtectedLoginHandlerReservesParallelGuessesBeforePasswordCheck(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	// One connection keeps every request on the same in-memory database.
+	sqlDB.SetMaxOpenConns(1)
+	user := modelstesting.GenerateUser("ada", 0)
+	if err := user.HashPassword("password123"); err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+
+	repo := rusers.NewGormRepository(db)
+	users := &countingLoginUsers{LoginUserRepository: repo}
+	policy := dusers.LoginThrottlePolicy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Minute, LockoutThreshold: 3, LockoutDuration: 15 * time.Minute}
+	handler := ProtectedLoginHandler(users, LoginProtection{
+		Guard:  dusers.NewService(repo, nil, nil),
+		Policy: policy,
+	}, security.NewSecurityService(), []byte("test-secret-key-for-testing"))
+
+	const guesses = 12
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodPost, "/v0/login", bytes.NewBufferString(`{"username":"ada","password":"wrong"}`)))
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := users.lookups.Load(); got > int64(policy.LockoutThreshold) {
+		t.Fatalf("%d parallel guesses reached the password check, want at most %d", got, policy.LockoutThreshold)
+	}
+	var throttle models.UserLoginThrottle
+	if err := db.Where("username = ?", "ada").First(&throttle).Error; err != nil {
+		t.Fatalf("load throttle: %v", err)
+	}
+	if throttle.LockedUntil == nil {
+		t.Fatalf("expected account to be locked after parallel guesses: %+v", throttle)
+	}
+}
@@ -579,6 +579,72 @@ func TestValidateUserAndEnforcePasswordChangeFromToken_PasswordChangeRequired(t
 	}
 }
 
+func TestValidateTokenAndGetUserFromToken_RejectsBannedAccount(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	signingKey := []byte("test-secret-key")
+
+	testUser := modelstesting.GenerateUser("banned-user", 1000)
+	testUser.AccountStatus = string(dusers.AccountStatusBanned)
+	if err := db.Create(&testUser).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	svc := dusers.NewService(rusers.NewGormRepository(db), nil, security.NewSecurityService().Sanitizer)
+	token, err := generateJWT(testUser.Username, signingKey)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	user, authErr := ValidateTokenAndGetUserFromToken(t.Context(), token, svc, signingKey)
+	if user != nil {
+		t.Fatalf("expected nil user for banned account")
+	}
+	if authErr == nil || authErr.Kind != ErrorKindAccountBanned {
+		t.Fatalf("expected account-banned error, got %v", authErr)
+	}
+
+	expired := time.Now().UTC().Add(-time.Minute)
+	if err := db.Model(&testUser).Update("account_status_expires_at", expired).Error; err != nil {
+		t.Fatalf("expire ban: %v", err)
+	}
+	if _, authErr := ValidateTokenAndGetUserFromToken(t.Context(), token, svc, signingKey); authErr != nil {
+		t.Fatalf("expired ban should no longer block tokens: %v", authErr)
+	}
+}
+
+func TestLoginHandler_BannedAccountReturnsAccountBanned(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	repo := rusers.NewGormRepository(db)
+	t.Setenv("JWT_SIGNING_KEY", "test-secret-key-for-testing")
+
+	testUser := modelstesting.GenerateUser("banneduser", 1000)
+	if err := testUser.HashPassword("password123"); err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	testUser.AccountStatus = string(dusers.AccountStatusBanned)
+	if err := db.Create(&testUser).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"username":"banneduser","password":"password123"}`))
+	w := httptest.NewRecorder()
+
+	LoginHandler(repo, security.NewSecurityService())(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Reason != string(handlers.ReasonAccountBanned) {
+		t.Fatalf("expected reason %q, got %q", handlers.ReasonAccountBanned, response.Reason)
+	}
+}
+
 func TestValidateAdminToken_MissingHeader(t *testing.T) {
 	db := modelstesting.NewFakeDB(t)
 	svc := dusers.NewService(rusers.NewGormRepository(db), nil, security.NewSecurityService().Sanitizer)
@@ -0,0 +1,198 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+const defaultStateTTL = 10 * time.Minute
+
+var (
+	// ErrNotConfigured indicates that no identity provider is configured.
+	ErrNotConfigured = errors.New("oidc: login is not configured")
+	// ErrInvalidState indicates an unknown, expired, or already used state value.
+	ErrInvalidState = errors.New("oidc: invalid or expired login state")
+)
+
+// LoginState holds the secrets of one in-flight login attempt.
+type LoginState struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// StateStore keeps login states between the authorization redirect and the callback.
+type StateStore interface {
+	SaveState(ctx context.Context, state LoginState) error
+	// ConsumeState returns and deletes the state so each one can only complete a single login.
+	ConsumeState(ctx context.Context, stateHash string, now time.Time) (*LoginState, error)
+}
+
+// IdentityProvider is the protocol surface the flow needs from a provider client.
+type IdentityProvider interface {
+	AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error)
+}
+
+// Authorization is the redirect a client follows to start signing in.
+type Authorization struct {
+	URL       string
+	State     string
+	ExpiresAt time.Time
+}
+
+// Flow runs the authorization code + PKCE flow against a single provider.
+type Flow struct {
+	provider IdentityProvider
+	states   StateStore
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewFlow wires a provider and state store. A nil provider yields a flow that reports ErrNotConfigured.
+func NewFlow(provider IdentityProvider, states StateStore, now func() time.Time) *Flow {
+	if now == nil {
+		now = time.Now
+	}
+	return &Flow{provider: provider, states: states, ttl: defaultStateTTL, now: now}
+}
+
+// Begin creates a new login attempt and returns the provider authorization URL.
+func (f *Flow) Begin(ctx context.Context) (*Authorization, error) {
+	if f == nil || f.provider == nil || f.states == nil {
+		return nil, ErrNotConfigured
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := f.now().UTC().Add(f.ttl)
+	if err := f.states.SaveState(ctx, LoginState{
+		StateHash:    hashState(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	authURL, err := f.provider.AuthorizationURL(ctx, state, nonce, codeChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+	return &Authorization{URL: authURL, State: state, ExpiresAt: expiresAt}, nil
+}
+
+// Complete validates the returned state, redeems the code, and returns the verified claims.
+func (f *Flow) Complete(ctx context.Context, code, state string) (*Claims, error) {
+	if f == nil || f.provider == nil || f.states == nil {
+		return nil, ErrNotConfigured
+	}
+	code = strings.TrimSpace(code)
+	state = strings.TrimSpace(state)
+	if code == "" || state == "" {
+		return nil, ErrInvalidState
+	}
+
+	stored, err := f.states.ConsumeState(ctx, hashState(state), f.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return f.provider.Exchange(ctx, code, stored.CodeVerifier, stored.Nonce)
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("oidc: generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// GormStateStore persists login states in the oidc_login_states table.
+type GormStateStore struct {
+	db *gorm.DB
+}
+
+// NewGormStateStore constructs a database-backed state store.
+func NewGormStateStore(db *gorm.DB) *GormStateStore {
+	return &GormStateStore{db: db}
+}
+
+// SaveState stores a new login state and opportunistically prunes expired ones.
+func (s *GormStateStore) SaveState(ctx context.Context, state LoginState) error {
+	db := s.db.WithContext(ctx)
+	if err := db.Where("expires_at < ?", state.ExpiresAt.Add(-2*defaultStateTTL)).Delete(&models.OIDCLoginState{}).Error; err != nil {
+		return err
+	}
+	return db.Create(&models.OIDCLoginState{
+		StateHash:    state.StateHash,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		ExpiresAt:    state.ExpiresAt,
+	}).Error
+}
+
+// ConsumeState deletes and returns a live state in one transaction.
+func (s *GormStateStore) ConsumeState(ctx context.Context, stateHash string, now time.Time) (*LoginState, error) {
+	var consumed *LoginState
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row models.OIDCLoginState
+		if err := tx.Where("state_hash = ?", stateHash).First(&row).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidState
+			}
+			return err
+		}
+		deleted := tx.Where("id = ?", row.ID).Delete(&models.OIDCLoginState{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 || !now.Before(row.ExpiresAt) {
+			return ErrInvalidState
+		}
+		consumed = &LoginState{
+			StateHash:    row.StateHash,
+			Nonce:        row.Nonce,
+			CodeVerifier: row.CodeVerifier,
+			ExpiresAt:    row.ExpiresAt,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return consumed, nil
+}
@@ -0,0 +1,152 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"socialpredict/internal/service/auth/oidc"
+	"socialpredict/internal/service/auth/oidc/oidctest"
+	"socialpredict/models/modelstesting"
+)
+
+const testClientID = "socialpredict-test"
+
+func newTestFlow(t *testing.T) (*oidc.Flow, *oidctest.Server, *oidc.Provider) {
+	t.Helper()
+	idp, err := oidctest.NewServer(testClientID)
+	if err != nil {
+		t.Fatalf("start stub idp: %v", err)
+	}
+	t.Cleanup(idp.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		IssuerURL:   idp.Issuer(),
+		ClientID:    testClientID,
+		RedirectURL: "https://predict.example.com/auth/callback",
+	}, idp.Client())
+	flow := oidc.NewFlow(provider, oidc.NewGormStateStore(modelstesting.NewFakeDB(t)), time.Now)
+	return flow, idp, provider
+}
+
+func TestFlowCompletesAuthorizationCodeWithPKCE(t *testing.T) {
+	flow, idp, _ := newTestFlow(t)
+	ctx := context.Background()
+	idp.SetIdentity(oidctest.Identity{Subject: "user-123", Email: "Ada@Example.com", EmailVerified: true, Name: "Ada"})
+
+	authorization, err := flow.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin returned error: %v", err)
+	}
+	callback, err := idp.Authorize(authorization.URL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if callback.Query().Get("state") != authorization.State {
+		t.Fatalf("state was not echoed back: %s", callback.String())
+	}
+
+	claims, err := flow.Complete(ctx, callback.Query().Get("code"), callback.Query().Get("state"))
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if claims.Issuer != idp.Issuer() || claims.Subject != "user-123" || claims.Email != "Ada@Example.com" || !claims.EmailVerified {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := flow.Complete(ctx, callback.Query().Get("code"), callback.Query().Get("state")); !errors.Is(err, oidc.ErrInvalidState) {
+		t.Fatalf("replayed state error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestFlowRejectsUnknownState(t *testing.T) {
+	flow, _, _ := newTestFlow(t)
+
+	if _, err := flow.Complete(context.Background(), "code", "forged-state"); !errors.Is(err, oidc.ErrInvalidState) {
+		t.Fatalf("Complete error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestFlowRejectsExpiredState(t *testing.T) {
+	idp, err := oidctest.NewServer(testClientID)
+	if err != nil {
+		t.Fatalf("start stub idp: %v", err)
+	}
+	defer idp.Close()
+
+	now := time.Date(2026, 6, 23, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	provider := oidc.NewProvider(oidc.Config{IssuerURL: idp.Issuer(), ClientID: testClientID, RedirectURL: "https://predict.example.com/cb"}, idp.Client())
+	flow := oidc.NewFlow(provider, oidc.NewGormStateStore(modelstesting.NewFakeDB(t)), clock)
+
+	authorization, err := flow.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Begin returned error: %v", err)
+	}
+	callback, err := idp.Authorize(authorization.URL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := flow.Complete(context.Background(), callback.Query().Get("code"), authorization.State); !errors.Is(err, oidc.ErrInvalidState) {
+		t.Fatalf("Complete error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestProviderVerifyIDTokenChecksNonceAndAudience(t *testing.T) {
+	_, idp, provider := newTestFlow(t)
+	ctx := context.Background()
+	identity := oidctest.Identity{Subject: "user-123", Email: "ada@example.com", EmailVerified: true}
+
+	token, err := idp.SignIDToken(identity, "expected-nonce", time.Now())
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, token, "expected-nonce"); err != nil {
+		t.Fatalf("VerifyIDToken returned error: %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, token, "other-nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+		t.Fatalf("nonce mismatch error = %v, want ErrInvalidIDToken", err)
+	}
+
+	expired, err := idp.SignIDToken(identity, "expected-nonce", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, expired, "expected-nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+		t.Fatalf("expired token error = %v, want ErrInvalidIDToken", err)
+	}
+
+	otherClient := oidc.NewProvider(oidc.Config{IssuerURL: idp.Issuer(), ClientID: "someone-else", RedirectURL: "https://x.example.com"}, idp.Client())
+	if _, err := otherClient.VerifyIDToken(ctx, token, "expected-nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+		t.Fatalf("audience mismatch error = %v, want ErrInvalidIDToken", err)
+	}
+}
+
+func TestProviderExchangeEnforcesPKCE(t *testing.T) {
+	_, idp, provider := newTestFlow(t)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthorizationURL(ctx, "state", "nonce", "challenge-that-does-not-match")
+	if err != nil {
+		t.Fatalf("AuthorizationURL returned error: %v", err)
+	}
+	callback, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	if _, err := provider.Exchange(ctx, callback.Query().Get("code"), "wrong-verifier", "nonce"); !errors.Is(err, oidc.ErrTokenExchange) {
+		t.Fatalf("Exchange error = %v, want ErrTokenExchange", err)
+	}
+}
+
+func TestFlowWithoutProviderIsNotConfigured(t *testing.T) {
+	flow := oidc.NewFlow(nil, nil, nil)
+
+	if _, err := flow.Begin(context.Background()); !errors.Is(err, oidc.ErrNotConfigured) {
+		t.Fatalf("Begin error = %v, want ErrNotConfigured", err)
+	}
+}
@@ -0,0 +1,257 @@
+// Package oidctest provides a minimal in-process OpenID Connect identity
+// provider for tests and local development. It implements discovery, JWKS,
+// an authorization endpoint that signs in a configured identity without any
+// UI, and a token endpoint that enforces PKCE (S256).
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const signingKeyID = "stub-key-1"
+
+// Identity is the user the stub provider signs in.
+type Identity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
+
+type pendingCode struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	identity      Identity
+}
+
+// Provider is a stub identity provider. Use NewServer for an httptest-backed
+// instance or Handler to mount it on a real listener.
+type Provider struct {
+	ClientID     string
+	ClientSecret string
+
+	mu       sync.Mutex
+	issuer   string
+	key      *rsa.PrivateKey
+	identity Identity
+	codes    map[string]pendingCode
+}
+
+// NewProvider creates a stub provider that will answer as issuer.
+func NewProvider(issuer, clientID string) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{
+		ClientID: clientID,
+		issuer:   strings.TrimRight(issuer, "/"),
+		key:      key,
+		identity: Identity{Subject: "stub-subject", Email: "stub.user@example.com", EmailVerified: true, Name: "Stub User"},
+		codes:    map[string]pendingCode{},
+	}, nil
+}
+
+// Server bundles a stub provider with the httptest server hosting it.
+type Server struct {
+	*Provider
+	*httptest.Server
+}
+
+// NewServer starts a stub provider on a local httptest server.
+func NewServer(clientID string) (*Server, error) {
+	server := httptest.NewUnstartedServer(nil)
+	server.Start()
+	provider, err := NewProvider(server.URL, clientID)
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+	server.Config.Handler = provider.Handler()
+	return &Server{Provider: provider, Server: server}, nil
+}
+
+// Issuer returns the issuer identifier advertised by the provider.
+func (p *Provider) Issuer() string {
+	return p.issuer
+}
+
+// SetIdentity changes the identity signed in by subsequent authorization requests.
+func (p *Provider) SetIdentity(identity Identity) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.identity = identity
+}
+
+// Handler serves the provider endpoints.
+func (p *Provider) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	return mux
+}
+
+// Authorize performs the authorization step for an authorization URL and
+// returns the redirect the browser would follow, without an HTTP round trip.
+func (p *Provider) Authorize(authorizationURL string) (*url.URL, error) {
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		return nil, err
+	}
+	recorder := httptest.NewRecorder()
+	p.authorize(recorder, httptest.NewRequest(http.MethodGet, parsed.RequestURI(), nil))
+	return url.Parse(recorder.Header().Get("Location"))
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	public := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kid": signingKeyID,
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.String() == "" || query.Get("response_type") != "code" ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = pendingCode{
+		clientID:      query.Get("client_id"),
+		redirectURI:   redirectURI.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		identity:      p.identity,
+	}
+	p.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	pending, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	clientID := r.PostForm.Get("client_id")
+	if user, secret, hasBasic := r.BasicAuth(); hasBasic {
+		clientID, _ = url.QueryUnescape(user)
+		secret, _ = url.QueryUnescape(secret)
+		if p.ClientSecret != "" && secret != p.ClientSecret {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+	} else if p.ClientSecret != "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || clientID != pending.clientID || clientID != p.ClientID ||
+		r.PostForm.Get("redirect_uri") != pending.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != pending.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := p.SignIDToken(pending.identity, pending.nonce, time.Now())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+// SignIDToken issues an ID token for identity as the provider would.
+func (p *Provider) SignIDToken(identity Identity, nonce string, issuedAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            identity.Subject,
+		"aud":            p.ClientID,
+		"iat":            issuedAt.Unix(),
+		"exp":            issuedAt.Add(5 * time.Minute).Unix(),
+		"nonce":          nonce,
+		"email":          identity.Email,
+		"email_verified": identity.EmailVerified,
+	}
+	if identity.PreferredUsername != "" {
+		claims["preferred_username"] = identity.PreferredUsername
+	}
+	if identity.Name != "" {
+		claims["name"] = identity.Name
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = signingKeyID
+	return token.SignedString(p.key)
+}
+
+func randomString() string {
+	buf := make([]byte, 24)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"socialpredict/handlers"
+	dusers "socialpredict/internal/domain/users"
+	"socialpredict/internal/service/auth/oidc"
+	"socialpredict/logger"
+)
+
+// OIDCFlow is the relying-party flow used by the single sign-on handlers.
+type OIDCFlow interface {
+	Begin(ctx context.Context) (*oidc.Authorization, error)
+	Complete(ctx context.Context, code, state string) (*oidc.Claims, error)
+}
+
+// ExternalSignInService resolves verified external identities to local accounts.
+type ExternalSignInService interface {
+	SignInWithExternalIdentity(ctx context.Context, req dusers.ExternalSignInRequest) (*dusers.User, error)
+}
+
+// ExternalIdentityLinker connects an external identity to a signed-in account.
+type ExternalIdentityLinker interface {
+	LinkExternalIdentityToAccount(ctx context.Context, actor *dusers.User, req dusers.ExternalLinkRequest) (*dusers.ExternalIdentity, error)
+}
+
+// OIDCSignInSettings carries the provisioning policy applied after a successful
+// provider login and the audit recorder for completed sign-ins.
+type OIDCSignInSettings struct {
+	Policy                dusers.ExternalSignInPolicy
+	InitialAccountBalance func() int64
+	Now                   func() time.Time
+	Protection            LoginProtection
+}
+
+// oidcStateCookie binds a login attempt to the browser that started it. It is
+// scoped to the OIDC routes and never readable from script.
+const (
+	oidcStateCookie     = "sp_oidc_state"
+	oidcStateCookiePath = "/v0/auth/oidc"
+)
+
+type oidcAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	ExpiresAt        string `json:"expiresAt"`
+}
+
+type oidcCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+type oidcLinkResponse struct {
+	Issuer   string `json:"issuer"`
+	Username string `json:"username"`
+}
+
+// OIDCAuthorizeHandler starts a single sign-on attempt and returns the identity
+// provider URL the client should navigate to. The attempt's state is set in an
+// HttpOnly cookie, so only the browser that started the login can complete it.
+func OIDCAuthorizeHandler(flow OIDCFlow) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if flow == nil {
+			_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+			return
+		}
+
+		authorization, err := flow.Begin(r.Context())
+		if err != nil {
+			writeOIDCFailure(w, err)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    authorization.State,
+			Path:     oidcStateCookiePath,
+			Expires:  authorization.ExpiresAt.UTC(),
+			HttpOnly: true,
+			Secure:   isHTTPSRequest(r),
+			SameSite: http.SameSiteLaxMode,
+		})
+		_ = handlers.WriteResult(w, http.StatusOK, oidcAuthorizationResponse{
+			AuthorizationURL: authorization.URL,
+			ExpiresAt:        authorization.ExpiresAt.UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// OIDCCallbackHandler completes a single sign-on attempt and, on success,
+// returns the same bearer token payload as the password login route. The
+// state the provider echoed must match the state cookie set when the attempt
+// began; the cookie is cleared either way.
+func OIDCCallbackHandler(flow OIDCFlow, users ExternalSignInService, settings OIDCSignInSettings, jwtSigningKey ...[]byte) http.HandlerFunc {
+	key := currentJWTSigningKey()
+	if len(jwtSigningKey) > 0 {
+		key = jwtSigningKey[0]
+	}
+	key = cloneJWTKey(key)
+	if settings.Now == nil {
+		settings.Now = time.Now
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if flow == nil {
+			_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+			return
+		}
+		if users == nil || len(key) == 0 {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+
+		claims, ok := completeOIDCAttempt(w, r, flow)
+		if !ok {
+			return
+		}
+
+		var initialBalance int64
+		if settings.InitialAccountBalance != nil {
+			initialBalance = settings.InitialAccountBalance()
+		}
+		user, err := users.SignInWithExternalIdentity(r.Context(), dusers.ExternalSignInRequest{
+			Issuer:                       claims.Issuer,
+			Subject:                      claims.Subject,
+			Email:                        claims.Email,
+			EmailVerified:                claims.EmailVerified,
+			PreferredUsername:            claims.PreferredUsername,
+			Name:                         claims.Name,
+			Policy:                       settings.Policy,
+			DefaultInitialAccountBalance: initialBalance,
+			Now:                          settings.Now().UTC(),
+		})
+		if err != nil {
+			writeOIDCFailure(w, err)
+			return
+		}
+		if err := user.EnsureCanAuthenticate(settings.Now().UTC()); err != nil {
+			writeOIDCFailure(w, err)
+			return
+		}
+
+		tokenString, err := generateJWT(user.Username, key)
+		if err != nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		settings.Protection.record(r, dusers.LoginAttempt{
+			Username: user.Username,
+			Outcome:  dusers.LoginOutcomeSuccess,
+			Method:   dusers.LoginMethodOIDC,
+			Now:      settings.Now().UTC(),
+		})
+		_ = handlers.WriteResult(w, http.StatusOK, loginResponse{
+			Token:              tokenString,
+			Username:           user.Username,
+			UserType:           user.UserType,
+			ModeratorStatus:    string(user.ModeratorStatus),
+			MustChangePassword: user.MustChangePassword,
+			AccountStatus:      string(user.EffectiveAccountStatus(settings.Now().UTC())),
+		})
+	}
+}
+
+// OIDCLinkHandler completes a provider login started by a signed-in user and
+// links the identity to their account instead of signing in. This is how an
+// account whose email was never verified, or a privileged account, connects
+// single sign-on; the state cookie check is the same as the callback's.
+func OIDCLinkHandler(flow OIDCFlow, users ExternalIdentityLinker, auth Authenticator, now func() time.Time) http.HandlerFunc {
+	if now == nil {
+		now = time.Now
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			_ = handlers.WriteFailure(w, http.StatusMethodNotAllowed, handlers.ReasonMethodNotAllowed)
+			return
+		}
+		if flow == nil {
+			_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+			return
+		}
+		if users == nil || auth == nil {
+			_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+			return
+		}
+		actor, authErr := auth.RequireUser(r)
+		if authErr != nil {
+			writeOIDCAuthFailure(w, authErr)
+			return
+		}
+
+		claims, ok := completeOIDCAttempt(w, r, flow)
+		if !ok {
+			return
+		}
+		identity, err := users.LinkExternalIdentityToAccount(r.Context(), actor, dusers.ExternalLinkRequest{
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+			Email:   claims.Email,
+			Now:     now().UTC(),
+		})
+		if err != nil {
+			writeOIDCFailure(w, err)
+			return
+		}
+		_ = handlers.WriteResult(w, http.StatusOK, oidcLinkResponse{
+			Issuer:   identity.Issuer,
+			Username: identity.Username,
+		})
+	}
+}
+
+// completeOIDCAttempt decodes the code and state, checks the state against
+// the browser's state cookie (clearing it either way), and redeems the code.
+func completeOIDCAttempt(w http.ResponseWriter, r *http.Request, flow OIDCFlow) (*oidc.Claims, bool) {
+	var req oidcCallbackRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+		return nil, false
+	}
+
+	bound, _ := r.Cookie(oidcStateCookie)
+	clearOIDCStateCookie(w, r)
+	if bound == nil || bound.Value == "" || subtle.ConstantTimeCompare([]byte(bound.Value), []byte(req.State)) != 1 {
+		writeOIDCFailure(w, oidc.ErrInvalidState)
+		return nil, false
+	}
+
+	claims, err := flow.Complete(r.Context(), req.Code, req.State)
+	if err != nil {
+		writeOIDCFailure(w, err)
+		return nil, false
+	}
+	return claims, true
+}
+
+func clearOIDCStateCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     oidcStateCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPSRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// isHTTPSRequest reports whether the client reached us over TLS, directly or
+// through a proxy that says so.
+func isHTTPSRequest(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")), "https")
+}
+
+func writeOIDCFailure(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, oidc.ErrNotConfigured):
+		_ = handlers.WriteFailure(w, http.StatusNotFound, handlers.ReasonNotFound)
+	case errors.Is(err, oidc.ErrInvalidState):
+		_ = handlers.WriteFailure(w, http.StatusBadRequest, handlers.ReasonInvalidRequest)
+	case errors.Is(err, oidc.ErrTokenExchange), errors.Is(err, oidc.ErrInvalidIDToken):
+		logger.LogError("OIDCLogin", "Complete", err)
+		_ = handlers.WriteFailure(w, http.StatusUnauthorized, handlers.ReasonAuthorizationDenied)
+	case errors.Is(err, dusers.ErrExternalEmailUnverified), errors.Is(err, dusers.ErrExternalIdentityNotAllowed):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAuthorizationDenied)
+	case errors.Is(err, dusers.ErrExternalIdentityLinkRequired), errors.Is(err, dusers.ErrExternalIdentityAlreadyLinked):
+		_ = handlers.WriteFailureWithDetails(w, http.StatusConflict, handlers.ReasonInvalidState, err.Error(), nil)
+	case errors.Is(err, dusers.ErrAccountBanned):
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountBanned)
+	default:
+		logger.LogError("OIDCLogin", "SignIn", err)
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
+
+func writeOIDCAuthFailure(w http.ResponseWriter, err *AuthError) {
+	switch err.Kind {
+	case ErrorKindMissingToken, ErrorKindInvalidToken:
+		_ = handlers.WriteFailure(w, http.StatusUnauthorized, handlers.ReasonInvalidToken)
+	case ErrorKindPasswordChangeRequired:
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonPasswordChangeRequired)
+	case ErrorKindAccountBanned:
+		_ = handlers.WriteFailure(w, http.StatusForbidden, handlers.ReasonAccountBanned)
+	default:
+		_ = handlers.WriteFailure(w, http.StatusInternalServerError, handlers.ReasonInternalError)
+	}
+}
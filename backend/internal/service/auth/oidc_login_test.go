@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"socialpredict/handlers"
+	dusers "socialpredict/internal/domain/users"
+	rusers "socialpredict/internal/repository/users"
+	"socialpredict/internal/service/auth/oidc"
+	"socialpredict/internal/service/auth/oidc/oidctest"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+	"socialpredict/security"
+
+	"gorm.io/gorm"
+)
+
+func newOIDCLoginFixture(t *testing.T, policy dusers.ExternalSignInPolicy) (*oidctest.Server, http.HandlerFunc, http.HandlerFunc, *gorm.DB) {
+	t.Helper()
+	idp, err := oidctest.NewServer("socialpredict")
+	if err != nil {
+		t.Fatalf("start stub idp: %v", err)
+	}
+	t.Cleanup(idp.Close)
+
+	db := modelstesting.NewFakeDB(t)
+	repo := rusers.NewGormRepository(db)
+	usersService := dusers.NewService(repo, nil, security.NewSecurityService().Sanitizer)
+	provider := oidc.NewProvider(oidc.Config{
+		IssuerURL:   idp.Issuer(),
+		ClientID:    "socialpredict",
+		RedirectURL: "https://predict.example.com/auth/oidc/callback",
+	}, idp.Client())
+	flow := oidc.NewFlow(provider, oidc.NewGormStateStore(db), time.Now)
+
+	settings := OIDCSignInSettings{Policy: policy, InitialAccountBalance: func() int64 { return 0 }}
+	return idp, OIDCAuthorizeHandler(flow), OIDCCallbackHandler(flow, usersService, settings, []byte("test-secret-key-for-testing")), db
+}
+
+func runOIDCLogin(t *testing.T, idp *oidctest.Server, authorize, callback http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	code, state, cookie := beginOIDCLogin(t, idp, authorize)
+	return completeOIDCLogin(callback, code, state, cookie)
+}
+
+// beginOIDCLogin starts a login, follows the stub provider, and returns the
+// code and state it echoed with the state cookie the browser would hold.
+func beginOIDCLogin(t *testing.T, idp *oidctest.Server, authorize http.HandlerFunc) (string, string, *http.Cookie) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	authorize(rec, httptest.NewRequest(http.MethodGet, "/v0/auth/oidc/authorize", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("authorize status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var started handlers.SuccessEnvelope[oidcAuthorizationResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &started); err != nil {
+		t.Fatalf("decode authorize response: %v", err)
+	}
+	var cookie *http.Cookie
+	for _, set := range rec.Result().Cookies() {
+		if set.Name == oidcStateCookie {
+			cookie = set
+		}
+	}
+	if cookie == nil || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != oidcStateCookiePath {
+		t.Fatalf("expected an HttpOnly SameSite state cookie, got %+v", cookie)
+	}
+
+	redirect, err := idp.Authorize(started.Result.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("stub authorize: %v", err)
+	}
+	return redirect.Query().Get("code"), redirect.Query().Get("state"), cookie
+}
+
+func completeOIDCLogin(callback http.HandlerFunc, code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(oidcCallbackRequest{Code: code, State: state})
+	req := httptest.NewRequest(http.MethodPost, "/v0/auth/oidc/callback", bytes.NewReader(body))
+	if cookie != nil {
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	rec := httptest.NewRecorder()
+	callback(rec, req)
+	return rec
+}
+
+func TestOIDCLoginLinksExistingUserAndIssuesToken(t *testing.T) {
+	idp, authorize, callback, db := newOIDCLoginFixture(t, dusers.ExternalSignInPolicy{AllowedEmailDomains: []string{"example.com"}})
+	existing := modelstesting.GenerateUser("ada", 1000)
+	existing.Email = "ada@example.com"
+	verifiedAt := time.Now().UTC()
+	existing.EmailVerifiedAt = &verifiedAt
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+	idp.SetIdentity(oidctest.Identity{Subject: "ada-sub", Email: "ADA@example.com", EmailVerified: true})
+
+	rec := runOIDCLogin(t, idp, authorize, callback)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("callback status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var resp handlers.SuccessEnvelope[loginResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode callback response: %v", err)
+	}
+	if resp.Result.Username != "ada" || resp.Result.Token == "" {
+		t.Fatalf("unexpected login response: %+v", resp.Result)
+	}
+
+	user, authErr := ValidateTokenAndGetUserFromToken(t.Context(), resp.Result.Token, dusers.NewService(rusers.NewGormRepository(db), nil, nil), []byte("test-secret-key-for-testing"))
+	if authErr != nil || user.Username != "ada" {
+		t.Fatalf("issued token did not authenticate: user=%+v err=%v", user, authErr)
+	}
+}
+
+func TestOIDCCallbackRequiresTheBrowserStateCookie(t *testing.T) {
+	idp, authorize, callback, db := newOIDCLoginFixture(t, dusers.ExternalSignInPolicy{})
+	existing := modelstesting.GenerateUser("ada", 1000)
+	existing.Email = "ada@example.com"
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+	idp.SetIdentity(oidctest.Identity{Subject: "ada-sub", Email: "ada@example.com", EmailVerified: true})
+
+	code, state, _ := beginOIDCLogin(t, idp, authorize)
+	if rec := completeOIDCLogin(callback, code, state, nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("callback without state cookie status = %d, want 400 body=%s", rec.Code, rec.Body.String())
+	}
+
+	// A code and state started in another browser cannot be completed with
+	// this browser's cookie.
+	code, state, _ = beginOIDCLogin(t, idp, authorize)
+	_, _, victimCookie := beginOIDCLogin(t, idp, authorize)
+	if rec := completeOIDCLogin(callback, code, state, victimCookie); rec.Code != http.StatusBadRequest {
+		t.Fatalf("callback with another attempt's cookie status = %d, want 400 body=%s", rec.Code, rec.Body.String())
+	}
+
+	var identities int64
+	db.Model(&models.UserExternalIdentity{}).Count(&identities)
+	if identities != 0 {
+		t.Fatalf("rejected callbacks must not link identities, got %d", identities)
+	}
+}
+
+func TestOIDCLoginRefusesPreRegisteredUnverifiedEmail(t *testing.T) {
+	idp, authorize, callback, db := newOIDCLoginFixture(t, dusers.ExternalSignInPolicy{AutoProvision: true, AllowedEmailDomains: []string{"example.com"}})
+	squatter := modelstesting.GenerateUser("squatter", 0)
+	squatter.Email = "victim@example.com"
+	if err := db.Create(&squatter).Error; err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+	idp.SetIdentity(oidctest.Identity{Subject: "victim-sub", Email: "victim@example.com", EmailVerified: true})
+
+	rec := runOIDCLogin(t, idp, authorize, callback)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("callback status = %d, want 409 body=%s", rec.Code, rec.Body.String())
+	}
+	var identities int64
+	db.Model(&models.UserExternalIdentity{}).Count(&identities)
+	if identities != 0 {
+		t.Fatalf("unverified account must not be linked, got %d identities", identities)
+	}
+}
+
+func TestOIDCLoginNeverLinksAdminAccountsByEmail(t *testing.T) {
+	idp, authorize, callback, db := newOIDCLoginFixture(t, dusers.ExternalSignInPolicy{AllowedEmailDomains: []string{"example.com"}})
+	admin := modelstesting.GenerateUser("root", 0)
+	admin.Email = "root@example.com"
+	admin.UserType = "ADMIN"
+	if err := db.Create(&admin).Error; err != nil {
+		t.Fatalf("seed admin: %v", err)
+	}
+	idp.SetIdentity(oidctest.Identity{Subject: "root-sub", Email: "root@example.com", EmailVerified: true})
+
+	rec := runOIDCLogin(t, idp, authorize, callback)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("callback status = %d, want 403 body=%s", rec.Code, rec.Body.String())
+	}
+	var identities int64
+	db.Model(&models.UserExternalIdentity{}).Count(&identities)
+	if identities != 0 {
+		t.Fatalf("admin account must not be linked, got %d identities", identities)
+	}
+}
+
+func TestOIDCLoginRefusesUnlistedNewUsers(t *testing.T) {
+	policy := dusers.ExternalSignInPolicy{AutoProvision: true, AllowedEmailDomains: []string{"example.com"}}
+	idp, authorize, callback, db := newOIDCLoginFixture(t, policy)
+	idp.SetIdentity(oidctest.Identity{Subject: "eve-sub", Email: "eve@elsewhere.test", EmailVerified: true})
+
+	rec := runOIDCLogin(t, idp, authorize, callback)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("callback status = %d, want 403 body=%s", rec.Code, rec.Body.String())
+	}
+	var count int64
+	db.Model(&models.User{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no users provisioned, got %d", count)
+	}
+}
+
+func TestOIDCLoginProvisionsAllowedDomain(t *testing.T) {
+	policy := dusers.ExternalSignInPolicy{AutoProvision: true, AllowedEmailDomains: []string{"example.com"}}
+	idp, authorize, callback, db := newOIDCLoginFixture(t, policy)
+	idp.SetIdentity(oidctest.Identity{Subject: "lin-sub", Email: "lin@example.com", EmailVerified: true, PreferredUsername: "lin"})
+
+	rec := runOIDCLogin(t, idp, authorize, callback)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("callback status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var identity models.UserExternalIdentity
+	if err := db.Where("subject = ?", "lin-sub").First(&identity).Error; err != nil {
+		t.Fatalf("expected identity link: %v", err)
+	}
+	if identity.Username != "lin" || identity.Issuer != idp.Issuer() {
+		t.Fatalf("unexpected identity link: %+v", identity)
+	}
+}
+
+func TestOIDCHandlersReportNotFoundWhenDisabled(t *testing.T) {
+	rec := httptest.NewRecorder()
+	OIDCAuthorizeHandler(nil)(rec, httptest.NewRequest(http.MethodGet, "/v0/auth/oidc/authorize", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("authorize status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	OIDCCallbackHandler(nil, nil, OIDCSignInSettings{})(rec, httptest.NewRequest(http.MethodPost, "/v0/auth/oidc/callback", bytes.NewBufferString(`{}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("callback status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"socialpredict/internal/domain/permissions"
+	dusers "socialpredict/internal/domain/users"
+)
+
+// PermissionAuthenticator is implemented by authenticators that can resolve
+// fine-grained permissions rather than only the ADMIN user type.
+type PermissionAuthenticator interface {
+	RequirePermission(r *http.Request, permission permissions.Permission) (*dusers.User, *AuthError)
+}
+
+// RequirePermission resolves the current user and ensures it holds permission.
+// Authenticators without permission support fall back to RequireAdmin.
+func RequirePermission(auth Authenticator, r *http.Request, permission permissions.Permission) (*dusers.User, *AuthError) {
+	if auth == nil {
+		return nil, newAuthError(ErrorKindServiceUnavailable)
+	}
+	if checker, ok := auth.(PermissionAuthenticator); ok {
+		return checker.RequirePermission(r, permission)
+	}
+	return auth.RequireAdmin(r)
+}
+
+// SetAuthorizer configures how RequirePermission resolves grants. Without one,
+// only admins hold permissions.
+func (a *AuthService) SetAuthorizer(authorizer permissions.Authorizer) {
+	if a != nil {
+		a.authorizer = authorizer
+	}
+}
+
+// RequirePermission ensures the current user is authenticated and holds permission.
+func (a *AuthService) RequirePermission(r *http.Request, permission permissions.Permission) (*dusers.User, *AuthError) {
+	tokenString, authErr := tokenFromRequest(r)
+	if authErr != nil {
+		return nil, authErr
+	}
+	return a.RequirePermissionFromToken(r.Context(), tokenString, permission)
+}
+
+// RequirePermissionFromToken resolves the user from an extracted token and
+// ensures it holds permission.
+func (a *AuthService) RequirePermissionFromToken(ctx context.Context, tokenString string, permission permissions.Permission) (*dusers.User, *AuthError) {
+	user, authErr := a.CurrentUserFromToken(ctx, tokenString)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	allowed, err := permissions.OrDefault(a.authorizer).Can(ctx, user.PermissionSubject(), permission)
+	if err != nil {
+		return nil, newAuthError(ErrorKindServiceUnavailable)
+	}
+	if !allowed {
+		return nil, newAuthError(ErrorKindPermissionDenied)
+	}
+	return user, nil
+}
@@ -0,0 +1,55 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	demail "socialpredict/internal/domain/email"
+)
+
+// FileSink writes each email to its own .eml file instead of sending it, so
+// local and test deployments can inspect exactly what would go out.
+type FileSink struct {
+	dir  string
+	from *mail.Address
+	now  func() time.Time
+}
+
+var _ demail.Transport = (*FileSink)(nil)
+
+// NewFileSink builds a sink that writes into dir, creating it if needed.
+func NewFileSink(dir, from string) (*FileSink, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileSink{dir: dir, from: sender, now: time.Now}, nil
+}
+
+// Send writes the rendered message to a new file named after the time it
+// was written.
+func (s *FileSink) Send(ctx context.Context, envelope demail.Envelope) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	_, message, err := buildMessage(s.from, envelope, now)
+	if err != nil {
+		return err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := now.Format("20060102T150405.000000000Z") + "-" + hex.EncodeToString(suffix) + ".eml"
+	return os.WriteFile(filepath.Join(s.dir, name), message, 0o600)
+}
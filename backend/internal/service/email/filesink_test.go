@@ -0,0 +1,98 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	demail "socialpredict/internal/domain/email"
+)
+
+func TestFileSinkWritesMultipartMessage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	sink, err := NewFileSink(dir, "SocialPredict <noreply@example.com>")
+	if err != nil {
+		t.Fatalf("NewFileSink returned error: %v", err)
+	}
+	err = sink.Send(context.Background(), demail.Envelope{
+		To:       "alice@example.com",
+		Subject:  "Résolu: Will it rain?",
+		TextBody: "Hi alice, you made +40.",
+		HTMLBody: "<p>Hi alice, you made <strong>+40</strong>.</p>",
+		Headers:  map[string]string{"List-Unsubscribe": "<https://example.com/api/v0/email/unsubscribe?token=abc>"},
+	})
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one .eml file, got %v, %v", files, err)
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	message, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil || subject != "Résolu: Will it rain?" {
+		t.Fatalf("subject = %q, %v", subject, err)
+	}
+	if got := message.Header.Get("To"); got != "<alice@example.com>" {
+		t.Fatalf("To = %q", got)
+	}
+	if got := message.Header.Get("List-Unsubscribe"); !strings.Contains(got, "token=abc") {
+		t.Fatalf("List-Unsubscribe = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q, %v", mediaType, err)
+	}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		bodies = append(bodies, string(content))
+	}
+	if len(bodies) != 2 || bodies[0] != "Hi alice, you made +40." || !strings.Contains(bodies[1], "<strong>+40</strong>") {
+		t.Fatalf("unexpected parts: %q", bodies)
+	}
+}
+
+func TestBuildMessageRejectsBadAddressesAndHeaderInjection(t *testing.T) {
+	sink, err := NewFileSink(t.TempDir(), "noreply@example.com")
+	if err != nil {
+		t.Fatalf("NewFileSink returned error: %v", err)
+	}
+	if err := sink.Send(context.Background(), demail.Envelope{To: "not an address", Subject: "hi", TextBody: "x"}); err == nil {
+		t.Fatalf("expected invalid recipient error")
+	}
+	err = sink.Send(context.Background(), demail.Envelope{To: "alice@example.com", Subject: "hi", TextBody: "x", Headers: map[string]string{"X-Test": "a\r\nBcc: eve@example.com"}})
+	if !errors.Is(err, errHeaderInjection) {
+		t.Fatalf("header injection error = %v", err)
+	}
+	if _, err := NewSMTPTransport(SMTPConfig{Host: "smtp.example.com", Port: 587, From: ""}); err == nil {
+		t.Fatalf("expected invalid sender error")
+	}
+}
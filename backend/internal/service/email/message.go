@@ -0,0 +1,98 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	demail "socialpredict/internal/domain/email"
+)
+
+// errHeaderInjection rejects header values that would start a new header.
+var errHeaderInjection = errors.New("email header contains a line break")
+
+// buildMessage renders envelope as an RFC 5322 message with plain-text and
+// HTML alternatives. It returns the bare recipient address and the bytes to
+// hand to the mail system.
+func buildMessage(from *mail.Address, envelope demail.Envelope, now time.Time) (string, []byte, error) {
+	to, err := mail.ParseAddress(envelope.To)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+	headers := map[string]string{
+		"From":         from.String(),
+		"To":           to.String(),
+		"Subject":      mime.QEncoding.Encode("utf-8", envelope.Subject),
+		"Date":         now.UTC().Format(time.RFC1123Z),
+		"Message-ID":   messageID(from.Address),
+		"MIME-Version": "1.0",
+	}
+	for key, value := range envelope.Headers {
+		headers[textproto.CanonicalMIMEHeaderKey(key)] = value
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", envelope.TextBody},
+		{"text/html; charset=utf-8", envelope.HTMLBody},
+	} {
+		if part.content == "" {
+			continue
+		}
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return "", nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return "", nil, err
+	}
+	headers["Content-Type"] = `multipart/alternative; boundary="` + parts.Boundary() + `"`
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var out bytes.Buffer
+	for _, key := range keys {
+		if strings.ContainsAny(headers[key], "\r\n") {
+			return "", nil, errHeaderInjection
+		}
+		fmt.Fprintf(&out, "%s: %s\r\n", key, headers[key])
+	}
+	out.WriteString("\r\n")
+	out.Write(body.Bytes())
+	return to.Address, out.Bytes(), nil
+}
+
+func messageID(fromAddress string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 && at < len(fromAddress)-1 {
+		domain = fromAddress[at+1:]
+	}
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">"
+}
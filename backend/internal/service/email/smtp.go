@@ -0,0 +1,63 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	demail "socialpredict/internal/domain/email"
+)
+
+// SMTPConfig describes the relay outgoing email is submitted to.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPTransport submits email to an SMTP relay. net/smtp upgrades to TLS
+// when the relay offers STARTTLS and refuses to send credentials otherwise,
+// except to localhost.
+type SMTPTransport struct {
+	addr string
+	auth smtp.Auth
+	from *mail.Address
+	now  func() time.Time
+}
+
+var _ demail.Transport = (*SMTPTransport)(nil)
+
+// NewSMTPTransport validates the sender address and builds a transport.
+func NewSMTPTransport(config SMTPConfig) (*SMTPTransport, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+	transport := &SMTPTransport{
+		addr: net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		from: from,
+		now:  time.Now,
+	}
+	if config.Username != "" {
+		transport.auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+	return transport, nil
+}
+
+// Send submits the envelope to the relay.
+func (t *SMTPTransport) Send(ctx context.Context, envelope demail.Envelope) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	to, message, err := buildMessage(t.from, envelope, t.now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(t.addr, t.auth, t.from.Address, []string{to}, message)
+}
@@ -0,0 +1,85 @@
+package email
+
+import (
+	"context"
+	"time"
+
+	"socialpredict/logger"
+)
+
+const (
+	defaultPollInterval  = 10 * time.Second
+	defaultSweepInterval = time.Hour
+)
+
+type mailer interface {
+	DeliverDue(ctx context.Context) (int, error)
+	RemindStewards(ctx context.Context) (int, error)
+	SendWeeklyDigests(ctx context.Context) (int, error)
+}
+
+// Worker sends due email and periodically queues steward reminders and
+// weekly digests until its context is cancelled.
+type Worker struct {
+	mailer        mailer
+	pollInterval  time.Duration
+	sweepInterval time.Duration
+}
+
+// NewWorker builds an email worker. Non-positive intervals poll every ten
+// seconds and sweep hourly.
+func NewWorker(mailer mailer, pollInterval, sweepInterval time.Duration) *Worker {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	if sweepInterval <= 0 {
+		sweepInterval = defaultSweepInterval
+	}
+	return &Worker{mailer: mailer, pollInterval: pollInterval, sweepInterval: sweepInterval}
+}
+
+// Run sweeps once at start and then every sweep interval, and delivers due
+// batches every poll interval. A full batch is followed immediately by the
+// next one so a backlog drains without waiting a tick.
+func (w *Worker) Run(ctx context.Context) {
+	if w == nil || w.mailer == nil {
+		return
+	}
+	poll := time.NewTicker(w.pollInterval)
+	defer poll.Stop()
+	sweep := time.NewTicker(w.sweepInterval)
+	defer sweep.Stop()
+
+	w.sweep(ctx)
+	for {
+		w.drain(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-sweep.C:
+			w.sweep(ctx)
+		case <-poll.C:
+		}
+	}
+}
+
+func (w *Worker) sweep(ctx context.Context) {
+	if _, err := w.mailer.RemindStewards(ctx); err != nil && ctx.Err() == nil {
+		logger.LogError("email", "RemindStewards", err)
+	}
+	if _, err := w.mailer.SendWeeklyDigests(ctx); err != nil && ctx.Err() == nil {
+		logger.LogError("email", "SendWeeklyDigests", err)
+	}
+}
+
+func (w *Worker) drain(ctx context.Context) {
+	for {
+		claimed, err := w.mailer.DeliverDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.LogError("email", "DeliverDue", err)
+		}
+		if claimed == 0 || err != nil || ctx.Err() != nil {
+			return
+		}
+	}
+}
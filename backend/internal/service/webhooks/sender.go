@@ -0,0 +1,107 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	dwebhooks "socialpredict/internal/domain/webhooks"
+)
+
+const (
+	defaultSendTimeout = 10 * time.Second
+	dialTimeout        = 5 * time.Second
+	userAgent          = "SocialPredict-Webhooks/1"
+	maxDrainBytes      = 64 << 10
+)
+
+var errBlockedDestination = errors.New("webhook destination is not a public https address")
+
+// HTTPSender posts webhook deliveries over HTTP. Redirects are not followed
+// so a receiver cannot bounce a signed payload to another host, and unless
+// insecure targets are allowed only https URLs are sent and every dialed
+// address must be public, so a hostname that resolves (or later rebinds) to
+// an internal address is refused.
+type HTTPSender struct {
+	client        *http.Client
+	allowInsecure bool
+}
+
+var _ dwebhooks.Sender = (*HTTPSender)(nil)
+
+// NewHTTPSender builds a sender. A zero timeout uses 10 seconds.
+// allowInsecureTargets permits http and internal addresses for local development.
+func NewHTTPSender(timeout time.Duration, allowInsecureTargets bool) *HTTPSender {
+	if timeout <= 0 {
+		timeout = defaultSendTimeout
+	}
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if !allowInsecureTargets {
+		dialer.Control = refuseInternalAddresses
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the receiver, bypassing the address check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowInsecure: allowInsecureTargets,
+	}
+}
+
+// refuseInternalAddresses runs after name resolution, once per address the
+// dialer tries, so it sees the IP actually being connected to.
+func refuseInternalAddresses(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !dwebhooks.PublicAddress(addr) {
+		return fmt.Errorf("%w: %s", errBlockedDestination, host)
+	}
+	return nil
+}
+
+// Send posts the request body and returns the response status code.
+func (s *HTTPSender) Send(ctx context.Context, request dwebhooks.Request) (int, error) {
+	if !s.allowInsecure {
+		parsed, err := url.Parse(request.URL)
+		if err != nil {
+			return 0, err
+		}
+		if parsed.Scheme != "https" {
+			return 0, errBlockedDestination
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return 0, err
+	}
+	for key, value := range request.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,74 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dwebhooks "socialpredict/internal/domain/webhooks"
+)
+
+func TestHTTPSenderPostsHeadersAndDoesNotFollowRedirects(t *testing.T) {
+	var gotBody string
+	var gotHeaders http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("redirect target should not be called")
+	}))
+	defer target.Close()
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotHeaders = r.Header.Clone()
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	sender := NewHTTPSender(0, true)
+	status, err := sender.Send(context.Background(), dwebhooks.Request{
+		URL:     receiver.URL + "/hook",
+		Headers: map[string]string{dwebhooks.SignatureHeader: "t=1,v1=abc", "Content-Type": "application/json"},
+		Body:    []byte(`{"id":"evt_1"}`),
+	})
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("Send = %d, %v", status, err)
+	}
+	if gotBody != `{"id":"evt_1"}` || gotHeaders.Get(dwebhooks.SignatureHeader) != "t=1,v1=abc" || gotHeaders.Get("User-Agent") != userAgent {
+		t.Fatalf("unexpected request: body=%s headers=%v", gotBody, gotHeaders)
+	}
+
+	status, err = sender.Send(context.Background(), dwebhooks.Request{URL: receiver.URL + "/redirect", Body: []byte(`{}`)})
+	if err != nil || status != http.StatusTemporaryRedirect {
+		t.Fatalf("redirect Send = %d, %v", status, err)
+	}
+}
+
+func TestHTTPSenderRefusesInternalAndPlainHTTPDestinations(t *testing.T) {
+	called := false
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	sender := NewHTTPSender(0, false)
+	for _, target := range []string{
+		receiver.URL + "/hook",
+		"http://hooks.example.com/hook",
+		"https://169.254.169.254/latest/meta-data",
+	} {
+		status, err := sender.Send(context.Background(), dwebhooks.Request{URL: target, Body: []byte(`{}`)})
+		if !errors.Is(err, errBlockedDestination) || status != 0 {
+			t.Fatalf("Send(%s) = %d, %v; want errBlockedDestination", target, status, err)
+		}
+	}
+	if called {
+		t.Fatalf("loopback receiver must not be reached")
+	}
+}
@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"socialpredict/logger"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+type deliverer interface {
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// Worker polls for due webhook deliveries until its context is cancelled.
+type Worker struct {
+	deliverer    deliverer
+	pollInterval time.Duration
+}
+
+// NewWorker builds a delivery worker. A non-positive interval polls every
+// five seconds.
+func NewWorker(deliverer deliverer, pollInterval time.Duration) *Worker {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	return &Worker{deliverer: deliverer, pollInterval: pollInterval}
+}
+
+// Run delivers due batches until ctx is cancelled. A full batch is followed
+// immediately by the next one so a backlog drains without waiting a tick.
+func (w *Worker) Run(ctx context.Context) {
+	if w == nil || w.deliverer == nil {
+		return
+	}
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			claimed, err := w.deliverer.DeliverDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.LogError("webhooks", "DeliverDue", err)
+			}
+			if claimed == 0 || err != nil || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddUserInvites adds invite codes for self-registration and the
+// redemption log that records which accounts consumed them.
+func MigrateAddUserInvites(db *gorm.DB) error {
+	return db.AutoMigrate(&models.UserInvite{}, &models.UserInviteRedemption{})
+}
+
+func init() {
+	migration.Register("20260622090000", func(db *gorm.DB) error {
+		return MigrateAddUserInvites(db)
+	})
+}
@@ -0,0 +1,37 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddUserInvitesCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddUserInvites(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.UserInvite{}) {
+		t.Fatalf("expected user invites table")
+	}
+	if !db.Migrator().HasTable(&models.UserInviteRedemption{}) {
+		t.Fatalf("expected user invite redemptions table")
+	}
+	if !db.Migrator().HasColumn(&models.UserInvite{}, "CodeHash") {
+		t.Fatalf("expected hashed invite code column")
+	}
+}
+
+func TestMigrateAddUserInvitesIsIdempotent(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+	if err := migrations.MigrateAddUserInvites(db); err != nil {
+		t.Fatalf("first migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddUserInvites(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddOIDCLogin adds linked external identities and the short-lived
+// state rows used by the OpenID Connect login flow.
+func MigrateAddOIDCLogin(db *gorm.DB) error {
+	return db.AutoMigrate(&models.UserExternalIdentity{}, &models.OIDCLoginState{})
+}
+
+func init() {
+	migration.Register("20260623090000", func(db *gorm.DB) error {
+		return MigrateAddOIDCLogin(db)
+	})
+}
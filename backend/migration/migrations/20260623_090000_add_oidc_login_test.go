@@ -0,0 +1,30 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddOIDCLoginCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddOIDCLogin(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddOIDCLogin(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.UserExternalIdentity{}) {
+		t.Fatalf("expected user external identities table")
+	}
+	if !db.Migrator().HasIndex(&models.UserExternalIdentity{}, "idx_user_external_identity_subject") {
+		t.Fatalf("expected unique issuer/subject index")
+	}
+	if !db.Migrator().HasTable(&models.OIDCLoginState{}) {
+		t.Fatalf("expected oidc login states table")
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddLoginSecurity adds persistent per-account login failure counters
+// and the login audit trail.
+func MigrateAddLoginSecurity(db *gorm.DB) error {
+	return db.AutoMigrate(&models.UserLoginThrottle{}, &models.LoginAuditEvent{})
+}
+
+func init() {
+	migration.Register("20260624090000", func(db *gorm.DB) error {
+		return MigrateAddLoginSecurity(db)
+	})
+}
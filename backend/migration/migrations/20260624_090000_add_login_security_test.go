@@ -0,0 +1,37 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddLoginSecurityCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddLoginSecurity(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.UserLoginThrottle{}) {
+		t.Fatalf("expected login throttle table")
+	}
+	if !db.Migrator().HasTable(&models.LoginAuditEvent{}) {
+		t.Fatalf("expected login audit events table")
+	}
+	if !db.Migrator().HasColumn(&models.UserLoginThrottle{}, "LockedUntil") {
+		t.Fatalf("expected lockout column")
+	}
+}
+
+func TestMigrateAddLoginSecurityIsIdempotent(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+	if err := migrations.MigrateAddLoginSecurity(db); err != nil {
+		t.Fatalf("first migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddLoginSecurity(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MigrateAddAccessRoles adds stored role grants and user role assignments and
+// seeds the built-in roles plus a CMS_EDITOR role holding cms.edit.
+func MigrateAddAccessRoles(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.AccessRole{}, &models.AccessRolePermission{}, &models.UserRoleAssignment{}); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	roles := []models.AccessRole{
+		{Name: "MODERATOR", Description: "Built-in role for moderator accounts.", CreatedAt: now, UpdatedAt: now},
+		{Name: "REGULAR", Description: "Built-in role for every other account.", CreatedAt: now, UpdatedAt: now},
+		{Name: "CMS_EDITOR", Description: "Edits homepage and discovery content without full admin access.", CreatedAt: now, UpdatedAt: now},
+	}
+	if err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&roles).Error; err != nil {
+		return err
+	}
+
+	grants := []models.AccessRolePermission{
+		{Role: "CMS_EDITOR", Permission: "cms.edit", CreatedAt: now},
+	}
+	return db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "role"}, {Name: "permission"}}, DoNothing: true}).Create(&grants).Error
+}
+
+func init() {
+	migration.Register("20260625090000", func(db *gorm.DB) error {
+		return MigrateAddAccessRoles(db)
+	})
+}
@@ -0,0 +1,44 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddAccessRolesCreatesTablesAndSeeds(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddAccessRoles(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.AccessRole{}, &models.AccessRolePermission{}, &models.UserRoleAssignment{}} {
+		if !db.Migrator().HasTable(model) {
+			t.Fatalf("expected table for %T", model)
+		}
+	}
+
+	var grant models.AccessRolePermission
+	if err := db.Where("role = ? AND permission = ?", "CMS_EDITOR", "cms.edit").First(&grant).Error; err != nil {
+		t.Fatalf("expected seeded CMS_EDITOR grant: %v", err)
+	}
+}
+
+func TestMigrateAddAccessRolesIsIdempotent(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+	if err := migrations.MigrateAddAccessRoles(db); err != nil {
+		t.Fatalf("first migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddAccessRoles(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	var roles int64
+	db.Model(&models.AccessRole{}).Count(&roles)
+	if roles != 3 {
+		t.Fatalf("expected 3 seeded roles, got %d", roles)
+	}
+}
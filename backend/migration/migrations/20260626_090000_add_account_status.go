@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddAccountStatus adds admin suspension and ban columns to users and
+// the account status audit trail.
+func MigrateAddAccountStatus(db *gorm.DB) error {
+	m := db.Migrator()
+
+	for _, column := range []string{"AccountStatus", "AccountStatusReason", "AccountStatusBy", "AccountStatusAt", "AccountStatusExpiresAt"} {
+		if m.HasColumn(&models.User{}, column) {
+			continue
+		}
+		if err := m.AddColumn(&models.User{}, column); err != nil {
+			return err
+		}
+	}
+
+	if err := db.AutoMigrate(&models.AccountStatusAudit{}); err != nil {
+		return err
+	}
+
+	return db.Model(&models.User{}).
+		Where("account_status IS NULL OR account_status = ''").
+		Update("account_status", "active").Error
+}
+
+func init() {
+	migration.Register("20260626090000", func(db *gorm.DB) error {
+		return MigrateAddAccountStatus(db)
+	})
+}
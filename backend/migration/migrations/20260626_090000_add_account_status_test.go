@@ -0,0 +1,54 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+var accountStatusColumns = []string{"AccountStatus", "AccountStatusReason", "AccountStatusBy", "AccountStatusAt", "AccountStatusExpiresAt"}
+
+func TestMigrateAddAccountStatusAddsColumnsAndAuditTable(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+
+	_ = db.Migrator().DropTable(&models.AccountStatusAudit{})
+	for _, column := range accountStatusColumns {
+		_ = db.Migrator().DropColumn(&models.User{}, column)
+	}
+	if err := db.Exec("INSERT INTO users (username, display_name, user_type, email, api_key, password) VALUES ('legacy', 'Legacy', 'REGULAR', 'legacy@example.com', 'legacy-key', 'x')").Error; err != nil {
+		t.Fatalf("seed legacy user: %v", err)
+	}
+
+	if err := migrations.MigrateAddAccountStatus(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	for _, column := range accountStatusColumns {
+		if !db.Migrator().HasColumn(&models.User{}, column) {
+			t.Fatalf("expected users column %s", column)
+		}
+	}
+	if !db.Migrator().HasTable(&models.AccountStatusAudit{}) {
+		t.Fatalf("expected account status audit table")
+	}
+
+	var status string
+	if err := db.Table("users").Select("account_status").Where("username = ?", "legacy").Scan(&status).Error; err != nil {
+		t.Fatalf("read account status: %v", err)
+	}
+	if status != "active" {
+		t.Fatalf("expected legacy user to be active, got %q", status)
+	}
+}
+
+func TestMigrateAddAccountStatusIsIdempotent(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	if err := migrations.MigrateAddAccountStatus(db); err != nil {
+		t.Fatalf("first migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddAccountStatus(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+}
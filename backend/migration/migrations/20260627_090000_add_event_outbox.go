@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddEventOutbox creates the transactional outbox for domain events and
+// the per-subscriber delivery ledger.
+func MigrateAddEventOutbox(db *gorm.DB) error {
+	return db.AutoMigrate(&models.OutboxEvent{}, &models.OutboxDelivery{})
+}
+
+func init() {
+	migration.Register("20260627090000", func(db *gorm.DB) error {
+		return MigrateAddEventOutbox(db)
+	})
+}
@@ -0,0 +1,29 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddEventOutboxCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddEventOutbox(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddEventOutbox(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.OutboxEvent{}, &models.OutboxDelivery{}} {
+		if !db.Migrator().HasTable(model) {
+			t.Fatalf("expected table for %T", model)
+		}
+	}
+	if !db.Migrator().HasIndex(&models.OutboxDelivery{}, "idx_outbox_deliveries_event_subscriber") {
+		t.Fatalf("expected unique event/subscriber delivery index")
+	}
+}
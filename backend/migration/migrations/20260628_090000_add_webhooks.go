@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddWebhooks creates webhook endpoints and their delivery log.
+func MigrateAddWebhooks(db *gorm.DB) error {
+	return db.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{})
+}
+
+func init() {
+	migration.Register("20260628090000", func(db *gorm.DB) error {
+		return MigrateAddWebhooks(db)
+	})
+}
@@ -0,0 +1,29 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddWebhooksCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddWebhooks(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddWebhooks(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.WebhookEndpoint{}, &models.WebhookDelivery{}} {
+		if !db.Migrator().HasTable(model) {
+			t.Fatalf("expected table for %T", model)
+		}
+	}
+	if !db.Migrator().HasIndex(&models.WebhookDelivery{}, "idx_webhook_deliveries_endpoint_event") {
+		t.Fatalf("expected unique endpoint/event delivery index")
+	}
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddNotifications creates in-app notifications and per-type preferences.
+func MigrateAddNotifications(db *gorm.DB) error {
+	return db.AutoMigrate(&models.Notification{}, &models.NotificationPreference{})
+}
+
+func init() {
+	migration.Register("20260629090000", func(db *gorm.DB) error {
+		return MigrateAddNotifications(db)
+	})
+}
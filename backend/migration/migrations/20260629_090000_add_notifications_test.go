@@ -0,0 +1,32 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddNotificationsCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddNotifications(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddNotifications(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.Notification{}, &models.NotificationPreference{}} {
+		if !db.Migrator().HasTable(model) {
+			t.Fatalf("expected table for %T", model)
+		}
+	}
+	if !db.Migrator().HasIndex(&models.Notification{}, "idx_notifications_username_event") {
+		t.Fatalf("expected unique username/event notification index")
+	}
+	if !db.Migrator().HasIndex(&models.NotificationPreference{}, "idx_notification_preferences_username_type") {
+		t.Fatalf("expected unique username/type preference index")
+	}
+}
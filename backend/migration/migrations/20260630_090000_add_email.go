@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddEmail creates the email queue, opt-in preferences, unsubscribe
+// tokens, and weekly digest runs.
+func MigrateAddEmail(db *gorm.DB) error {
+	return db.AutoMigrate(&models.EmailMessage{}, &models.EmailPreference{}, &models.EmailUnsubscribeToken{}, &models.EmailDigestRun{})
+}
+
+func init() {
+	migration.Register("20260630090000", func(db *gorm.DB) error {
+		return MigrateAddEmail(db)
+	})
+}
@@ -0,0 +1,32 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddEmailCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddEmail(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddEmail(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.EmailMessage{}, &models.EmailPreference{}, &models.EmailUnsubscribeToken{}, &models.EmailDigestRun{}} {
+		if !db.Migrator().HasTable(model) {
+			t.Fatalf("expected table for %T", model)
+		}
+	}
+	if !db.Migrator().HasIndex(&models.EmailMessage{}, "idx_email_messages_username_dedupe") {
+		t.Fatalf("expected unique username/dedupe message index")
+	}
+	if !db.Migrator().HasIndex(&models.EmailDigestRun{}, "idx_email_digest_runs_username_week") {
+		t.Fatalf("expected unique username/week digest index")
+	}
+}
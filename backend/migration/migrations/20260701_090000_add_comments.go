@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"time"
+
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MigrateAddComments creates the market and market group discussion table and
+// grants comments.moderate to the built-in MODERATOR role.
+func MigrateAddComments(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.Comment{}); err != nil {
+		return err
+	}
+	grant := models.AccessRolePermission{Role: "MODERATOR", Permission: "comments.moderate", CreatedAt: time.Now().UTC()}
+	return db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "role"}, {Name: "permission"}}, DoNothing: true}).Create(&grant).Error
+}
+
+func init() {
+	migration.Register("20260701090000", func(db *gorm.DB) error {
+		return MigrateAddComments(db)
+	})
+}
@@ -0,0 +1,35 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddCommentsCreatesTableAndGrantsModerators(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+	if err := migrations.MigrateAddAccessRoles(db); err != nil {
+		t.Fatalf("access roles migration failed: %v", err)
+	}
+
+	if err := migrations.MigrateAddComments(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddComments(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.Comment{}) {
+		t.Fatalf("expected comments table")
+	}
+	if !db.Migrator().HasIndex(&models.Comment{}, "idx_comments_thread") {
+		t.Fatalf("expected thread index")
+	}
+	var grants int64
+	db.Model(&models.AccessRolePermission{}).Where("role = ? AND permission = ?", "MODERATOR", "comments.moderate").Count(&grants)
+	if grants != 1 {
+		t.Fatalf("expected one MODERATOR comments.moderate grant, got %d", grants)
+	}
+}
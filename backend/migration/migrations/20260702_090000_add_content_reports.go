@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"time"
+
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MigrateAddContentReports creates user reports and the moderation action log
+// and grants reports.review to the built-in MODERATOR role.
+func MigrateAddContentReports(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.ContentReport{}, &models.ModerationAction{}); err != nil {
+		return err
+	}
+	grant := models.AccessRolePermission{Role: "MODERATOR", Permission: "reports.review", CreatedAt: time.Now().UTC()}
+	return db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "role"}, {Name: "permission"}}, DoNothing: true}).Create(&grant).Error
+}
+
+func init() {
+	migration.Register("20260702090000", func(db *gorm.DB) error {
+		return MigrateAddContentReports(db)
+	})
+}
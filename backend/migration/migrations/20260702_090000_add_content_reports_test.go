@@ -0,0 +1,37 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddContentReportsCreatesTablesAndGrantsModerators(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+	if err := migrations.MigrateAddAccessRoles(db); err != nil {
+		t.Fatalf("access roles migration failed: %v", err)
+	}
+
+	if err := migrations.MigrateAddContentReports(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddContentReports(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.ContentReport{}, &models.ModerationAction{}} {
+		if !db.Migrator().HasTable(model) {
+			t.Fatalf("expected table for %T", model)
+		}
+	}
+	if !db.Migrator().HasIndex(&models.ContentReport{}, "idx_content_reports_queue") {
+		t.Fatalf("expected status/target queue index")
+	}
+	var grants int64
+	db.Model(&models.AccessRolePermission{}).Where("role = ? AND permission = ?", "MODERATOR", "reports.review").Count(&grants)
+	if grants != 1 {
+		t.Fatalf("expected one MODERATOR reports.review grant, got %d", grants)
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddWatchlistEntries creates the table of followed markets, market
+// groups, and tags.
+func MigrateAddWatchlistEntries(db *gorm.DB) error {
+	return db.AutoMigrate(&models.WatchlistEntry{})
+}
+
+func init() {
+	migration.Register("20260703090000", func(db *gorm.DB) error {
+		return MigrateAddWatchlistEntries(db)
+	})
+}
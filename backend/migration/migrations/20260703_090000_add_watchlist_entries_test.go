@@ -0,0 +1,29 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddWatchlistEntriesCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddWatchlistEntries(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddWatchlistEntries(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.WatchlistEntry{}) {
+		t.Fatalf("expected watchlist_entries table")
+	}
+	for _, index := range []string{"idx_watchlist_entries_follow", "idx_watchlist_entries_target"} {
+		if !db.Migrator().HasIndex(&models.WatchlistEntry{}, index) {
+			t.Fatalf("expected index %s", index)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddUserFollows creates the table of users following other users.
+func MigrateAddUserFollows(db *gorm.DB) error {
+	return db.AutoMigrate(&models.UserFollow{})
+}
+
+func init() {
+	migration.Register("20260704090000", func(db *gorm.DB) error {
+		return MigrateAddUserFollows(db)
+	})
+}
@@ -0,0 +1,29 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddUserFollowsCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddUserFollows(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddUserFollows(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.UserFollow{}) {
+		t.Fatalf("expected user_follows table")
+	}
+	for _, index := range []string{"idx_user_follows_pair", "idx_user_follows_followee"} {
+		if !db.Migrator().HasIndex(&models.UserFollow{}, index) {
+			t.Fatalf("expected index %s", index)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddTradingPrivacySettings creates the table of per-user trading
+// privacy settings.
+func MigrateAddTradingPrivacySettings(db *gorm.DB) error {
+	return db.AutoMigrate(&models.TradingPrivacySetting{})
+}
+
+func init() {
+	migration.Register("20260705090000", func(db *gorm.DB) error {
+		return MigrateAddTradingPrivacySettings(db)
+	})
+}
@@ -0,0 +1,29 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddTradingPrivacySettingsCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddTradingPrivacySettings(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddTradingPrivacySettings(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.TradingPrivacySetting{}) {
+		t.Fatalf("expected trading_privacy_settings table")
+	}
+	for _, index := range []string{"idx_trading_privacy_settings_username", "idx_trading_privacy_settings_anonymous"} {
+		if !db.Migrator().HasIndex(&models.TradingPrivacySetting{}, index) {
+			t.Fatalf("expected index %s", index)
+		}
+	}
+}
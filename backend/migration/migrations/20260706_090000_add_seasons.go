@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddSeasons creates seasons, their archived standings, and the
+// record of balances reset at rollover.
+func MigrateAddSeasons(db *gorm.DB) error {
+	return db.AutoMigrate(&models.Season{}, &models.SeasonStanding{}, &models.SeasonBalanceReset{})
+}
+
+func init() {
+	migration.Register("20260706090000", func(db *gorm.DB) error {
+		return MigrateAddSeasons(db)
+	})
+}
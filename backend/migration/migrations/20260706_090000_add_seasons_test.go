@@ -0,0 +1,32 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddSeasonsCreatesTables(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddSeasons(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddSeasons(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	for _, model := range []any{&models.Season{}, &models.SeasonStanding{}, &models.SeasonBalanceReset{}} {
+		if !db.Migrator().HasTable(model) {
+			t.Fatalf("expected table for %T", model)
+		}
+	}
+	if !db.Migrator().HasIndex(&models.SeasonStanding{}, "idx_season_standings_user") {
+		t.Fatalf("expected unique standing index")
+	}
+	if !db.Migrator().HasIndex(&models.SeasonBalanceReset{}, "idx_season_balance_resets_user") {
+		t.Fatalf("expected unique balance reset index")
+	}
+}
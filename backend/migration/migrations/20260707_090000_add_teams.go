@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddTeams creates teams, their members and invitations, and the
+// team-only market registry.
+func MigrateAddTeams(db *gorm.DB) error {
+	return db.AutoMigrate(&models.Team{}, &models.TeamMember{}, &models.TeamInvite{}, &models.TeamMarket{})
+}
+
+func init() {
+	migration.Register("20260707090000", func(db *gorm.DB) error {
+		return MigrateAddTeams(db)
+	})
+}
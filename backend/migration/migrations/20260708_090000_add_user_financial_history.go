@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddUserFinancialHistory creates the per-user equity history.
+func MigrateAddUserFinancialHistory(db *gorm.DB) error {
+	return db.AutoMigrate(&models.UserFinancialHistoryPoint{})
+}
+
+func init() {
+	migration.Register("20260708090000", func(db *gorm.DB) error {
+		return MigrateAddUserFinancialHistory(db)
+	})
+}
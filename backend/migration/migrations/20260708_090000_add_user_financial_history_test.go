@@ -0,0 +1,27 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddUserFinancialHistoryCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddUserFinancialHistory(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddUserFinancialHistory(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.UserFinancialHistoryPoint{}) {
+		t.Fatalf("expected user_financial_history_points table")
+	}
+	if !db.Migrator().HasIndex(&models.UserFinancialHistoryPoint{}, "idx_user_financial_history_bucket") {
+		t.Fatalf("expected bucket index")
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddMarketProbabilityCandles creates the market probability candle
+// cache.
+func MigrateAddMarketProbabilityCandles(db *gorm.DB) error {
+	return db.AutoMigrate(&models.MarketProbabilityCandle{})
+}
+
+func init() {
+	migration.Register("20260709090000", func(db *gorm.DB) error {
+		return MigrateAddMarketProbabilityCandles(db)
+	})
+}
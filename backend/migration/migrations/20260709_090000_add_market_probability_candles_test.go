@@ -0,0 +1,27 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddMarketProbabilityCandlesCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddMarketProbabilityCandles(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddMarketProbabilityCandles(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.MarketProbabilityCandle{}) {
+		t.Fatalf("expected market_probability_candles table")
+	}
+	if !db.Migrator().HasIndex(&models.MarketProbabilityCandle{}, "idx_market_probability_candle_bucket") {
+		t.Fatalf("expected bucket index")
+	}
+}
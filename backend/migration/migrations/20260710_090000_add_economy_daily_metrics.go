@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddEconomyDailyMetrics creates the daily admin economy history.
+func MigrateAddEconomyDailyMetrics(db *gorm.DB) error {
+	return db.AutoMigrate(&models.EconomyDailyMetric{})
+}
+
+func init() {
+	migration.Register("20260710090000", func(db *gorm.DB) error {
+		return MigrateAddEconomyDailyMetrics(db)
+	})
+}
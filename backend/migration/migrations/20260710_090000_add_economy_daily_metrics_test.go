@@ -0,0 +1,27 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddEconomyDailyMetricsCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddEconomyDailyMetrics(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddEconomyDailyMetrics(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.EconomyDailyMetric{}) {
+		t.Fatalf("expected economy_daily_metrics table")
+	}
+	if !db.Migrator().HasIndex(&models.EconomyDailyMetric{}, "idx_economy_daily_metric_day") {
+		t.Fatalf("expected day index")
+	}
+}
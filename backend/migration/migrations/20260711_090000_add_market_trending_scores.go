@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddMarketTrendingScores creates the materialized market trending
+// scores.
+func MigrateAddMarketTrendingScores(db *gorm.DB) error {
+	return db.AutoMigrate(&models.MarketTrendingScore{})
+}
+
+func init() {
+	migration.Register("20260711090000", func(db *gorm.DB) error {
+		return MigrateAddMarketTrendingScores(db)
+	})
+}
@@ -0,0 +1,27 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddMarketTrendingScoresCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddMarketTrendingScores(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddMarketTrendingScores(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.MarketTrendingScore{}) {
+		t.Fatalf("expected market_trending_scores table")
+	}
+	if !db.Migrator().HasIndex(&models.MarketTrendingScore{}, "MarketID") {
+		t.Fatalf("expected market id index")
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddSeasonLiquidations creates the record of positions a season
+// rollover sold.
+func MigrateAddSeasonLiquidations(db *gorm.DB) error {
+	return db.AutoMigrate(&models.SeasonLiquidation{})
+}
+
+func init() {
+	migration.Register("20260712090000", func(db *gorm.DB) error {
+		return MigrateAddSeasonLiquidations(db)
+	})
+}
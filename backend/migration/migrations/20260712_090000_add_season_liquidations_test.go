@@ -0,0 +1,27 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddSeasonLiquidationsCreatesTable(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddSeasonLiquidations(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddSeasonLiquidations(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasTable(&models.SeasonLiquidation{}) {
+		t.Fatalf("expected season_liquidations table")
+	}
+	if !db.Migrator().HasIndex(&models.SeasonLiquidation{}, "BetID") {
+		t.Fatalf("expected bet id index")
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateIndexLoginThrottleLastFailure indexes login throttle rows by their
+// last failure so stale rows can be pruned cheaply.
+func MigrateIndexLoginThrottleLastFailure(db *gorm.DB) error {
+	return db.AutoMigrate(&models.UserLoginThrottle{})
+}
+
+func init() {
+	migration.Register("20260713090000", func(db *gorm.DB) error {
+		return MigrateIndexLoginThrottleLastFailure(db)
+	})
+}
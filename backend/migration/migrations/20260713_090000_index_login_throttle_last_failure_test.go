@@ -0,0 +1,27 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateIndexLoginThrottleLastFailureAddsIndex(t *testing.T) {
+	db := modelstesting.NewTestDB(t)
+
+	if err := migrations.MigrateAddLoginSecurity(db); err != nil {
+		t.Fatalf("login security migration failed: %v", err)
+	}
+	if err := migrations.MigrateIndexLoginThrottleLastFailure(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateIndexLoginThrottleLastFailure(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+
+	if !db.Migrator().HasIndex(&models.UserLoginThrottle{}, "LastFailedAt") {
+		t.Fatalf("expected last failed at index")
+	}
+}
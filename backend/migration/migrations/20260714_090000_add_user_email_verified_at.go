@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"socialpredict/migration"
+	"socialpredict/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateAddUserEmailVerifiedAt records whether an account's email address
+// has been proven, so external identities only auto-link to verified emails.
+// Existing addresses were typed in and stay unverified.
+func MigrateAddUserEmailVerifiedAt(db *gorm.DB) error {
+	m := db.Migrator()
+	if m.HasColumn(&models.User{}, "EmailVerifiedAt") {
+		return nil
+	}
+	return m.AddColumn(&models.User{}, "EmailVerifiedAt")
+}
+
+func init() {
+	migration.Register("20260714090000", func(db *gorm.DB) error {
+		return MigrateAddUserEmailVerifiedAt(db)
+	})
+}
@@ -0,0 +1,24 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"socialpredict/migration/migrations"
+	"socialpredict/models"
+	"socialpredict/models/modelstesting"
+)
+
+func TestMigrateAddUserEmailVerifiedAtAddsColumn(t *testing.T) {
+	db := modelstesting.NewFakeDB(t)
+	_ = db.Migrator().DropColumn(&models.User{}, "EmailVerifiedAt")
+
+	if err := migrations.MigrateAddUserEmailVerifiedAt(db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if err := migrations.MigrateAddUserEmailVerifiedAt(db); err != nil {
+		t.Fatalf("second migration failed: %v", err)
+	}
+	if !db.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt") {
+		t.Fatalf("expected users email_verified_at column")
+	}
+}
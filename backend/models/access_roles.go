@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// AccessRole is a named permission set. Built-in roles mirror user types;
+// custom roles are assigned to individual accounts through UserRoleAssignment.
+type AccessRole struct {
+	ID          int64     `json:"id" gorm:"primary_key"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex;size:32"`
+	Description string    `json:"description" gorm:"size:200"`
+	UpdatedBy   string    `json:"updatedBy,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// AccessRolePermission grants one registered permission to a role.
+type AccessRolePermission struct {
+	ID         int64     `json:"id" gorm:"primary_key"`
+	Role       string    `json:"role" gorm:"not null;size:32;uniqueIndex:idx_access_role_permission"`
+	Permission string    `json:"permission" gorm:"not null;size:64;uniqueIndex:idx_access_role_permission"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// UserRoleAssignment assigns a custom role to an account in addition to the
+// built-in role implied by its user type.
+type UserRoleAssignment struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	Username  string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_user_role_assignment"`
+	Role      string    `json:"role" gorm:"not null;size:32;uniqueIndex:idx_user_role_assignment;index"`
+	GrantedBy string    `json:"grantedBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// AccountStatusAudit is an append-only record of an admin suspending,
+// banning, or reinstating an account.
+type AccountStatusAudit struct {
+	ID            int64      `json:"id" gorm:"primary_key"`
+	Username      string     `json:"username" gorm:"not null;index;size:64"`
+	ActorUsername string     `json:"actorUsername" gorm:"not null;index;size:64"`
+	Action        string     `json:"action" gorm:"not null;index;size:16"`
+	FromStatus    string     `json:"fromStatus" gorm:"not null;size:16"`
+	ToStatus      string     `json:"toStatus" gorm:"not null;size:16"`
+	Reason        string     `json:"reason,omitempty" gorm:"type:text"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"not null;index"`
+}
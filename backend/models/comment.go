@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Comment is one post in the discussion thread of a market or a market
+// group; exactly one of MarketID and MarketGroupID is set. Replies point at
+// their parent and at the top-level comment that starts the thread, so a
+// page of threads can be loaded with one query for roots and one for
+// replies. Body keeps the author's markdown and BodyHTML the sanitized
+// rendering served to readers.
+type Comment struct {
+	ID            int64      `json:"id" gorm:"primary_key"`
+	MarketID      int64      `json:"marketId,omitempty" gorm:"not null;default:0;index:idx_comments_thread,priority:1"`
+	MarketGroupID int64      `json:"marketGroupId,omitempty" gorm:"not null;default:0;index:idx_comments_thread,priority:2"`
+	RootID        int64      `json:"rootId,omitempty" gorm:"not null;default:0;index:idx_comments_thread,priority:3"`
+	ParentID      int64      `json:"parentId,omitempty" gorm:"not null;default:0"`
+	Username      string     `json:"username" gorm:"not null;size:64;index"`
+	Body          string     `json:"body" gorm:"type:text;not null"`
+	BodyHTML      string     `json:"bodyHtml" gorm:"type:text;not null"`
+	EditedAt      *time.Time `json:"editedAt,omitempty"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy     string     `json:"deletedBy,omitempty" gorm:"size:64"`
+	HiddenAt      *time.Time `json:"hiddenAt,omitempty"`
+	HiddenBy      string     `json:"hiddenBy,omitempty" gorm:"size:64"`
+	HiddenReason  string     `json:"hiddenReason,omitempty" gorm:"size:500"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"index:idx_comments_thread,priority:4"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
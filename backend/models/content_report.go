@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ContentReport is one user's flag on a market, comment, or profile.
+// TargetKey combines the target type and ID (for example "comment:12") so
+// the moderation queue can group reports on the same content.
+type ContentReport struct {
+	ID               int64      `json:"id" gorm:"primary_key"`
+	TargetKey        string     `json:"targetKey" gorm:"not null;size:80;index:idx_content_reports_queue,priority:2"`
+	TargetType       string     `json:"targetType" gorm:"not null;size:16"`
+	TargetID         string     `json:"targetId" gorm:"not null;size:64"`
+	ReporterUsername string     `json:"reporterUsername" gorm:"not null;size:64;index:idx_content_reports_reporter,priority:1"`
+	Category         string     `json:"category" gorm:"not null;size:32"`
+	Details          string     `json:"details" gorm:"type:text"`
+	Status           string     `json:"status" gorm:"not null;size:16;index:idx_content_reports_queue,priority:1"`
+	Outcome          string     `json:"outcome,omitempty" gorm:"size:32"`
+	ReviewedBy       string     `json:"reviewedBy,omitempty" gorm:"size:64"`
+	ReviewedAt       *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt" gorm:"index:idx_content_reports_reporter,priority:2"`
+}
+
+// ModerationAction records a moderator's decision on reported content, with
+// the actor, the reason, and how many open reports it closed.
+type ModerationAction struct {
+	ID            int64     `json:"id" gorm:"primary_key"`
+	TargetKey     string    `json:"targetKey" gorm:"not null;size:80;index"`
+	TargetType    string    `json:"targetType" gorm:"not null;size:16"`
+	TargetID      string    `json:"targetId" gorm:"not null;size:64"`
+	Action        string    `json:"action" gorm:"not null;size:32"`
+	ActorUsername string    `json:"actorUsername" gorm:"not null;size:64"`
+	Reason        string    `json:"reason" gorm:"type:text"`
+	ReportsClosed int       `json:"reportsClosed"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"index"`
+}
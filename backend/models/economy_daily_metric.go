@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// EconomyDailyMetric is one UTC day of the admin economy history. Stock
+// figures (money supply, debt, retained fees) are the system metrics at
+// RecordedAt; activity figures count the trades, markets and resolutions of
+// the day. Final marks a day whose activity was recounted after it ended.
+type EconomyDailyMetric struct {
+	ID                 int64     `json:"id" gorm:"primary_key"`
+	Day                time.Time `json:"day" gorm:"not null;uniqueIndex:idx_economy_daily_metric_day"`
+	MoneySupply        int64     `json:"moneySupply" gorm:"not null;default:0"`
+	NumUsers           int64     `json:"numUsers" gorm:"not null;default:0"`
+	DebtUtilized       int64     `json:"debtUtilized" gorm:"not null;default:0"`
+	UnusedDebt         int64     `json:"unusedDebt" gorm:"not null;default:0"`
+	ActiveBetVolume    int64     `json:"activeBetVolume" gorm:"not null;default:0"`
+	MarketCreationFees int64     `json:"marketCreationFees" gorm:"not null;default:0"`
+	ParticipationFees  int64     `json:"participationFees" gorm:"not null;default:0"`
+	BonusesPaid        int64     `json:"bonusesPaid" gorm:"not null;default:0"`
+	Surplus            int64     `json:"surplus" gorm:"not null;default:0"`
+	Volume             int64     `json:"volume" gorm:"not null;default:0"`
+	Trades             int64     `json:"trades" gorm:"not null;default:0"`
+	ActiveTraders      int64     `json:"activeTraders" gorm:"not null;default:0"`
+	NewMarkets         int64     `json:"newMarkets" gorm:"not null;default:0"`
+	Resolutions        int64     `json:"resolutions" gorm:"not null;default:0"`
+	Final              bool      `json:"final" gorm:"not null;default:false"`
+	RecordedAt         time.Time `json:"recordedAt" gorm:"not null"`
+}
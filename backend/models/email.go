@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Email message statuses.
+const (
+	EmailMessagePending = "pending"
+	EmailMessageSent    = "sent"
+	EmailMessageDead    = "dead"
+)
+
+// EmailMessage is one rendered email waiting for, or past, delivery.
+// DedupeKey identifies what the email is about, so redelivered events and
+// repeated sweeps never queue the same email twice for a user.
+type EmailMessage struct {
+	ID             int64      `json:"id" gorm:"primary_key"`
+	Username       string     `json:"username" gorm:"not null;size:64;uniqueIndex:idx_email_messages_username_dedupe,priority:1"`
+	DedupeKey      string     `json:"dedupeKey" gorm:"not null;size:128;uniqueIndex:idx_email_messages_username_dedupe,priority:2"`
+	ToAddress      string     `json:"toAddress" gorm:"not null;size:320"`
+	Kind           string     `json:"kind" gorm:"not null;size:64"`
+	Subject        string     `json:"subject" gorm:"not null;size:300"`
+	TextBody       string     `json:"textBody" gorm:"type:text;not null"`
+	HTMLBody       string     `json:"htmlBody" gorm:"type:text"`
+	UnsubscribeURL string     `json:"unsubscribeUrl" gorm:"size:2048"`
+	Status         string     `json:"status" gorm:"not null;default:pending;index:idx_email_messages_due,priority:1;size:16"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt" gorm:"not null;index:idx_email_messages_due,priority:2"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	LastError      string     `json:"lastError,omitempty" gorm:"type:text"`
+	SentAt         *time.Time `json:"sentAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// EmailPreference records whether a user opted in to one kind of email.
+// Email is opt-in, so kinds without a row are off.
+type EmailPreference struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	Username  string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_email_preferences_username_kind,priority:1"`
+	Kind      string    `json:"kind" gorm:"not null;size:64;uniqueIndex:idx_email_preferences_username_kind,priority:2"`
+	Enabled   bool      `json:"enabled" gorm:"not null"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// EmailUnsubscribeToken is the secret carried by unsubscribe links. It lets
+// the holder turn off a user's email without signing in, and nothing else.
+type EmailUnsubscribeToken struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	Username  string    `json:"username" gorm:"not null;size:64;uniqueIndex"`
+	Token     string    `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// EmailDigestRun records the weekly digest queued for a user and the equity
+// it reported, which the next digest compares against.
+type EmailDigestRun struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	Username  string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_email_digest_runs_username_week,priority:1"`
+	Week      string    `json:"week" gorm:"not null;size:16;uniqueIndex:idx_email_digest_runs_username_week,priority:2"`
+	Equity    int64     `json:"equity"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// UserLoginThrottle holds the persisted failed-password counters for one
+// username. Rows may exist for usernames with no account so that throttling
+// behaves identically for real and unknown usernames.
+type UserLoginThrottle struct {
+	ID             int64      `json:"id" gorm:"primary_key"`
+	Username       string     `json:"username" gorm:"not null;uniqueIndex;size:64"`
+	FailedAttempts int        `json:"failedAttempts" gorm:"not null;default:0"`
+	LastFailedAt   *time.Time `json:"lastFailedAt,omitempty" gorm:"index"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty" gorm:"index"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// LoginAuditEvent is an append-only record of a login success, failure,
+// throttled attempt, or admin unlock.
+type LoginAuditEvent struct {
+	ID            int64     `json:"id" gorm:"primary_key"`
+	Username      string    `json:"username" gorm:"not null;index;size:64"`
+	Outcome       string    `json:"outcome" gorm:"not null;index;size:16"`
+	Method        string    `json:"method" gorm:"not null;size:16"`
+	Reason        string    `json:"reason,omitempty" gorm:"size:200"`
+	IPAddress     string    `json:"ipAddress,omitempty" gorm:"index;size:64"`
+	UserAgent     string    `json:"userAgent,omitempty" gorm:"size:512"`
+	ActorUsername string    `json:"actorUsername,omitempty"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"not null;index"`
+}
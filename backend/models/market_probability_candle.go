@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// MarketProbabilityCandle caches one bucket of a market's probability history
+// at one interval. Only buckets with trades are stored, apart from the bucket
+// the market was created in. YesContribution, NoContribution and
+// CumulativeTrades are the probability engine state after the bucket's last
+// trade, so the newest bucket can be recomputed without replaying earlier
+// bets. Like the other read-model snapshots it is display data, not
+// transaction truth.
+type MarketProbabilityCandle struct {
+	ID               int64     `json:"id" gorm:"primary_key"`
+	MarketID         int64     `json:"marketId" gorm:"not null;uniqueIndex:idx_market_probability_candle_bucket,priority:1"`
+	BucketInterval   string    `json:"bucketInterval" gorm:"not null;size:8;uniqueIndex:idx_market_probability_candle_bucket,priority:2"`
+	BucketStart      time.Time `json:"bucketStart" gorm:"not null;uniqueIndex:idx_market_probability_candle_bucket,priority:3"`
+	OpenProbability  float64   `json:"openProbability" gorm:"not null"`
+	HighProbability  float64   `json:"highProbability" gorm:"not null"`
+	LowProbability   float64   `json:"lowProbability" gorm:"not null"`
+	CloseProbability float64   `json:"closeProbability" gorm:"not null"`
+	Volume           int64     `json:"volume" gorm:"not null;default:0"`
+	Trades           int64     `json:"trades" gorm:"not null;default:0"`
+	Traders          int64     `json:"traders" gorm:"not null;default:0"`
+	CumulativeTrades int64     `json:"cumulativeTrades" gorm:"not null;default:0"`
+	YesContribution  int64     `json:"yesContribution" gorm:"not null;default:0"`
+	NoContribution   int64     `json:"noContribution" gorm:"not null;default:0"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// MarketTrendingScore is a market's materialized trending score, replaced
+// wholesale on each refresh. Volume, NewTraders, ProbabilityMovement and
+// Comments are the raw figures over the trending window; Score weighs them
+// with time decay. Like the other read-model snapshots it is display data,
+// not transaction truth.
+type MarketTrendingScore struct {
+	ID                  int64     `json:"id" gorm:"primary_key"`
+	MarketID            int64     `json:"marketId" gorm:"not null;uniqueIndex"`
+	Score               float64   `json:"score" gorm:"not null;default:0;index"`
+	Volume              int64     `json:"volume" gorm:"not null;default:0"`
+	NewTraders          int64     `json:"newTraders" gorm:"not null;default:0"`
+	ProbabilityMovement float64   `json:"probabilityMovement" gorm:"not null;default:0"`
+	Comments            int64     `json:"comments" gorm:"not null;default:0"`
+	ComputedAt          time.Time `json:"computedAt" gorm:"not null"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Notification is one in-app message for a user, generated from a committed
+// domain event. EventKey identifies the source event so redelivery by the
+// event bus never notifies the same user twice.
+type Notification struct {
+	ID            int64      `json:"id" gorm:"primary_key"`
+	Username      string     `json:"username" gorm:"not null;size:64;uniqueIndex:idx_notifications_username_event,priority:1;index:idx_notifications_username_created,priority:1"`
+	EventKey      string     `json:"eventKey" gorm:"not null;size:64;uniqueIndex:idx_notifications_username_event,priority:2"`
+	Type          string     `json:"type" gorm:"not null;size:64"`
+	Title         string     `json:"title" gorm:"not null;size:200"`
+	Body          string     `json:"body" gorm:"type:text"`
+	MarketID      int64      `json:"marketId,omitempty"`
+	MarketGroupID int64      `json:"marketGroupId,omitempty"`
+	ReadAt        *time.Time `json:"readAt,omitempty" gorm:"index"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"index:idx_notifications_username_created,priority:2"`
+}
+
+// NotificationPreference records a user's choice for one notification type.
+// Types without a row are enabled.
+type NotificationPreference struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	Username  string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_notification_preferences_username_type,priority:1"`
+	Type      string    `json:"type" gorm:"not null;size:64;uniqueIndex:idx_notification_preferences_username_type,priority:2"`
+	Enabled   bool      `json:"enabled" gorm:"not null"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Outbox event statuses.
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change
+// it describes and delivered to in-process subscribers afterwards.
+type OutboxEvent struct {
+	ID            int64      `json:"id" gorm:"primary_key"`
+	EventType     string     `json:"eventType" gorm:"not null;index;size:64"`
+	MarketID      int64      `json:"marketId" gorm:"index"`
+	MarketGroupID int64      `json:"marketGroupId" gorm:"index"`
+	Username      string     `json:"username" gorm:"index;size:64"`
+	Payload       string     `json:"payload" gorm:"type:text"`
+	OccurredAt    time.Time  `json:"occurredAt" gorm:"not null;index"`
+	Status        string     `json:"status" gorm:"not null;default:pending;index:idx_outbox_events_due,priority:1;size:16"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt" gorm:"not null;index:idx_outbox_events_due,priority:2"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"lastError,omitempty" gorm:"type:text"`
+	DeliveredAt   *time.Time `json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// OutboxDelivery records that one subscriber has handled an outbox event, so
+// retries only replay the subscribers that failed.
+type OutboxDelivery struct {
+	ID          int64     `json:"id" gorm:"primary_key"`
+	EventID     int64     `json:"eventId" gorm:"not null;uniqueIndex:idx_outbox_deliveries_event_subscriber,priority:1"`
+	Subscriber  string    `json:"subscriber" gorm:"not null;uniqueIndex:idx_outbox_deliveries_event_subscriber,priority:2;size:64"`
+	DeliveredAt time.Time `json:"deliveredAt" gorm:"not null"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Season is an admin-defined contest period. Rolling it over freezes its
+// standings into SeasonStanding rows and, when ResetBalances is set, resets
+// every balance to the account's initial balance, first liquidating open
+// positions when PositionPolicy is "liquidate".
+type Season struct {
+	ID                  int64      `json:"id" gorm:"primary_key"`
+	Name                string     `json:"name" gorm:"not null;size:120"`
+	StartsAt            time.Time  `json:"startsAt" gorm:"not null;index:idx_seasons_period,priority:1"`
+	EndsAt              time.Time  `json:"endsAt" gorm:"not null;index:idx_seasons_period,priority:2"`
+	ResetBalances       bool       `json:"resetBalances" gorm:"not null;default:false"`
+	PositionPolicy      string     `json:"positionPolicy" gorm:"not null;size:16;default:carry"`
+	CreatedBy           string     `json:"createdBy" gorm:"not null;size:64"`
+	ArchivedAt          *time.Time `json:"archivedAt,omitempty" gorm:"index"`
+	ArchivedBy          string     `json:"archivedBy,omitempty" gorm:"size:64"`
+	PositionsLiquidated int        `json:"positionsLiquidated"`
+	PositionsCarried    int        `json:"positionsCarried"`
+	BalancesReset       int        `json:"balancesReset"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+}
+
+// SeasonStanding is one user's frozen result in an archived season.
+type SeasonStanding struct {
+	ID              int64  `json:"id" gorm:"primary_key"`
+	SeasonID        int64  `json:"seasonId" gorm:"not null;uniqueIndex:idx_season_standings_user,priority:1;index:idx_season_standings_rank,priority:1"`
+	Username        string `json:"username" gorm:"not null;size:64;uniqueIndex:idx_season_standings_user,priority:2"`
+	Rank            int    `json:"rank" gorm:"not null;index:idx_season_standings_rank,priority:2"`
+	Profit          int64  `json:"profit"`
+	Spent           int64  `json:"spent"`
+	CurrentValue    int64  `json:"currentValue"`
+	ActiveMarkets   int    `json:"activeMarkets"`
+	ResolvedMarkets int    `json:"resolvedMarkets"`
+	FinalBalance    int64  `json:"finalBalance"`
+}
+
+// SeasonBalanceReset records one balance a season rollover reset, so the
+// credits it added or removed stay accountable.
+type SeasonBalanceReset struct {
+	ID            int64     `json:"id" gorm:"primary_key"`
+	SeasonID      int64     `json:"seasonId" gorm:"not null;uniqueIndex:idx_season_balance_resets_user,priority:1"`
+	Username      string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_season_balance_resets_user,priority:2;index"`
+	BalanceBefore int64     `json:"balanceBefore"`
+	BalanceAfter  int64     `json:"balanceAfter"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// SeasonLiquidation records one position a season rollover sold, with the
+// sale bet it wrote and the value credited for it. Every position in a
+// rollover is valued at the same snapshot, so the credit is stored rather
+// than replayed from the bets before the sale.
+type SeasonLiquidation struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	SeasonID  int64     `json:"seasonId" gorm:"not null;index"`
+	Username  string    `json:"username" gorm:"not null;size:64"`
+	MarketID  int64     `json:"marketId" gorm:"not null"`
+	BetID     uint      `json:"betId" gorm:"not null;uniqueIndex"`
+	Outcome   string    `json:"outcome" gorm:"not null;size:16"`
+	Shares    int64     `json:"shares"`
+	Value     int64     `json:"value"`
+	CreatedAt time.Time `json:"createdAt"`
+}
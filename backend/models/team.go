@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Team groups users for team scoring. A user belongs to at most one team.
+type Team struct {
+	ID          int64     `json:"id" gorm:"primary_key"`
+	Slug        string    `json:"slug" gorm:"not null;uniqueIndex;size:64"`
+	Name        string    `json:"name" gorm:"not null;size:120"`
+	Description string    `json:"description,omitempty" gorm:"type:text"`
+	CreatedBy   string    `json:"createdBy" gorm:"not null;size:64"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TeamMember is a user's membership in a team. Owners may invite and remove
+// members and manage the team's profile and markets.
+type TeamMember struct {
+	ID       int64     `json:"id" gorm:"primary_key"`
+	TeamID   int64     `json:"teamId" gorm:"not null;index"`
+	Username string    `json:"username" gorm:"not null;size:64;uniqueIndex"`
+	Role     string    `json:"role" gorm:"not null;size:16;default:member"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// TeamInvite is an owner's invitation for a user to join a team.
+type TeamInvite struct {
+	ID          int64      `json:"id" gorm:"primary_key"`
+	TeamID      int64      `json:"teamId" gorm:"not null;index:idx_team_invites_lookup,priority:1"`
+	Username    string     `json:"username" gorm:"not null;size:64;index:idx_team_invites_lookup,priority:2;index"`
+	InvitedBy   string     `json:"invitedBy" gorm:"not null;size:64"`
+	Status      string     `json:"status" gorm:"not null;size:16;default:pending;index"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+}
+
+// TeamMarket restricts buying in a market to one team's members.
+type TeamMarket struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	MarketID  int64     `json:"marketId" gorm:"not null;uniqueIndex"`
+	TeamID    int64     `json:"teamId" gorm:"not null;index"`
+	CreatedBy string    `json:"createdBy" gorm:"not null;size:64"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// TradingPrivacySetting holds one user's trading privacy choices. Users
+// without a row keep the defaults: a public portfolio and named trades.
+type TradingPrivacySetting struct {
+	ID              int64     `json:"id" gorm:"primary_key"`
+	Username        string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_trading_privacy_settings_username"`
+	HidePortfolio   bool      `json:"hidePortfolio" gorm:"not null;default:false"`
+	AnonymousTrades bool      `json:"anonymousTrades" gorm:"not null;default:false;index:idx_trading_privacy_settings_anonymous"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
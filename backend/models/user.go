@@ -14,6 +14,7 @@ type User struct {
 	PrivateUser
 	MustChangePassword bool `json:"mustChangePassword" gorm:"default:true"`
 	ModeratorGovernance
+	AccountStanding
 }
 
 type PublicUser struct {
@@ -34,6 +35,9 @@ type PrivateUser struct {
 	Email    string `json:"email" gorm:"unique;not null"`
 	APIKey   string `json:"apiKey,omitempty" gorm:"unique"`
 	Password string `json:"password,omitempty" gorm:"not null"`
+	// EmailVerifiedAt is set once someone proved they control Email; typed-in
+	// addresses stay unverified.
+	EmailVerifiedAt *time.Time `json:"-"`
 }
 
 type ModeratorGovernance struct {
@@ -43,6 +47,16 @@ type ModeratorGovernance struct {
 	ModeratorSuspendedAt      *time.Time `json:"-"`
 }
 
+// AccountStanding records an admin-imposed suspension or ban. Suspended
+// accounts are read-only; banned accounts cannot authenticate.
+type AccountStanding struct {
+	AccountStatus          string     `json:"accountStatus,omitempty" gorm:"not null;default:active;index"`
+	AccountStatusReason    string     `json:"-" gorm:"type:text"`
+	AccountStatusBy        string     `json:"-" gorm:"index"`
+	AccountStatusAt        *time.Time `json:"-"`
+	AccountStatusExpiresAt *time.Time `json:"-" gorm:"index"`
+}
+
 type ModeratorRoleAudit struct {
 	gorm.Model
 	ID                  int64  `json:"id" gorm:"primary_key"`
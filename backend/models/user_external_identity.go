@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserExternalIdentity links a local account to a subject at an external
+// OpenID Connect identity provider.
+type UserExternalIdentity struct {
+	gorm.Model
+	ID          int64      `json:"id" gorm:"primary_key"`
+	Issuer      string     `json:"issuer" gorm:"not null;uniqueIndex:idx_user_external_identity_subject"`
+	Subject     string     `json:"subject" gorm:"not null;uniqueIndex:idx_user_external_identity_subject"`
+	Username    string     `json:"username" gorm:"not null;index"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+}
+
+// OIDCLoginState holds the per-attempt secrets of an in-flight OpenID Connect
+// authorization code flow. Rows are single use and short lived.
+type OIDCLoginState struct {
+	ID           int64     `json:"id" gorm:"primary_key"`
+	StateHash    string    `json:"-" gorm:"not null;uniqueIndex;size:64"`
+	Nonce        string    `json:"-" gorm:"not null"`
+	CodeVerifier string    `json:"-" gorm:"not null"`
+	ExpiresAt    time.Time `json:"expiresAt" gorm:"not null;index"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// UserFinancialHistoryPoint is one sample of a user's equity history. Samples
+// are kept per hour and compacted to the last sample of each UTC day once
+// they age out of the hourly window. Like UserFinancialMetricSnapshot it is
+// display data, not transaction truth.
+type UserFinancialHistoryPoint struct {
+	ID                int64     `json:"id" gorm:"primary_key"`
+	Username          string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_user_financial_history_bucket,priority:1"`
+	BucketStart       time.Time `json:"bucketStart" gorm:"not null;uniqueIndex:idx_user_financial_history_bucket,priority:2"`
+	Granularity       string    `json:"granularity" gorm:"not null;size:8;default:hour;index"`
+	AccountBalance    int64     `json:"accountBalance" gorm:"not null;default:0"`
+	Equity            int64     `json:"equity" gorm:"not null;default:0"`
+	AmountInPlay      int64     `json:"amountInPlay" gorm:"not null;default:0"`
+	RealizedProfits   int64     `json:"realizedProfits" gorm:"not null;default:0"`
+	UnrealizedProfits int64     `json:"unrealizedProfits" gorm:"not null;default:0"`
+	RecordedAt        time.Time `json:"recordedAt" gorm:"not null"`
+}
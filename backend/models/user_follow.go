@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// UserFollow records that one user follows another forecaster's activity.
+type UserFollow struct {
+	ID               int64     `json:"id" gorm:"primary_key"`
+	FollowerUsername string    `json:"followerUsername" gorm:"not null;size:64;uniqueIndex:idx_user_follows_pair,priority:1"`
+	FolloweeUsername string    `json:"followeeUsername" gorm:"not null;size:64;uniqueIndex:idx_user_follows_pair,priority:2;index:idx_user_follows_followee"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
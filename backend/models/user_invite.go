@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserInvite is an admin- or moderator-minted registration code. Only a hash
+// of the code is stored; the plaintext code is returned once at creation.
+type UserInvite struct {
+	gorm.Model
+	ID                    int64      `json:"id" gorm:"primary_key"`
+	CodeHash              string     `json:"-" gorm:"not null;uniqueIndex;size:64"`
+	CodePrefix            string     `json:"codePrefix" gorm:"not null;size:8"`
+	CreatedBy             string     `json:"createdBy" gorm:"not null;index"`
+	UserType              string     `json:"usertype" gorm:"not null;default:REGULAR"`
+	InitialAccountBalance *int64     `json:"initialAccountBalance,omitempty"`
+	MaxUses               int64      `json:"maxUses" gorm:"not null;default:1"`
+	UseCount              int64      `json:"useCount" gorm:"not null;default:0"`
+	ExpiresAt             *time.Time `json:"expiresAt,omitempty" gorm:"index"`
+	RevokedAt             *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy             string     `json:"revokedBy,omitempty"`
+	Note                  string     `json:"note,omitempty" gorm:"type:text"`
+}
+
+// UserInviteRedemption records which account consumed an invite use.
+type UserInviteRedemption struct {
+	gorm.Model
+	ID       int64  `json:"id" gorm:"primary_key"`
+	InviteID int64  `json:"inviteId" gorm:"not null;index"`
+	Username string `json:"username" gorm:"not null;uniqueIndex"`
+}
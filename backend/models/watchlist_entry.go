@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// WatchlistEntry records that a user follows a market, a market group, or a
+// tag. TargetID holds the market or group ID in decimal, or the tag slug, so
+// every kind of follow shares one table and one follower-count index.
+type WatchlistEntry struct {
+	ID         int64     `json:"id" gorm:"primary_key"`
+	Username   string    `json:"username" gorm:"not null;size:64;uniqueIndex:idx_watchlist_entries_follow,priority:1"`
+	TargetType string    `json:"targetType" gorm:"not null;size:16;uniqueIndex:idx_watchlist_entries_follow,priority:2;index:idx_watchlist_entries_target,priority:1"`
+	TargetID   string    `json:"targetId" gorm:"not null;size:64;uniqueIndex:idx_watchlist_entries_follow,priority:3;index:idx_watchlist_entries_target,priority:2"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
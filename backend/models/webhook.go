@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookEndpoint is an admin-registered URL that receives signed event
+// notifications. EventTypes is a comma-separated list of domain event types.
+type WebhookEndpoint struct {
+	ID          int64     `json:"id" gorm:"primary_key"`
+	URL         string    `json:"url" gorm:"not null;size:2048"`
+	Description string    `json:"description" gorm:"size:200"`
+	Secret      string    `json:"-" gorm:"not null;size:128"`
+	EventTypes  string    `json:"eventTypes" gorm:"type:text;not null"`
+	Active      bool      `json:"active" gorm:"not null;default:true;index"`
+	CreatedBy   string    `json:"createdBy" gorm:"size:64"`
+	UpdatedBy   string    `json:"updatedBy" gorm:"size:64"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// WebhookDelivery is one event queued for one endpoint, together with the
+// outcome of its latest attempt. The payload is stored so every retry signs
+// and sends the same bytes.
+type WebhookDelivery struct {
+	ID             int64      `json:"id" gorm:"primary_key"`
+	EndpointID     int64      `json:"endpointId" gorm:"not null;uniqueIndex:idx_webhook_deliveries_endpoint_event,priority:1;index:idx_webhook_deliveries_endpoint_created,priority:1"`
+	EventKey       string     `json:"eventKey" gorm:"not null;uniqueIndex:idx_webhook_deliveries_endpoint_event,priority:2;size:64"`
+	EventType      string     `json:"eventType" gorm:"not null;size:64"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"not null;default:pending;index:idx_webhook_deliveries_due,priority:1;size:16"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt" gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus int        `json:"responseStatus"`
+	LastError      string     `json:"lastError,omitempty" gorm:"type:text"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"index:idx_webhook_deliveries_endpoint_created,priority:2"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
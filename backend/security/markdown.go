@@ -0,0 +1,54 @@
+package security
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// MarkdownLite renders user-written markdown for discussion surfaces such as
+// comments. Raw HTML in the source is dropped by goldmark, and the output is
+// sanitized down to paragraphs, emphasis, code, quotes, lists, and links.
+// Headings, images, and tables lose their markup but keep their text.
+type MarkdownLite struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+// NewMarkdownLite creates a markdown-lite renderer.
+func NewMarkdownLite() *MarkdownLite {
+	return &MarkdownLite{
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.Linkify, extension.Strikethrough),
+			goldmark.WithRendererOptions(html.WithHardWraps()),
+		),
+		policy: createMarkdownLitePolicy(),
+	}
+}
+
+func createMarkdownLitePolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	p.RequireParseableURLs(true)
+	p.AllowURLSchemes("http", "https")
+	p.AllowElements("p", "br", "strong", "em", "del", "code", "pre", "blockquote", "ul", "ol", "li", "a")
+	p.AllowAttrs("href").Matching(regexp.MustCompile(`^https?://`)).OnElements("a")
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render converts source to sanitized HTML.
+func (m *MarkdownLite) Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := m.md.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(m.policy.Sanitize(buf.String())), nil
+}
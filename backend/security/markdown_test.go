@@ -0,0 +1,56 @@
+package security
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownLiteRendersBasicFormatting(t *testing.T) {
+	out, err := NewMarkdownLite().Render("**Yes** because _polls_ moved.\n\n- one\n- two\n\n> quoted `code`")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{"<strong>Yes</strong>", "<em>polls</em>", "<li>one</li>", "<blockquote>", "<code>code</code>"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in %q", want, out)
+		}
+	}
+}
+
+func TestMarkdownLiteStripsUnsafeMarkup(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		banned []string
+	}{
+		{name: "raw script", source: "hi <script>alert(1)</script>", banned: []string{"<script", "alert(1)</script>"}},
+		{name: "javascript link", source: "[click](javascript:alert(1))", banned: []string{"javascript:"}},
+		{name: "image", source: "![x](https://example.com/x.png)", banned: []string{"<img"}},
+		{name: "heading", source: "# Big", banned: []string{"<h1"}},
+		{name: "inline handler", source: `<a href="https://example.com" onclick="x()">x</a>`, banned: []string{"onclick"}},
+	}
+	renderer := NewMarkdownLite()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := renderer.Render(tt.source)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			for _, banned := range tt.banned {
+				if strings.Contains(out, banned) {
+					t.Fatalf("expected %q to be stripped from %q", banned, out)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownLiteLinksAreNofollow(t *testing.T) {
+	out, err := NewMarkdownLite().Render("see https://example.com/poll")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, `href="https://example.com/poll"`) || !strings.Contains(out, "nofollow") {
+		t.Fatalf("expected linkified nofollow link, got %q", out)
+	}
+}
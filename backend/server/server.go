@@ -24,22 +24,58 @@ import (
 	cmsreportinghttp "socialpredict/handlers/cms/reportingvisibility/http"
 	"socialpredict/handlers/cms/socialshare"
 	cmssocialhttp "socialpredict/handlers/cms/socialshare/http"
+	commentshandlers "socialpredict/handlers/comments"
+	emailhandlers "socialpredict/handlers/email"
+	feedhandlers "socialpredict/handlers/feed"
 	marketshandlers "socialpredict/handlers/markets"
 	metricshandlers "socialpredict/handlers/metrics"
+	notificationshandlers "socialpredict/handlers/notifications"
 	positionshandlers "socialpredict/handlers/positions"
+	privacyhandlers "socialpredict/handlers/privacy"
+	reportshandlers "socialpredict/handlers/reports"
+	seasonshandlers "socialpredict/handlers/seasons"
 	setuphandlers "socialpredict/handlers/setup"
 	statshandlers "socialpredict/handlers/stats"
+	teamshandlers "socialpredict/handlers/teams"
 	usershandlers "socialpredict/handlers/users"
 	usercredit "socialpredict/handlers/users/credit"
 	privateuser "socialpredict/handlers/users/privateuser"
 	publicuser "socialpredict/handlers/users/publicuser"
 	"socialpredict/internal/app"
+	"socialpredict/internal/app/economyhistory"
+	"socialpredict/internal/app/equityhistory"
+	"socialpredict/internal/app/livestream"
 	"socialpredict/internal/app/readmodelinvalidation"
 	appruntime "socialpredict/internal/app/runtime"
+	"socialpredict/internal/app/trending"
+	dcomments "socialpredict/internal/domain/comments"
+	demail "socialpredict/internal/domain/email"
+	dfeed "socialpredict/internal/domain/feed"
 	dmarkets "socialpredict/internal/domain/markets"
+	dnotifications "socialpredict/internal/domain/notifications"
+	dprivacy "socialpredict/internal/domain/privacy"
+	dreports "socialpredict/internal/domain/reports"
+	dseasons "socialpredict/internal/domain/seasons"
+	dteams "socialpredict/internal/domain/teams"
+	dusers "socialpredict/internal/domain/users"
+	dwatchlists "socialpredict/internal/domain/watchlists"
+	dwebhooks "socialpredict/internal/domain/webhooks"
+	rcomments "socialpredict/internal/repository/comments"
+	remail "socialpredict/internal/repository/email"
+	rfeed "socialpredict/internal/repository/feed"
+	rnotifications "socialpredict/internal/repository/notifications"
+	rprivacy "socialpredict/internal/repository/privacy"
 	readmodelrepo "socialpredict/internal/repository/readmodels"
+	rreports "socialpredict/internal/repository/reports"
+	rseasons "socialpredict/internal/repository/seasons"
+	rteams "socialpredict/internal/repository/teams"
+	rwatchlists "socialpredict/internal/repository/watchlists"
+	rwebhooks "socialpredict/internal/repository/webhooks"
 	authsvc "socialpredict/internal/service/auth"
+	"socialpredict/internal/service/auth/oidc"
 	configsvc "socialpredict/internal/service/config"
+	emailsvc "socialpredict/internal/service/email"
+	webhooksvc "socialpredict/internal/service/webhooks"
 	"socialpredict/logger"
 	"socialpredict/models"
 	"socialpredict/security"
@@ -50,6 +86,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
+	"golang.org/x/time/rate"
 	"gorm.io/gorm"
 )
 
@@ -69,10 +106,23 @@ func buildCORS(config appruntime.CORSConfig) *cors.Cors {
 }
 
 func buildHandler(openAPISpec []byte, swaggerUIFS fs.FS, db *gorm.DB, configService configsvc.Service, readiness *appruntime.Readiness, securityConfig appruntime.SecurityConfig) (http.Handler, error) {
+	handler, _, err := buildHandlerWithWorkers(openAPISpec, swaggerUIFS, db, configService, readiness, securityConfig)
+	return handler, err
+}
+
+// backgroundWorker is a loop that runs alongside the HTTP server until its
+// context is cancelled, such as the outbox dispatcher.
+type backgroundWorker interface {
+	Run(ctx context.Context)
+}
+
+// buildHandlerWithWorkers also returns the background workers so Start can
+// run them for the lifetime of the server.
+func buildHandlerWithWorkers(openAPISpec []byte, swaggerUIFS fs.FS, db *gorm.DB, configService configsvc.Service, readiness *appruntime.Readiness, securityConfig appruntime.SecurityConfig) (http.Handler, []backgroundWorker, error) {
 	operationalMetrics := appruntime.NewOperationalMetrics()
-	router, err := buildRouter(openAPISpec, swaggerUIFS, db, configService, readiness, securityConfig, operationalMetrics)
+	router, workers, err := buildRouterWithWorkers(openAPISpec, swaggerUIFS, db, configService, readiness, securityConfig, operationalMetrics)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	handler := http.Handler(router)
@@ -86,25 +136,30 @@ func buildHandler(openAPISpec []byte, swaggerUIFS fs.FS, db *gorm.DB, configServ
 	handler = security.RequestBoundaryMiddlewareWithProxyTrust(securityConfig.TrustProxyHeaders)(handler)
 	handler = operationalMetrics.Middleware(handler)
 
-	return handler, nil
+	return handler, workers, nil
 }
 
 func buildRouter(openAPISpec []byte, swaggerUIFS fs.FS, db *gorm.DB, configService configsvc.Service, readiness *appruntime.Readiness, securityConfig appruntime.SecurityConfig, operationalMetrics *appruntime.OperationalMetrics) (*mux.Router, error) {
+	router, _, err := buildRouterWithWorkers(openAPISpec, swaggerUIFS, db, configService, readiness, securityConfig, operationalMetrics)
+	return router, err
+}
+
+func buildRouterWithWorkers(openAPISpec []byte, swaggerUIFS fs.FS, db *gorm.DB, configService configsvc.Service, readiness *appruntime.Readiness, securityConfig appruntime.SecurityConfig, operationalMetrics *appruntime.OperationalMetrics) (*mux.Router, []backgroundWorker, error) {
 	if configService == nil {
-		return nil, fmt.Errorf("config init: configuration service unavailable")
+		return nil, nil, fmt.Errorf("config init: configuration service unavailable")
 	}
 	if len(securityConfig.JWTSigningKey) == 0 {
-		return nil, fmt.Errorf("security init: JWT signing key unavailable")
+		return nil, nil, fmt.Errorf("security init: JWT signing key unavailable")
 	}
 
 	router := mux.NewRouter()
 	router.MethodNotAllowedHandler = methodNotAllowedHandler(router)
 	if err := registerInfraRoutes(router, openAPISpec, swaggerUIFS, db, readiness, operationalMetrics); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	registerApplicationRoutes(router, db, configService, securityConfig)
-	return router, nil
+	workers := registerApplicationRoutes(router, db, configService, securityConfig)
+	return router, workers, nil
 }
 
 func methodNotAllowedHandler(router *mux.Router) http.Handler {
@@ -162,6 +217,7 @@ const readinessProbeTimeout = 2 * time.Second
 type applicationReportingService interface {
 	metricshandlers.SystemMetricsService
 	metricshandlers.GlobalLeaderboardService
+	metricshandlers.CalibrationService
 }
 
 func registerInfraRoutes(router *mux.Router, openAPISpec []byte, swaggerUIFS fs.FS, db *gorm.DB, readiness *appruntime.Readiness, operationalMetrics *appruntime.OperationalMetrics) error {
@@ -289,6 +345,13 @@ func registerApplicationReportingRoutes(router *mux.Router, configService config
 	router.Handle("/v0/global/leaderboard", securityMiddleware(reportingVisibilityGate(visibility, auth, func(s *models.ReportingVisibilitySettings) bool {
 		return s == nil || s.GlobalLeaderboardPublic
 	}, metricshandlers.GetGlobalLeaderboardHandler(reportingService)))).Methods("GET")
+	router.Handle("/v0/market-tags/{slug}/leaderboard", securityMiddleware(reportingVisibilityGate(visibility, auth, func(s *models.ReportingVisibilitySettings) bool {
+		return s == nil || s.GlobalLeaderboardPublic
+	}, metricshandlers.GetTagLeaderboardHandler(reportingService)))).Methods("GET")
+	router.Handle("/v0/global/leaderboard/skill", securityMiddleware(reportingVisibilityGate(visibility, auth, func(s *models.ReportingVisibilitySettings) bool {
+		return s == nil || s.GlobalLeaderboardPublic
+	}, metricshandlers.GetSkillLeaderboardHandler(reportingService)))).Methods("GET")
+	router.Handle("/v0/users/{username}/calibration", securityMiddleware(metricshandlers.GetUserCalibrationHandler(reportingService))).Methods("GET")
 }
 
 func reportingVisibilityGate(visibility reportingVisibilityService, auth authsvc.Authenticator, isPublic func(*models.ReportingVisibilitySettings) bool, next http.HandlerFunc) http.Handler {
@@ -333,55 +396,64 @@ func requirePasswordChangeCleared(auth authsvc.Authenticator, next http.Handler)
 	})
 }
 
-type marketDiscoveryStaleMarker interface {
-	MarkMarketDiscoverySnapshotsStale(ctx context.Context, reason string) error
-}
-
-type responseStatusRecorder struct {
-	http.ResponseWriter
-	status int
-}
-
-func (r *responseStatusRecorder) WriteHeader(status int) {
-	r.status = status
-	r.ResponseWriter.WriteHeader(status)
-}
-
-func (r *responseStatusRecorder) Write(data []byte) (int, error) {
-	if r.status == 0 {
-		r.status = http.StatusOK
-	}
-	return r.ResponseWriter.Write(data)
-}
-
-func markDiscoveryStaleOnSuccess(marker marketDiscoveryStaleMarker, reason string, next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		recorder := &responseStatusRecorder{ResponseWriter: w}
-		next.ServeHTTP(recorder, r)
-		status := recorder.status
-		if status == 0 {
-			status = http.StatusOK
-		}
-		if marker != nil && status >= 200 && status < 300 {
-			_ = marker.MarkMarketDiscoverySnapshotsStale(r.Context(), reason)
-		}
-	})
-}
-
-func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService configsvc.Service, securityConfig appruntime.SecurityConfig) {
+// registerApplicationRoutes wires the /v0 API and returns the background
+// workers, starting with the outbox dispatcher and its subscribers.
+func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService configsvc.Service, securityConfig appruntime.SecurityConfig) []backgroundWorker {
 	container := app.BuildApplicationWithConfigAndJWTSigningKey(db, configService, securityConfig.JWTSigningKey)
 	marketsService := container.GetMarketsService()
 	usersService := container.GetUsersService()
+	permissionsService := container.GetPermissionsService()
 	usersRepo := container.GetUsersRepository()
 	analyticsService := container.GetAnalyticsService()
 	authService := container.GetAuthService()
 	requestSecurityService := container.GetSecurityService()
 	readModelSnapshotRepo := readmodelrepo.NewGormRepository(db)
 	readModelInvalidator := readmodelinvalidation.New(marketsService, analyticsService, readModelSnapshotRepo)
+	eventDispatcher := container.GetEventDispatcher()
+	eventDispatcher.Subscribe("read_models", readModelInvalidator)
+	allowInsecureWebhooks := securityConfig.Webhooks.AllowInsecureTargets
+	webhooksService := dwebhooks.NewService(rwebhooks.NewGormRepository(db), webhooksvc.NewHTTPSender(0, allowInsecureWebhooks), permissionsService, dwebhooks.Config{AllowInsecureTargets: allowInsecureWebhooks}, time.Now)
+	eventDispatcher.Subscribe("webhooks", webhooksService)
+	privacyService := dprivacy.NewService(rprivacy.NewGormRepository(db), permissionsService, time.Now)
+	tradePrivacy := privacyhandlers.NewGuard(privacyService, authService)
+	liveStreams := livestream.NewHub(container.GetEventRecorder(), marketsService, livestream.Config{})
+	liveStreams.SetTradePrivacy(privacyService)
+	eventDispatcher.Subscribe("live_streams", liveStreams)
+	notificationsService := dnotifications.NewService(rnotifications.NewGormRepository(db), marketsService, time.Now)
+	watchlistsService := dwatchlists.NewService(rwatchlists.NewGormRepository(db), marketsService, time.Now)
+	notificationsService.SetFollowers(watchlistsService)
+	eventDispatcher.Subscribe("notifications", notificationsService)
+	financialHistory := equityhistory.NewRecorder(analyticsService, marketsService, 0, time.Now)
+	eventDispatcher.Subscribe("financial_history", financialHistory)
+	economyHistory := economyhistory.NewRecorder(analyticsService, 0, time.Now)
+	emailService := demail.NewService(remail.NewGormRepository(db), usersService, marketsService, buildEmailTransport(securityConfig.Email), demail.Config{
+		PublicBaseURL: securityConfig.Share.PublicBaseURL,
+		SiteName:      securityConfig.Share.SiteName,
+	}, time.Now)
+	commentsService := dcomments.NewService(rcomments.NewGormRepository(db), marketsService, security.NewMarkdownLite(), usersService, permissionsService, dcomments.Config{}, time.Now)
+	commentsService.SetTradePrivacy(privacyService)
+	// Each account may post or edit a comment every ten seconds, with a
+	// burst of five.
+	commentLimiter := security.NewRateLimiter(rate.Every(10*time.Second), 5, time.Hour)
+	reportsService := dreports.NewService(rreports.NewGormRepository(db), marketsService, commentsService, usersService, permissionsService, time.Now)
+	// Each account may file a report every thirty seconds, with a burst of
+	// ten.
+	reportLimiter := security.NewRateLimiter(rate.Every(30*time.Second), 10, time.Hour)
+	feedService := dfeed.NewService(rfeed.NewGormRepository(db), usersService, time.Now)
+	seasonsService := dseasons.NewService(rseasons.NewGormRepository(db), analyticsService, marketsService, permissionsService, time.Now)
+	teamsService := dteams.NewService(rteams.NewGormRepository(db), usersService, marketsService, analyticsService, permissionsService, time.Now)
+	container.GetBetsService().SetTradeGuard(teamsService)
+	trendingScores := trending.NewRefresher(marketsService, 0, time.Now)
+	workers := []backgroundWorker{eventDispatcher, webhooksvc.NewWorker(webhooksService, 0), liveStreams, financialHistory, economyHistory, trendingScores}
+	if securityConfig.Email.Enabled() {
+		notificationsService.SetForwarder(emailService)
+		workers = append(workers, emailsvc.NewWorker(emailService, 0, 0))
+	}
 
 	// Create Handler instances
 	marketsHandler := marketshandlers.NewHandler(marketsService, authService, requestSecurityService)
-	marketsHandler.SetReadModelInvalidator(readModelInvalidator)
+	marketsHandler.SetFollowerCounter(watchlistsService)
+	marketsHandler.SetTradePrivacy(tradePrivacy)
 
 	// Define endpoint handlers using Gorilla Mux router
 	// This defines all functions starting with /api/
@@ -389,13 +461,22 @@ func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService co
 	// Apply security middleware to all routes
 	securityService := security.NewRuntimeSecurityService(securityConfig.RateLimit, securityConfig.Headers)
 	securityMiddleware := securityService.SecurityMiddleware()
+	// Live streams are capped per client address, using the same proxy-header
+	// trust as rate limiting.
+	streamClientID := security.NewClientIdentityExtractor(securityConfig.TrustProxyHeaders).Extract
 	loginSecurityMiddleware := securityService.LoginSecurityMiddleware()
 	privateActionMiddleware := func(next http.Handler) http.Handler {
 		return securityMiddleware(requirePasswordChangeCleared(authService, next))
 	}
 
 	router.HandleFunc("/v0/home", handlers.HomeHandler).Methods("GET")
-	router.Handle("/v0/login", loginSecurityMiddleware(authsvc.LoginHandler(usersRepo, requestSecurityService, securityConfig.JWTSigningKey))).Methods("POST")
+	loginProtection := loginProtectionSettings(usersService, securityConfig)
+	router.Handle("/v0/login", loginSecurityMiddleware(authsvc.ProtectedLoginHandler(usersRepo, loginProtection, requestSecurityService, securityConfig.JWTSigningKey))).Methods("POST")
+	router.Handle("/v0/register", loginSecurityMiddleware(usershandlers.RegisterHandler(usersService, configService, requestSecurityService, time.Now))).Methods("POST")
+	oidcFlow := buildOIDCFlow(db, securityConfig.OIDC)
+	router.Handle("/v0/auth/oidc/authorize", loginSecurityMiddleware(authsvc.OIDCAuthorizeHandler(oidcFlow))).Methods("GET")
+	router.Handle("/v0/auth/oidc/callback", loginSecurityMiddleware(authsvc.OIDCCallbackHandler(oidcFlow, usersService, oidcSignInSettings(securityConfig.OIDC, configService, loginProtection), securityConfig.JWTSigningKey))).Methods("POST")
+	router.Handle("/v0/auth/oidc/link", privateActionMiddleware(authsvc.OIDCLinkHandler(oidcFlow, usersService, authService, time.Now))).Methods("POST")
 
 	// application setup information
 	router.Handle("/v0/setup", securityMiddleware(http.HandlerFunc(setuphandlers.GetSetupHandler(container.GetConfigService())))).Methods("GET")
@@ -404,6 +485,17 @@ func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService co
 	reportingVisibilitySvc := reportingvisibility.NewService(reportingVisibilityRepo)
 	reportingVisibilityHandler := cmsreportinghttp.NewHandler(reportingVisibilitySvc, authService)
 	registerApplicationReportingRoutes(router, configService, analyticsService, analyticsService, reportingVisibilitySvc, authService, securityMiddleware)
+	globalLeaderboardGate := func(next http.HandlerFunc) http.Handler {
+		return securityMiddleware(reportingVisibilityGate(reportingVisibilitySvc, authService, func(s *models.ReportingVisibilitySettings) bool {
+			return s == nil || s.GlobalLeaderboardPublic
+		}, next))
+	}
+	router.Handle("/v0/seasons", securityMiddleware(seasonshandlers.ListSeasonsHandler(seasonsService, time.Now))).Methods("GET")
+	router.Handle("/v0/seasons/{id}", securityMiddleware(seasonshandlers.GetSeasonHandler(seasonsService, time.Now))).Methods("GET")
+	router.Handle("/v0/seasons/{id}/standings", globalLeaderboardGate(seasonshandlers.GetSeasonStandingsHandler(seasonsService, time.Now))).Methods("GET")
+	router.Handle("/v0/global/leaderboard/teams", globalLeaderboardGate(teamshandlers.TeamLeaderboardHandler(teamsService))).Methods("GET")
+	router.Handle("/v0/teams", securityMiddleware(teamshandlers.ListTeamsHandler(teamsService))).Methods("GET")
+	router.Handle("/v0/teams/{slug}", globalLeaderboardGate(teamshandlers.GetTeamHandler(teamsService))).Methods("GET")
 
 	// CMS routes and services
 	homepageRepo := homepage.NewGormRepository(db)
@@ -414,7 +506,7 @@ func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService co
 	marketDiscoveryRepo := marketdiscovery.NewGormRepository(db)
 	marketDiscoverySvc := marketdiscovery.NewService(marketDiscoveryRepo)
 	marketDiscoveryHandler := cmsdiscoveryhttp.NewHandler(marketDiscoverySvc, authService)
-	marketDiscoveryHandler.SetReadModelInvalidator(readModelSnapshotRepo)
+	marketDiscoveryHandler.SetEventRecorder(container.GetEventRecorder())
 	marketDiscoveryReadModelHandler := marketshandlers.NewMarketDiscoveryReadModelHandler(marketsService, marketDiscoverySvc, readModelSnapshotRepo)
 
 	socialShareRepo := socialshare.NewGormRepository(db)
@@ -447,16 +539,21 @@ func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService co
 	router.Handle("/v0/read/market-discovery/{slug}", securityMiddleware(http.HandlerFunc(marketDiscoveryReadModelHandler.Get))).Methods("GET")
 	router.Handle("/v0/markets", securityMiddleware(http.HandlerFunc(marketsHandler.CreateMarket))).Methods("POST")
 	router.Handle("/v0/market-groups", securityMiddleware(http.HandlerFunc(marketsHandler.CreateMarketGroup))).Methods("POST")
-	router.Handle("/v0/market-groups/{id}/answers", privateActionMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_group_answer_added", http.HandlerFunc(marketsHandler.ProposeMarketGroupAnswerAddition)))).Methods("POST")
+	router.Handle("/v0/market-groups/{id}/answers", privateActionMiddleware(http.HandlerFunc(marketsHandler.ProposeMarketGroupAnswerAddition))).Methods("POST")
 	router.Handle("/v0/market-groups/{id}/answer-additions", securityMiddleware(http.HandlerFunc(marketsHandler.ListMarketGroupAnswerAdditionsForReview))).Methods("GET")
-	router.Handle("/v0/market-groups/{id}/answer-addition-settings", privateActionMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_group_answer_addition_policy", http.HandlerFunc(marketsHandler.UpdateMarketGroupAnswerAdditionSettings)))).Methods("PATCH")
+	router.Handle("/v0/market-groups/{id}/answer-addition-settings", privateActionMiddleware(http.HandlerFunc(marketsHandler.UpdateMarketGroupAnswerAdditionSettings))).Methods("PATCH")
 	router.Handle("/v0/market-groups/{id}/bets", securityMiddleware(http.HandlerFunc(marketsHandler.MarketGroupBets))).Methods("GET")
 	router.Handle("/v0/market-groups/{id}/positions", securityMiddleware(http.HandlerFunc(marketsHandler.MarketGroupPositions))).Methods("GET")
 	router.Handle("/v0/market-groups/{id}/leaderboard", securityMiddleware(http.HandlerFunc(marketsHandler.MarketGroupLeaderboard))).Methods("GET")
 	router.Handle("/v0/market-groups/{id}/resolve", securityMiddleware(http.HandlerFunc(marketsHandler.ResolveMarketGroup))).Methods("POST")
+	router.Handle("/v0/market-groups/{id}/stream", securityMiddleware(marketshandlers.MarketGroupStreamHandler(liveStreams, streamClientID))).Methods("GET")
+	router.Handle("/v0/market-groups/{id}/comments", securityMiddleware(commentshandlers.ListMarketGroupCommentsHandler(commentsService))).Methods("GET")
+	router.Handle("/v0/market-groups/{id}/comments", privateActionMiddleware(commentshandlers.PostMarketGroupCommentHandler(commentsService, authService, commentLimiter))).Methods("POST")
+	router.Handle("/v0/market-groups/{id}/follow", privateActionMiddleware(marketshandlers.FollowHandler(watchlistsService, authService, dwatchlists.TargetMarketGroup))).Methods("POST")
+	router.Handle("/v0/market-groups/{id}/follow", privateActionMiddleware(marketshandlers.UnfollowHandler(watchlistsService, authService, dwatchlists.TargetMarketGroup))).Methods("DELETE")
 	router.Handle("/v0/market-groups/{id}", securityMiddleware(http.HandlerFunc(marketsHandler.GetMarketGroup))).Methods("GET")
 	router.Handle("/v0/profile/market-group-answer-additions", securityMiddleware(http.HandlerFunc(marketsHandler.ListMarketGroupAnswerAdditionsForReview))).Methods("GET")
-	router.Handle("/v0/profile/market-group-answer-additions/{additionId}", privateActionMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_group_answer_reviewed", http.HandlerFunc(marketsHandler.ReviewMarketGroupAnswerAddition)))).Methods("PATCH")
+	router.Handle("/v0/profile/market-group-answer-additions/{additionId}", privateActionMiddleware(http.HandlerFunc(marketsHandler.ReviewMarketGroupAnswerAddition))).Methods("PATCH")
 	router.Handle("/v0/markets/search", securityMiddleware(http.HandlerFunc(marketsHandler.SearchMarkets))).Methods("GET")
 	router.Handle("/v0/markets/status/{status}", securityMiddleware(http.HandlerFunc(marketsHandler.ListByStatus))).Methods("GET")
 	router.Handle("/v0/markets/status", securityMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -489,26 +586,66 @@ func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService co
 	router.Handle("/v0/markets/{id}/description-amendments", privateActionMiddleware(http.HandlerFunc(marketsHandler.ProposeDescriptionAmendment))).Methods("POST")
 	router.Handle("/v0/markets/{id}/leaderboard", securityMiddleware(http.HandlerFunc(marketsHandler.MarketLeaderboard))).Methods("GET")
 	router.Handle("/v0/markets/{id}/projection", securityMiddleware(http.HandlerFunc(marketsHandler.ProjectProbability))).Methods("GET")
+	router.Handle("/v0/markets/{id}/history", securityMiddleware(http.HandlerFunc(marketsHandler.ProbabilityHistory))).Methods("GET")
+	router.Handle("/v0/markets/{id}/stream", securityMiddleware(marketshandlers.MarketStreamHandler(liveStreams, streamClientID))).Methods("GET")
+	router.Handle("/v0/markets/{id}/comments", securityMiddleware(commentshandlers.ListMarketCommentsHandler(commentsService))).Methods("GET")
+	router.Handle("/v0/markets/{id}/comments", privateActionMiddleware(commentshandlers.PostMarketCommentHandler(commentsService, authService, commentLimiter))).Methods("POST")
+	router.Handle("/v0/markets/{id}/follow", privateActionMiddleware(marketshandlers.FollowHandler(watchlistsService, authService, dwatchlists.TargetMarket))).Methods("POST")
+	router.Handle("/v0/markets/{id}/follow", privateActionMiddleware(marketshandlers.UnfollowHandler(watchlistsService, authService, dwatchlists.TargetMarket))).Methods("DELETE")
+	router.Handle("/v0/comments/{id}", privateActionMiddleware(commentshandlers.EditCommentHandler(commentsService, authService, commentLimiter))).Methods("PATCH")
+	router.Handle("/v0/comments/{id}", privateActionMiddleware(commentshandlers.DeleteCommentHandler(commentsService, authService))).Methods("DELETE")
 	router.Handle("/v0/market-tags", securityMiddleware(marketshandlers.ListMarketTagsHandler(marketsService))).Methods("GET")
+	router.Handle("/v0/market-tags/{slug}/follow", privateActionMiddleware(marketshandlers.FollowHandler(watchlistsService, authService, dwatchlists.TargetTag))).Methods("POST")
+	router.Handle("/v0/market-tags/{slug}/follow", privateActionMiddleware(marketshandlers.UnfollowHandler(watchlistsService, authService, dwatchlists.TargetTag))).Methods("DELETE")
 	router.Handle("/v0/marketprojection/{marketId}/{amount}/{outcome}", securityMiddleware(marketshandlers.ProjectNewProbabilityHandler(marketsService))).Methods("GET")
 	router.Handle("/v0/marketprojection/{marketId}/{amount}/{outcome}/", securityMiddleware(marketshandlers.ProjectNewProbabilityHandler(marketsService))).Methods("GET")
 
 	// handle market positions, get trades - using service injection from new locations
-	router.Handle("/v0/markets/bets/{marketId}", securityMiddleware(betshandlers.MarketBetsHandlerWithService(marketsService))).Methods("GET")
-	router.Handle("/v0/markets/positions/{marketId}", securityMiddleware(positionshandlers.MarketPositionsHandlerWithService(marketsService))).Methods("GET")
-	router.Handle("/v0/markets/positions/{marketId}/{username}", securityMiddleware(positionshandlers.MarketUserPositionHandlerWithService(marketsService))).Methods("GET")
+	router.Handle("/v0/markets/bets/{marketId}", securityMiddleware(betshandlers.MarketBetsHandlerWithService(marketsService, tradePrivacy))).Methods("GET")
+	router.Handle("/v0/markets/positions/{marketId}", securityMiddleware(positionshandlers.MarketPositionsHandlerWithService(marketsService, tradePrivacy))).Methods("GET")
+	router.Handle("/v0/markets/positions/{marketId}/{username}", securityMiddleware(positionshandlers.MarketUserPositionHandlerWithService(marketsService, tradePrivacy))).Methods("GET")
 
 	// handle public user stuff
 	router.Handle("/v0/userinfo/{username}", securityMiddleware(usershandlers.GetPublicUserHandler(usersService))).Methods("GET")
 	router.Handle("/v0/usercredit/{username}", securityMiddleware(usercredit.GetUserCreditHandler(usersService, configService.Economics().User.MaximumDebtAllowed))).Methods("GET")
-	router.Handle("/v0/portfolio/{username}", securityMiddleware(publicuser.GetPortfolioHandler(usersService))).Methods("GET")
-	router.Handle("/v0/users/{username}/financial", securityMiddleware(usershandlers.GetUserFinancialHandler(usersService))).Methods("GET")
-	router.Handle("/v0/read/users/{username}/financial-summary", securityMiddleware(usershandlers.GetUserFinancialReadModelHandler(analyticsService, authService))).Methods("GET")
+	router.Handle("/v0/portfolio/{username}", securityMiddleware(publicuser.GetPortfolioHandler(usersService, tradePrivacy))).Methods("GET")
+	router.Handle("/v0/users/{username}/financial", securityMiddleware(usershandlers.GetUserFinancialHandler(usersService, tradePrivacy))).Methods("GET")
+	router.Handle("/v0/users/{username}/financial/history", securityMiddleware(usershandlers.GetUserFinancialHistoryHandler(analyticsService, tradePrivacy, time.Now))).Methods("GET")
+	router.Handle("/v0/read/users/{username}/financial-summary", securityMiddleware(usershandlers.GetUserFinancialReadModelHandler(analyticsService, authService, tradePrivacy))).Methods("GET")
 	router.Handle("/v0/users/{username}/owned-markets", securityMiddleware(marketshandlers.ListUserOwnedMarketsHandler(marketsService, authService))).Methods("GET")
+	router.Handle("/v0/users/{username}/follow", privateActionMiddleware(feedhandlers.FollowUserHandler(feedService, authService))).Methods("POST")
+	router.Handle("/v0/users/{username}/follow", privateActionMiddleware(feedhandlers.UnfollowUserHandler(feedService, authService))).Methods("DELETE")
+	router.Handle("/v0/activity", securityMiddleware(feedhandlers.GlobalFeedHandler(feedService))).Methods("GET")
+	router.Handle("/v0/teams/{slug}", privateActionMiddleware(teamshandlers.UpdateTeamHandler(teamsService, authService))).Methods("PATCH")
+	router.Handle("/v0/teams/{slug}/invites", privateActionMiddleware(teamshandlers.InviteMemberHandler(teamsService, authService))).Methods("POST")
+	router.Handle("/v0/teams/{slug}/members/{username}", privateActionMiddleware(teamshandlers.RemoveMemberHandler(teamsService, authService))).Methods("DELETE")
+	router.Handle("/v0/teams/{slug}/markets/{marketId}", privateActionMiddleware(teamshandlers.TeamMarketHandler(teamsService, authService, true))).Methods("PUT")
+	router.Handle("/v0/teams/{slug}/markets/{marketId}", privateActionMiddleware(teamshandlers.TeamMarketHandler(teamsService, authService, false))).Methods("DELETE")
+	router.Handle("/v0/team-invites/{id}/accept", privateActionMiddleware(teamshandlers.AnswerInviteHandler(teamsService, authService, true))).Methods("POST")
+	router.Handle("/v0/team-invites/{id}/decline", privateActionMiddleware(teamshandlers.AnswerInviteHandler(teamsService, authService, false))).Methods("POST")
 
 	// handle private user stuff, display sensitive profile information to customize
 	router.Handle("/v0/privateprofile", securityMiddleware(privateuser.GetPrivateProfileHandler(usersService))).Methods("GET")
+	router.Handle("/v0/reports", securityMiddleware(reportshandlers.ListMyReportsHandler(reportsService, authService))).Methods("GET")
+	router.Handle("/v0/reports", privateActionMiddleware(reportshandlers.SubmitReportHandler(reportsService, authService, reportLimiter))).Methods("POST")
+	router.Handle("/v0/notifications", securityMiddleware(notificationshandlers.ListNotificationsHandler(notificationsService, authService))).Methods("GET")
+	router.Handle("/v0/notifications/unread-count", securityMiddleware(notificationshandlers.UnreadCountHandler(notificationsService, authService))).Methods("GET")
+	router.Handle("/v0/notifications/read", privateActionMiddleware(notificationshandlers.MarkReadHandler(notificationsService, authService))).Methods("POST")
+	router.Handle("/v0/notifications/read-all", privateActionMiddleware(notificationshandlers.MarkAllReadHandler(notificationsService, authService))).Methods("POST")
+	router.Handle("/v0/notifications/preferences", securityMiddleware(notificationshandlers.GetPreferencesHandler(notificationsService, authService))).Methods("GET")
+	router.Handle("/v0/notifications/preferences", privateActionMiddleware(notificationshandlers.UpdatePreferencesHandler(notificationsService, authService))).Methods("PUT")
+	router.Handle("/v0/email/preferences", securityMiddleware(emailhandlers.GetPreferencesHandler(emailService, authService))).Methods("GET")
+	router.Handle("/v0/email/preferences", privateActionMiddleware(emailhandlers.UpdatePreferencesHandler(emailService, authService))).Methods("PUT")
+	// Unsubscribe links are authorized by the token they carry, not a session.
+	router.Handle("/v0/email/unsubscribe", securityMiddleware(emailhandlers.GetUnsubscribeHandler(emailService))).Methods("GET")
+	router.Handle("/v0/email/unsubscribe", securityMiddleware(emailhandlers.UnsubscribeHandler(emailService))).Methods("POST")
 	router.Handle("/v0/profile/markets", securityMiddleware(marketshandlers.ListMyLifecycleMarketsHandler(marketsService, authService))).Methods("GET")
+	router.Handle("/v0/profile/watchlist", securityMiddleware(marketshandlers.WatchlistHandler(watchlistsService, marketsService, authService))).Methods("GET")
+	router.Handle("/v0/profile/following", securityMiddleware(feedhandlers.FollowingHandler(feedService, authService))).Methods("GET")
+	router.Handle("/v0/profile/team-invites", securityMiddleware(teamshandlers.MyInvitesHandler(teamsService, authService))).Methods("GET")
+	router.Handle("/v0/feed", securityMiddleware(feedhandlers.FeedHandler(feedService, authService))).Methods("GET")
+	router.Handle("/v0/profile/privacy", securityMiddleware(privacyhandlers.GetSettingsHandler(privacyService, authService))).Methods("GET")
+	router.Handle("/v0/profile/privacy", securityMiddleware(privacyhandlers.UpdateSettingsHandler(privacyService, authService))).Methods("PUT")
 	router.Handle("/v0/profile/market-description-amendments", securityMiddleware(http.HandlerFunc(marketsHandler.ListMyDescriptionAmendments))).Methods("GET")
 
 	// changing profile stuff - apply security middleware
@@ -519,35 +656,68 @@ func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService co
 	router.Handle("/v0/profilechange/links", securityMiddleware(usershandlers.ChangePersonalLinksHandler(usersService))).Methods("POST")
 
 	// handle private user actions such as make a bet, sell positions, get user position
-	router.Handle("/v0/bet", privateActionMiddleware(buybetshandlers.PlaceBetHandlerWithInvalidator(container.GetBetsService(), container.GetUsersService(), readModelInvalidator))).Methods("POST")
+	router.Handle("/v0/bet", privateActionMiddleware(buybetshandlers.PlaceBetHandler(container.GetBetsService(), container.GetUsersService()))).Methods("POST")
 	router.Handle("/v0/userposition/{marketId}", privateActionMiddleware(usershandlers.UserMarketPositionHandlerWithService(marketsService, usersService))).Methods("GET")
 	router.Handle("/v0/sell/quote", privateActionMiddleware(sellbetshandlers.SellQuoteHandler(container.GetBetsService(), container.GetUsersService()))).Methods("POST")
-	router.Handle("/v0/sell", privateActionMiddleware(sellbetshandlers.SellPositionHandlerWithInvalidator(container.GetBetsService(), container.GetUsersService(), readModelInvalidator))).Methods("POST")
+	router.Handle("/v0/sell", privateActionMiddleware(sellbetshandlers.SellPositionHandler(container.GetBetsService(), container.GetUsersService()))).Methods("POST")
 
 	// admin stuff - apply security middleware
 	router.Handle("/v0/admin/createuser", securityMiddleware(http.HandlerFunc(adminhandlers.AddUserHandler(usersService, container.GetConfigService(), authService, requestSecurityService)))).Methods("POST")
-	router.Handle("/v0/admin/users", securityMiddleware(adminhandlers.ListAdminUsersHandler(usersService, authService))).Methods("GET")
-	router.Handle("/v0/admin/users/{username}/role", securityMiddleware(adminhandlers.UpdateAdminUserRoleHandler(usersService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/users", securityMiddleware(adminhandlers.ListAdminUsersHandler(usersService, authService, time.Now))).Methods("GET")
+	router.Handle("/v0/admin/users/{username}/role", securityMiddleware(adminhandlers.UpdateAdminUserRoleHandler(usersService, authService, time.Now))).Methods("PATCH")
+	router.Handle("/v0/admin/users/{username}/login-lock", securityMiddleware(adminhandlers.GetLoginLockHandler(usersService, authService, time.Now))).Methods("GET")
+	router.Handle("/v0/admin/users/{username}/unlock", securityMiddleware(adminhandlers.UnlockLoginHandler(usersService, authService, time.Now))).Methods("PATCH")
+	router.Handle("/v0/admin/users/{username}/account-status", securityMiddleware(adminhandlers.UpdateAccountStatusHandler(usersService, authService, time.Now))).Methods("PATCH")
+	router.Handle("/v0/admin/users/{username}/account-status-audits", securityMiddleware(adminhandlers.ListAccountStatusAuditsHandler(usersService, authService))).Methods("GET")
+	router.Handle("/v0/admin/login-events", securityMiddleware(adminhandlers.ListLoginEventsHandler(usersService, authService))).Methods("GET")
+	router.Handle("/v0/admin/permissions", securityMiddleware(adminhandlers.ListPermissionsHandler(authService))).Methods("GET")
+	router.Handle("/v0/admin/roles", securityMiddleware(adminhandlers.ListRolesHandler(permissionsService, authService))).Methods("GET")
+	router.Handle("/v0/admin/roles/{role}", securityMiddleware(adminhandlers.UpdateRoleHandler(permissionsService, authService, time.Now))).Methods("PUT")
+	router.Handle("/v0/admin/users/{username}/roles", securityMiddleware(adminhandlers.GetUserRolesHandler(permissionsService, authService))).Methods("GET")
+	router.Handle("/v0/admin/users/{username}/roles", securityMiddleware(adminhandlers.UpdateUserRolesHandler(permissionsService, authService, time.Now))).Methods("PUT")
+	router.Handle("/v0/admin/webhooks", securityMiddleware(adminhandlers.ListWebhooksHandler(webhooksService, authService))).Methods("GET")
+	router.Handle("/v0/admin/webhooks", securityMiddleware(adminhandlers.CreateWebhookHandler(webhooksService, authService))).Methods("POST")
+	router.Handle("/v0/admin/webhooks/{id}", securityMiddleware(adminhandlers.GetWebhookHandler(webhooksService, authService))).Methods("GET")
+	router.Handle("/v0/admin/webhooks/{id}", securityMiddleware(adminhandlers.UpdateWebhookHandler(webhooksService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/webhooks/{id}", securityMiddleware(adminhandlers.DeleteWebhookHandler(webhooksService, authService))).Methods("DELETE")
+	router.Handle("/v0/admin/webhooks/{id}/deliveries", securityMiddleware(adminhandlers.ListWebhookDeliveriesHandler(webhooksService, authService))).Methods("GET")
+	router.Handle("/v0/admin/webhooks/{id}/deliveries/{deliveryId}/retry", securityMiddleware(adminhandlers.RetryWebhookDeliveryHandler(webhooksService, authService))).Methods("POST")
+	router.Handle("/v0/admin/webhooks/{id}/test", securityMiddleware(adminhandlers.SendWebhookTestHandler(webhooksService, authService))).Methods("POST")
+	router.Handle("/v0/admin/invites", securityMiddleware(adminhandlers.ListInvitesHandler(usersService, authService))).Methods("GET")
+	router.Handle("/v0/admin/invites", securityMiddleware(adminhandlers.CreateInviteHandler(usersService, authService, time.Now))).Methods("POST")
+	router.Handle("/v0/admin/invites/{id}/revoke", securityMiddleware(adminhandlers.RevokeInviteHandler(usersService, authService, time.Now))).Methods("PATCH")
+	router.Handle("/v0/admin/comments/{id}/hide", securityMiddleware(commentshandlers.HideCommentHandler(commentsService, authService))).Methods("POST")
+	router.Handle("/v0/admin/comments/{id}/unhide", securityMiddleware(commentshandlers.UnhideCommentHandler(commentsService, authService))).Methods("POST")
 	router.Handle("/v0/admin/moderators/{username}/suspension", securityMiddleware(adminhandlers.UpdateAdminModeratorSuspensionHandler(usersService, authService, time.Now))).Methods("PATCH")
 	router.Handle("/v0/admin/markets", securityMiddleware(adminhandlers.ListReviewMarketsHandler(marketsService, authService))).Methods("GET")
-	router.Handle("/v0/admin/markets/{id}/approve", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_status_changed", adminhandlers.ApproveMarketHandler(marketsService, authService)))).Methods("PATCH")
-	router.Handle("/v0/admin/markets/{id}/reject", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_status_changed", adminhandlers.RejectMarketHandler(marketsService, authService)))).Methods("PATCH")
-	router.Handle("/v0/admin/market-groups/{id}/approve", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_group_approved", adminhandlers.ApproveMarketGroupHandler(marketsService, authService)))).Methods("PATCH")
-	router.Handle("/v0/admin/market-groups/{id}/reject", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_group_rejected", adminhandlers.RejectMarketGroupHandler(marketsService, authService)))).Methods("PATCH")
-	router.Handle("/v0/admin/market-groups/{id}/steward", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_steward_changed", adminhandlers.ReassignMarketGroupStewardHandler(marketsService, authService)))).Methods("PATCH")
-	router.Handle("/v0/admin/market-groups/{id}/tags", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_tags_changed", adminhandlers.UpdateMarketGroupTagsHandler(marketsService, authService)))).Methods("PATCH")
+	router.Handle("/v0/admin/markets/{id}/approve", securityMiddleware(adminhandlers.ApproveMarketHandler(marketsService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/markets/{id}/reject", securityMiddleware(adminhandlers.RejectMarketHandler(marketsService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/market-groups/{id}/approve", securityMiddleware(adminhandlers.ApproveMarketGroupHandler(marketsService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/market-groups/{id}/reject", securityMiddleware(adminhandlers.RejectMarketGroupHandler(marketsService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/market-groups/{id}/steward", securityMiddleware(adminhandlers.ReassignMarketGroupStewardHandler(marketsService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/market-groups/{id}/tags", securityMiddleware(adminhandlers.UpdateMarketGroupTagsHandler(marketsService, authService))).Methods("PATCH")
 	router.Handle("/v0/admin/markets/{id}/steward", securityMiddleware(adminhandlers.ReassignMarketStewardHandler(marketsService, authService))).Methods("PATCH")
-	router.Handle("/v0/admin/markets/{id}/tags", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_tags_changed", adminhandlers.UpdateMarketTagsHandler(marketsService, authService)))).Methods("PATCH")
+	router.Handle("/v0/admin/markets/{id}/tags", securityMiddleware(adminhandlers.UpdateMarketTagsHandler(marketsService, authService))).Methods("PATCH")
+	router.Handle("/v0/admin/reports", securityMiddleware(reportshandlers.ListReportQueueHandler(reportsService, authService))).Methods("GET")
+	router.Handle("/v0/admin/reports/actions", securityMiddleware(reportshandlers.ListModerationActionsHandler(reportsService, authService))).Methods("GET")
+	router.Handle("/v0/admin/reports/actions", securityMiddleware(reportshandlers.CreateModerationActionHandler(reportsService, authService))).Methods("POST")
+	router.Handle("/v0/admin/seasons", securityMiddleware(seasonshandlers.CreateSeasonHandler(seasonsService, authService, time.Now))).Methods("POST")
+	router.Handle("/v0/admin/seasons/{id}", securityMiddleware(seasonshandlers.UpdateSeasonHandler(seasonsService, authService, time.Now))).Methods("PATCH")
+	router.Handle("/v0/admin/seasons/{id}/rollover", securityMiddleware(seasonshandlers.RolloverSeasonHandler(seasonsService, authService, time.Now))).Methods("POST")
+	router.Handle("/v0/admin/economy/history", securityMiddleware(adminhandlers.GetEconomyHistoryHandler(analyticsService, authService, time.Now))).Methods("GET")
+	router.Handle("/v0/admin/economy/reconciliation", securityMiddleware(adminhandlers.GetEconomyReconciliationHandler(analyticsService, authService, time.Now))).Methods("GET")
+	router.Handle("/v0/admin/teams", securityMiddleware(teamshandlers.CreateTeamHandler(teamsService, authService))).Methods("POST")
+	router.Handle("/v0/admin/teams/{slug}/members/{username}", securityMiddleware(teamshandlers.AddMemberHandler(teamsService, authService))).Methods("PUT")
 	router.Handle("/v0/admin/market-description-amendments", securityMiddleware(adminhandlers.ListMarketDescriptionAmendmentsHandler(marketsService, authService))).Methods("GET")
 	router.Handle("/v0/admin/market-description-amendments/settings", securityMiddleware(adminhandlers.GetMarketGovernanceSettingsHandler(marketsService, authService))).Methods("GET")
 	router.Handle("/v0/admin/market-description-amendments/settings", securityMiddleware(adminhandlers.UpdateMarketGovernanceSettingsHandler(marketsService, authService))).Methods("PUT")
 	router.Handle("/v0/admin/market-description-amendments/grouped-review", securityMiddleware(adminhandlers.ReviewGroupedMarketDescriptionAmendmentsHandler(marketsService, authService))).Methods("PATCH")
 	router.Handle("/v0/admin/market-description-amendments/{id}", securityMiddleware(adminhandlers.ReviewMarketDescriptionAmendmentHandler(marketsService, authService))).Methods("PATCH")
 	router.Handle("/v0/admin/market-group-answer-additions", securityMiddleware(adminhandlers.ListMarketGroupAnswerAdditionsHandler(marketsService, authService))).Methods("GET")
-	router.Handle("/v0/admin/market-group-answer-additions/{id}", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "market_group_answer_added", adminhandlers.ReviewMarketGroupAnswerAdditionHandler(marketsService, authService)))).Methods("PATCH")
+	router.Handle("/v0/admin/market-group-answer-additions/{id}", securityMiddleware(adminhandlers.ReviewMarketGroupAnswerAdditionHandler(marketsService, authService))).Methods("PATCH")
 	router.Handle("/v0/admin/market-tags", securityMiddleware(adminhandlers.ListAdminMarketTagsHandler(marketsService, authService))).Methods("GET")
-	router.Handle("/v0/admin/market-tags", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "tag_catalog_changed", adminhandlers.CreateAdminMarketTagHandler(marketsService, authService)))).Methods("POST")
-	router.Handle("/v0/admin/market-tags/{slug}", securityMiddleware(markDiscoveryStaleOnSuccess(readModelSnapshotRepo, "tag_catalog_changed", adminhandlers.UpdateAdminMarketTagHandler(marketsService, authService)))).Methods("PATCH")
+	router.Handle("/v0/admin/market-tags", securityMiddleware(adminhandlers.CreateAdminMarketTagHandler(marketsService, authService))).Methods("POST")
+	router.Handle("/v0/admin/market-tags/{slug}", securityMiddleware(adminhandlers.UpdateAdminMarketTagHandler(marketsService, authService))).Methods("PATCH")
 
 	router.HandleFunc("/v0/content/home", homepageHandler.PublicGet).Methods("GET")
 	router.Handle("/v0/admin/content/home", securityMiddleware(http.HandlerFunc(homepageHandler.AdminUpdate))).Methods("PUT")
@@ -561,6 +731,83 @@ func registerApplicationRoutes(router *mux.Router, db *gorm.DB, configService co
 	router.Handle("/v0/admin/content/social-share/image", securityMiddleware(http.HandlerFunc(socialShareHandler.AdminUploadImage))).Methods("POST")
 	router.Handle("/v0/content/reporting-visibility", securityMiddleware(http.HandlerFunc(reportingVisibilityHandler.PublicGet))).Methods("GET")
 	router.Handle("/v0/admin/content/reporting-visibility", securityMiddleware(http.HandlerFunc(reportingVisibilityHandler.AdminUpdate))).Methods("PUT")
+
+	return workers
+}
+
+// buildEmailTransport returns nil when email is off. A transport that fails
+// to build is logged and also returns nil, so queued email is retried and
+// then marked dead instead of blocking startup.
+func buildEmailTransport(config appruntime.EmailConfig) demail.Transport {
+	var (
+		transport demail.Transport
+		err       error
+	)
+	switch config.Transport {
+	case appruntime.EmailTransportSMTP:
+		transport, err = emailsvc.NewSMTPTransport(emailsvc.SMTPConfig{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.From,
+		})
+	case appruntime.EmailTransportFile:
+		transport, err = emailsvc.NewFileSink(config.FileSinkDir, config.From)
+	default:
+		return nil
+	}
+	if err != nil {
+		logger.LogError("email", "buildEmailTransport", err)
+		return nil
+	}
+	return transport
+}
+
+// buildOIDCFlow returns nil when single sign-on is not configured so the
+// handlers answer 404 instead of attempting provider discovery.
+func buildOIDCFlow(db *gorm.DB, config appruntime.OIDCConfig) authsvc.OIDCFlow {
+	if !config.Enabled() {
+		return nil
+	}
+	provider := oidc.NewProvider(oidc.Config{
+		IssuerURL:    config.IssuerURL,
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		RedirectURL:  config.RedirectURL,
+		Scopes:       config.Scopes,
+	}, nil)
+	return oidc.NewFlow(provider, oidc.NewGormStateStore(db), time.Now)
+}
+
+func loginProtectionSettings(guard authsvc.LoginAttemptGuard, config appruntime.SecurityConfig) authsvc.LoginProtection {
+	return authsvc.LoginProtection{
+		Guard: guard,
+		Policy: dusers.LoginThrottlePolicy{
+			FreeAttempts:     config.LoginLockout.FreeAttempts,
+			BaseDelay:        config.LoginLockout.BaseDelay,
+			MaxDelay:         config.LoginLockout.MaxDelay,
+			LockoutThreshold: config.LoginLockout.Threshold,
+			LockoutDuration:  config.LoginLockout.Duration,
+		},
+		ClientIdentity: security.NewClientIdentityExtractor(config.TrustProxyHeaders),
+		Now:            time.Now,
+	}
+}
+
+func oidcSignInSettings(config appruntime.OIDCConfig, configService configsvc.Service, protection authsvc.LoginProtection) authsvc.OIDCSignInSettings {
+	return authsvc.OIDCSignInSettings{
+		Policy: dusers.ExternalSignInPolicy{
+			AutoProvision:       config.AutoProvision,
+			AllowedEmailDomains: config.AllowedEmailDomains,
+			AllowedEmails:       config.AllowedEmails,
+		},
+		InitialAccountBalance: func() int64 {
+			return configService.Economics().User.InitialAccountBalance
+		},
+		Now:        time.Now,
+		Protection: protection,
+	}
 }
 
 func shareMetadataConfig(config appruntime.ShareConfig) dmarkets.ShareMetadataConfig {
@@ -595,10 +842,15 @@ func shutdownHTTPServer(server gracefulShutdowner, readiness *appruntime.Readine
 
 func Start(openAPISpec []byte, swaggerUIFS embed.FS, db *gorm.DB, configService configsvc.Service, readiness *appruntime.Readiness, securityConfig appruntime.SecurityConfig, shutdownConfig appruntime.ShutdownConfig) {
 	authsvc.ConfigureJWTSigningKey(securityConfig.JWTSigningKey)
-	handler, err := buildHandler(openAPISpec, swaggerUIFS, db, configService, readiness, securityConfig)
+	handler, workers, err := buildHandlerWithWorkers(openAPISpec, swaggerUIFS, db, configService, readiness, securityConfig)
 	if err != nil {
 		logger.Fatal("server", "http handler initialization failed", err, logger.Operation("buildHandler"))
 	}
+	workerContext, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	for _, worker := range workers {
+		go worker.Run(workerContext)
+	}
 	shutdownConfig = appruntime.NormalizeShutdownConfig(shutdownConfig)
 
 	// Allow BACKEND_PORT to be configured via environment, default to 8080
@@ -612,6 +864,9 @@ func Start(openAPISpec []byte, swaggerUIFS embed.FS, db *gorm.DB, configService
 		Addr:    address,
 		Handler: handler,
 	}
+	// Stop the workers as soon as shutdown begins: live streams never finish
+	// on their own, so Shutdown would otherwise wait out its whole timeout.
+	server.RegisterOnShutdown(stopWorkers)
 
 	serveErrs := make(chan error, 1)
 	go func() {
@@ -602,6 +602,10 @@ func TestServerBlocksPrivateActionRoutesWhenPasswordChangeRequiredWithSharedReas
 		{name: "quote sell position", method: http.MethodPost, path: "/v0/sell/quote", body: `{"marketId":1,"amount":1,"outcome":"YES"}`},
 		{name: "sell position", method: http.MethodPost, path: "/v0/sell", body: `{"marketId":1,"amount":1,"outcome":"YES"}`},
 		{name: "user position", method: http.MethodGet, path: "/v0/userposition/1"},
+		{name: "mark notifications read", method: http.MethodPost, path: "/v0/notifications/read", body: `{"ids":[1]}`},
+		{name: "mark all notifications read", method: http.MethodPost, path: "/v0/notifications/read-all"},
+		{name: "update notification preferences", method: http.MethodPut, path: "/v0/notifications/preferences", body: `{}`},
+		{name: "update email preferences", method: http.MethodPut, path: "/v0/email/preferences", body: `{}`},
 	}
 
 	for _, tt := range tests {